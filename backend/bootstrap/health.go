@@ -0,0 +1,82 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/SFLuv/app/backend/bot"
+	"github.com/SFLuv/app/backend/clientconfig"
+	"github.com/SFLuv/app/backend/health"
+)
+
+// NewHealthChecker registers the readiness checks for a running server: each
+// database pool, the schema version, the read RPC and every payout bot. Bot
+// checks are non-critical; they fail below HEALTH_BOT_MIN_TOKENS or
+// HEALTH_BOT_MIN_GAS and mark the service degraded without taking it out of
+// rotation.
+func NewHealthChecker(pools *DBPools, clientConfig *clientconfig.Config, payoutBots *bot.Router) *health.Checker {
+	checker := health.New(latestDBVersion())
+	if pools != nil {
+		checker.Add("app_db", true, health.Pool(pools.App))
+		checker.Add("bot_db", true, health.Pool(pools.Bot))
+		checker.Add("ponder_db", true, health.Pool(pools.Ponder))
+		checker.Add("schema_version", true, schemaVersionCheck(pools))
+	}
+	if clientConfig != nil {
+		checker.Add("rpc", true, health.RPC(clientConfig.ReadRPCURL(), int64(clientConfig.ActiveChainID())))
+	}
+	if payoutBots != nil {
+		thresholds := health.BotThresholds{
+			MinTokens: envFloat("HEALTH_BOT_MIN_TOKENS"),
+			MinGas:    envFloat("HEALTH_BOT_MIN_GAS"),
+		}
+		for _, chainID := range payoutBots.ChainIDs() {
+			b, ok := payoutBots.ForChain(chainID)
+			if !ok {
+				continue
+			}
+			checker.Add(fmt.Sprintf("bot_%d", chainID), false, health.Bot(b, thresholds))
+		}
+	}
+	return checker
+}
+
+// schemaVersionCheck fails until both versioned databases are at the latest
+// migration this build knows, so a deploy is not cut over before its
+// migrations have run.
+func schemaVersionCheck(pools *DBPools) health.CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		expected := latestDBVersion()
+		detail := map[string]string{"expected": expected}
+		targets := []versionTarget{
+			{name: "app", pool: pools.App},
+			{name: "bot", pool: pools.Bot},
+		}
+		for _, target := range targets {
+			if target.pool == nil {
+				return detail, fmt.Errorf("%s db pool is not configured", target.name)
+			}
+			version, ok, err := getCurrentVersion(ctx, target.pool)
+			if err != nil {
+				return detail, fmt.Errorf("error reading %s db version: %w", target.name, err)
+			}
+			if !ok {
+				version = baselineDBVersion
+			}
+			detail[target.name] = version
+			if version != expected {
+				return detail, fmt.Errorf("%s db is at version %s, expected %s", target.name, version, expected)
+			}
+		}
+		return detail, nil
+	}
+}
+
+func envFloat(key string) float64 {
+	value, err := strconv.ParseFloat(envOrDefault(key, ""), 64)
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+package bootstrap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/SFLuv/app/backend/bot"
+	"github.com/SFLuv/app/backend/health"
+	"github.com/SFLuv/app/backend/lifecycle"
+	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/metrics"
+)
+
+const defaultBotMetricsInterval = time.Minute
+
+// RegisterDBPoolMetrics exposes pgxpool statistics for each open pool.
+func RegisterDBPoolMetrics(pools *DBPools) error {
+	if pools == nil {
+		return nil
+	}
+	if err := metrics.RegisterDBPool("bot", pools.Bot); err != nil {
+		return err
+	}
+	if err := metrics.RegisterDBPool("app", pools.App); err != nil {
+		return err
+	}
+	return metrics.RegisterDBPool("ponder", pools.Ponder)
+}
+
+// StartBotMetricsLoop samples each chain bot's token balance and pending
+// transaction count every BOT_METRICS_INTERVAL_SECONDS, so alerts can fire
+// before the faucet runs dry. Balances are read off chain, which is too slow
+// to do on every scrape.
+func StartBotMetricsLoop(lc *lifecycle.Manager, payoutBots *bot.Router, appLogger *logger.LogCloser) {
+	if payoutBots == nil {
+		return
+	}
+
+	interval := defaultBotMetricsInterval
+	if seconds, err := strconv.Atoi(envOrDefault("BOT_METRICS_INTERVAL_SECONDS", "")); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	lc.Go("bot metrics loop", func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			sampleBotMetrics(ctx, payoutBots, appLogger)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+func sampleBotMetrics(ctx context.Context, payoutBots *bot.Router, appLogger *logger.LogCloser) {
+	for _, chainID := range payoutBots.ChainIDs() {
+		b, ok := payoutBots.ForChain(chainID)
+		if !ok || b == nil {
+			continue
+		}
+		metrics.SetBotPendingTransactions(chainID, b.PendingTransactions())
+
+		balance, err := b.Balance(ctx)
+		if err != nil {
+			metrics.BotBalanceError(chainID)
+			appLogger.Errorf(ctx, "error reading bot balance on chain %d for metrics: %s", chainID, err)
+			continue
+		}
+		metrics.SetBotBalance(chainID, health.WholeUnits(balance, b.TokenDecimals()))
+	}
+}
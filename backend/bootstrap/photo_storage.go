@@ -0,0 +1,66 @@
+package bootstrap
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/handlers"
+	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/photostore"
+)
+
+// PhotoStorageBackend is the store new workflow photos are written to.
+func PhotoStorageBackend() string {
+	return strings.ToLower(envOrDefault("PHOTO_STORAGE_BACKEND", photostore.BackendPostgres))
+}
+
+// NewPhotoStore builds the named store from PHOTO_STORAGE_* settings.
+func NewPhotoStore(appDb *db.AppDB, backend string) (photostore.Store, error) {
+	switch backend {
+	case photostore.BackendPostgres:
+		return appDb.PostgresPhotoStore(), nil
+	case photostore.BackendLocal:
+		return photostore.NewLocal(envOrDefault("PHOTO_STORAGE_LOCAL_DIR", "./data/workflow-photos"))
+	case photostore.BackendS3:
+		pathStyle, _ := strconv.ParseBool(os.Getenv("PHOTO_STORAGE_S3_FORCE_PATH_STYLE"))
+		return photostore.NewS3(photostore.S3Config{
+			Endpoint:        strings.TrimSpace(os.Getenv("PHOTO_STORAGE_S3_ENDPOINT")),
+			Region:          strings.TrimSpace(os.Getenv("PHOTO_STORAGE_S3_REGION")),
+			Bucket:          strings.TrimSpace(os.Getenv("PHOTO_STORAGE_S3_BUCKET")),
+			AccessKeyId:     strings.TrimSpace(os.Getenv("PHOTO_STORAGE_S3_ACCESS_KEY_ID")),
+			SecretAccessKey: strings.TrimSpace(os.Getenv("PHOTO_STORAGE_S3_SECRET_ACCESS_KEY")),
+			PathStyle:       pathStyle,
+		}, nil)
+	}
+	return nil, fmt.Errorf("unknown photo storage backend %q", backend)
+}
+
+func configurePhotoStorage(appDb *db.AppDB, a *handlers.AppService, appLogger *logger.LogCloser) error {
+	store, err := NewPhotoStore(appDb, PhotoStorageBackend())
+	if err != nil {
+		return fmt.Errorf("error initializing photo storage: %w", err)
+	}
+	appDb.SetPhotoStore(store)
+	appLogger.Infof(context.Background(), "writing workflow photos to %s storage", store.Backend())
+
+	key := []byte(strings.TrimSpace(os.Getenv("PHOTO_URL_SIGNING_KEY")))
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("error generating photo url signing key: %w", err)
+		}
+		appLogger.Warnf(context.Background(), "PHOTO_URL_SIGNING_KEY is not set; signed photo links will only work on this instance until it restarts")
+	}
+	ttlSeconds, err := strconv.Atoi(envOrDefault("PHOTO_SIGNED_URL_TTL_SECONDS", "300"))
+	if err != nil || ttlSeconds <= 0 {
+		return fmt.Errorf("invalid PHOTO_SIGNED_URL_TTL_SECONDS %q", os.Getenv("PHOTO_SIGNED_URL_TTL_SECONDS"))
+	}
+	a.SetPhotoURLSigning(photostore.NewURLSigner(key), time.Duration(ttlSeconds)*time.Second, os.Getenv("PUBLIC_BACKEND_URL"))
+	return nil
+}
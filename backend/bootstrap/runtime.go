@@ -3,8 +3,10 @@ package bootstrap
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,14 +14,26 @@ import (
 	"github.com/SFLuv/app/backend/clientconfig"
 	"github.com/SFLuv/app/backend/db"
 	"github.com/SFLuv/app/backend/handlers"
+	"github.com/SFLuv/app/backend/lifecycle"
 	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/push"
+	"github.com/SFLuv/app/backend/ratelimit"
 	"github.com/SFLuv/app/backend/router"
+	"github.com/SFLuv/app/backend/utils"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
 
 const deletedAccountPurgeRunTimeout = 30 * time.Minute
 
+const defaultShutdownTimeout = 60 * time.Second
+
+const (
+	rateLimitSweepInterval = time.Hour
+	// Buckets idle this long have refilled under any sensible limit period.
+	rateLimitBucketIdleAge = 24 * time.Hour
+)
+
 const (
 	defaultBotDBName    = "bot"
 	defaultAppDBName    = "app"
@@ -86,8 +100,26 @@ func (p *DBPools) Close() {
 	}
 }
 
+// NewAppLogger builds the app logger and installs it as the slog default, so
+// packages without a logger of their own (the bot, db query tracing) log
+// through the same handler.
 func NewAppLogger() (*logger.LogCloser, error) {
-	return logger.New("./logs/prod/app.log", "APP: ")
+	appLogger, err := logger.New("./logs/prod/app.log", "APP: ")
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(appLogger.Slog())
+	return appLogger, nil
+}
+
+// ShutdownTimeout is how long the server waits on SIGTERM for in-flight
+// requests and background work before closing the pools regardless, from
+// SHUTDOWN_TIMEOUT_SECONDS.
+func ShutdownTimeout() time.Duration {
+	if seconds, err := strconv.Atoi(envOrDefault("SHUTDOWN_TIMEOUT_SECONDS", "")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultShutdownTimeout
 }
 
 func resolveDBPoolNames() (string, string) {
@@ -118,7 +150,7 @@ func InitializeDatabases(ctx context.Context, pools *DBPools, appLogger *logger.
 
 	defaultAdminID, err := appDb.GetFirstAdminId(ctx)
 	if err != nil && appLogger != nil {
-		appLogger.Logf("error getting default admin id during init: %s", err)
+		appLogger.Errorf(ctx, "error getting default admin id during init: %s", err)
 	}
 
 	botDb := db.Bot(pools.Bot)
@@ -157,36 +189,39 @@ func RunInitializationSyncs(ctx context.Context, pools *DBPools, appLogger *logg
 
 	redeemer := handlers.NewRedeemerService(appDb, appLogger, clientConfig)
 	if err := redeemer.SyncApprovedMerchants(ctx); err != nil {
-		appLogger.Logf("error syncing redeemer roles during init: %s", err)
+		appLogger.Errorf(ctx, "error syncing redeemer roles during init: %s", err)
 	}
 	if err := redeemer.SyncAdmins(ctx); err != nil {
-		appLogger.Logf("error syncing admin redeemer roles during init: %s", err)
+		appLogger.Errorf(ctx, "error syncing admin redeemer roles during init: %s", err)
 	}
 
 	minter := handlers.NewMinterService(appDb, appLogger, clientConfig)
 	if err := minter.SyncWalletMinterStatuses(ctx); err != nil {
-		appLogger.Logf("error syncing minter roles during init: %s", err)
+		appLogger.Errorf(ctx, "error syncing minter roles during init: %s", err)
 	}
 
 	appService := handlers.NewAppService(appDb, appLogger, nil, clientConfig)
 	if err := appService.SyncPrivyLinkedEmailsForAllUsers(ctx); err != nil {
-		appLogger.Logf("error syncing Privy linked emails during init: %s", err)
+		appLogger.Errorf(ctx, "error syncing Privy linked emails during init: %s", err)
+	}
+	if err := appService.RekeyPonderHooks(ctx); err != nil {
+		appLogger.Errorf(ctx, "error re-keying ponder hooks during init: %s", err)
 	}
 
 	return nil
 }
 
-func StartDeletedAccountPurgeLoop(ctx context.Context, appService *handlers.AppService, appLogger *logger.LogCloser) {
-	if ctx == nil || appService == nil || appLogger == nil {
+func StartDeletedAccountPurgeLoop(lc *lifecycle.Manager, appService *handlers.AppService, appLogger *logger.LogCloser) {
+	if appService == nil || appLogger == nil {
 		return
 	}
 
-	go func() {
+	lc.Go("deleted account purge loop", func(ctx context.Context) {
 		runDeletedAccountPurge(ctx, appService, appLogger, "startup")
 		if ctx.Err() != nil {
 			return
 		}
-		appLogger.Logf("deleted account purge loop started; next run scheduled for %s", nextDeletedAccountPurgeRun(time.Now().UTC()).Format(time.RFC3339))
+		appLogger.Infof(ctx, "deleted account purge loop started; next run scheduled for %s", nextDeletedAccountPurgeRun(time.Now().UTC()).Format(time.RFC3339))
 
 		for {
 			nextRun := nextDeletedAccountPurgeRun(time.Now().UTC())
@@ -200,7 +235,7 @@ func StartDeletedAccountPurgeLoop(ctx context.Context, appService *handlers.AppS
 				runDeletedAccountPurge(ctx, appService, appLogger, "daily")
 			}
 		}
-	}()
+	})
 }
 
 func runDeletedAccountPurge(ctx context.Context, appService *handlers.AppService, appLogger *logger.LogCloser, runType string) {
@@ -214,13 +249,13 @@ func runDeletedAccountPurge(ctx context.Context, appService *handlers.AppService
 	purged, err := appService.PurgeDeletedAccounts(runCtx, time.Now().UTC())
 	if err != nil {
 		if ctx.Err() == nil {
-			appLogger.Logf("error running deleted account purge during %s pass: %s", runType, err)
+			appLogger.Errorf(ctx, "error running deleted account purge during %s pass: %s", runType, err)
 		}
 		return
 	}
 
 	if purged > 0 {
-		appLogger.Logf("purged %d deleted accounts during %s pass", purged, runType)
+		appLogger.Infof(ctx, "purged %d deleted accounts during %s pass", purged, runType)
 	}
 }
 
@@ -233,13 +268,44 @@ func nextDeletedAccountPurgeRun(now time.Time) time.Time {
 	return nextMidnightUTC
 }
 
-func NewServerHandler(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) (http.Handler, error) {
+// NewServerHandler builds the services and starts their background workers
+// under lc, which the caller shuts down before closing pools.
+// newRateLimitStore picks the rate limit bucket store from RATE_LIMIT_STORE:
+// "memory" (the default) for a single instance, or "postgres" to share
+// buckets across instances, with idle buckets swept hourly.
+func newRateLimitStore(lc *lifecycle.Manager, appDb *db.AppDB, appLogger *logger.LogCloser) ratelimit.Store {
+	switch strings.ToLower(envOrDefault("RATE_LIMIT_STORE", "memory")) {
+	case "postgres":
+		lc.Go("rate limit bucket sweep", func(ctx context.Context) {
+			ticker := time.NewTicker(rateLimitSweepInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if _, err := appDb.DeleteIdleRateLimitBuckets(ctx, time.Now().Add(-rateLimitBucketIdleAge)); err != nil && ctx.Err() == nil {
+						appLogger.Errorf(ctx, "error sweeping idle rate limit buckets: %s", err)
+					}
+				}
+			}
+		})
+		return ratelimit.StoreFunc(appDb.TakeRateLimitToken)
+	case "memory":
+	default:
+		appLogger.Warnf(context.Background(), "unknown RATE_LIMIT_STORE %q; using memory", os.Getenv("RATE_LIMIT_STORE"))
+	}
+	return ratelimit.NewMemoryStore()
+}
+
+func NewServerHandler(lc *lifecycle.Manager, pools *DBPools, appLogger *logger.LogCloser) (http.Handler, error) {
 	if pools == nil || pools.Bot == nil || pools.App == nil || pools.Ponder == nil {
 		return nil, fmt.Errorf("bot, app, and ponder db pools are required")
 	}
 	if appLogger == nil {
 		return nil, fmt.Errorf("app logger is required")
 	}
+	ctx := lc.Context()
 
 	if err := pools.Bot.Ping(ctx); err != nil {
 		return nil, fmt.Errorf("error pinging bot db: %w", err)
@@ -259,7 +325,7 @@ func NewServerHandler(ctx context.Context, pools *DBPools, appLogger *logger.Log
 	if err != nil {
 		return nil, fmt.Errorf("error loading client config: %w", err)
 	}
-	appLogger.Logf("loaded client config from %s for alias %s on chain %d", clientConfig.Source(), clientConfig.Community.Alias, clientConfig.ActiveChainID())
+	appLogger.Infof(ctx, "loaded client config from %s for alias %s on chain %d", clientConfig.Source(), clientConfig.Community.Alias, clientConfig.ActiveChainID())
 	activeChainID := int64(clientConfig.ActiveChainID())
 	if err := appDb.BackfillTransactionChainIDs(ctx, activeChainID); err != nil {
 		return nil, err
@@ -274,28 +340,59 @@ func NewServerHandler(ctx context.Context, pools *DBPools, appLogger *logger.Log
 	// not exist"), which halts indexing. Ponder tags chain ids itself via its
 	// schema; the cross-chain migration handles legacy rows on a clone only.
 
-	botClient, err := bot.Init(clientConfig)
+	payoutBots, err := bot.InitRouter(clientConfig)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing bot service: %w", err)
 	}
+	payoutBots.SetTxStore(botDb)
+	// The sender monitors and batchers stop when shutdown begins; a payout
+	// still in flight after that sends its transfer unbatched.
+	if err := payoutBots.Start(lc); err != nil {
+		appLogger.Errorf(ctx, "error starting bot transaction sender: %s", err)
+	}
+	appLogger.Infof(ctx, "payout bots running on chains %v", payoutBots.ChainIDs())
+	if err := RegisterDBPoolMetrics(pools); err != nil {
+		appLogger.Errorf(ctx, "error registering db pool metrics: %s", err)
+	}
+	StartBotMetricsLoop(lc, payoutBots, appLogger)
 
 	w9 := handlers.NewW9Service(appDb, ponderDb, appLogger, activeChainID)
 	affiliateScheduler := handlers.NewAffiliateScheduler(appDb, botDb, appLogger)
-	affiliateScheduler.Start(ctx)
+	affiliateScheduler.Start(lc)
 
 	redeemer := handlers.NewRedeemerService(appDb, appLogger, clientConfig)
 	minter := handlers.NewMinterService(appDb, appLogger, clientConfig)
 
-	s := handlers.NewBotService(botDb, appDb, botClient, w9, affiliateScheduler, activeChainID, clientConfig.ReadRPCURL())
+	s := handlers.NewBotService(botDb, appDb, ponderDb, payoutBots, w9, affiliateScheduler, activeChainID, clientConfig.ReadRPCURL())
+	affiliateScheduler.SetEventCreator(s.CreateAffiliateEvent)
+	pushProviders, err := push.NewRegistryFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing push providers: %w", err)
+	}
+
 	a := handlers.NewAppService(appDb, appLogger, w9, clientConfig)
+	if err := configurePhotoStorage(appDb, a, appLogger); err != nil {
+		return nil, err
+	}
+	a.SetPushProviders(pushProviders)
+	a.SetLifecycle(lc)
 	a.SetBotService(s)
+	s.SetNotifier(a.Notify)
+	affiliateScheduler.SetNotifier(a.Notify)
 	a.SetRedeemerService(redeemer)
 	a.SetMinterService(minter)
-	StartDeletedAccountPurgeLoop(ctx, a, appLogger)
+	payoutQueue := handlers.NewWorkflowPayoutQueue(a, appLogger)
+	a.SetWorkflowPayoutQueue(payoutQueue)
+	payoutQueue.Start(lc)
+	emailOutbox := handlers.NewEmailOutbox(a, utils.NewEmailSink(), appLogger)
+	a.SetEmailOutbox(emailOutbox)
+	emailOutbox.Start(lc)
+	a.StartHeldPushDelivery(lc)
+	StartDeletedAccountPurgeLoop(lc, a, appLogger)
 
 	p := handlers.NewPonderService(ponderDb, appDb, botDb, appLogger, activeChainID)
 	if err := p.SyncCurrentAnalyticsWalletRoleHistory(ctx); err != nil {
-		appLogger.Logf("error syncing analytics wallet role history during startup: %s", err)
+		appLogger.Errorf(ctx, "error syncing analytics wallet role history during startup: %s", err)
 	}
-	return router.New(s, a, p), nil
+	return router.New(s, a, p, NewHealthChecker(pools, clientConfig, payoutBots), newRateLimitStore(lc, appDb, appLogger)), nil
 }
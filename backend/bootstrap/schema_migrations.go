@@ -756,7 +756,7 @@ var schemaMigrations = []SchemaMigration{
 			// which halted indexing in production. Legacy chain-id tagging is handled
 			// by the cross-chain migration on a clone only — never the live Ponder DB.
 			if appLogger != nil {
-				appLogger.Logf("migration 1.18: ponder chain-id backfill is disabled (Ponder DB must not be modified by the backend)")
+				appLogger.Infof(ctx, "migration 1.18: ponder chain-id backfill is disabled (Ponder DB must not be modified by the backend)")
 			}
 			return nil
 		},
@@ -803,6 +803,734 @@ var schemaMigrations = []SchemaMigration{
 				return err
 			}
 
+			return nil
+		},
+	},
+	{
+		Version:     "1.21",
+		Description: "add durable workflow payout job queue",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS workflow_payout_jobs(
+					id TEXT PRIMARY KEY,
+					workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+					step_id TEXT NOT NULL DEFAULT '',
+					target_type TEXT NOT NULL,
+					status TEXT NOT NULL DEFAULT 'queued',
+					attempts INTEGER NOT NULL DEFAULT 0,
+					max_attempts INTEGER NOT NULL DEFAULT 5,
+					run_at BIGINT NOT NULL DEFAULT unix_now(),
+					lease_owner TEXT,
+					lease_expires_at BIGINT,
+					heartbeat_at BIGINT,
+					target_locked BOOLEAN NOT NULL DEFAULT false,
+					wallet_address TEXT NOT NULL DEFAULT '',
+					amount BIGINT NOT NULL DEFAULT 0,
+					tx_hash TEXT NOT NULL DEFAULT '',
+					tx_chain_id BIGINT,
+					tx_submitted_at BIGINT,
+					last_error TEXT NOT NULL DEFAULT '',
+					created_at BIGINT NOT NULL DEFAULT unix_now(),
+					updated_at BIGINT NOT NULL DEFAULT unix_now(),
+					completed_at BIGINT,
+					CHECK (target_type IN ('step', 'supervisor')),
+					CHECK (status IN ('queued', 'leased', 'succeeded', 'dead'))
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS workflow_payout_jobs_active_target_idx
+					ON workflow_payout_jobs(workflow_id, step_id, target_type)
+					WHERE status IN ('queued', 'leased');
+				CREATE INDEX IF NOT EXISTS workflow_payout_jobs_ready_idx
+					ON workflow_payout_jobs(status, run_at, created_at);
+				CREATE INDEX IF NOT EXISTS workflow_payout_jobs_workflow_idx
+					ON workflow_payout_jobs(workflow_id, created_at DESC);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.22",
+		Description: "track bot-submitted transactions for nonce management and gas bumps",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.Bot.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS bot_transactions(
+					hash TEXT PRIMARY KEY,
+					original_hash TEXT NOT NULL,
+					chain_id BIGINT NOT NULL,
+					from_address TEXT NOT NULL,
+					to_address TEXT NOT NULL,
+					nonce BIGINT NOT NULL,
+					data TEXT NOT NULL DEFAULT '',
+					gas_limit BIGINT NOT NULL,
+					gas_fee_cap TEXT NOT NULL DEFAULT '0',
+					gas_tip_cap TEXT NOT NULL DEFAULT '0',
+					raw_tx TEXT NOT NULL,
+					bump_count INTEGER NOT NULL DEFAULT 0,
+					status TEXT NOT NULL DEFAULT 'pending',
+					submitted_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+					updated_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+					CHECK (status IN ('pending', 'replaced', 'confirmed', 'failed', 'dropped'))
+				);
+
+				CREATE INDEX IF NOT EXISTS bot_transactions_original_idx
+					ON bot_transactions(original_hash);
+				CREATE INDEX IF NOT EXISTS bot_transactions_pending_idx
+					ON bot_transactions(chain_id, from_address, nonce)
+					WHERE status = 'pending';
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.23",
+		Description: "add per-address, per-user, per-device and cooldown redemption limits to faucet events",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.Bot.Exec(ctx, `
+				ALTER TABLE events
+					ADD COLUMN IF NOT EXISTS max_per_address INTEGER NOT NULL DEFAULT 1,
+					ADD COLUMN IF NOT EXISTS max_per_user INTEGER NOT NULL DEFAULT 0,
+					ADD COLUMN IF NOT EXISTS max_per_device INTEGER NOT NULL DEFAULT 0,
+					ADD COLUMN IF NOT EXISTS cooldown_seconds BIGINT NOT NULL DEFAULT 0;
+
+				ALTER TABLE redemptions
+					ADD COLUMN IF NOT EXISTS user_id TEXT,
+					ADD COLUMN IF NOT EXISTS device_id TEXT,
+					ADD COLUMN IF NOT EXISTS redeemed_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT;
+
+				-- Per-address limits are enforced in BotDB.Redeem now that an
+				-- event may allow more than one code per address.
+				DROP INDEX IF EXISTS redemptions_address_event_unique_idx;
+
+				CREATE INDEX IF NOT EXISTS redemptions_event_user_idx
+					ON redemptions(event, user_id)
+					WHERE user_id IS NOT NULL;
+				CREATE INDEX IF NOT EXISTS redemptions_event_device_idx
+					ON redemptions(event, device_id)
+					WHERE device_id IS NOT NULL;
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.24",
+		Description: "add append-only faucet redemption ledger",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.Bot.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS redemption_ledger(
+					id BIGSERIAL PRIMARY KEY,
+					code TEXT NOT NULL,
+					event TEXT NOT NULL,
+					address TEXT NOT NULL,
+					chain_id BIGINT NOT NULL DEFAULT 0,
+					amount BIGINT NOT NULL DEFAULT 0,
+					action TEXT NOT NULL,
+					tx_hash TEXT NOT NULL DEFAULT '',
+					error TEXT NOT NULL DEFAULT '',
+					created_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+					CHECK (action IN ('reserved', 'sent', 'failed', 'undone'))
+				);
+
+				CREATE INDEX IF NOT EXISTS redemption_ledger_event_idx
+					ON redemption_ledger(event, id);
+				CREATE INDEX IF NOT EXISTS redemption_ledger_code_idx
+					ON redemption_ledger(code, id);
+				CREATE INDEX IF NOT EXISTS redemption_ledger_tx_hash_idx
+					ON redemption_ledger(tx_hash)
+					WHERE tx_hash <> '';
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.25",
+		Description: "add affiliate event templates with weekly and monthly recurrence",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.Bot.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS event_templates(
+					id TEXT PRIMARY KEY,
+					owner TEXT NOT NULL,
+					title TEXT NOT NULL DEFAULT '',
+					description TEXT NOT NULL DEFAULT '',
+					codes INTEGER NOT NULL,
+					amount INTEGER NOT NULL,
+					duration_seconds BIGINT NOT NULL,
+					max_per_address INTEGER NOT NULL DEFAULT 1,
+					max_per_user INTEGER NOT NULL DEFAULT 0,
+					max_per_device INTEGER NOT NULL DEFAULT 0,
+					cooldown_seconds BIGINT NOT NULL DEFAULT 0,
+					recurrence TEXT NOT NULL DEFAULT 'none',
+					next_start_at BIGINT NOT NULL DEFAULT 0,
+					active BOOLEAN NOT NULL DEFAULT true,
+					last_event_id TEXT NOT NULL DEFAULT '',
+					last_run_at BIGINT NOT NULL DEFAULT 0,
+					last_error TEXT NOT NULL DEFAULT '',
+					created_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+					updated_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW())::BIGINT,
+					CHECK (recurrence IN ('none', 'weekly', 'monthly'))
+				);
+
+				CREATE INDEX IF NOT EXISTS event_templates_owner_idx
+					ON event_templates(owner, created_at DESC);
+				CREATE INDEX IF NOT EXISTS event_templates_due_idx
+					ON event_templates(next_start_at)
+					WHERE active AND recurrence <> 'none';
+
+				ALTER TABLE events
+					ADD COLUMN IF NOT EXISTS template_id TEXT;
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.26",
+		Description: "add signed ponder callback secrets, nonces and dedupe",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS ponder_hook_secrets(
+					hook_id INTEGER PRIMARY KEY,
+					secret TEXT NOT NULL,
+					created_at BIGINT NOT NULL DEFAULT unix_now()
+				);
+
+				CREATE TABLE IF NOT EXISTS ponder_callback_nonces(
+					nonce TEXT PRIMARY KEY,
+					received_at BIGINT NOT NULL DEFAULT unix_now()
+				);
+
+				CREATE INDEX IF NOT EXISTS ponder_callback_nonces_received_idx
+					ON ponder_callback_nonces(received_at);
+
+				CREATE TABLE IF NOT EXISTS ponder_callback_events(
+					source TEXT NOT NULL,
+					chain_id BIGINT NOT NULL,
+					tx_hash TEXT NOT NULL,
+					log_index INTEGER NOT NULL,
+					received_at BIGINT NOT NULL DEFAULT unix_now(),
+					PRIMARY KEY (source, chain_id, tx_hash, log_index)
+				);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.27",
+		Description: "add push providers to mobile push subscriptions and tickets",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE mobile_push_subscriptions
+					ADD COLUMN IF NOT EXISTS provider TEXT NOT NULL DEFAULT 'expo';
+
+				ALTER TABLE mobile_push_notification_tickets
+					ADD COLUMN IF NOT EXISTS provider TEXT NOT NULL DEFAULT 'expo';
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.28",
+		Description: "add notification preferences and quiet hours",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS notification_settings(
+					user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+					timezone TEXT NOT NULL DEFAULT '',
+					quiet_hours_start TEXT NOT NULL DEFAULT '',
+					quiet_hours_end TEXT NOT NULL DEFAULT '',
+					updated_at BIGINT NOT NULL DEFAULT unix_now()
+				);
+
+				CREATE TABLE IF NOT EXISTS notification_preferences(
+					user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+					category TEXT NOT NULL,
+					email BOOLEAN NOT NULL DEFAULT true,
+					push BOOLEAN NOT NULL DEFAULT true,
+					updated_at BIGINT NOT NULL DEFAULT unix_now(),
+					PRIMARY KEY (user_id, category)
+				);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.29",
+		Description: "add persisted email outbox with retries and bounce tracking",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS email_outbox(
+					id TEXT PRIMARY KEY,
+					category TEXT NOT NULL DEFAULT '',
+					user_id TEXT NOT NULL DEFAULT '',
+					to_email TEXT NOT NULL,
+					to_name TEXT NOT NULL DEFAULT '',
+					from_email TEXT NOT NULL,
+					from_name TEXT NOT NULL DEFAULT '',
+					subject TEXT NOT NULL DEFAULT '',
+					html TEXT NOT NULL DEFAULT '',
+					attachments JSONB NOT NULL DEFAULT '[]'::jsonb,
+					status TEXT NOT NULL DEFAULT 'pending',
+					attempts INTEGER NOT NULL DEFAULT 0,
+					max_attempts INTEGER NOT NULL DEFAULT 8,
+					run_at BIGINT NOT NULL DEFAULT unix_now(),
+					lease_owner TEXT,
+					lease_expires_at BIGINT,
+					sink TEXT NOT NULL DEFAULT '',
+					provider_message_id TEXT NOT NULL DEFAULT '',
+					last_error TEXT NOT NULL DEFAULT '',
+					created_at BIGINT NOT NULL DEFAULT unix_now(),
+					updated_at BIGINT NOT NULL DEFAULT unix_now(),
+					sent_at BIGINT,
+					bounced_at BIGINT,
+					CHECK (status IN ('pending', 'sending', 'sent', 'failed', 'bounced'))
+				);
+
+				CREATE INDEX IF NOT EXISTS email_outbox_ready_idx
+					ON email_outbox(status, run_at, created_at);
+				CREATE INDEX IF NOT EXISTS email_outbox_provider_message_idx
+					ON email_outbox(provider_message_id)
+					WHERE provider_message_id <> '';
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.30",
+		Description: "add email locale preference and plain-text email bodies",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE notification_settings
+					ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT '';
+
+				ALTER TABLE email_outbox
+					ADD COLUMN IF NOT EXISTS text TEXT NOT NULL DEFAULT '';
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.31",
+		Description: "record the request id that queued payout jobs and outbox emails",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE workflow_payout_jobs
+					ADD COLUMN IF NOT EXISTS request_id TEXT NOT NULL DEFAULT '';
+
+				ALTER TABLE email_outbox
+					ADD COLUMN IF NOT EXISTS request_id TEXT NOT NULL DEFAULT '';
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.32",
+		Description: "add shared token buckets for per-route rate limits",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS rate_limit_buckets(
+					key TEXT PRIMARY KEY,
+					tokens DOUBLE PRECISION NOT NULL,
+					updated_at_ms BIGINT NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS rate_limit_buckets_updated_idx
+					ON rate_limit_buckets(updated_at_ms);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.33",
+		Description: "add scoped api keys and attribute admin payout actions to them",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS api_keys(
+					id TEXT PRIMARY KEY,
+					name TEXT NOT NULL,
+					owner_user_id TEXT NOT NULL REFERENCES users(id),
+					prefix TEXT NOT NULL UNIQUE,
+					key_hash TEXT NOT NULL,
+					scopes TEXT[] NOT NULL DEFAULT ARRAY[]::TEXT[],
+					created_at BIGINT NOT NULL DEFAULT unix_now(),
+					expires_at BIGINT NOT NULL,
+					last_used_at BIGINT,
+					revoked_at BIGINT,
+					revoked_by_user_id TEXT REFERENCES users(id)
+				);
+
+				CREATE INDEX IF NOT EXISTS api_keys_owner_idx
+					ON api_keys(owner_user_id, created_at DESC);
+
+				ALTER TABLE workflow_payout_admin_actions
+					ADD COLUMN IF NOT EXISTS api_key_id TEXT;
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.34",
+		Description: "add admin audit log",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS admin_audit_log(
+					id BIGSERIAL PRIMARY KEY,
+					actor_user_id TEXT NOT NULL DEFAULT '',
+					api_key_id TEXT NOT NULL DEFAULT '',
+					action TEXT NOT NULL,
+					target_type TEXT NOT NULL,
+					target_id TEXT NOT NULL,
+					before_state JSONB,
+					after_state JSONB,
+					request_id TEXT NOT NULL DEFAULT '',
+					created_at BIGINT NOT NULL DEFAULT unix_now()
+				);
+
+				CREATE INDEX IF NOT EXISTS admin_audit_log_created_idx
+					ON admin_audit_log(created_at DESC, id DESC);
+
+				CREATE INDEX IF NOT EXISTS admin_audit_log_actor_idx
+					ON admin_audit_log(actor_user_id, created_at DESC);
+
+				CREATE INDEX IF NOT EXISTS admin_audit_log_target_idx
+					ON admin_audit_log(target_type, target_id, created_at DESC);
+
+				CREATE INDEX IF NOT EXISTS admin_audit_log_action_idx
+					ON admin_audit_log(action, created_at DESC);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.35",
+		Description: "track where workflow photo bytes are stored",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE workflow_submission_photos
+					ADD COLUMN IF NOT EXISTS storage_backend TEXT NOT NULL DEFAULT 'postgres',
+					ADD COLUMN IF NOT EXISTS storage_key TEXT NOT NULL DEFAULT '',
+					ALTER COLUMN photo_data DROP NOT NULL;
+
+				ALTER TABLE workflow_step_photo_uploads
+					ADD COLUMN IF NOT EXISTS storage_backend TEXT NOT NULL DEFAULT 'postgres',
+					ADD COLUMN IF NOT EXISTS storage_key TEXT NOT NULL DEFAULT '',
+					ALTER COLUMN photo_data DROP NOT NULL;
+
+				CREATE INDEX IF NOT EXISTS workflow_submission_photos_storage_backend_idx
+					ON workflow_submission_photos(storage_backend);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.36",
+		Description: "add workflow photo renditions, perceptual hashes and location retention",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE workflow_step_items
+					ADD COLUMN IF NOT EXISTS keep_photo_location BOOLEAN NOT NULL DEFAULT false;
+
+				ALTER TABLE workflow_submission_photos
+					ADD COLUMN IF NOT EXISTS location_stripped BOOLEAN NOT NULL DEFAULT false,
+					ADD COLUMN IF NOT EXISTS perceptual_hash BIGINT,
+					ADD COLUMN IF NOT EXISTS reused_photo_id TEXT,
+					ADD COLUMN IF NOT EXISTS reuse_distance INTEGER;
+
+				ALTER TABLE workflow_step_photo_uploads
+					ADD COLUMN IF NOT EXISTS location_stripped BOOLEAN NOT NULL DEFAULT false,
+					ADD COLUMN IF NOT EXISTS perceptual_hash BIGINT,
+					ADD COLUMN IF NOT EXISTS reused_photo_id TEXT,
+					ADD COLUMN IF NOT EXISTS reuse_distance INTEGER;
+
+				CREATE INDEX IF NOT EXISTS workflow_submission_photos_reused_idx
+					ON workflow_submission_photos(created_at DESC)
+					WHERE reused_photo_id IS NOT NULL;
+
+				CREATE TABLE IF NOT EXISTS workflow_photo_renditions(
+					id TEXT PRIMARY KEY,
+					photo_id TEXT NOT NULL,
+					workflow_id TEXT NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+					variant TEXT NOT NULL,
+					content_type TEXT NOT NULL DEFAULT 'image/jpeg',
+					width INTEGER NOT NULL DEFAULT 0,
+					height INTEGER NOT NULL DEFAULT 0,
+					size_bytes INTEGER NOT NULL DEFAULT 0,
+					photo_data BYTEA,
+					storage_backend TEXT NOT NULL DEFAULT 'postgres',
+					storage_key TEXT NOT NULL DEFAULT '',
+					created_at BIGINT NOT NULL DEFAULT unix_now(),
+					updated_at BIGINT NOT NULL DEFAULT unix_now(),
+					UNIQUE (photo_id, variant)
+				);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.37",
+		Description: "add workflow step geofences, completion windows and review holds",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE workflow_steps
+					ADD COLUMN IF NOT EXISTS geofence_latitude DOUBLE PRECISION,
+					ADD COLUMN IF NOT EXISTS geofence_longitude DOUBLE PRECISION,
+					ADD COLUMN IF NOT EXISTS geofence_radius_meters DOUBLE PRECISION,
+					ADD COLUMN IF NOT EXISTS completion_opens_after_minutes INTEGER,
+					ADD COLUMN IF NOT EXISTS completion_closes_after_minutes INTEGER,
+					ADD COLUMN IF NOT EXISTS review_status TEXT NOT NULL DEFAULT '',
+					ADD COLUMN IF NOT EXISTS reviewed_at BIGINT,
+					ADD COLUMN IF NOT EXISTS reviewed_by TEXT;
+
+				ALTER TABLE workflow_step_submissions
+					ADD COLUMN IF NOT EXISTS review_flags JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+				ALTER TABLE workflow_submission_photos
+					ADD COLUMN IF NOT EXISTS gps_distance_meters DOUBLE PRECISION;
+
+				ALTER TABLE workflow_step_photo_uploads
+					ADD COLUMN IF NOT EXISTS gps_distance_meters DOUBLE PRECISION;
+
+				CREATE INDEX IF NOT EXISTS workflow_steps_review_status_idx
+					ON workflow_steps(review_status)
+					WHERE review_status <> '';
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.38",
+		Description: "add supervisor approval for workflow step payouts",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE workflows
+					ADD COLUMN IF NOT EXISTS supervisor_approval_required BOOLEAN NOT NULL DEFAULT false;
+
+				ALTER TABLE workflow_states
+					ADD COLUMN IF NOT EXISTS supervisor_approval_required BOOLEAN NOT NULL DEFAULT false;
+
+				ALTER TABLE workflow_templates
+					ADD COLUMN IF NOT EXISTS supervisor_approval_required BOOLEAN NOT NULL DEFAULT false;
+
+				ALTER TABLE workflow_steps
+					ADD COLUMN IF NOT EXISTS review_comment TEXT;
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.39",
+		Description: "add workflow work item bounty weights",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE workflow_step_items
+					ADD COLUMN IF NOT EXISTS bounty_weight INTEGER NOT NULL DEFAULT 0;
+
+				ALTER TABLE workflow_steps
+					ADD COLUMN IF NOT EXISTS full_bounty BIGINT;
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.40",
+		Description: "index recent workflow photo hashes for reuse checks",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE INDEX IF NOT EXISTS workflow_submission_photos_hash_created_idx
+					ON workflow_submission_photos(created_at)
+					WHERE perceptual_hash IS NOT NULL;
+
+				CREATE INDEX IF NOT EXISTS workflow_step_photo_uploads_hash_created_idx
+					ON workflow_step_photo_uploads(created_at)
+					WHERE perceptual_hash IS NOT NULL;
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.41",
+		Description: "add mailgun webhook token log",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS mailgun_webhook_tokens(
+					token TEXT PRIMARY KEY,
+					received_at BIGINT NOT NULL DEFAULT unix_now()
+				);
+
+				CREATE INDEX IF NOT EXISTS mailgun_webhook_tokens_received_idx
+					ON mailgun_webhook_tokens(received_at);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.42",
+		Description: "hold pushes sent during quiet hours",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				CREATE TABLE IF NOT EXISTS held_push_notifications(
+					id TEXT PRIMARY KEY,
+					subscription_id INTEGER NOT NULL REFERENCES mobile_push_subscriptions(id) ON DELETE CASCADE,
+					category TEXT NOT NULL,
+					title TEXT NOT NULL,
+					body TEXT NOT NULL,
+					data JSONB NOT NULL DEFAULT '{}'::jsonb,
+					deliver_at BIGINT NOT NULL,
+					created_at BIGINT NOT NULL DEFAULT unix_now()
+				);
+
+				CREATE INDEX IF NOT EXISTS held_push_notifications_deliver_idx
+					ON held_push_notifications(deliver_at);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.43",
+		Description: "anchor monthly event templates to their first start",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.Bot.Exec(ctx, `
+				ALTER TABLE event_templates
+					ADD COLUMN IF NOT EXISTS anchor_start_at BIGINT NOT NULL DEFAULT 0;
+
+				UPDATE
+					event_templates
+				SET
+					anchor_start_at = next_start_at
+				WHERE
+					anchor_start_at = 0;
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.44",
+		Description: "drop the client-reported redemption device limit and restore the per-address redemption index",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.Bot.Exec(ctx, `
+				DROP INDEX IF EXISTS redemptions_event_device_idx;
+
+				ALTER TABLE redemptions
+					DROP COLUMN IF EXISTS device_id;
+				ALTER TABLE events
+					DROP COLUMN IF EXISTS max_per_device;
+				ALTER TABLE event_templates
+					DROP COLUMN IF EXISTS max_per_device;
+
+				-- address_seq numbers an address's redemptions within an
+				-- event, so the unique index still allows max_per_address
+				-- above one while catching any insert that skips the
+				-- advisory lock in BotDB.Redeem.
+				ALTER TABLE redemptions
+					ADD COLUMN IF NOT EXISTS address_seq INTEGER;
+
+				UPDATE
+					redemptions r
+				SET
+					address_seq = numbered.seq
+				FROM (
+					SELECT
+						id,
+						ROW_NUMBER() OVER (PARTITION BY address, event ORDER BY id) AS seq
+					FROM
+						redemptions
+				) numbered
+				WHERE
+					r.id = numbered.id
+				AND
+					r.address_seq IS NULL;
+
+				ALTER TABLE redemptions
+					ALTER COLUMN address_seq SET DEFAULT 1,
+					ALTER COLUMN address_seq SET NOT NULL;
+
+				CREATE UNIQUE INDEX IF NOT EXISTS redemptions_address_event_unique_idx
+					ON redemptions(address, event, address_seq);
+			`); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	},
+	{
+		Version:     "1.45",
+		Description: "lease held pushes until they are sent",
+		Apply: func(ctx context.Context, pools *DBPools, appLogger *logger.LogCloser) error {
+			if _, err := pools.App.Exec(ctx, `
+				ALTER TABLE held_push_notifications
+					ADD COLUMN IF NOT EXISTS lease_expires_at BIGINT,
+					ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+			`); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	},
@@ -854,7 +1582,7 @@ func RunPendingMigrations(ctx context.Context, pools *DBPools, appLogger *logger
 	}
 	if cmp == 0 {
 		if appLogger != nil {
-			appLogger.Logf("database schema already at version %s", currentVersion)
+			appLogger.Infof(ctx, "database schema already at version %s", currentVersion)
 		}
 		return nil
 	}
@@ -869,7 +1597,7 @@ func RunPendingMigrations(ctx context.Context, pools *DBPools, appLogger *logger
 		}
 
 		if appLogger != nil {
-			appLogger.Logf("applying schema migration %s: %s", migration.Version, migration.Description)
+			appLogger.Infof(ctx, "applying schema migration %s: %s", migration.Version, migration.Description)
 		}
 		if err := migration.Apply(ctx, pools, appLogger); err != nil {
 			return fmt.Errorf("error applying schema migration %s (%s): %w", migration.Version, migration.Description, err)
@@ -881,7 +1609,7 @@ func RunPendingMigrations(ctx context.Context, pools *DBPools, appLogger *logger
 	}
 
 	if appLogger != nil {
-		appLogger.Logf("database schema updated to version %s", currentVersion)
+		appLogger.Infof(ctx, "database schema updated to version %s", currentVersion)
 	}
 
 	return nil
@@ -953,7 +1681,7 @@ func ensureConsistentDBVersion(ctx context.Context, targets []versionTarget, app
 	}
 
 	if appLogger != nil {
-		appLogger.Logf("database schema version is %s", currentVersion)
+		appLogger.Infof(ctx, "database schema version is %s", currentVersion)
 	}
 
 	return currentVersion, nil
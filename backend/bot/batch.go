@@ -0,0 +1,191 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultBatchWindow  = 1500 * time.Millisecond
+	defaultBatchMaxSize = 25
+)
+
+// disperseABI is the Disperse-style batch contract interface. The contract
+// pulls each value from msg.sender with transferFrom, so the bot must approve
+// it as a spender; the allowance can only ever be spent by the bot's own calls.
+const disperseABI = `[{"inputs":[{"internalType":"address","name":"token","type":"address"},{"internalType":"address[]","name":"recipients","type":"address[]"},{"internalType":"uint256[]","name":"values","type":"uint256[]"}],"name":"disperseToken","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+type batchRequest struct {
+	to     common.Address
+	amount *big.Int
+	result chan batchResult
+}
+
+type batchResult struct {
+	tx  *types.Transaction
+	err error
+}
+
+// transferBatcher groups transfers submitted within a short window into a
+// single disperseToken call. Every caller in a batch receives the same tx.
+type transferBatcher struct {
+	bot      *Bot
+	contract common.Address
+	abi      ethabi.ABI
+	window   time.Duration
+	maxSize  int
+	requests chan *batchRequest
+	// done is closed when run returns, after which submit sends directly.
+	done chan struct{}
+}
+
+// newTransferBatcherFromEnv returns nil unless a batch contract is configured
+// for the bot's chain: BOT_BATCH_CONTRACT_<chain id>, or BOT_BATCH_CONTRACT on
+// the active chain.
+func newTransferBatcherFromEnv(b *Bot) (*transferBatcher, error) {
+	contract := strings.TrimSpace(os.Getenv(fmt.Sprintf("BOT_BATCH_CONTRACT_%d", b.chainID)))
+	if contract == "" && b.primary {
+		contract = strings.TrimSpace(os.Getenv("BOT_BATCH_CONTRACT"))
+	}
+	if contract == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(contract) {
+		return nil, fmt.Errorf("invalid BOT_BATCH_CONTRACT address: %s", contract)
+	}
+	parsed, err := ethabi.JSON(strings.NewReader(disperseABI))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing batch contract abi: %s", err)
+	}
+
+	window := defaultBatchWindow
+	if ms := envPositiveInt("BOT_BATCH_WINDOW_MS", 0); ms > 0 {
+		window = time.Duration(ms) * time.Millisecond
+	}
+
+	return &transferBatcher{
+		bot:      b,
+		contract: common.HexToAddress(contract),
+		abi:      parsed,
+		window:   window,
+		maxSize:  envPositiveInt("BOT_BATCH_MAX_SIZE", defaultBatchMaxSize),
+		requests: make(chan *batchRequest),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (t *transferBatcher) submit(ctx context.Context, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	req := &batchRequest{to: to, amount: amount, result: make(chan batchResult, 1)}
+	select {
+	case t.requests <- req:
+	case <-t.done:
+		return t.bot.sendTransfer(ctx, to, amount)
+	case <-ctx.Done():
+		return nil, newSendError(ctx.Err(), true)
+	}
+
+	res := <-req.result
+	return res.tx, res.err
+}
+
+// run collects and flushes batches until ctx is cancelled. A batch being
+// collected when that happens is still flushed, with ctx, so every caller
+// gets a reply.
+func (t *transferBatcher) run(ctx context.Context) {
+	defer close(t.done)
+	for {
+		var first *batchRequest
+		select {
+		case <-ctx.Done():
+			return
+		case first = <-t.requests:
+		}
+
+		batch := []*batchRequest{first}
+		timer := time.NewTimer(t.window)
+	collect:
+		for len(batch) < t.maxSize {
+			select {
+			case req := <-t.requests:
+				batch = append(batch, req)
+			case <-timer.C:
+				break collect
+			case <-ctx.Done():
+				break collect
+			}
+		}
+		timer.Stop()
+
+		t.flush(ctx, batch)
+	}
+}
+
+func (t *transferBatcher) flush(ctx context.Context, batch []*batchRequest) {
+	if len(batch) == 1 {
+		t.sendIndividually(ctx, batch)
+		return
+	}
+
+	recipients := make([]common.Address, 0, len(batch))
+	values := make([]*big.Int, 0, len(batch))
+	total := new(big.Int)
+	for _, req := range batch {
+		recipients = append(recipients, req.to)
+		values = append(values, req.amount)
+		total.Add(total, req.amount)
+	}
+
+	allowance, err := t.bot.allowance(ctx, t.contract)
+	if err != nil || allowance.Cmp(total) < 0 {
+		slog.WarnContext(ctx, "batch contract allowance unavailable or too low; sending transfers individually", "required", total.String(), "count", len(batch), "error", err)
+		t.sendIndividually(ctx, batch)
+		return
+	}
+
+	tokenAddress := common.HexToAddress(t.bot.tokenId)
+	callData, err := t.abi.Pack("disperseToken", tokenAddress, recipients, values)
+	if err != nil {
+		t.reply(batch, nil, newSendError(fmt.Errorf("error packing batch transfer call data: %s", err), true))
+		return
+	}
+
+	simCtx, simCancel := context.WithTimeout(ctx, 15*time.Second)
+	from, err := t.bot.sender.Address(simCtx)
+	if err == nil {
+		_, err = t.bot.client.CallContract(simCtx, ethereum.CallMsg{From: from, To: &t.contract, Data: callData}, nil)
+	}
+	simCancel()
+	if err != nil {
+		slog.WarnContext(ctx, "batch transfer simulation failed; sending transfers individually", "count", len(batch), "error", err)
+		t.sendIndividually(ctx, batch)
+		return
+	}
+
+	sendCtx, sendCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer sendCancel()
+	tx, err := t.bot.sender.send(sendCtx, t.contract, callData)
+	t.reply(batch, tx, err)
+}
+
+func (t *transferBatcher) sendIndividually(ctx context.Context, batch []*batchRequest) {
+	for _, req := range batch {
+		tx, err := t.bot.sendTransfer(ctx, req.to, req.amount)
+		req.result <- batchResult{tx: tx, err: err}
+	}
+}
+
+func (t *transferBatcher) reply(batch []*batchRequest, tx *types.Transaction, err error) {
+	for _, req := range batch {
+		req.result <- batchResult{tx: tx, err: err}
+	}
+}
@@ -2,16 +2,17 @@ package bot
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
-	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/SFLuv/app/backend/abi"
 	"github.com/SFLuv/app/backend/clientconfig"
+	"github.com/SFLuv/app/backend/lifecycle"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -22,20 +23,31 @@ import (
 
 type IBot interface {
 	Key() string
-	Send(amount uint64, address string) error
-	SubmitTransfer(amount uint64, address string) (string, error)
-	SubmitTransferBaseUnits(amount *big.Int, address string) (string, error)
+	Send(ctx context.Context, amount uint64, address string) (string, error)
+	SubmitTransfer(ctx context.Context, amount uint64, address string) (string, error)
+	SubmitTransferBaseUnits(ctx context.Context, amount *big.Int, address string) (string, error)
 	VerifyTransfer(ctx context.Context, txHash string, address string, amount uint64) (*TransferVerificationResult, error)
 	VerifyTransferBaseUnits(ctx context.Context, txHash string, address string, amount *big.Int) (*TransferVerificationResult, error)
-	Drain(address common.Address) error
-	Balance() (*big.Int, error)
+	Drain(ctx context.Context, address common.Address) error
+	Balance(ctx context.Context) (*big.Int, error)
+	GasBalance(ctx context.Context) (*big.Int, error)
+	PendingTransactions() int
+	ChainID() int64
+	TokenDecimals() int
 }
 
 type Bot struct {
-	pKey          string
+	chainID       int64
+	primary       bool
 	tokenId       string
 	tokenDecimals int
 	client        *ethclient.Client
+
+	senderMu sync.Mutex
+	signer   Signer
+	sender   *Sender
+	store    TxStore
+	batcher  *transferBatcher
 }
 
 type SendError struct {
@@ -79,16 +91,24 @@ func Init(config *clientconfig.Config) (*Bot, error) {
 	if config == nil {
 		return nil, fmt.Errorf("client config is required")
 	}
-	token, err := config.PrimaryToken()
+	return InitForChain(config, config.ActiveChainID())
+}
+
+// InitForChain builds a bot that pays out the community token deployed on
+// chainID, read from the client config's tokens map.
+func InitForChain(config *clientconfig.Config, chainID int) (*Bot, error) {
+	if config == nil {
+		return nil, fmt.Errorf("client config is required")
+	}
+	token, err := config.TokenForChain(chainID)
 	if err != nil {
 		return nil, err
 	}
 
-	pkey := os.Getenv("BOT_KEY")
 	tokenId := token.Address
-	rpcUrl := config.ReadRPCURL()
+	rpcUrl := config.ReadRPCURLForChain(token.ChainID)
 	if strings.TrimSpace(rpcUrl) == "" {
-		return nil, fmt.Errorf("read RPC URL is missing from client config")
+		return nil, fmt.Errorf("read RPC URL for chain %d is missing from client config", token.ChainID)
 	}
 
 	client, err := ethclient.Dial(rpcUrl)
@@ -97,12 +117,113 @@ func Init(config *clientconfig.Config) (*Bot, error) {
 		return nil, err
 	}
 
-	return &Bot{pKey: pkey, tokenId: tokenId, tokenDecimals: token.Decimals, client: client}, nil
+	return &Bot{
+		chainID:       int64(token.ChainID),
+		primary:       token.ChainID == config.ActiveChainID(),
+		tokenId:       tokenId,
+		tokenDecimals: token.Decimals,
+		client:        client,
+	}, nil
+}
+
+// ChainID is the chain this bot's token and transactions live on.
+func (b *Bot) ChainID() int64 {
+	return b.chainID
+}
+
+func (b *Bot) TokenDecimals() int {
+	return b.tokenDecimals
+}
+
+// SetSigner overrides the signer selected by BOT_SIGNER. It must be called
+// before the bot signs anything.
+func (b *Bot) SetSigner(signer Signer) {
+	b.signer = signer
+}
+
+// SetTxStore persists submitted transactions so pending ones survive restarts.
+// It must be called before Start.
+func (b *Bot) SetTxStore(store TxStore) {
+	b.store = store
+}
+
+// Start resumes tracking of persisted pending transactions and, when
+// BOT_BATCH_CONTRACT is configured, starts batching transfers. Both loops run
+// as lc workers and stop once shutdown begins; transfers submitted after that
+// are sent individually.
+func (b *Bot) Start(lc *lifecycle.Manager) error {
+	sender, err := b.txSender()
+	if err != nil {
+		return err
+	}
+	lc.Go(fmt.Sprintf("bot sender %d", b.chainID), sender.monitor)
+
+	batcher, err := newTransferBatcherFromEnv(b)
+	if err != nil {
+		return err
+	}
+	if batcher != nil && lc.Go(fmt.Sprintf("bot batcher %d", b.chainID), batcher.run) {
+		b.senderMu.Lock()
+		b.batcher = batcher
+		b.senderMu.Unlock()
+	}
+	return nil
 }
 
+// txSender lazily builds the nonce-managed sender, since the signer is only
+// validated the first time the bot has to sign something.
+func (b *Bot) txSender() (*Sender, error) {
+	b.senderMu.Lock()
+	defer b.senderMu.Unlock()
+	if b.sender != nil {
+		return b.sender, nil
+	}
+
+	if b.signer == nil {
+		signer, err := NewSignerFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		b.signer = signer
+	}
+
+	b.sender = newSender(b.client, b.signer)
+	b.sender.store = b.store
+	return b.sender, nil
+}
+
+// PendingTransactions counts the transactions this bot has broadcast that
+// have not been mined or dropped yet.
+func (b *Bot) PendingTransactions() int {
+	b.senderMu.Lock()
+	sender := b.sender
+	b.senderMu.Unlock()
+	if sender == nil {
+		return 0
+	}
+	return sender.pendingCount()
+}
+
+func (b *Bot) activeBatcher() *transferBatcher {
+	b.senderMu.Lock()
+	defer b.senderMu.Unlock()
+	return b.batcher
+}
+
+// Key returns the bot's public address. The private key never leaves the
+// signer.
 func (b *Bot) Key() string {
-	// get bot's public key and return it
-	return ""
+	sender, err := b.txSender()
+	if err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	address, err := sender.Address(ctx)
+	if err != nil {
+		return ""
+	}
+	return address.Hex()
 }
 
 func (b *Bot) tokenAmountFromWholeUnits(amount uint64) (*big.Int, error) {
@@ -113,31 +234,29 @@ func (b *Bot) tokenAmountFromWholeUnits(amount uint64) (*big.Int, error) {
 	return new(big.Int).Mul(scale, big.NewInt(int64(amount))), nil
 }
 
-func (b *Bot) deriveFromAddress() (common.Address, error) {
-	privateKey, err := crypto.HexToECDSA(b.pKey)
+func (b *Bot) deriveFromAddress(ctx context.Context) (common.Address, error) {
+	sender, err := b.txSender()
 	if err != nil {
-		return common.Address{}, fmt.Errorf("error parsing private key: %s", err)
-	}
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return common.Address{}, fmt.Errorf("error asserting type: publicKey is not of type ecdsa.PublicKey")
+		return common.Address{}, err
 	}
-	return crypto.PubkeyToAddress(*publicKeyECDSA), nil
+	return sender.Address(ctx)
 }
 
-func (b *Bot) submitTransferTx(amount uint64, address string) (*types.Transaction, error) {
+func (b *Bot) submitTransferTx(ctx context.Context, amount uint64, address string) (*types.Transaction, error) {
 	tokenAmount, err := b.tokenAmountFromWholeUnits(amount)
 	if err != nil {
 		return nil, err
 	}
-	return b.submitTransferTxBaseUnits(tokenAmount, address)
+	return b.submitTransferTxBaseUnits(ctx, tokenAmount, address)
 }
 
 // submitTransferTxBaseUnits transfers an exact base-unit token amount without
 // whole-unit scaling. Used for migration recovery payouts, which must pay a
-// holder's stored fractional balance precisely.
-func (b *Bot) submitTransferTxBaseUnits(tokenAmount *big.Int, address string) (*types.Transaction, error) {
+// holder's stored fractional balance precisely. ctx carries the caller's
+// request ID into the sender's logs; its cancellation is ignored so a client
+// hanging up cannot abandon a transfer halfway through broadcasting.
+func (b *Bot) submitTransferTxBaseUnits(ctx context.Context, tokenAmount *big.Int, address string) (*types.Transaction, error) {
+	ctx = context.WithoutCancel(ctx)
 	if tokenAmount == nil || tokenAmount.Sign() <= 0 {
 		return nil, fmt.Errorf("transfer amount must be positive")
 	}
@@ -146,24 +265,12 @@ func (b *Bot) submitTransferTxBaseUnits(tokenAmount *big.Int, address string) (*
 	}
 	toAddress := common.HexToAddress(address)
 	tokenAddress := common.HexToAddress(b.tokenId)
-	contract, err := abi.NewSFLUVv2(tokenAddress, b.client)
-	if err != nil {
-		return nil, newSendError(fmt.Errorf("error creating sfluv contract instance: %s", err), true)
-	}
 
-	privateKey, err := crypto.HexToECDSA(b.pKey)
+	sender, err := b.txSender()
 	if err != nil {
-		return nil, newSendError(fmt.Errorf("error parsing private key: %s", err), true)
-	}
-
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, newSendError(fmt.Errorf("error asserting type: publicKey is not of type ecdsa.PublicKey"), true)
+		return nil, newSendError(err, true)
 	}
 
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-
 	contractABI, err := abi.SFLUVv2MetaData.GetAbi()
 	if err != nil {
 		return nil, newSendError(fmt.Errorf("error loading sfluv contract abi: %s", err), true)
@@ -174,8 +281,12 @@ func (b *Bot) submitTransferTxBaseUnits(tokenAmount *big.Int, address string) (*
 		return nil, newSendError(fmt.Errorf("error packing transfer call data: %s", err), true)
 	}
 
-	simCtx, simCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	simCtx, simCancel := context.WithTimeout(ctx, 15*time.Second)
 	defer simCancel()
+	fromAddress, err := sender.Address(simCtx)
+	if err != nil {
+		return nil, newSendError(err, true)
+	}
 	simResult, err := b.client.CallContract(simCtx, ethereum.CallMsg{
 		From: fromAddress,
 		To:   &tokenAddress,
@@ -195,31 +306,54 @@ func (b *Bot) submitTransferTxBaseUnits(tokenAmount *big.Int, address string) (*
 		}
 	}
 
-	chainId, err := b.client.ChainID(context.Background())
-	if err != nil {
-		return nil, newSendError(fmt.Errorf("error getting chainId from rpc node: %s", err), true)
+	sendCtx, sendCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer sendCancel()
+	if batcher := b.activeBatcher(); batcher != nil {
+		return batcher.submit(sendCtx, toAddress, tokenAmount)
 	}
+	return b.sendTransfer(sendCtx, toAddress, tokenAmount)
+}
 
-	s, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+// sendTransfer broadcasts a plain token transfer through the nonce-managed
+// sender.
+func (b *Bot) sendTransfer(ctx context.Context, to common.Address, tokenAmount *big.Int) (*types.Transaction, error) {
+	sender, err := b.txSender()
 	if err != nil {
-		return nil, newSendError(fmt.Errorf("error creating transactor: %s", err), true)
+		return nil, newSendError(err, true)
 	}
-	opts := &bind.TransactOpts{
-		From:    fromAddress,
-		Signer:  s.Signer,
-		Context: context.Background(),
+	contractABI, err := abi.SFLUVv2MetaData.GetAbi()
+	if err != nil {
+		return nil, newSendError(fmt.Errorf("error loading sfluv contract abi: %s", err), true)
 	}
-
-	tx, err := contract.Transfer(opts, toAddress, tokenAmount)
+	callData, err := contractABI.Pack("transfer", to, tokenAmount)
+	if err != nil {
+		return nil, newSendError(fmt.Errorf("error packing transfer call data: %s", err), true)
+	}
+	tx, err := sender.send(ctx, common.HexToAddress(b.tokenId), callData)
 	if err != nil {
 		return nil, newSendError(fmt.Errorf("error sending transfer transaction: %s", err), true)
 	}
-
 	return tx, nil
 }
 
-func (b *Bot) SubmitTransfer(amount uint64, address string) (string, error) {
-	tx, err := b.submitTransferTx(amount, address)
+func (b *Bot) allowance(ctx context.Context, spender common.Address) (*big.Int, error) {
+	sender, err := b.txSender()
+	if err != nil {
+		return nil, err
+	}
+	contract, err := abi.NewSFLUVv2(common.HexToAddress(b.tokenId), b.client)
+	if err != nil {
+		return nil, fmt.Errorf("error creating sfluv contract instance: %s", err)
+	}
+	owner, err := sender.Address(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return contract.Allowance(&bind.CallOpts{Context: ctx}, owner, spender)
+}
+
+func (b *Bot) SubmitTransfer(ctx context.Context, amount uint64, address string) (string, error) {
+	tx, err := b.submitTransferTx(ctx, amount, address)
 	if err != nil {
 		return "", err
 	}
@@ -228,8 +362,8 @@ func (b *Bot) SubmitTransfer(amount uint64, address string) (string, error) {
 
 // SubmitTransferBaseUnits sends an exact base-unit amount and returns the tx
 // hash. Used by the migration recovery flow to pay precise stored balances.
-func (b *Bot) SubmitTransferBaseUnits(amount *big.Int, address string) (string, error) {
-	tx, err := b.submitTransferTxBaseUnits(amount, address)
+func (b *Bot) SubmitTransferBaseUnits(ctx context.Context, amount *big.Int, address string) (string, error) {
+	tx, err := b.submitTransferTxBaseUnits(ctx, amount, address)
 	if err != nil {
 		return "", err
 	}
@@ -263,24 +397,25 @@ func (b *Bot) verifyTransferReceipt(ctx context.Context, txHash string, address
 		return nil, fmt.Errorf("invalid recipient address: %s", address)
 	}
 
-	hash := common.HexToHash(txHash)
-	receipt, err := b.client.TransactionReceipt(ctx, hash)
+	// A gas bump replaces the tx under a new hash, so check every broadcast
+	// that shared this one's nonce.
+	sender, _ := b.txSender()
+	receipt, err := sender.receiptForGroup(ctx, b.client, txHash)
 	if err != nil {
-		if errors.Is(err, ethereum.NotFound) {
-			tx, isPending, txErr := b.client.TransactionByHash(ctx, hash)
+		return nil, err
+	}
+	if receipt == nil {
+		for _, groupHash := range sender.groupHashes(ctx, txHash) {
+			tx, isPending, txErr := b.client.TransactionByHash(ctx, common.HexToHash(groupHash))
 			if txErr == nil && tx != nil {
 				result.Found = true
 				result.Pending = isPending
 				return result, nil
 			}
 			if txErr != nil && !errors.Is(txErr, ethereum.NotFound) {
-				return nil, fmt.Errorf("error checking transfer transaction by hash %s: %w", txHash, txErr)
+				return nil, fmt.Errorf("error checking transfer transaction by hash %s: %w", groupHash, txErr)
 			}
-			return result, nil
 		}
-		return nil, fmt.Errorf("error loading transfer receipt %s: %w", txHash, err)
-	}
-	if receipt == nil {
 		return result, nil
 	}
 
@@ -289,7 +424,7 @@ func (b *Bot) verifyTransferReceipt(ctx context.Context, txHash string, address
 		return result, nil
 	}
 
-	fromAddress, err := b.deriveFromAddress()
+	fromAddress, err := b.deriveFromAddress(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -317,71 +452,62 @@ func (b *Bot) verifyTransferReceipt(ctx context.Context, txHash string, address
 	return result, nil
 }
 
-// send {amount} tokens to {address}
-func (b *Bot) Send(amount uint64, address string) error {
-	tx, err := b.submitTransferTx(amount, address)
+// send {amount} tokens to {address} and wait for it to be mined. The tx hash
+// is returned whenever a transaction was broadcast, even if waiting failed.
+func (b *Bot) Send(ctx context.Context, amount uint64, address string) (string, error) {
+	ctx = context.WithoutCancel(ctx)
+	tx, err := b.submitTransferTx(ctx, amount, address)
 	if err != nil {
-		return err
+		return "", err
+	}
+	txHash := tx.Hash().Hex()
+
+	sender, err := b.txSender()
+	if err != nil {
+		return txHash, newSendError(err, false)
 	}
 
-	waitCtx, waitCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	waitCtx, waitCancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer waitCancel()
-	receipt, err := bind.WaitMined(waitCtx, b.client, tx)
+	receipt, err := sender.waitMined(waitCtx, txHash)
 	if err != nil {
-		return newSendError(fmt.Errorf("error waiting for transfer tx %s: %w", tx.Hash().Hex(), err), false)
+		return txHash, newSendError(fmt.Errorf("error waiting for transfer tx %s: %w", txHash, err), false)
 	}
 	if receipt == nil {
-		return newSendError(fmt.Errorf("missing receipt for transfer tx %s", tx.Hash().Hex()), false)
+		return txHash, newSendError(fmt.Errorf("missing receipt for transfer tx %s", txHash), false)
 	}
 	if receipt.Status != types.ReceiptStatusSuccessful {
-		return newSendError(fmt.Errorf("transfer transaction reverted: %s", tx.Hash().Hex()), true)
+		return receipt.TxHash.Hex(), newSendError(fmt.Errorf("transfer transaction reverted: %s", receipt.TxHash.Hex()), true)
 	}
 
-	fmt.Printf("Sent Transaction: %s\n", tx.Hash().Hex())
-	return nil
+	slog.InfoContext(ctx, "sent transfer", "chain_id", b.chainID, "tx_hash", receipt.TxHash.Hex(), "to", address)
+	return receipt.TxHash.Hex(), nil
 }
 
-func (b *Bot) Drain(address common.Address) error {
-
+// Drain sends the signer's whole token balance to address.
+func (b *Bot) Drain(ctx context.Context, address common.Address) error {
 	tokenAddress := common.HexToAddress(b.tokenId)
 
-	contract, err := abi.NewSFLUVv2(tokenAddress, b.client)
-	if err != nil {
-		return fmt.Errorf("error creating sfluv contract instance: %s", err)
-	}
-
-	amount, err := contract.BalanceOf(nil, common.HexToAddress(os.Getenv("BOT_ADDRESS")))
+	amount, err := b.Balance(ctx)
 	if err != nil {
 		return fmt.Errorf("error getting bot balance: %s", err)
 	}
 
-	chid, err := b.client.ChainID(context.Background())
+	sender, err := b.txSender()
 	if err != nil {
-		return fmt.Errorf("error getting chainId:%s", err)
+		return err
 	}
 
-	privKey, err := crypto.HexToECDSA(b.pKey)
+	contractABI, err := abi.SFLUVv2MetaData.GetAbi()
 	if err != nil {
-		return fmt.Errorf("error parsing private key: %s", err)
-	}
-
-	pubKey, ok := privKey.Public().(*ecdsa.PublicKey)
-	if !ok {
-		return fmt.Errorf("error parsing public key into ecdsa type")
+		return fmt.Errorf("error loading sfluv contract abi: %s", err)
 	}
-
-	s, err := bind.NewKeyedTransactorWithChainID(privKey, chid)
+	callData, err := contractABI.Pack("transfer", address, amount)
 	if err != nil {
-		return fmt.Errorf("error creating transactor: %s", err)
-	}
-
-	opts := &bind.TransactOpts{
-		From:    crypto.PubkeyToAddress(*pubKey),
-		Signer:  s.Signer,
-		Context: context.Background(),
+		return fmt.Errorf("error packing drain call data: %s", err)
 	}
 
-	_, err = contract.Transfer(opts, address, amount)
+	_, err = sender.send(ctx, tokenAddress, callData)
 	if err != nil {
 		return fmt.Errorf("error draining faucet balance: %s", err)
 	}
@@ -389,13 +515,27 @@ func (b *Bot) Drain(address common.Address) error {
 	return nil
 }
 
-func (b *Bot) Balance() (*big.Int, error) {
-	tokenAddress := common.HexToAddress(b.tokenId)
+// GasBalance is the native balance of the bot key, which pays gas for every
+// payout.
+func (b *Bot) GasBalance(ctx context.Context) (*big.Int, error) {
+	from, err := b.deriveFromAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.client.BalanceAt(ctx, from, nil)
+}
+
+// Balance is the token balance of the bot key, which funds every payout.
+func (b *Bot) Balance(ctx context.Context) (*big.Int, error) {
+	from, err := b.deriveFromAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	contract, err := abi.NewSFLUVv2(tokenAddress, b.client)
+	contract, err := abi.NewSFLUVv2(common.HexToAddress(b.tokenId), b.client)
 	if err != nil {
 		return nil, fmt.Errorf("error creating sfluv contract instance to get balance: %s", err)
 	}
 
-	return contract.BalanceOf(nil, common.HexToAddress(os.Getenv("BOT_ADDRESS")))
+	return contract.BalanceOf(&bind.CallOpts{Context: ctx}, from)
 }
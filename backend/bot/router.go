@@ -0,0 +1,208 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SFLuv/app/backend/clientconfig"
+	"github.com/SFLuv/app/backend/lifecycle"
+)
+
+// Payout targets that can be routed to a chain with PAYOUT_CHAIN_ROUTES.
+const (
+	PayoutTargetRedemption = "redemption"
+	PayoutTargetRecovery   = "recovery"
+	PayoutTargetWorkflow   = "workflow"
+)
+
+var payoutTargets = []string{PayoutTargetRedemption, PayoutTargetRecovery, PayoutTargetWorkflow}
+
+// Router holds one bot per payout chain and decides which chain pays each
+// kind of target, so payouts on two chains can run side by side during a
+// migration. All bots share one signer; nonces are tracked per chain.
+//
+//   - PAYOUT_CHAIN_IDS: extra chains to run a bot on, besides the active chain
+//   - PAYOUT_CHAIN_ID: chain for targets without a route (default active chain)
+//   - PAYOUT_CHAIN_ROUTES: per-target overrides, e.g. "workflow=42220,recovery=42220"
+type Router struct {
+	bots           map[int64]*Bot
+	defaultChainID int64
+	routes         map[string]int64
+	signer         Signer
+}
+
+func InitRouter(config *clientconfig.Config) (*Router, error) {
+	if config == nil {
+		return nil, fmt.Errorf("client config is required")
+	}
+
+	activeChainID := int64(config.ActiveChainID())
+	chainIDs, err := parseChainIDList(os.Getenv("PAYOUT_CHAIN_IDS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAYOUT_CHAIN_IDS: %s", err)
+	}
+	chainIDs = append([]int64{activeChainID}, chainIDs...)
+
+	bots := map[int64]*Bot{}
+	for _, chainID := range chainIDs {
+		if _, ok := bots[chainID]; ok {
+			continue
+		}
+		b, err := InitForChain(config, int(chainID))
+		if err != nil {
+			return nil, fmt.Errorf("error initializing bot for chain %d: %w", chainID, err)
+		}
+		bots[chainID] = b
+	}
+
+	defaultChainID := activeChainID
+	if raw := strings.TrimSpace(os.Getenv("PAYOUT_CHAIN_ID")); raw != "" {
+		defaultChainID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || defaultChainID <= 0 {
+			return nil, fmt.Errorf("invalid PAYOUT_CHAIN_ID %q", raw)
+		}
+	}
+	routes, err := parsePayoutRoutes(os.Getenv("PAYOUT_CHAIN_ROUTES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAYOUT_CHAIN_ROUTES: %s", err)
+	}
+
+	return newRouter(bots, defaultChainID, routes)
+}
+
+func newRouter(bots map[int64]*Bot, defaultChainID int64, routes map[string]int64) (*Router, error) {
+	if _, ok := bots[defaultChainID]; !ok {
+		return nil, fmt.Errorf("default payout chain %d has no bot; add it to PAYOUT_CHAIN_IDS", defaultChainID)
+	}
+	for target, chainID := range routes {
+		if _, ok := bots[chainID]; !ok {
+			return nil, fmt.Errorf("%s payouts are routed to chain %d, which has no bot; add it to PAYOUT_CHAIN_IDS", target, chainID)
+		}
+	}
+	return &Router{bots: bots, defaultChainID: defaultChainID, routes: routes}, nil
+}
+
+// parsePayoutRoutes reads "target=chainID" pairs separated by commas.
+func parsePayoutRoutes(raw string) (map[string]int64, error) {
+	routes := map[string]int64{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		target, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected target=chain_id, got %q", part)
+		}
+		target = strings.ToLower(strings.TrimSpace(target))
+		if !isPayoutTarget(target) {
+			return nil, fmt.Errorf("unknown payout target %q", target)
+		}
+		chainID, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || chainID <= 0 {
+			return nil, fmt.Errorf("invalid chain id for %s: %q", target, value)
+		}
+		routes[target] = chainID
+	}
+	return routes, nil
+}
+
+func parseChainIDList(raw string) ([]int64, error) {
+	chainIDs := []int64{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		chainID, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || chainID <= 0 {
+			return nil, fmt.Errorf("invalid chain id %q", part)
+		}
+		chainIDs = append(chainIDs, chainID)
+	}
+	return chainIDs, nil
+}
+
+func isPayoutTarget(target string) bool {
+	for _, known := range payoutTargets {
+		if target == known {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSigner overrides the signer selected by BOT_SIGNER for every chain. It
+// must be called before Start.
+func (r *Router) SetSigner(signer Signer) {
+	r.signer = signer
+}
+
+// SetTxStore persists submitted transactions for every chain. It must be
+// called before Start.
+func (r *Router) SetTxStore(store TxStore) {
+	for _, b := range r.bots {
+		b.SetTxStore(store)
+	}
+}
+
+// Start shares one signer across the chain bots and starts each of them.
+func (r *Router) Start(lc *lifecycle.Manager) error {
+	if r.signer == nil {
+		signer, err := NewSignerFromEnv()
+		if err != nil {
+			return err
+		}
+		r.signer = signer
+	}
+
+	var errs []error
+	for _, chainID := range r.ChainIDs() {
+		b := r.bots[chainID]
+		b.SetSigner(r.signer)
+		if err := b.Start(lc); err != nil {
+			errs = append(errs, fmt.Errorf("chain %d: %w", chainID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ChainIDs lists the chains with a running bot, in ascending order.
+func (r *Router) ChainIDs() []int64 {
+	chainIDs := make([]int64, 0, len(r.bots))
+	for chainID := range r.bots {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+	return chainIDs
+}
+
+// Default returns the bot for PAYOUT_CHAIN_ID.
+func (r *Router) Default() IBot {
+	return r.bots[r.defaultChainID]
+}
+
+// ForTarget returns the bot that pays the given payout target.
+func (r *Router) ForTarget(target string) IBot {
+	if chainID, ok := r.routes[target]; ok {
+		return r.bots[chainID]
+	}
+	return r.Default()
+}
+
+// ForChain returns the bot for chainID, used to verify a payout on the chain
+// it was submitted on. A non-positive chainID means the default chain.
+func (r *Router) ForChain(chainID int64) (IBot, bool) {
+	if chainID <= 0 {
+		return r.Default(), true
+	}
+	b, ok := r.bots[chainID]
+	if !ok {
+		return nil, false
+	}
+	return b, true
+}
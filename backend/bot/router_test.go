@@ -0,0 +1,49 @@
+package bot
+
+import "testing"
+
+func TestParsePayoutRoutes(t *testing.T) {
+	routes, err := parsePayoutRoutes(" workflow=42220, Recovery=42220 ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if routes[PayoutTargetWorkflow] != 42220 || routes[PayoutTargetRecovery] != 42220 || len(routes) != 2 {
+		t.Fatalf("routes = %v", routes)
+	}
+
+	for _, raw := range []string{"workflow", "workflow=abc", "workflow=0", "payroll=42220"} {
+		if _, err := parsePayoutRoutes(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestRouterPicksChainPerTarget(t *testing.T) {
+	bera := &Bot{chainID: 80094, primary: true}
+	celo := &Bot{chainID: 42220}
+	bots := map[int64]*Bot{80094: bera, 42220: celo}
+
+	r, err := newRouter(bots, 80094, map[string]int64{PayoutTargetWorkflow: 42220})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := r.ForTarget(PayoutTargetWorkflow).ChainID(); got != 42220 {
+		t.Fatalf("workflow chain = %d; want 42220", got)
+	}
+	if got := r.ForTarget(PayoutTargetRedemption).ChainID(); got != 80094 {
+		t.Fatalf("redemption chain = %d; want 80094", got)
+	}
+	if b, ok := r.ForChain(0); !ok || b.ChainID() != 80094 {
+		t.Fatalf("ForChain(0) should return the default bot")
+	}
+	if _, ok := r.ForChain(1); ok {
+		t.Fatalf("ForChain(1) should not resolve")
+	}
+
+	if _, err := newRouter(bots, 80094, map[string]int64{PayoutTargetRecovery: 1}); err == nil {
+		t.Fatalf("expected error routing to a chain without a bot")
+	}
+	if _, err := newRouter(bots, 1, nil); err == nil {
+		t.Fatalf("expected error for a default chain without a bot")
+	}
+}
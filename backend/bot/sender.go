@@ -0,0 +1,617 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	defaultTxStuckAfter      = 3 * time.Minute
+	defaultTxMonitorInterval = 15 * time.Second
+	defaultTxMaxBumps        = 5
+	txGasLimitMarginPercent  = 20
+)
+
+// TxStore persists broadcast transactions so the sender can keep tracking,
+// rebroadcasting and gas-bumping them across restarts.
+type TxStore interface {
+	RecordBotTransaction(ctx context.Context, tx *structs.BotTransaction) error
+	ReplaceBotTransaction(ctx context.Context, oldHash string, tx *structs.BotTransaction) error
+	SettleBotTransactionGroup(ctx context.Context, originalHash string, minedHash string, status string) error
+	GetPendingBotTransactions(ctx context.Context, chainID int64, fromAddress string) ([]*structs.BotTransaction, error)
+	GetBotTransactionGroupHashes(ctx context.Context, hash string) ([]string, error)
+}
+
+type pendingTxGroup struct {
+	hashes  []string
+	current *structs.BotTransaction
+}
+
+// Sender owns the bot account's nonce. Every transaction the bot broadcasts
+// goes through send, which hands out nonces under a single lock so concurrent
+// redemptions, recovery claims and workflow payouts never collide. Broadcast
+// transactions are tracked until mined and re-sent with higher fees when they
+// sit in the mempool longer than stuckAfter.
+type Sender struct {
+	client *ethclient.Client
+	signer Signer
+	store  TxStore
+
+	stuckAfter      time.Duration
+	monitorInterval time.Duration
+	maxBumps        int
+
+	mu          sync.Mutex
+	from        common.Address
+	chainID     *big.Int
+	nonce       uint64
+	nonceLoaded bool
+
+	pendingMu sync.Mutex
+	pending   map[string]*pendingTxGroup
+}
+
+func newSender(client *ethclient.Client, signer Signer) *Sender {
+	return &Sender{
+		client:          client,
+		signer:          signer,
+		stuckAfter:      envDuration("BOT_TX_STUCK_AFTER_SECONDS", defaultTxStuckAfter),
+		monitorInterval: defaultTxMonitorInterval,
+		maxBumps:        envPositiveInt("BOT_TX_MAX_BUMPS", defaultTxMaxBumps),
+		pending:         map[string]*pendingTxGroup{},
+	}
+}
+
+// resolveFrom refreshes the signing address, dropping the cached nonce when a
+// key rotation changed it. Callers must hold s.mu.
+func (s *Sender) resolveFrom(ctx context.Context) (common.Address, error) {
+	from, err := s.signer.Address(ctx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error resolving bot signer address: %s", err)
+	}
+	if from != s.from {
+		s.from = from
+		s.nonceLoaded = false
+	}
+	return from, nil
+}
+
+// Address returns the account the sender currently signs for.
+func (s *Sender) Address(ctx context.Context) (common.Address, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resolveFrom(ctx)
+}
+
+func (s *Sender) chainIDValue(ctx context.Context) (*big.Int, error) {
+	if s.chainID != nil {
+		return s.chainID, nil
+	}
+	chainID, err := s.client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting chainId from rpc node: %s", err)
+	}
+	s.chainID = chainID
+	return chainID, nil
+}
+
+// send builds, signs and broadcasts a call to `to` with the next local nonce.
+// Errors are SendErrors that allow reverting the redemption, since nothing
+// reached the network.
+func (s *Sender) send(ctx context.Context, to common.Address, data []byte) (*types.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chainID, err := s.chainIDValue(ctx)
+	if err != nil {
+		return nil, newSendError(err, true)
+	}
+	from, err := s.resolveFrom(ctx)
+	if err != nil {
+		return nil, newSendError(err, true)
+	}
+
+	gas, err := s.client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &to, Data: data})
+	if err != nil {
+		return nil, newSendError(fmt.Errorf("error estimating gas: %s", err), true)
+	}
+	gas += gas * txGasLimitMarginPercent / 100
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if !s.nonceLoaded {
+			nonce, err := s.client.PendingNonceAt(ctx, from)
+			if err != nil {
+				return nil, newSendError(fmt.Errorf("error getting pending nonce: %s", err), true)
+			}
+			s.nonce = nonce
+			s.nonceLoaded = true
+		}
+
+		tipCap, feeCap, err := s.suggestFees(ctx)
+		if err != nil {
+			return nil, newSendError(err, true)
+		}
+		signed, err := s.signer.SignTx(ctx, buildTx(chainID, s.nonce, to, data, gas, tipCap, feeCap), chainID)
+		if err != nil {
+			return nil, newSendError(fmt.Errorf("error signing transaction: %s", err), true)
+		}
+
+		err = s.client.SendTransaction(ctx, signed)
+		if err != nil && !isAlreadyKnownError(err) {
+			// The nonce was not consumed; resync from the node before the next
+			// send in case our local view drifted.
+			s.nonceLoaded = false
+			if isNonceTooLowError(err) && attempt == 0 {
+				continue
+			}
+			return nil, newSendError(fmt.Errorf("error sending transaction: %s", err), true)
+		}
+
+		s.nonce++
+		s.track(ctx, signed, chainID, from, "", 0)
+		return signed, nil
+	}
+
+	return nil, newSendError(fmt.Errorf("error sending transaction: nonce could not be synchronized"), true)
+}
+
+func (s *Sender) suggestFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	header, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading latest header: %s", err)
+	}
+	if header.BaseFee == nil {
+		gasPrice, err := s.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error suggesting gas price: %s", err)
+		}
+		return nil, gasPrice, nil
+	}
+
+	tipCap, err := s.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error suggesting gas tip cap: %s", err)
+	}
+	feeCap := new(big.Int).Add(tipCap, new(big.Int).Mul(header.BaseFee, big.NewInt(2)))
+	return tipCap, feeCap, nil
+}
+
+// buildTx returns a dynamic-fee transaction, or a legacy one when tipCap is nil
+// (chains without a base fee).
+func buildTx(chainID *big.Int, nonce uint64, to common.Address, data []byte, gas uint64, tipCap *big.Int, feeCap *big.Int) *types.Transaction {
+	if tipCap == nil {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Gas:      gas,
+			GasPrice: feeCap,
+			Data:     data,
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        &to,
+		Gas:       gas,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Data:      data,
+	})
+}
+
+// bumpFee raises a fee by 12.5%, rounding up, which clears the 10% minimum
+// replacement bump enforced by geth-derived mempools.
+func bumpFee(fee *big.Int) *big.Int {
+	if fee == nil {
+		return nil
+	}
+	bumped := new(big.Int).Mul(fee, big.NewInt(9))
+	bumped.Add(bumped, big.NewInt(7))
+	return bumped.Div(bumped, big.NewInt(8))
+}
+
+func maxBig(a *big.Int, b *big.Int) *big.Int {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+func (s *Sender) track(ctx context.Context, signed *types.Transaction, chainID *big.Int, from common.Address, originalHash string, bumpCount int) {
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		slog.ErrorContext(ctx, "error encoding bot transaction", "tx_hash", signed.Hash().Hex(), "error", err)
+		return
+	}
+
+	hash := strings.ToLower(signed.Hash().Hex())
+	oldHash := ""
+	if originalHash == "" {
+		originalHash = hash
+	}
+	record := &structs.BotTransaction{
+		Hash:         hash,
+		OriginalHash: originalHash,
+		ChainId:      chainID.Int64(),
+		FromAddress:  strings.ToLower(from.Hex()),
+		ToAddress:    strings.ToLower(signed.To().Hex()),
+		Nonce:        signed.Nonce(),
+		Data:         hexutil.Encode(signed.Data()),
+		GasLimit:     signed.Gas(),
+		GasFeeCap:    signed.GasFeeCap().String(),
+		GasTipCap:    signed.GasTipCap().String(),
+		RawTx:        hexutil.Encode(raw),
+		BumpCount:    bumpCount,
+		Status:       structs.BotTransactionStatusPending,
+		SubmittedAt:  time.Now().Unix(),
+		UpdatedAt:    time.Now().Unix(),
+	}
+
+	s.pendingMu.Lock()
+	group := s.pending[originalHash]
+	if group == nil {
+		group = &pendingTxGroup{}
+		s.pending[originalHash] = group
+	}
+	if group.current != nil {
+		oldHash = group.current.Hash
+	}
+	group.hashes = append([]string{hash}, group.hashes...)
+	group.current = record
+	s.pendingMu.Unlock()
+
+	if s.store == nil {
+		return
+	}
+	if oldHash != "" {
+		err = s.store.ReplaceBotTransaction(ctx, oldHash, record)
+	} else {
+		err = s.store.RecordBotTransaction(ctx, record)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "error persisting bot transaction", "tx_hash", hash, "error", err)
+	}
+}
+
+// pendingCount is the number of transactions still awaiting a receipt, with
+// a transaction and its gas-bumped replacements counted once.
+func (s *Sender) pendingCount() int {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	return len(s.pending)
+}
+
+// groupHashes returns every hash broadcast for the same nonce as hash, newest
+// first, so callers holding the original hash can still find a replacement.
+func (s *Sender) groupHashes(ctx context.Context, hash string) []string {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if s == nil {
+		return []string{hash}
+	}
+
+	s.pendingMu.Lock()
+	for _, group := range s.pending {
+		for _, groupHash := range group.hashes {
+			if groupHash == hash {
+				hashes := append([]string{}, group.hashes...)
+				s.pendingMu.Unlock()
+				return hashes
+			}
+		}
+	}
+	s.pendingMu.Unlock()
+
+	if s.store != nil {
+		hashes, err := s.store.GetBotTransactionGroupHashes(ctx, hash)
+		if err != nil {
+			slog.ErrorContext(ctx, "error loading bot transaction group", "tx_hash", hash, "error", err)
+		} else if len(hashes) > 0 {
+			return hashes
+		}
+	}
+	return []string{hash}
+}
+
+// receiptForGroup returns the receipt of whichever broadcast in hash's group
+// was mined, or nil if none has been.
+func (s *Sender) receiptForGroup(ctx context.Context, client *ethclient.Client, hash string) (*types.Receipt, error) {
+	for _, groupHash := range s.groupHashes(ctx, hash) {
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(groupHash))
+		if err != nil {
+			if errors.Is(err, ethereum.NotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("error loading transfer receipt %s: %w", groupHash, err)
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Sender) waitMined(ctx context.Context, hash string) (*types.Receipt, error) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := s.receiptForGroup(ctx, s.client, hash)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// monitor reloads unsettled transactions from the store and watches them for
+// confirmation, rebroadcast and gas bumps until ctx is cancelled. Anything
+// still pending then is picked up from the store on the next start.
+func (s *Sender) monitor(ctx context.Context) {
+	s.loadPending(ctx)
+
+	ticker := time.NewTicker(s.monitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkPending(ctx)
+		}
+	}
+}
+
+func (s *Sender) loadPending(ctx context.Context) {
+	if s.store == nil {
+		return
+	}
+
+	s.mu.Lock()
+	chainID, err := s.chainIDValue(ctx)
+	var from common.Address
+	if err == nil {
+		from, err = s.resolveFrom(ctx)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		slog.ErrorContext(ctx, "error loading pending bot transactions", "error", err)
+		return
+	}
+
+	txs, err := s.store.GetPendingBotTransactions(ctx, chainID.Int64(), from.Hex())
+	if err != nil {
+		slog.ErrorContext(ctx, "error loading pending bot transactions", "error", err)
+		return
+	}
+
+	for _, tx := range txs {
+		hashes, err := s.store.GetBotTransactionGroupHashes(ctx, tx.Hash)
+		if err != nil || len(hashes) == 0 {
+			hashes = []string{tx.Hash}
+		}
+		s.pendingMu.Lock()
+		if existing := s.pending[tx.OriginalHash]; existing == nil || existing.current.BumpCount < tx.BumpCount {
+			s.pending[tx.OriginalHash] = &pendingTxGroup{hashes: hashes, current: tx}
+		}
+		s.pendingMu.Unlock()
+	}
+	if len(txs) > 0 {
+		slog.InfoContext(ctx, "resumed tracking pending bot transactions", "chain_id", chainID.Int64(), "count", len(txs))
+	}
+}
+
+func (s *Sender) checkPending(ctx context.Context) {
+	s.pendingMu.Lock()
+	groups := make(map[string]*pendingTxGroup, len(s.pending))
+	for originalHash, group := range s.pending {
+		groups[originalHash] = &pendingTxGroup{hashes: append([]string{}, group.hashes...), current: group.current}
+	}
+	s.pendingMu.Unlock()
+	if len(groups) == 0 {
+		return
+	}
+
+	confirmedNonces := map[string]uint64{}
+
+	for originalHash, group := range groups {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var receipt *types.Receipt
+		for _, hash := range group.hashes {
+			r, err := s.client.TransactionReceipt(ctx, common.HexToHash(hash))
+			if err == nil && r != nil {
+				receipt = r
+				break
+			}
+		}
+
+		switch {
+		case receipt != nil:
+			status := structs.BotTransactionStatusConfirmed
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				status = structs.BotTransactionStatusFailed
+			}
+			s.settle(ctx, originalHash, strings.ToLower(receipt.TxHash.Hex()), status)
+		case s.nonceConsumed(ctx, confirmedNonces, group.current):
+			// Some other transaction consumed this nonce.
+			s.settle(ctx, originalHash, "", structs.BotTransactionStatusDropped)
+		case time.Since(time.Unix(group.current.SubmittedAt, 0)) >= s.stuckAfter && group.current.BumpCount < s.maxBumps:
+			if err := s.bump(ctx, originalHash, group.current); err != nil {
+				slog.ErrorContext(ctx, "error bumping gas for bot transaction", "tx_hash", group.current.Hash, "error", err)
+			}
+		default:
+			s.rebroadcastIfMissing(ctx, group.current)
+		}
+	}
+}
+
+// nonceConsumed reports whether the account that sent current has already
+// mined a transaction at or past current's nonce. Lookups are memoized per
+// address within one monitor pass.
+func (s *Sender) nonceConsumed(ctx context.Context, confirmed map[string]uint64, current *structs.BotTransaction) bool {
+	address := strings.ToLower(current.FromAddress)
+	nonce, ok := confirmed[address]
+	if !ok {
+		var err error
+		nonce, err = s.client.NonceAt(ctx, common.HexToAddress(address), nil)
+		if err != nil {
+			slog.ErrorContext(ctx, "error loading confirmed bot nonce", "address", address, "error", err)
+			return false
+		}
+		confirmed[address] = nonce
+	}
+	return current.Nonce < nonce
+}
+
+func (s *Sender) settle(ctx context.Context, originalHash string, minedHash string, status string) {
+	s.pendingMu.Lock()
+	delete(s.pending, originalHash)
+	s.pendingMu.Unlock()
+
+	if s.store == nil {
+		return
+	}
+	if err := s.store.SettleBotTransactionGroup(ctx, originalHash, minedHash, status); err != nil {
+		slog.ErrorContext(ctx, "error settling bot transaction", "tx_hash", originalHash, "status", status, "error", err)
+	}
+}
+
+// bump re-signs current at the same nonce with fees raised by at least 12.5%
+// (or to the current network suggestion, if higher) and broadcasts it.
+func (s *Sender) bump(ctx context.Context, originalHash string, current *structs.BotTransaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chainID, err := s.chainIDValue(ctx)
+	if err != nil {
+		return err
+	}
+	from, err := s.resolveFrom(ctx)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(from.Hex(), current.FromAddress) {
+		// The key was rotated; only the old key can replace this nonce, so
+		// keep rebroadcasting it as-is.
+		s.rebroadcastIfMissing(ctx, current)
+		return nil
+	}
+	data, err := hexutil.Decode(current.Data)
+	if err != nil {
+		return fmt.Errorf("error decoding stored call data: %s", err)
+	}
+	oldTip, _ := new(big.Int).SetString(current.GasTipCap, 10)
+	oldFeeCap, _ := new(big.Int).SetString(current.GasFeeCap, 10)
+	if oldFeeCap == nil {
+		return fmt.Errorf("invalid stored gas fee cap %q", current.GasFeeCap)
+	}
+
+	suggestedTip, suggestedFeeCap, err := s.suggestFees(ctx)
+	if err != nil {
+		return err
+	}
+
+	var tipCap *big.Int
+	if suggestedTip != nil {
+		tipCap = maxBig(bumpFee(oldTip), suggestedTip)
+	}
+	feeCap := maxBig(bumpFee(oldFeeCap), suggestedFeeCap)
+	if tipCap != nil && tipCap.Cmp(feeCap) > 0 {
+		feeCap = tipCap
+	}
+
+	signed, err := s.signer.SignTx(ctx, buildTx(chainID, current.Nonce, common.HexToAddress(current.ToAddress), data, current.GasLimit, tipCap, feeCap), chainID)
+	if err != nil {
+		return fmt.Errorf("error signing replacement transaction: %s", err)
+	}
+	if err := s.client.SendTransaction(ctx, signed); err != nil && !isAlreadyKnownError(err) {
+		return fmt.Errorf("error sending replacement transaction: %s", err)
+	}
+
+	slog.InfoContext(ctx, "bumped gas for bot transaction", "nonce", current.Nonce, "tx_hash", current.Hash, "replacement_tx_hash", signed.Hash().Hex())
+	s.track(ctx, signed, chainID, from, originalHash, current.BumpCount+1)
+	return nil
+}
+
+// rebroadcastIfMissing re-sends the stored raw transaction when the node no
+// longer knows about it, e.g. after an RPC node restart evicted its mempool.
+func (s *Sender) rebroadcastIfMissing(ctx context.Context, current *structs.BotTransaction) {
+	_, _, err := s.client.TransactionByHash(ctx, common.HexToHash(current.Hash))
+	if err == nil || !errors.Is(err, ethereum.NotFound) {
+		return
+	}
+
+	raw, err := hexutil.Decode(current.RawTx)
+	if err != nil {
+		slog.ErrorContext(ctx, "error decoding stored bot transaction", "tx_hash", current.Hash, "error", err)
+		return
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		slog.ErrorContext(ctx, "error decoding stored bot transaction", "tx_hash", current.Hash, "error", err)
+		return
+	}
+	if err := s.client.SendTransaction(ctx, tx); err != nil && !isAlreadyKnownError(err) {
+		slog.ErrorContext(ctx, "error rebroadcasting bot transaction", "tx_hash", current.Hash, "error", err)
+	}
+}
+
+func isNonceTooLowError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") || strings.Contains(msg, "replacement transaction underpriced")
+}
+
+func isAlreadyKnownError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
+
+func envPositiveInt(key string, fallback int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	seconds := envPositiveInt(key, 0)
+	if seconds == 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,253 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// fakeNode answers the JSON-RPC calls the sender makes and records every
+// transaction broadcast to it. Nothing is ever mined.
+type fakeNode struct {
+	mu      sync.Mutex
+	baseFee *big.Int
+	tip     *big.Int
+	sent    []*types.Transaction
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	n.mu.Lock()
+	baseFee, tip := n.baseFee, n.tip
+	n.mu.Unlock()
+
+	var result any
+	switch req.Method {
+	case "eth_chainId":
+		result = hexutil.EncodeBig(big.NewInt(80094))
+	case "eth_estimateGas":
+		result = hexutil.EncodeUint64(50000)
+	case "eth_getTransactionCount":
+		result = hexutil.EncodeUint64(0)
+	case "eth_getBlockByNumber":
+		result = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0), BaseFee: baseFee}
+	case "eth_maxPriorityFeePerGas":
+		result = hexutil.EncodeBig(tip)
+	case "eth_sendRawTransaction":
+		var raw hexutil.Bytes
+		tx := new(types.Transaction)
+		if err := json.Unmarshal(req.Params[0], &raw); err != nil || tx.UnmarshalBinary(raw) != nil {
+			http.Error(w, "bad raw transaction", http.StatusBadRequest)
+			return
+		}
+		// Widen the window in which unserialized senders would interleave.
+		time.Sleep(time.Millisecond)
+		n.mu.Lock()
+		n.sent = append(n.sent, tx)
+		n.mu.Unlock()
+		result = tx.Hash()
+	case "eth_getTransactionReceipt", "eth_getTransactionByHash":
+		result = nil
+	default:
+		http.Error(w, "unexpected method "+req.Method, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+}
+
+func (n *fakeNode) sentTxs() []*types.Transaction {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*types.Transaction{}, n.sent...)
+}
+
+func newTestSender(t *testing.T, node *fakeNode) *Sender {
+	t.Helper()
+	srv := httptest.NewServer(node)
+	t.Cleanup(srv.Close)
+
+	client, err := ethclient.Dial(srv.URL)
+	if err != nil {
+		t.Fatalf("dialing fake node: %s", err)
+	}
+	t.Cleanup(client.Close)
+
+	signer, err := NewPrivateKeySigner(testSignerKeyA)
+	if err != nil {
+		t.Fatalf("creating signer: %s", err)
+	}
+	return newSender(client, signer)
+}
+
+func TestBumpFeeClearsReplacementThreshold(t *testing.T) {
+	cases := []struct {
+		fee  int64
+		want int64
+	}{
+		{fee: 0, want: 0},
+		{fee: 1, want: 2},
+		{fee: 8, want: 9},
+		{fee: 100, want: 113},
+		{fee: 1_000_000_000, want: 1_125_000_000},
+	}
+
+	for _, tc := range cases {
+		got := bumpFee(big.NewInt(tc.fee))
+		if got.Int64() != tc.want {
+			t.Fatalf("bumpFee(%d) = %s; want %d", tc.fee, got, tc.want)
+		}
+		minimum := new(big.Int).Div(new(big.Int).Mul(big.NewInt(tc.fee), big.NewInt(11)), big.NewInt(10))
+		if got.Cmp(minimum) < 0 {
+			t.Fatalf("bumpFee(%d) = %s is below the 10%% replacement bump", tc.fee, got)
+		}
+	}
+}
+
+func TestBuildTxChoosesFeeModel(t *testing.T) {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	chainID := big.NewInt(80094)
+
+	dynamic := buildTx(chainID, 7, to, []byte{0x01}, 21000, big.NewInt(2), big.NewInt(10))
+	if dynamic.Type() != types.DynamicFeeTxType {
+		t.Fatalf("expected dynamic fee tx, got type %d", dynamic.Type())
+	}
+	if dynamic.Nonce() != 7 || dynamic.GasTipCap().Int64() != 2 || dynamic.GasFeeCap().Int64() != 10 {
+		t.Fatalf("unexpected dynamic tx fields: nonce=%d tip=%s cap=%s", dynamic.Nonce(), dynamic.GasTipCap(), dynamic.GasFeeCap())
+	}
+
+	legacy := buildTx(chainID, 7, to, []byte{0x01}, 21000, nil, big.NewInt(10))
+	if legacy.Type() != types.LegacyTxType {
+		t.Fatalf("expected legacy tx, got type %d", legacy.Type())
+	}
+	if legacy.GasPrice().Int64() != 10 {
+		t.Fatalf("legacy gas price = %s; want 10", legacy.GasPrice())
+	}
+}
+
+func TestSendErrorClassification(t *testing.T) {
+	if !isNonceTooLowError(errors.New("nonce too low: next nonce 5, tx nonce 4")) {
+		t.Fatalf("expected nonce too low to be detected")
+	}
+	if isNonceTooLowError(errors.New("insufficient funds for gas")) {
+		t.Fatalf("unexpected nonce classification")
+	}
+	if !isAlreadyKnownError(errors.New("already known")) {
+		t.Fatalf("expected already known to be detected")
+	}
+}
+
+func TestGroupHashesWithoutSender(t *testing.T) {
+	var sender *Sender
+	hashes := sender.groupHashes(context.Background(), " 0xABC ")
+	if len(hashes) != 1 || hashes[0] != "0xabc" {
+		t.Fatalf("groupHashes = %v; want [0xabc]", hashes)
+	}
+}
+
+func TestSendSerializesNoncesAcrossConcurrentCallers(t *testing.T) {
+	node := &fakeNode{baseFee: big.NewInt(10), tip: big.NewInt(2)}
+	sender := newTestSender(t, node)
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	const callers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := sender.send(context.Background(), to, []byte{0x01}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("send failed: %s", err)
+	}
+
+	sent := node.sentTxs()
+	if len(sent) != callers {
+		t.Fatalf("broadcast %d transactions; want %d", len(sent), callers)
+	}
+	for i, tx := range sent {
+		if tx.Nonce() != uint64(i) {
+			t.Fatalf("transaction %d broadcast with nonce %d; want nonces handed out in order", i, tx.Nonce())
+		}
+	}
+	if got := sender.pendingCount(); got != callers {
+		t.Fatalf("tracking %d pending transactions; want %d", got, callers)
+	}
+}
+
+func TestCheckPendingReplacesStuckTransactionAtSameNonce(t *testing.T) {
+	node := &fakeNode{baseFee: big.NewInt(10), tip: big.NewInt(2)}
+	sender := newTestSender(t, node)
+	sender.stuckAfter = time.Minute
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	original, err := sender.send(context.Background(), to, []byte{0x01})
+	if err != nil {
+		t.Fatalf("send failed: %s", err)
+	}
+	originalHash := hexutil.Encode(original.Hash().Bytes())
+	sender.pendingMu.Lock()
+	sender.pending[originalHash].current.SubmittedAt = time.Now().Add(-2 * time.Minute).Unix()
+	sender.pendingMu.Unlock()
+
+	// The network's suggestion now beats a 12.5% bump, so the replacement
+	// should follow it.
+	node.mu.Lock()
+	node.tip = big.NewInt(100)
+	node.mu.Unlock()
+
+	sender.checkPending(context.Background())
+
+	sent := node.sentTxs()
+	if len(sent) != 2 {
+		t.Fatalf("broadcast %d transactions; want the original and one replacement", len(sent))
+	}
+	replacement := sent[1]
+	if replacement.Nonce() != original.Nonce() {
+		t.Fatalf("replacement nonce %d; want %d", replacement.Nonce(), original.Nonce())
+	}
+	if replacement.GasTipCap().Int64() != 100 || replacement.GasFeeCap().Int64() != 120 {
+		t.Fatalf("replacement fees tip=%s cap=%s; want tip=100 cap=120", replacement.GasTipCap(), replacement.GasFeeCap())
+	}
+	if replacement.GasFeeCap().Cmp(bumpFee(original.GasFeeCap())) < 0 {
+		t.Fatalf("replacement fee cap %s is below the bump of %s", replacement.GasFeeCap(), original.GasFeeCap())
+	}
+
+	sender.pendingMu.Lock()
+	group := sender.pending[originalHash]
+	sender.pendingMu.Unlock()
+	if group == nil || group.current.BumpCount != 1 || len(group.hashes) != 2 {
+		t.Fatalf("pending group = %+v; want the replacement tracked under the original hash", group)
+	}
+	if group.hashes[0] != hexutil.Encode(replacement.Hash().Bytes()) {
+		t.Fatalf("newest group hash %s; want the replacement %s", group.hashes[0], replacement.Hash().Hex())
+	}
+}
@@ -0,0 +1,384 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions for the bot account. Address may change between
+// calls when the underlying key is rotated; the sender resynchronizes its
+// nonce when it does.
+type Signer interface {
+	Address(ctx context.Context) (common.Address, error)
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+const remoteSignerAddressTTL = 5 * time.Minute
+
+// NewSignerFromEnv picks the signer backend named by BOT_SIGNER:
+//
+//   - "key" (default): raw hex private key in BOT_KEY
+//   - "keystore": encrypted JSON keystore at BOT_KEYSTORE_PATH, unlocked with
+//     BOT_KEYSTORE_PASSWORD or the contents of BOT_KEYSTORE_PASSWORD_FILE
+//   - "remote": HTTP signer at BOT_REMOTE_SIGNER_URL, authenticated with
+//     BOT_REMOTE_SIGNER_TOKEN
+func NewSignerFromEnv() (Signer, error) {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("BOT_SIGNER"))) {
+	case "", "key":
+		return NewPrivateKeySigner(os.Getenv("BOT_KEY"))
+	case "keystore":
+		password := os.Getenv("BOT_KEYSTORE_PASSWORD")
+		if passwordFile := strings.TrimSpace(os.Getenv("BOT_KEYSTORE_PASSWORD_FILE")); passwordFile != "" {
+			raw, err := os.ReadFile(passwordFile)
+			if err != nil {
+				return nil, fmt.Errorf("error reading keystore password file: %s", err)
+			}
+			password = strings.TrimRight(string(raw), "\r\n")
+		}
+		return NewKeystoreSigner(os.Getenv("BOT_KEYSTORE_PATH"), password)
+	case "remote":
+		return NewRemoteSigner(os.Getenv("BOT_REMOTE_SIGNER_URL"), os.Getenv("BOT_REMOTE_SIGNER_TOKEN"))
+	default:
+		return nil, fmt.Errorf("unknown BOT_SIGNER %q", os.Getenv("BOT_SIGNER"))
+	}
+}
+
+type privateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func NewPrivateKeySigner(hexKey string) (Signer, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key: %s", err)
+	}
+	return &privateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+func (p *privateKeySigner) Address(ctx context.Context) (common.Address, error) {
+	return p.address, nil
+}
+
+func (p *privateKeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), p.key)
+}
+
+// keystoreSigner holds a key decrypted from a geth-style keystore file. The
+// file is re-read when its modification time changes, so the key can be
+// rotated by replacing the file in place.
+type keystoreSigner struct {
+	path     string
+	password string
+
+	mu      sync.Mutex
+	modTime time.Time
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func NewKeystoreSigner(path string, password string) (Signer, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("keystore path is required")
+	}
+	signer := &keystoreSigner{path: path, password: password}
+	if err := signer.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+func (k *keystoreSigner) reloadIfChanged() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return fmt.Errorf("error reading keystore file: %s", err)
+	}
+	if k.key != nil && info.ModTime().Equal(k.modTime) {
+		return nil
+	}
+
+	raw, err := os.ReadFile(k.path)
+	if err != nil {
+		return fmt.Errorf("error reading keystore file: %s", err)
+	}
+	decrypted, err := keystore.DecryptKey(raw, k.password)
+	if err != nil {
+		return fmt.Errorf("error decrypting keystore file: %s", err)
+	}
+
+	if k.key != nil && decrypted.Address != k.address {
+		slog.Info("bot keystore rotated", "from", k.address.Hex(), "to", decrypted.Address.Hex())
+	}
+	k.key = decrypted.PrivateKey
+	k.address = decrypted.Address
+	k.modTime = info.ModTime()
+	return nil
+}
+
+func (k *keystoreSigner) Address(ctx context.Context) (common.Address, error) {
+	if err := k.reloadIfChanged(); err != nil {
+		return common.Address{}, err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.address, nil
+}
+
+func (k *keystoreSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if err := k.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+	k.mu.Lock()
+	key := k.key
+	k.mu.Unlock()
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+}
+
+// Remote signer protocol. Both endpoints take and return JSON and expect
+// "Authorization: Bearer <token>" when a token is configured.
+//
+//	GET  {base}/address -> {"address": "0x..."}
+//	POST {base}/sign    <- {"chain_id": "80094", "tx": "0x<unsigned tx binary>"}
+//	                    -> {"signed_tx": "0x<signed tx binary>"}
+//
+// The unsigned tx uses the same typed encoding as types.Transaction
+// MarshalBinary. The returned tx must carry the same fields and recover to the
+// advertised address; anything else is rejected.
+type RemoteSignerAddressResponse struct {
+	Address string `json:"address"`
+}
+
+type RemoteSignerSignRequest struct {
+	ChainId string `json:"chain_id"`
+	Tx      string `json:"tx"`
+}
+
+type RemoteSignerSignResponse struct {
+	SignedTx string `json:"signed_tx"`
+}
+
+type remoteSigner struct {
+	baseURL string
+	token   string
+	client  *http.Client
+
+	mu          sync.Mutex
+	address     common.Address
+	addressedAt time.Time
+}
+
+func NewRemoteSigner(baseURL string, token string) (Signer, error) {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("remote signer url is required")
+	}
+	return &remoteSigner{
+		baseURL: baseURL,
+		token:   strings.TrimSpace(token),
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (r *remoteSigner) do(ctx context.Context, method string, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling remote signer: %s", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("remote signer returned %d: %s", res.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding remote signer response: %s", err)
+	}
+	return nil
+}
+
+func (r *remoteSigner) Address(ctx context.Context) (common.Address, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.address != (common.Address{}) && time.Since(r.addressedAt) < remoteSignerAddressTTL {
+		return r.address, nil
+	}
+
+	var res RemoteSignerAddressResponse
+	if err := r.do(ctx, http.MethodGet, "/address", nil, &res); err != nil {
+		return common.Address{}, err
+	}
+	if !common.IsHexAddress(res.Address) {
+		return common.Address{}, fmt.Errorf("remote signer returned invalid address %q", res.Address)
+	}
+	r.address = common.HexToAddress(res.Address)
+	r.addressedAt = time.Now()
+	return r.address, nil
+}
+
+func (r *remoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	address, err := r.Address(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unsigned, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("error encoding unsigned transaction: %s", err)
+	}
+
+	var res RemoteSignerSignResponse
+	err = r.do(ctx, http.MethodPost, "/sign", RemoteSignerSignRequest{ChainId: chainID.String(), Tx: hexutil.Encode(unsigned)}, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := hexutil.Decode(res.SignedTx)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned invalid signed tx: %s", err)
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("remote signer returned invalid signed tx: %s", err)
+	}
+	if err := matchSignedTx(tx, signed, chainID, address); err != nil {
+		// The remote key may have been rotated; refetch the address next time.
+		r.mu.Lock()
+		r.address = common.Address{}
+		r.mu.Unlock()
+		return nil, err
+	}
+	return signed, nil
+}
+
+// matchSignedTx rejects a signed tx that differs from what was asked for or
+// was signed by a different key.
+func matchSignedTx(unsigned *types.Transaction, signed *types.Transaction, chainID *big.Int, address common.Address) error {
+	if unsigned.Type() != signed.Type() ||
+		unsigned.Nonce() != signed.Nonce() ||
+		unsigned.Gas() != signed.Gas() ||
+		unsigned.GasFeeCap().Cmp(signed.GasFeeCap()) != 0 ||
+		unsigned.GasTipCap().Cmp(signed.GasTipCap()) != 0 ||
+		unsigned.Value().Cmp(signed.Value()) != 0 ||
+		!bytes.Equal(unsigned.Data(), signed.Data()) ||
+		unsigned.To() == nil || signed.To() == nil || *unsigned.To() != *signed.To() {
+		return fmt.Errorf("remote signer returned a transaction that does not match the request")
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("error recovering remote signer address: %s", err)
+	}
+	if sender != address {
+		return fmt.Errorf("remote signer signed with %s; expected %s", sender.Hex(), address.Hex())
+	}
+	return nil
+}
+
+// RemoteSignerHandler serves the remote signer protocol on top of any Signer.
+// It is the local stand-in for a real signing service and is what tests and
+// development setups point BOT_REMOTE_SIGNER_URL at.
+func RemoteSignerHandler(signer Signer, token string) http.Handler {
+	token = strings.TrimSpace(token)
+	mux := http.NewServeMux()
+	authorized := func(r *http.Request) bool {
+		return token == "" || r.Header.Get("Authorization") == "Bearer "+token
+	}
+
+	mux.HandleFunc("GET /address", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		address, err := signer.Address(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RemoteSignerAddressResponse{Address: address.Hex()})
+	})
+
+	mux.HandleFunc("POST /sign", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req RemoteSignerSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		chainID, ok := new(big.Int).SetString(req.ChainId, 10)
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid chain_id"))
+			return
+		}
+		raw, err := hexutil.Decode(req.Tx)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid tx"))
+			return
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid tx"))
+			return
+		}
+
+		signed, err := signer.SignTx(r.Context(), tx, chainID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		encoded, err := signed.MarshalBinary()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RemoteSignerSignResponse{SignedTx: hexutil.Encode(encoded)})
+	})
+
+	return mux
+}
@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+const (
+	testSignerKeyA = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	testSignerKeyB = "8f2a55949038a9610f50fb23b5883af3b4ecb3c3bb792cbcefbd1542c692be63"
+)
+
+func testSignerTx() *types.Transaction {
+	return buildTx(big.NewInt(80094), 7, common.HexToAddress("0x00000000000000000000000000000000000000aa"), []byte{0xa9, 0x05, 0x9c, 0xbb}, 60000, big.NewInt(1), big.NewInt(100))
+}
+
+func assertSignedBy(t *testing.T, signed *types.Transaction, want common.Address) {
+	t.Helper()
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(80094)), signed)
+	if err != nil {
+		t.Fatalf("recovering sender: %s", err)
+	}
+	if sender != want {
+		t.Fatalf("signed by %s; want %s", sender.Hex(), want.Hex())
+	}
+}
+
+func TestRemoteSignerRoundTrip(t *testing.T) {
+	local, err := NewPrivateKeySigner(testSignerKeyA)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want, _ := local.Address(context.Background())
+
+	server := httptest.NewServer(RemoteSignerHandler(local, "secret"))
+	defer server.Close()
+
+	remote, err := NewRemoteSigner(server.URL, "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	address, err := remote.Address(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if address != want {
+		t.Fatalf("address = %s; want %s", address.Hex(), want.Hex())
+	}
+
+	tx := testSignerTx()
+	signed, err := remote.SignTx(context.Background(), tx, big.NewInt(80094))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assertSignedBy(t, signed, want)
+	if signed.Nonce() != tx.Nonce() || signed.Gas() != tx.Gas() {
+		t.Fatalf("signed tx fields changed")
+	}
+}
+
+func TestRemoteSignerRejectsBadToken(t *testing.T) {
+	local, _ := NewPrivateKeySigner(testSignerKeyA)
+	server := httptest.NewServer(RemoteSignerHandler(local, "secret"))
+	defer server.Close()
+
+	remote, _ := NewRemoteSigner(server.URL, "wrong")
+	if _, err := remote.Address(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+}
+
+// swappingSigner advertises one key and signs with another, as a rotated or
+// misconfigured remote signer would.
+type swappingSigner struct {
+	advertised Signer
+	signing    Signer
+}
+
+func (s *swappingSigner) Address(ctx context.Context) (common.Address, error) {
+	return s.advertised.Address(ctx)
+}
+
+func (s *swappingSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.signing.SignTx(ctx, tx, chainID)
+}
+
+func TestRemoteSignerRejectsWrongKey(t *testing.T) {
+	a, _ := NewPrivateKeySigner(testSignerKeyA)
+	b, _ := NewPrivateKeySigner(testSignerKeyB)
+	server := httptest.NewServer(RemoteSignerHandler(&swappingSigner{advertised: a, signing: b}, ""))
+	defer server.Close()
+
+	remote, _ := NewRemoteSigner(server.URL, "")
+	if _, err := remote.SignTx(context.Background(), testSignerTx(), big.NewInt(80094)); err == nil {
+		t.Fatalf("expected mismatched signer to be rejected")
+	}
+}
+
+func writeTestKeystore(t *testing.T, path string, hexKey string, password string) common.Address {
+	t.Helper()
+	privateKey, err := crypto.HexToECDSA(hexKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	encrypted, err := keystore.EncryptKey(key, password, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := os.WriteFile(path, encrypted, 0600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return key.Address
+}
+
+func TestKeystoreSignerRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.json")
+	first := writeTestKeystore(t, path, testSignerKeyA, "pw")
+
+	signer, err := NewKeystoreSigner(path, "pw")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	signed, err := signer.SignTx(context.Background(), testSignerTx(), big.NewInt(80094))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assertSignedBy(t, signed, first)
+
+	second := writeTestKeystore(t, path, testSignerKeyB, "pw")
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	address, err := signer.Address(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if address != second {
+		t.Fatalf("address = %s; want rotated %s", address.Hex(), second.Hex())
+	}
+
+	if _, err := NewKeystoreSigner(path, "wrong"); err == nil {
+		t.Fatalf("expected wrong password to fail")
+	}
+}
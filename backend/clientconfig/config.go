@@ -141,6 +141,29 @@ func (c *Config) PrimaryToken() (Token, error) {
 	return findToken(c.Tokens, c.Community.PrimaryToken)
 }
 
+// TokenForChain returns the community token deployed on chainID. The primary
+// token is returned for the active chain; on other chains the token with the
+// primary token's symbol is used, so a migration can run the same currency on
+// two chains side by side.
+func (c *Config) TokenForChain(chainID int) (Token, error) {
+	if c == nil {
+		return Token{}, fmt.Errorf("client config is nil")
+	}
+	primary, err := c.PrimaryToken()
+	if err != nil {
+		return Token{}, err
+	}
+	if chainID == 0 || chainID == primary.ChainID {
+		return primary, nil
+	}
+	for _, token := range c.Tokens {
+		if token.ChainID == chainID && strings.EqualFold(strings.TrimSpace(token.Symbol), strings.TrimSpace(primary.Symbol)) {
+			return token, nil
+		}
+	}
+	return Token{}, fmt.Errorf("no %s token configured for chain %d", primary.Symbol, chainID)
+}
+
 func (c *Config) PrimaryAccount() (Account, error) {
 	if c == nil {
 		return Account{}, fmt.Errorf("client config is nil")
@@ -172,6 +195,23 @@ func (c *Config) ReadRPCURL() string {
 	return c.PrimaryRPCURL()
 }
 
+// ReadRPCURLForChain is ReadRPCURL for an arbitrary configured chain. Chains
+// other than the active one read RPC_URL_<chain id> from the environment and
+// fall back to the chain's node URL.
+func (c *Config) ReadRPCURLForChain(chainID int) string {
+	if c == nil {
+		return ""
+	}
+	if chainID == 0 || chainID == c.ActiveChainID() {
+		return c.ReadRPCURL()
+	}
+	if _, value := firstEnv(fmt.Sprintf("RPC_URL_%d", chainID)); value != "" {
+		return value
+	}
+	chain := c.Chains[strconv.Itoa(chainID)]
+	return strings.TrimSpace(chain.Node.URL)
+}
+
 func loadRemote(ctx context.Context) (*Config, error) {
 	// An explicit single-community config URL wins when set (a raw config object).
 	explicit, err := explicitConfigURL()
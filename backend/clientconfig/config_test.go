@@ -186,6 +186,63 @@ func TestLoadLocalOnlyUsesFallbackFile(t *testing.T) {
 	}
 }
 
+func TestTokenForChainMatchesPrimarySymbol(t *testing.T) {
+	clearExtrasEnv(t)
+	t.Setenv("RPC_URL", "")
+	t.Setenv("CLIENT_READ_RPC_URL", "")
+	t.Setenv("NEXT_PUBLIC_RPC_URL", "")
+	t.Setenv("RPC_URL_42220", "https://forno.celo.org")
+
+	body := strings.Replace(testConfigJSON, `    }
+  },
+  "accounts":`, `    },
+    "42220:0x5555555555555555555555555555555555555555": {
+      "standard": "erc20",
+      "name": "Test Token",
+      "address": "0x5555555555555555555555555555555555555555",
+      "symbol": "TEST",
+      "decimals": 6,
+      "chain_id": 42220
+    },
+    "42220:0x6666666666666666666666666666666666666666": {
+      "standard": "erc20",
+      "name": "Other",
+      "address": "0x6666666666666666666666666666666666666666",
+      "symbol": "OTHER",
+      "decimals": 18,
+      "chain_id": 42220
+    }
+  },
+  "accounts":`, 1)
+
+	cfg, err := parse([]byte(body), "test")
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+
+	primary, err := cfg.TokenForChain(80094)
+	if err != nil || primary.Address != "0x1111111111111111111111111111111111111111" {
+		t.Fatalf("TokenForChain(80094) = %#v, %v", primary, err)
+	}
+	celo, err := cfg.TokenForChain(42220)
+	if err != nil {
+		t.Fatalf("TokenForChain(42220) error = %v", err)
+	}
+	if celo.Address != "0x5555555555555555555555555555555555555555" || celo.Decimals != 6 {
+		t.Fatalf("TokenForChain(42220) = %#v", celo)
+	}
+	if _, err := cfg.TokenForChain(1); err == nil {
+		t.Fatalf("expected error for chain without a community token")
+	}
+
+	if got := cfg.ReadRPCURLForChain(42220); got != "https://forno.celo.org" {
+		t.Fatalf("ReadRPCURLForChain(42220) = %q", got)
+	}
+	if got := cfg.ReadRPCURLForChain(80094); got != cfg.ReadRPCURL() {
+		t.Fatalf("ReadRPCURLForChain(80094) = %q, want %q", got, cfg.ReadRPCURL())
+	}
+}
+
 func TestExtractCommunityConfigReadsJSONField(t *testing.T) {
 	// The communities API wraps the full config in a "json" envelope field.
 	body := []byte(`{"alias":"test.wallet","chain_id":80094,"json":` + testConfigJSON + `,"active":true}`)
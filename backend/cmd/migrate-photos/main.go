@@ -0,0 +1,74 @@
+// Command migrate-photos moves workflow photo bytes between storage
+// backends, e.g. out of Postgres after switching PHOTO_STORAGE_BACKEND:
+//
+//	go run ./cmd/migrate-photos -from postgres -to s3
+//
+// It is safe to stop and rerun; each photo is repointed only after its copy
+// is written.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/SFLuv/app/backend/bootstrap"
+	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/photostore"
+)
+
+func main() {
+	bootstrap.LoadEnv()
+
+	from := flag.String("from", photostore.BackendPostgres, "backend to move photos out of (postgres, local, s3)")
+	to := flag.String("to", bootstrap.PhotoStorageBackend(), "backend to move photos into; defaults to PHOTO_STORAGE_BACKEND")
+	batchSize := flag.Int("batch", 100, "photos to move per batch")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pools, err := bootstrap.OpenDBPools(false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer pools.Close()
+
+	appLogger, err := bootstrap.NewAppLogger()
+	if err != nil {
+		log.Fatal(fmt.Sprintf("error initializing app logger: %s", err))
+	}
+	defer appLogger.Close()
+
+	appDb := db.App(pools.App, appLogger)
+	source, err := bootstrap.NewPhotoStore(appDb, *from)
+	if err != nil {
+		log.Fatal(err)
+	}
+	target, err := bootstrap.NewPhotoStore(appDb, *to)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	total := 0
+	for ctx.Err() == nil {
+		moved, err := appDb.MigrateWorkflowPhotos(ctx, source, target, *batchSize)
+		total += moved
+		if err != nil {
+			log.Fatalf("moved %d photos before error: %s", total, err)
+		}
+		if moved == 0 {
+			break
+		}
+		fmt.Printf("moved %d photos from %s to %s\n", total, source.Backend(), target.Backend())
+	}
+
+	if ctx.Err() != nil {
+		fmt.Printf("photo migration interrupted after moving %d photos; rerun to continue\n", total)
+		return
+	}
+	fmt.Printf("photo migration complete: %d photos moved from %s to %s\n", total, source.Backend(), target.Backend())
+}
@@ -2,14 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/SFLuv/app/backend/bootstrap"
+	"github.com/SFLuv/app/backend/lifecycle"
 )
 
 func main() {
@@ -19,23 +22,39 @@ func main() {
 
 	pools, err := bootstrap.OpenDBPools(true)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("error opening database pools", "error", err)
+		os.Exit(1)
 	}
-	defer pools.Close()
 
 	appLogger, err := bootstrap.NewAppLogger()
 	if err != nil {
-		log.Fatal(fmt.Sprintf("error initializing app logger: %s", err))
+		pools.Close()
+		slog.Error("error initializing app logger", "error", err)
+		os.Exit(1)
 	}
 	defer appLogger.Close()
 
+	// Closers run last-registered first, and the pools have to outlive every
+	// worker, so they are registered before anything else.
+	lc := lifecycle.New(context.Background())
+	lc.OnClose(pools.Close)
+
+	// Once the app logger is up, startup failures go through it so they land
+	// in the structured log alongside everything else.
+	fatal := func(msg string, err error) {
+		appLogger.Errorf(ctx, "%s: %s", msg, err)
+		pools.Close()
+		appLogger.Close()
+		os.Exit(1)
+	}
+
 	if err := bootstrap.RunPendingMigrations(ctx, pools, appLogger); err != nil {
-		log.Fatal(err)
+		fatal("error running migrations", err)
 	}
 
-	handler, err := bootstrap.NewServerHandler(ctx, pools, appLogger)
+	handler, err := bootstrap.NewServerHandler(lc, pools, appLogger)
 	if err != nil {
-		log.Fatal(err)
+		fatal("error building server handler", err)
 	}
 
 	port := os.Getenv("PORT")
@@ -43,6 +62,7 @@ func main() {
 		port = "8080"
 	}
 
+	servers := []*http.Server{{Addr: fmt.Sprintf(":%s", port), Handler: handler}}
 	certFile := os.Getenv("TLS_CERT_FILE")
 	keyFile := os.Getenv("TLS_KEY_FILE")
 	tlsPort := os.Getenv("TLS_PORT")
@@ -50,24 +70,45 @@ func main() {
 		tlsPort = "8443"
 	}
 	if certFile != "" && keyFile != "" {
+		tlsSrv := &http.Server{Addr: fmt.Sprintf(":%s", tlsPort), Handler: handler}
+		servers = append(servers, tlsSrv)
 		go func() {
-			fmt.Printf("now listening on TLS port %s\n", tlsPort)
-			if err := http.ListenAndServeTLS(fmt.Sprintf(":%s", tlsPort), certFile, keyFile, handler); err != nil {
-				fmt.Println(err)
+			appLogger.Infof(ctx, "now listening on TLS port %s", tlsPort)
+			if err := tlsSrv.ListenAndServeTLS(certFile, keyFile); !errors.Is(err, http.ErrServerClosed) {
+				appLogger.Errorf(ctx, "error listening on TLS port %s: %s", tlsPort, err)
 			}
 		}()
 	}
 
-	srv := &http.Server{Addr: fmt.Sprintf(":%s", port), Handler: handler}
-
 	go func() {
-		<-ctx.Done()
-		fmt.Println("\nshutting down...")
-		srv.Shutdown(context.Background())
+		appLogger.Infof(ctx, "now listening on port %s", port)
+		if err := servers[0].ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			appLogger.Errorf(ctx, "error listening on port %s: %s", port, err)
+			stop()
+		}
 	}()
 
-	fmt.Printf("now listening on port %s\n", port)
-	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
-		fmt.Println(err)
+	<-ctx.Done()
+	appLogger.Infof(ctx, "shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), bootstrap.ShutdownTimeout())
+	defer cancel()
+
+	// Stop taking requests first so nothing queues new payouts, then drain
+	// the background workers and close the pools.
+	var wg sync.WaitGroup
+	for _, srv := range servers {
+		wg.Add(1)
+		go func(srv *http.Server) {
+			defer wg.Done()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				appLogger.Errorf(ctx, "error shutting down listener on %s: %s", srv.Addr, err)
+			}
+		}(srv)
+	}
+	wg.Wait()
+
+	if err := lc.Shutdown(shutdownCtx); err != nil {
+		appLogger.Errorf(ctx, "%s", err)
 	}
+	appLogger.Infof(ctx, "shutdown complete")
 }
@@ -5,16 +5,18 @@ import (
 	"fmt"
 
 	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/photostore"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type AppDB struct {
-	db     *pgxpool.Pool
-	logger *logger.LogCloser
+	db         *pgxpool.Pool
+	logger     *logger.LogCloser
+	photoStore photostore.Store
 }
 
 func App(db *pgxpool.Pool, logger *logger.LogCloser) *AppDB {
-	return &AppDB{db, logger}
+	return &AppDB{db: db, logger: logger}
 }
 
 func (s *AppDB) CreateTables() error {
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/jackc/pgx/v5"
+)
+
+const adminAuditColumns = `
+	id,
+	actor_user_id,
+	api_key_id,
+	action,
+	target_type,
+	target_id,
+	before_state,
+	after_state,
+	request_id,
+	created_at
+`
+
+// adminAuditWhere matches the filter passed as $1..$6 in AdminAuditFilter
+// field order.
+const adminAuditWhere = `
+	($1 = '' OR actor_user_id = $1)
+	AND ($2 = '' OR action = $2)
+	AND ($3 = '' OR target_type = $3)
+	AND ($4 = '' OR target_id = $4)
+	AND ($5 = 0 OR created_at >= $5)
+	AND ($6 = 0 OR created_at <= $6)
+`
+
+func adminAuditFilterArgs(filter structs.AdminAuditFilter) []any {
+	return []any{filter.ActorUserId, filter.Action, filter.TargetType, filter.TargetId, filter.From, filter.To}
+}
+
+func scanAdminAuditEntry(row pgx.Row) (*structs.AdminAuditEntry, error) {
+	entry := &structs.AdminAuditEntry{}
+	var before, after []byte
+	err := row.Scan(
+		&entry.Id,
+		&entry.ActorUserId,
+		&entry.ApiKeyId,
+		&entry.Action,
+		&entry.TargetType,
+		&entry.TargetId,
+		&before,
+		&after,
+		&entry.RequestId,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(before) > 0 {
+		entry.Before = json.RawMessage(before)
+	}
+	if len(after) > 0 {
+		entry.After = json.RawMessage(after)
+	}
+	return entry, nil
+}
+
+// InsertAdminAuditEntry appends an entry. The request id is taken from ctx.
+func (a *AppDB) InsertAdminAuditEntry(ctx context.Context, entry *structs.AdminAuditEntry) error {
+	var before, after []byte
+	if len(entry.Before) > 0 {
+		before = entry.Before
+	}
+	if len(entry.After) > 0 {
+		after = entry.After
+	}
+
+	_, err := a.db.Exec(ctx, `
+		INSERT INTO admin_audit_log(
+			actor_user_id,
+			api_key_id,
+			action,
+			target_type,
+			target_id,
+			before_state,
+			after_state,
+			request_id
+		) VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6::jsonb,
+			$7::jsonb,
+			$8
+		);
+	`, entry.ActorUserId, entry.ApiKeyId, entry.Action, entry.TargetType, entry.TargetId, before, after, logger.RequestID(ctx))
+	if err != nil {
+		return fmt.Errorf("error inserting admin audit entry: %s", err)
+	}
+	return nil
+}
+
+// GetAdminAuditEntries lists audit entries matching filter, newest first.
+func (a *AppDB) GetAdminAuditEntries(ctx context.Context, filter structs.AdminAuditFilter, page, count int) (*structs.AdminAuditListResponse, error) {
+	if page < 0 {
+		page = 0
+	}
+	if count <= 0 {
+		count = 50
+	}
+	if count > 200 {
+		count = 200
+	}
+	args := adminAuditFilterArgs(filter)
+
+	var total int
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*)
+		FROM
+			admin_audit_log
+		WHERE
+			`+adminAuditWhere+`;
+	`, args...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("error counting admin audit entries: %s", err)
+	}
+
+	items, err := a.queryAdminAuditEntries(ctx, args, count, page*count)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.AdminAuditListResponse{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Count: count,
+	}, nil
+}
+
+// ExportAdminAuditEntries returns up to limit entries matching filter, newest
+// first, for the CSV export.
+func (a *AppDB) ExportAdminAuditEntries(ctx context.Context, filter structs.AdminAuditFilter, limit int) ([]*structs.AdminAuditEntry, error) {
+	return a.queryAdminAuditEntries(ctx, adminAuditFilterArgs(filter), limit, 0)
+}
+
+func (a *AppDB) queryAdminAuditEntries(ctx context.Context, filterArgs []any, limit, offset int) ([]*structs.AdminAuditEntry, error) {
+	args := append(filterArgs, limit, offset)
+	rows, err := a.db.Query(ctx, `
+		SELECT
+			`+adminAuditColumns+`
+		FROM
+			admin_audit_log
+		WHERE
+			`+adminAuditWhere+`
+		ORDER BY
+			created_at DESC,
+			id DESC
+		LIMIT
+			$7
+		OFFSET
+			$8;
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting admin audit entries: %s", err)
+	}
+	defer rows.Close()
+
+	items := []*structs.AdminAuditEntry{}
+	for rows.Next() {
+		entry, err := scanAdminAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning admin audit entry: %s", err)
+		}
+		items = append(items, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading admin audit entries: %s", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const apiKeyColumns = `
+	id,
+	name,
+	owner_user_id,
+	prefix,
+	key_hash,
+	scopes,
+	created_at,
+	expires_at,
+	last_used_at,
+	revoked_at,
+	revoked_by_user_id
+`
+
+func scanAPIKey(row pgx.Row) (*structs.APIKey, error) {
+	key := &structs.APIKey{}
+	err := row.Scan(
+		&key.Id,
+		&key.Name,
+		&key.OwnerUserId,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scopes,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.RevokedByUserId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (a *AppDB) CreateAPIKey(ctx context.Context, key *structs.APIKey) (*structs.APIKey, error) {
+	if key.Id == "" {
+		key.Id = uuid.NewString()
+	}
+	row := a.db.QueryRow(ctx, `
+		INSERT INTO api_keys(
+			id,
+			name,
+			owner_user_id,
+			prefix,
+			key_hash,
+			scopes,
+			expires_at
+		) VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7
+		)
+		RETURNING `+apiKeyColumns+`;
+	`, key.Id, key.Name, key.OwnerUserId, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt)
+	created, err := scanAPIKey(row)
+	if err != nil {
+		return nil, fmt.Errorf("error creating api key: %w", err)
+	}
+	return created, nil
+}
+
+func (a *AppDB) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*structs.APIKey, error) {
+	row := a.db.QueryRow(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM
+			api_keys
+		WHERE
+			prefix = $1;
+	`, prefix)
+	return scanAPIKey(row)
+}
+
+// GetAPIKeys lists keys newest first, including revoked and expired ones so
+// the admin list shows their history.
+func (a *AppDB) GetAPIKeys(ctx context.Context) ([]*structs.APIKey, error) {
+	rows, err := a.db.Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM
+			api_keys
+		ORDER BY
+			created_at DESC, id;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*structs.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey returns pgx.ErrNoRows when the key does not exist or was
+// already revoked.
+func (a *AppDB) RevokeAPIKey(ctx context.Context, id string, revokedBy string) (*structs.APIKey, error) {
+	row := a.db.QueryRow(ctx, `
+		UPDATE
+			api_keys
+		SET
+			revoked_at = unix_now(),
+			revoked_by_user_id = $2
+		WHERE
+			id = $1
+		AND
+			revoked_at IS NULL
+		RETURNING `+apiKeyColumns+`;
+	`, id, revokedBy)
+	return scanAPIKey(row)
+}
+
+// TouchAPIKey records a use of the key, at most once a minute so busy
+// integrations do not write on every request.
+func (a *AppDB) TouchAPIKey(ctx context.Context, id string) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			api_keys
+		SET
+			last_used_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			(last_used_at IS NULL OR last_used_at < unix_now() - 60);
+	`, id)
+	return err
+}
@@ -0,0 +1,464 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const emailOutboxColumns = `
+	id,
+	category,
+	user_id,
+	to_email,
+	to_name,
+	from_email,
+	from_name,
+	subject,
+	html,
+	text,
+	attachments,
+	status,
+	attempts,
+	max_attempts,
+	run_at,
+	sink,
+	provider_message_id,
+	last_error,
+	created_at,
+	updated_at,
+	sent_at,
+	bounced_at,
+	request_id
+`
+
+func scanEmailOutboxMessage(row pgx.Row) (*structs.EmailOutboxMessage, error) {
+	msg := &structs.EmailOutboxMessage{}
+	var attachments []byte
+	err := row.Scan(
+		&msg.Id,
+		&msg.Category,
+		&msg.UserId,
+		&msg.ToEmail,
+		&msg.ToName,
+		&msg.FromEmail,
+		&msg.FromName,
+		&msg.Subject,
+		&msg.HTML,
+		&msg.Text,
+		&attachments,
+		&msg.Status,
+		&msg.Attempts,
+		&msg.MaxAttempts,
+		&msg.RunAt,
+		&msg.Sink,
+		&msg.ProviderMessageId,
+		&msg.LastError,
+		&msg.CreatedAt,
+		&msg.UpdatedAt,
+		&msg.SentAt,
+		&msg.BouncedAt,
+		&msg.RequestId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(attachments) > 0 {
+		if err := json.Unmarshal(attachments, &msg.Attachments); err != nil {
+			return nil, fmt.Errorf("error decoding email outbox attachments for %s: %s", msg.Id, err)
+		}
+	}
+	return msg, nil
+}
+
+func truncateEmailOutboxError(message string) string {
+	message = strings.TrimSpace(message)
+	if len(message) <= 800 {
+		return message
+	}
+	return message[:800]
+}
+
+// EnqueueEmail stores msg as pending and returns its id. The outbox worker
+// picks it up on its next drain.
+func (a *AppDB) EnqueueEmail(ctx context.Context, msg *structs.EmailOutboxMessage) (string, error) {
+	toEmail := strings.TrimSpace(msg.ToEmail)
+	if toEmail == "" {
+		return "", fmt.Errorf("email recipient is required")
+	}
+	maxAttempts := msg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	attachments := msg.Attachments
+	if attachments == nil {
+		attachments = []structs.EmailOutboxAttachment{}
+	}
+	attachmentsJSON, err := json.Marshal(attachments)
+	if err != nil {
+		return "", fmt.Errorf("error encoding email outbox attachments: %s", err)
+	}
+
+	id := uuid.NewString()
+	_, err = a.db.Exec(ctx, `
+		INSERT INTO email_outbox(
+			id,
+			category,
+			user_id,
+			to_email,
+			to_name,
+			from_email,
+			from_name,
+			subject,
+			html,
+			text,
+			attachments,
+			max_attempts,
+			request_id
+		) VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5,
+			$6,
+			$7,
+			$8,
+			$9,
+			$10,
+			$11,
+			$12,
+			$13
+		);
+	`, id, msg.Category, msg.UserId, toEmail, msg.ToName, msg.FromEmail, msg.FromName, msg.Subject, msg.HTML, msg.Text, attachmentsJSON, maxAttempts, logger.RequestID(ctx))
+	if err != nil {
+		return "", fmt.Errorf("error enqueueing email to outbox: %s", err)
+	}
+	return id, nil
+}
+
+// LeaseEmailOutboxMessages leases up to limit pending messages that are due,
+// along with sending messages whose lease expired because their worker died.
+func (a *AppDB) LeaseEmailOutboxMessages(ctx context.Context, owner string, leaseSeconds int64, limit int) ([]*structs.EmailOutboxMessage, error) {
+	if limit <= 0 {
+		limit = 1
+	}
+	rows, err := a.db.Query(ctx, `
+		UPDATE
+			email_outbox
+		SET
+			status = 'sending',
+			lease_owner = $1,
+			lease_expires_at = unix_now() + $2,
+			updated_at = unix_now()
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					email_outbox
+				WHERE
+					(status = 'pending' AND run_at <= unix_now())
+				OR
+					(status = 'sending' AND COALESCE(lease_expires_at, 0) <= unix_now())
+				ORDER BY
+					run_at,
+					created_at
+				LIMIT
+					$3
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING
+			`+emailOutboxColumns+`;
+	`, owner, leaseSeconds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error leasing email outbox messages: %s", err)
+	}
+	defer rows.Close()
+
+	messages := []*structs.EmailOutboxMessage{}
+	for rows.Next() {
+		msg, err := scanEmailOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning leased email outbox message: %s", err)
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading leased email outbox messages: %s", err)
+	}
+	return messages, nil
+}
+
+func (a *AppDB) MarkEmailOutboxSent(ctx context.Context, id string, owner string, sink string, providerMessageId string) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			email_outbox
+		SET
+			status = 'sent',
+			attempts = attempts + 1,
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			sink = $3,
+			provider_message_id = $4,
+			last_error = '',
+			sent_at = unix_now(),
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'sending'
+		AND
+			lease_owner = $2;
+	`, id, owner, sink, strings.TrimSpace(providerMessageId))
+	if err != nil {
+		return fmt.Errorf("error marking email outbox message sent: %s", err)
+	}
+	return nil
+}
+
+// RetryEmailOutboxMessage records a failed attempt and schedules the next one
+// after delaySeconds.
+func (a *AppDB) RetryEmailOutboxMessage(ctx context.Context, id string, owner string, delaySeconds int64, lastError string) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			email_outbox
+		SET
+			status = 'pending',
+			attempts = attempts + 1,
+			run_at = unix_now() + $3,
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			last_error = $4,
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'sending'
+		AND
+			lease_owner = $2;
+	`, id, owner, delaySeconds, truncateEmailOutboxError(lastError))
+	if err != nil {
+		return fmt.Errorf("error scheduling email outbox retry: %s", err)
+	}
+	return nil
+}
+
+func (a *AppDB) FailEmailOutboxMessage(ctx context.Context, id string, owner string, lastError string) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			email_outbox
+		SET
+			status = 'failed',
+			attempts = attempts + 1,
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			last_error = $3,
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'sending'
+		AND
+			lease_owner = $2;
+	`, id, owner, truncateEmailOutboxError(lastError))
+	if err != nil {
+		return fmt.Errorf("error failing email outbox message: %s", err)
+	}
+	return nil
+}
+
+// MarkEmailOutboxBounced flags the sent message with providerMessageId as
+// bounced. It returns false when no sent message matches.
+func (a *AppDB) MarkEmailOutboxBounced(ctx context.Context, providerMessageId string, reason string) (bool, error) {
+	providerMessageId = strings.TrimSpace(providerMessageId)
+	if providerMessageId == "" {
+		return false, nil
+	}
+	cmd, err := a.db.Exec(ctx, `
+		UPDATE
+			email_outbox
+		SET
+			status = 'bounced',
+			last_error = $2,
+			bounced_at = unix_now(),
+			updated_at = unix_now()
+		WHERE
+			provider_message_id = $1
+		AND
+			status IN ('sent', 'bounced');
+	`, providerMessageId, truncateEmailOutboxError(reason))
+	if err != nil {
+		return false, fmt.Errorf("error marking email outbox message bounced: %s", err)
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// GetEmailOutboxMessages lists outbox messages for the admin view, newest
+// first. No statuses returns every message.
+func (a *AppDB) GetEmailOutboxMessages(ctx context.Context, statuses []string, page, count int) (*structs.EmailOutboxListResponse, error) {
+	if page < 0 {
+		page = 0
+	}
+	if count <= 0 {
+		count = 20
+	}
+	if count > 200 {
+		count = 200
+	}
+	if statuses == nil {
+		statuses = []string{}
+	}
+	offset := page * count
+
+	var total int
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*)
+		FROM
+			email_outbox
+		WHERE
+			(cardinality($1::text[]) = 0 OR status = ANY($1::text[]));
+	`, statuses).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("error counting email outbox messages: %s", err)
+	}
+
+	rows, err := a.db.Query(ctx, `
+		SELECT
+			`+emailOutboxColumns+`
+		FROM
+			email_outbox
+		WHERE
+			(cardinality($1::text[]) = 0 OR status = ANY($1::text[]))
+		ORDER BY
+			created_at DESC,
+			id
+		LIMIT
+			$2
+		OFFSET
+			$3;
+	`, statuses, count, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting email outbox messages: %s", err)
+	}
+	defer rows.Close()
+
+	items := []*structs.EmailOutboxMessage{}
+	for rows.Next() {
+		msg, err := scanEmailOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning email outbox message: %s", err)
+		}
+		items = append(items, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading email outbox messages: %s", err)
+	}
+
+	return &structs.EmailOutboxListResponse{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Count: count,
+	}, nil
+}
+
+// ResendEmailOutboxMessage puts a failed or bounced message back in the queue
+// with a fresh attempt budget. The update re-checks the status, so a message
+// that another resend has already queued, or that is being sent, is left
+// alone.
+func (a *AppDB) ResendEmailOutboxMessage(ctx context.Context, id string) (*structs.EmailOutboxMessage, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("email outbox message id is required")
+	}
+
+	var status string
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			status
+		FROM
+			email_outbox
+		WHERE
+			id = $1;
+	`, id).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+	if status != structs.EmailOutboxStatusFailed && status != structs.EmailOutboxStatusBounced {
+		return nil, fmt.Errorf("only failed or bounced emails can be resent")
+	}
+
+	msg, err := scanEmailOutboxMessage(a.db.QueryRow(ctx, `
+		UPDATE
+			email_outbox
+		SET
+			status = 'pending',
+			attempts = 0,
+			run_at = unix_now(),
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			provider_message_id = '',
+			sent_at = NULL,
+			bounced_at = NULL,
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status IN ('failed', 'bounced')
+		RETURNING
+			`+emailOutboxColumns+`;
+	`, id))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("only failed or bounced emails can be resent")
+	}
+	return msg, err
+}
+
+// ClaimMailgunWebhookToken records the token of a signed Mailgun webhook and
+// returns false when it has been seen before, so a replayed delivery is not
+// acted on twice. Tokens older than retainSeconds are pruned first; webhooks
+// that old are refused as stale before their token is checked.
+func (a *AppDB) ClaimMailgunWebhookToken(ctx context.Context, token string, retainSeconds int64) (bool, error) {
+	if _, err := a.db.Exec(ctx, `
+		DELETE FROM
+			mailgun_webhook_tokens
+		WHERE
+			received_at < unix_now() - $1;
+	`, retainSeconds); err != nil {
+		return false, fmt.Errorf("error pruning mailgun webhook tokens: %s", err)
+	}
+
+	cmd, err := a.db.Exec(ctx, `
+		INSERT INTO mailgun_webhook_tokens
+			(token)
+		VALUES
+			($1)
+		ON CONFLICT (token) DO NOTHING;
+	`, token)
+	if err != nil {
+		return false, fmt.Errorf("error recording mailgun webhook token: %s", err)
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// ReleaseMailgunWebhookToken forgets a token whose webhook could not be
+// processed, so Mailgun's retry of it is accepted.
+func (a *AppDB) ReleaseMailgunWebhookToken(ctx context.Context, token string) error {
+	if _, err := a.db.Exec(ctx, `
+		DELETE FROM
+			mailgun_webhook_tokens
+		WHERE
+			token = $1;
+	`, token); err != nil {
+		return fmt.Errorf("error releasing mailgun webhook token: %s", err)
+	}
+	return nil
+}
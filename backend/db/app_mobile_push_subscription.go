@@ -135,6 +135,7 @@ func (a *AppDB) SyncMobilePushSubscriptions(
 	ctx context.Context,
 	owner string,
 	token string,
+	provider string,
 	addresses []string,
 	installationID string,
 	ponderHookIDsByAddress map[string]int,
@@ -379,7 +380,8 @@ func (a *AppDB) SyncMobilePushSubscriptions(
 				installation_id_hash,
 				preference_enabled,
 				device_registered,
-				active
+				active,
+				provider
 			) VALUES (
 				$1,
 				$2,
@@ -388,11 +390,13 @@ func (a *AppDB) SyncMobilePushSubscriptions(
 				$5,
 				TRUE,
 				$6,
-				$6
+				$6,
+				$7
 			)
 			ON CONFLICT (token, address) DO UPDATE
 			SET
 				owner = EXCLUDED.owner,
+				provider = EXCLUDED.provider,
 				installation_id_hash = COALESCE(NULLIF(EXCLUDED.installation_id_hash, ''), mobile_push_subscriptions.installation_id_hash),
 				preference_enabled = TRUE,
 				device_registered = EXCLUDED.device_registered,
@@ -401,7 +405,7 @@ func (a *AppDB) SyncMobilePushSubscriptions(
 				delete_date = NULL,
 				delete_reason = NULL,
 				updated_at = NOW();
-		`, owner, normalizedToken, address, ponderHookID, installationIDHash, nextDeviceRegistered); err != nil {
+		`, owner, normalizedToken, address, ponderHookID, installationIDHash, nextDeviceRegistered, provider); err != nil {
 			return nil, fmt.Errorf("error upserting mobile push subscription for %s: %w", address, err)
 		}
 	}
@@ -433,6 +437,7 @@ func scanMobilePushSubscriptions(
 			&subscription.DeviceRegistered,
 			&subscription.InstallationIDHash,
 			&ponderHookID,
+			&subscription.Provider,
 		); err != nil {
 			return nil, err
 		}
@@ -464,6 +469,7 @@ func scanMobilePushSubscriptionRecord(row pgx.Row) (*structs.MobilePushSubscript
 		&subscription.DeviceRegistered,
 		&subscription.InstallationIDHash,
 		&ponderHookID,
+		&subscription.Provider,
 	)
 	if err != nil {
 		return nil, err
@@ -494,7 +500,8 @@ func (a *AppDB) GetMobilePushSubscriptionsByOwnerToken(
 			preference_enabled,
 			device_registered,
 			installation_id_hash,
-			ponder_hook_id
+			ponder_hook_id,
+			provider
 		FROM
 			mobile_push_subscriptions
 		WHERE
@@ -539,7 +546,8 @@ func (a *AppDB) GetMobilePushSubscription(
 			preference_enabled,
 			device_registered,
 			installation_id_hash,
-			ponder_hook_id
+			ponder_hook_id,
+			provider
 		FROM
 			mobile_push_subscriptions
 		WHERE
@@ -565,7 +573,8 @@ func (a *AppDB) GetMobilePushSubscriptionsByUser(
 			preference_enabled,
 			device_registered,
 			installation_id_hash,
-			ponder_hook_id
+			ponder_hook_id,
+			provider
 		FROM
 			mobile_push_subscriptions
 		WHERE
@@ -594,7 +603,8 @@ func (a *AppDB) GetMobilePushSubscriptionsByAddress(
 			preference_enabled,
 			device_registered,
 			installation_id_hash,
-			ponder_hook_id
+			ponder_hook_id,
+			provider
 		FROM
 			mobile_push_subscriptions
 		WHERE
@@ -736,6 +746,7 @@ func (a *AppDB) AddMobilePushNotificationTicket(
 	owner string,
 	token string,
 	address string,
+	provider string,
 	ticketID string,
 ) error {
 	normalizedToken := normalizePushToken(token)
@@ -751,12 +762,14 @@ func (a *AppDB) AddMobilePushNotificationTicket(
 			owner,
 			token,
 			address,
+			provider,
 			status
 		) VALUES (
 			$1,
 			$2,
 			$3,
 			$4,
+			$5,
 			'pending'
 		)
 		ON CONFLICT (ticket_id) DO UPDATE
@@ -764,12 +777,13 @@ func (a *AppDB) AddMobilePushNotificationTicket(
 			owner = EXCLUDED.owner,
 			token = EXCLUDED.token,
 			address = EXCLUDED.address,
+			provider = EXCLUDED.provider,
 			status = 'pending',
 			receipt_status = NULL,
 			receipt_message = NULL,
 			receipt_error_code = NULL,
 			checked_at = NULL;
-	`, ticketID, owner, normalizedToken, normalizedAddress)
+	`, ticketID, owner, normalizedToken, normalizedAddress, provider)
 	if err != nil {
 		return fmt.Errorf("error storing mobile push notification ticket %s: %w", ticketID, err)
 	}
@@ -0,0 +1,336 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetNotificationSettings returns what the user has saved. Categories they
+// never changed are missing from Preferences and fall back to defaults.
+func (a *AppDB) GetNotificationSettings(ctx context.Context, userId string) (*structs.NotificationSettings, error) {
+	settings := &structs.NotificationSettings{
+		Preferences: []structs.NotificationPreference{},
+	}
+
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			locale,
+			timezone,
+			quiet_hours_start,
+			quiet_hours_end
+		FROM
+			notification_settings
+		WHERE
+			user_id = $1;
+	`, userId).Scan(&settings.Locale, &settings.Timezone, &settings.QuietHoursStart, &settings.QuietHoursEnd)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("error getting notification settings for user %s: %s", userId, err)
+	}
+
+	rows, err := a.db.Query(ctx, `
+		SELECT
+			category,
+			email,
+			push
+		FROM
+			notification_preferences
+		WHERE
+			user_id = $1
+		ORDER BY
+			category ASC;
+	`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying notification preferences for user %s: %s", userId, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var preference structs.NotificationPreference
+		if err := rows.Scan(&preference.Category, &preference.Email, &preference.Push); err != nil {
+			return nil, fmt.Errorf("error scanning notification preference for user %s: %s", userId, err)
+		}
+		settings.Preferences = append(settings.Preferences, preference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading notification preferences for user %s: %s", userId, err)
+	}
+
+	return settings, nil
+}
+
+func (a *AppDB) SaveNotificationSettings(ctx context.Context, userId string, settings *structs.NotificationSettings) error {
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error beginning notification settings tx: %s", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO notification_settings(
+			user_id,
+			locale,
+			timezone,
+			quiet_hours_start,
+			quiet_hours_end
+		) VALUES (
+			$1,
+			$2,
+			$3,
+			$4,
+			$5
+		)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			locale = EXCLUDED.locale,
+			timezone = EXCLUDED.timezone,
+			quiet_hours_start = EXCLUDED.quiet_hours_start,
+			quiet_hours_end = EXCLUDED.quiet_hours_end,
+			updated_at = unix_now();
+	`, userId, settings.Locale, settings.Timezone, settings.QuietHoursStart, settings.QuietHoursEnd)
+	if err != nil {
+		return fmt.Errorf("error saving notification settings for user %s: %s", userId, err)
+	}
+
+	for _, preference := range settings.Preferences {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO notification_preferences(
+				user_id,
+				category,
+				email,
+				push
+			) VALUES (
+				$1,
+				$2,
+				$3,
+				$4
+			)
+			ON CONFLICT (user_id, category) DO UPDATE
+			SET
+				email = EXCLUDED.email,
+				push = EXCLUDED.push,
+				updated_at = unix_now();
+		`, userId, preference.Category, preference.Email, preference.Push)
+		if err != nil {
+			return fmt.Errorf("error saving %s notification preference for user %s: %s", preference.Category, userId, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing notification settings for user %s: %s", userId, err)
+	}
+
+	return nil
+}
+
+// GetUserIdByNotificationEmail finds the user a notification email belongs
+// to through their contact or verified emails. Returns "" when no user has it.
+func (a *AppDB) GetUserIdByNotificationEmail(ctx context.Context, email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", nil
+	}
+
+	var userId string
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			id
+		FROM
+			users
+		WHERE
+			LOWER(TRIM(contact_email)) = $1
+		UNION ALL
+		SELECT
+			user_id
+		FROM
+			user_verified_emails
+		WHERE
+			email_normalized = $1
+		AND
+			verified_at IS NOT NULL
+		LIMIT 1;
+	`, email).Scan(&userId)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting user for notification email: %s", err)
+	}
+
+	return userId, nil
+}
+
+// HoldPushNotification keeps a push for delivery once the recipient's quiet
+// hours end.
+func (a *AppDB) HoldPushNotification(ctx context.Context, held *structs.HeldPushNotification) error {
+	data := []byte("{}")
+	if held.Data != nil {
+		encoded, err := json.Marshal(held.Data)
+		if err != nil {
+			return fmt.Errorf("error encoding held push data: %s", err)
+		}
+		data = encoded
+	}
+
+	_, err := a.db.Exec(ctx, `
+		INSERT INTO held_push_notifications
+			(id, subscription_id, category, title, body, data, deliver_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7);
+	`, uuid.NewString(), held.Subscription.Id, held.Category, held.Title, held.Body, data, held.DeliverAt)
+	if err != nil {
+		return fmt.Errorf("error holding push notification for subscription %d: %s", held.Subscription.Id, err)
+	}
+	return nil
+}
+
+// LeaseDueHeldPushNotifications leases up to limit held pushes whose quiet
+// hours have ended, along with pushes whose lease expired because their
+// worker died before sending them. A leased push stays in the table until
+// DeleteHeldPushNotification or RetryHeldPushNotification settles it.
+func (a *AppDB) LeaseDueHeldPushNotifications(ctx context.Context, leaseSeconds int64, limit int) ([]*structs.HeldPushNotification, error) {
+	rows, err := a.db.Query(ctx, `
+		WITH due AS (
+			UPDATE
+				held_push_notifications
+			SET
+				lease_expires_at = unix_now() + $1
+			WHERE
+				id IN (
+					SELECT
+						id
+					FROM
+						held_push_notifications
+					WHERE
+						deliver_at <= unix_now()
+					AND
+						COALESCE(lease_expires_at, 0) <= unix_now()
+					ORDER BY
+						deliver_at ASC
+					LIMIT $2
+					FOR UPDATE SKIP LOCKED
+				)
+			RETURNING
+				id,
+				subscription_id,
+				category,
+				title,
+				body,
+				data,
+				deliver_at,
+				attempts
+		)
+		SELECT
+			due.id,
+			due.category,
+			due.title,
+			due.body,
+			due.data,
+			due.deliver_at,
+			due.attempts,
+			s.id,
+			s.owner,
+			s.token,
+			s.address,
+			s.active,
+			s.preference_enabled,
+			s.device_registered,
+			s.installation_id_hash,
+			s.ponder_hook_id,
+			s.provider
+		FROM
+			due
+		JOIN
+			mobile_push_subscriptions s
+		ON
+			s.id = due.subscription_id
+		ORDER BY
+			due.deliver_at ASC;
+	`, leaseSeconds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error leasing due held push notifications: %s", err)
+	}
+	defer rows.Close()
+
+	results := []*structs.HeldPushNotification{}
+	for rows.Next() {
+		held := &structs.HeldPushNotification{Subscription: &structs.MobilePushSubscription{}}
+		var data []byte
+		var ponderHookId sql.NullInt64
+		if err := rows.Scan(
+			&held.Id,
+			&held.Category,
+			&held.Title,
+			&held.Body,
+			&data,
+			&held.DeliverAt,
+			&held.Attempts,
+			&held.Subscription.Id,
+			&held.Subscription.Owner,
+			&held.Subscription.Token,
+			&held.Subscription.Address,
+			&held.Subscription.Active,
+			&held.Subscription.PreferenceEnabled,
+			&held.Subscription.DeviceRegistered,
+			&held.Subscription.InstallationIDHash,
+			&ponderHookId,
+			&held.Subscription.Provider,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning held push notification: %s", err)
+		}
+		if err := json.Unmarshal(data, &held.Data); err != nil {
+			return nil, fmt.Errorf("error decoding held push notification %s: %s", held.Id, err)
+		}
+		if ponderHookId.Valid {
+			hookId := int(ponderHookId.Int64)
+			held.Subscription.PonderHookId = &hookId
+		}
+		held.Subscription.Type = structs.PushSubscription
+		held.Subscription.Data = []byte(held.Subscription.Token)
+		results = append(results, held)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading held push notifications: %s", err)
+	}
+	return results, nil
+}
+
+// DeleteHeldPushNotification removes a held push once it has been sent, or
+// once it is given up on.
+func (a *AppDB) DeleteHeldPushNotification(ctx context.Context, id string) error {
+	_, err := a.db.Exec(ctx, `
+		DELETE FROM
+			held_push_notifications
+		WHERE
+			id = $1;
+	`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting held push notification %s: %s", id, err)
+	}
+	return nil
+}
+
+// RetryHeldPushNotification records a failed send and releases the lease so
+// the push is sent again after delaySeconds.
+func (a *AppDB) RetryHeldPushNotification(ctx context.Context, id string, delaySeconds int64) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			held_push_notifications
+		SET
+			attempts = attempts + 1,
+			deliver_at = unix_now() + $2,
+			lease_expires_at = NULL
+		WHERE
+			id = $1;
+	`, id, delaySeconds)
+	if err != nil {
+		return fmt.Errorf("error scheduling retry for held push notification %s: %s", id, err)
+	}
+	return nil
+}
@@ -67,7 +67,7 @@ func (a *AppDB) GetPonderSubscriptions(ctx context.Context, address string) ([]*
 			&subscription.Data,
 		)
 		if err != nil {
-			a.logger.Logf("error scanning row into subscription struct: %s", err)
+			a.logger.Errorf(ctx, "error scanning row into subscription struct: %s", err)
 			continue
 		}
 
@@ -107,7 +107,7 @@ func (a *AppDB) GetPonderSubscriptionsByUser(ctx context.Context, userDid string
 			&subscription.Data,
 		)
 		if err != nil {
-			a.logger.Logf("error scanning row into subscription struct: %s", err)
+			a.logger.Errorf(ctx, "error scanning row into subscription struct: %s", err)
 			continue
 		}
 
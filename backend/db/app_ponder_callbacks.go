@@ -0,0 +1,202 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (a *AppDB) SetPonderHookSecret(ctx context.Context, hookID int, secret string) error {
+	_, err := a.db.Exec(ctx, `
+		INSERT INTO ponder_hook_secrets(
+			hook_id,
+			secret
+		) VALUES (
+			$1,
+			$2
+		)
+		ON CONFLICT (hook_id) DO UPDATE
+		SET
+			secret = EXCLUDED.secret,
+			created_at = unix_now();
+	`, hookID, secret)
+	if err != nil {
+		return fmt.Errorf("error saving secret for ponder hook %d: %s", hookID, err)
+	}
+
+	return nil
+}
+
+// GetPonderHookSecret returns an empty secret for hooks created before
+// callbacks were signed.
+func (a *AppDB) GetPonderHookSecret(ctx context.Context, hookID int) (string, error) {
+	var secret string
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			secret
+		FROM
+			ponder_hook_secrets
+		WHERE
+			hook_id = $1;
+	`, hookID).Scan(&secret)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting secret for ponder hook %d: %s", hookID, err)
+	}
+
+	return secret, nil
+}
+
+// GetUnkeyedPonderHookIDs lists hooks this app knows about that were
+// created before callbacks were signed.
+func (a *AppDB) GetUnkeyedPonderHookIDs(ctx context.Context) ([]int, error) {
+	rows, err := a.db.Query(ctx, `
+		SELECT
+			hook_id
+		FROM (
+			SELECT
+				id AS hook_id
+			FROM
+				ponder_subscriptions
+			UNION
+			SELECT
+				ponder_hook_id AS hook_id
+			FROM
+				mobile_push_subscriptions
+			WHERE
+				ponder_hook_id IS NOT NULL
+		) known
+		WHERE
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					ponder_hook_secrets s
+				WHERE
+					s.hook_id = known.hook_id
+			)
+		ORDER BY
+			hook_id ASC;
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying unkeyed ponder hook ids: %s", err)
+	}
+	defer rows.Close()
+
+	hookIDs := make([]int, 0)
+	for rows.Next() {
+		var hookID int
+		if err := rows.Scan(&hookID); err != nil {
+			return nil, fmt.Errorf("error scanning unkeyed ponder hook id: %s", err)
+		}
+		hookIDs = append(hookIDs, hookID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading unkeyed ponder hook ids: %s", err)
+	}
+
+	return hookIDs, nil
+}
+
+func (a *AppDB) DeletePonderHookSecret(ctx context.Context, hookID int) error {
+	_, err := a.db.Exec(ctx, `
+		DELETE FROM
+			ponder_hook_secrets
+		WHERE
+			hook_id = $1;
+	`, hookID)
+	if err != nil {
+		return fmt.Errorf("error deleting secret for ponder hook %d: %s", hookID, err)
+	}
+
+	return nil
+}
+
+// ClaimPonderCallbackNonce records a callback nonce and reports whether it
+// was unused. Nonces older than retainSeconds are pruned first; the caller's
+// timestamp window keeps those from being replayed.
+func (a *AppDB) ClaimPonderCallbackNonce(ctx context.Context, nonce string, retainSeconds int64) (bool, error) {
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error beginning ponder callback nonce tx: %s", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM
+			ponder_callback_nonces
+		WHERE
+			received_at < unix_now() - $1;
+	`, retainSeconds)
+	if err != nil {
+		return false, fmt.Errorf("error pruning ponder callback nonces: %s", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO ponder_callback_nonces(
+			nonce
+		) VALUES (
+			$1
+		)
+		ON CONFLICT (nonce) DO NOTHING;
+	`, nonce)
+	if err != nil {
+		return false, fmt.Errorf("error recording ponder callback nonce: %s", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("error committing ponder callback nonce: %s", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// ClaimPonderCallbackEvent marks a transfer log as handled by source and
+// reports whether this is the first time it was seen.
+func (a *AppDB) ClaimPonderCallbackEvent(ctx context.Context, source string, chainID int64, txHash string, logIndex int) (bool, error) {
+	tag, err := a.db.Exec(ctx, `
+		INSERT INTO ponder_callback_events(
+			source,
+			chain_id,
+			tx_hash,
+			log_index
+		) VALUES (
+			$1,
+			$2,
+			$3,
+			$4
+		)
+		ON CONFLICT (source, chain_id, tx_hash, log_index) DO NOTHING;
+	`, source, chainID, strings.ToLower(txHash), logIndex)
+	if err != nil {
+		return false, fmt.Errorf("error recording %s callback for tx %s log %d: %s", source, txHash, logIndex, err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReleasePonderCallbackEvent forgets a claimed transfer log so a callback
+// that failed before doing anything can be delivered again.
+func (a *AppDB) ReleasePonderCallbackEvent(ctx context.Context, source string, chainID int64, txHash string, logIndex int) error {
+	_, err := a.db.Exec(ctx, `
+		DELETE FROM
+			ponder_callback_events
+		WHERE
+			source = $1
+		AND
+			chain_id = $2
+		AND
+			tx_hash = $3
+		AND
+			log_index = $4;
+	`, source, chainID, strings.ToLower(txHash), logIndex)
+	if err != nil {
+		return fmt.Errorf("error releasing %s callback for tx %s log %d: %s", source, txHash, logIndex, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/SFLuv/app/backend/ratelimit"
+)
+
+// TakeRateLimitToken spends a token from the shared bucket for key, so limits
+// hold across every backend instance. The row lock serializes concurrent
+// requests for the same key.
+func (a *AppDB) TakeRateLimitToken(ctx context.Context, key string, limit ratelimit.Limit, now time.Time) (bool, time.Duration, error) {
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return false, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO rate_limit_buckets(
+			key,
+			tokens,
+			updated_at_ms
+		) VALUES (
+			$1,
+			$2,
+			$3
+		)
+		ON CONFLICT (key) DO NOTHING;
+	`, key, float64(limit.Burst), now.UnixMilli())
+	if err != nil {
+		return false, 0, err
+	}
+
+	var bucket ratelimit.Bucket
+	var updatedAtMs int64
+	err = tx.QueryRow(ctx, `
+		SELECT
+			tokens,
+			updated_at_ms
+		FROM
+			rate_limit_buckets
+		WHERE
+			key = $1
+		FOR UPDATE;
+	`, key).Scan(&bucket.Tokens, &updatedAtMs)
+	if err != nil {
+		return false, 0, err
+	}
+	bucket.UpdatedAt = time.UnixMilli(updatedAtMs)
+
+	next, allowed, retryAfter := ratelimit.Take(bucket, limit, now)
+	_, err = tx.Exec(ctx, `
+		UPDATE
+			rate_limit_buckets
+		SET
+			tokens = $2,
+			updated_at_ms = $3
+		WHERE
+			key = $1;
+	`, key, next.Tokens, next.UpdatedAt.UnixMilli())
+	if err != nil {
+		return false, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, 0, err
+	}
+	return allowed, retryAfter, nil
+}
+
+// DeleteIdleRateLimitBuckets removes buckets untouched since before, which
+// have long since refilled.
+func (a *AppDB) DeleteIdleRateLimitBuckets(ctx context.Context, before time.Time) (int64, error) {
+	cmd, err := a.db.Exec(ctx, `
+		DELETE FROM
+			rate_limit_buckets
+		WHERE
+			updated_at_ms < $1;
+	`, before.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return cmd.RowsAffected(), nil
+}
@@ -1279,15 +1279,16 @@ func getSupervisorByUser(ctx context.Context, querier interface {
 }
 
 type normalizedWorkflowTemplateData struct {
-	SeriesId             *string
-	Recurrence           string
-	StartAt              int64
-	SupervisorUserId     *string
-	SupervisorBounty     *uint64
-	SupervisorDataFields []structs.WorkflowSupervisorDataField
-	Roles                []structs.WorkflowRoleCreateInput
-	Steps                []structs.WorkflowStepCreateInput
-	TotalBounty          uint64
+	SeriesId                   *string
+	Recurrence                 string
+	StartAt                    int64
+	SupervisorUserId           *string
+	SupervisorBounty           *uint64
+	SupervisorApprovalRequired bool
+	SupervisorDataFields       []structs.WorkflowSupervisorDataField
+	Roles                      []structs.WorkflowRoleCreateInput
+	Steps                      []structs.WorkflowStepCreateInput
+	TotalBounty                uint64
 }
 
 func parseWorkflowTemplateStartTime(value *string) (int64, error) {
@@ -1463,6 +1464,9 @@ func normalizeWorkflowTemplateData(req *structs.WorkflowTemplateCreateRequest, v
 	if len(normalizedSupervisorDataFields) > 0 && supervisorUserId == nil {
 		return nil, fmt.Errorf("workflow supervisor user_id is required when supervisor data fields are provided")
 	}
+	if req.SupervisorApprovalRequired && supervisorUserId == nil {
+		return nil, fmt.Errorf("workflow supervisor user_id is required when supervisor approval is required")
+	}
 
 	normalizedSteps := make([]structs.WorkflowStepCreateInput, 0, len(req.Steps))
 	for _, stepInput := range req.Steps {
@@ -1503,6 +1507,9 @@ func normalizeWorkflowTemplateData(req *structs.WorkflowTemplateCreateRequest, v
 			if err != nil {
 				return nil, fmt.Errorf("workflow work item photo_aspect_ratio is invalid")
 			}
+			if err := validateWorkflowItemBountyWeight(itemInput.BountyWeight); err != nil {
+				return nil, err
+			}
 
 			normalizedDropdownOptions := []structs.WorkflowDropdownOptionCreateInput{}
 			if itemInput.RequiresDropdown {
@@ -1536,6 +1543,11 @@ func normalizeWorkflowTemplateData(req *structs.WorkflowTemplateCreateRequest, v
 					if len(notifyEmails) == 0 {
 						notifyEmailSubject = ""
 					}
+					if option.BountyWeight != nil {
+						if err := validateWorkflowItemBountyWeight(*option.BountyWeight); err != nil {
+							return nil, fmt.Errorf("dropdown option bounty_weight must be between 0 and %d", maxWorkflowItemBountyWeight)
+						}
+					}
 
 					normalizedDropdownOptions = append(normalizedDropdownOptions, structs.WorkflowDropdownOptionCreateInput{
 						Label:                   label,
@@ -1556,6 +1568,7 @@ func normalizeWorkflowTemplateData(req *structs.WorkflowTemplateCreateRequest, v
 						NotifyEmails:          notifyEmails,
 						NotifyEmailSubject:    notifyEmailSubject,
 						SendPicturesWithEmail: option.SendPicturesWithEmail,
+						BountyWeight:          option.BountyWeight,
 					})
 				}
 			}
@@ -1566,21 +1579,39 @@ func normalizeWorkflowTemplateData(req *structs.WorkflowTemplateCreateRequest, v
 				Optional:           itemInput.Optional,
 				RequiresPhoto:      itemInput.RequiresPhoto,
 				CameraCaptureOnly:  itemInput.RequiresPhoto && itemInput.CameraCaptureOnly,
+				KeepPhotoLocation:  itemInput.KeepPhotoLocation,
 				PhotoRequiredCount: photoRequiredCount,
 				PhotoAllowAnyCount: photoAllowAnyCount,
 				PhotoAspectRatio:   photoAspectRatio,
 				RequiresWritten:    itemInput.RequiresWritten,
 				RequiresDropdown:   itemInput.RequiresDropdown,
 				DropdownOptions:    normalizedDropdownOptions,
+				BountyWeight:       itemInput.BountyWeight,
 			})
 		}
 
+		geofence, err := normalizeWorkflowStepGeofence(stepInput.Geofence)
+		if err != nil {
+			return nil, err
+		}
+		completionWindow, err := normalizeWorkflowStepCompletionWindow(stepInput.CompletionWindow)
+		if err != nil {
+			return nil, err
+		}
+		// Out of bounds submissions wait for a supervisor, so bounded steps
+		// need one to review them.
+		if (geofence != nil || completionWindow != nil) && supervisorUserId == nil {
+			return nil, fmt.Errorf("workflow supervisor user_id is required when a step has a geofence or completion window")
+		}
+
 		normalizedSteps = append(normalizedSteps, structs.WorkflowStepCreateInput{
 			Title:                stepTitle,
 			Description:          strings.TrimSpace(stepInput.Description),
 			Bounty:               stepInput.Bounty,
 			RoleClientId:         roleClientId,
 			AllowStepNotPossible: stepInput.AllowStepNotPossible,
+			Geofence:             geofence,
+			CompletionWindow:     completionWindow,
 			WorkItems:            normalizedItems,
 		})
 	}
@@ -1594,32 +1625,34 @@ func normalizeWorkflowTemplateData(req *structs.WorkflowTemplateCreateRequest, v
 	}
 
 	return &normalizedWorkflowTemplateData{
-		SeriesId:             seriesId,
-		Recurrence:           recurrence,
-		StartAt:              startAt,
-		SupervisorUserId:     supervisorUserId,
-		SupervisorBounty:     supervisorBounty,
-		SupervisorDataFields: normalizedSupervisorDataFields,
-		Roles:                normalizedRoles,
-		Steps:                normalizedSteps,
-		TotalBounty:          totalBounty,
+		SeriesId:                   seriesId,
+		Recurrence:                 recurrence,
+		StartAt:                    startAt,
+		SupervisorUserId:           supervisorUserId,
+		SupervisorBounty:           supervisorBounty,
+		SupervisorApprovalRequired: req.SupervisorApprovalRequired,
+		SupervisorDataFields:       normalizedSupervisorDataFields,
+		Roles:                      normalizedRoles,
+		Steps:                      normalizedSteps,
+		TotalBounty:                totalBounty,
 	}, nil
 }
 
 type normalizedWorkflowDefinitionData struct {
-	Title                string
-	Description          string
-	Recurrence           string
-	StartAt              *int64
-	RecurrenceEndAt      *int64
-	SupervisorRequired   bool
-	SupervisorUserId     *string
-	SupervisorBounty     uint64
-	SupervisorDataFields []structs.WorkflowSupervisorDataField
-	Roles                []structs.WorkflowRoleCreateInput
-	Steps                []structs.WorkflowStepCreateInput
-	TotalBounty          uint64
-	WeeklyRequirement    uint64
+	Title                      string
+	Description                string
+	Recurrence                 string
+	StartAt                    *int64
+	RecurrenceEndAt            *int64
+	SupervisorRequired         bool
+	SupervisorUserId           *string
+	SupervisorBounty           uint64
+	SupervisorApprovalRequired bool
+	SupervisorDataFields       []structs.WorkflowSupervisorDataField
+	Roles                      []structs.WorkflowRoleCreateInput
+	Steps                      []structs.WorkflowStepCreateInput
+	TotalBounty                uint64
+	WeeklyRequirement          uint64
 }
 
 func sortedPositiveWorkflowStepBounties(steps []structs.WorkflowStepCreateInput) []uint64 {
@@ -1703,6 +1736,7 @@ func normalizeWorkflowDefinitionData(
 		}
 		supervisorBounty := supervisor.Bounty
 		templateReq.SupervisorBounty = &supervisorBounty
+		templateReq.SupervisorApprovalRequired = supervisor.ApprovalRequired
 	}
 
 	normalizedTemplate, err := normalizeWorkflowTemplateData(templateReq, validCredentials)
@@ -1731,19 +1765,20 @@ func normalizeWorkflowDefinitionData(
 	}
 
 	return &normalizedWorkflowDefinitionData{
-		Title:                title,
-		Description:          description,
-		Recurrence:           normalizedTemplate.Recurrence,
-		StartAt:              normalizedStartAt,
-		RecurrenceEndAt:      normalizedEndAt,
-		SupervisorRequired:   supervisorRequired,
-		SupervisorUserId:     normalizedTemplate.SupervisorUserId,
-		SupervisorBounty:     supervisorBounty,
-		SupervisorDataFields: normalizedTemplate.SupervisorDataFields,
-		Roles:                normalizedTemplate.Roles,
-		Steps:                normalizedTemplate.Steps,
-		TotalBounty:          normalizedTemplate.TotalBounty,
-		WeeklyRequirement:    weeklyBountyRequirement(normalizedTemplate.TotalBounty, normalizedTemplate.Recurrence),
+		Title:                      title,
+		Description:                description,
+		Recurrence:                 normalizedTemplate.Recurrence,
+		StartAt:                    normalizedStartAt,
+		RecurrenceEndAt:            normalizedEndAt,
+		SupervisorRequired:         supervisorRequired,
+		SupervisorUserId:           normalizedTemplate.SupervisorUserId,
+		SupervisorBounty:           supervisorBounty,
+		SupervisorApprovalRequired: normalizedTemplate.SupervisorApprovalRequired,
+		SupervisorDataFields:       normalizedTemplate.SupervisorDataFields,
+		Roles:                      normalizedTemplate.Roles,
+		Steps:                      normalizedTemplate.Steps,
+		TotalBounty:                normalizedTemplate.TotalBounty,
+		WeeklyRequirement:          weeklyBountyRequirement(normalizedTemplate.TotalBounty, normalizedTemplate.Recurrence),
 	}, nil
 }
 
@@ -1803,12 +1838,14 @@ func upsertWorkflowStateVersionTx(
 			ws.roles_json = $10::jsonb
 		AND
 			ws.steps_json = $11::jsonb
+		AND
+			ws.supervisor_approval_required = $12
 		ORDER BY
 			ws.created_at ASC,
 			ws.id ASC
 		LIMIT 1
 		FOR UPDATE;
-	`, seriesId, def.Title, def.Description, def.Recurrence, def.StartAt, def.RecurrenceEndAt, def.SupervisorUserId, def.SupervisorBounty, string(supervisorDataJSON), string(rolesJSON), string(stepsJSON)).Scan(&existingStateID)
+	`, seriesId, def.Title, def.Description, def.Recurrence, def.StartAt, def.RecurrenceEndAt, def.SupervisorUserId, def.SupervisorBounty, string(supervisorDataJSON), string(rolesJSON), string(stepsJSON), def.SupervisorApprovalRequired).Scan(&existingStateID)
 	if err == nil && strings.TrimSpace(existingStateID) != "" {
 		return existingStateID, nil
 	}
@@ -1833,11 +1870,12 @@ func upsertWorkflowStateVersionTx(
 			roles_json,
 			steps_json,
 			source_workflow_id,
-			proposed_by_user_id
+			proposed_by_user_id,
+			supervisor_approval_required
 		)
 		VALUES
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb, $12::jsonb, $13::jsonb, $14, $15);
-	`, stateID, seriesId, proposerId, def.Title, def.Description, def.Recurrence, def.StartAt, def.RecurrenceEndAt, def.SupervisorUserId, def.SupervisorBounty, string(supervisorDataJSON), string(rolesJSON), string(stepsJSON), sourceWorkflowID, proposedByUserID)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb, $12::jsonb, $13::jsonb, $14, $15, $16);
+	`, stateID, seriesId, proposerId, def.Title, def.Description, def.Recurrence, def.StartAt, def.RecurrenceEndAt, def.SupervisorUserId, def.SupervisorBounty, string(supervisorDataJSON), string(rolesJSON), string(stepsJSON), sourceWorkflowID, proposedByUserID, def.SupervisorApprovalRequired)
 	if err != nil {
 		return "", fmt.Errorf("error inserting workflow state: %s", err)
 	}
@@ -1960,11 +1998,12 @@ func (a *AppDB) CreateWorkflowTemplate(
 					supervisor_bounty,
 					supervisor_data_json,
 					roles_json,
-					steps_json
+					steps_json,
+					supervisor_approval_required
 				)
 			VALUES
-				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::jsonb, $13::jsonb, $14::jsonb);
-	`, templateId, templateTitle, templateDescription, ownerUserId, creatorUserId, isDefault, normalized.Recurrence, normalized.StartAt, normalized.SeriesId, normalized.SupervisorUserId, normalized.SupervisorBounty, string(supervisorDataJSON), string(rolesJSON), string(stepsJSON))
+				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::jsonb, $13::jsonb, $14::jsonb, $15);
+	`, templateId, templateTitle, templateDescription, ownerUserId, creatorUserId, isDefault, normalized.Recurrence, normalized.StartAt, normalized.SeriesId, normalized.SupervisorUserId, normalized.SupervisorBounty, string(supervisorDataJSON), string(rolesJSON), string(stepsJSON), normalized.SupervisorApprovalRequired)
 	if err != nil {
 		return nil, fmt.Errorf("error creating workflow template: %s", err)
 	}
@@ -1986,6 +2025,7 @@ func (a *AppDB) GetWorkflowTemplateByID(ctx context.Context, templateId string)
 				series_id,
 				supervisor_user_id,
 				supervisor_bounty,
+				supervisor_approval_required,
 				COALESCE(supervisor_data_json, '[]'::jsonb),
 				roles_json,
 				steps_json,
@@ -2015,6 +2055,7 @@ func (a *AppDB) GetWorkflowTemplateByID(ctx context.Context, templateId string)
 		&template.SeriesId,
 		&supervisorUserId,
 		&supervisorBounty,
+		&template.SupervisorApprovalRequired,
 		&supervisorDataBytes,
 		&rolesBytes,
 		&stepsBytes,
@@ -2065,6 +2106,7 @@ func (a *AppDB) GetWorkflowTemplatesForProposer(ctx context.Context, proposerId
 				series_id,
 				supervisor_user_id,
 				supervisor_bounty,
+				supervisor_approval_required,
 				COALESCE(supervisor_data_json, '[]'::jsonb),
 				roles_json,
 				steps_json,
@@ -2106,6 +2148,7 @@ func (a *AppDB) GetWorkflowTemplatesForProposer(ctx context.Context, proposerId
 			&template.SeriesId,
 			&supervisorUserId,
 			&supervisorBounty,
+			&template.SupervisorApprovalRequired,
 			&supervisorDataBytes,
 			&rolesBytes,
 			&stepsBytes,
@@ -2321,11 +2364,12 @@ func (a *AppDB) CreateWorkflow(
 					budget_one_time_deducted,
 					manager_required,
 					manager_improver_id,
-					manager_bounty
+					manager_bounty,
+					supervisor_approval_required
 				)
 			VALUES
-				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
-		`, workflowId, seriesId, stateID, proposerId, startAt.UTC().Unix(), status, isStartBlocked, blockedById, definition.TotalBounty, definition.WeeklyRequirement, 0, 0, definition.SupervisorRequired, definition.SupervisorUserId, definition.SupervisorBounty)
+				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);
+		`, workflowId, seriesId, stateID, proposerId, startAt.UTC().Unix(), status, isStartBlocked, blockedById, definition.TotalBounty, definition.WeeklyRequirement, 0, 0, definition.SupervisorRequired, definition.SupervisorUserId, definition.SupervisorBounty, definition.SupervisorApprovalRequired)
 	if err != nil {
 		return nil, fmt.Errorf("error inserting workflow: %s", err)
 	}
@@ -2385,12 +2429,14 @@ func (a *AppDB) CreateWorkflow(
 		}
 		roleId = &mappedRoleId
 
+		geofenceLatitude, geofenceLongitude, geofenceRadius := workflowStepGeofenceColumns(stepInput.Geofence)
+		completionOpensAfter, completionClosesAfter := workflowStepCompletionWindowColumns(stepInput.CompletionWindow)
 		_, err = tx.Exec(ctx, `
 			INSERT INTO workflow_steps
-				(id, series_id, workflow_id, step_order, title, description, bounty, allow_step_not_possible, role_id, status)
+				(id, series_id, workflow_id, step_order, title, description, bounty, allow_step_not_possible, role_id, status, geofence_latitude, geofence_longitude, geofence_radius_meters, completion_opens_after_minutes, completion_closes_after_minutes)
 			VALUES
-				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
-		`, stepId, seriesId, workflowId, stepIndex+1, stepTitle, strings.TrimSpace(stepInput.Description), stepInput.Bounty, stepInput.AllowStepNotPossible, roleId, stepStatus)
+				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
+		`, stepId, seriesId, workflowId, stepIndex+1, stepTitle, strings.TrimSpace(stepInput.Description), stepInput.Bounty, stepInput.AllowStepNotPossible, roleId, stepStatus, geofenceLatitude, geofenceLongitude, geofenceRadius, completionOpensAfter, completionClosesAfter)
 		if err != nil {
 			return nil, fmt.Errorf("error inserting workflow step: %s", err)
 		}
@@ -2429,6 +2475,7 @@ func (a *AppDB) CreateWorkflow(
 					NotifyEmails:            option.NotifyEmails,
 					NotifyEmailSubject:      strings.TrimSpace(option.NotifyEmailSubject),
 					SendPicturesWithEmail:   option.SendPicturesWithEmail,
+					BountyWeight:            option.BountyWeight,
 				})
 				dropdownRequiresWritten[value] = option.RequiresWrittenResponse
 			}
@@ -2472,11 +2519,13 @@ func (a *AppDB) CreateWorkflow(
 							dropdown_options,
 							dropdown_requires_written_response,
 							notify_emails,
-						notify_on_dropdown_values
+						notify_on_dropdown_values,
+						keep_photo_location,
+						bounty_weight
 					)
 						VALUES
-							($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14::jsonb, $15::jsonb, $16::jsonb, $17::jsonb);
-				`, itemId, stepId, itemIndex+1, itemTitle, strings.TrimSpace(itemInput.Description), itemInput.Optional, itemInput.RequiresPhoto, itemInput.RequiresPhoto && itemInput.CameraCaptureOnly, photoRequiredCount, photoAllowAnyCount, photoAspectRatio, itemInput.RequiresWritten, itemInput.RequiresDropdown, string(dropdownOptionsJSON), string(dropdownRequiresJSON), string(legacyNotifyEmailsJSON), string(legacyNotifyValuesJSON))
+							($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14::jsonb, $15::jsonb, $16::jsonb, $17::jsonb, $18, $19);
+				`, itemId, stepId, itemIndex+1, itemTitle, strings.TrimSpace(itemInput.Description), itemInput.Optional, itemInput.RequiresPhoto, itemInput.RequiresPhoto && itemInput.CameraCaptureOnly, photoRequiredCount, photoAllowAnyCount, photoAspectRatio, itemInput.RequiresWritten, itemInput.RequiresDropdown, string(dropdownOptionsJSON), string(dropdownRequiresJSON), string(legacyNotifyEmailsJSON), string(legacyNotifyValuesJSON), itemInput.KeepPhotoLocation, itemInput.BountyWeight)
 			if err != nil {
 				return nil, fmt.Errorf("error inserting workflow work item: %s", err)
 			}
@@ -2837,6 +2886,7 @@ func (a *AppDB) GetWorkflowByID(ctx context.Context, workflowId string) (*struct
 			w.manager_payout_in_progress,
 			w.manager_retry_requested_at,
 			w.manager_retry_requested_by,
+			w.supervisor_approval_required,
 			w.created_at,
 			w.updated_at
 		FROM
@@ -2888,6 +2938,7 @@ func (a *AppDB) GetWorkflowByID(ctx context.Context, workflowId string) (*struct
 		&workflow.ManagerPayoutInProgress,
 		&workflow.ManagerRetryRequestedAt,
 		&workflow.ManagerRetryRequestedBy,
+		&workflow.SupervisorApprovalRequired,
 		&workflow.CreatedAt,
 		&workflow.UpdatedAt,
 	)
@@ -3023,6 +3074,7 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			ws.title,
 			ws.description,
 			ws.bounty,
+			ws.full_bounty,
 			ws.allow_step_not_possible,
 			ws.role_id,
 			ws.assigned_improver_id,
@@ -3038,7 +3090,16 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			ws.payout_last_try_at,
 			ws.payout_in_progress,
 			ws.retry_requested_at,
-			ws.retry_requested_by
+			ws.retry_requested_by,
+			ws.geofence_latitude,
+			ws.geofence_longitude,
+			ws.geofence_radius_meters,
+			ws.completion_opens_after_minutes,
+			ws.completion_closes_after_minutes,
+			ws.review_status,
+			ws.reviewed_at,
+			ws.reviewed_by,
+			ws.review_comment
 		FROM
 			workflow_steps ws
 		LEFT JOIN
@@ -3059,6 +3120,8 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 	stepIndex := map[string]int{}
 	for rows.Next() {
 		step := structs.WorkflowStep{}
+		var geofenceLatitude, geofenceLongitude, geofenceRadius *float64
+		var completionOpensAfter, completionClosesAfter *int
 		if err := rows.Scan(
 			&step.Id,
 			&step.WorkflowId,
@@ -3066,6 +3129,7 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			&step.Title,
 			&step.Description,
 			&step.Bounty,
+			&step.FullBounty,
 			&step.AllowStepNotPossible,
 			&step.RoleId,
 			&step.AssignedImproverId,
@@ -3078,9 +3142,20 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			&step.PayoutInProgress,
 			&step.RetryRequestedAt,
 			&step.RetryRequestedBy,
+			&geofenceLatitude,
+			&geofenceLongitude,
+			&geofenceRadius,
+			&completionOpensAfter,
+			&completionClosesAfter,
+			&step.ReviewStatus,
+			&step.ReviewedAt,
+			&step.ReviewedBy,
+			&step.ReviewComment,
 		); err != nil {
 			return nil, fmt.Errorf("error scanning workflow step: %s", err)
 		}
+		step.Geofence = workflowStepGeofenceFromColumns(geofenceLatitude, geofenceLongitude, geofenceRadius)
+		step.CompletionWindow = workflowStepCompletionWindowFromColumns(completionOpensAfter, completionClosesAfter)
 		step.WorkItems = []structs.WorkflowWorkItem{}
 		step.Submission = nil
 		stepIndex[step.Id] = len(steps)
@@ -3105,7 +3180,9 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 				dropdown_options,
 				dropdown_requires_written_response,
 				notify_emails,
-			notify_on_dropdown_values
+			notify_on_dropdown_values,
+			keep_photo_location,
+			bounty_weight
 		FROM
 			workflow_step_items
 		WHERE
@@ -3144,6 +3221,8 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			&dropdownRequiresBytes,
 			&notifyEmailsBytes,
 			&notifyValuesBytes,
+			&item.KeepPhotoLocation,
+			&item.BountyWeight,
 		); err != nil {
 			return nil, fmt.Errorf("error scanning workflow work item: %s", err)
 		}
@@ -3237,6 +3316,7 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			step_not_possible,
 			step_not_possible_details,
 			item_responses,
+			review_flags,
 			submitted_at,
 			updated_at
 		FROM
@@ -3252,6 +3332,7 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 	for submissionRows.Next() {
 		submission := structs.WorkflowStepSubmission{}
 		var itemResponsesBytes []byte
+		var reviewFlagsBytes []byte
 		if err := submissionRows.Scan(
 			&submission.Id,
 			&submission.WorkflowId,
@@ -3260,6 +3341,7 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			&submission.StepNotPossible,
 			&submission.StepNotPossibleDetails,
 			&itemResponsesBytes,
+			&reviewFlagsBytes,
 			&submission.SubmittedAt,
 			&submission.UpdatedAt,
 		); err != nil {
@@ -3272,6 +3354,11 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 				return nil, fmt.Errorf("error unmarshalling workflow step submission item responses: %s", err)
 			}
 		}
+		if len(reviewFlagsBytes) > 0 {
+			if err := json.Unmarshal(reviewFlagsBytes, &submission.ReviewFlags); err != nil {
+				return nil, fmt.Errorf("error unmarshalling workflow step submission review flags: %s", err)
+			}
+		}
 
 		if idx, ok := stepIndex[submission.StepId]; ok {
 			steps[idx].Submission = &submission
@@ -3288,6 +3375,9 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			file_name,
 			content_type,
 			size_bytes,
+			location_stripped,
+			reused_photo_id,
+			reuse_distance,
 			created_at
 		FROM
 			workflow_submission_photos
@@ -3313,6 +3403,9 @@ func (a *AppDB) getWorkflowSteps(ctx context.Context, workflowId string) ([]stru
 			&photo.FileName,
 			&photo.ContentType,
 			&photo.SizeBytes,
+			&photo.LocationStripped,
+			&photo.ReusedPhotoId,
+			&photo.ReuseDistance,
 			&photo.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("error scanning workflow submission photo: %s", err)
@@ -3417,6 +3510,9 @@ func (a *AppDB) DeleteWorkflowByProposer(ctx context.Context, workflowId string,
 	return nil
 }
 
+// weeklyBountyRequirement scales a workflow's total bounty to a week. Work
+// item weights only ever reduce what a step pays, so the step bounties in the
+// total are the maximum liability.
 func weeklyBountyRequirement(total uint64, recurrence string) uint64 {
 	switch recurrence {
 	case "daily":
@@ -3876,6 +3972,7 @@ func ensureRecurringWorkflowSuccessorTx(
 		RecurrenceEndAt  *int64
 		SupervisorUserID *string
 		SupervisorBounty uint64
+		ApprovalRequired bool
 		RolesJSON        []byte
 		StepsJSON        []byte
 	}
@@ -3894,6 +3991,7 @@ func ensureRecurringWorkflowSuccessorTx(
 			COALESCE(st.recurrence_end_at, s.recurrence_end_at),
 			st.supervisor_user_id,
 			COALESCE(st.supervisor_bounty, 0),
+			COALESCE(st.supervisor_approval_required, false),
 			COALESCE(st.roles_json, '[]'::jsonb),
 			COALESCE(st.steps_json, '[]'::jsonb)
 		FROM
@@ -3921,6 +4019,7 @@ func ensureRecurringWorkflowSuccessorTx(
 		&seed.RecurrenceEndAt,
 		&seed.SupervisorUserID,
 		&seed.SupervisorBounty,
+		&seed.ApprovalRequired,
 		&seed.RolesJSON,
 		&seed.StepsJSON,
 	)
@@ -4047,11 +4146,12 @@ func ensureRecurringWorkflowSuccessorTx(
 			vote_finalized_at,
 			vote_decision,
 			approved_at,
-			approved_by_user_id
+			approved_by_user_id,
+			supervisor_approval_required
 		)
 		VALUES
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 0, 0, $11, $12, $13, $14, $14, $14, 'approve', $14, NULL);
-	`, successorId, seed.SeriesId, seed.WorkflowStateID, seed.ProposerId, nextStartAt, successorStatus, successorIsBlocked, blockedByWorkflowId, totalBounty, weeklyRequirement, seed.SupervisorUserID != nil, seed.SupervisorUserID, seed.SupervisorBounty, nowUnix)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 0, 0, $11, $12, $13, $14, $14, $14, 'approve', $14, NULL, $15);
+	`, successorId, seed.SeriesId, seed.WorkflowStateID, seed.ProposerId, nextStartAt, successorStatus, successorIsBlocked, blockedByWorkflowId, totalBounty, weeklyRequirement, seed.SupervisorUserID != nil, seed.SupervisorUserID, seed.SupervisorBounty, nowUnix, seed.ApprovalRequired && seed.SupervisorUserID != nil)
 	if err != nil {
 		return "", fmt.Errorf("error inserting recurring workflow successor: %s", err)
 	}
@@ -4119,6 +4219,8 @@ func ensureRecurringWorkflowSuccessorTx(
 		}
 
 		newStepID := uuid.NewString()
+		geofenceLatitude, geofenceLongitude, geofenceRadius := workflowStepGeofenceColumns(step.Geofence)
+		completionOpensAfter, completionClosesAfter := workflowStepCompletionWindowColumns(step.CompletionWindow)
 		_, err = tx.Exec(ctx, `
 			INSERT INTO workflow_steps(
 				id,
@@ -4130,11 +4232,16 @@ func ensureRecurringWorkflowSuccessorTx(
 				bounty,
 				allow_step_not_possible,
 				role_id,
-				status
+				status,
+				geofence_latitude,
+				geofence_longitude,
+				geofence_radius_meters,
+				completion_opens_after_minutes,
+				completion_closes_after_minutes
 			)
 			VALUES
-				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
-		`, newStepID, seed.SeriesId, successorId, stepIndex+1, stepTitle, strings.TrimSpace(step.Description), step.Bounty, step.AllowStepNotPossible, roleID, stepStatus)
+				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
+		`, newStepID, seed.SeriesId, successorId, stepIndex+1, stepTitle, strings.TrimSpace(step.Description), step.Bounty, step.AllowStepNotPossible, roleID, stepStatus, geofenceLatitude, geofenceLongitude, geofenceRadius, completionOpensAfter, completionClosesAfter)
 		if err != nil {
 			return "", fmt.Errorf("error cloning recurring step: %s", err)
 		}
@@ -4176,6 +4283,7 @@ func ensureRecurringWorkflowSuccessorTx(
 					NotifyEmails:            option.NotifyEmails,
 					NotifyEmailSubject:      strings.TrimSpace(option.NotifyEmailSubject),
 					SendPicturesWithEmail:   option.SendPicturesWithEmail,
+					BountyWeight:            option.BountyWeight,
 				})
 				dropdownRequiresWritten[value] = option.RequiresWrittenResponse
 			}
@@ -4214,11 +4322,13 @@ func ensureRecurringWorkflowSuccessorTx(
 					dropdown_options,
 					dropdown_requires_written_response,
 					notify_emails,
-					notify_on_dropdown_values
+					notify_on_dropdown_values,
+					keep_photo_location,
+					bounty_weight
 				)
 				VALUES
-					($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14::jsonb, $15::jsonb, $16::jsonb, $17::jsonb);
-			`, uuid.NewString(), newStepID, itemIndex+1, itemTitle, strings.TrimSpace(item.Description), item.Optional, item.RequiresPhoto, item.RequiresPhoto && item.CameraCaptureOnly, photoRequiredCount, photoAllowAnyCount, photoAspectRatio, item.RequiresWritten, item.RequiresDropdown, string(dropdownOptionsJSON), string(dropdownRequiresWrittenJSON), string(notifyEmailsJSON), string(notifyValuesJSON))
+					($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14::jsonb, $15::jsonb, $16::jsonb, $17::jsonb, $18, $19);
+			`, uuid.NewString(), newStepID, itemIndex+1, itemTitle, strings.TrimSpace(item.Description), item.Optional, item.RequiresPhoto, item.RequiresPhoto && item.CameraCaptureOnly, photoRequiredCount, photoAllowAnyCount, photoAspectRatio, item.RequiresWritten, item.RequiresDropdown, string(dropdownOptionsJSON), string(dropdownRequiresWrittenJSON), string(notifyEmailsJSON), string(notifyValuesJSON), item.KeepPhotoLocation, item.BountyWeight)
 			if err != nil {
 				return "", fmt.Errorf("error cloning recurring step item: %s", err)
 			}
@@ -4588,6 +4698,8 @@ func (a *AppDB) AllocatedWorkflowBalance(ctx context.Context) (uint64, error) {
 					w.status IN ('approved', 'blocked', 'in_progress', 'completed')
 				AND
 					ws.status != 'paid_out'
+				AND
+					ws.review_status <> 'rejected'
 			), 0)
 			+
 			COALESCE((
@@ -4630,6 +4742,8 @@ func (a *AppDB) AllocatedWorkflowBalanceByProposer(ctx context.Context, proposer
 					w.status IN ('pending', 'approved', 'blocked', 'in_progress', 'completed')
 				AND
 					ws.status != 'paid_out'
+				AND
+					ws.review_status <> 'rejected'
 			), 0)
 			+
 			COALESCE((
@@ -5680,7 +5794,17 @@ func (a *AppDB) GetSupervisorWorkflows(
 								workflow_steps ws
 							WHERE
 								ws.workflow_id = w.id
-						) AS completed_at
+						) AS completed_at,
+						(
+							SELECT
+								COUNT(*)
+							FROM
+								workflow_steps ws
+							WHERE
+								ws.workflow_id = w.id
+							AND
+								ws.review_status IN ('pending_review', 'flagged')
+						) AS steps_awaiting_review
 					FROM
 						workflows w
 					LEFT JOIN
@@ -5770,7 +5894,8 @@ func (a *AppDB) GetSupervisorWorkflows(
 			bw.manager_bounty,
 			bw.supervisor_user_id,
 			bw.supervisor_title,
-			bw.supervisor_organization
+			bw.supervisor_organization,
+			bw.steps_awaiting_review
 		FROM
 			bw
 		WHERE
@@ -5807,6 +5932,7 @@ func (a *AppDB) GetSupervisorWorkflows(
 			&item.SupervisorUserId,
 			&item.SupervisorTitle,
 			&item.SupervisorOrganization,
+			&item.StepsAwaitingReview,
 		); err != nil {
 			return nil, fmt.Errorf("error scanning supervisor workflow list item: %s", err)
 		}
@@ -5999,6 +6125,23 @@ func (a *AppDB) IsImproverAssignedOrManagerForWorkflow(ctx context.Context, work
 }
 
 func (a *AppDB) GetWorkflowSubmissionPhotoBlobByID(ctx context.Context, photoID string) (*structs.WorkflowSubmissionPhotoBlob, error) {
+	photo, err := a.getWorkflowSubmissionPhoto(ctx, photoID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.loadWorkflowPhotoData(ctx, photo); err != nil {
+		return nil, err
+	}
+	return photo, nil
+}
+
+// GetWorkflowSubmissionPhotoByID loads a photo's metadata and storage
+// location without its bytes.
+func (a *AppDB) GetWorkflowSubmissionPhotoByID(ctx context.Context, photoID string) (*structs.WorkflowSubmissionPhotoBlob, error) {
+	return a.getWorkflowSubmissionPhoto(ctx, photoID, false)
+}
+
+func (a *AppDB) getWorkflowSubmissionPhoto(ctx context.Context, photoID string, withData bool) (*structs.WorkflowSubmissionPhotoBlob, error) {
 	row := a.db.QueryRow(ctx, `
 		SELECT
 			id,
@@ -6010,12 +6153,14 @@ func (a *AppDB) GetWorkflowSubmissionPhotoBlobByID(ctx context.Context, photoID
 			content_type,
 			size_bytes,
 			created_at,
-			photo_data
+			storage_backend,
+			storage_key,
+			CASE WHEN $2 THEN photo_data END
 		FROM
 			workflow_submission_photos
 		WHERE
 			id = $1;
-	`, photoID)
+	`, photoID, withData)
 
 	photo := &structs.WorkflowSubmissionPhotoBlob{}
 	if err := row.Scan(
@@ -6028,6 +6173,8 @@ func (a *AppDB) GetWorkflowSubmissionPhotoBlobByID(ctx context.Context, photoID
 		&photo.ContentType,
 		&photo.SizeBytes,
 		&photo.CreatedAt,
+		&photo.StorageBackend,
+		&photo.StorageKey,
 		&photo.PhotoData,
 	); err != nil {
 		return nil, err
@@ -6035,7 +6182,9 @@ func (a *AppDB) GetWorkflowSubmissionPhotoBlobByID(ctx context.Context, photoID
 	return photo, nil
 }
 
-func (a *AppDB) GetWorkflowSubmissionPhotoExports(ctx context.Context, workflowID string) ([]*structs.WorkflowSubmissionPhotoExport, error) {
+// GetWorkflowSubmissionPhotoExports loads a workflow's photos with their
+// bytes. A non-empty variant swaps in that rendition where one exists.
+func (a *AppDB) GetWorkflowSubmissionPhotoExports(ctx context.Context, workflowID string, variant string) ([]*structs.WorkflowSubmissionPhotoExport, error) {
 	rows, err := a.db.Query(ctx, `
 			SELECT
 				p.id,
@@ -6044,10 +6193,12 @@ func (a *AppDB) GetWorkflowSubmissionPhotoExports(ctx context.Context, workflowI
 			p.item_id,
 			p.submission_id,
 			p.file_name,
-			p.content_type,
-			p.size_bytes,
+			COALESCE(r.content_type, p.content_type),
+			COALESCE(r.size_bytes, p.size_bytes),
 			p.created_at,
-				p.photo_data,
+				CASE WHEN r.id IS NULL THEN p.photo_data ELSE r.photo_data END,
+				COALESCE(r.storage_backend, p.storage_backend),
+				COALESCE(r.storage_key, p.storage_key),
 				COALESCE(ws.step_order, 0),
 				COALESCE(wsi.item_order, 0),
 				COALESCE(wsi.title, ''),
@@ -6055,6 +6206,12 @@ func (a *AppDB) GetWorkflowSubmissionPhotoExports(ctx context.Context, workflowI
 				w.start_at
 			FROM
 				workflow_submission_photos p
+			LEFT JOIN
+				workflow_photo_renditions r
+			ON
+				r.photo_id = p.id
+			AND
+				r.variant = $2
 			LEFT JOIN
 				workflows w
 			ON
@@ -6077,7 +6234,7 @@ func (a *AppDB) GetWorkflowSubmissionPhotoExports(ctx context.Context, workflowI
 			ws.step_order ASC,
 			wsi.item_order ASC,
 			p.created_at ASC;
-	`, workflowID)
+	`, workflowID, variant)
 	if err != nil {
 		return nil, fmt.Errorf("error querying workflow submission photos export: %s", err)
 	}
@@ -6097,6 +6254,8 @@ func (a *AppDB) GetWorkflowSubmissionPhotoExports(ctx context.Context, workflowI
 			&export.Photo.SizeBytes,
 			&export.Photo.CreatedAt,
 			&export.Photo.PhotoData,
+			&export.Photo.StorageBackend,
+			&export.Photo.StorageKey,
 			&export.StepOrder,
 			&export.ItemOrder,
 			&export.ItemTitle,
@@ -6107,6 +6266,13 @@ func (a *AppDB) GetWorkflowSubmissionPhotoExports(ctx context.Context, workflowI
 		}
 		results = append(results, export)
 	}
+	rows.Close()
+
+	for _, export := range results {
+		if err := a.loadWorkflowPhotoData(ctx, &export.Photo); err != nil {
+			return nil, err
+		}
+	}
 
 	return results, nil
 }
@@ -7516,6 +7682,14 @@ func (a *AppDB) CreateWorkflowStepPhotoUpload(
 	if err != nil {
 		return nil, err
 	}
+	keepLocation, err := workflowItemKeepsPhotoLocation(ctx, a.db, itemId)
+	if err != nil {
+		return nil, err
+	}
+	processed, err := a.processWorkflowPhoto(ctx, parsedUpload, keepLocation)
+	if err != nil {
+		return nil, err
+	}
 
 	tx, err := a.db.Begin(ctx)
 	if err != nil {
@@ -7596,7 +7770,53 @@ func (a *AppDB) CreateWorkflowStepPhotoUpload(
 		return nil, fmt.Errorf("work item does not belong to step")
 	}
 
+	photo, stored, err := a.insertWorkflowStepPhotoUploadTx(ctx, tx, workflowId, stepId, itemId, improverId, processed)
+	committed := false
+	defer func() {
+		if !committed {
+			a.deleteWorkflowPhotoObjects(context.WithoutCancel(ctx), stored)
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return photo, nil
+}
+
+// insertWorkflowStepPhotoUploadTx stores a processed upload, its renditions
+// and any reuse match. It returns the objects written, including on failure,
+// for the caller to remove if the transaction does not commit.
+func (a *AppDB) insertWorkflowStepPhotoUploadTx(
+	ctx context.Context,
+	tx pgx.Tx,
+	workflowId string,
+	stepId string,
+	itemId string,
+	improverId string,
+	processed *processedWorkflowPhoto,
+) (*structs.WorkflowSubmissionPhoto, []photoObject, error) {
+	reuse, err := findReusedWorkflowPhotoTx(ctx, tx, stepId, processed.Hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	geofence, err := workflowStepGeofence(ctx, tx, stepId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading workflow step geofence: %s", err)
+	}
+
 	photoID := uuid.NewString()
+	object, err := a.storeWorkflowPhoto(ctx, workflowId, photoID, processed.ContentType, processed.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	stored := []photoObject{object}
+
 	if _, err := tx.Exec(ctx, `
 		INSERT INTO workflow_step_photo_uploads
 			(
@@ -7608,29 +7828,41 @@ func (a *AppDB) CreateWorkflowStepPhotoUpload(
 				file_name,
 				content_type,
 				photo_data,
-				size_bytes
+				size_bytes,
+				storage_backend,
+				storage_key,
+				location_stripped,
+				perceptual_hash,
+				reused_photo_id,
+				reuse_distance,
+				gps_distance_meters
 			)
 		VALUES
-			($1, $2, $3, $4, $5, $6, $7, $8, $9);
-	`, photoID, workflowId, stepId, itemId, improverId, parsedUpload.FileName, parsedUpload.ContentType, parsedUpload.Data, len(parsedUpload.Data)); err != nil {
-		return nil, fmt.Errorf("error inserting workflow step photo upload: %s", err)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);
+	`, photoID, workflowId, stepId, itemId, improverId, processed.FileName, processed.ContentType, object.Inline, len(processed.Data), object.Backend, object.Key, processed.LocationStripped, processed.Hash, reuse.PhotoId, reuse.Distance, workflowPhotoGPSDistance(processed.Location, geofence)); err != nil {
+		return nil, stored, fmt.Errorf("error inserting workflow step photo upload: %s", err)
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, err
+	renditions, err := a.storeWorkflowPhotoRenditionsTx(ctx, tx, workflowId, photoID, processed.Renditions)
+	stored = append(stored, renditions...)
+	if err != nil {
+		return nil, stored, err
 	}
 
 	return &structs.WorkflowSubmissionPhoto{
-		Id:           photoID,
-		WorkflowId:   workflowId,
-		StepId:       stepId,
-		ItemId:       itemId,
-		SubmissionId: "",
-		FileName:     parsedUpload.FileName,
-		ContentType:  parsedUpload.ContentType,
-		SizeBytes:    len(parsedUpload.Data),
-		CreatedAt:    time.Now().Unix(),
-	}, nil
+		Id:               photoID,
+		WorkflowId:       workflowId,
+		StepId:           stepId,
+		ItemId:           itemId,
+		SubmissionId:     "",
+		FileName:         processed.FileName,
+		ContentType:      processed.ContentType,
+		SizeBytes:        len(processed.Data),
+		LocationStripped: processed.LocationStripped,
+		ReusedPhotoId:    reuse.PhotoId,
+		ReuseDistance:    reuse.Distance,
+		CreatedAt:        time.Now().Unix(),
+	}, stored, nil
 }
 
 func validateWorkflowStepPhotoUploadTargetTx(
@@ -7899,25 +8131,24 @@ func (a *AppDB) CreateWorkflowStepPhotoUploadChunk(
 	if err != nil {
 		return nil, err
 	}
+	keepLocation, err := workflowItemKeepsPhotoLocation(ctx, tx, itemId)
+	if err != nil {
+		return nil, err
+	}
+	processed, err := a.processWorkflowPhoto(ctx, parsedUpload, keepLocation)
+	if err != nil {
+		return nil, err
+	}
 
-	photoID := uuid.NewString()
-	if _, err := tx.Exec(ctx, `
-		INSERT INTO workflow_step_photo_uploads
-			(
-				id,
-				workflow_id,
-				step_id,
-				item_id,
-				improver_id,
-				file_name,
-				content_type,
-				photo_data,
-				size_bytes
-			)
-		VALUES
-			($1, $2, $3, $4, $5, $6, $7, $8, $9);
-	`, photoID, workflowId, stepId, itemId, improverId, parsedUpload.FileName, parsedUpload.ContentType, parsedUpload.Data, len(parsedUpload.Data)); err != nil {
-		return nil, fmt.Errorf("error finalizing workflow photo upload: %s", err)
+	photo, stored, err := a.insertWorkflowStepPhotoUploadTx(ctx, tx, workflowId, stepId, itemId, improverId, processed)
+	committed := false
+	defer func() {
+		if !committed {
+			a.deleteWorkflowPhotoObjects(context.WithoutCancel(ctx), stored)
+		}
+	}()
+	if err != nil {
+		return nil, err
 	}
 
 	if _, err := tx.Exec(ctx, `
@@ -7930,19 +8161,10 @@ func (a *AppDB) CreateWorkflowStepPhotoUploadChunk(
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
+	committed = true
 
 	result.Complete = true
-	result.Photo = &structs.WorkflowSubmissionPhoto{
-		Id:           photoID,
-		WorkflowId:   workflowId,
-		StepId:       stepId,
-		ItemId:       itemId,
-		SubmissionId: "",
-		FileName:     parsedUpload.FileName,
-		ContentType:  parsedUpload.ContentType,
-		SizeBytes:    len(parsedUpload.Data),
-		CreatedAt:    time.Now().Unix(),
-	}
+	result.Photo = photo
 	return result, nil
 }
 
@@ -7953,14 +8175,33 @@ func (a *AppDB) CompleteWorkflowStep(
 	improverId string,
 	stepNotPossible bool,
 	stepNotPossibleDetails *string,
+	location *structs.WorkflowStepLocation,
 	itemResponses []structs.WorkflowStepItemResponse,
 ) (*structs.WorkflowStepCompletionResult, error) {
+	location, err := normalizeWorkflowStepLocation(location)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := a.db.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback(ctx)
 
+	// Objects written for photos sent with the submission are removed if it
+	// does not commit; objects of the photos it replaces once it does.
+	storedPhotos := []photoObject{}
+	replacedPhotos := []photoObject{}
+	committed := false
+	defer func() {
+		if committed {
+			a.deleteWorkflowPhotoObjects(context.WithoutCancel(ctx), replacedPhotos)
+		} else {
+			a.deleteWorkflowPhotoObjects(context.WithoutCancel(ctx), storedPhotos)
+		}
+	}()
+
 	result := &structs.WorkflowStepCompletionResult{
 		AvailabilityNotifications: []structs.WorkflowStepAvailabilityNotification{},
 		DropdownNotifications:     []structs.WorkflowDropdownNotification{},
@@ -7968,11 +8209,13 @@ func (a *AppDB) CompleteWorkflowStep(
 
 	var workflowStatus string
 	var workflowStartAt int64
+	var approvalRequired bool
 	var workflowTitle string
 	err = tx.QueryRow(ctx, `
 			SELECT
 				w.status,
 				w.start_at,
+				w.supervisor_approval_required,
 				COALESCE(
 					(
 						SELECT
@@ -7989,7 +8232,7 @@ func (a *AppDB) CompleteWorkflowStep(
 			WHERE
 				w.id = $1
 			FOR UPDATE;
-		`, workflowId).Scan(&workflowStatus, &workflowStartAt, &workflowTitle)
+		`, workflowId).Scan(&workflowStatus, &workflowStartAt, &approvalRequired, &workflowTitle)
 	if err != nil {
 		return nil, err
 	}
@@ -8001,25 +8244,49 @@ func (a *AppDB) CompleteWorkflowStep(
 	var stepOrder int
 	var stepStatus string
 	var stepTitle string
+	var fullBounty uint64
 	var allowStepNotPossible bool
 	var assignedImproverId *string
+	var geofenceLatitude, geofenceLongitude, geofenceRadius *float64
+	var completionOpensAfter, completionClosesAfter *int
 	err = tx.QueryRow(ctx, `
 		SELECT
 			workflow_id,
 			step_order,
 			status,
 			title,
+			COALESCE(full_bounty, bounty),
 			allow_step_not_possible,
-			assigned_improver_id
+			assigned_improver_id,
+			geofence_latitude,
+			geofence_longitude,
+			geofence_radius_meters,
+			completion_opens_after_minutes,
+			completion_closes_after_minutes
 		FROM
 			workflow_steps
 		WHERE
 			id = $1
 		FOR UPDATE;
-	`, stepId).Scan(&stepWorkflowId, &stepOrder, &stepStatus, &stepTitle, &allowStepNotPossible, &assignedImproverId)
+	`, stepId).Scan(
+		&stepWorkflowId,
+		&stepOrder,
+		&stepStatus,
+		&stepTitle,
+		&fullBounty,
+		&allowStepNotPossible,
+		&assignedImproverId,
+		&geofenceLatitude,
+		&geofenceLongitude,
+		&geofenceRadius,
+		&completionOpensAfter,
+		&completionClosesAfter,
+	)
 	if err != nil {
 		return nil, err
 	}
+	geofence := workflowStepGeofenceFromColumns(geofenceLatitude, geofenceLongitude, geofenceRadius)
+	completionWindow := workflowStepCompletionWindowFromColumns(completionOpensAfter, completionClosesAfter)
 	if stepWorkflowId != workflowId {
 		return nil, fmt.Errorf("step does not belong to workflow")
 	}
@@ -8065,6 +8332,11 @@ func (a *AppDB) CompleteWorkflowStep(
 	photoUploadsByItem := map[string][]parsedWorkflowPhotoUpload{}
 	uploadedPhotoIDsByItem := map[string][]string{}
 	itemTitlesByID := map[string]string{}
+	keepPhotoLocationByItem := map[string]bool{}
+	// Only photos the app captured live count as location evidence; gallery
+	// uploads may have been taken anywhere, at any time.
+	cameraCaptureItemIDs := []string{}
+	payoutItems := []structs.WorkflowWorkItem{}
 	if !stepNotPossible {
 		itemRows, err := tx.Query(ctx, `
 			SELECT
@@ -8081,7 +8353,9 @@ func (a *AppDB) CompleteWorkflowStep(
 				dropdown_options,
 				dropdown_requires_written_response,
 				notify_emails,
-			notify_on_dropdown_values
+			notify_on_dropdown_values,
+			keep_photo_location,
+			bounty_weight
 		FROM
 			workflow_step_items
 		WHERE
@@ -8107,6 +8381,8 @@ func (a *AppDB) CompleteWorkflowStep(
 			RequiresDropdown           bool
 			DropdownOptions            []structs.WorkflowDropdownOption
 			DropdownRequiresWrittenMap map[string]bool
+			KeepPhotoLocation          bool
+			BountyWeight               int
 		}
 
 		items := []stepItemMeta{}
@@ -8133,6 +8409,8 @@ func (a *AppDB) CompleteWorkflowStep(
 				&dropdownRequiresBytes,
 				&notifyEmailsBytes,
 				&notifyValuesBytes,
+				&item.KeepPhotoLocation,
+				&item.BountyWeight,
 			); err != nil {
 				return nil, fmt.Errorf("error scanning workflow step item metadata: %s", err)
 			}
@@ -8214,6 +8492,13 @@ func (a *AppDB) CompleteWorkflowStep(
 			items = append(items, item)
 			itemByID[item.Id] = item
 			itemTitlesByID[item.Id] = item.Title
+			keepPhotoLocationByItem[item.Id] = item.KeepPhotoLocation
+			payoutItems = append(payoutItems, structs.WorkflowWorkItem{
+				Id:               item.Id,
+				RequiresDropdown: item.RequiresDropdown,
+				DropdownOptions:  item.DropdownOptions,
+				BountyWeight:     item.BountyWeight,
+			})
 		}
 
 		responseMap := map[string]structs.WorkflowStepItemResponse{}
@@ -8334,6 +8619,9 @@ func (a *AppDB) CompleteWorkflowStep(
 				}
 
 				if selectedOption != nil {
+					if selectedOption.CameraCaptureOnly && !item.CameraCaptureOnly {
+						cameraCaptureItemIDs = append(cameraCaptureItemIDs, item.Id)
+					}
 					if selectedOption.RequiresPhotoAttachment && totalPhotoCount == 0 {
 						instructions := strings.TrimSpace(selectedOption.PhotoInstructions)
 						requirementLabel := "photo attachment"
@@ -8363,6 +8651,9 @@ func (a *AppDB) CompleteWorkflowStep(
 				}
 			}
 
+			if item.CameraCaptureOnly {
+				cameraCaptureItemIDs = append(cameraCaptureItemIDs, item.Id)
+			}
 			serializedResponses = append(serializedResponses, response)
 		}
 	}
@@ -8397,13 +8688,35 @@ func (a *AppDB) CompleteWorkflowStep(
 		return nil, fmt.Errorf("error upserting workflow step submission: %s", err)
 	}
 
-	_, err = tx.Exec(ctx, `
-		DELETE FROM workflow_submission_photos
-		WHERE submission_id = $1;
+	replacedRows, err := tx.Query(ctx, `
+		WITH replaced AS (
+			DELETE FROM workflow_submission_photos
+			WHERE submission_id = $1
+			RETURNING id, storage_backend, storage_key
+		), replaced_renditions AS (
+			DELETE FROM workflow_photo_renditions
+			WHERE photo_id IN (SELECT id FROM replaced)
+			RETURNING storage_backend, storage_key
+		)
+		SELECT storage_backend, storage_key FROM replaced
+		UNION ALL
+		SELECT storage_backend, storage_key FROM replaced_renditions;
 	`, submissionId)
 	if err != nil {
 		return nil, fmt.Errorf("error clearing workflow submission photos: %s", err)
 	}
+	for replacedRows.Next() {
+		replaced := photoObject{}
+		if err := replacedRows.Scan(&replaced.Backend, &replaced.Key); err != nil {
+			replacedRows.Close()
+			return nil, fmt.Errorf("error scanning cleared workflow submission photo: %s", err)
+		}
+		replacedPhotos = append(replacedPhotos, replaced)
+	}
+	replacedRows.Close()
+	if err := replacedRows.Err(); err != nil {
+		return nil, fmt.Errorf("error clearing workflow submission photos: %s", err)
+	}
 
 	for responseIndex := range serializedResponses {
 		response := serializedResponses[responseIndex]
@@ -8415,7 +8728,21 @@ func (a *AppDB) CompleteWorkflowStep(
 
 		photoIDs := make([]string, 0, len(uploads)+len(uploadedPhotoIDs))
 		for _, upload := range uploads {
+			processed, err := a.processWorkflowPhoto(ctx, &upload, keepPhotoLocationByItem[response.ItemId])
+			if err != nil {
+				return nil, err
+			}
+			reuse, err := findReusedWorkflowPhotoTx(ctx, tx, stepId, processed.Hash)
+			if err != nil {
+				return nil, err
+			}
+
 			photoID := uuid.NewString()
+			object, err := a.storeWorkflowPhoto(ctx, workflowId, photoID, processed.ContentType, processed.Data)
+			if err != nil {
+				return nil, err
+			}
+			storedPhotos = append(storedPhotos, object)
 			if _, err := tx.Exec(ctx, `
 				INSERT INTO workflow_submission_photos
 					(
@@ -8427,13 +8754,26 @@ func (a *AppDB) CompleteWorkflowStep(
 						file_name,
 						content_type,
 						photo_data,
-						size_bytes
+						size_bytes,
+						storage_backend,
+						storage_key,
+						location_stripped,
+						perceptual_hash,
+						reused_photo_id,
+						reuse_distance,
+						gps_distance_meters
 					)
 				VALUES
-					($1, $2, $3, $4, $5, $6, $7, $8, $9);
-			`, photoID, workflowId, stepId, response.ItemId, submissionId, upload.FileName, upload.ContentType, upload.Data, len(upload.Data)); err != nil {
+					($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);
+			`, photoID, workflowId, stepId, response.ItemId, submissionId, processed.FileName, processed.ContentType, object.Inline, len(processed.Data), object.Backend, object.Key, processed.LocationStripped, processed.Hash, reuse.PhotoId, reuse.Distance, workflowPhotoGPSDistance(processed.Location, geofence)); err != nil {
 				return nil, fmt.Errorf("error inserting workflow submission photo: %s", err)
 			}
+
+			renditions, err := a.storeWorkflowPhotoRenditionsTx(ctx, tx, workflowId, photoID, processed.Renditions)
+			storedPhotos = append(storedPhotos, renditions...)
+			if err != nil {
+				return nil, err
+			}
 			photoIDs = append(photoIDs, photoID)
 		}
 
@@ -8450,6 +8790,13 @@ func (a *AppDB) CompleteWorkflowStep(
 						content_type,
 						photo_data,
 						size_bytes,
+						storage_backend,
+						storage_key,
+						location_stripped,
+						perceptual_hash,
+						reused_photo_id,
+						reuse_distance,
+						gps_distance_meters,
 						created_at,
 						updated_at
 					)
@@ -8463,6 +8810,13 @@ func (a *AppDB) CompleteWorkflowStep(
 					content_type,
 					photo_data,
 					size_bytes,
+					storage_backend,
+					storage_key,
+					location_stripped,
+					perceptual_hash,
+					reused_photo_id,
+					reuse_distance,
+					gps_distance_meters,
 					created_at,
 					unix_now()
 				FROM
@@ -8533,16 +8887,36 @@ func (a *AppDB) CompleteWorkflowStep(
 		return nil, fmt.Errorf("error marshalling workflow step responses: %s", err)
 	}
 
+	reviewFlags := []structs.WorkflowStepReviewFlag{}
+	if !stepNotPossible {
+		photoDistances, err := workflowSubmissionPhotoGPSDistancesTx(ctx, tx, submissionId, cameraCaptureItemIDs)
+		if err != nil {
+			return nil, err
+		}
+		reviewFlags = workflowStepReviewFlags(geofence, completionWindow, workflowStartAt, time.Now().Unix(), location, photoDistances)
+	}
+	reviewFlagsJSON, err := json.Marshal(reviewFlags)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling workflow step review flags: %s", err)
+	}
+	reviewStatus := ""
+	if len(reviewFlags) > 0 {
+		reviewStatus = structs.WorkflowStepReviewFlagged
+	} else if approvalRequired {
+		reviewStatus = structs.WorkflowStepReviewPending
+	}
+
 	_, err = tx.Exec(ctx, `
 		UPDATE
 			workflow_step_submissions
 		SET
 			item_responses = $2::jsonb,
+			review_flags = $3::jsonb,
 			submitted_at = unix_now(),
 			updated_at = unix_now()
 		WHERE
 			id = $1;
-	`, submissionId, string(responsesJSON))
+	`, submissionId, string(responsesJSON), string(reviewFlagsJSON))
 	if err != nil {
 		return nil, fmt.Errorf("error updating workflow step submission responses: %s", err)
 	}
@@ -8563,6 +8937,7 @@ func (a *AppDB) CompleteWorkflowStep(
 				payout_in_progress = false,
 				retry_requested_at = NULL,
 				retry_requested_by = NULL,
+				review_status = CASE WHEN review_status IN ('approved', 'rejected') THEN review_status ELSE '' END,
 				updated_at = unix_now()
 			WHERE
 				workflow_id = $1;
@@ -8602,9 +8977,13 @@ func (a *AppDB) CompleteWorkflowStep(
 		if err := tx.Commit(ctx); err != nil {
 			return nil, err
 		}
+		committed = true
 		return result, nil
 	}
 
+	// The step bounty becomes what this completion earns; full_bounty keeps
+	// the original so a resubmission is paid against it again.
+	payoutAmount := workflowStepPayoutAmount(fullBounty, payoutItems, serializedResponses)
 	_, err = tx.Exec(ctx, `
 		UPDATE
 			workflow_steps
@@ -8612,11 +8991,17 @@ func (a *AppDB) CompleteWorkflowStep(
 			status = 'completed',
 			started_at = COALESCE(started_at, unix_now()),
 			completed_at = unix_now(),
+			bounty = $3,
+			full_bounty = $4,
 			payout_in_progress = false,
+			review_status = $2,
+			reviewed_at = NULL,
+			reviewed_by = NULL,
+			review_comment = NULL,
 			updated_at = unix_now()
 		WHERE
 			id = $1;
-	`, stepId)
+	`, stepId, reviewStatus, payoutAmount, fullBounty)
 	if err != nil {
 		return nil, fmt.Errorf("error marking workflow step completed: %s", err)
 	}
@@ -8749,6 +9134,7 @@ func (a *AppDB) CompleteWorkflowStep(
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
+	committed = true
 	return result, nil
 }
 
@@ -9039,9 +9425,12 @@ func normalizeAdminWorkflowPayoutResolutionAction(value string) (string, error)
 	}
 }
 
+// ResolveWorkflowPayoutLockByAdmin applies an admin payout resolution and
+// records it, attributed to apiKeyId when the admin acted through an API key.
 func (a *AppDB) ResolveWorkflowPayoutLockByAdmin(
 	ctx context.Context,
 	adminId string,
+	apiKeyId string,
 	workflowId string,
 	req *structs.AdminWorkflowPayoutResolutionRequest,
 ) error {
@@ -9238,6 +9627,14 @@ func (a *AppDB) ResolveWorkflowPayoutLockByAdmin(
 		}
 	}
 
+	jobStepId := ""
+	if targetType == "step" {
+		jobStepId = stepId
+	}
+	if err := cancelActiveWorkflowPayoutJobsTx(ctx, tx, workflowId, jobStepId, targetType, "payout lock resolved by admin: "+action); err != nil {
+		return err
+	}
+
 	var stepIDValue any
 	if targetType == "step" {
 		stepIDValue = stepId
@@ -9252,11 +9649,12 @@ func (a *AppDB) ResolveWorkflowPayoutLockByAdmin(
 			target_type,
 			action,
 			error_message,
-			performed_by_user_id
+			performed_by_user_id,
+			api_key_id
 		)
 		VALUES
-			($1, $2, $3, $4, $5, $6, $7);
-	`, uuid.NewString(), workflowId, stepIDValue, targetType, action, errorMessage, adminId)
+			($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''));
+	`, uuid.NewString(), workflowId, stepIDValue, targetType, action, errorMessage, adminId, apiKeyId)
 	if err != nil {
 		return fmt.Errorf("error recording workflow payout admin action: %s", err)
 	}
@@ -9268,7 +9666,13 @@ func (a *AppDB) ResolveWorkflowPayoutLockByAdmin(
 }
 
 func (a *AppDB) ClaimWorkflowStepPayoutAttempt(ctx context.Context, workflowId string, stepId string) (bool, error) {
-	cmd, err := a.db.Exec(ctx, `
+	return claimWorkflowStepPayoutAttempt(ctx, a.db, workflowId, stepId)
+}
+
+func claimWorkflowStepPayoutAttempt(ctx context.Context, querier interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+}, workflowId string, stepId string) (bool, error) {
+	cmd, err := querier.Exec(ctx, `
 		UPDATE
 			workflow_steps
 		SET
@@ -9286,6 +9690,8 @@ func (a *AppDB) ClaimWorkflowStepPayoutAttempt(ctx context.Context, workflowId s
 			status = 'completed'
 		AND
 			bounty > 0
+		AND
+			review_status IN ('', 'approved')
 		AND
 			payout_in_progress = false;
 	`, stepId, workflowId)
@@ -9296,7 +9702,13 @@ func (a *AppDB) ClaimWorkflowStepPayoutAttempt(ctx context.Context, workflowId s
 }
 
 func (a *AppDB) ClaimWorkflowManagerPayoutAttempt(ctx context.Context, workflowId string) (bool, error) {
-	cmd, err := a.db.Exec(ctx, `
+	return claimWorkflowManagerPayoutAttempt(ctx, a.db, workflowId)
+}
+
+func claimWorkflowManagerPayoutAttempt(ctx context.Context, querier interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+}, workflowId string) (bool, error) {
+	cmd, err := querier.Exec(ctx, `
 		UPDATE
 			workflows
 		SET
@@ -9555,7 +9967,9 @@ func (a *AppDB) FinalizeWorkflowPaidOutIfSettled(ctx context.Context, workflowId
 		AND
 			status = 'completed'
 		AND
-			bounty = 0;
+			bounty = 0
+		AND
+			review_status IN ('', 'approved');
 	`, workflowId)
 	if err != nil {
 		return false, fmt.Errorf("error auto-settling zero-bounty workflow steps: %s", err)
@@ -9570,7 +9984,9 @@ func (a *AppDB) FinalizeWorkflowPaidOutIfSettled(ctx context.Context, workflowId
 		WHERE
 			workflow_id = $1
 		AND
-			status <> 'paid_out';
+			status <> 'paid_out'
+		AND NOT
+			(status = 'completed' AND review_status = 'rejected');
 	`, workflowId).Scan(&pendingStepCount)
 	if err != nil {
 		return false, fmt.Errorf("error checking pending workflow step payouts: %s", err)
@@ -9773,7 +10189,22 @@ func (a *AppDB) RecoverStaleWorkflowPayoutLocksForImprover(ctx context.Context,
 		AND
 			COALESCE(payout_last_try_at, 0) > 0
 		AND
-			payout_last_try_at <= $3;
+			payout_last_try_at <= $3
+		AND
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workflow_payout_jobs j
+				WHERE
+					j.workflow_id = workflow_steps.workflow_id
+				AND
+					j.step_id = workflow_steps.id
+				AND
+					j.target_type = 'step'
+				AND
+					j.status IN ('queued', 'leased')
+			);
 	`, improverId, errorMessage, staleBefore)
 	if err != nil {
 		return 0, 0, fmt.Errorf("error recovering stale workflow step payout locks: %s", err)
@@ -9804,7 +10235,20 @@ func (a *AppDB) RecoverStaleWorkflowPayoutLocksForImprover(ctx context.Context,
 		AND
 			COALESCE(manager_payout_last_try_at, 0) > 0
 		AND
-			manager_payout_last_try_at <= $3;
+			manager_payout_last_try_at <= $3
+		AND
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workflow_payout_jobs j
+				WHERE
+					j.workflow_id = workflows.id
+				AND
+					j.target_type = 'supervisor'
+				AND
+					j.status IN ('queued', 'leased')
+			);
 	`, improverId, errorMessage, staleBefore)
 	if err != nil {
 		return 0, 0, fmt.Errorf("error recovering stale workflow manager payout locks: %s", err)
@@ -9842,7 +10286,22 @@ func (a *AppDB) RecoverStaleWorkflowStepPayoutLock(ctx context.Context, workflow
 		AND
 			COALESCE(payout_last_try_at, 0) > 0
 		AND
-			payout_last_try_at <= $5;
+			payout_last_try_at <= $5
+		AND
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workflow_payout_jobs j
+				WHERE
+					j.workflow_id = workflow_steps.workflow_id
+				AND
+					j.step_id = workflow_steps.id
+				AND
+					j.target_type = 'step'
+				AND
+					j.status IN ('queued', 'leased')
+			);
 	`, stepId, workflowId, improverId, errorMessage, staleBefore)
 	if err != nil {
 		return false, fmt.Errorf("error recovering stale workflow step payout lock: %s", err)
@@ -9879,7 +10338,20 @@ func (a *AppDB) RecoverStaleWorkflowManagerPayoutLock(ctx context.Context, workf
 		AND
 			COALESCE(manager_payout_last_try_at, 0) > 0
 		AND
-			manager_payout_last_try_at <= $4;
+			manager_payout_last_try_at <= $4
+		AND
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workflow_payout_jobs j
+				WHERE
+					j.workflow_id = workflows.id
+				AND
+					j.target_type = 'supervisor'
+				AND
+					j.status IN ('queued', 'leased')
+			);
 	`, workflowId, improverId, errorMessage, staleBefore)
 	if err != nil {
 		return false, fmt.Errorf("error recovering stale workflow manager payout lock: %s", err)
@@ -9926,7 +10398,22 @@ func (a *AppDB) RecoverStaleWorkflowPayoutLocksForSeries(ctx context.Context, tr
 		AND
 			COALESCE(ws.payout_last_try_at, 0) > 0
 		AND
-			ws.payout_last_try_at <= $2;
+			ws.payout_last_try_at <= $2
+		AND
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workflow_payout_jobs j
+				WHERE
+					j.workflow_id = ws.workflow_id
+				AND
+					j.step_id = ws.id
+				AND
+					j.target_type = 'step'
+				AND
+					j.status IN ('queued', 'leased')
+			);
 	`, triggerWorkflowId, staleBefore, errorMessage)
 	if err != nil {
 		return 0, 0, fmt.Errorf("error recovering stale workflow step payout locks for series: %s", err)
@@ -9967,7 +10454,20 @@ func (a *AppDB) RecoverStaleWorkflowPayoutLocksForSeries(ctx context.Context, tr
 		AND
 			COALESCE(w.manager_payout_last_try_at, 0) > 0
 		AND
-			w.manager_payout_last_try_at <= $2;
+			w.manager_payout_last_try_at <= $2
+		AND
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workflow_payout_jobs j
+				WHERE
+					j.workflow_id = w.id
+				AND
+					j.target_type = 'supervisor'
+				AND
+					j.status IN ('queued', 'leased')
+			);
 	`, triggerWorkflowId, staleBefore, errorMessage)
 	if err != nil {
 		return 0, 0, fmt.Errorf("error recovering stale workflow manager payout locks for series: %s", err)
@@ -11578,6 +12078,7 @@ func (a *AppDB) GetWorkflowEditProposalByIDForUser(ctx context.Context, proposal
 			COALESCE(st.recurrence_end_at, sr.recurrence_end_at),
 			st.supervisor_user_id,
 			COALESCE(st.supervisor_bounty, 0),
+			COALESCE(st.supervisor_approval_required, false),
 			COALESCE(st.roles_json, '[]'::jsonb),
 			COALESCE(st.steps_json, '[]'::jsonb)
 		FROM
@@ -11623,6 +12124,7 @@ func (a *AppDB) GetWorkflowEditProposalByIDForUser(ctx context.Context, proposal
 		&proposal.RecurrenceEndAt,
 		&proposal.SupervisorUserId,
 		&proposal.SupervisorBounty,
+		&proposal.SupervisorApprovalRequired,
 		&rolesJSON,
 		&stepsJSON,
 	); err != nil {
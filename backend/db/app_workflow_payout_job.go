@@ -0,0 +1,639 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const workflowPayoutJobColumns = `
+	j.id,
+	j.workflow_id,
+	COALESCE(NULLIF(TRIM(st.title), ''), COALESCE(NULLIF(TRIM(s.title), ''), '')) AS workflow_title,
+	j.step_id,
+	j.target_type,
+	j.status,
+	j.attempts,
+	j.max_attempts,
+	j.run_at,
+	COALESCE(j.lease_owner, ''),
+	j.lease_expires_at,
+	j.heartbeat_at,
+	j.target_locked,
+	j.wallet_address,
+	j.amount,
+	j.tx_hash,
+	j.tx_chain_id,
+	j.tx_submitted_at,
+	j.last_error,
+	j.created_at,
+	j.updated_at,
+	j.completed_at,
+	j.request_id
+`
+
+const workflowPayoutJobJoins = `
+	LEFT JOIN
+		workflows w
+	ON
+		w.id = j.workflow_id
+	LEFT JOIN
+		workflow_states st
+	ON
+		st.id = w.workflow_state_id
+	LEFT JOIN
+		workflow_series s
+	ON
+		s.id = w.series_id
+`
+
+func scanWorkflowPayoutJob(row pgx.Row) (*structs.WorkflowPayoutJob, error) {
+	job := &structs.WorkflowPayoutJob{}
+	err := row.Scan(
+		&job.Id,
+		&job.WorkflowId,
+		&job.WorkflowTitle,
+		&job.StepId,
+		&job.TargetType,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LeaseOwner,
+		&job.LeaseExpiresAt,
+		&job.HeartbeatAt,
+		&job.TargetLocked,
+		&job.WalletAddress,
+		&job.Amount,
+		&job.TxHash,
+		&job.TxChainId,
+		&job.TxSubmittedAt,
+		&job.LastError,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.CompletedAt,
+		&job.RequestId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// EnqueueWorkflowPayoutJob queues a payout for a step or supervisor target. At
+// most one queued or leased job may exist per target, so enqueueing a target
+// that already has an active job is a no-op and returns false.
+func (a *AppDB) EnqueueWorkflowPayoutJob(ctx context.Context, workflowId string, stepId string, targetType string, amount uint64, targetLocked bool, maxAttempts int) (bool, error) {
+	workflowId = strings.TrimSpace(workflowId)
+	stepId = strings.TrimSpace(stepId)
+	if workflowId == "" {
+		return false, fmt.Errorf("workflow id is required")
+	}
+	switch targetType {
+	case structs.WorkflowPayoutTargetStep:
+		if stepId == "" {
+			return false, fmt.Errorf("step id is required for step payout job")
+		}
+	case structs.WorkflowPayoutTargetSupervisor:
+		stepId = ""
+	default:
+		return false, fmt.Errorf("invalid workflow payout job target type")
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	cmd, err := a.db.Exec(ctx, `
+		INSERT INTO workflow_payout_jobs(
+			id,
+			workflow_id,
+			step_id,
+			target_type,
+			amount,
+			target_locked,
+			max_attempts,
+			request_id
+		)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (workflow_id, step_id, target_type) WHERE status IN ('queued', 'leased')
+		DO NOTHING;
+	`, uuid.NewString(), workflowId, stepId, targetType, amount, targetLocked, maxAttempts, logger.RequestID(ctx))
+	if err != nil {
+		return false, fmt.Errorf("error enqueueing workflow payout job: %s", err)
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// LeaseWorkflowPayoutJobs leases up to limit runnable jobs to owner. Queued jobs
+// whose run_at has passed and leased jobs whose lease has expired (the previous
+// worker died or lost its heartbeat) are both eligible.
+func (a *AppDB) LeaseWorkflowPayoutJobs(ctx context.Context, owner string, leaseSeconds int64, limit int) ([]*structs.WorkflowPayoutJob, error) {
+	if limit <= 0 {
+		limit = 1
+	}
+	rows, err := a.db.Query(ctx, `
+		WITH leased AS (
+			UPDATE
+				workflow_payout_jobs
+			SET
+				status = 'leased',
+				lease_owner = $1,
+				lease_expires_at = unix_now() + $2,
+				heartbeat_at = unix_now(),
+				updated_at = unix_now()
+			WHERE
+				id IN (
+					SELECT
+						id
+					FROM
+						workflow_payout_jobs
+					WHERE
+						(status = 'queued' AND run_at <= unix_now())
+					OR
+						(status = 'leased' AND COALESCE(lease_expires_at, 0) <= unix_now())
+					ORDER BY
+						run_at,
+						created_at
+					LIMIT
+						$3
+					FOR UPDATE SKIP LOCKED
+				)
+			RETURNING
+				*
+		)
+		SELECT
+			`+workflowPayoutJobColumns+`
+		FROM
+			leased j
+		`+workflowPayoutJobJoins+`
+		ORDER BY
+			j.run_at,
+			j.created_at;
+	`, owner, leaseSeconds, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error leasing workflow payout jobs: %s", err)
+	}
+	defer rows.Close()
+
+	jobs := []*structs.WorkflowPayoutJob{}
+	for rows.Next() {
+		job, err := scanWorkflowPayoutJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning leased workflow payout job: %s", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading leased workflow payout jobs: %s", err)
+	}
+	return jobs, nil
+}
+
+// HeartbeatWorkflowPayoutJob extends the lease on a running job. It returns
+// false when owner no longer holds the lease.
+func (a *AppDB) HeartbeatWorkflowPayoutJob(ctx context.Context, jobId string, owner string, leaseSeconds int64) (bool, error) {
+	cmd, err := a.db.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			lease_expires_at = unix_now() + $3,
+			heartbeat_at = unix_now(),
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'leased'
+		AND
+			lease_owner = $2;
+	`, jobId, owner, leaseSeconds)
+	if err != nil {
+		return false, fmt.Errorf("error extending workflow payout job lease: %s", err)
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// ClaimWorkflowPayoutJobTarget takes the payout lock on a leased job's step,
+// or on its workflow's supervisor payout, and marks the job as holding it in
+// the same transaction. A job that comes back after a failed run or a lost
+// lease therefore knows whether the lock is its own. It returns false when
+// another payout attempt holds the lock.
+func (a *AppDB) ClaimWorkflowPayoutJobTarget(ctx context.Context, jobId string, owner string, workflowId string, stepId string, isManager bool) (bool, error) {
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var claimed bool
+	if isManager {
+		claimed, err = claimWorkflowManagerPayoutAttempt(ctx, tx, workflowId)
+	} else {
+		claimed, err = claimWorkflowStepPayoutAttempt(ctx, tx, workflowId, stepId)
+	}
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	cmd, err := tx.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			target_locked = true,
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'leased'
+		AND
+			lease_owner = $2;
+	`, jobId, owner)
+	if err != nil {
+		return false, fmt.Errorf("error marking workflow payout job target locked: %s", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return false, fmt.Errorf("workflow payout job lease lost")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *AppDB) SetWorkflowPayoutJobWallet(ctx context.Context, jobId string, owner string, walletAddress string, amount uint64) error {
+	cmd, err := a.db.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			wallet_address = $3,
+			amount = $4,
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'leased'
+		AND
+			lease_owner = $2;
+	`, jobId, owner, strings.TrimSpace(walletAddress), amount)
+	if err != nil {
+		return fmt.Errorf("error updating workflow payout job target: %s", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("workflow payout job lease lost")
+	}
+	return nil
+}
+
+func (a *AppDB) RecordWorkflowPayoutJobTx(ctx context.Context, jobId string, owner string, txHash string, chainID int64) error {
+	cmd, err := a.db.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			tx_hash = $3,
+			tx_chain_id = $4,
+			tx_submitted_at = unix_now(),
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'leased'
+		AND
+			lease_owner = $2;
+	`, jobId, owner, strings.TrimSpace(txHash), chainID)
+	if err != nil {
+		return fmt.Errorf("error recording workflow payout job tx hash: %s", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("workflow payout job lease lost")
+	}
+	return nil
+}
+
+// DeferWorkflowPayoutJob releases the lease and schedules the job to run again
+// after delaySeconds without consuming an attempt. It is used while a submitted
+// transfer is still waiting for confirmation.
+func (a *AppDB) DeferWorkflowPayoutJob(ctx context.Context, jobId string, owner string, delaySeconds int64, lastError string) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			status = 'queued',
+			run_at = unix_now() + $3,
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			last_error = $4,
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'leased'
+		AND
+			lease_owner = $2;
+	`, jobId, owner, delaySeconds, truncateWorkflowPayoutErrorMessage(lastError))
+	if err != nil {
+		return fmt.Errorf("error deferring workflow payout job: %s", err)
+	}
+	return nil
+}
+
+// RetryWorkflowPayoutJob records a failed attempt and schedules the next one
+// after delaySeconds. Any recorded tx hash is cleared because the caller has
+// established that the transfer did not land.
+func (a *AppDB) RetryWorkflowPayoutJob(ctx context.Context, jobId string, owner string, delaySeconds int64, lastError string) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			status = 'queued',
+			attempts = attempts + 1,
+			run_at = unix_now() + $3,
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			tx_hash = '',
+			tx_chain_id = NULL,
+			tx_submitted_at = NULL,
+			last_error = $4,
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'leased'
+		AND
+			lease_owner = $2;
+	`, jobId, owner, delaySeconds, truncateWorkflowPayoutErrorMessage(lastError))
+	if err != nil {
+		return fmt.Errorf("error scheduling workflow payout job retry: %s", err)
+	}
+	return nil
+}
+
+func (a *AppDB) CompleteWorkflowPayoutJob(ctx context.Context, jobId string, owner string) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			status = 'succeeded',
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			last_error = '',
+			completed_at = unix_now(),
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'leased'
+		AND
+			lease_owner = $2;
+	`, jobId, owner)
+	if err != nil {
+		return fmt.Errorf("error completing workflow payout job: %s", err)
+	}
+	return nil
+}
+
+func (a *AppDB) DeadLetterWorkflowPayoutJob(ctx context.Context, jobId string, owner string, lastError string) error {
+	_, err := a.db.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			status = 'dead',
+			attempts = attempts + 1,
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			target_locked = false,
+			last_error = $3,
+			completed_at = unix_now(),
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'leased'
+		AND
+			lease_owner = $2;
+	`, jobId, owner, truncateWorkflowPayoutErrorMessage(lastError))
+	if err != nil {
+		return fmt.Errorf("error dead-lettering workflow payout job: %s", err)
+	}
+	return nil
+}
+
+// GetWorkflowPayoutJobs lists payout jobs for the admin queue view, newest
+// first. An empty status returns every job.
+func (a *AppDB) GetWorkflowPayoutJobs(ctx context.Context, status string, workflowId string, page, count int) (*structs.WorkflowPayoutJobListResponse, error) {
+	if page < 0 {
+		page = 0
+	}
+	if count <= 0 {
+		count = 20
+	}
+	if count > 200 {
+		count = 200
+	}
+	status = strings.TrimSpace(status)
+	workflowId = strings.TrimSpace(workflowId)
+	offset := page * count
+
+	var total int
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*)
+		FROM
+			workflow_payout_jobs
+		WHERE
+			($1 = '' OR status = $1)
+		AND
+			($2 = '' OR workflow_id = $2);
+	`, status, workflowId).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("error counting workflow payout jobs: %s", err)
+	}
+
+	rows, err := a.db.Query(ctx, `
+		SELECT
+			`+workflowPayoutJobColumns+`
+		FROM
+			workflow_payout_jobs j
+		`+workflowPayoutJobJoins+`
+		WHERE
+			($1 = '' OR j.status = $1)
+		AND
+			($2 = '' OR j.workflow_id = $2)
+		ORDER BY
+			j.created_at DESC,
+			j.id
+		LIMIT
+			$3
+		OFFSET
+			$4;
+	`, status, workflowId, count, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting workflow payout jobs: %s", err)
+	}
+	defer rows.Close()
+
+	items := []*structs.WorkflowPayoutJob{}
+	for rows.Next() {
+		job, err := scanWorkflowPayoutJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning workflow payout job: %s", err)
+		}
+		items = append(items, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading workflow payout jobs: %s", err)
+	}
+
+	return &structs.WorkflowPayoutJobListResponse{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Count: count,
+	}, nil
+}
+
+// ReleaseWorkflowPayoutTarget clears the payout lock a job took on its step or
+// supervisor payout, recording why so the improver can request a retry.
+func (a *AppDB) ReleaseWorkflowPayoutTarget(ctx context.Context, workflowId string, stepId string, isManager bool, errorMessage string) error {
+	errorMessage = truncateWorkflowPayoutErrorMessage(errorMessage)
+	var err error
+	if isManager {
+		_, err = a.db.Exec(ctx, `
+			UPDATE
+				workflows
+			SET
+				manager_payout_in_progress = false,
+				manager_payout_error = $2,
+				updated_at = unix_now()
+			WHERE
+				id = $1
+			AND
+				manager_payout_in_progress = true;
+		`, workflowId, errorMessage)
+	} else {
+		_, err = a.db.Exec(ctx, `
+			UPDATE
+				workflow_steps
+			SET
+				payout_in_progress = false,
+				payout_error = $3,
+				updated_at = unix_now()
+			WHERE
+				id = $1
+			AND
+				workflow_id = $2
+			AND
+				payout_in_progress = true;
+		`, stepId, workflowId, errorMessage)
+	}
+	if err != nil {
+		return fmt.Errorf("error releasing workflow payout target lock: %s", err)
+	}
+	return nil
+}
+
+func (a *AppDB) GetWorkflowPayoutJob(ctx context.Context, jobId string) (*structs.WorkflowPayoutJob, error) {
+	return scanWorkflowPayoutJob(a.db.QueryRow(ctx, `
+		SELECT
+			`+workflowPayoutJobColumns+`
+		FROM
+			workflow_payout_jobs j
+		`+workflowPayoutJobJoins+`
+		WHERE
+			j.id = $1;
+	`, strings.TrimSpace(jobId)))
+}
+
+// RequeueWorkflowPayoutJob moves a dead job back onto the queue with a fresh
+// attempt budget. The worker re-claims the target's payout lock when it runs.
+// clearTx drops a recorded transfer so the worker sends a new one; leave it
+// false when that transfer may still land, and the worker re-verifies it.
+func (a *AppDB) RequeueWorkflowPayoutJob(ctx context.Context, jobId string, clearTx bool) (*structs.WorkflowPayoutJob, error) {
+	jobId = strings.TrimSpace(jobId)
+	if jobId == "" {
+		return nil, fmt.Errorf("job id is required")
+	}
+
+	var status string
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			status
+		FROM
+			workflow_payout_jobs
+		WHERE
+			id = $1;
+	`, jobId).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+	if status != structs.WorkflowPayoutJobStatusDead {
+		return nil, fmt.Errorf("only dead workflow payout jobs can be requeued")
+	}
+
+	_, err = a.db.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			status = 'queued',
+			attempts = 0,
+			run_at = unix_now(),
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			target_locked = false,
+			tx_hash = CASE WHEN $2 THEN '' ELSE tx_hash END,
+			tx_chain_id = CASE WHEN $2 THEN NULL ELSE tx_chain_id END,
+			tx_submitted_at = CASE WHEN $2 THEN NULL ELSE tx_submitted_at END,
+			completed_at = NULL,
+			updated_at = unix_now()
+		WHERE
+			id = $1
+		AND
+			status = 'dead';
+	`, jobId, clearTx)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "workflow_payout_jobs_active_target_idx" {
+			return nil, fmt.Errorf("workflow payout target already has an active job")
+		}
+		return nil, fmt.Errorf("error requeueing workflow payout job: %s", err)
+	}
+
+	return a.GetWorkflowPayoutJob(ctx, jobId)
+}
+
+// cancelActiveWorkflowPayoutJobsTx dead-letters any queued or leased job for a
+// target whose payout lock is being resolved by hand, so the worker does not
+// pick it back up afterwards.
+func cancelActiveWorkflowPayoutJobsTx(ctx context.Context, tx pgx.Tx, workflowId string, stepId string, targetType string, reason string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE
+			workflow_payout_jobs
+		SET
+			status = 'dead',
+			lease_owner = NULL,
+			lease_expires_at = NULL,
+			target_locked = false,
+			last_error = $4,
+			completed_at = unix_now(),
+			updated_at = unix_now()
+		WHERE
+			workflow_id = $1
+		AND
+			step_id = $2
+		AND
+			target_type = $3
+		AND
+			status IN ('queued', 'leased');
+	`, workflowId, stepId, targetType, truncateWorkflowPayoutErrorMessage(reason))
+	if err != nil {
+		return fmt.Errorf("error cancelling active workflow payout jobs: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/SFLuv/app/backend/photoproc"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// workflowPhotoReuseMaxDistance is how many of the 64 perceptual hash bits
+// may differ for two photos to count as the same picture.
+const workflowPhotoReuseMaxDistance = 6
+
+// workflowPhotoReuseWindowSeconds bounds how far back an upload is compared
+// for reuse, so the check scans a recent slice of photos rather than all of
+// them.
+const workflowPhotoReuseWindowSeconds = 180 * 24 * 60 * 60
+
+// processedWorkflowPhoto is an upload after photoproc has scrubbed it, ready
+// to store. Hash is nil for formats that could not be decoded; Location is
+// the GPS position the upload carried, kept only in memory for geofence
+// checks.
+type processedWorkflowPhoto struct {
+	parsedWorkflowPhotoUpload
+	LocationStripped bool
+	Location         *photoproc.Location
+	Hash             *int64
+	Renditions       []photoproc.Rendition
+}
+
+// workflowPhotoReuse is an earlier photo from another step that a new upload
+// matches.
+type workflowPhotoReuse struct {
+	PhotoId  *string
+	Distance *int
+}
+
+func workflowItemKeepsPhotoLocation(ctx context.Context, querier interface {
+	QueryRow(context.Context, string, ...any) pgx.Row
+}, itemId string) (bool, error) {
+	var keepLocation bool
+	err := querier.QueryRow(ctx, `
+		SELECT
+			keep_photo_location
+		FROM
+			workflow_step_items
+		WHERE
+			id = $1;
+	`, itemId).Scan(&keepLocation)
+	return keepLocation, err
+}
+
+// processWorkflowPhoto scrubs and renders an upload. Photos in a format whose
+// location cannot be removed are refused unless the item keeps locations.
+func (a *AppDB) processWorkflowPhoto(ctx context.Context, upload *parsedWorkflowPhotoUpload, keepLocation bool) (*processedWorkflowPhoto, error) {
+	result, err := photoproc.Process(upload.Data, photoproc.Options{KeepLocation: keepLocation})
+	if errors.Is(err, photoproc.ErrLocationNotRemovable) {
+		return nil, fmt.Errorf("invalid photo format for %s: upload a JPEG, PNG or GIF so its location can be removed", upload.FileName)
+	}
+	if err != nil {
+		a.logger.Errorf(ctx, "error rendering workflow photo %s: %s", upload.FileName, err)
+	}
+
+	processed := &processedWorkflowPhoto{
+		parsedWorkflowPhotoUpload: *upload,
+		LocationStripped:          result.LocationStripped,
+		Location:                  result.Location,
+		Renditions:                result.Renditions,
+	}
+	processed.Data = result.Data
+	if result.HasHash {
+		hash := int64(result.Hash)
+		processed.Hash = &hash
+	}
+	return processed, nil
+}
+
+// findReusedWorkflowPhotoTx looks for an earlier photo from a different step,
+// taken within workflowPhotoReuseWindowSeconds, that is within
+// workflowPhotoReuseMaxDistance of hash. Nearly uniform pictures, such as a
+// dark frame, hash alike without being the same photo and are skipped.
+func findReusedWorkflowPhotoTx(ctx context.Context, tx pgx.Tx, stepId string, hash *int64) (workflowPhotoReuse, error) {
+	reuse := workflowPhotoReuse{}
+	if hash == nil {
+		return reuse, nil
+	}
+	if ones := bits.OnesCount64(uint64(*hash)); ones < 8 || ones > 56 {
+		return reuse, nil
+	}
+
+	var photoId string
+	var distance int
+	err := tx.QueryRow(ctx, `
+		SELECT
+			id,
+			distance
+		FROM
+			(
+				SELECT
+					id,
+					created_at,
+					LENGTH(REPLACE(((perceptual_hash # $1)::BIT(64))::TEXT, '0', '')) AS distance
+				FROM
+					workflow_submission_photos
+				WHERE
+					perceptual_hash IS NOT NULL
+				AND
+					created_at >= unix_now() - $4
+				AND
+					step_id <> $2
+				UNION ALL
+				SELECT
+					id,
+					created_at,
+					LENGTH(REPLACE(((perceptual_hash # $1)::BIT(64))::TEXT, '0', '')) AS distance
+				FROM
+					workflow_step_photo_uploads
+				WHERE
+					perceptual_hash IS NOT NULL
+				AND
+					created_at >= unix_now() - $4
+				AND
+					step_id <> $2
+			) candidates
+		WHERE
+			distance <= $3
+		ORDER BY
+			distance ASC,
+			created_at ASC
+		LIMIT 1;
+	`, *hash, stepId, workflowPhotoReuseMaxDistance, workflowPhotoReuseWindowSeconds).Scan(&photoId, &distance)
+	if err == pgx.ErrNoRows {
+		return reuse, nil
+	}
+	if err != nil {
+		return reuse, fmt.Errorf("error checking workflow photo reuse: %s", err)
+	}
+	reuse.PhotoId = &photoId
+	reuse.Distance = &distance
+	return reuse, nil
+}
+
+// storeWorkflowPhotoRenditionsTx writes a photo's renditions and returns the
+// objects stored, including on failure, so callers can clean them up.
+func (a *AppDB) storeWorkflowPhotoRenditionsTx(ctx context.Context, tx pgx.Tx, workflowId string, photoId string, renditions []photoproc.Rendition) ([]photoObject, error) {
+	stored := []photoObject{}
+	for _, rendition := range renditions {
+		renditionId := uuid.NewString()
+		object, err := a.storeWorkflowPhoto(ctx, workflowId, renditionId, rendition.ContentType, rendition.Data)
+		if err != nil {
+			return stored, err
+		}
+		stored = append(stored, object)
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO workflow_photo_renditions
+				(
+					id,
+					photo_id,
+					workflow_id,
+					variant,
+					content_type,
+					width,
+					height,
+					size_bytes,
+					photo_data,
+					storage_backend,
+					storage_key
+				)
+			VALUES
+				($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+		`, renditionId, photoId, workflowId, rendition.Variant, rendition.ContentType, rendition.Width, rendition.Height, len(rendition.Data), object.Inline, object.Backend, object.Key); err != nil {
+			return stored, fmt.Errorf("error inserting workflow photo rendition: %s", err)
+		}
+	}
+	return stored, nil
+}
+
+// GetWorkflowPhotoRendition loads the metadata and storage location of a
+// resized copy of photo. It returns pgx.ErrNoRows for photos stored before
+// renditions existed or in formats that could not be decoded.
+func (a *AppDB) GetWorkflowPhotoRendition(ctx context.Context, photo *structs.WorkflowSubmissionPhotoBlob, variant string) (*structs.WorkflowSubmissionPhotoBlob, error) {
+	return a.getWorkflowPhotoRendition(ctx, photo, variant, false)
+}
+
+// GetWorkflowPhotoRenditionBlob is GetWorkflowPhotoRendition with the bytes.
+func (a *AppDB) GetWorkflowPhotoRenditionBlob(ctx context.Context, photo *structs.WorkflowSubmissionPhotoBlob, variant string) (*structs.WorkflowSubmissionPhotoBlob, error) {
+	rendition, err := a.getWorkflowPhotoRendition(ctx, photo, variant, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.loadWorkflowPhotoData(ctx, rendition); err != nil {
+		return nil, err
+	}
+	return rendition, nil
+}
+
+func (a *AppDB) getWorkflowPhotoRendition(ctx context.Context, photo *structs.WorkflowSubmissionPhotoBlob, variant string, withData bool) (*structs.WorkflowSubmissionPhotoBlob, error) {
+	rendition := &structs.WorkflowSubmissionPhotoBlob{
+		WorkflowSubmissionPhoto: photo.WorkflowSubmissionPhoto,
+	}
+	err := a.db.QueryRow(ctx, `
+		SELECT
+			content_type,
+			size_bytes,
+			CASE WHEN $3 THEN photo_data END,
+			storage_backend,
+			storage_key
+		FROM
+			workflow_photo_renditions
+		WHERE
+			photo_id = $1
+		AND
+			variant = $2;
+	`, photo.Id, variant, withData).Scan(
+		&rendition.ContentType,
+		&rendition.SizeBytes,
+		&rendition.PhotoData,
+		&rendition.StorageBackend,
+		&rendition.StorageKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rendition, nil
+}
+
+// GetWorkflowPhotoReuses lists submitted photos flagged as matching a photo
+// from another step, newest first.
+func (a *AppDB) GetWorkflowPhotoReuses(ctx context.Context, page int, count int) (*structs.WorkflowPhotoReuseListResponse, error) {
+	var total int
+	if err := a.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*)
+		FROM
+			workflow_submission_photos
+		WHERE
+			reused_photo_id IS NOT NULL;
+	`).Scan(&total); err != nil {
+		return nil, fmt.Errorf("error counting reused workflow photos: %s", err)
+	}
+
+	rows, err := a.db.Query(ctx, `
+		SELECT
+			p.id,
+			p.workflow_id,
+			p.step_id,
+			p.item_id,
+			p.submission_id,
+			p.file_name,
+			p.content_type,
+			p.size_bytes,
+			p.location_stripped,
+			p.reused_photo_id,
+			p.reuse_distance,
+			p.created_at,
+			COALESCE(s.improver_id, ''),
+			COALESCE(original.workflow_id, ''),
+			COALESCE(original.step_id, ''),
+			COALESCE(original.created_at, 0)
+		FROM
+			workflow_submission_photos p
+		LEFT JOIN
+			workflow_step_submissions s
+		ON
+			s.id = p.submission_id
+		LEFT JOIN LATERAL
+			(
+				SELECT workflow_id, step_id, created_at FROM workflow_submission_photos WHERE id = p.reused_photo_id
+				UNION ALL
+				SELECT workflow_id, step_id, created_at FROM workflow_step_photo_uploads WHERE id = p.reused_photo_id
+				LIMIT 1
+			) original
+		ON
+			true
+		WHERE
+			p.reused_photo_id IS NOT NULL
+		ORDER BY
+			p.created_at DESC,
+			p.id DESC
+		LIMIT $1
+		OFFSET $2;
+	`, count, page*count)
+	if err != nil {
+		return nil, fmt.Errorf("error querying reused workflow photos: %s", err)
+	}
+	defer rows.Close()
+
+	results := []*structs.WorkflowPhotoReuse{}
+	for rows.Next() {
+		reuse := &structs.WorkflowPhotoReuse{}
+		if err := rows.Scan(
+			&reuse.Photo.Id,
+			&reuse.Photo.WorkflowId,
+			&reuse.Photo.StepId,
+			&reuse.Photo.ItemId,
+			&reuse.Photo.SubmissionId,
+			&reuse.Photo.FileName,
+			&reuse.Photo.ContentType,
+			&reuse.Photo.SizeBytes,
+			&reuse.Photo.LocationStripped,
+			&reuse.Photo.ReusedPhotoId,
+			&reuse.Photo.ReuseDistance,
+			&reuse.Photo.CreatedAt,
+			&reuse.ImproverId,
+			&reuse.ReusedWorkflowId,
+			&reuse.ReusedStepId,
+			&reuse.ReusedCreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning reused workflow photo: %s", err)
+		}
+		results = append(results, reuse)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error querying reused workflow photos: %s", err)
+	}
+	return &structs.WorkflowPhotoReuseListResponse{
+		Items: results,
+		Total: total,
+		Page:  page,
+		Count: count,
+	}, nil
+}
@@ -0,0 +1,275 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SFLuv/app/backend/photostore"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/jackc/pgx/v5"
+)
+
+// SetPhotoStore sets where new workflow photos are written. Without one,
+// photos stay in Postgres on the photo rows.
+func (a *AppDB) SetPhotoStore(store photostore.Store) {
+	if store != nil && store.Backend() == photostore.BackendPostgres {
+		store = nil
+	}
+	a.photoStore = store
+}
+
+// PhotoStore returns the store new workflow photos are written to.
+func (a *AppDB) PhotoStore() photostore.Store {
+	if a.photoStore == nil {
+		return a.PostgresPhotoStore()
+	}
+	return a.photoStore
+}
+
+// PostgresPhotoStore reads and writes photo bytes on the photo rows. Its keys
+// are photo ids.
+func (a *AppDB) PostgresPhotoStore() photostore.Store {
+	return &postgresPhotoStore{a: a}
+}
+
+// workflowPhotoTables hold photo bytes or their storage location, keyed by
+// id.
+var workflowPhotoTables = []string{"workflow_submission_photos", "workflow_step_photo_uploads", "workflow_photo_renditions"}
+
+type postgresPhotoStore struct {
+	a *AppDB
+}
+
+func (p *postgresPhotoStore) Backend() string {
+	return photostore.BackendPostgres
+}
+
+func (p *postgresPhotoStore) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	var updated int64
+	for _, table := range workflowPhotoTables {
+		cmd, err := p.a.db.Exec(ctx, `
+			UPDATE
+				`+table+`
+			SET
+				photo_data = $2,
+				storage_backend = 'postgres',
+				storage_key = '',
+				updated_at = unix_now()
+			WHERE
+				id = $1;
+		`, key, data)
+		if err != nil {
+			return fmt.Errorf("error storing workflow photo %s in postgres: %s", key, err)
+		}
+		updated += cmd.RowsAffected()
+	}
+	if updated == 0 {
+		return photostore.ErrNotFound
+	}
+	return nil
+}
+
+func (p *postgresPhotoStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := p.a.db.QueryRow(ctx, `
+		SELECT photo_data FROM workflow_submission_photos WHERE id = $1 AND photo_data IS NOT NULL
+		UNION ALL
+		SELECT photo_data FROM workflow_step_photo_uploads WHERE id = $1 AND photo_data IS NOT NULL
+		UNION ALL
+		SELECT photo_data FROM workflow_photo_renditions WHERE id = $1 AND photo_data IS NOT NULL
+		LIMIT 1;
+	`, key).Scan(&data)
+	if err == pgx.ErrNoRows {
+		return nil, photostore.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading workflow photo %s from postgres: %s", key, err)
+	}
+	return data, nil
+}
+
+// Delete is a no-op: the bytes go with the row, and callers moving a photo
+// elsewhere clear photo_data when they repoint the row.
+func (p *postgresPhotoStore) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+// photoObject is where a photo row's bytes live. Inline carries the bytes
+// for the Postgres backend, which keeps them on the row.
+type photoObject struct {
+	Backend string
+	Key     string
+	Inline  []byte
+}
+
+// storeWorkflowPhoto writes a new photo's bytes to the configured store and
+// returns what to record on its row.
+func (a *AppDB) storeWorkflowPhoto(ctx context.Context, workflowId string, photoId string, contentType string, data []byte) (photoObject, error) {
+	if a.photoStore == nil {
+		return photoObject{Backend: photostore.BackendPostgres, Inline: data}, nil
+	}
+	key := photostore.ObjectKey(workflowId, photoId)
+	if err := a.photoStore.Put(ctx, key, contentType, data); err != nil {
+		return photoObject{}, fmt.Errorf("error storing workflow photo: %s", err)
+	}
+	return photoObject{Backend: a.photoStore.Backend(), Key: key}, nil
+}
+
+// deleteWorkflowPhotoObjects removes external objects whose rows are gone.
+// Failures only leave an orphaned object behind, so they are logged.
+func (a *AppDB) deleteWorkflowPhotoObjects(ctx context.Context, objects []photoObject) {
+	for _, object := range objects {
+		if object.Backend == photostore.BackendPostgres || object.Key == "" {
+			continue
+		}
+		store, err := a.photoStoreFor(object.Backend)
+		if err == nil {
+			err = store.Delete(ctx, object.Key)
+		}
+		if err != nil {
+			a.logger.Errorf(ctx, "error deleting workflow photo object %s from %s: %s", object.Key, object.Backend, err)
+		}
+	}
+}
+
+func (a *AppDB) photoStoreFor(backend string) (photostore.Store, error) {
+	if backend == photostore.BackendPostgres {
+		return a.PostgresPhotoStore(), nil
+	}
+	if a.photoStore != nil && a.photoStore.Backend() == backend {
+		return a.photoStore, nil
+	}
+	return nil, fmt.Errorf("photo storage backend %q is not configured", backend)
+}
+
+// loadWorkflowPhotoData fills in PhotoData for photos kept outside Postgres.
+func (a *AppDB) loadWorkflowPhotoData(ctx context.Context, photo *structs.WorkflowSubmissionPhotoBlob) error {
+	if photo.StorageBackend == "" || photo.StorageBackend == photostore.BackendPostgres {
+		return nil
+	}
+	store, err := a.photoStoreFor(photo.StorageBackend)
+	if err != nil {
+		return err
+	}
+	data, err := store.Get(ctx, photo.StorageKey)
+	if err != nil {
+		return fmt.Errorf("error loading workflow photo %s from %s: %w", photo.Id, photo.StorageBackend, err)
+	}
+	photo.PhotoData = data
+	return nil
+}
+
+// WorkflowPhotoSigner returns the store able to sign a direct URL for photo,
+// if its backend has one.
+func (a *AppDB) WorkflowPhotoSigner(photo *structs.WorkflowSubmissionPhotoBlob) (photostore.Signer, bool) {
+	store, err := a.photoStoreFor(photo.StorageBackend)
+	if err != nil {
+		return nil, false
+	}
+	signer, ok := store.(photostore.Signer)
+	return signer, ok
+}
+
+// MigrateWorkflowPhotos moves up to batchSize photos, uploads and renditions
+// included, from one store to another and returns how many moved. Each row
+// is repointed only if it still names the source backend, so concurrent runs
+// and new uploads are safe; the source object is deleted once the row points
+// at the copy.
+func (a *AppDB) MigrateWorkflowPhotos(ctx context.Context, from photostore.Store, to photostore.Store, batchSize int) (int, error) {
+	if from.Backend() == to.Backend() {
+		return 0, fmt.Errorf("source and target photo storage are both %s", from.Backend())
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	rows, err := a.db.Query(ctx, `
+		SELECT 'workflow_submission_photos', id, workflow_id, content_type, storage_key
+		FROM workflow_submission_photos
+		WHERE storage_backend = $1
+		UNION ALL
+		SELECT 'workflow_step_photo_uploads', id, workflow_id, content_type, storage_key
+		FROM workflow_step_photo_uploads
+		WHERE storage_backend = $1
+		UNION ALL
+		SELECT 'workflow_photo_renditions', id, workflow_id, content_type, storage_key
+		FROM workflow_photo_renditions
+		WHERE storage_backend = $1
+		LIMIT $2;
+	`, from.Backend(), batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("error listing workflow photos to migrate: %s", err)
+	}
+
+	type pendingPhoto struct {
+		table       string
+		id          string
+		workflowId  string
+		contentType string
+		key         string
+	}
+	pending := []pendingPhoto{}
+	for rows.Next() {
+		photo := pendingPhoto{}
+		if err := rows.Scan(&photo.table, &photo.id, &photo.workflowId, &photo.contentType, &photo.key); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("error scanning workflow photo to migrate: %s", err)
+		}
+		if from.Backend() == photostore.BackendPostgres {
+			photo.key = photo.id
+		}
+		pending = append(pending, photo)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error listing workflow photos to migrate: %s", err)
+	}
+
+	moved := 0
+	for _, photo := range pending {
+		data, err := from.Get(ctx, photo.key)
+		if err != nil {
+			return moved, fmt.Errorf("error reading workflow photo %s: %w", photo.id, err)
+		}
+
+		target := photoObject{Backend: to.Backend()}
+		if to.Backend() == photostore.BackendPostgres {
+			target.Inline = data
+		} else {
+			target.Key = photostore.ObjectKey(photo.workflowId, photo.id)
+			if err := to.Put(ctx, target.Key, photo.contentType, data); err != nil {
+				return moved, fmt.Errorf("error writing workflow photo %s: %w", photo.id, err)
+			}
+		}
+
+		cmd, err := a.db.Exec(ctx, `
+			UPDATE
+				`+photo.table+`
+			SET
+				storage_backend = $3,
+				storage_key = $4,
+				photo_data = $5,
+				updated_at = unix_now()
+			WHERE
+				id = $1
+			AND
+				storage_backend = $2;
+		`, photo.id, from.Backend(), target.Backend, target.Key, target.Inline)
+		if err != nil {
+			return moved, fmt.Errorf("error repointing workflow photo %s: %s", photo.id, err)
+		}
+		if cmd.RowsAffected() == 0 {
+			// Moved or deleted by someone else; drop our copy.
+			if target.Key != "" {
+				_ = to.Delete(ctx, target.Key)
+			}
+			continue
+		}
+
+		if err := from.Delete(ctx, photo.key); err != nil {
+			a.logger.Errorf(ctx, "error deleting migrated workflow photo %s from %s: %s", photo.id, from.Backend(), err)
+		}
+		moved++
+	}
+	return moved, nil
+}
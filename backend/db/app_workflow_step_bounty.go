@@ -0,0 +1,78 @@
+package db
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+const maxWorkflowItemBountyWeight = 1000
+
+func validateWorkflowItemBountyWeight(weight int) error {
+	if weight < 0 || weight > maxWorkflowItemBountyWeight {
+		return fmt.Errorf("workflow work item bounty_weight must be between 0 and %d", maxWorkflowItemBountyWeight)
+	}
+	return nil
+}
+
+// workflowItemMaxBountyWeight is the most an item can earn: its own weight or
+// that of its best dropdown option, whichever is higher.
+func workflowItemMaxBountyWeight(item structs.WorkflowWorkItem) uint64 {
+	weight := uint64(item.BountyWeight)
+	for _, option := range item.DropdownOptions {
+		if option.BountyWeight != nil && uint64(*option.BountyWeight) > weight {
+			weight = uint64(*option.BountyWeight)
+		}
+	}
+	return weight
+}
+
+// workflowItemEarnedBountyWeight is the weight a completed item earns. A
+// selected dropdown option with its own weight replaces the item's.
+func workflowItemEarnedBountyWeight(item structs.WorkflowWorkItem, response structs.WorkflowStepItemResponse) uint64 {
+	if item.RequiresDropdown && response.DropdownValue != nil {
+		for _, option := range item.DropdownOptions {
+			if option.Value == *response.DropdownValue && option.BountyWeight != nil {
+				return uint64(*option.BountyWeight)
+			}
+		}
+	}
+	return uint64(item.BountyWeight)
+}
+
+// workflowStepPayoutAmount works out what a completed step pays. When none
+// of the step's items carry a bounty weight the full bounty is paid, as
+// before weights existed. Otherwise the bounty is split by weight and the
+// step pays the share earned by the items answered in responses, rounded
+// down. The result never exceeds bounty, so step bounties remain the
+// maximum liability used for budgeting.
+func workflowStepPayoutAmount(bounty uint64, items []structs.WorkflowWorkItem, responses []structs.WorkflowStepItemResponse) uint64 {
+	possible := uint64(0)
+	for _, item := range items {
+		possible += workflowItemMaxBountyWeight(item)
+	}
+	if possible == 0 {
+		return bounty
+	}
+
+	responseByItem := make(map[string]structs.WorkflowStepItemResponse, len(responses))
+	for _, response := range responses {
+		responseByItem[response.ItemId] = response
+	}
+	earned := uint64(0)
+	for _, item := range items {
+		response, ok := responseByItem[item.Id]
+		if !ok {
+			continue
+		}
+		earned += workflowItemEarnedBountyWeight(item, response)
+	}
+	if earned >= possible {
+		return bounty
+	}
+
+	hi, lo := bits.Mul64(bounty, earned)
+	amount, _ := bits.Div64(hi, lo, possible)
+	return amount
+}
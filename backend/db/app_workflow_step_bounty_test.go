@@ -0,0 +1,61 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestWorkflowStepPayoutAmount(t *testing.T) {
+	weight := func(value int) *int { return &value }
+	selected := func(value string) *string { return &value }
+
+	unweighted := []structs.WorkflowWorkItem{{Id: "a"}, {Id: "b"}}
+	weighted := []structs.WorkflowWorkItem{
+		{Id: "a", BountyWeight: 1},
+		{Id: "b", BountyWeight: 1},
+		{Id: "c", BountyWeight: 1},
+		{Id: "d", BountyWeight: 1},
+		{Id: "e", BountyWeight: 1},
+	}
+	// A condition dropdown worth up to 4 alongside a photo worth 1.
+	dropdown := []structs.WorkflowWorkItem{
+		{
+			Id:               "condition",
+			RequiresDropdown: true,
+			BountyWeight:     2,
+			DropdownOptions: []structs.WorkflowDropdownOption{
+				{Value: "cleaned", BountyWeight: weight(4)},
+				{Value: "partly_cleaned"},
+				{Value: "not_cleaned", BountyWeight: weight(0)},
+			},
+		},
+		{Id: "photo", BountyWeight: 1},
+	}
+
+	tests := []struct {
+		name      string
+		bounty    uint64
+		items     []structs.WorkflowWorkItem
+		responses []structs.WorkflowStepItemResponse
+		want      uint64
+	}{
+		{name: "unweighted pays in full", bounty: 100, items: unweighted, responses: []structs.WorkflowStepItemResponse{{ItemId: "a"}}, want: 100},
+		{name: "all weighted items", bounty: 100, items: weighted, responses: []structs.WorkflowStepItemResponse{{ItemId: "a"}, {ItemId: "b"}, {ItemId: "c"}, {ItemId: "d"}, {ItemId: "e"}}, want: 100},
+		{name: "four of five items", bounty: 100, items: weighted, responses: []structs.WorkflowStepItemResponse{{ItemId: "a"}, {ItemId: "b"}, {ItemId: "c"}, {ItemId: "d"}}, want: 80},
+		{name: "rounds down", bounty: 10, items: weighted[:3], responses: []structs.WorkflowStepItemResponse{{ItemId: "a"}}, want: 3},
+		{name: "best option", bounty: 50, items: dropdown, responses: []structs.WorkflowStepItemResponse{{ItemId: "condition", DropdownValue: selected("cleaned")}, {ItemId: "photo"}}, want: 50},
+		{name: "option without weight uses the item", bounty: 50, items: dropdown, responses: []structs.WorkflowStepItemResponse{{ItemId: "condition", DropdownValue: selected("partly_cleaned")}, {ItemId: "photo"}}, want: 30},
+		{name: "zero weight option", bounty: 50, items: dropdown, responses: []structs.WorkflowStepItemResponse{{ItemId: "condition", DropdownValue: selected("not_cleaned")}, {ItemId: "photo"}}, want: 10},
+		{name: "nothing answered", bounty: 50, items: dropdown, want: 0},
+		{name: "large bounty", bounty: 1 << 62, items: weighted[:2], responses: []structs.WorkflowStepItemResponse{{ItemId: "a"}}, want: 1 << 61},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := workflowStepPayoutAmount(test.bounty, test.items, test.responses); got != test.want {
+				t.Fatalf("expected %d, got %d", test.want, got)
+			}
+		})
+	}
+}
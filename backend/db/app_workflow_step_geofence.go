@@ -0,0 +1,253 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/SFLuv/app/backend/photoproc"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	maxWorkflowStepGeofenceRadiusMeters = 100000
+
+	// workflowPhotoGPSToleranceMeters allows for the error in camera GPS
+	// fixes, which photos do not record alongside the position.
+	workflowPhotoGPSToleranceMeters = 30
+
+	earthRadiusMeters = 6371008.8
+)
+
+func normalizeWorkflowStepGeofence(geofence *structs.WorkflowStepGeofence) (*structs.WorkflowStepGeofence, error) {
+	if geofence == nil {
+		return nil, nil
+	}
+	if !validWorkflowCoordinates(geofence.Latitude, geofence.Longitude) {
+		return nil, fmt.Errorf("workflow step geofence requires a valid latitude and longitude")
+	}
+	if math.IsNaN(geofence.RadiusMeters) || geofence.RadiusMeters <= 0 || geofence.RadiusMeters > maxWorkflowStepGeofenceRadiusMeters {
+		return nil, fmt.Errorf("workflow step geofence radius_meters must be greater than 0 and at most %d", maxWorkflowStepGeofenceRadiusMeters)
+	}
+	normalized := *geofence
+	return &normalized, nil
+}
+
+func normalizeWorkflowStepCompletionWindow(window *structs.WorkflowStepCompletionWindow) (*structs.WorkflowStepCompletionWindow, error) {
+	if window == nil {
+		return nil, nil
+	}
+	if window.OpensAfterMinutes < 0 {
+		return nil, fmt.Errorf("workflow step completion window cannot open before the workflow starts")
+	}
+	if window.ClosesAfterMinutes <= window.OpensAfterMinutes {
+		return nil, fmt.Errorf("workflow step completion window must close after it opens")
+	}
+	normalized := *window
+	return &normalized, nil
+}
+
+func validWorkflowCoordinates(latitude float64, longitude float64) bool {
+	if math.IsNaN(latitude) || math.IsNaN(longitude) {
+		return false
+	}
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+func normalizeWorkflowStepLocation(location *structs.WorkflowStepLocation) (*structs.WorkflowStepLocation, error) {
+	if location == nil {
+		return nil, nil
+	}
+	if !validWorkflowCoordinates(location.Latitude, location.Longitude) {
+		return nil, fmt.Errorf("invalid location: latitude or longitude is out of range")
+	}
+	if location.AccuracyMeters != nil && (math.IsNaN(*location.AccuracyMeters) || *location.AccuracyMeters < 0) {
+		return nil, fmt.Errorf("invalid location: accuracy_meters must not be negative")
+	}
+	normalized := *location
+	return &normalized, nil
+}
+
+// workflowStepGeofenceColumns splits a geofence into its nullable columns.
+func workflowStepGeofenceColumns(geofence *structs.WorkflowStepGeofence) (*float64, *float64, *float64) {
+	if geofence == nil {
+		return nil, nil, nil
+	}
+	return &geofence.Latitude, &geofence.Longitude, &geofence.RadiusMeters
+}
+
+func workflowStepGeofenceFromColumns(latitude *float64, longitude *float64, radiusMeters *float64) *structs.WorkflowStepGeofence {
+	if latitude == nil || longitude == nil || radiusMeters == nil {
+		return nil
+	}
+	return &structs.WorkflowStepGeofence{
+		Latitude:     *latitude,
+		Longitude:    *longitude,
+		RadiusMeters: *radiusMeters,
+	}
+}
+
+// workflowStepCompletionWindowColumns splits a completion window into its
+// nullable columns.
+func workflowStepCompletionWindowColumns(window *structs.WorkflowStepCompletionWindow) (*int, *int) {
+	if window == nil {
+		return nil, nil
+	}
+	return &window.OpensAfterMinutes, &window.ClosesAfterMinutes
+}
+
+func workflowStepCompletionWindowFromColumns(opensAfterMinutes *int, closesAfterMinutes *int) *structs.WorkflowStepCompletionWindow {
+	if opensAfterMinutes == nil || closesAfterMinutes == nil {
+		return nil
+	}
+	return &structs.WorkflowStepCompletionWindow{
+		OpensAfterMinutes:  *opensAfterMinutes,
+		ClosesAfterMinutes: *closesAfterMinutes,
+	}
+}
+
+func workflowStepGeofence(ctx context.Context, querier interface {
+	QueryRow(context.Context, string, ...any) pgx.Row
+}, stepId string) (*structs.WorkflowStepGeofence, error) {
+	var latitude, longitude, radiusMeters *float64
+	err := querier.QueryRow(ctx, `
+		SELECT
+			geofence_latitude,
+			geofence_longitude,
+			geofence_radius_meters
+		FROM
+			workflow_steps
+		WHERE
+			id = $1;
+	`, stepId).Scan(&latitude, &longitude, &radiusMeters)
+	if err != nil {
+		return nil, err
+	}
+	return workflowStepGeofenceFromColumns(latitude, longitude, radiusMeters), nil
+}
+
+// workflowSubmissionPhotoGPSDistancesTx returns the recorded geofence
+// distances of a submission's photos for the given items.
+func workflowSubmissionPhotoGPSDistancesTx(ctx context.Context, tx pgx.Tx, submissionId string, itemIds []string) ([]float64, error) {
+	distances := []float64{}
+	if len(itemIds) == 0 {
+		return distances, nil
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT
+			gps_distance_meters
+		FROM
+			workflow_submission_photos
+		WHERE
+			submission_id = $1
+		AND
+			item_id = ANY($2::text[])
+		AND
+			gps_distance_meters IS NOT NULL;
+	`, submissionId, itemIds)
+	if err != nil {
+		return nil, fmt.Errorf("error querying workflow submission photo locations: %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var distance float64
+		if err := rows.Scan(&distance); err != nil {
+			return nil, fmt.Errorf("error scanning workflow submission photo location: %s", err)
+		}
+		distances = append(distances, distance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error querying workflow submission photo locations: %s", err)
+	}
+	return distances, nil
+}
+
+// distanceMeters is the great-circle distance between two positions.
+func distanceMeters(latitudeA float64, longitudeA float64, latitudeB float64, longitudeB float64) float64 {
+	toRadians := math.Pi / 180
+	deltaLatitude := (latitudeB - latitudeA) * toRadians
+	deltaLongitude := (longitudeB - longitudeA) * toRadians
+	h := math.Sin(deltaLatitude/2)*math.Sin(deltaLatitude/2) +
+		math.Cos(latitudeA*toRadians)*math.Cos(latitudeB*toRadians)*math.Sin(deltaLongitude/2)*math.Sin(deltaLongitude/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// workflowPhotoGPSDistance is how far from the geofence centre a photo was
+// taken. Only the distance is kept; the position itself is not stored.
+func workflowPhotoGPSDistance(location *photoproc.Location, geofence *structs.WorkflowStepGeofence) *float64 {
+	if location == nil || geofence == nil {
+		return nil
+	}
+	distance := distanceMeters(geofence.Latitude, geofence.Longitude, location.Latitude, location.Longitude)
+	return &distance
+}
+
+// workflowStepReviewFlags checks a completion against the step's geofence
+// and completion window. The device location counts as inside when its
+// reported accuracy, capped at the radius, reaches the geofence; camera
+// photo distances get workflowPhotoGPSToleranceMeters instead. A geofenced
+// step completed with neither is flagged as unverified.
+func workflowStepReviewFlags(
+	geofence *structs.WorkflowStepGeofence,
+	window *structs.WorkflowStepCompletionWindow,
+	workflowStartAt int64,
+	submittedAt int64,
+	location *structs.WorkflowStepLocation,
+	photoDistances []float64,
+) []structs.WorkflowStepReviewFlag {
+	flags := []structs.WorkflowStepReviewFlag{}
+
+	if window != nil {
+		opensAt := workflowStartAt + int64(window.OpensAfterMinutes)*60
+		closesAt := workflowStartAt + int64(window.ClosesAfterMinutes)*60
+		if submittedAt < opensAt {
+			flags = append(flags, structs.WorkflowStepReviewFlag{
+				Code:   structs.WorkflowStepFlagOutsideCompletionWindow,
+				Detail: fmt.Sprintf("completed %d minute(s) before the completion window opened", (opensAt-submittedAt+59)/60),
+			})
+		} else if submittedAt > closesAt {
+			flags = append(flags, structs.WorkflowStepReviewFlag{
+				Code:   structs.WorkflowStepFlagOutsideCompletionWindow,
+				Detail: fmt.Sprintf("completed %d minute(s) after the completion window closed", (submittedAt-closesAt+59)/60),
+			})
+		}
+	}
+
+	if geofence == nil {
+		return flags
+	}
+	if location == nil && len(photoDistances) == 0 {
+		return append(flags, structs.WorkflowStepReviewFlag{
+			Code:   structs.WorkflowStepFlagLocationUnverified,
+			Detail: "no device location or camera photo location was provided",
+		})
+	}
+
+	if location != nil {
+		allowance := 0.0
+		if location.AccuracyMeters != nil {
+			allowance = math.Min(*location.AccuracyMeters, geofence.RadiusMeters)
+		}
+		distance := distanceMeters(geofence.Latitude, geofence.Longitude, location.Latitude, location.Longitude)
+		if distance > geofence.RadiusMeters+allowance {
+			flags = append(flags, structs.WorkflowStepReviewFlag{
+				Code:   structs.WorkflowStepFlagOutsideGeofence,
+				Detail: fmt.Sprintf("device location was %.0f m from the step location (radius %.0f m)", distance, geofence.RadiusMeters),
+			})
+		}
+	}
+
+	farthest := -1.0
+	for _, distance := range photoDistances {
+		farthest = math.Max(farthest, distance)
+	}
+	if farthest > geofence.RadiusMeters+workflowPhotoGPSToleranceMeters {
+		flags = append(flags, structs.WorkflowStepReviewFlag{
+			Code:   structs.WorkflowStepFlagOutsideGeofence,
+			Detail: fmt.Sprintf("a camera photo was taken %.0f m from the step location (radius %.0f m)", farthest, geofence.RadiusMeters),
+		})
+	}
+
+	return flags
+}
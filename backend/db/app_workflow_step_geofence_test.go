@@ -0,0 +1,93 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestWorkflowStepReviewFlags(t *testing.T) {
+	// Dolores Park, with a 100 m radius.
+	geofence := &structs.WorkflowStepGeofence{Latitude: 37.759773, Longitude: -122.427063, RadiusMeters: 100}
+	window := &structs.WorkflowStepCompletionWindow{OpensAfterMinutes: 60, ClosesAfterMinutes: 180}
+	startAt := int64(1_700_000_000)
+	inWindow := startAt + 90*60
+	accuracy := 80.0
+
+	inside := &structs.WorkflowStepLocation{Latitude: 37.7601, Longitude: -122.4272}
+	// Roughly 160 m north of the centre.
+	nearby := &structs.WorkflowStepLocation{Latitude: 37.76121, Longitude: -122.427063, AccuracyMeters: &accuracy}
+	// The Ferry Building, about 4.5 km away.
+	farAway := &structs.WorkflowStepLocation{Latitude: 37.795490, Longitude: -122.393738}
+
+	tests := []struct {
+		name           string
+		geofence       *structs.WorkflowStepGeofence
+		window         *structs.WorkflowStepCompletionWindow
+		submittedAt    int64
+		location       *structs.WorkflowStepLocation
+		photoDistances []float64
+		want           []string
+	}{
+		{name: "no bounds", submittedAt: startAt},
+		{name: "inside", geofence: geofence, window: window, submittedAt: inWindow, location: inside},
+		{name: "accuracy reaches the fence", geofence: geofence, submittedAt: inWindow, location: nearby},
+		{name: "outside", geofence: geofence, submittedAt: inWindow, location: farAway, want: []string{structs.WorkflowStepFlagOutsideGeofence}},
+		{name: "unverified", geofence: geofence, submittedAt: inWindow, want: []string{structs.WorkflowStepFlagLocationUnverified}},
+		{name: "camera photo inside", geofence: geofence, submittedAt: inWindow, photoDistances: []float64{40, 120}},
+		{name: "camera photo outside", geofence: geofence, submittedAt: inWindow, location: inside, photoDistances: []float64{40, 900}, want: []string{structs.WorkflowStepFlagOutsideGeofence}},
+		{name: "early", window: window, submittedAt: startAt + 30*60, want: []string{structs.WorkflowStepFlagOutsideCompletionWindow}},
+		{name: "late and outside", geofence: geofence, window: window, submittedAt: startAt + 240*60, location: farAway, want: []string{structs.WorkflowStepFlagOutsideCompletionWindow, structs.WorkflowStepFlagOutsideGeofence}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			flags := workflowStepReviewFlags(test.geofence, test.window, startAt, test.submittedAt, test.location, test.photoDistances)
+			if len(flags) != len(test.want) {
+				t.Fatalf("expected flags %v, got %+v", test.want, flags)
+			}
+			for i, flag := range flags {
+				if flag.Code != test.want[i] {
+					t.Fatalf("expected flags %v, got %+v", test.want, flags)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeWorkflowTemplateDataRequiresSupervisorForBounds(t *testing.T) {
+	supervisor := "did:privy:supervisor"
+	request := func(supervisorUserId *string, geofence *structs.WorkflowStepGeofence, window *structs.WorkflowStepCompletionWindow) *structs.WorkflowTemplateCreateRequest {
+		return &structs.WorkflowTemplateCreateRequest{
+			Recurrence:       "one_time",
+			SupervisorUserId: supervisorUserId,
+			Roles: []structs.WorkflowRoleCreateInput{
+				{ClientId: "role-1", Title: "Cleaner", RequiredCredentials: []string{"dpw_certified"}},
+			},
+			Steps: []structs.WorkflowStepCreateInput{{
+				Title:            "Sweep the park",
+				Bounty:           10,
+				RoleClientId:     "role-1",
+				Geofence:         geofence,
+				CompletionWindow: window,
+				WorkItems:        []structs.WorkflowWorkItemCreateInput{{Title: "Notes", RequiresWritten: true}},
+			}},
+		}
+	}
+	credentials := map[string]struct{}{"dpw_certified": {}}
+	geofence := &structs.WorkflowStepGeofence{Latitude: 37.759773, Longitude: -122.427063, RadiusMeters: 100}
+	window := &structs.WorkflowStepCompletionWindow{OpensAfterMinutes: 0, ClosesAfterMinutes: 120}
+
+	if _, err := normalizeWorkflowTemplateData(request(nil, nil, nil), credentials); err != nil {
+		t.Fatalf("unbounded step without supervisor: %s", err)
+	}
+	if _, err := normalizeWorkflowTemplateData(request(nil, geofence, nil), credentials); err == nil {
+		t.Fatalf("expected geofenced step without supervisor to be refused")
+	}
+	if _, err := normalizeWorkflowTemplateData(request(nil, nil, window), credentials); err == nil {
+		t.Fatalf("expected windowed step without supervisor to be refused")
+	}
+	if _, err := normalizeWorkflowTemplateData(request(&supervisor, geofence, window), credentials); err != nil {
+		t.Fatalf("bounded step with supervisor: %s", err)
+	}
+}
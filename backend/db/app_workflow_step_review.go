@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+// RecordWorkflowStepReview applies a supervisor's decision to a step
+// completion held for review and returns the review status it replaced.
+// Approving releases the step for payout and rejecting settles it unpaid. A
+// resubmission request reopens the step, and the workflow if it had already
+// completed, so the assigned improver can complete it again. It returns
+// pgx.ErrNoRows when the step is not part of the workflow.
+func (a *AppDB) RecordWorkflowStepReview(ctx context.Context, workflowId string, stepId string, reviewerId string, decision string, comment string) (string, error) {
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var workflowStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT
+			status
+		FROM
+			workflows
+		WHERE
+			id = $1
+		FOR UPDATE;
+	`, workflowId).Scan(&workflowStatus)
+	if err != nil {
+		return "", err
+	}
+
+	var reviewStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT
+			review_status
+		FROM
+			workflow_steps
+		WHERE
+			id = $1
+		AND
+			workflow_id = $2
+		FOR UPDATE;
+	`, stepId, workflowId).Scan(&reviewStatus)
+	if err != nil {
+		return "", err
+	}
+	if reviewStatus != structs.WorkflowStepReviewPending && reviewStatus != structs.WorkflowStepReviewFlagged {
+		return "", fmt.Errorf("step is not awaiting review")
+	}
+
+	var reviewComment *string
+	if trimmed := strings.TrimSpace(comment); trimmed != "" {
+		reviewComment = &trimmed
+	}
+
+	switch decision {
+	case structs.WorkflowStepReviewDecisionApprove, structs.WorkflowStepReviewDecisionReject:
+		newStatus := structs.WorkflowStepReviewApproved
+		if decision == structs.WorkflowStepReviewDecisionReject {
+			newStatus = structs.WorkflowStepReviewRejected
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE
+				workflow_steps
+			SET
+				review_status = $2,
+				reviewed_at = unix_now(),
+				reviewed_by = $3,
+				review_comment = $4,
+				updated_at = unix_now()
+			WHERE
+				id = $1;
+		`, stepId, newStatus, reviewerId, reviewComment); err != nil {
+			return "", fmt.Errorf("error recording workflow step review: %s", err)
+		}
+	case structs.WorkflowStepReviewDecisionRequestResubmission:
+		if workflowStatus != "in_progress" && workflowStatus != "completed" {
+			return "", fmt.Errorf("workflow is not active")
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE
+				workflow_steps
+			SET
+				status = 'in_progress',
+				completed_at = NULL,
+				review_status = $2,
+				reviewed_at = unix_now(),
+				reviewed_by = $3,
+				review_comment = $4,
+				updated_at = unix_now()
+			WHERE
+				id = $1;
+		`, stepId, structs.WorkflowStepReviewResubmissionRequested, reviewerId, reviewComment); err != nil {
+			return "", fmt.Errorf("error reopening workflow step for resubmission: %s", err)
+		}
+		if workflowStatus == "completed" {
+			if _, err := tx.Exec(ctx, `
+				UPDATE
+					workflows
+				SET
+					status = 'in_progress',
+					updated_at = unix_now()
+				WHERE
+					id = $1;
+			`, workflowId); err != nil {
+				return "", fmt.Errorf("error reopening workflow for resubmission: %s", err)
+			}
+		}
+	default:
+		return "", fmt.Errorf("invalid review decision")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+	return reviewStatus, nil
+}
@@ -0,0 +1,248 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func (s *BotDB) RecordBotTransaction(ctx context.Context, tx *structs.BotTransaction) error {
+	if tx == nil {
+		return fmt.Errorf("bot transaction is required")
+	}
+	originalHash := tx.OriginalHash
+	if originalHash == "" {
+		originalHash = tx.Hash
+	}
+
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO bot_transactions(
+			hash,
+			original_hash,
+			chain_id,
+			from_address,
+			to_address,
+			nonce,
+			data,
+			gas_limit,
+			gas_fee_cap,
+			gas_tip_cap,
+			raw_tx,
+			bump_count
+		)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (hash) DO NOTHING;
+	`,
+		strings.ToLower(tx.Hash),
+		strings.ToLower(originalHash),
+		tx.ChainId,
+		strings.ToLower(tx.FromAddress),
+		strings.ToLower(tx.ToAddress),
+		tx.Nonce,
+		tx.Data,
+		tx.GasLimit,
+		tx.GasFeeCap,
+		tx.GasTipCap,
+		tx.RawTx,
+		tx.BumpCount,
+	)
+	if err != nil {
+		return fmt.Errorf("error recording bot transaction: %s", err)
+	}
+	return nil
+}
+
+// ReplaceBotTransaction records a gas-bumped replacement for oldHash and marks
+// the old broadcast as replaced.
+func (s *BotDB) ReplaceBotTransaction(ctx context.Context, oldHash string, tx *structs.BotTransaction) error {
+	if tx == nil {
+		return fmt.Errorf("bot transaction is required")
+	}
+
+	dbTx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer dbTx.Rollback(ctx)
+
+	_, err = dbTx.Exec(ctx, `
+		UPDATE
+			bot_transactions
+		SET
+			status = 'replaced',
+			updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT
+		WHERE
+			hash = $1
+		AND
+			status = 'pending';
+	`, strings.ToLower(oldHash))
+	if err != nil {
+		return fmt.Errorf("error marking bot transaction replaced: %s", err)
+	}
+
+	_, err = dbTx.Exec(ctx, `
+		INSERT INTO bot_transactions(
+			hash,
+			original_hash,
+			chain_id,
+			from_address,
+			to_address,
+			nonce,
+			data,
+			gas_limit,
+			gas_fee_cap,
+			gas_tip_cap,
+			raw_tx,
+			bump_count
+		)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (hash) DO NOTHING;
+	`,
+		strings.ToLower(tx.Hash),
+		strings.ToLower(tx.OriginalHash),
+		tx.ChainId,
+		strings.ToLower(tx.FromAddress),
+		strings.ToLower(tx.ToAddress),
+		tx.Nonce,
+		tx.Data,
+		tx.GasLimit,
+		tx.GasFeeCap,
+		tx.GasTipCap,
+		tx.RawTx,
+		tx.BumpCount,
+	)
+	if err != nil {
+		return fmt.Errorf("error recording replacement bot transaction: %s", err)
+	}
+
+	return dbTx.Commit(ctx)
+}
+
+// SettleBotTransactionGroup closes out every broadcast sharing originalHash.
+// minedHash takes status; the rest of the group is marked replaced. Pass an
+// empty minedHash to mark the whole group with status (e.g. dropped).
+func (s *BotDB) SettleBotTransactionGroup(ctx context.Context, originalHash string, minedHash string, status string) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE
+			bot_transactions
+		SET
+			status = CASE
+				WHEN $2 = '' OR hash = $2 THEN $3
+				ELSE 'replaced'
+			END,
+			updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT
+		WHERE
+			original_hash = $1;
+	`, strings.ToLower(originalHash), strings.ToLower(minedHash), status)
+	if err != nil {
+		return fmt.Errorf("error settling bot transaction group: %s", err)
+	}
+	return nil
+}
+
+// GetPendingBotTransactions returns the latest broadcast of every unsettled
+// nonce for fromAddress on chainID, lowest nonce first.
+func (s *BotDB) GetPendingBotTransactions(ctx context.Context, chainID int64, fromAddress string) ([]*structs.BotTransaction, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT
+			hash,
+			original_hash,
+			chain_id,
+			from_address,
+			to_address,
+			nonce,
+			data,
+			gas_limit,
+			gas_fee_cap,
+			gas_tip_cap,
+			raw_tx,
+			bump_count,
+			status,
+			submitted_at,
+			updated_at
+		FROM
+			bot_transactions
+		WHERE
+			chain_id = $1
+		AND
+			from_address = $2
+		AND
+			status = 'pending'
+		ORDER BY
+			nonce,
+			bump_count DESC;
+	`, chainID, strings.ToLower(fromAddress))
+	if err != nil {
+		return nil, fmt.Errorf("error getting pending bot transactions: %s", err)
+	}
+	defer rows.Close()
+
+	txs := []*structs.BotTransaction{}
+	for rows.Next() {
+		tx := &structs.BotTransaction{}
+		if err := rows.Scan(
+			&tx.Hash,
+			&tx.OriginalHash,
+			&tx.ChainId,
+			&tx.FromAddress,
+			&tx.ToAddress,
+			&tx.Nonce,
+			&tx.Data,
+			&tx.GasLimit,
+			&tx.GasFeeCap,
+			&tx.GasTipCap,
+			&tx.RawTx,
+			&tx.BumpCount,
+			&tx.Status,
+			&tx.SubmittedAt,
+			&tx.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning pending bot transaction: %s", err)
+		}
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading pending bot transactions: %s", err)
+	}
+	return txs, nil
+}
+
+// GetBotTransactionGroupHashes returns every hash broadcast for the same nonce
+// as hash, newest first. Unknown hashes return an empty slice.
+func (s *BotDB) GetBotTransactionGroupHashes(ctx context.Context, hash string) ([]string, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT
+			g.hash
+		FROM
+			bot_transactions t
+		JOIN
+			bot_transactions g
+		ON
+			g.original_hash = t.original_hash
+		WHERE
+			t.hash = $1
+		ORDER BY
+			g.bump_count DESC;
+	`, strings.ToLower(strings.TrimSpace(hash)))
+	if err != nil {
+		return nil, fmt.Errorf("error getting bot transaction group: %s", err)
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+	for rows.Next() {
+		var groupHash string
+		if err := rows.Scan(&groupHash); err != nil {
+			return nil, fmt.Errorf("error scanning bot transaction group: %s", err)
+		}
+		hashes = append(hashes, groupHash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading bot transaction group: %s", err)
+	}
+	return hashes, nil
+}
@@ -19,6 +19,7 @@ func PgxDB(name string) (*pgxpool.Pool, error) {
 	}
 
 	config.MaxConns = 8
+	config.ConnConfig.Tracer = newQueryTracer(name)
 
 	return pgxpool.NewWithConfig(context.Background(), config)
 }
@@ -0,0 +1,244 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const eventTemplateColumns = `
+	id,
+	owner,
+	title,
+	description,
+	codes,
+	amount,
+	duration_seconds,
+	max_per_address,
+	max_per_user,
+	cooldown_seconds,
+	recurrence,
+	next_start_at,
+	anchor_start_at,
+	active,
+	last_event_id,
+	last_run_at,
+	last_error,
+	created_at,
+	updated_at
+`
+
+func scanEventTemplate(row interface {
+	Scan(dest ...any) error
+}) (*structs.EventTemplate, error) {
+	t := structs.EventTemplate{}
+	err := row.Scan(
+		&t.Id,
+		&t.Owner,
+		&t.Title,
+		&t.Description,
+		&t.Codes,
+		&t.Amount,
+		&t.DurationSeconds,
+		&t.MaxPerAddress,
+		&t.MaxPerUser,
+		&t.CooldownSeconds,
+		&t.Recurrence,
+		&t.NextStartAt,
+		&t.AnchorStartAt,
+		&t.Active,
+		&t.LastEventId,
+		&t.LastRunAt,
+		&t.LastError,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *BotDB) NewEventTemplate(ctx context.Context, t *structs.EventTemplate) (*structs.EventTemplate, error) {
+	row := s.db.QueryRow(ctx, `
+		INSERT INTO event_templates(
+			id,
+			owner,
+			title,
+			description,
+			codes,
+			amount,
+			duration_seconds,
+			max_per_address,
+			max_per_user,
+			cooldown_seconds,
+			recurrence,
+			next_start_at,
+			anchor_start_at
+		)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+		RETURNING `+eventTemplateColumns+`;
+	`,
+		uuid.NewString(),
+		t.Owner,
+		t.Title,
+		t.Description,
+		t.Codes,
+		t.Amount,
+		t.DurationSeconds,
+		t.MaxPerAddress,
+		t.MaxPerUser,
+		t.CooldownSeconds,
+		t.Recurrence,
+		t.NextStartAt,
+	)
+
+	template, err := scanEventTemplate(row)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting event template: %s", err)
+	}
+	return template, nil
+}
+
+func (s *BotDB) GetEventTemplate(ctx context.Context, id string) (*structs.EventTemplate, error) {
+	row := s.db.QueryRow(ctx, `
+		SELECT `+eventTemplateColumns+`
+		FROM
+			event_templates
+		WHERE
+			id = $1;
+	`, id)
+	return scanEventTemplate(row)
+}
+
+func (s *BotDB) GetEventTemplatesByOwner(ctx context.Context, owner string) ([]*structs.EventTemplate, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+eventTemplateColumns+`
+		FROM
+			event_templates
+		WHERE
+			owner = $1
+		AND
+			active
+		ORDER BY
+			created_at DESC;
+	`, owner)
+	if err != nil {
+		return nil, fmt.Errorf("error querying event templates: %s", err)
+	}
+	defer rows.Close()
+
+	templates := []*structs.EventTemplate{}
+	for rows.Next() {
+		template, err := scanEventTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning event template: %s", err)
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// DeactivateEventTemplate stops a template from recurring and hides it.
+// Events it already created are left alone.
+func (s *BotDB) DeactivateEventTemplate(ctx context.Context, id string, owner string) error {
+	tag, err := s.db.Exec(ctx, `
+		UPDATE
+			event_templates
+		SET
+			active = false,
+			updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT
+		WHERE
+			id = $1
+		AND
+			owner = $2
+		AND
+			active;
+	`, id, owner)
+	if err != nil {
+		return fmt.Errorf("error deactivating event template: %s", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetDueEventTemplates returns active recurring templates whose next event
+// should have started by now.
+func (s *BotDB) GetDueEventTemplates(ctx context.Context, now int64) ([]*structs.EventTemplate, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT `+eventTemplateColumns+`
+		FROM
+			event_templates
+		WHERE
+			active
+		AND
+			recurrence <> 'none'
+		AND
+			next_start_at > 0
+		AND
+			next_start_at <= $1
+		ORDER BY
+			next_start_at ASC;
+	`, now)
+	if err != nil {
+		return nil, fmt.Errorf("error querying due event templates: %s", err)
+	}
+	defer rows.Close()
+
+	templates := []*structs.EventTemplate{}
+	for rows.Next() {
+		template, err := scanEventTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning due event template: %s", err)
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// ClaimEventTemplateRun moves a template's next start from dueAt to
+// nextStartAt. It returns false if another run already claimed dueAt.
+func (s *BotDB) ClaimEventTemplateRun(ctx context.Context, id string, dueAt uint64, nextStartAt uint64) (bool, error) {
+	tag, err := s.db.Exec(ctx, `
+		UPDATE
+			event_templates
+		SET
+			next_start_at = $3,
+			updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT
+		WHERE
+			id = $1
+		AND
+			next_start_at = $2
+		AND
+			active;
+	`, id, dueAt, nextStartAt)
+	if err != nil {
+		return false, fmt.Errorf("error claiming event template run: %s", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// RecordEventTemplateRun stores the outcome of a scheduled run.
+func (s *BotDB) RecordEventTemplateRun(ctx context.Context, id string, eventId string, lastError string, ranAt uint64) error {
+	_, err := s.db.Exec(ctx, `
+		UPDATE
+			event_templates
+		SET
+			last_event_id = CASE WHEN $2 = '' THEN last_event_id ELSE $2 END,
+			last_error = $3,
+			last_run_at = $4,
+			updated_at = EXTRACT(EPOCH FROM NOW())::BIGINT
+		WHERE
+			id = $1;
+	`, id, eventId, lastError, ranAt)
+	if err != nil {
+		return fmt.Errorf("error recording event template run: %s", err)
+	}
+	return nil
+}
@@ -139,12 +139,6 @@ func (s *BotDB) CreateTables(defaultOwner string) error {
 		WHERE r.code = dup.code
 		AND r.id > dup.id;
 
-		DELETE FROM redemptions r
-		USING redemptions dup
-		WHERE r.id > dup.id
-		AND r.address = dup.address
-		AND r.event = dup.event;
-
 		ALTER TABLE redemptions
 		ALTER COLUMN address SET NOT NULL;
 
@@ -158,7 +152,6 @@ func (s *BotDB) CreateTables(defaultOwner string) error {
 			CREATE INDEX IF NOT EXISTS redemptions_chain_idx ON redemptions(chain_id);
 			CREATE INDEX IF NOT EXISTS redemption_address_event_idx ON redemptions(address, event);
 		CREATE UNIQUE INDEX IF NOT EXISTS redemptions_code_unique_idx ON redemptions(code);
-		CREATE UNIQUE INDEX IF NOT EXISTS redemptions_address_event_unique_idx ON redemptions(address, event);
 	`)
 	if err != nil {
 		err = fmt.Errorf("error migrating redemptions table constraints: %s", err)
@@ -199,10 +192,10 @@ func (s *BotDB) NewEvent(ctx context.Context, e *structs.Event) (string, error)
 
 	_, err = tx.Exec(ctx, `
 		INSERT INTO events
-			(id, title, description, amount, start_at, expiration, owner)
+			(id, title, description, amount, start_at, expiration, owner, max_per_address, max_per_user, cooldown_seconds, template_id)
 		VALUES
-		 ($1, $2, $3, $4, $5, $6, $7);
-	`, id, e.Title, e.Description, e.Amount, e.StartAt, e.Expiration, e.Owner)
+		 ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''));
+	`, id, e.Title, e.Description, e.Amount, e.StartAt, e.Expiration, e.Owner, e.MaxPerAddress, e.MaxPerUser, e.CooldownSeconds, e.TemplateId)
 	if err != nil {
 		tx.Rollback(ctx)
 		err = fmt.Errorf("error inserting event object: %s", err)
@@ -257,6 +250,10 @@ func (s *BotDB) GetEvents(ctx context.Context, e *structs.EventsRequest) ([]*str
 			e.start_at,
 			e.expiration,
 			e.owner,
+			e.max_per_address,
+			e.max_per_user,
+			e.cooldown_seconds,
+			COALESCE(e.template_id, ''),
 			COUNT(c.id)
 		FROM
 			events e
@@ -300,6 +297,10 @@ func (s *BotDB) GetEvents(ctx context.Context, e *structs.EventsRequest) ([]*str
 			&event.StartAt,
 			&event.Expiration,
 			&event.Owner,
+			&event.MaxPerAddress,
+			&event.MaxPerUser,
+			&event.CooldownSeconds,
+			&event.TemplateId,
 			&event.Codes,
 		)
 		if err != nil {
@@ -334,6 +335,10 @@ func (s *BotDB) GetEventsByOwner(ctx context.Context, e *structs.EventsRequest,
 			e.start_at,
 			e.expiration,
 			e.owner,
+			e.max_per_address,
+			e.max_per_user,
+			e.cooldown_seconds,
+			COALESCE(e.template_id, ''),
 			COUNT(c.id)
 		FROM
 			events e
@@ -379,6 +384,10 @@ func (s *BotDB) GetEventsByOwner(ctx context.Context, e *structs.EventsRequest,
 			&event.StartAt,
 			&event.Expiration,
 			&event.Owner,
+			&event.MaxPerAddress,
+			&event.MaxPerUser,
+			&event.CooldownSeconds,
+			&event.TemplateId,
 			&event.Codes,
 		)
 		if err != nil {
@@ -603,6 +612,108 @@ func (s *BotDB) GetCodes(ctx context.Context, r *structs.CodesPageRequest) ([]*s
 	return codes, nil
 }
 
+func (s *BotDB) GetEvent(ctx context.Context, id string) (*structs.Event, error) {
+	row := s.db.QueryRow(ctx, `
+		SELECT
+			e.id,
+			COALESCE(e.title, ''),
+			COALESCE(e.description, ''),
+			e.amount,
+			e.start_at,
+			COALESCE(e.expiration, 0),
+			COALESCE(e.owner, ''),
+			e.max_per_address,
+			e.max_per_user,
+			e.cooldown_seconds,
+			COALESCE(e.template_id, ''),
+			(SELECT COUNT(*) FROM codes c WHERE c.event = e.id)
+		FROM
+			events e
+		WHERE
+			e.id = $1;
+	`, id)
+
+	event := structs.Event{}
+	err := row.Scan(
+		&event.Id,
+		&event.Title,
+		&event.Description,
+		&event.Amount,
+		&event.StartAt,
+		&event.Expiration,
+		&event.Owner,
+		&event.MaxPerAddress,
+		&event.MaxPerUser,
+		&event.CooldownSeconds,
+		&event.TemplateId,
+		&event.Codes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// GetCodesForExport returns every code for an event with the address that
+// redeemed it, oldest first. unredeemedOnly limits the list to codes that can
+// still be handed out.
+func (s *BotDB) GetCodesForExport(ctx context.Context, event string, unredeemedOnly bool) ([]*structs.CodeExportRow, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT
+			c.id,
+			c.redeemed,
+			COALESCE(r.address, ''),
+			COALESCE(r.chain_id, 0)
+		FROM (
+			SELECT
+				id,
+				CASE
+					WHEN redeemed IS NULL THEN false
+					WHEN redeemed::text ~ '^-?[0-9]+$' THEN (redeemed::text)::bigint <> 0
+					WHEN LOWER(redeemed::text) IN ('t', 'true', 'y', 'yes', 'on') THEN true
+					ELSE false
+				END AS redeemed
+			FROM
+				codes
+			WHERE
+				event = $1
+		) c
+		LEFT JOIN LATERAL (
+			SELECT
+				address,
+				chain_id
+			FROM
+				redemptions
+			WHERE
+				code = c.id
+			ORDER BY
+				id DESC
+			LIMIT 1
+		) r ON true
+		WHERE
+			NOT $2 OR NOT c.redeemed
+		ORDER BY
+			c.id ASC;
+	`, event, unredeemedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("error querying event codes for export: %s", err)
+	}
+	defer rows.Close()
+
+	codes := []*structs.CodeExportRow{}
+	for rows.Next() {
+		code := structs.CodeExportRow{}
+		if err := rows.Scan(&code.Id, &code.Redeemed, &code.RedeemedBy, &code.ChainId); err != nil {
+			return nil, fmt.Errorf("error unpacking event codes for export: %s", err)
+		}
+		codes = append(codes, &code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading event codes for export: %s", err)
+	}
+	return codes, nil
+}
+
 func (s *BotDB) NewCodes(ctx context.Context, r *structs.NewCodesRequest) ([]*structs.Code, error) {
 	results := make([]*structs.Code, r.Count)
 
@@ -643,7 +754,53 @@ func (s *BotDB) NewCodes(ctx context.Context, r *structs.NewCodesRequest) ([]*st
 	return results, nil
 }
 
-func (s *BotDB) Redeem(ctx context.Context, id string, account string, chainID int64) (uint64, error) {
+// RedemptionLimitError is returned by Redeem when the event's redemption
+// policy rejects the claim.
+type RedemptionLimitError struct {
+	Limit structs.RedemptionLimit
+}
+
+func (e *RedemptionLimitError) Error() string {
+	return "redemption limit: " + e.Limit.Reason
+}
+
+// redemptionUsage is what a claimant has already redeemed from one event.
+type redemptionUsage struct {
+	addressCount uint32
+	userCount    uint32
+	lastRedeemAt int64
+}
+
+// checkRedemptionPolicy applies an event's limits to a claimant's prior
+// redemptions and returns the first one that is exceeded, or nil.
+func checkRedemptionPolicy(event *structs.Event, claimant structs.RedeemClaimant, usage redemptionUsage, now int64) *structs.RedemptionLimit {
+	maxPerAddress := event.MaxPerAddress
+	if maxPerAddress == 0 {
+		maxPerAddress = 1
+	}
+	if usage.addressCount >= maxPerAddress {
+		return &structs.RedemptionLimit{Error: "redemption_limit", Reason: "address_limit", Limit: maxPerAddress}
+	}
+	if event.MaxPerUser > 0 {
+		// Fresh addresses would otherwise walk around the user limit, so an
+		// event that sets one only pays out to addresses a user owns.
+		if claimant.UserId == "" {
+			return &structs.RedemptionLimit{Error: "redemption_limit", Reason: "user_required", Limit: event.MaxPerUser}
+		}
+		if usage.userCount >= event.MaxPerUser {
+			return &structs.RedemptionLimit{Error: "redemption_limit", Reason: "user_limit", Limit: event.MaxPerUser}
+		}
+	}
+	if event.CooldownSeconds > 0 && usage.lastRedeemAt > 0 {
+		readyAt := usage.lastRedeemAt + int64(event.CooldownSeconds)
+		if readyAt > now {
+			return &structs.RedemptionLimit{Error: "redemption_limit", Reason: "cooldown", RetryAfter: readyAt - now}
+		}
+	}
+	return nil
+}
+
+func (s *BotDB) Redeem(ctx context.Context, id string, account string, chainID int64, claimant structs.RedeemClaimant) (uint64, error) {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		err = fmt.Errorf("error creating db tx: %s", err)
@@ -657,7 +814,10 @@ func (s *BotDB) Redeem(ctx context.Context, id string, account string, chainID i
 			c.redeemed,
 			e.amount,
 			e.start_at,
-			e.expiration
+			e.expiration,
+			e.max_per_address,
+			e.max_per_user,
+			e.cooldown_seconds
 		FROM
 			codes c
 		JOIN
@@ -669,12 +829,20 @@ func (s *BotDB) Redeem(ctx context.Context, id string, account string, chainID i
 		FOR UPDATE;
 	`, id)
 
-	var eventID string
+	event := structs.Event{}
 	var codeRedeemed bool
-	var amount uint64
 	var startAt int64
 	var expiration int64
-	err = row.Scan(&eventID, &codeRedeemed, &amount, &startAt, &expiration)
+	err = row.Scan(
+		&event.Id,
+		&codeRedeemed,
+		&event.Amount,
+		&startAt,
+		&expiration,
+		&event.MaxPerAddress,
+		&event.MaxPerUser,
+		&event.CooldownSeconds,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return 0, fmt.Errorf("code redeemed")
@@ -693,6 +861,38 @@ func (s *BotDB) Redeem(ctx context.Context, id string, account string, chainID i
 		return 0, fmt.Errorf("code expired")
 	}
 
+	// Serialize redemptions within the event so concurrent claims by the same
+	// address or user cannot both pass the limit checks below. The address
+	// sequence index backs this up if anything inserts around the lock.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('redemptions:' || $1));`, event.Id); err != nil {
+		return 0, fmt.Errorf("error locking event redemptions: %w", err)
+	}
+
+	usage := redemptionUsage{}
+	err = tx.QueryRow(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE address = $2),
+			COUNT(*) FILTER (WHERE $3 <> '' AND user_id = $3),
+			COALESCE(MAX(redeemed_at) FILTER (
+				WHERE address = $2
+				OR ($3 <> '' AND user_id = $3)
+			), 0)
+		FROM
+			redemptions
+		WHERE
+			event = $1;
+	`, event.Id, account, claimant.UserId).Scan(
+		&usage.addressCount,
+		&usage.userCount,
+		&usage.lastRedeemAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error loading event redemption usage: %w", err)
+	}
+	if limit := checkRedemptionPolicy(&event, claimant, usage, currentTime); limit != nil {
+		return 0, &RedemptionLimitError{Limit: *limit}
+	}
+
 	tag, err := tx.Exec(ctx, `
 		UPDATE codes
 		SET redeemed = true
@@ -707,9 +907,9 @@ func (s *BotDB) Redeem(ctx context.Context, id string, account string, chainID i
 	}
 
 	_, err = tx.Exec(ctx, `
-			INSERT INTO redemptions(address, code, event, chain_id)
-			VALUES ($1, $2, $3, $4);
-		`, account, id, eventID, chainID)
+			INSERT INTO redemptions(address, code, event, chain_id, user_id, redeemed_at, address_seq)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7);
+		`, account, id, event.Id, chainID, claimant.UserId, currentTime, usage.addressCount+1)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -723,11 +923,23 @@ func (s *BotDB) Redeem(ctx context.Context, id string, account string, chainID i
 		return 0, fmt.Errorf("error inserting code redemption: %w", err)
 	}
 
+	err = recordRedemptionLedger(ctx, tx, &structs.RedemptionLedgerEntry{
+		Code:    id,
+		Event:   event.Id,
+		Address: account,
+		ChainId: chainID,
+		Amount:  event.Amount,
+		Action:  structs.RedemptionLedgerReserved,
+	})
+	if err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return 0, fmt.Errorf("error committing code redemption: %w", err)
 	}
 
-	return amount, nil
+	return event.Amount, nil
 }
 
 func (s *BotDB) GetCodeAmount(ctx context.Context, id string) (uint64, error) {
@@ -763,16 +975,24 @@ func (s *BotDB) UndoRedeem(ctx context.Context, id string, account string, chain
 
 	row := tx.QueryRow(ctx, `
 		SELECT
-			redeemed
+			c.redeemed,
+			c.event,
+			e.amount
 		FROM
-			codes
+			codes c
+		JOIN
+			events e
+		ON
+			e.id = c.event
 		WHERE
-			id = $1
-		FOR UPDATE;
+			c.id = $1
+		FOR UPDATE OF c;
 	`, id)
 
 	var redeemed bool
-	if err := row.Scan(&redeemed); err != nil {
+	var eventID string
+	var amount uint64
+	if err := row.Scan(&redeemed, &eventID, &amount); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil
 		}
@@ -820,6 +1040,18 @@ func (s *BotDB) UndoRedeem(ctx context.Context, id string, account string, chain
 		return fmt.Errorf("error updating code status during redeem undo: %w", err)
 	}
 
+	err = recordRedemptionLedger(ctx, tx, &structs.RedemptionLedgerEntry{
+		Code:    id,
+		Event:   eventID,
+		Address: account,
+		ChainId: chainID,
+		Amount:  amount,
+		Action:  structs.RedemptionLedgerUndone,
+	})
+	if err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("error committing redeem undo: %w", err)
 	}
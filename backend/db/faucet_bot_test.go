@@ -0,0 +1,48 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestCheckRedemptionPolicy(t *testing.T) {
+	now := int64(1700000000)
+	claimant := structs.RedeemClaimant{UserId: "did:privy:user"}
+
+	cases := []struct {
+		name       string
+		event      structs.Event
+		claimant   structs.RedeemClaimant
+		usage      redemptionUsage
+		wantReason string
+		wantRetry  int64
+	}{
+		{name: "first redemption", event: structs.Event{}, claimant: claimant},
+		{name: "default one per address", event: structs.Event{}, claimant: claimant, usage: redemptionUsage{addressCount: 1}, wantReason: "address_limit"},
+		{name: "address under limit", event: structs.Event{MaxPerAddress: 3}, claimant: claimant, usage: redemptionUsage{addressCount: 2}},
+		{name: "user limit", event: structs.Event{MaxPerAddress: 5, MaxPerUser: 2}, claimant: claimant, usage: redemptionUsage{userCount: 2}, wantReason: "user_limit"},
+		{name: "user limit requires an owned address", event: structs.Event{MaxPerAddress: 5, MaxPerUser: 2}, wantReason: "user_required"},
+		{name: "unowned address without user limit", event: structs.Event{MaxPerAddress: 5}},
+		{name: "cooldown active", event: structs.Event{MaxPerAddress: 5, CooldownSeconds: 600}, claimant: claimant, usage: redemptionUsage{addressCount: 1, lastRedeemAt: now - 100}, wantReason: "cooldown", wantRetry: 500},
+		{name: "cooldown elapsed", event: structs.Event{MaxPerAddress: 5, CooldownSeconds: 600}, claimant: claimant, usage: redemptionUsage{addressCount: 1, lastRedeemAt: now - 600}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			limit := checkRedemptionPolicy(&tc.event, tc.claimant, tc.usage, now)
+			if tc.wantReason == "" {
+				if limit != nil {
+					t.Fatalf("unexpected limit %+v", limit)
+				}
+				return
+			}
+			if limit == nil || limit.Reason != tc.wantReason || limit.Error != "redemption_limit" {
+				t.Fatalf("limit = %+v; want reason %s", limit, tc.wantReason)
+			}
+			if limit.RetryAfter != tc.wantRetry {
+				t.Fatalf("retry_after = %d; want %d", limit.RetryAfter, tc.wantRetry)
+			}
+		})
+	}
+}
@@ -155,3 +155,47 @@ func (p *PonderDB) GetTransactionPartiesByHash(ctx context.Context, txHash strin
 	tx.ChainID = chainID
 	return &tx, nil
 }
+
+// GetTransfersFromAddress returns the transfers sent by address between
+// startTimestamp and endTimestamp, inclusive, oldest first.
+func (p *PonderDB) GetTransfersFromAddress(ctx context.Context, address string, startTimestamp int64, endTimestamp int64, chainID int64) ([]*structs.PonderTransaction, error) {
+	rows, err := p.db.Query(ctx, `
+			SELECT
+				t.id,
+				t.hash,
+				t.amount::text,
+				t.timestamp,
+				LOWER(t.from),
+				LOWER(t.to)
+			FROM
+				transfer_event t
+			WHERE
+				t.from = LOWER($1)
+			AND
+				t.timestamp >= $2
+			AND
+				t.timestamp <= $3
+			ORDER BY
+				t.timestamp ASC,
+				t.id ASC;
+		`, address, startTimestamp, endTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("error querying transfers from address %s: %s", address, err)
+	}
+	defer rows.Close()
+
+	transfers := make([]*structs.PonderTransaction, 0)
+	for rows.Next() {
+		var t structs.PonderTransaction
+		if err := rows.Scan(&t.Id, &t.Hash, &t.Amount, &t.Timestamp, &t.From, &t.To); err != nil {
+			return nil, fmt.Errorf("error scanning transfer from address %s: %s", address, err)
+		}
+		t.Hash = strings.ToLower(t.Hash)
+		t.ChainID = chainID
+		transfers = append(transfers, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transfers from address %s: %s", address, err)
+	}
+	return transfers, nil
+}
@@ -18,14 +18,15 @@ func (s *BotDB) GetRecoveryBalance(ctx context.Context, address string) (*struct
 	address = strings.ToLower(strings.TrimSpace(address))
 	row := s.db.QueryRow(ctx, `
 		SELECT address, chain_id, amount::text, claim_status,
-		       COALESCE(claimed_by, ''), COALESCE(claim_tx_hash, ''), claimed_at
+		       COALESCE(claimed_by, ''), COALESCE(claim_tx_hash, ''),
+		       COALESCE(claim_tx_chain_id, 0), claimed_at
 		FROM recovery_balances
 		WHERE address = $1;
 	`, address)
 
 	var rb structs.RecoveryBalance
 	var claimedAt *time.Time
-	err := row.Scan(&rb.Address, &rb.ChainID, &rb.Amount, &rb.ClaimStatus, &rb.ClaimedBy, &rb.ClaimTxHash, &claimedAt)
+	err := row.Scan(&rb.Address, &rb.ChainID, &rb.Amount, &rb.ClaimStatus, &rb.ClaimedBy, &rb.ClaimTxHash, &rb.ClaimTxChainID, &claimedAt)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
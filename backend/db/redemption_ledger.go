@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// RecordRedemptionLedger appends an entry to the redemption ledger. The event
+// is looked up from the code when the entry does not carry one.
+func (s *BotDB) RecordRedemptionLedger(ctx context.Context, entry *structs.RedemptionLedgerEntry) error {
+	return recordRedemptionLedger(ctx, s.db, entry)
+}
+
+func recordRedemptionLedger(ctx context.Context, execer interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+}, entry *structs.RedemptionLedgerEntry) error {
+	if entry == nil {
+		return fmt.Errorf("redemption ledger entry is required")
+	}
+
+	_, err := execer.Exec(ctx, `
+		INSERT INTO redemption_ledger(
+			code,
+			event,
+			address,
+			chain_id,
+			amount,
+			action,
+			tx_hash,
+			error
+		)
+		VALUES
+			($1, COALESCE(NULLIF($2, ''), (SELECT event FROM codes WHERE id = $1)), $3, $4, $5, $6, $7, $8);
+	`,
+		entry.Code,
+		entry.Event,
+		strings.ToLower(entry.Address),
+		entry.ChainId,
+		entry.Amount,
+		entry.Action,
+		strings.ToLower(strings.TrimSpace(entry.TxHash)),
+		entry.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("error recording redemption ledger entry: %s", err)
+	}
+	return nil
+}
+
+// GetRedemptionLedger returns an event's ledger in the order it was written.
+func (s *BotDB) GetRedemptionLedger(ctx context.Context, event string) ([]*structs.RedemptionLedgerEntry, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT
+			id,
+			code,
+			event,
+			address,
+			chain_id,
+			amount,
+			action,
+			tx_hash,
+			error,
+			created_at
+		FROM
+			redemption_ledger
+		WHERE
+			event = $1
+		ORDER BY
+			id ASC;
+	`, event)
+	if err != nil {
+		return nil, fmt.Errorf("error querying redemption ledger: %s", err)
+	}
+	defer rows.Close()
+
+	entries := []*structs.RedemptionLedgerEntry{}
+	for rows.Next() {
+		entry := structs.RedemptionLedgerEntry{}
+		err := rows.Scan(
+			&entry.Id,
+			&entry.Code,
+			&entry.Event,
+			&entry.Address,
+			&entry.ChainId,
+			&entry.Amount,
+			&entry.Action,
+			&entry.TxHash,
+			&entry.Error,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning redemption ledger entry: %s", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading redemption ledger: %s", err)
+	}
+	return entries, nil
+}
+
+// GetRedemptionLedgerRecordedHashes reports which of hashes any redemption
+// ledger entry accounts for, directly or through a gas-bumped replacement of
+// the recorded transaction.
+func (s *BotDB) GetRedemptionLedgerRecordedHashes(ctx context.Context, hashes []string) (map[string]bool, error) {
+	recorded := map[string]bool{}
+	if len(hashes) == 0 {
+		return recorded, nil
+	}
+
+	normalized := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(hash)))
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT
+			h.hash
+		FROM
+			unnest($1::text[]) AS h(hash)
+		WHERE
+			EXISTS (
+				SELECT
+					1
+				FROM
+					redemption_ledger l
+				WHERE
+					l.tx_hash = h.hash
+			)
+		OR
+			EXISTS (
+				SELECT
+					1
+				FROM
+					bot_transactions t
+				JOIN
+					bot_transactions g
+				ON
+					g.original_hash = t.original_hash
+				JOIN
+					redemption_ledger l
+				ON
+					l.tx_hash = g.hash
+				WHERE
+					t.hash = h.hash
+			);
+	`, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("error querying recorded redemption hashes: %s", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("error scanning recorded redemption hash: %s", err)
+		}
+		recorded[hash] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading recorded redemption hashes: %s", err)
+	}
+	return recorded, nil
+}
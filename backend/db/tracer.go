@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultSlowQueryThreshold = 500 * time.Millisecond
+	maxLoggedQueryLength      = 500
+)
+
+// queryTracer logs failed and slow queries at warn and every other query at
+// debug. Records go through the default slog logger with the query's
+// context, so they carry the request ID of the handler or job that ran them.
+type queryTracer struct {
+	db   string
+	slow time.Duration
+}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	sql   string
+	start time.Time
+}
+
+func newQueryTracer(name string) *queryTracer {
+	slow := defaultSlowQueryThreshold
+	if ms, err := strconv.Atoi(strings.TrimSpace(os.Getenv("LOG_SLOW_QUERY_MS"))); err == nil && ms > 0 {
+		slow = time.Duration(ms) * time.Millisecond
+	}
+	return &queryTracer{db: name, slow: slow}
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, queryTrace{sql: data.SQL, start: time.Now()})
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(queryTrace)
+	if !ok {
+		return
+	}
+	duration := time.Since(trace.start)
+
+	level := slog.LevelDebug
+	msg := "query"
+	switch {
+	case data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) && !errors.Is(data.Err, context.Canceled):
+		level = slog.LevelWarn
+		msg = "query failed"
+	case duration >= t.slow:
+		level = slog.LevelWarn
+		msg = "slow query"
+	}
+
+	logger := slog.Default()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("db", t.db),
+		slog.String("sql", compactSQL(trace.sql)),
+		slog.Duration("duration", duration),
+		slog.Int64("rows", data.CommandTag.RowsAffected()),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, slog.String("error", data.Err.Error()))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxLoggedQueryLength {
+		sql = sql[:maxLoggedQueryLength] + "..."
+	}
+	return sql
+}
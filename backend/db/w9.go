@@ -349,6 +349,79 @@ func (a *AppDB) GetW9SubmissionByWalletYear(ctx context.Context, wallet string,
 	return &submission, nil
 }
 
+func (a *AppDB) GetW9SubmissionByID(ctx context.Context, id int) (*structs.W9Submission, error) {
+	row := a.db.QueryRow(ctx, `
+		SELECT
+			id,
+			wallet_address,
+			year,
+			email,
+			submitted_at,
+			pending_approval,
+			approved_at,
+			approved_by_user_id,
+			rejected_at,
+			rejected_by_user_id,
+			rejection_reason,
+			w9_url
+		FROM
+			w9_submissions
+		WHERE
+			id = $1;
+	`, id)
+
+	var submission structs.W9Submission
+	var approvedAt sql.NullTime
+	var approvedBy sql.NullString
+	var rejectedAt sql.NullTime
+	var rejectedBy sql.NullString
+	var rejectionReason sql.NullString
+	var w9Url sql.NullString
+	err := row.Scan(
+		&submission.Id,
+		&submission.WalletAddress,
+		&submission.Year,
+		&submission.Email,
+		&submission.SubmittedAt,
+		&submission.PendingApproval,
+		&approvedAt,
+		&approvedBy,
+		&rejectedAt,
+		&rejectedBy,
+		&rejectionReason,
+		&w9Url,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if approvedAt.Valid {
+		t := approvedAt.Time
+		submission.ApprovedAt = &t
+	}
+	if approvedBy.Valid {
+		submission.ApprovedByUserId = &approvedBy.String
+	}
+	if rejectedAt.Valid {
+		t := rejectedAt.Time
+		submission.RejectedAt = &t
+	}
+	if rejectedBy.Valid {
+		submission.RejectedByUserId = &rejectedBy.String
+	}
+	if rejectionReason.Valid {
+		submission.RejectionReason = &rejectionReason.String
+	}
+	if w9Url.Valid {
+		submission.W9URL = &w9Url.String
+	}
+
+	return &submission, nil
+}
+
 func (a *AppDB) GetPendingW9Submissions(ctx context.Context) ([]*structs.W9Submission, error) {
 	rows, err := a.db.Query(ctx, `
 		SELECT
@@ -0,0 +1,247 @@
+// Package emails renders notification emails from the templates embedded
+// under templates/. Each email is one file per locale that defines its
+// "subject", "title", "subtitle", "html" and "text" blocks; the shared
+// layouts wrap them. Copy lives in those files so wording changes never touch
+// handler code. Every supported locale has a file for every email; unsupported
+// locales fall back to English.
+package emails
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+const DefaultLocale = "en"
+
+// Locales are the languages emails are written in, for SF's English,
+// Spanish and Chinese speaking communities.
+var Locales = []string{"en", "es", "zh"}
+
+//go:embed all:templates
+var templateFS embed.FS
+
+//go:embed samples
+var sampleFS embed.FS
+
+// Data is what a template renders. Keys are the names the template uses,
+// like .WorkflowTitle.
+type Data map[string]any
+
+type Rendered struct {
+	Locale  string `json:"locale"`
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+type TemplateInfo struct {
+	Name    string   `json:"name"`
+	Locales []string `json:"locales"`
+}
+
+// Row is one label and value line of an email's details table.
+type Row struct {
+	Label string
+	Value any
+	Kind  string
+	Last  bool
+}
+
+var funcs = map[string]any{
+	"row":       func(label string, value any) Row { return Row{Label: label, Value: value} },
+	"idRow":     func(label string, value any) Row { return Row{Label: label, Value: value, Kind: "id"} },
+	"strongRow": func(label string, value any) Row { return Row{Label: label, Value: value, Kind: "strong"} },
+	"preRow":    func(label string, value any) Row { return Row{Label: label, Value: value, Kind: "pre"} },
+	"rows": func(rows ...Row) []Row {
+		if len(rows) > 0 {
+			rows[len(rows)-1].Last = true
+		}
+		return rows
+	},
+}
+
+type templateKey struct {
+	name   string
+	locale string
+}
+
+type registry struct {
+	html    map[templateKey]*htmltemplate.Template
+	text    map[templateKey]*texttemplate.Template
+	locales map[string][]string
+}
+
+var templates = mustLoad()
+
+func mustLoad() *registry {
+	r, err := load(templateFS)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func load(fsys fs.FS) (*registry, error) {
+	r := &registry{
+		html:    map[templateKey]*htmltemplate.Template{},
+		text:    map[templateKey]*texttemplate.Template{},
+		locales: map[string][]string{},
+	}
+
+	for _, locale := range Locales {
+		files, err := fs.Glob(fsys, path.Join("templates", locale, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+		common := path.Join("templates", locale, "_common.tmpl")
+		for _, file := range files {
+			name := strings.TrimSuffix(path.Base(file), ".tmpl")
+			if strings.HasPrefix(name, "_") {
+				continue
+			}
+			key := templateKey{name: name, locale: locale}
+
+			htmlTemplate, err := htmltemplate.New(name).Option("missingkey=error").Funcs(funcs).ParseFS(fsys, "templates/layout.html.tmpl", common, file)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s email template: %w", file, err)
+			}
+			textTemplate, err := texttemplate.New(name).Option("missingkey=error").Funcs(funcs).ParseFS(fsys, "templates/layout.text.tmpl", common, file)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing %s email template: %w", file, err)
+			}
+
+			r.html[key] = htmlTemplate
+			r.text[key] = textTemplate
+			r.locales[name] = append(r.locales[name], locale)
+		}
+	}
+
+	for name, locales := range r.locales {
+		if !containsLocale(locales, DefaultLocale) {
+			return nil, fmt.Errorf("email template %s has no %s version", name, DefaultLocale)
+		}
+	}
+	return r, nil
+}
+
+func containsLocale(locales []string, locale string) bool {
+	for _, l := range locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeLocale maps a language tag like "es-MX" or "zh_Hant" onto a
+// supported locale, falling back to English.
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if containsLocale(Locales, locale) {
+		return locale
+	}
+	return DefaultLocale
+}
+
+func SupportedLocale(locale string) bool {
+	return containsLocale(Locales, strings.ToLower(strings.TrimSpace(locale)))
+}
+
+func Exists(name string) bool {
+	_, ok := templates.locales[name]
+	return ok
+}
+
+// Templates lists every email with the locales it is written in.
+func Templates() []TemplateInfo {
+	infos := make([]TemplateInfo, 0, len(templates.locales))
+	for name, locales := range templates.locales {
+		infos = append(infos, TemplateInfo{Name: name, Locales: append([]string(nil), locales...)})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// Render renders email name in locale, or in English when that locale has
+// no version of it.
+func Render(name string, locale string, data Data) (*Rendered, error) {
+	locale = NormalizeLocale(locale)
+	key := templateKey{name: name, locale: locale}
+	if _, ok := templates.html[key]; !ok {
+		key.locale = DefaultLocale
+	}
+	htmlTemplate, ok := templates.html[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %s", name)
+	}
+	textTemplate := templates.text[key]
+
+	values := Data{}
+	for k, v := range data {
+		values[k] = v
+	}
+	values["Locale"] = key.locale
+
+	var subject bytes.Buffer
+	if err := textTemplate.ExecuteTemplate(&subject, "subject", values); err != nil {
+		return nil, fmt.Errorf("error rendering %s email subject: %w", name, err)
+	}
+	var html bytes.Buffer
+	if err := htmlTemplate.ExecuteTemplate(&html, "layout", values); err != nil {
+		return nil, fmt.Errorf("error rendering %s email html: %w", name, err)
+	}
+	var text bytes.Buffer
+	if err := textTemplate.ExecuteTemplate(&text, "layout", values); err != nil {
+		return nil, fmt.Errorf("error rendering %s email text: %w", name, err)
+	}
+
+	lines := strings.Split(text.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return &Rendered{
+		Locale:  key.locale,
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		HTML:    html.String(),
+		Text:    blankLines.ReplaceAllString(strings.TrimSpace(strings.Join(lines, "\n")), "\n\n") + "\n",
+	}, nil
+}
+
+// SampleData returns the example data admins preview email name with.
+func SampleData(name string) (Data, error) {
+	raw, err := sampleFS.ReadFile(path.Join("samples", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("no sample data for email template %s", name)
+	}
+	data := Data{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("error decoding sample data for email template %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func Preview(name string, locale string) (*Rendered, error) {
+	if !Exists(name) {
+		return nil, fmt.Errorf("unknown email template %s", name)
+	}
+	data, err := SampleData(name)
+	if err != nil {
+		return nil, err
+	}
+	return Render(name, locale, data)
+}
@@ -0,0 +1,93 @@
+package emails
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEveryTemplateRendersItsSample(t *testing.T) {
+	infos := Templates()
+	if len(infos) == 0 {
+		t.Fatalf("expected embedded templates")
+	}
+	for _, info := range infos {
+		for _, locale := range Locales {
+			rendered, err := Preview(info.Name, locale)
+			if err != nil {
+				t.Fatalf("Preview(%s, %s): %s", info.Name, locale, err)
+			}
+			if rendered.Subject == "" || rendered.HTML == "" || rendered.Text == "" {
+				t.Fatalf("Preview(%s, %s) left a part empty: %+v", info.Name, locale, rendered)
+			}
+			if strings.Contains(rendered.HTML, "<no value>") || strings.Contains(rendered.Text, "<no value>") {
+				t.Fatalf("Preview(%s, %s) rendered a missing value", info.Name, locale)
+			}
+		}
+	}
+}
+
+func TestEveryLocaleHasEveryTemplate(t *testing.T) {
+	for _, info := range Templates() {
+		for _, locale := range Locales {
+			if !containsLocale(info.Locales, locale) {
+				t.Errorf("email template %s has no %s version", info.Name, locale)
+			}
+		}
+	}
+}
+
+func TestRenderFallsBackToEnglish(t *testing.T) {
+	rendered, err := Render("merchant_pin_help", "fr", Data{"UserId": "user-1", "ContactEmail": "a@example.com"})
+	if err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+	if rendered.Locale != DefaultLocale {
+		t.Fatalf("expected fallback to %s, got %s", DefaultLocale, rendered.Locale)
+	}
+
+	rendered, err = Render("location_approved", "es-MX", Data{"LocationName": "Tienda"})
+	if err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+	if rendered.Locale != "es" || rendered.Subject != "Ubicación aprobada" {
+		t.Fatalf("expected the Spanish version, got %s %q", rendered.Locale, rendered.Subject)
+	}
+}
+
+func TestRenderEscapesHTMLOnly(t *testing.T) {
+	rendered, err := Render("location_approved", "en", Data{"LocationName": `<b>Joe's & Co</b>`})
+	if err != nil {
+		t.Fatalf("Render: %s", err)
+	}
+	if strings.Contains(rendered.HTML, "<b>Joe") {
+		t.Fatalf("expected location name to be escaped in html")
+	}
+	if !strings.Contains(rendered.Text, `<b>Joe's & Co</b>`) {
+		t.Fatalf("expected location name verbatim in text, got %q", rendered.Text)
+	}
+}
+
+func TestRenderRequiresEveryField(t *testing.T) {
+	if _, err := Render("w9_approved", "en", Data{}); err == nil {
+		t.Fatalf("expected a missing field to fail rendering")
+	}
+	if _, err := Render("no_such_email", "en", Data{}); err == nil {
+		t.Fatalf("expected an unknown template to fail")
+	}
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	cases := map[string]string{
+		"":        "en",
+		"es":      "es",
+		"es-MX":   "es",
+		"zh_Hant": "zh",
+		"ZH-cn":   "zh",
+		"fr":      "en",
+	}
+	for in, want := range cases {
+		if got := NormalizeLocale(in); got != want {
+			t.Fatalf("NormalizeLocale(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
@@ -60,14 +60,14 @@ func (p *PonderService) GetAdminAnalyticsDashboard(w http.ResponseWriter, r *htt
 	chainID := p.requestChainID(r)
 
 	if err := p.SyncAnalyticsWalletRoleHistory(r.Context(), chainID); err != nil {
-		p.logger.Logf("error syncing analytics wallet role history: %s", err)
+		p.logger.Errorf(r.Context(), "error syncing analytics wallet role history: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	roleHistory, err := p.appDB.GetAnalyticsWalletRoleHistory(r.Context())
 	if err != nil {
-		p.logger.Logf("error loading analytics wallet role history: %s", err)
+		p.logger.Errorf(r.Context(), "error loading analytics wallet role history: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -75,14 +75,14 @@ func (p *PonderService) GetAdminAnalyticsDashboard(w http.ResponseWriter, r *htt
 
 	transfers, err := p.db.GetAnalyticsTransfersSince(r.Context(), chainID, 0)
 	if err != nil {
-		p.logger.Logf("error loading analytics transfers: %s", err)
+		p.logger.Errorf(r.Context(), "error loading analytics transfers: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	events, err := p.botDB.GetAnalyticsVolunteerEvents(r.Context())
 	if err != nil {
-		p.logger.Logf("error loading analytics bot events: %s", err)
+		p.logger.Errorf(r.Context(), "error loading analytics bot events: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -90,7 +90,7 @@ func (p *PonderService) GetAdminAnalyticsDashboard(w http.ResponseWriter, r *htt
 	periods := analyticsReportingPeriods(now)
 	activity, err := p.appDB.GetAnalyticsUserActivity(r.Context(), time.Unix(0, 0).UTC())
 	if err != nil {
-		p.logger.Logf("error loading analytics user activity: %s", err)
+		p.logger.Errorf(r.Context(), "error loading analytics user activity: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+)
+
+const adminAuditExportLimit = 50000
+
+var adminAuditCSVHeader = []string{
+	"id",
+	"created_at",
+	"actor_user_id",
+	"api_key_id",
+	"action",
+	"target_type",
+	"target_id",
+	"before",
+	"after",
+	"request_id",
+}
+
+// recordAdminAudit appends an audit entry for a mutation made by the caller
+// of r. The mutation has already been committed by the time this runs, so a
+// failure to write the entry is logged rather than returned.
+func recordAdminAudit(r *http.Request, appDb *db.AppDB, action, targetType, targetId string, before, after any) {
+	if appDb == nil {
+		return
+	}
+
+	entry := &structs.AdminAuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+	}
+	if actor := utils.GetDid(r); actor != nil {
+		entry.ActorUserId = *actor
+	}
+	if apiKeyId := utils.GetAPIKeyId(r); apiKeyId != nil {
+		entry.ApiKeyId = *apiKeyId
+	}
+
+	var err error
+	if entry.Before, err = marshalAdminAuditState(before); err == nil {
+		entry.After, err = marshalAdminAuditState(after)
+	}
+	if err == nil {
+		err = appDb.InsertAdminAuditEntry(r.Context(), entry)
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error recording admin audit entry", "action", action, "target_type", targetType, "target_id", targetId, "actor", entry.ActorUserId, "error", err)
+	}
+}
+
+func marshalAdminAuditState(state any) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	if string(raw) == "null" {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// workflowAuditState reduces a workflow or proposal to its status; the full
+// objects are large and their history is kept in their own tables.
+func workflowAuditState(status string) map[string]string {
+	return map[string]string{"status": status}
+}
+
+// issuerScopesAuditState snapshots the issuer role and scopes for userId in
+// the shape SetIssuerScopes returns, or nil when they cannot be loaded.
+func (a *AppService) issuerScopesAuditState(ctx context.Context, userId string) *structs.IssuerWithScopes {
+	user, err := a.db.GetUserById(ctx, userId)
+	if err != nil {
+		return nil
+	}
+	scopes, err := a.db.GetIssuerScopeCredentials(ctx, userId)
+	if err != nil {
+		return nil
+	}
+	return &structs.IssuerWithScopes{
+		UserId:             userId,
+		IsIssuer:           user.IsIssuer,
+		AllowedCredentials: scopes,
+	}
+}
+
+func (a *AppService) recordAdminAudit(r *http.Request, action, targetType, targetId string, before, after any) {
+	recordAdminAudit(r, a.db, action, targetType, targetId, before, after)
+}
+
+func (s *BotService) recordAdminAudit(r *http.Request, action, targetType, targetId string, before, after any) {
+	recordAdminAudit(r, s.appDb, action, targetType, targetId, before, after)
+}
+
+func parseAdminAuditFilter(params url.Values) (structs.AdminAuditFilter, error) {
+	filter := structs.AdminAuditFilter{
+		ActorUserId: strings.TrimSpace(params.Get("actor")),
+		Action:      strings.TrimSpace(params.Get("action")),
+		TargetType:  strings.TrimSpace(params.Get("target_type")),
+		TargetId:    strings.TrimSpace(params.Get("target_id")),
+	}
+
+	for _, bound := range []struct {
+		name  string
+		value *int64
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		raw := strings.TrimSpace(params.Get(bound.name))
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value < 0 {
+			return filter, fmt.Errorf("%s must be a unix timestamp in seconds", bound.name)
+		}
+		*bound.value = value
+	}
+	if filter.From > 0 && filter.To > 0 && filter.From > filter.To {
+		return filter, fmt.Errorf("from must not be after to")
+	}
+
+	return filter, nil
+}
+
+func adminAuditCSVRow(entry *structs.AdminAuditEntry) []string {
+	return []string{
+		strconv.FormatInt(entry.Id, 10),
+		time.Unix(entry.CreatedAt, 0).UTC().Format(time.RFC3339),
+		entry.ActorUserId,
+		entry.ApiKeyId,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetId,
+		string(entry.Before),
+		string(entry.After),
+		entry.RequestId,
+	}
+}
+
+func (a *AppService) GetAdminAuditLog(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	filter, err := parseAdminAuditFilter(params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	page, count := parsePageAndCount(params, 50, 200)
+
+	response, err := a.db.GetAdminAuditEntries(r.Context(), filter, page, count)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error loading admin audit log: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (a *AppService) ExportAdminAuditLog(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAdminAuditFilter(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	entries, err := a.db.ExportAdminAuditEntries(r.Context(), filter, adminAuditExportLimit)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error exporting admin audit log: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(adminAuditCSVHeader); err != nil {
+		a.logger.Errorf(r.Context(), "error writing admin audit csv header: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	for _, entry := range entries {
+		if err := writer.Write(adminAuditCSVRow(entry)); err != nil {
+			a.logger.Errorf(r.Context(), "error writing admin audit csv row: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		a.logger.Errorf(r.Context(), "error flushing admin audit csv: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	filename := "sfluv-admin-audit-" + time.Now().UTC().Format("2006-01-02") + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestParseAdminAuditFilter(t *testing.T) {
+	filter, err := parseAdminAuditFilter(url.Values{
+		"actor":       {" did:privy:admin "},
+		"action":      {structs.AdminAuditW9Approve},
+		"target_type": {"w9_submission"},
+		"target_id":   {"42"},
+		"from":        {"1700000000"},
+		"to":          {"1700003600"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := structs.AdminAuditFilter{
+		ActorUserId: "did:privy:admin",
+		Action:      structs.AdminAuditW9Approve,
+		TargetType:  "w9_submission",
+		TargetId:    "42",
+		From:        1700000000,
+		To:          1700003600,
+	}
+	if filter != want {
+		t.Fatalf("expected %+v, got %+v", want, filter)
+	}
+
+	for _, params := range []url.Values{
+		{"from": {"yesterday"}},
+		{"to": {"-1"}},
+		{"from": {"20"}, "to": {"10"}},
+	} {
+		if _, err := parseAdminAuditFilter(params); err == nil {
+			t.Fatalf("expected %v to be rejected", params)
+		}
+	}
+}
+
+func TestMarshalAdminAuditStateDropsNulls(t *testing.T) {
+	var proposer *structs.Proposer
+	for _, state := range []any{nil, proposer} {
+		raw, err := marshalAdminAuditState(state)
+		if err != nil || raw != nil {
+			t.Fatalf("expected no state for %#v, got %q err %v", state, raw, err)
+		}
+	}
+
+	raw, err := marshalAdminAuditState(workflowAuditState("approved"))
+	if err != nil || string(raw) != `{"status":"approved"}` {
+		t.Fatalf("unexpected state %q err %v", raw, err)
+	}
+}
+
+func TestAdminAuditCSVRow(t *testing.T) {
+	row := adminAuditCSVRow(&structs.AdminAuditEntry{
+		Id:          7,
+		ActorUserId: "did:privy:admin",
+		ApiKeyId:    structs.LegacyAdminKeyId,
+		Action:      structs.AdminAuditFaucetDrain,
+		TargetType:  "faucet",
+		TargetId:    "0xabc",
+		After:       []byte(`{"destination":"0xabc"}`),
+		CreatedAt:   1700000000,
+	})
+	if len(row) != len(adminAuditCSVHeader) {
+		t.Fatalf("expected %d columns, got %d", len(adminAuditCSVHeader), len(row))
+	}
+	if row[1] != "2023-11-14T22:13:20Z" || row[7] != "" || row[8] != `{"destination":"0xabc"}` {
+		t.Fatalf("unexpected row %q", row)
+	}
+}
@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/emails"
+	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+const eventTemplateCheckInterval = time.Minute
+
+// SetEventCreator gives the scheduler the path used to create affiliate
+// events, so scheduled events reserve balance exactly like manual ones.
+func (s *AffiliateScheduler) SetEventCreator(create func(context.Context, *structs.Event) (string, error)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.createEvent = create
+	s.mu.Unlock()
+}
+
+// SetNotifier routes the scheduler's affiliate emails through the app's
+// notification dispatcher.
+func (s *AffiliateScheduler) SetNotifier(notify Notifier) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.notify = notify
+	s.mu.Unlock()
+}
+
+// nextTemplateStart steps a recurring start time forward from the due
+// occurrence from until it is after now, skipping occurrences that were
+// missed while the server was down. Monthly occurrences are counted from
+// anchor so a template started on the 31st returns to the 31st after a
+// shorter month.
+func nextTemplateStart(recurrence string, anchor time.Time, from time.Time, now time.Time) time.Time {
+	switch recurrence {
+	case structs.EventRecurrenceWeekly:
+		next := from
+		for !next.After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	case structs.EventRecurrenceMonthly:
+		months := (from.Year()-anchor.Year())*12 + int(from.Month()-anchor.Month())
+		next := monthlyTemplateStart(anchor, months)
+		for !next.After(now) || !next.After(from) {
+			months++
+			next = monthlyTemplateStart(anchor, months)
+		}
+		return next
+	default:
+		return time.Time{}
+	}
+}
+
+// monthlyTemplateStart is anchor moved forward by months, on the anchor's
+// day or the last day of that month if it is shorter.
+func monthlyTemplateStart(anchor time.Time, months int) time.Time {
+	year, month := anchor.Year(), anchor.Month()+time.Month(months)
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, anchor.Location()).Day()
+	day := anchor.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month, day, anchor.Hour(), anchor.Minute(), anchor.Second(), 0, anchor.Location())
+}
+
+func (s *AffiliateScheduler) startTemplateLoop(ctx context.Context) {
+	ticker := time.NewTicker(eventTemplateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runDueEventTemplates(logger.WithRequestID(ctx, "event-templates-"+logger.NewRequestID()))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *AffiliateScheduler) runDueEventTemplates(ctx context.Context) {
+	if s == nil || s.botDb == nil {
+		return
+	}
+	s.mu.Lock()
+	create := s.createEvent
+	s.mu.Unlock()
+	if create == nil {
+		return
+	}
+
+	now := time.Now()
+	templates, err := s.botDb.GetDueEventTemplates(ctx, now.Unix())
+	if err != nil {
+		s.logger.Errorf(ctx, "error loading due event templates: %s", err)
+		return
+	}
+
+	for _, template := range templates {
+		if ctx.Err() != nil {
+			return
+		}
+		s.runEventTemplate(ctx, create, template, now)
+	}
+}
+
+// runEventTemplate creates the event for a template's due occurrence and
+// schedules the one after it. An occurrence whose whole window was missed is
+// skipped rather than created late.
+func (s *AffiliateScheduler) runEventTemplate(ctx context.Context, create func(context.Context, *structs.Event) (string, error), template *structs.EventTemplate, now time.Time) {
+	dueAt := time.Unix(int64(template.NextStartAt), 0).In(s.loc)
+	anchor := dueAt
+	if template.AnchorStartAt > 0 {
+		anchor = time.Unix(int64(template.AnchorStartAt), 0).In(s.loc)
+	}
+	next := nextTemplateStart(template.Recurrence, anchor, dueAt, now)
+	nextStartAt := uint64(0)
+	if !next.IsZero() {
+		nextStartAt = uint64(next.Unix())
+	}
+
+	claimed, err := s.botDb.ClaimEventTemplateRun(ctx, template.Id, template.NextStartAt, nextStartAt)
+	if err != nil {
+		s.logger.Errorf(ctx, "error claiming event template %s: %s", template.Id, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	expiration := template.NextStartAt + template.DurationSeconds
+	runErr := ""
+	eventId := ""
+	if expiration <= uint64(now.Unix()) {
+		runErr = "missed scheduled start"
+	} else {
+		eventId, err = create(ctx, &structs.Event{
+			Title:           template.Title,
+			Description:     template.Description,
+			Codes:           template.Codes,
+			Amount:          template.Amount,
+			StartAt:         uint64(now.Unix()),
+			Expiration:      expiration,
+			Owner:           template.Owner,
+			MaxPerAddress:   template.MaxPerAddress,
+			MaxPerUser:      template.MaxPerUser,
+			CooldownSeconds: template.CooldownSeconds,
+			TemplateId:      template.Id,
+		})
+		if err != nil {
+			runErr = err.Error()
+			s.logger.Errorf(ctx, "error creating scheduled event for template %s: %s", template.Id, err)
+			if errors.Is(err, errInsufficientAffiliateBalance) {
+				s.notifyTemplateBalanceShort(ctx, template, next)
+			}
+		}
+	}
+
+	if err := s.botDb.RecordEventTemplateRun(ctx, template.Id, eventId, runErr, uint64(now.Unix())); err != nil {
+		s.logger.Errorf(ctx, "error recording run for event template %s: %s", template.Id, err)
+	}
+}
+
+func (s *AffiliateScheduler) notifyTemplateBalanceShort(ctx context.Context, template *structs.EventTemplate, next time.Time) {
+	if s.appDb == nil {
+		return
+	}
+	user, err := s.appDb.GetUserById(ctx, template.Owner)
+	if err != nil || user == nil || user.Email == nil || strings.TrimSpace(*user.Email) == "" {
+		s.logger.Warnf(ctx, "no contact email for affiliate %s to report short balance for template %s", template.Owner, template.Id)
+		return
+	}
+
+	available := ""
+	if affiliate, err := s.appDb.GetAffiliateByUser(ctx, template.Owner); err == nil && affiliate != nil {
+		available = fmt.Sprintf("%d", affiliate.WeeklyBalance+affiliate.OneTimeBalance)
+	}
+	nextRun := ""
+	if !next.IsZero() {
+		nextRun = next.In(s.loc).Format("Mon Jan 2, 3:04 PM MST")
+	}
+
+	notification := &Notification{
+		Category: structs.NotificationCategoryAffiliate,
+		UserId:   template.Owner,
+		Email: &NotificationEmail{
+			To:       strings.TrimSpace(*user.Email),
+			ToName:   "Affiliate",
+			Template: "affiliate_event_skipped",
+			Data: emails.Data{
+				"EventTitle":       template.Title,
+				"RequiredBalance":  template.Amount * uint64(template.Codes),
+				"AvailableBalance": available,
+				"NextAttempt":      nextRun,
+			},
+			FromName: "SFLuv Affiliates",
+		},
+	}
+	s.mu.Lock()
+	notify := s.notify
+	s.mu.Unlock()
+	if notify != nil {
+		err = notify(ctx, notification)
+	} else {
+		err = deliverNotificationEmail(notification.Email)
+	}
+	if err != nil {
+		s.logger.Errorf(ctx, "error sending short balance email for template %s: %s", template.Id, err)
+	}
+}
+
+func validateEventTemplate(template *structs.EventTemplate) error {
+	if template == nil {
+		return fmt.Errorf("invalid template payload")
+	}
+	if template.Codes == 0 || template.Amount == 0 {
+		return fmt.Errorf("codes and amount are required")
+	}
+	if template.DurationSeconds == 0 {
+		return fmt.Errorf("duration_seconds is required")
+	}
+	template.Recurrence = strings.ToLower(strings.TrimSpace(template.Recurrence))
+	switch template.Recurrence {
+	case "":
+		template.Recurrence = structs.EventRecurrenceNone
+	case structs.EventRecurrenceNone, structs.EventRecurrenceWeekly, structs.EventRecurrenceMonthly:
+	default:
+		return fmt.Errorf("recurrence must be none, weekly or monthly")
+	}
+	if template.Recurrence == structs.EventRecurrenceNone {
+		template.NextStartAt = 0
+		return nil
+	}
+	if template.NextStartAt == 0 {
+		return fmt.Errorf("next_start_at is required for recurring templates")
+	}
+	if int64(template.NextStartAt) < time.Now().Unix()-60 {
+		return fmt.Errorf("next_start_at must not be in the past")
+	}
+	return nil
+}
+
+func (s *BotService) AffiliateNewEventTemplate(w http.ResponseWriter, r *http.Request) {
+	body := EnsureBody(w, r)
+	if body == nil {
+		return
+	}
+
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var template *structs.EventTemplate
+	if !EnsureUnmarshal(w, &template, body) {
+		return
+	}
+	if err := validateEventTemplate(template); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	template.Owner = *userDid
+
+	created, err := s.db.NewEventTemplate(r.Context(), template)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error creating event template", "user_id", *userDid, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(created)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(bytes)
+}
+
+func (s *BotService) AffiliateGetEventTemplates(w http.ResponseWriter, r *http.Request) {
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	templates, err := s.db.GetEventTemplatesByOwner(r.Context(), *userDid)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error getting event templates", "user_id", *userDid, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(templates)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
+
+func (s *BotService) AffiliateDeleteEventTemplate(w http.ResponseWriter, r *http.Request) {
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	id := r.PathValue("template_id")
+	if err := s.db.DeactivateEventTemplate(r.Context(), id, *userDid); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		slog.ErrorContext(r.Context(), "error deleting event template", "template_id", id, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Create one event from a template now, or at start_at. Responds with the
+// event id like AffiliateNewEvent.
+func (s *BotService) AffiliateNewEventFromTemplate(w http.ResponseWriter, r *http.Request) {
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var request structs.EventFromTemplateRequest
+	if r.ContentLength > 0 {
+		body := EnsureBody(w, r)
+		if body == nil {
+			return
+		}
+		var parsed *structs.EventFromTemplateRequest
+		if !EnsureUnmarshal(w, &parsed, body) {
+			return
+		}
+		if parsed != nil {
+			request = *parsed
+		}
+	}
+
+	id := r.PathValue("template_id")
+	template, err := s.db.GetEventTemplate(r.Context(), id)
+	if err != nil || !template.Active || template.Owner != *userDid {
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			slog.ErrorContext(r.Context(), "error loading event template", "template_id", id, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	startAt := request.StartAt
+	if startAt == 0 {
+		startAt = uint64(time.Now().Unix())
+	}
+	event := &structs.Event{
+		Title:           template.Title,
+		Description:     template.Description,
+		Codes:           template.Codes,
+		Amount:          template.Amount,
+		StartAt:         startAt,
+		Expiration:      startAt + template.DurationSeconds,
+		Owner:           *userDid,
+		MaxPerAddress:   template.MaxPerAddress,
+		MaxPerUser:      template.MaxPerUser,
+		CooldownSeconds: template.CooldownSeconds,
+		TemplateId:      template.Id,
+	}
+	if err := validateEventTiming(event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("start_at must not be in the past"))
+		return
+	}
+
+	eventId, err := s.CreateAffiliateEvent(r.Context(), event)
+	if err != nil {
+		switch {
+		case errors.Is(err, errInsufficientAffiliateBalance):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("insufficient affiliate balance"))
+		case errors.Is(err, errInsufficientFaucetBalance):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Not enough balance in faucet. Please try again later, or contact us at admin@sfluv.org."))
+		default:
+			slog.ErrorContext(r.Context(), "error creating event from template", "template_id", id, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(eventId))
+}
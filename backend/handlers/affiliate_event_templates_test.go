@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestNextTemplateStart(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+	// Saturday 9am, the week before daylight saving ends.
+	due := time.Date(2026, time.October, 31, 9, 0, 0, 0, loc)
+
+	next := nextTemplateStart(structs.EventRecurrenceWeekly, due, due, due)
+	if want := time.Date(2026, time.November, 7, 9, 0, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("weekly next = %s; want %s", next, want)
+	}
+
+	// Missed occurrences are skipped rather than replayed.
+	next = nextTemplateStart(structs.EventRecurrenceWeekly, due, due, due.AddDate(0, 0, 15))
+	if want := time.Date(2026, time.November, 21, 9, 0, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("weekly catch-up = %s; want %s", next, want)
+	}
+
+	monthlyDue := time.Date(2026, time.October, 10, 9, 0, 0, 0, loc)
+	next = nextTemplateStart(structs.EventRecurrenceMonthly, monthlyDue, monthlyDue, monthlyDue)
+	if want := time.Date(2026, time.November, 10, 9, 0, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("monthly next = %s; want %s", next, want)
+	}
+
+	// A template anchored on the 31st moves to the end of shorter months and
+	// returns to the 31st rather than drifting to the 28th.
+	anchor := time.Date(2027, time.January, 31, 9, 0, 0, 0, loc)
+	steps := []time.Time{
+		time.Date(2027, time.February, 28, 9, 0, 0, 0, loc),
+		time.Date(2027, time.March, 31, 9, 0, 0, 0, loc),
+		time.Date(2027, time.April, 30, 9, 0, 0, 0, loc),
+		time.Date(2027, time.May, 31, 9, 0, 0, 0, loc),
+	}
+	from := anchor
+	for _, want := range steps {
+		next = nextTemplateStart(structs.EventRecurrenceMonthly, anchor, from, from)
+		if !next.Equal(want) {
+			t.Fatalf("monthly after %s = %s; want %s", from, next, want)
+		}
+		from = next
+	}
+
+	// Catching up after downtime still lands on the anchor day.
+	next = nextTemplateStart(structs.EventRecurrenceMonthly, anchor, anchor, time.Date(2027, time.March, 5, 0, 0, 0, 0, loc))
+	if want := time.Date(2027, time.March, 31, 9, 0, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("monthly catch-up = %s; want %s", next, want)
+	}
+
+	if next := nextTemplateStart(structs.EventRecurrenceNone, due, due, due); !next.IsZero() {
+		t.Fatalf("non-recurring next = %s; want zero", next)
+	}
+}
+
+func TestValidateEventTemplate(t *testing.T) {
+	future := uint64(time.Now().Add(time.Hour).Unix())
+
+	cases := []struct {
+		name     string
+		template structs.EventTemplate
+		wantErr  bool
+	}{
+		{name: "one-off", template: structs.EventTemplate{Codes: 10, Amount: 5, DurationSeconds: 3600}},
+		{name: "weekly", template: structs.EventTemplate{Codes: 10, Amount: 5, DurationSeconds: 3600, Recurrence: "Weekly", NextStartAt: future}},
+		{name: "weekly without start", template: structs.EventTemplate{Codes: 10, Amount: 5, DurationSeconds: 3600, Recurrence: "weekly"}, wantErr: true},
+		{name: "unknown recurrence", template: structs.EventTemplate{Codes: 10, Amount: 5, DurationSeconds: 3600, Recurrence: "daily", NextStartAt: future}, wantErr: true},
+		{name: "missing duration", template: structs.EventTemplate{Codes: 10, Amount: 5}, wantErr: true},
+		{name: "missing codes", template: structs.EventTemplate{Amount: 5, DurationSeconds: 3600}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		template := tc.template
+		err := validateEventTemplate(&template)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("%s: err = %v; wantErr %t", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/lifecycle"
 	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/structs"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -17,8 +19,11 @@ type AffiliateScheduler struct {
 	logger *logger.LogCloser
 	loc    *time.Location
 
-	mu     sync.Mutex
-	timers map[string]*time.Timer
+	lifecycle   *lifecycle.Manager
+	mu          sync.Mutex
+	timers      map[string]*time.Timer
+	createEvent func(context.Context, *structs.Event) (string, error)
+	notify      Notifier
 }
 
 func NewAffiliateScheduler(appDb *db.AppDB, botDb *db.BotDB, logger *logger.LogCloser) *AffiliateScheduler {
@@ -36,19 +41,51 @@ func NewAffiliateScheduler(appDb *db.AppDB, botDb *db.BotDB, logger *logger.LogC
 	}
 }
 
-func (s *AffiliateScheduler) Start(ctx context.Context) {
+func (s *AffiliateScheduler) Start(lc *lifecycle.Manager) {
 	if s == nil {
 		return
 	}
+	s.mu.Lock()
+	s.lifecycle = lc
+	s.mu.Unlock()
 
-	go func() {
-		if err := s.RecomputeWeeklyBalances(context.Background()); err != nil {
-			s.logf("error recomputing affiliate weekly balances: %s", err)
+	lc.Go("affiliate weekly recompute", func(ctx context.Context) {
+		ctx = logger.WithRequestID(ctx, "affiliate-weekly-"+logger.NewRequestID())
+		if err := s.RecomputeWeeklyBalances(ctx); err != nil {
+			s.logger.Errorf(ctx, "error recomputing affiliate weekly balances: %s", err)
 		}
-	}()
+	})
+
+	lc.Go("affiliate event expirations", s.scheduleExistingEventExpirations)
+	lc.Go("affiliate weekly loop", s.startWeeklyLoop)
+	lc.Go("affiliate template loop", s.startTemplateLoop)
+	lc.Go("affiliate expiration timers", func(ctx context.Context) {
+		<-ctx.Done()
+		s.stopTimers()
+	})
+}
+
+// stopTimers cancels pending event expirations on shutdown. They are
+// rescheduled from the active events on the next start.
+func (s *AffiliateScheduler) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for eventId, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, eventId)
+	}
+}
 
-	go s.scheduleExistingEventExpirations(ctx)
-	go s.startWeeklyLoop(ctx)
+// runEventExpiration refunds an expired event on a tracked goroutine, or
+// skips it once shutdown has begun.
+func (s *AffiliateScheduler) runEventExpiration(eventId string, owner string) {
+	s.mu.Lock()
+	lc := s.lifecycle
+	s.mu.Unlock()
+
+	lc.Go("affiliate event expiration", func(ctx context.Context) {
+		s.handleEventExpiration(eventId, owner)
+	})
 }
 
 func (s *AffiliateScheduler) RecomputeWeeklyBalances(ctx context.Context) error {
@@ -68,7 +105,7 @@ func (s *AffiliateScheduler) RecomputeWeeklyBalances(ctx context.Context) error
 
 		reserved, err := s.botDb.AllocatedBalanceByOwner(ctx, cfg.UserId)
 		if err != nil {
-			s.logf("error getting reserved balance for affiliate %s: %s", cfg.UserId, err)
+			s.logger.Errorf(ctx, "error getting reserved balance for affiliate %s: %s", cfg.UserId, err)
 			continue
 		}
 
@@ -79,7 +116,7 @@ func (s *AffiliateScheduler) RecomputeWeeklyBalances(ctx context.Context) error
 
 		err = s.appDb.SetAffiliateWeeklyBalance(ctx, cfg.UserId, weekly)
 		if err != nil {
-			s.logf("error setting weekly balance for affiliate %s: %s", cfg.UserId, err)
+			s.logger.Errorf(ctx, "error setting weekly balance for affiliate %s: %s", cfg.UserId, err)
 			continue
 		}
 	}
@@ -98,7 +135,7 @@ func (s *AffiliateScheduler) ScheduleEventExpiration(eventId string, owner strin
 	expiresAt := time.Unix(int64(expiration), 0)
 	delay := time.Until(expiresAt)
 	if delay <= 0 {
-		go s.handleEventExpiration(eventId, owner)
+		s.runEventExpiration(eventId, owner)
 		return
 	}
 
@@ -107,7 +144,7 @@ func (s *AffiliateScheduler) ScheduleEventExpiration(eventId string, owner strin
 		existing.Stop()
 	}
 	s.timers[eventId] = time.AfterFunc(delay, func() {
-		s.handleEventExpiration(eventId, owner)
+		s.runEventExpiration(eventId, owner)
 	})
 	s.mu.Unlock()
 }
@@ -126,8 +163,9 @@ func (s *AffiliateScheduler) startWeeklyLoop(ctx context.Context) {
 			timer.Stop()
 			return
 		case <-timer.C:
-			if err := s.RecomputeWeeklyBalances(context.Background()); err != nil {
-				s.logf("error recomputing affiliate weekly balances: %s", err)
+			runCtx := logger.WithRequestID(context.Background(), "affiliate-weekly-"+logger.NewRequestID())
+			if err := s.RecomputeWeeklyBalances(runCtx); err != nil {
+				s.logger.Errorf(runCtx, "error recomputing affiliate weekly balances: %s", err)
 			}
 		}
 	}
@@ -140,7 +178,7 @@ func (s *AffiliateScheduler) scheduleExistingEventExpirations(ctx context.Contex
 
 	events, err := s.botDb.GetActiveEvents(ctx)
 	if err != nil {
-		s.logf("error loading active events for affiliate scheduler: %s", err)
+		s.logger.Errorf(ctx, "error loading active events for affiliate scheduler: %s", err)
 		return
 	}
 
@@ -160,13 +198,13 @@ func (s *AffiliateScheduler) handleEventExpiration(eventId string, owner string)
 	delete(s.timers, eventId)
 	s.mu.Unlock()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(logger.WithRequestID(context.Background(), "event-expiration-"+eventId), 10*time.Second)
 	defer cancel()
 
 	value, err := s.botDb.EventUnredeemedValue(ctx, eventId)
 	if err != nil {
 		if err != pgx.ErrNoRows {
-			s.logf("error getting unredeemed value for event %s: %s", eventId, err)
+			s.logger.Errorf(ctx, "error getting unredeemed value for event %s: %s", eventId, err)
 		}
 		return
 	}
@@ -175,7 +213,7 @@ func (s *AffiliateScheduler) handleEventExpiration(eventId string, owner string)
 	}
 
 	if err := s.appDb.AddAffiliateWeeklyBalance(ctx, owner, value); err != nil {
-		s.logf("error refunding affiliate balance for event %s: %s", eventId, err)
+		s.logger.Errorf(ctx, "error refunding affiliate balance for event %s: %s", eventId, err)
 	}
 }
 
@@ -189,11 +227,3 @@ func (s *AffiliateScheduler) nextMondayMidnight(now time.Time) time.Time {
 	}
 	return startOfDay.AddDate(0, 0, daysUntil)
 }
-
-func (s *AffiliateScheduler) logf(message string, args ...any) {
-	if s != nil && s.logger != nil {
-		s.logger.Logf(message, args...)
-		return
-	}
-	fmt.Printf(message+"\n", args...)
-}
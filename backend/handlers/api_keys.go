@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	apiKeyPrefix          = "sfluv"
+	apiKeyDefaultLifetime = 90 * 24 * time.Hour
+	apiKeyMaxLifetime     = 366 * 24 * time.Hour
+	apiKeyPrefixBytes     = 6
+	apiKeySecretBytes     = 32
+	apiKeyNameMaxLength   = 120
+)
+
+var errInvalidAPIKey = errors.New("invalid api key")
+
+// generateAPIKey returns a new key of the form sfluv_<prefix>_<secret>, its
+// lookup prefix and the hash stored in its place.
+func generateAPIKey() (key string, prefix string, hash string, err error) {
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+	secretBytes := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	key = apiKeyPrefix + "_" + prefix + "_" + hex.EncodeToString(secretBytes)
+	return key, prefix, hashAPIKey(key), nil
+}
+
+// hashAPIKey is a plain SHA-256: keys carry 256 bits of randomness, so a
+// slow password hash would add latency to every request without adding
+// resistance to guessing.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseAPIKeyPrefix(key string) (string, bool) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || len(parts[1]) != apiKeyPrefixBytes*2 || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// AuthenticateAPIKey resolves a presented key. It fails for unknown, revoked
+// and expired keys, and for keys whose owner is no longer an admin.
+func (a *AppService) AuthenticateAPIKey(ctx context.Context, key string) (*structs.APIKey, error) {
+	prefix, ok := parseAPIKeyPrefix(strings.TrimSpace(key))
+	if !ok {
+		return nil, errInvalidAPIKey
+	}
+
+	stored, err := a.db.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errInvalidAPIKey
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(strings.TrimSpace(key))), []byte(stored.KeyHash)) != 1 {
+		return nil, errInvalidAPIKey
+	}
+	if stored.RevokedAt != nil || stored.ExpiresAt <= time.Now().Unix() {
+		return nil, errInvalidAPIKey
+	}
+	if !a.IsAdmin(ctx, stored.OwnerUserId) {
+		return nil, errInvalidAPIKey
+	}
+
+	if err := a.db.TouchAPIKey(ctx, stored.Id); err != nil {
+		a.logger.Errorf(ctx, "error recording use of api key %s: %s", stored.Id, err)
+	}
+	return stored, nil
+}
+
+// requireUserSession rejects requests made with an API key, so keys cannot be
+// used to mint or revoke other keys.
+func requireUserSession(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if utils.GetAPIKeyId(r) != nil {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("api keys cannot be managed with an api key"))
+		return "", false
+	}
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return "", false
+	}
+	return *userDid, true
+}
+
+func (a *AppService) GetAdminAPIKeys(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireUserSession(w, r); !ok {
+		return
+	}
+
+	keys, err := a.db.GetAPIKeys(r.Context())
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error loading api keys: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(keys)
+}
+
+func (a *AppService) CreateAdminAPIKey(w http.ResponseWriter, r *http.Request) {
+	adminId, ok := requireUserSession(w, r)
+	if !ok {
+		return
+	}
+
+	req := structs.APIKeyCreateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > apiKeyNameMaxLength {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("name is required and must be at most 120 characters"))
+		return
+	}
+
+	scopes := []string{}
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !slices.Contains(structs.APIKeyScopes, scope) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid scope " + scope))
+			return
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("at least one scope is required"))
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(apiKeyDefaultLifetime).Unix()
+	if req.ExpiresAt != 0 {
+		if req.ExpiresAt <= now.Unix() || req.ExpiresAt > now.Add(apiKeyMaxLifetime).Unix() {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("expires_at must be in the future and within a year"))
+			return
+		}
+		expiresAt = req.ExpiresAt
+	}
+
+	ownerId := strings.TrimSpace(req.OwnerUserId)
+	if ownerId == "" {
+		ownerId = adminId
+	}
+	if !a.IsAdmin(r.Context(), ownerId) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("api key owner must be an admin"))
+		return
+	}
+
+	key, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error generating api key: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	created, err := a.db.CreateAPIKey(r.Context(), &structs.APIKey{
+		Name:        req.Name,
+		OwnerUserId: ownerId,
+		Prefix:      prefix,
+		KeyHash:     hash,
+		Scopes:      scopes,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error creating api key for owner %s by admin %s: %s", ownerId, adminId, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	a.logger.Infof(r.Context(), "api key %s (%s) with scopes %v issued to %s by admin %s", created.Id, created.Name, created.Scopes, ownerId, adminId)
+	a.recordAdminAudit(r, structs.AdminAuditAPIKeyCreate, "api_key", created.Id, nil, created)
+
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(structs.APIKeyCreateResponse{Key: key, APIKey: created})
+}
+
+func (a *AppService) RevokeAdminAPIKey(w http.ResponseWriter, r *http.Request) {
+	adminId, ok := requireUserSession(w, r)
+	if !ok {
+		return
+	}
+
+	keyId := strings.TrimSpace(r.PathValue("key_id"))
+	if keyId == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	revoked, err := a.db.RevokeAPIKey(r.Context(), keyId, adminId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		a.logger.Errorf(r.Context(), "error revoking api key %s by admin %s: %s", keyId, adminId, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	a.logger.Infof(r.Context(), "api key %s revoked by admin %s", keyId, adminId)
+	a.recordAdminAudit(r, structs.AdminAuditAPIKeyRevoke, "api_key", keyId, nil, revoked)
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(revoked)
+}
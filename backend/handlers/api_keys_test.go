@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestGenerateAPIKeyRoundTrip(t *testing.T) {
+	key, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		t.Fatalf("generating key: %s", err)
+	}
+	if !strings.HasPrefix(key, "sfluv_"+prefix+"_") {
+		t.Fatalf("expected key to embed its prefix, got %s", key)
+	}
+	if strings.Contains(hash, key) || hash != hashAPIKey(key) {
+		t.Fatal("expected the stored hash to be the SHA-256 of the key")
+	}
+
+	parsed, ok := parseAPIKeyPrefix(key)
+	if !ok || parsed != prefix {
+		t.Fatalf("expected prefix %s, got %s (ok=%v)", prefix, parsed, ok)
+	}
+
+	other, _, _, _ := generateAPIKey()
+	if other == key {
+		t.Fatal("expected distinct keys")
+	}
+}
+
+func TestParseAPIKeyPrefixRejectsMalformedKeys(t *testing.T) {
+	for _, key := range []string{"", "x", "sfluv_abc_def", "other_0123456789ab_secret", "sfluv_0123456789ab_", "sfluv_0123456789ab_a_b"} {
+		if _, ok := parseAPIKeyPrefix(key); ok {
+			t.Fatalf("expected %q to be rejected", key)
+		}
+	}
+}
+
+func TestAPIKeyHasScope(t *testing.T) {
+	key := &structs.APIKey{Scopes: []string{structs.APIKeyScopeW9Write}}
+	if !key.HasScope(structs.APIKeyScopeW9Write) {
+		t.Fatal("expected w9:write key to have w9:write")
+	}
+	if key.HasScope(structs.APIKeyScopeEventsCreate) || key.HasScope(structs.APIKeyScopeAdmin) {
+		t.Fatal("expected w9:write key to be limited to its scope")
+	}
+
+	admin := &structs.APIKey{Scopes: []string{structs.APIKeyScopeAdmin}}
+	if !admin.HasScope(structs.APIKeyScopeAnalyticsRead) {
+		t.Fatal("expected admin scope to cover every route")
+	}
+}
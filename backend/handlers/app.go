@@ -8,7 +8,10 @@ import (
 
 	"github.com/SFLuv/app/backend/clientconfig"
 	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/lifecycle"
 	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/photostore"
+	"github.com/SFLuv/app/backend/push"
 )
 
 type AppService struct {
@@ -17,8 +20,16 @@ type AppService struct {
 	bot          *BotService
 	redeemer     *RedeemerService
 	minter       *MinterService
+	payoutQueue  *WorkflowPayoutQueue
+	emailOutbox  *EmailOutbox
+	push         *push.Registry
+	lifecycle    *lifecycle.Manager
 	logger       *logger.LogCloser
 	clientConfig *clientconfig.Config
+
+	photoURLSigner *photostore.URLSigner
+	photoURLTTL    time.Duration
+	photoURLBase   string
 }
 
 func NewAppService(db *db.AppDB, logger *logger.LogCloser, w9 *W9Service, clientConfig *clientconfig.Config) *AppService {
@@ -46,6 +57,20 @@ func (a *AppService) SetMinterService(minter *MinterService) {
 	a.minter = minter
 }
 
+func (a *AppService) SetPushProviders(providers *push.Registry) {
+	a.push = providers
+}
+
+func (a *AppService) SetWorkflowPayoutQueue(queue *WorkflowPayoutQueue) {
+	a.payoutQueue = queue
+}
+
+// SetLifecycle tracks the service's own background goroutines, such as push
+// receipt checks, so shutdown waits for them.
+func (a *AppService) SetLifecycle(lc *lifecycle.Manager) {
+	a.lifecycle = lc
+}
+
 func (a *AppService) RecordAnalyticsUserActivity(ctx context.Context, userID string, r *http.Request) {
 	if a == nil || a.db == nil {
 		return
@@ -59,7 +84,7 @@ func (a *AppService) RecordAnalyticsUserActivity(ctx context.Context, userID str
 		}
 	}
 	if err := a.db.RecordAnalyticsUserActivity(ctx, userID, platform, time.Now().UTC()); err != nil && a.logger != nil {
-		a.logger.Logf("error recording analytics user activity for %s: %s", userID, err)
+		a.logger.Errorf(ctx, "error recording analytics user activity for %s: %s", userID, err)
 	}
 	a.recordClientVersionObservation(ctx, userID, "authenticated_request", r)
 }
@@ -20,7 +20,7 @@ func accountPurgeEnabled() bool {
 func (a *AppService) UserIsActive(ctx context.Context, id string) bool {
 	active, err := a.db.UserIsActive(ctx, id)
 	if err != nil {
-		a.logger.Logf("error checking active user state for %s: %s", id, err)
+		a.logger.Errorf(ctx, "error checking active user state for %s: %s", id, err)
 		return false
 	}
 	return active
@@ -43,7 +43,7 @@ func (a *AppService) GetDeleteAccountPreview(w http.ResponseWriter, r *http.Requ
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error building delete-account preview for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error building delete-account preview for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -70,13 +70,13 @@ func (a *AppService) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error scheduling account deletion for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error scheduling account deletion for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if err := a.revokeDeletedUserAppleAccess(r.Context(), *userDid); err != nil {
-		a.logger.Logf("error revoking apple access at delete initiation for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error revoking apple access at delete initiation for user %s: %s", *userDid, err)
 	}
 
 	status.PurgeEnabled = accountPurgeEnabled()
@@ -104,7 +104,7 @@ func (a *AppService) CancelDeleteAccount(w http.ResponseWriter, r *http.Request)
 			w.WriteHeader(http.StatusNotFound)
 			return
 		default:
-			a.logger.Logf("error canceling account deletion for user %s: %s", *userDid, err)
+			a.logger.Errorf(r.Context(), "error canceling account deletion for user %s: %s", *userDid, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -128,7 +128,7 @@ func (a *AppService) GetDeleteAccountStatus(w http.ResponseWriter, r *http.Reque
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error getting delete-account status for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting delete-account status for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -170,7 +170,7 @@ func (a *AppService) PurgeDeletedAccountsManual(w http.ResponseWriter, r *http.R
 
 	if userID != "" {
 		if err := a.revokeDeletedUserAppleAccess(r.Context(), userID); err != nil {
-			a.logger.Logf("error revoking apple access for purged user %s: %s", userID, err)
+			a.logger.Errorf(r.Context(), "error revoking apple access for purged user %s: %s", userID, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -183,7 +183,7 @@ func (a *AppService) PurgeDeletedAccountsManual(w http.ResponseWriter, r *http.R
 				w.WriteHeader(http.StatusNotFound)
 				return
 			default:
-				a.logger.Logf("error manually purging deleted user %s: %s", userID, err)
+				a.logger.Errorf(r.Context(), "error manually purging deleted user %s: %s", userID, err)
 				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
@@ -199,7 +199,7 @@ func (a *AppService) PurgeDeletedAccountsManual(w http.ResponseWriter, r *http.R
 
 	userIDs, err := a.db.ListUsersReadyForPurge(r.Context(), now)
 	if err != nil {
-		a.logger.Logf("error listing purge-ready users: %s", err)
+		a.logger.Errorf(r.Context(), "error listing purge-ready users: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -207,12 +207,12 @@ func (a *AppService) PurgeDeletedAccountsManual(w http.ResponseWriter, r *http.R
 	purgedUserIDs := make([]string, 0, len(userIDs))
 	for _, candidateID := range userIDs {
 		if err := a.revokeDeletedUserAppleAccess(r.Context(), candidateID); err != nil {
-			a.logger.Logf("error revoking apple access for purged user %s: %s", candidateID, err)
+			a.logger.Errorf(r.Context(), "error revoking apple access for purged user %s: %s", candidateID, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 		if err := a.db.PurgeDeletedUser(r.Context(), candidateID, now); err != nil {
-			a.logger.Logf("error manually purging deleted user %s: %s", candidateID, err)
+			a.logger.Errorf(r.Context(), "error manually purging deleted user %s: %s", candidateID, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -42,33 +42,33 @@ func (a *AppService) GetUsers(w http.ResponseWriter, r *http.Request) {
 
 	users, err := a.db.GetUsers(r.Context(), page, count, search, versionFilters)
 	if err != nil {
-		a.logger.Logf("error getting users: %s", err)
+		a.logger.Errorf(r.Context(), "error getting users: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if err := a.db.AttachClientVersionDevices(r.Context(), users); err != nil {
-		a.logger.Logf("error attaching client version devices to users: %s", err)
+		a.logger.Errorf(r.Context(), "error attaching client version devices to users: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	total, err := a.db.CountUsers(r.Context(), search, versionFilters)
 	if err != nil {
-		a.logger.Logf("error counting users: %s", err)
+		a.logger.Errorf(r.Context(), "error counting users: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	versionOptions, err := a.db.GetClientVersionFilterOptions(r.Context())
 	if err != nil {
-		a.logger.Logf("error getting client version filter options: %s", err)
+		a.logger.Errorf(r.Context(), "error getting client version filter options: %s", err)
 		versionOptions = []string{}
 	}
 
 	versionCounts, err := a.db.GetClientVersionUserCounts(r.Context())
 	if err != nil {
-		a.logger.Logf("error getting client version user counts: %s", err)
+		a.logger.Errorf(r.Context(), "error getting client version user counts: %s", err)
 		versionCounts = []*structs.ClientVersionUserCount{}
 	}
 
@@ -107,7 +107,7 @@ func (a *AppService) ExportUserEmailList(w http.ResponseWriter, r *http.Request)
 
 	emails, err := a.db.GetMailingListEmails(r.Context())
 	if err != nil {
-		a.logger.Logf("error exporting mailing list emails: %s", err)
+		a.logger.Errorf(r.Context(), "error exporting mailing list emails: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -115,20 +115,20 @@ func (a *AppService) ExportUserEmailList(w http.ResponseWriter, r *http.Request)
 	var buf bytes.Buffer
 	writer := csv.NewWriter(&buf)
 	if err := writer.Write([]string{"email"}); err != nil {
-		a.logger.Logf("error writing mailing list csv header: %s", err)
+		a.logger.Errorf(r.Context(), "error writing mailing list csv header: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 	for _, email := range emails {
 		if err := writer.Write([]string{email}); err != nil {
-			a.logger.Logf("error writing mailing list csv row: %s", err)
+			a.logger.Errorf(r.Context(), "error writing mailing list csv row: %s", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 	}
 	writer.Flush()
 	if err := writer.Error(); err != nil {
-		a.logger.Logf("error flushing mailing list csv: %s", err)
+		a.logger.Errorf(r.Context(), "error flushing mailing list csv: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -155,7 +155,7 @@ func (a *AppService) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update user role body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading update user role body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -175,20 +175,51 @@ func (a *AppService) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var before any
+	if user, err := a.db.GetUserById(r.Context(), req.UserId); err == nil {
+		if value, ok := userRoleValue(user, req.Role); ok {
+			before = map[string]any{"role": req.Role, "value": value}
+		}
+	}
 	if err := a.db.UpdateUserRole(r.Context(), req.UserId, req.Role, req.Value); err != nil {
-		a.logger.Logf("error updating user role: %s", err)
+		a.logger.Errorf(r.Context(), "error updating user role: %s", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditUserRoleUpdate, "user", req.UserId, before, map[string]any{"role": req.Role, "value": req.Value})
 
 	w.WriteHeader(http.StatusCreated)
 }
 
+func userRoleValue(user *structs.User, role string) (bool, bool) {
+	switch role {
+	case "admin":
+		return user.IsAdmin, true
+	case "merchant":
+		return user.IsMerchant, true
+	case "organizer":
+		return user.IsOrganizer, true
+	case "improver":
+		return user.IsImprover, true
+	case "proposer":
+		return user.IsProposer, true
+	case "voter":
+		return user.IsVoter, true
+	case "issuer":
+		return user.IsIssuer, true
+	case "supervisor":
+		return user.IsSupervisor, true
+	case "affiliate":
+		return user.IsAffiliate, true
+	}
+	return false, false
+}
+
 func (a *AppService) UpdateLocationApproval(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update location approval body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading update location approval body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -196,14 +227,14 @@ func (a *AppService) UpdateLocationApproval(w http.ResponseWriter, r *http.Reque
 	var u structs.UpdateLocationApprovalRequest
 	err = json.Unmarshal(body, &u)
 	if err != nil {
-		a.logger.Logf("error unmarshalling update location approval body: %s", err)
+		a.logger.Errorf(r.Context(), "error unmarshalling update location approval body: %s", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	ownerID, wasApproved, err := a.db.GetLocationOwnerAndApproval(r.Context(), u.Id)
 	if err != nil {
-		a.logger.Logf("error loading location %d owner/approval state: %s", u.Id, err)
+		a.logger.Errorf(r.Context(), "error loading location %d owner/approval state: %s", u.Id, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -213,7 +244,7 @@ func (a *AppService) UpdateLocationApproval(w http.ResponseWriter, r *http.Reque
 	if isApproving {
 		hadOtherApprovedLocations, err = a.db.OwnerHasApprovedLocationExcluding(r.Context(), ownerID, u.Id)
 		if err != nil {
-			a.logger.Logf("error checking existing approved locations for owner %s: %s", ownerID, err)
+			a.logger.Errorf(r.Context(), "error checking existing approved locations for owner %s: %s", ownerID, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -229,14 +260,22 @@ func (a *AppService) UpdateLocationApproval(w http.ResponseWriter, r *http.Reque
 				status = "rejected"
 			}
 		}
-		a.logger.Logf("error updating location approval for location %d to %s", u.Id, status)
+		a.logger.Errorf(r.Context(), "error updating location approval for location %d to %s", u.Id, status)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(
+		r,
+		structs.AdminAuditLocationApprovalUpdate,
+		"location",
+		strconv.FormatUint(uint64(u.Id), 10),
+		map[string]any{"owner_id": ownerID, "approved": wasApproved},
+		map[string]any{"owner_id": ownerID, "approval": u.Approval},
+	)
 
 	if a.redeemer != nil && a.redeemer.IsEnabled() && isApproving && !wasApproved && !hadOtherApprovedLocations {
 		if err := a.redeemer.EnsureMerchantHasRedeemerWallet(r.Context(), ownerID); err != nil {
-			a.logger.Logf("error auto-granting redeemer role for user %s after location %d approval: %s", ownerID, u.Id, err)
+			a.logger.Errorf(r.Context(), "error auto-granting redeemer role for user %s after location %d approval: %s", ownerID, u.Id, err)
 		}
 	}
 
@@ -246,7 +285,7 @@ func (a *AppService) UpdateLocationApproval(w http.ResponseWriter, r *http.Reque
 func (a *AppService) IsAdmin(ctx context.Context, id string) bool {
 	isAdmin, err := a.db.IsAdmin(ctx, id)
 	if err != nil {
-		a.logger.Logf("error getting admin state for user %s: %s", id, err)
+		a.logger.Errorf(ctx, "error getting admin state for user %s: %s", id, err)
 		return false
 	}
 
@@ -3,10 +3,10 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/SFLuv/app/backend/emails"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
 	"github.com/jackc/pgx/v5"
@@ -22,7 +22,7 @@ func (a *AppService) RequestAffiliateStatus(w http.ResponseWriter, r *http.Reque
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading affiliate request body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading affiliate request body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -40,27 +40,15 @@ func (a *AppService) RequestAffiliateStatus(w http.ResponseWriter, r *http.Reque
 			w.WriteHeader(http.StatusConflict)
 			return
 		}
-		a.logger.Logf("error upserting affiliate request for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error upserting affiliate request for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	a.sendRoleRequestEmail(
-		"AFFILIATE_ADMIN_EMAIL",
-		"New Affiliate Request",
-		"A user has requested affiliate status.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:140px;">User</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Organization</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(affiliate.UserId), utils.EscapeEmailHTML(affiliate.Organization)),
-	)
+	a.sendRoleRequestEmail("AFFILIATE_ADMIN_EMAIL", "affiliate_request", emails.Data{
+		"UserId":       affiliate.UserId,
+		"Organization": affiliate.Organization,
+	})
 
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(affiliate)
@@ -71,7 +59,7 @@ func (a *AppService) GetAffiliates(w http.ResponseWriter, r *http.Request) {
 	page, count := parsePageAndCount(r.URL.Query(), 20, 100)
 	affiliates, err := a.db.GetAffiliates(r.Context(), search, page, count)
 	if err != nil {
-		a.logger.Logf("error getting affiliates: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error getting affiliates: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -104,7 +92,7 @@ func (a *AppService) GetAffiliate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		a.logger.Logf("error getting affiliate %s: %s", userId, err.Error())
+		a.logger.Errorf(r.Context(), "error getting affiliate %s: %s", userId, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -123,7 +111,7 @@ func (a *AppService) UpdateAffiliateLogo(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading affiliate logo update body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading affiliate logo update body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -137,7 +125,7 @@ func (a *AppService) UpdateAffiliateLogo(w http.ResponseWriter, r *http.Request)
 
 	affiliate, err := a.db.UpdateAffiliateLogo(r.Context(), *userDid, &req.Logo)
 	if err != nil {
-		a.logger.Logf("error updating affiliate logo for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error updating affiliate logo for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -150,7 +138,7 @@ func (a *AppService) UpdateAffiliate(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update affiliate body: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error reading update affiliate body: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -162,12 +150,14 @@ func (a *AppService) UpdateAffiliate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before, _ := a.db.GetAffiliateByUser(r.Context(), req.UserId)
 	affiliate, err := a.db.UpdateAffiliate(r.Context(), &req)
 	if err != nil {
-		a.logger.Logf("error updating affiliate %s: %s", req.UserId, err.Error())
+		a.logger.Errorf(r.Context(), "error updating affiliate %s: %s", req.UserId, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditAffiliateUpdate, "user", req.UserId, before, affiliate)
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(affiliate)
@@ -176,7 +166,7 @@ func (a *AppService) UpdateAffiliate(w http.ResponseWriter, r *http.Request) {
 func (a *AppService) IsAffiliate(ctx context.Context, id string) bool {
 	isAffiliate, err := a.db.IsAffiliate(ctx, id)
 	if err != nil {
-		a.logger.Logf("error getting affiliate state for user %s: %s", id, err)
+		a.logger.Errorf(ctx, "error getting affiliate state for user %s: %s", id, err)
 		return false
 	}
 
@@ -186,7 +176,7 @@ func (a *AppService) IsAffiliate(ctx context.Context, id string) bool {
 func (a *AppService) GetFirstAdminId(ctx context.Context) string {
 	id, err := a.db.GetFirstAdminId(ctx)
 	if err != nil && err != pgx.ErrNoRows {
-		a.logger.Logf("error getting default admin id: %s", err)
+		a.logger.Errorf(ctx, "error getting default admin id: %s", err)
 	}
 	return id
 }
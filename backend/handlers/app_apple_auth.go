@@ -79,7 +79,7 @@ func (a *AppService) StoreAppleOAuthCredential(w http.ResponseWriter, r *http.Re
 	if ok {
 		record, err := a.fetchPrivyUser(r.Context(), *userDid, appID, appSecret)
 		if err != nil {
-			a.logger.Logf("error fetching privy user for apple oauth credential sync %s: %s", *userDid, err)
+			a.logger.Errorf(r.Context(), "error fetching privy user for apple oauth credential sync %s: %s", *userDid, err)
 		} else {
 			appleAccount = extractLinkedAppleAccount(record)
 		}
@@ -100,13 +100,13 @@ func (a *AppService) StoreAppleOAuthCredential(w http.ResponseWriter, r *http.Re
 
 	accessTokenEncrypted, err := encryptSensitiveValue(strings.TrimSpace(request.AccessToken))
 	if err != nil {
-		a.logger.Logf("error encrypting apple access token for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error encrypting apple access token for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 	refreshTokenEncrypted, err := encryptSensitiveValue(strings.TrimSpace(request.RefreshToken))
 	if err != nil {
-		a.logger.Logf("error encrypting apple refresh token for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error encrypting apple refresh token for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -138,14 +138,14 @@ func (a *AppService) StoreAppleOAuthCredential(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := a.db.UpsertUserOAuthCredential(r.Context(), credential); err != nil {
-		a.logger.Logf("error storing apple oauth credential for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error storing apple oauth credential for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if exists, err := a.db.UserExistsAndActive(r.Context(), *userDid); err == nil && exists {
 		if _, err := a.SyncPrivyLinkedEmailsForUser(r.Context(), *userDid); err != nil {
-			a.logger.Logf("error syncing Privy linked emails after apple oauth store for user %s: %s", *userDid, err)
+			a.logger.Errorf(r.Context(), "error syncing Privy linked emails after apple oauth store for user %s: %s", *userDid, err)
 		}
 	}
 
@@ -159,7 +159,7 @@ func (a *AppService) recordClientPhoneHome(ctx context.Context, endpoint string,
 		Build:    truncateMetricField(build, phoneHomeFieldMaxLen),
 	}
 	if err := a.db.RecordClientPhoneHome(ctx, metric); err != nil && a.logger != nil {
-		a.logger.Logf("error recording client phone home for %s: %s", endpoint, err)
+		a.logger.Errorf(ctx, "error recording client phone home for %s: %s", endpoint, err)
 	}
 }
 
@@ -195,7 +195,7 @@ func (a *AppService) recordClientVersionObservation(ctx context.Context, userID
 		SeenAt:      time.Now().UTC(),
 	}
 	if err := a.db.RecordClientVersionObservation(ctx, observation); err != nil && a.logger != nil {
-		a.logger.Logf("error recording client version observation for %s: %s", userID, err)
+		a.logger.Errorf(ctx, "error recording client version observation for %s: %s", userID, err)
 	}
 }
 
@@ -217,6 +217,6 @@ func (a *AppService) recordLegacyMobileClientObservation(ctx context.Context, us
 		SeenAt:         time.Now().UTC(),
 	}
 	if err := a.db.RecordClientVersionObservation(ctx, observation); err != nil && a.logger != nil {
-		a.logger.Logf("error recording inferred legacy client for %s: %s", userID, err)
+		a.logger.Errorf(ctx, "error recording inferred legacy client for %s: %s", userID, err)
 	}
 }
@@ -21,7 +21,7 @@ func (a *AppService) NewContact(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf(err.Error())
+		a.logger.Errorf(r.Context(), "%s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -29,21 +29,21 @@ func (a *AppService) NewContact(w http.ResponseWriter, r *http.Request) {
 	var c structs.Contact
 	err = json.Unmarshal(body, &c)
 	if err != nil {
-		a.logger.Logf(err.Error())
+		a.logger.Errorf(r.Context(), "%s", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	contact, err := a.db.AddContact(r.Context(), &c, *userDid)
 	if err != nil {
-		a.logger.Logf("error adding contact for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error adding contact for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	res, err := json.Marshal(contact)
 	if err != nil {
-		a.logger.Logf(err.Error())
+		a.logger.Errorf(r.Context(), "%s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -63,14 +63,14 @@ func (a *AppService) GetContacts(w http.ResponseWriter, r *http.Request) {
 
 	contacts, err := a.db.GetContacts(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting contacts for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting contacts for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	res, err := json.Marshal(contacts)
 	if err != nil {
-		a.logger.Logf(err.Error())
+		a.logger.Errorf(r.Context(), "%s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -90,7 +90,7 @@ func (a *AppService) UpdateContact(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf(err.Error())
+		a.logger.Errorf(r.Context(), "%s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -98,14 +98,14 @@ func (a *AppService) UpdateContact(w http.ResponseWriter, r *http.Request) {
 	var c structs.Contact
 	err = json.Unmarshal(body, &c)
 	if err != nil {
-		a.logger.Logf(err.Error())
+		a.logger.Errorf(r.Context(), "%s", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	err = a.db.UpdateContact(r.Context(), &c, *userDid)
 	if err != nil {
-		a.logger.Logf("error updating contact %d for user %s: %s", c.Id, *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error updating contact %d for user %s: %s", c.Id, *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -123,14 +123,14 @@ func (a *AppService) DeleteContact(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("id")
 	cId, err := strconv.Atoi(id)
 	if err != nil {
-		a.logger.Logf(err.Error())
+		a.logger.Errorf(r.Context(), "%s", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	err = a.db.DeleteContact(r.Context(), cId, *userDid)
 	if err != nil {
-		a.logger.Logf("error deleting contact %d for user %s: %s", cId, *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error deleting contact %d for user %s: %s", cId, *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
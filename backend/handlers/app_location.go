@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/SFLuv/app/backend/emails"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
 	"github.com/go-chi/chi/v5"
@@ -17,13 +18,13 @@ func (a *AppService) GetLocation(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	num, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		a.logger.Logf("invalid id, got: %s: %s", id, err.Error())
+		a.logger.Infof(r.Context(), "invalid id, got: %s: %s", id, err.Error())
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 	location, err := a.db.GetLocation(r.Context(), num)
 	if err != nil {
-		a.logger.Logf("no location with id %s: %s", id, err.Error())
+		a.logger.Infof(r.Context(), "no location with id %s: %s", id, err.Error())
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -32,7 +33,7 @@ func (a *AppService) GetLocation(w http.ResponseWriter, r *http.Request) {
 		"location": location,
 	})
 	if err != nil {
-		a.logger.Logf("Error marhalling json %s", err.Error())
+		a.logger.Errorf(r.Context(), "Error marhalling json %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -50,7 +51,7 @@ func (a *AppService) GetLocations(w http.ResponseWriter, r *http.Request) {
 
 	locations, err := a.db.GetLocations(r.Context(), &request)
 	if err != nil {
-		a.logger.Logf("Failed to get locations %s", err.Error())
+		a.logger.Errorf(r.Context(), "Failed to get locations %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -59,7 +60,7 @@ func (a *AppService) GetLocations(w http.ResponseWriter, r *http.Request) {
 	})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		a.logger.Logf("Error marshalling JSON for locations objects %s", err.Error())
+		a.logger.Errorf(r.Context(), "Error marshalling JSON for locations objects %s", err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -76,7 +77,7 @@ func (a *AppService) GetAuthedLocations(w http.ResponseWriter, r *http.Request)
 
 	locations, err := a.db.GetAuthedLocations(r.Context(), &request)
 	if err != nil {
-		a.logger.Logf("Failed to get locations %s", err.Error())
+		a.logger.Errorf(r.Context(), "Failed to get locations %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -85,7 +86,7 @@ func (a *AppService) GetAuthedLocations(w http.ResponseWriter, r *http.Request)
 	})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		a.logger.Logf("Error marshalling JSON for locations objects %s", err.Error())
+		a.logger.Errorf(r.Context(), "Error marshalling JSON for locations objects %s", err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -95,14 +96,14 @@ func (a *AppService) GetAuthedLocations(w http.ResponseWriter, r *http.Request)
 func (a *AppService) GetLocationsByUser(w http.ResponseWriter, r *http.Request) {
 	userDid := utils.GetDid(r)
 	if userDid == nil {
-		a.logger.Logf("Could not pull user DID")
+		a.logger.Infof(r.Context(), "Could not pull user DID")
 		w.WriteHeader(http.StatusForbidden)
 		return
 	}
 
 	locations, err := a.db.GetLocationsByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting locations for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting locations for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -110,7 +111,7 @@ func (a *AppService) GetLocationsByUser(w http.ResponseWriter, r *http.Request)
 		"locations": locations,
 	})
 	if err != nil {
-		a.logger.Logf(("Error marshalling JSON for locations objects"))
+		a.logger.Errorf(r.Context(), ("Error marshalling JSON for locations objects"))
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -123,14 +124,14 @@ func (a *AppService) AddLocation(w http.ResponseWriter, r *http.Request) {
 
 	userDid := utils.GetDid(r)
 	if userDid == nil {
-		a.logger.Logf("Could not pull user DID")
+		a.logger.Infof(r.Context(), "Could not pull user DID")
 		w.WriteHeader(http.StatusForbidden)
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading req body: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error reading req body: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -138,7 +139,7 @@ func (a *AppService) AddLocation(w http.ResponseWriter, r *http.Request) {
 	var location *structs.Location
 	err = json.Unmarshal(body, &location)
 	if err != nil {
-		a.logger.Logf("invalid req body: %s", err.Error())
+		a.logger.Infof(r.Context(), "invalid req body: %s", err.Error())
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -146,26 +147,14 @@ func (a *AppService) AddLocation(w http.ResponseWriter, r *http.Request) {
 	err = a.db.AddLocation(r.Context(), location)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		a.logger.Logf("invalid location body: %s", err.Error())
+		a.logger.Infof(r.Context(), "invalid location body: %s", err.Error())
 		return
 	}
 
-	a.sendRoleRequestEmail(
-		"MERCHANT_ADMIN_EMAIL",
-		"New Location Added",
-		"A new merchant location has been submitted for review.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:140px;">Location</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Submitted By</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(location.Name), utils.EscapeEmailHTML(location.OwnerID)),
-	)
+	a.sendRoleRequestEmail("MERCHANT_ADMIN_EMAIL", "location_submitted", emails.Data{
+		"LocationName": location.Name,
+		"SubmittedBy":  location.OwnerID,
+	})
 
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte("success"))
@@ -176,14 +165,14 @@ func (a *AppService) UpdateLocation(w http.ResponseWriter, r *http.Request) {
 
 	userDid := utils.GetDid(r)
 	if userDid == nil {
-		a.logger.Logf("could not pull user DID")
+		a.logger.Infof(r.Context(), "could not pull user DID")
 		w.WriteHeader(http.StatusForbidden)
 		return
 	}
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update location body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading update location body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -191,7 +180,7 @@ func (a *AppService) UpdateLocation(w http.ResponseWriter, r *http.Request) {
 	var location structs.Location
 	err = json.Unmarshal(body, &location)
 	if err != nil {
-		a.logger.Logf("error unmarshalling update location body: %s", err)
+		a.logger.Errorf(r.Context(), "error unmarshalling update location body: %s", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -200,7 +189,7 @@ func (a *AppService) UpdateLocation(w http.ResponseWriter, r *http.Request) {
 
 	err = a.db.UpdateLocation(r.Context(), &location)
 	if err != nil {
-		a.logger.Logf("failed to update location %s", err.Error())
+		a.logger.Errorf(r.Context(), "failed to update location %s", err.Error())
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -208,30 +197,18 @@ func (a *AppService) UpdateLocation(w http.ResponseWriter, r *http.Request) {
 	// TODO: how do we make sure this is the first time a location is approved?
 	if *location.Approval {
 		// send confirmation email to contact associated with location
-		sender := utils.NewEmailSender()
-		if sender != nil {
-			details := fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:160px;">Location</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Status</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">Approved</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(location.Name))
-
-			htmlContent := utils.BuildStyledEmail(
-				"Location Approved",
-				"Your location has been approved.",
-				details,
-			)
-
-			err = sender.SendEmail(location.AdminEmail, fmt.Sprintf("%s %s", location.ContactFirstName, location.ContactLastName), "Location Approved", htmlContent, utils.NotificationFromEmail(), "SFLuv Admin")
-			if err != nil {
-				a.logger.Logf("error sending confirmation email: %s", err.Error())
-			}
+		err = a.Notify(r.Context(), &Notification{
+			Category: structs.NotificationCategoryAccount,
+			Email: &NotificationEmail{
+				To:       location.AdminEmail,
+				ToName:   fmt.Sprintf("%s %s", location.ContactFirstName, location.ContactLastName),
+				Template: "location_approved",
+				Data:     emails.Data{"LocationName": location.Name},
+				FromName: "SFLuv Admin",
+			},
+		})
+		if err != nil {
+			a.logger.Errorf(r.Context(), "error sending confirmation email: %s", err.Error())
 		}
 	}
 
@@ -255,7 +232,7 @@ func (a *AppService) UpdateLocationWalletSettings(w http.ResponseWriter, r *http
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading location wallet settings body for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading location wallet settings body for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -280,7 +257,7 @@ func (a *AppService) UpdateLocationWalletSettings(w http.ResponseWriter, r *http
 			return
 		}
 
-		a.logger.Logf("error updating location wallet settings for user %s and location %d: %s", *userDid, locationID, errMsg)
+		a.logger.Errorf(r.Context(), "error updating location wallet settings for user %s and location %d: %s", *userDid, locationID, errMsg)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3,12 +3,12 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
 	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/emails"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
 	"github.com/go-chi/chi/v5"
@@ -47,14 +47,14 @@ func (a *AppService) GetMerchantModeStatus(w http.ResponseWriter, r *http.Reques
 
 	status, err := a.db.GetMerchantModeStatus(r.Context(), *userDid, r.URL.Query().Get("installation_id"))
 	if err != nil {
-		a.logger.Logf("error getting merchant mode status for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting merchant mode status for user %s: %s", *userDid, err.Error())
 		writeMerchantModeError(w, err)
 		return
 	}
 
 	jsonBytes, err := json.Marshal(status)
 	if err != nil {
-		a.logger.Logf("error marshalling merchant mode status for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error marshalling merchant mode status for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -72,7 +72,7 @@ func (a *AppService) SetMerchantModePIN(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading merchant mode PIN body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading merchant mode PIN body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -85,14 +85,14 @@ func (a *AppService) SetMerchantModePIN(w http.ResponseWriter, r *http.Request)
 
 	status, err := a.db.SetMerchantModePIN(r.Context(), *userDid, request.PIN, request.CurrentPIN)
 	if err != nil {
-		a.logger.Logf("error setting merchant mode PIN for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error setting merchant mode PIN for user %s: %s", *userDid, err.Error())
 		writeMerchantModeError(w, err)
 		return
 	}
 
 	jsonBytes, err := json.Marshal(status)
 	if err != nil {
-		a.logger.Logf("error marshalling merchant mode PIN response for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error marshalling merchant mode PIN response for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -110,7 +110,7 @@ func (a *AppService) RequestMerchantModePINHelp(w http.ResponseWriter, r *http.R
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading merchant mode PIN help body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading merchant mode PIN help body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -123,32 +123,18 @@ func (a *AppService) RequestMerchantModePINHelp(w http.ResponseWriter, r *http.R
 		}
 	}
 
-	emailSender := utils.NewEmailSender()
-	if emailSender == nil {
-		a.logger.Logf("merchant mode PIN help requested by %s, but email sender is not configured", *userDid)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
 	contactEmail := strings.TrimSpace(request.ContactEmail)
-	details := fmt.Sprintf(`<table role="presentation" width="100%%" cellpadding="0" cellspacing="0">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">User ID</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; text-align:right;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Contact Email</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827; text-align:right;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(*userDid), utils.EscapeEmailHTML(contactEmail))
-	htmlContent := utils.BuildStyledEmail(
-		"Merchant Mode PIN help requested",
-		"A merchant needs help resetting their Merchant Mode PIN.",
-		details,
-	)
-
-	if err := emailSender.SendEmail("techsupport@sfluv.org", "Tech Support", "Merchant Mode PIN help requested", htmlContent, utils.NotificationFromEmail(), "SFLuv Support"); err != nil {
-		a.logger.Logf("error sending merchant mode PIN help email for user %s: %s", *userDid, err.Error())
+	if err := a.Notify(r.Context(), &Notification{
+		Category: structs.NotificationCategoryAdmin,
+		Email: &NotificationEmail{
+			To:       "techsupport@sfluv.org",
+			ToName:   "Tech Support",
+			Template: "merchant_pin_help",
+			Data:     emails.Data{"UserId": *userDid, "ContactEmail": contactEmail},
+			FromName: "SFLuv Support",
+		},
+	}); err != nil {
+		a.logger.Errorf(r.Context(), "error sending merchant mode PIN help email for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -165,14 +151,14 @@ func (a *AppService) ListMerchantModeDevices(w http.ResponseWriter, r *http.Requ
 
 	response, err := a.db.ListMerchantModeDevices(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error listing merchant mode devices for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error listing merchant mode devices for user %s: %s", *userDid, err.Error())
 		writeMerchantModeError(w, err)
 		return
 	}
 
 	jsonBytes, err := json.Marshal(response)
 	if err != nil {
-		a.logger.Logf("error marshalling merchant mode devices for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error marshalling merchant mode devices for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -196,7 +182,7 @@ func (a *AppService) UpdateMerchantModeDevice(w http.ResponseWriter, r *http.Req
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading merchant mode device update body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading merchant mode device update body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -209,7 +195,7 @@ func (a *AppService) UpdateMerchantModeDevice(w http.ResponseWriter, r *http.Req
 
 	device, err := a.db.SetMerchantModeDeviceEnabled(r.Context(), *userDid, deviceID, request.MerchantModeEnabled)
 	if err != nil {
-		a.logger.Logf("error updating merchant mode device %s for user %s: %s", deviceID, *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error updating merchant mode device %s for user %s: %s", deviceID, *userDid, err.Error())
 		writeMerchantModeError(w, err)
 		return
 	}
@@ -217,7 +203,7 @@ func (a *AppService) UpdateMerchantModeDevice(w http.ResponseWriter, r *http.Req
 	response := &structs.MerchantModeDeviceUpdateResponse{Device: device}
 	jsonBytes, err := json.Marshal(response)
 	if err != nil {
-		a.logger.Logf("error marshalling merchant mode device update response for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error marshalling merchant mode device update response for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -235,7 +221,7 @@ func (a *AppService) EnableMerchantMode(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading merchant mode enable body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading merchant mode enable body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -248,14 +234,14 @@ func (a *AppService) EnableMerchantMode(w http.ResponseWriter, r *http.Request)
 
 	status, err := a.db.EnableMerchantModeDevice(r.Context(), *userDid, &request)
 	if err != nil {
-		a.logger.Logf("error enabling merchant mode for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error enabling merchant mode for user %s: %s", *userDid, err.Error())
 		writeMerchantModeError(w, err)
 		return
 	}
 
 	jsonBytes, err := json.Marshal(status)
 	if err != nil {
-		a.logger.Logf("error marshalling merchant mode enable response for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error marshalling merchant mode enable response for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -273,7 +259,7 @@ func (a *AppService) DisableMerchantMode(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading merchant mode disable body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading merchant mode disable body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -286,14 +272,14 @@ func (a *AppService) DisableMerchantMode(w http.ResponseWriter, r *http.Request)
 
 	status, err := a.db.DisableMerchantModeDevice(r.Context(), *userDid, &request)
 	if err != nil {
-		a.logger.Logf("error disabling merchant mode for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error disabling merchant mode for user %s: %s", *userDid, err.Error())
 		writeMerchantModeError(w, err)
 		return
 	}
 
 	jsonBytes, err := json.Marshal(status)
 	if err != nil {
-		a.logger.Logf("error marshalling merchant mode disable response for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error marshalling merchant mode disable response for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
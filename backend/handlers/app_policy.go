@@ -16,7 +16,7 @@ import (
 func (a *AppService) UserHasAcceptedPrivacyPolicy(ctx context.Context, id string) bool {
 	accepted, err := a.db.UserHasAcceptedPrivacyPolicy(ctx, id)
 	if err != nil {
-		a.logger.Logf("error checking privacy-policy acceptance for user %s: %s", id, err)
+		a.logger.Errorf(ctx, "error checking privacy-policy acceptance for user %s: %s", id, err)
 		return false
 	}
 
@@ -36,7 +36,7 @@ func (a *AppService) GetUserPolicyStatus(w http.ResponseWriter, r *http.Request)
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error getting user policy status for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting user policy status for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -57,7 +57,7 @@ func (a *AppService) AcceptUserPolicies(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading user policy acceptance request for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading user policy acceptance request for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -79,7 +79,7 @@ func (a *AppService) AcceptUserPolicies(w http.ResponseWriter, r *http.Request)
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading current user policy status for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading current user policy status for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -99,7 +99,7 @@ func (a *AppService) AcceptUserPolicies(w http.ResponseWriter, r *http.Request)
 		case db.ErrUserPendingDeletion:
 			w.WriteHeader(http.StatusConflict)
 		default:
-			a.logger.Logf("error accepting policies for user %s: %s", *userDid, err)
+			a.logger.Errorf(r.Context(), "error accepting policies for user %s: %s", *userDid, err)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 		return
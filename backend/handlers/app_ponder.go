@@ -4,47 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/SFLuv/app/backend/emails"
+	"github.com/SFLuv/app/backend/push"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
 )
 
-const (
-	defaultExpoPushAPIURL        = "https://exp.host/--/api/v2/push/send"
-	defaultExpoPushReceiptAPIURL = "https://exp.host/--/api/v2/push/getReceipts"
-	expoDeviceNotRegistered      = "DeviceNotRegistered"
-	pushDeleteReasonDeadToken    = "push_device_not_registered"
-)
-
-type expoPushTicket struct {
-	Status  string         `json:"status"`
-	ID      string         `json:"id,omitempty"`
-	Message string         `json:"message,omitempty"`
-	Details map[string]any `json:"details,omitempty"`
-}
-
-type expoPushSendResponse struct {
-	Data   json.RawMessage  `json:"data"`
-	Errors []expoPushTicket `json:"errors,omitempty"`
-}
-
-type expoPushReceipt struct {
-	Status  string         `json:"status"`
-	Message string         `json:"message,omitempty"`
-	Details map[string]any `json:"details,omitempty"`
-}
-
-type expoPushReceiptResponse struct {
-	Data   map[string]expoPushReceipt `json:"data"`
-	Errors []expoPushTicket           `json:"errors,omitempty"`
-}
+const pushDeleteReasonDeadToken = "push_device_not_registered"
 
 func shortenPonderAddress(address string) string {
 	address = strings.TrimSpace(address)
@@ -86,10 +60,16 @@ func (a *AppService) createPonderHook(ctx context.Context, address string) (*str
 		return nil, fmt.Errorf("PONDER_SERVER_BASE_URL is required")
 	}
 
+	secret, err := newPonderHookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("error generating ponder hook secret: %w", err)
+	}
+
 	subscriptionBody := structs.PonderSubscriptionServerRequest{
 		Id:      0,
 		Address: address,
 		Url:     os.Getenv("PONDER_CALLBACK_URL"),
+		Secret:  secret,
 	}
 
 	reqBody, err := json.Marshal(subscriptionBody)
@@ -123,6 +103,11 @@ func (a *AppService) createPonderHook(ctx context.Context, address string) (*str
 	if err := json.Unmarshal(resBody, &newSubscription); err != nil {
 		return nil, fmt.Errorf("error unmarshalling ponder subscription response: %w", err)
 	}
+	newSubscription.Secret = ""
+
+	if err := a.db.SetPonderHookSecret(ctx, newSubscription.Id, secret); err != nil {
+		return nil, err
+	}
 
 	return &newSubscription, nil
 }
@@ -150,150 +135,109 @@ func (a *AppService) deletePonderHook(ctx context.Context, hookID int) error {
 		return fmt.Errorf("ponder subscription not deleted: status %d: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
 	}
 
-	return nil
+	return a.db.DeletePonderHookSecret(ctx, hookID)
 }
 
-func (a *AppService) deletePonderHooksForAddressIfUnused(ctx context.Context, address string) error {
-	hasActiveDependency, err := a.db.HasActivePonderNotificationDependency(ctx, address)
-	if err != nil {
-		return err
-	}
-	if hasActiveDependency {
-		return nil
+// setPonderHookSecret re-keys an existing hook. The secret is stored locally
+// first so a failed Ponder update leaves callbacks rejected rather than
+// accepted unsigned; the local row is rolled back if Ponder refuses it.
+func (a *AppService) setPonderHookSecret(ctx context.Context, hookID int) error {
+	ponderUrl := ponderServerBaseURL()
+	if ponderUrl == "" {
+		return fmt.Errorf("PONDER_SERVER_BASE_URL is required")
 	}
 
-	hookIDs, err := a.db.GetKnownPonderHookIDsForAddress(ctx, address)
+	secret, err := newPonderHookSecret()
 	if err != nil {
-		return err
+		return fmt.Errorf("error generating ponder hook secret: %w", err)
 	}
 
-	for _, hookID := range hookIDs {
-		if err := a.deletePonderHook(ctx, hookID); err != nil {
-			return err
-		}
-		if err := a.db.ClearMobilePushSubscriptionPonderHook(ctx, hookID); err != nil {
-			return err
-		}
+	reqBody, err := json.Marshal(structs.PonderSubscriptionServerRequest{
+		Id:     hookID,
+		Secret: secret,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling ponder hook secret body: %w", err)
 	}
 
-	return nil
-}
-
-func expoPushReceiptURL() string {
-	receiptURL := strings.TrimSpace(os.Getenv("EXPO_PUSH_RECEIPT_API_URL"))
-	if receiptURL == "" {
-		receiptURL = defaultExpoPushReceiptAPIURL
+	if err := a.db.SetPonderHookSecret(ctx, hookID, secret); err != nil {
+		return err
 	}
-	return receiptURL
-}
 
-func expoPushReceiptDelay() time.Duration {
-	rawValue := strings.TrimSpace(os.Getenv("EXPO_PUSH_RECEIPT_DELAY_SECONDS"))
-	if rawValue == "" {
-		return 30 * time.Second
-	}
-	seconds, err := strconv.Atoi(rawValue)
-	if err != nil || seconds < 0 {
-		return 30 * time.Second
+	secretReq, err := http.NewRequestWithContext(ctx, http.MethodPatch, fmt.Sprintf("%s/hooks", ponderUrl), bytes.NewReader(reqBody))
+	if err != nil {
+		return errors.Join(fmt.Errorf("error creating ponder hook secret request: %w", err), a.db.DeletePonderHookSecret(ctx, hookID))
 	}
-	return time.Duration(seconds) * time.Second
-}
+	secretReq.Header.Add("X-Admin-Key", os.Getenv("PONDER_KEY"))
 
-func expoDetailsError(details map[string]any) string {
-	if len(details) == 0 {
-		return ""
+	res, err := http.DefaultClient.Do(secretReq)
+	if err != nil {
+		return errors.Join(fmt.Errorf("error sending ponder hook secret request: %w", err), a.db.DeletePonderHookSecret(ctx, hookID))
 	}
-	if value, ok := details["error"].(string); ok {
-		return strings.TrimSpace(value)
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		resBody, _ := io.ReadAll(res.Body)
+		return errors.Join(
+			fmt.Errorf("ponder hook %d not re-keyed: status %d: %s", hookID, res.StatusCode, strings.TrimSpace(string(resBody))),
+			a.db.DeletePonderHookSecret(ctx, hookID),
+		)
 	}
-	return ""
-}
 
-func isExpoDeviceNotRegistered(details map[string]any) bool {
-	return expoDetailsError(details) == expoDeviceNotRegistered
+	return nil
 }
 
-func parseExpoPushTickets(raw json.RawMessage) ([]expoPushTicket, error) {
-	trimmed := bytes.TrimSpace(raw)
-	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
-		return nil, nil
-	}
-	if trimmed[0] == '[' {
-		var tickets []expoPushTicket
-		if err := json.Unmarshal(trimmed, &tickets); err != nil {
-			return nil, err
-		}
-		return tickets, nil
-	}
-
-	var ticket expoPushTicket
-	if err := json.Unmarshal(trimmed, &ticket); err != nil {
-		return nil, err
+// RekeyPonderHooks gives every hook created before callbacks were signed a
+// secret, so PONDER_ALLOW_UNSIGNED_CALLBACKS can stay off.
+func (a *AppService) RekeyPonderHooks(ctx context.Context) error {
+	if ponderServerBaseURL() == "" {
+		a.logger.Warnf(ctx, "skipping ponder hook re-key on startup: PONDER_SERVER_BASE_URL not configured")
+		return nil
 	}
-	return []expoPushTicket{ticket}, nil
-}
 
-func (a *AppService) deactivatePushTokenAndCleanup(ctx context.Context, token string, reason string) {
-	disabledAddresses, err := a.db.DeactivateMobilePushSubscriptionsByToken(ctx, token, reason)
+	hookIDs, err := a.db.GetUnkeyedPonderHookIDs(ctx)
 	if err != nil {
-		a.logger.Logf("error deactivating mobile push subscriptions for dead Expo token: %s", err)
-		return
+		return err
 	}
 
-	for _, address := range disabledAddresses {
-		if err := a.deletePonderHooksForAddressIfUnused(ctx, address); err != nil {
-			a.logger.Logf("error cleaning up ponder hooks after deactivating dead Expo token for address %s: %s", address, err)
+	failures := 0
+	for _, hookID := range hookIDs {
+		if err := a.setPonderHookSecret(ctx, hookID); err != nil {
+			failures++
+			a.logger.Errorf(ctx, "error re-keying ponder hook %d: %s", hookID, err)
 		}
 	}
-}
-
-func (a *AppService) checkExpoPushReceiptAfterDelay(ticketID string, token string) {
-	delay := expoPushReceiptDelay()
-	if delay > 0 {
-		time.Sleep(delay)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	receipt, err := getExpoPushReceipt(ctx, ticketID)
-	if err != nil {
-		a.logger.Logf("error getting Expo push receipt %s: %s", ticketID, err)
-		return
-	}
-
-	errorCode := expoDetailsError(receipt.Details)
-	if err := a.db.MarkMobilePushNotificationTicketReceipt(ctx, ticketID, receipt.Status, receipt.Message, errorCode); err != nil {
-		a.logger.Logf("error marking Expo push receipt %s: %s", ticketID, err)
+	if len(hookIDs) > 0 {
+		a.logger.Infof(ctx, "re-keyed %d of %d unsigned ponder hooks", len(hookIDs)-failures, len(hookIDs))
 	}
 
-	if receipt.Status == "error" && errorCode == expoDeviceNotRegistered {
-		cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), 15*time.Second)
-		defer cleanupCancel()
-		a.deactivatePushTokenAndCleanup(cleanupCtx, token, pushDeleteReasonDeadToken)
-	}
+	return nil
 }
 
-func (a *AppService) handleExpoPushTicket(ctx context.Context, listener *structs.MobilePushSubscription, token string, ticket *expoPushTicket) {
-	if listener == nil || ticket == nil {
-		return
+func (a *AppService) deletePonderHooksForAddressIfUnused(ctx context.Context, address string) error {
+	hasActiveDependency, err := a.db.HasActivePonderNotificationDependency(ctx, address)
+	if err != nil {
+		return err
 	}
-
-	if ticket.Status == "error" && isExpoDeviceNotRegistered(ticket.Details) {
-		a.deactivatePushTokenAndCleanup(ctx, token, pushDeleteReasonDeadToken)
-		return
+	if hasActiveDependency {
+		return nil
 	}
 
-	if ticket.Status != "ok" || strings.TrimSpace(ticket.ID) == "" {
-		return
+	hookIDs, err := a.db.GetKnownPonderHookIDsForAddress(ctx, address)
+	if err != nil {
+		return err
 	}
 
-	if err := a.db.AddMobilePushNotificationTicket(ctx, listener.Owner, token, listener.Address, ticket.ID); err != nil {
-		a.logger.Logf("error storing Expo push ticket %s for user %s address %s: %s", ticket.ID, listener.Owner, listener.Address, err)
-		return
+	for _, hookID := range hookIDs {
+		if err := a.deletePonderHook(ctx, hookID); err != nil {
+			return err
+		}
+		if err := a.db.ClearMobilePushSubscriptionPonderHook(ctx, hookID); err != nil {
+			return err
+		}
 	}
 
-	go a.checkExpoPushReceiptAfterDelay(ticket.ID, token)
+	return nil
 }
 
 func resolvePushSyncState(req structs.PushSubscriptionSyncRequest) (*bool, *bool, bool) {
@@ -340,14 +284,14 @@ func (a *AppService) AddPonderMerchantSubscription(w http.ResponseWriter, r *htt
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		a.logger.Logf("error reading request body from user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading request body from user %s: %s", *userDid, err)
 		return
 	}
 
 	var req structs.PonderSubscriptionRequest
 	err = json.Unmarshal(body, &req)
 	if err != nil {
-		fmt.Println(err)
+		a.logger.Infof(r.Context(), "invalid ponder subscription request: %s", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -364,7 +308,7 @@ func (a *AppService) AddPonderMerchantSubscription(w http.ResponseWriter, r *htt
 			w.Write([]byte(err.Error()))
 			return
 		}
-		a.logger.Logf("error checking verified email for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error checking verified email for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -376,7 +320,7 @@ func (a *AppService) AddPonderMerchantSubscription(w http.ResponseWriter, r *htt
 
 	user, err := a.db.GetUserById(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting user %s details: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting user %s details: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -388,7 +332,7 @@ func (a *AppService) AddPonderMerchantSubscription(w http.ResponseWriter, r *htt
 
 	wallets, err := a.db.GetWalletsByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting wallets for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting wallets for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -396,14 +340,14 @@ func (a *AppService) AddPonderMerchantSubscription(w http.ResponseWriter, r *htt
 	userWallets := allowedWalletAddresses(wallets)
 
 	if !userWallets[strings.ToLower(strings.TrimSpace(req.Address))] {
-		a.logger.Logf("user %s attempted to add ponder subscription for unowned address %s", *userDid, req.Address)
+		a.logger.Warnf(r.Context(), "user %s attempted to add ponder subscription for unowned address %s", *userDid, req.Address)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	newSubscription, err := a.createPonderHook(r.Context(), req.Address)
 	if err != nil {
-		a.logger.Logf("error creating ponder subscription: %s", err)
+		a.logger.Errorf(r.Context(), "error creating ponder subscription: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -418,7 +362,7 @@ func (a *AppService) AddPonderMerchantSubscription(w http.ResponseWriter, r *htt
 
 	err = a.db.AddPonderSubscription(r.Context(), &formattedSubscription)
 	if err != nil {
-		a.logger.Logf("error adding new ponder subscription: %s", err)
+		a.logger.Errorf(r.Context(), "error adding new ponder subscription: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -435,7 +379,7 @@ func (a *AppService) SyncPonderPushSubscriptions(w http.ResponseWriter, r *http.
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading push subscription sync body for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading push subscription sync body for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -451,10 +395,15 @@ func (a *AppService) SyncPonderPushSubscriptions(w http.ResponseWriter, r *http.
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	req.Provider = push.NormalizeProvider(req.Provider)
+	if !a.pushProviders().Has(req.Provider) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 
 	wallets, err := a.db.GetWalletsByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting wallets for push sync user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting wallets for push sync user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -486,7 +435,7 @@ func (a *AppService) SyncPonderPushSubscriptions(w http.ResponseWriter, r *http.
 
 	existingSubscriptions, err := a.db.GetMobilePushSubscriptionsByOwnerToken(r.Context(), *userDid, req.Token)
 	if err != nil {
-		a.logger.Logf("error getting existing push subscriptions for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting existing push subscriptions for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -534,10 +483,10 @@ func (a *AppService) SyncPonderPushSubscriptions(w http.ResponseWriter, r *http.
 		if err != nil {
 			for _, hookID := range createdHookIDsByAddress {
 				if deleteErr := a.deletePonderHook(r.Context(), hookID); deleteErr != nil {
-					a.logger.Logf("error deleting orphaned ponder hook %d after failed push hook creation for user %s: %s", hookID, *userDid, deleteErr)
+					a.logger.Errorf(r.Context(), "error deleting orphaned ponder hook %d after failed push hook creation for user %s: %s", hookID, *userDid, deleteErr)
 				}
 			}
-			a.logger.Logf("error creating ponder hook for push subscription user %s address %s: %s", *userDid, address, err)
+			a.logger.Errorf(r.Context(), "error creating ponder hook for push subscription user %s address %s: %s", *userDid, address, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -548,6 +497,7 @@ func (a *AppService) SyncPonderPushSubscriptions(w http.ResponseWriter, r *http.
 		r.Context(),
 		*userDid,
 		req.Token,
+		req.Provider,
 		syncAddresses,
 		req.InstallationID,
 		createdHookIDsByAddress,
@@ -558,10 +508,10 @@ func (a *AppService) SyncPonderPushSubscriptions(w http.ResponseWriter, r *http.
 	if err != nil {
 		for _, hookID := range createdHookIDsByAddress {
 			if deleteErr := a.deletePonderHook(r.Context(), hookID); deleteErr != nil {
-				a.logger.Logf("error deleting orphaned ponder hook %d after failed push sync for user %s: %s", hookID, *userDid, deleteErr)
+				a.logger.Errorf(r.Context(), "error deleting orphaned ponder hook %d after failed push sync for user %s: %s", hookID, *userDid, deleteErr)
 			}
 		}
-		a.logger.Logf("error syncing push subscriptions for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error syncing push subscriptions for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -569,9 +519,9 @@ func (a *AppService) SyncPonderPushSubscriptions(w http.ResponseWriter, r *http.
 	for address, hookID := range createdHookIDsByAddress {
 		if err := a.db.SetMobilePushSubscriptionPonderHook(r.Context(), *userDid, req.Token, address, hookID); err != nil {
 			if deleteErr := a.deletePonderHook(r.Context(), hookID); deleteErr != nil {
-				a.logger.Logf("error deleting orphaned ponder hook %d after failed hook-id sync for user %s: %s", hookID, *userDid, deleteErr)
+				a.logger.Errorf(r.Context(), "error deleting orphaned ponder hook %d after failed hook-id sync for user %s: %s", hookID, *userDid, deleteErr)
 			}
-			a.logger.Logf("error recording ponder hook %d for push subscription user %s address %s: %s", hookID, *userDid, address, err)
+			a.logger.Errorf(r.Context(), "error recording ponder hook %d for push subscription user %s address %s: %s", hookID, *userDid, address, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -579,7 +529,7 @@ func (a *AppService) SyncPonderPushSubscriptions(w http.ResponseWriter, r *http.
 
 	for _, address := range disabledAddresses {
 		if err := a.deletePonderHooksForAddressIfUnused(r.Context(), address); err != nil {
-			a.logger.Logf("error cleaning up ponder hooks for disabled push address %s: %s", address, err)
+			a.logger.Errorf(r.Context(), "error cleaning up ponder hooks for disabled push address %s: %s", address, err)
 		}
 	}
 
@@ -604,14 +554,14 @@ func (a *AppService) GetPonderPushSubscriptions(w http.ResponseWriter, r *http.R
 		subscriptions, err = a.db.GetMobilePushSubscriptionsByUser(r.Context(), *userDid)
 	}
 	if err != nil {
-		a.logger.Logf("error getting mobile push subscriptions for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting mobile push subscriptions for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	body, err := json.Marshal(subscriptions)
 	if err != nil {
-		a.logger.Logf("error marshalling mobile push subscriptions for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error marshalling mobile push subscriptions for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -635,14 +585,14 @@ func (a *AppService) DeletePonderPushSubscription(w http.ResponseWriter, r *http
 
 	subscription, err := a.db.GetMobilePushSubscription(r.Context(), subscriptionID, *userDid)
 	if err != nil {
-		a.logger.Logf("error getting mobile push subscription %d for user %s: %s", subscriptionID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting mobile push subscription %d for user %s: %s", subscriptionID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	address, err := a.db.DeleteMobilePushSubscription(r.Context(), subscriptionID, *userDid)
 	if err != nil {
-		a.logger.Logf("error deleting mobile push subscription %d for user %s: %s", subscriptionID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error deleting mobile push subscription %d for user %s: %s", subscriptionID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -651,7 +601,7 @@ func (a *AppService) DeletePonderPushSubscription(w http.ResponseWriter, r *http
 	}
 
 	if err := a.deletePonderHooksForAddressIfUnused(r.Context(), address); err != nil {
-		a.logger.Logf("error cleaning up ponder hooks after deleting push subscription %d for user %s: %s", subscriptionID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error cleaning up ponder hooks after deleting push subscription %d for user %s: %s", subscriptionID, *userDid, err)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -672,7 +622,7 @@ func (a *AppService) DeletePonderMerchantSubscription(w http.ResponseWriter, r *
 
 	subscription, err := a.db.GetPonderSubscription(r.Context(), hookId)
 	if err != nil {
-		a.logger.Logf("error getting ponder subscription for id %d: %s", hookId, err)
+		a.logger.Errorf(r.Context(), "error getting ponder subscription for id %d: %s", hookId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -689,7 +639,7 @@ func (a *AppService) DeletePonderMerchantSubscription(w http.ResponseWriter, r *
 	}
 
 	if err := a.deletePonderHooksForAddressIfUnused(r.Context(), subscription.Address); err != nil {
-		a.logger.Logf("error cleaning up ponder hooks after deleting subscription %d for user %s: %s", hookId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error cleaning up ponder hooks after deleting subscription %d for user %s: %s", hookId, *userDid, err)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -704,14 +654,14 @@ func (a *AppService) GetPonderSubscriptions(w http.ResponseWriter, r *http.Reque
 
 	subscriptions, err := a.db.GetPonderSubscriptionsByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting ponder subscriptions for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting ponder subscriptions for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	body, err := json.Marshal(subscriptions)
 	if err != nil {
-		a.logger.Logf("error marshalling ponder subscriptions for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error marshalling ponder subscriptions for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -739,15 +689,26 @@ func (a *AppService) PonderHookHandler(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading ponder hook body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading ponder hook body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	secret, err := a.ponderHookSecret(r)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error getting ponder hook secret: %s", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if !a.authenticatePonderCallback(r, body, secret) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
 	tx := structs.PonderHookData{}
 	err = json.Unmarshal(body, &tx)
 	if err != nil {
-		a.logger.Logf("error unmarshalling ponder txs into hook data: %s", err)
+		a.logger.Errorf(r.Context(), "error unmarshalling ponder txs into hook data: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -755,23 +716,29 @@ func (a *AppService) PonderHookHandler(w http.ResponseWriter, r *http.Request) {
 		tx.ChainID = a.activeChainID()
 	}
 
-	sender := utils.NewEmailSender()
-	if sender == nil {
-		a.logger.Logf("error initializing new email sender: %s", err)
+	fresh, release, err := a.claimPonderCallbackEvent(r.Context(), ponderCallbackSourceTransfer, tx.ChainID, tx.Hash, tx.LogIndex)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error deduping ponder callback for tx %s: %s", tx.Hash, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	if !fresh {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
 
 	formattedAmount, err := utils.FormatTokenAmountFromStrings(tx.Amount, os.Getenv("TOKEN_DECIMALS"), 2)
 	if err != nil {
-		a.logger.Logf("error formatting ponder transaction amount %s: %s", tx.Amount, err)
+		a.logger.Errorf(r.Context(), "error formatting ponder transaction amount %s: %s", tx.Amount, err)
+		release()
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	listeners, err := a.db.GetPonderSubscriptions(r.Context(), tx.To)
 	if err != nil {
-		a.logger.Logf("error getting ponder subscriptions for %s: %s", tx.To, err)
+		a.logger.Errorf(r.Context(), "error getting ponder subscriptions for %s: %s", tx.To, err)
+		release()
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -779,79 +746,36 @@ func (a *AppService) PonderHookHandler(w http.ResponseWriter, r *http.Request) {
 	for _, l := range listeners {
 		w, err := a.db.GetWalletByUserAndAddress(r.Context(), l.Owner, l.Address)
 		if err != nil {
-			a.logger.Logf("error getting wallet for user %s, address %s while sending tx receipt email: %s", l.Owner, l.Address, err)
+			a.logger.Errorf(r.Context(), "error getting wallet for user %s, address %s while sending tx receipt email: %s", l.Owner, l.Address, err)
 			continue
 		}
 
-		subjectTail := fmt.Sprintf("- %s", tx.Hash[:6])
-		toLine := tx.To
-
-		if w.Name != "" {
-			subjectTail = fmt.Sprintf("to %s %s", w.Name, subjectTail)
-			toLine = fmt.Sprintf("%s (%s)", w.Name, tx.To)
+		err = a.Notify(r.Context(), &Notification{
+			Category: structs.NotificationCategoryTransactions,
+			UserId:   l.Owner,
+			Email: &NotificationEmail{
+				To:       strings.TrimSpace(string(l.Data)),
+				ToName:   "Merchant",
+				Template: "transaction_received",
+				Data: emails.Data{
+					"Amount":     formattedAmount,
+					"WalletName": w.Name,
+					"ToAddress":  tx.To,
+					"From":       tx.From,
+					"Hash":       tx.Hash,
+					"HashPrefix": tx.Hash[:6],
+				},
+				FromName: "SFLuv Transactions",
+			},
+		})
+		if err != nil {
+			a.logger.Errorf(r.Context(), "error sending transaction receipt email for user %s, address %s: %s", l.Owner, l.Address, err)
 		}
-
-		subject := fmt.Sprintf("%s $SFLuv Incoming %s", formattedAmount, subjectTail)
-		sections := fmt.Sprintf(`
-            <tr>
-              <td style="padding:24px 28px 8px;">
-                <p style="margin:0 0 8px; font-size:14px; color:#6b7280;">Summary</p>
-                <p style="margin:0; font-size:18px; font-weight:600; color:#111827;">Value: %s SFLuv</p>
-              </td>
-            </tr>
-            <tr>
-              <td style="padding:0 28px 24px;">
-                <table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-                  <tr>
-                    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:120px;">From</td>
-                    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-                  </tr>
-                  <tr>
-                    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">To</td>
-                    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-                  </tr>
-                  <tr>
-                    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Hash</td>
-                    <td style="padding:12px 0; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-                  </tr>
-                </table>
-              </td>
-            </tr>
-            <tr>
-              <td style="padding:0 28px 24px;">
-                <div style="background-color:#f9fafb; border-radius:12px; padding:14px 16px; font-size:12px; color:#6b7280;">
-                  If you did not expect this transaction, please contact the SFLuv team.
-                </div>
-              </td>
-            </tr>`,
-			utils.EscapeEmailHTML(formattedAmount),
-			utils.EscapeEmailHTML(tx.From),
-			utils.EscapeEmailHTML(toLine),
-			utils.EscapeEmailHTML(tx.Hash),
-		)
-		htmlContent := utils.BuildStyledEmailWithSections(
-			"SFLuv Transaction Alert",
-			"A new transaction has been recorded.",
-			sections,
-			"SFLuv · Transaction Notifications",
-		)
-
-		err = sender.SendEmail(
-			strings.TrimSpace(string(l.Data)),
-			"Merchant",
-			subject,
-			htmlContent,
-			utils.NotificationFromEmail(),
-			"SFLuv Transactions",
-		)
-	}
-	if err != nil {
-		a.logger.Logf("error sending transaction receipt email: %s", err.Error())
 	}
 
 	pushListeners, err := a.db.GetMobilePushSubscriptionsByAddress(r.Context(), tx.To)
 	if err != nil {
-		a.logger.Logf("error getting mobile push subscriptions for %s: %s", tx.To, err)
+		a.logger.Errorf(r.Context(), "error getting mobile push subscriptions for %s: %s", tx.To, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -859,7 +783,7 @@ func (a *AppService) PonderHookHandler(w http.ResponseWriter, r *http.Request) {
 	for _, listener := range pushListeners {
 		wallet, walletErr := a.db.GetWalletByUserAndAddress(r.Context(), listener.Owner, listener.Address)
 		if walletErr != nil {
-			a.logger.Logf("error getting wallet for push notification user %s, address %s: %s", listener.Owner, listener.Address, walletErr)
+			a.logger.Errorf(r.Context(), "error getting wallet for push notification user %s, address %s: %s", listener.Owner, listener.Address, walletErr)
 			continue
 		}
 
@@ -867,154 +791,27 @@ func (a *AppService) PonderHookHandler(w http.ResponseWriter, r *http.Request) {
 		title := fmt.Sprintf("SFLuv received to %s", accountLabel)
 		body := fmt.Sprintf("%s SFLUV", formattedAmount)
 
-		token := strings.TrimSpace(string(listener.Data))
-		ticket, pushErr := sendExpoPushNotification(r.Context(), token, title, body, map[string]string{
-			"hash":     tx.Hash,
-			"chain_id": strconv.FormatInt(tx.ChainID, 10),
-			"to":       tx.To,
-			"from":     tx.From,
-			"amount":   formattedAmount,
-			"address":  listener.Address,
+		pushErr := a.Notify(r.Context(), &Notification{
+			Category: structs.NotificationCategoryTransactions,
+			UserId:   listener.Owner,
+			Push: &NotificationPush{
+				Subscriptions: []*structs.MobilePushSubscription{listener},
+				Title:         title,
+				Body:          body,
+				Data: map[string]string{
+					"hash":     tx.Hash,
+					"chain_id": strconv.FormatInt(tx.ChainID, 10),
+					"to":       tx.To,
+					"from":     tx.From,
+					"amount":   formattedAmount,
+					"address":  listener.Address,
+				},
+			},
 		})
-		a.handleExpoPushTicket(r.Context(), listener, token, ticket)
 		if pushErr != nil {
-			a.logger.Logf("error sending Expo push notification for user %s, address %s: %s", listener.Owner, listener.Address, pushErr)
+			a.logger.Errorf(r.Context(), "error sending push notification for user %s, address %s: %s", listener.Owner, listener.Address, pushErr)
 		}
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
-
-func sendExpoPushNotification(ctx context.Context, token string, title string, body string, data map[string]string) (*expoPushTicket, error) {
-	if strings.TrimSpace(token) == "" {
-		return nil, fmt.Errorf("empty Expo push token")
-	}
-	if utils.NotificationTestModeEnabled() {
-		if _, err := utils.WriteTestPushNotification(token, title, body, data); err != nil {
-			return nil, err
-		}
-		return &expoPushTicket{Status: "ok"}, nil
-	}
-
-	pushURL := strings.TrimSpace(os.Getenv("EXPO_PUSH_API_URL"))
-	if pushURL == "" {
-		pushURL = defaultExpoPushAPIURL
-	}
-
-	payload := map[string]any{
-		"to":    token,
-		"title": title,
-		"body":  body,
-		"sound": "default",
-		"data":  data,
-	}
-
-	reqBody, err := json.Marshal(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pushURL, bytes.NewReader(reqBody))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	bodyBytes, readErr := io.ReadAll(res.Body)
-	if readErr != nil {
-		return nil, readErr
-	}
-
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return nil, fmt.Errorf("expo push api returned %d: %s", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
-	}
-
-	var sendResponse expoPushSendResponse
-	if err := json.Unmarshal(bodyBytes, &sendResponse); err != nil {
-		return nil, fmt.Errorf("error parsing Expo push response: %w", err)
-	}
-
-	tickets, err := parseExpoPushTickets(sendResponse.Data)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing Expo push ticket: %w", err)
-	}
-	if len(tickets) == 0 {
-		if len(sendResponse.Errors) > 0 {
-			ticket := sendResponse.Errors[0]
-			return &ticket, fmt.Errorf("expo push api returned error: %s", ticket.Message)
-		}
-		return nil, fmt.Errorf("expo push api returned no ticket")
-	}
-
-	ticket := tickets[0]
-	if ticket.Status == "error" {
-		message := strings.TrimSpace(ticket.Message)
-		if message == "" {
-			message = "unknown Expo push ticket error"
-		}
-		return &ticket, fmt.Errorf("%s", message)
-	}
-
-	return &ticket, nil
-}
-
-func getExpoPushReceipt(ctx context.Context, ticketID string) (*expoPushReceipt, error) {
-	ticketID = strings.TrimSpace(ticketID)
-	if ticketID == "" {
-		return nil, fmt.Errorf("empty Expo push ticket id")
-	}
-
-	payload := map[string]any{
-		"ids": []string{ticketID},
-	}
-
-	reqBody, err := json.Marshal(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, expoPushReceiptURL(), bytes.NewReader(reqBody))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	bodyBytes, readErr := io.ReadAll(res.Body)
-	if readErr != nil {
-		return nil, readErr
-	}
-
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return nil, fmt.Errorf("expo push receipt api returned %d: %s", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
-	}
-
-	var receiptResponse expoPushReceiptResponse
-	if err := json.Unmarshal(bodyBytes, &receiptResponse); err != nil {
-		return nil, fmt.Errorf("error parsing Expo push receipt response: %w", err)
-	}
-	if len(receiptResponse.Errors) > 0 {
-		errTicket := receiptResponse.Errors[0]
-		return nil, fmt.Errorf("expo push receipt api returned error: %s", errTicket.Message)
-	}
-
-	receipt, ok := receiptResponse.Data[ticketID]
-	if !ok {
-		return nil, fmt.Errorf("Expo push receipt %s not found", ticketID)
-	}
-
-	return &receipt, nil
-}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/metrics"
+	"github.com/SFLuv/app/backend/push"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+)
+
+var defaultPushProviders = push.NewRegistry(push.NewExpoProvider())
+
+// pushProviders falls back to Expo only for services built without
+// SetPushProviders, like the init sync service.
+func (a *AppService) pushProviders() *push.Registry {
+	if a.push != nil {
+		return a.push
+	}
+	return defaultPushProviders
+}
+
+func pushReceiptDelay() time.Duration {
+	rawValue := strings.TrimSpace(os.Getenv("EXPO_PUSH_RECEIPT_DELAY_SECONDS"))
+	if rawValue == "" {
+		return 30 * time.Second
+	}
+	seconds, err := strconv.Atoi(rawValue)
+	if err != nil || seconds < 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sendPushNotification delivers through the subscription's provider, or
+// writes the push to disk in notification test mode.
+func (a *AppService) sendPushNotification(ctx context.Context, listener *structs.MobilePushSubscription, title string, body string, data map[string]string) (*push.Ticket, error) {
+	token := strings.TrimSpace(listener.Token)
+	if token == "" {
+		token = strings.TrimSpace(string(listener.Data))
+	}
+	if utils.NotificationTestModeEnabled() {
+		if _, err := utils.WriteTestPushNotification(token, title, body, data); err != nil {
+			return nil, err
+		}
+		return &push.Ticket{Status: push.StatusOK}, nil
+	}
+
+	provider, err := a.pushProviders().Get(listener.Provider)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := provider.Send(ctx, push.Message{
+		Token: token,
+		Title: title,
+		Body:  body,
+		Data:  data,
+	})
+	metrics.PushTicket(provider.Name(), pushOutcome(ticket, err))
+	return ticket, err
+}
+
+// pushOutcome labels a ticket or receipt by its error code when it has one,
+// so dead tokens and provider errors can be told apart.
+func pushOutcome(ticket *push.Ticket, err error) string {
+	if ticket != nil && ticket.Status == push.StatusError && ticket.ErrorCode != "" {
+		return ticket.ErrorCode
+	}
+	if err != nil || (ticket != nil && ticket.Status == push.StatusError) {
+		return push.StatusError
+	}
+	return push.StatusOK
+}
+
+func (a *AppService) deactivatePushTokenAndCleanup(ctx context.Context, token string, reason string) {
+	disabledAddresses, err := a.db.DeactivateMobilePushSubscriptionsByToken(ctx, token, reason)
+	if err != nil {
+		a.logger.Errorf(ctx, "error deactivating mobile push subscriptions for dead push token: %s", err)
+		return
+	}
+
+	for _, address := range disabledAddresses {
+		if err := a.deletePonderHooksForAddressIfUnused(ctx, address); err != nil {
+			a.logger.Errorf(ctx, "error cleaning up ponder hooks after deactivating dead push token for address %s: %s", address, err)
+		}
+	}
+}
+
+// checkPushReceiptAfterDelay gives up without checking if shutdown begins
+// during the delay; the ticket stays stored without a receipt.
+func (a *AppService) checkPushReceiptAfterDelay(stop context.Context, provider push.ReceiptProvider, ticketID string, token string) {
+	delay := pushReceiptDelay()
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-stop.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	receipt, err := provider.Receipt(ctx, ticketID)
+	if err != nil {
+		a.logger.Errorf(ctx, "error getting %s push receipt %s: %s", provider.Name(), ticketID, err)
+		return
+	}
+	metrics.PushReceipt(provider.Name(), pushOutcome(&push.Ticket{Status: receipt.Status, ErrorCode: receipt.ErrorCode}, nil))
+
+	if err := a.db.MarkMobilePushNotificationTicketReceipt(ctx, ticketID, receipt.Status, receipt.Message, receipt.ErrorCode); err != nil {
+		a.logger.Errorf(ctx, "error marking %s push receipt %s: %s", provider.Name(), ticketID, err)
+	}
+
+	if receipt.Status == push.StatusError && receipt.ErrorCode == push.ErrorDeviceNotRegistered {
+		cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cleanupCancel()
+		a.deactivatePushTokenAndCleanup(cleanupCtx, token, pushDeleteReasonDeadToken)
+	}
+}
+
+// handlePushTicket retires dead tokens right away and, for providers that
+// report delivery later, stores the ticket and checks its receipt.
+func (a *AppService) handlePushTicket(ctx context.Context, listener *structs.MobilePushSubscription, ticket *push.Ticket) {
+	if listener == nil || ticket == nil {
+		return
+	}
+	token := strings.TrimSpace(listener.Token)
+
+	if ticket.Status == push.StatusError && ticket.ErrorCode == push.ErrorDeviceNotRegistered {
+		a.deactivatePushTokenAndCleanup(ctx, token, pushDeleteReasonDeadToken)
+		return
+	}
+
+	if ticket.Status != push.StatusOK || strings.TrimSpace(ticket.ID) == "" {
+		return
+	}
+
+	provider, err := a.pushProviders().Get(listener.Provider)
+	if err != nil {
+		return
+	}
+	receiptProvider, ok := provider.(push.ReceiptProvider)
+	if !ok {
+		return
+	}
+
+	if err := a.db.AddMobilePushNotificationTicket(ctx, listener.Owner, token, listener.Address, receiptProvider.Name(), ticket.ID); err != nil {
+		a.logger.Errorf(ctx, "error storing %s push ticket %s for user %s address %s: %s", receiptProvider.Name(), ticket.ID, listener.Owner, listener.Address, err)
+		return
+	}
+
+	a.lifecycle.Go("push receipt check", func(ctx context.Context) {
+		a.checkPushReceiptAfterDelay(ctx, receiptProvider, ticket.ID, token)
+	})
+}
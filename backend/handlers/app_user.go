@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 
@@ -25,13 +24,13 @@ func (a *AppService) AddUser(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusConflict)
 			return
 		}
-		a.logger.Logf("error adding user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error adding user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if _, err := a.SyncPrivyLinkedEmailsForUser(r.Context(), *userDid); err != nil {
-		a.logger.Logf("error syncing Privy linked emails for new user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error syncing Privy linked emails for new user %s: %s", *userDid, err)
 	}
 
 	a.recordClientVersionObservation(r.Context(), *userDid, "user_create", r)
@@ -52,7 +51,7 @@ func (a *AppService) GetUserAuthed(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err != nil {
-		a.logger.Logf("error getting user by id %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting user by id %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -67,28 +66,28 @@ func (a *AppService) GetUserAuthed(w http.ResponseWriter, r *http.Request) {
 
 	wallets, err := a.db.GetWalletsByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting wallets for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting wallets for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	locations, err := a.db.GetLocationsByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting locations for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting locations for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	contacts, err := a.db.GetContacts(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting contacts for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting contacts for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	affiliate, err := a.db.GetAffiliateByUser(r.Context(), *userDid)
 	if err != nil && err != pgx.ErrNoRows {
-		a.logger.Logf("error getting affiliate info for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting affiliate info for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -98,7 +97,7 @@ func (a *AppService) GetUserAuthed(w http.ResponseWriter, r *http.Request) {
 
 	proposer, err := a.db.GetProposerByUser(r.Context(), *userDid)
 	if err != nil && err != pgx.ErrNoRows {
-		a.logger.Logf("error getting proposer info for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting proposer info for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -108,7 +107,7 @@ func (a *AppService) GetUserAuthed(w http.ResponseWriter, r *http.Request) {
 
 	improver, err := a.db.GetImproverByUser(r.Context(), *userDid)
 	if err != nil && err != pgx.ErrNoRows {
-		a.logger.Logf("error getting improver info for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting improver info for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -118,14 +117,14 @@ func (a *AppService) GetUserAuthed(w http.ResponseWriter, r *http.Request) {
 
 	issuer, err := a.db.GetIssuerByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting issuer info for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting issuer info for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	supervisor, err := a.db.GetSupervisorByUser(r.Context(), *userDid)
 	if err != nil && err != pgx.ErrNoRows {
-		a.logger.Logf("error getting supervisor info for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting supervisor info for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -147,7 +146,7 @@ func (a *AppService) GetUserAuthed(w http.ResponseWriter, r *http.Request) {
 
 	bytes, err := json.Marshal(response)
 	if err != nil {
-		a.logger.Logf("error marshalling user response struct:\n  %#v\nfor user %s: %s", response, *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error marshalling user response struct:\n  %#v\nfor user %s: %s", response, *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -166,7 +165,7 @@ func (a *AppService) UpdateUserInfo(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading add wallet request body from user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading add wallet request body from user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -181,7 +180,7 @@ func (a *AppService) UpdateUserInfo(w http.ResponseWriter, r *http.Request) {
 
 	err = a.db.UpdateUserInfo(r.Context(), &user)
 	if err != nil {
-		a.logger.Logf("error updating user info with struct:\n  %#v\nfor user %s: %s", user, *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error updating user info with struct:\n  %#v\nfor user %s: %s", user, *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -190,7 +189,6 @@ func (a *AppService) UpdateUserInfo(w http.ResponseWriter, r *http.Request) {
 }
 
 func (a *AppService) UpdateUserPayPalEth(w http.ResponseWriter, r *http.Request) {
-	fmt.Println("update paypal handler reached")
 	userDid := utils.GetDid(r)
 	if userDid == nil {
 		w.WriteHeader(http.StatusForbidden)
@@ -200,14 +198,14 @@ func (a *AppService) UpdateUserPayPalEth(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading paypal address from body from user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading paypal address from body from user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	err = a.db.UpdateUserPayPalEth(r.Context(), *userDid, body)
 	if err != nil {
-		a.logger.Logf("error updating user paypal address for user: %s", *userDid)
+		a.logger.Errorf(r.Context(), "error updating user paypal address for user: %s", *userDid)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -225,7 +223,7 @@ func (a *AppService) UpdateUserPrimaryWallet(w http.ResponseWriter, r *http.Requ
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading primary wallet body from user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading primary wallet body from user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -244,7 +242,7 @@ func (a *AppService) UpdateUserPrimaryWallet(w http.ResponseWriter, r *http.Requ
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error updating primary wallet for user %s: %s", *userDid, errMsg)
+		a.logger.Errorf(r.Context(), "error updating primary wallet for user %s: %s", *userDid, errMsg)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SFLuv/app/backend/emails"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
 )
@@ -24,44 +26,22 @@ func (a *AppService) appVerifyURL(token string) string {
 }
 
 func (a *AppService) sendUserEmailVerificationEmail(toEmail string, token string, expiresAt *time.Time) error {
-	emailSender := utils.NewEmailSender()
-	if emailSender == nil {
-		return fmt.Errorf("email sender is not configured")
-	}
-
 	verifyURL := a.appVerifyURL(token)
-	expiryLabel := "30 minutes"
+	expiryLabel := ""
 	if expiresAt != nil {
 		expiryLabel = expiresAt.UTC().Format(time.RFC1123)
 	}
 
-	title := "Verify Your Email"
-	htmlContent := utils.BuildStyledEmail(
-		title,
-		"Complete verification to use this email for SFLuv notifications.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:160px;">Email</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Verification Link</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px;">
-      <a href="%s" style="color:#eb6c6c; text-decoration:none; font-weight:600;">Verify Email</a>
-    </td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Expires</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>
-</table>
-<p style="margin:14px 0 0; font-size:12px; color:#6b7280;">
-  If you did not request this, you can ignore this email.
-</p>`, utils.EscapeEmailHTML(toEmail), utils.EscapeEmailHTML(verifyURL), utils.EscapeEmailHTML(expiryLabel)),
-	)
-
-	return emailSender.SendEmail(toEmail, "SFLuv User", title, htmlContent, utils.NotificationFromEmail(), "SFLuv")
+	return a.Notify(context.Background(), &Notification{
+		Category: structs.NotificationCategoryAccount,
+		Email: &NotificationEmail{
+			To:       toEmail,
+			ToName:   "SFLuv User",
+			Template: "verify_email",
+			Data:     emails.Data{"Email": toEmail, "VerifyURL": verifyURL, "ExpiresAt": expiryLabel},
+			FromName: "SFLuv",
+		},
+	})
 }
 
 func (a *AppService) GetUserVerifiedEmails(w http.ResponseWriter, r *http.Request) {
@@ -73,7 +53,7 @@ func (a *AppService) GetUserVerifiedEmails(w http.ResponseWriter, r *http.Reques
 
 	records, err := a.db.GetUserVerifiedEmails(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting verified emails for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting verified emails for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -92,7 +72,7 @@ func (a *AppService) RequestUserEmailVerification(w http.ResponseWriter, r *http
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading verified email request body for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading verified email request body for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -116,13 +96,13 @@ func (a *AppService) RequestUserEmailVerification(w http.ResponseWriter, r *http
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error creating verified email request for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error creating verified email request for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if err := a.sendUserEmailVerificationEmail(record.Email, token, record.VerificationTokenExpiresAt); err != nil {
-		a.logger.Logf("error sending verification email to %s for user %s: %s", record.Email, *userDid, err)
+		a.logger.Errorf(r.Context(), "error sending verification email to %s for user %s: %s", record.Email, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -162,13 +142,13 @@ func (a *AppService) ResendUserEmailVerification(w http.ResponseWriter, r *http.
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error resending verification email for user %s email %s: %s", *userDid, emailID, err)
+		a.logger.Errorf(r.Context(), "error resending verification email for user %s email %s: %s", *userDid, emailID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if err := a.sendUserEmailVerificationEmail(record.Email, token, record.VerificationTokenExpiresAt); err != nil {
-		a.logger.Logf("error sending verification email during resend to %s for user %s: %s", record.Email, *userDid, err)
+		a.logger.Errorf(r.Context(), "error sending verification email during resend to %s for user %s: %s", record.Email, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -214,7 +194,7 @@ func (a *AppService) VerifyUserEmailToken(w http.ResponseWriter, r *http.Request
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error verifying email token: %s", err)
+		a.logger.Errorf(r.Context(), "error verifying email token: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -20,14 +20,14 @@ func (a *AppService) GetWalletsByUser(w http.ResponseWriter, r *http.Request) {
 
 	wallets, err := a.db.GetWalletsByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting wallets for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error getting wallets for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	bytes, err := json.Marshal(wallets)
 	if err != nil {
-		a.logger.Logf("error marshalling wallets struct:\n  %#v\nfor user %s: %s", wallets, *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error marshalling wallets struct:\n  %#v\nfor user %s: %s", wallets, *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -45,7 +45,7 @@ func (a *AppService) AddWallet(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading add wallet request body from user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading add wallet request body from user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
@@ -65,7 +65,7 @@ func (a *AppService) AddWallet(w http.ResponseWriter, r *http.Request) {
 
 	id, err := a.db.AddWallet(r.Context(), &wallet)
 	if err != nil {
-		a.logger.Logf("error adding wallet:\n  %#v\nfor user %s: %s", wallet, *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error adding wallet:\n  %#v\nfor user %s: %s", wallet, *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -73,10 +73,10 @@ func (a *AppService) AddWallet(w http.ResponseWriter, r *http.Request) {
 	if a.redeemer != nil && a.redeemer.IsEnabled() && !wallet.IsEoa && wallet.SmartIndex != nil && *wallet.SmartIndex == 0 {
 		hasApprovedLocation, err := a.db.UserHasAnyApprovedLocation(r.Context(), wallet.Owner)
 		if err != nil {
-			a.logger.Logf("error checking approved locations for user %s after wallet add: %s", wallet.Owner, err)
+			a.logger.Errorf(r.Context(), "error checking approved locations for user %s after wallet add: %s", wallet.Owner, err)
 		} else if hasApprovedLocation {
 			if err := a.redeemer.EnsureMerchantHasRedeemerWallet(r.Context(), wallet.Owner); err != nil {
-				a.logger.Logf("error auto-granting redeemer role for user %s after wallet add: %s", wallet.Owner, err)
+				a.logger.Errorf(r.Context(), "error auto-granting redeemer role for user %s after wallet add: %s", wallet.Owner, err)
 			}
 		}
 	}
@@ -95,7 +95,7 @@ func (a *AppService) UpdateWallet(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update wallet request body from user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading update wallet request body from user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -110,7 +110,7 @@ func (a *AppService) UpdateWallet(w http.ResponseWriter, r *http.Request) {
 
 	err = a.db.UpdateWallet(r.Context(), &wallet)
 	if err != nil {
-		a.logger.Logf("error updating wallet:\n  %#v\nfor user %s: %s", wallet, *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error updating wallet:\n  %#v\nfor user %s: %s", wallet, *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -133,7 +133,7 @@ func (a *AppService) LookupWalletOwnerByAddress(w http.ResponseWriter, r *http.R
 
 	lookup, err := a.db.GetWalletAddressOwnerLookup(r.Context(), address)
 	if err != nil {
-		a.logger.Logf("error looking up wallet owner by address %s: %s", address, err)
+		a.logger.Errorf(r.Context(), "error looking up wallet owner by address %s: %s", address, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -23,7 +23,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SFLuv/app/backend/bot"
 	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/emails"
+	"github.com/SFLuv/app/backend/photoproc"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
 	"github.com/jackc/pgx/v5"
@@ -268,6 +271,9 @@ func workflowHasBlockingPendingPayouts(workflow *structs.Workflow) bool {
 		if step.Status != "completed" {
 			return true
 		}
+		if step.ReviewStatus == structs.WorkflowStepReviewRejected {
+			continue
+		}
 		if step.Bounty == 0 {
 			return true
 		}
@@ -288,7 +294,7 @@ func workflowHasBlockingPendingPayouts(workflow *structs.Workflow) bool {
 	return workflow.ManagerPayoutError == nil || strings.TrimSpace(*workflow.ManagerPayoutError) == ""
 }
 
-func (a *AppService) waitForWorkflowPayoutTransferConfirmation(ctx context.Context, txHash string, walletAddress string, amount uint64) error {
+func (a *AppService) waitForWorkflowPayoutTransferConfirmation(ctx context.Context, payoutBot bot.IBot, txHash string, walletAddress string, amount uint64) error {
 	txHash = strings.TrimSpace(txHash)
 	if txHash == "" {
 		return fmt.Errorf("missing transfer transaction hash")
@@ -298,7 +304,7 @@ func (a *AppService) waitForWorkflowPayoutTransferConfirmation(ctx context.Conte
 	defer ticker.Stop()
 
 	for {
-		result, err := a.bot.bot.VerifyTransfer(ctx, txHash, walletAddress, amount)
+		result, err := payoutBot.VerifyTransfer(ctx, txHash, walletAddress, amount)
 		if err != nil {
 			return err
 		}
@@ -330,7 +336,8 @@ func (a *AppService) reconcileWorkflowStepPayoutByHash(ctx context.Context, work
 	if txHash == "" {
 		return false, false, nil
 	}
-	if chainID > 0 && chainID != a.activeChainID() {
+	payoutBot, ok := a.bot.botForChain(chainID)
+	if !ok {
 		return false, true, nil
 	}
 
@@ -341,7 +348,7 @@ func (a *AppService) reconcileWorkflowStepPayoutByHash(ctx context.Context, work
 
 	checkCtx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
-	result, err := a.bot.bot.VerifyTransfer(checkCtx, txHash, walletAddress, bounty)
+	result, err := payoutBot.VerifyTransfer(checkCtx, txHash, walletAddress, bounty)
 	if err != nil {
 		return false, false, err
 	}
@@ -372,7 +379,8 @@ func (a *AppService) reconcileWorkflowManagerPayoutByHash(ctx context.Context, w
 	if txHash == "" {
 		return false, false, nil
 	}
-	if chainID > 0 && chainID != a.activeChainID() {
+	payoutBot, ok := a.bot.botForChain(chainID)
+	if !ok {
 		return false, true, nil
 	}
 
@@ -383,7 +391,7 @@ func (a *AppService) reconcileWorkflowManagerPayoutByHash(ctx context.Context, w
 
 	checkCtx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
-	result, err := a.bot.bot.VerifyTransfer(checkCtx, txHash, walletAddress, bounty)
+	result, err := payoutBot.VerifyTransfer(checkCtx, txHash, walletAddress, bounty)
 	if err != nil {
 		return false, false, err
 	}
@@ -454,7 +462,7 @@ func (a *AppService) RequestProposerStatus(w http.ResponseWriter, r *http.Reques
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading proposer request body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading proposer request body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -477,31 +485,16 @@ func (a *AppService) RequestProposerStatus(w http.ResponseWriter, r *http.Reques
 			w.Write([]byte(err.Error()))
 			return
 		}
-		a.logger.Logf("error upserting proposer request for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error upserting proposer request for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	a.sendRoleRequestEmail(
-		"PROPOSER_ADMIN_EMAIL",
-		"New Proposer Request",
-		"A user has requested proposer status.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:140px;">User</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Organization</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Notification Email</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(proposer.UserId), utils.EscapeEmailHTML(proposer.Organization), utils.EscapeEmailHTML(proposer.Email)),
-	)
+	a.sendRoleRequestEmail("PROPOSER_ADMIN_EMAIL", "proposer_request", emails.Data{
+		"UserId":       proposer.UserId,
+		"Organization": proposer.Organization,
+		"Email":        proposer.Email,
+	})
 
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(proposer)
@@ -512,7 +505,7 @@ func (a *AppService) GetProposers(w http.ResponseWriter, r *http.Request) {
 	page, count := parsePageAndCount(r.URL.Query(), 20, 100)
 	proposers, err := a.db.GetProposers(r.Context(), search, page, count)
 	if err != nil {
-		a.logger.Logf("error getting proposers: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error getting proposers: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -525,7 +518,7 @@ func (a *AppService) UpdateProposer(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update proposer body: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error reading update proposer body: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -537,12 +530,14 @@ func (a *AppService) UpdateProposer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before, _ := a.db.GetProposerByUser(r.Context(), req.UserId)
 	proposer, err := a.db.UpdateProposer(r.Context(), &req)
 	if err != nil {
-		a.logger.Logf("error updating proposer %s: %s", req.UserId, err.Error())
+		a.logger.Errorf(r.Context(), "error updating proposer %s: %s", req.UserId, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditProposerUpdate, "user", req.UserId, before, proposer)
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(proposer)
@@ -558,7 +553,7 @@ func (a *AppService) RequestImproverStatus(w http.ResponseWriter, r *http.Reques
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading improver request body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading improver request body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -581,35 +576,17 @@ func (a *AppService) RequestImproverStatus(w http.ResponseWriter, r *http.Reques
 			w.Write([]byte(err.Error()))
 			return
 		}
-		a.logger.Logf("error upserting improver request for user %s: %s", *userDid, err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	a.sendRoleRequestEmail(
-		"IMPROVER_ADMIN_EMAIL",
-		"New Improver Request",
-		"A user has requested improver status.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:140px;">User</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">First Name</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Last Name</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Email</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(improver.UserId), utils.EscapeEmailHTML(improver.FirstName), utils.EscapeEmailHTML(improver.LastName), utils.EscapeEmailHTML(improver.Email)),
-	)
+		a.logger.Errorf(r.Context(), "error upserting improver request for user %s: %s", *userDid, err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	a.sendRoleRequestEmail("IMPROVER_ADMIN_EMAIL", "improver_request", emails.Data{
+		"UserId":    improver.UserId,
+		"FirstName": improver.FirstName,
+		"LastName":  improver.LastName,
+		"Email":     improver.Email,
+	})
 
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(improver)
@@ -620,7 +597,7 @@ func (a *AppService) GetImprovers(w http.ResponseWriter, r *http.Request) {
 	page, count := parsePageAndCount(r.URL.Query(), 20, 100)
 	improvers, err := a.db.GetImprovers(r.Context(), search, page, count)
 	if err != nil {
-		a.logger.Logf("error getting improvers: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error getting improvers: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -633,7 +610,7 @@ func (a *AppService) UpdateImprover(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update improver body: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error reading update improver body: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -645,12 +622,14 @@ func (a *AppService) UpdateImprover(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before, _ := a.db.GetImproverByUser(r.Context(), req.UserId)
 	improver, err := a.db.UpdateImprover(r.Context(), &req)
 	if err != nil {
-		a.logger.Logf("error updating improver %s: %s", req.UserId, err.Error())
+		a.logger.Errorf(r.Context(), "error updating improver %s: %s", req.UserId, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditImproverUpdate, "user", req.UserId, before, improver)
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(improver)
@@ -666,7 +645,7 @@ func (a *AppService) UpdateImproverPrimaryRewardsAccount(w http.ResponseWriter,
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading improver primary rewards account body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading improver primary rewards account body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -689,7 +668,7 @@ func (a *AppService) UpdateImproverPrimaryRewardsAccount(w http.ResponseWriter,
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error updating improver primary rewards account for user %s: %s", *userDid, errMsg)
+		a.logger.Errorf(r.Context(), "error updating improver primary rewards account for user %s: %s", *userDid, errMsg)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -708,7 +687,7 @@ func (a *AppService) RequestSupervisorStatus(w http.ResponseWriter, r *http.Requ
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading supervisor request body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading supervisor request body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -730,31 +709,16 @@ func (a *AppService) RequestSupervisorStatus(w http.ResponseWriter, r *http.Requ
 			w.Write([]byte(err.Error()))
 			return
 		}
-		a.logger.Logf("error upserting supervisor request for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error upserting supervisor request for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	a.sendRoleRequestEmail(
-		"SUPERVISOR_ADMIN_EMAIL",
-		"New Supervisor Request",
-		"A user has requested supervisor status.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:140px;">User</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Organization</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Notification Email</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(supervisor.UserId), utils.EscapeEmailHTML(supervisor.Organization), utils.EscapeEmailHTML(supervisor.Email)),
-	)
+	a.sendRoleRequestEmail("SUPERVISOR_ADMIN_EMAIL", "supervisor_request", emails.Data{
+		"UserId":       supervisor.UserId,
+		"Organization": supervisor.Organization,
+		"Email":        supervisor.Email,
+	})
 
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(supervisor)
@@ -765,7 +729,7 @@ func (a *AppService) GetSupervisors(w http.ResponseWriter, r *http.Request) {
 	page, count := parsePageAndCount(r.URL.Query(), 20, 100)
 	supervisors, err := a.db.GetSupervisors(r.Context(), search, page, count)
 	if err != nil {
-		a.logger.Logf("error getting supervisors: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error getting supervisors: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -778,7 +742,7 @@ func (a *AppService) UpdateSupervisor(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update supervisor body: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error reading update supervisor body: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -789,6 +753,7 @@ func (a *AppService) UpdateSupervisor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before, _ := a.db.GetSupervisorByUser(r.Context(), req.UserId)
 	supervisor, err := a.db.UpdateSupervisor(r.Context(), &req)
 	if err != nil {
 		errMsg := err.Error()
@@ -797,10 +762,11 @@ func (a *AppService) UpdateSupervisor(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error updating supervisor %s: %s", req.UserId, err.Error())
+		a.logger.Errorf(r.Context(), "error updating supervisor %s: %s", req.UserId, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditSupervisorUpdate, "user", req.UserId, before, supervisor)
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(supervisor)
@@ -816,7 +782,7 @@ func (a *AppService) UpdateSupervisorPrimaryRewardsAccount(w http.ResponseWriter
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading supervisor primary rewards account body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading supervisor primary rewards account body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -839,7 +805,7 @@ func (a *AppService) UpdateSupervisorPrimaryRewardsAccount(w http.ResponseWriter
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error updating supervisor primary rewards account for user %s: %s", *userDid, errMsg)
+		a.logger.Errorf(r.Context(), "error updating supervisor primary rewards account for user %s: %s", *userDid, errMsg)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -851,7 +817,7 @@ func (a *AppService) UpdateSupervisorPrimaryRewardsAccount(w http.ResponseWriter
 func (a *AppService) GetApprovedSupervisors(w http.ResponseWriter, r *http.Request) {
 	supervisors, err := a.db.GetApprovedSupervisors(r.Context())
 	if err != nil {
-		a.logger.Logf("error getting approved supervisors: %s", err)
+		a.logger.Errorf(r.Context(), "error getting approved supervisors: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -869,7 +835,7 @@ func (a *AppService) GetProposerWorkflowTemplates(w http.ResponseWriter, r *http
 
 	templates, err := a.db.GetWorkflowTemplatesForProposer(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting workflow templates for proposer %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting workflow templates for proposer %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -888,7 +854,7 @@ func (a *AppService) CreateProposerWorkflowTemplate(w http.ResponseWriter, r *ht
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading workflow template request body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading workflow template request body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -920,7 +886,7 @@ func (a *AppService) CreateProposerWorkflowTemplate(w http.ResponseWriter, r *ht
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error creating workflow template for proposer %s: %s", *userDid, errMsg)
+		a.logger.Errorf(r.Context(), "error creating workflow template for proposer %s: %s", *userDid, errMsg)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -939,7 +905,7 @@ func (a *AppService) CreateDefaultWorkflowTemplate(w http.ResponseWriter, r *htt
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading default workflow template request body for admin %s: %s", *adminId, err.Error())
+		a.logger.Errorf(r.Context(), "error reading default workflow template request body for admin %s: %s", *adminId, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -971,7 +937,7 @@ func (a *AppService) CreateDefaultWorkflowTemplate(w http.ResponseWriter, r *htt
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error creating default workflow template for admin %s: %s", *adminId, errMsg)
+		a.logger.Errorf(r.Context(), "error creating default workflow template for admin %s: %s", *adminId, errMsg)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -990,7 +956,7 @@ func (a *AppService) CreateWorkflow(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading workflow request body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading workflow request body for user %s: %s", *userDid, err.Error())
 		writeWorkflowCreateError(w, http.StatusInternalServerError, "proposer_workflow_api.read_body", "Unable to read workflow request body.", "")
 		return
 	}
@@ -1045,12 +1011,14 @@ func (a *AppService) CreateWorkflow(w http.ResponseWriter, r *http.Request) {
 			writeWorkflowCreateError(w, http.StatusBadRequest, "proposer_workflow_db.validation", errMsg, "")
 			return
 		}
-		a.logger.Logf("error creating workflow for proposer %s: %s", *userDid, errMsg)
+		a.logger.Errorf(r.Context(), "error creating workflow for proposer %s: %s", *userDid, errMsg)
 		writeWorkflowCreateError(w, http.StatusInternalServerError, "proposer_workflow_db.internal", "Unable to create workflow because an internal workflow database operation failed.", "")
 		return
 	}
 	if workflow.Status == "approved" {
-		go a.sendWorkflowProposalOutcomeEmailByWorkflow(context.Background(), workflow.Id)
+		a.lifecycle.Go("workflow proposal outcome email", func(context.Context) {
+			a.sendWorkflowProposalOutcomeEmailByWorkflow(context.Background(), workflow.Id)
+		})
 	}
 
 	w.WriteHeader(http.StatusCreated)
@@ -1082,7 +1050,7 @@ func (a *AppService) GetProposerWorkflows(w http.ResponseWriter, r *http.Request
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error getting workflows for proposer %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting workflows for proposer %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1119,7 +1087,7 @@ func (a *AppService) GetProposerWorkflow(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := a.refreshWorkflowStartAvailabilityAndNotify(r.Context()); err != nil {
-		a.logger.Logf("error refreshing workflow availability before proposer workflow detail %s for user %s: %s", workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error refreshing workflow availability before proposer workflow detail %s for user %s: %s", workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1136,7 +1104,7 @@ func (a *AppService) GetProposerWorkflow(w http.ResponseWriter, r *http.Request)
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error getting workflow %s for proposer %s: %s", workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting workflow %s for proposer %s: %s", workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1170,7 +1138,7 @@ func (a *AppService) DeleteProposerWorkflow(w http.ResponseWriter, r *http.Reque
 			w.Write([]byte(err.Error()))
 			return
 		}
-		a.logger.Logf("error archiving workflow %s for proposer %s: %s", workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error archiving workflow %s for proposer %s: %s", workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1215,7 +1183,7 @@ func (a *AppService) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := a.refreshWorkflowStartAvailabilityAndNotify(r.Context()); err != nil {
-		a.logger.Logf("error refreshing workflow availability before workflow detail %s for user %s: %s", workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error refreshing workflow availability before workflow detail %s for user %s: %s", workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1226,7 +1194,7 @@ func (a *AppService) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error getting workflow %s: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error getting workflow %s: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1246,7 +1214,7 @@ func (a *AppService) GetImproverWorkflows(w http.ResponseWriter, r *http.Request
 
 	refreshResult, err := a.db.RefreshWorkflowStartAvailability(r.Context())
 	if err != nil {
-		a.logger.Logf("error refreshing workflow availability for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error refreshing workflow availability for improver %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1257,7 +1225,7 @@ func (a *AppService) GetImproverWorkflows(w http.ResponseWriter, r *http.Request
 
 	activeCredentials, err := a.db.GetActiveCredentialTypesForUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error loading active credentials for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading active credentials for improver %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1271,7 +1239,7 @@ func (a *AppService) GetImproverWorkflows(w http.ResponseWriter, r *http.Request
 			w.Write([]byte(err.Error()))
 			return
 		}
-		a.logger.Logf("error loading improver workflows for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading improver workflows for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1297,7 +1265,7 @@ func (a *AppService) GetManagedWorkflows(w http.ResponseWriter, r *http.Request)
 
 	workflows, err := a.db.GetManagedWorkflowsByImprover(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error loading managed workflows for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading managed workflows for improver %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1379,7 +1347,7 @@ func (a *AppService) GetSupervisorWorkflows(w http.ResponseWriter, r *http.Reque
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error loading supervisor workflows for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading supervisor workflows for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1398,12 +1366,12 @@ func (a *AppService) GetImproverUnpaidWorkflows(w http.ResponseWriter, r *http.R
 	staleBefore := time.Now().UTC().Add(-workflowPayoutStaleLockTimeout).Unix()
 
 	if _, _, err := a.db.RecoverStaleWorkflowPayoutLocksForImprover(r.Context(), *userDid, staleBefore, workflowPayoutErrorTimedOut); err != nil {
-		a.logger.Logf("error recovering stale payout locks for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error recovering stale payout locks for improver %s: %s", *userDid, err)
 	}
 
 	workflows, err := a.db.GetImproverUnpaidWorkflows(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error loading unpaid workflows for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading unpaid workflows for improver %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1414,7 +1382,7 @@ func (a *AppService) GetImproverUnpaidWorkflows(w http.ResponseWriter, r *http.R
 
 	refreshed, err := a.db.GetImproverUnpaidWorkflows(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error refreshing unpaid workflows for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error refreshing unpaid workflows for improver %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1441,12 +1409,12 @@ func (a *AppService) RequestWorkflowStepPayoutRetry(w http.ResponseWriter, r *ht
 
 	staleBefore := time.Now().UTC().Add(-workflowPayoutStaleLockTimeout).Unix()
 	if _, err := a.db.RecoverStaleWorkflowStepPayoutLock(r.Context(), workflowID, stepID, *userDid, staleBefore, workflowPayoutErrorTimedOut); err != nil {
-		a.logger.Logf("error recovering stale payout lock for workflow %s step %s improver %s: %s", workflowID, stepID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error recovering stale payout lock for workflow %s step %s improver %s: %s", workflowID, stepID, *userDid, err)
 	}
 
 	reconciled, pendingConfirmation, err := a.reconcileWorkflowStepPayoutByHash(r.Context(), workflowID, stepID, *userDid)
 	if err != nil {
-		a.logger.Logf("error reconciling workflow payout by tx hash for workflow %s step %s improver %s: %s", workflowID, stepID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error reconciling workflow payout by tx hash for workflow %s step %s improver %s: %s", workflowID, stepID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1468,7 +1436,7 @@ func (a *AppService) RequestWorkflowStepPayoutRetry(w http.ResponseWriter, r *ht
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error requesting workflow step payout retry for workflow %s step %s improver %s: %s", workflowID, stepID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error requesting workflow step payout retry for workflow %s step %s improver %s: %s", workflowID, stepID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1481,7 +1449,7 @@ func (a *AppService) RequestWorkflowStepPayoutRetry(w http.ResponseWriter, r *ht
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow %s after payout retry request: %s", workflowID, err)
+		a.logger.Errorf(r.Context(), "error loading workflow %s after payout retry request: %s", workflowID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1507,12 +1475,12 @@ func (a *AppService) RequestWorkflowManagerPayoutRetry(w http.ResponseWriter, r
 
 	staleBefore := time.Now().UTC().Add(-workflowPayoutStaleLockTimeout).Unix()
 	if _, err := a.db.RecoverStaleWorkflowManagerPayoutLock(r.Context(), workflowID, *userDid, staleBefore, workflowPayoutErrorTimedOut); err != nil {
-		a.logger.Logf("error recovering stale manager payout lock for workflow %s improver %s: %s", workflowID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error recovering stale manager payout lock for workflow %s improver %s: %s", workflowID, *userDid, err)
 	}
 
 	reconciled, pendingConfirmation, err := a.reconcileWorkflowManagerPayoutByHash(r.Context(), workflowID, *userDid)
 	if err != nil {
-		a.logger.Logf("error reconciling manager payout by tx hash for workflow %s improver %s: %s", workflowID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error reconciling manager payout by tx hash for workflow %s improver %s: %s", workflowID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1534,7 +1502,7 @@ func (a *AppService) RequestWorkflowManagerPayoutRetry(w http.ResponseWriter, r
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error requesting workflow manager payout retry for workflow %s improver %s: %s", workflowID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error requesting workflow manager payout retry for workflow %s improver %s: %s", workflowID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1547,7 +1515,7 @@ func (a *AppService) RequestWorkflowManagerPayoutRetry(w http.ResponseWriter, r
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow %s after manager payout retry request: %s", workflowID, err)
+		a.logger.Errorf(r.Context(), "error loading workflow %s after manager payout retry request: %s", workflowID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1596,7 +1564,7 @@ func (a *AppService) ClaimWorkflowManager(w http.ResponseWriter, r *http.Request
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error claiming workflow manager on workflow %s for improver %s: %s", workflowID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error claiming workflow manager on workflow %s for improver %s: %s", workflowID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1621,7 +1589,7 @@ func (a *AppService) DownloadManagedWorkflowCSV(w http.ResponseWriter, r *http.R
 
 	isManager, err := a.db.IsWorkflowManagedByImprover(r.Context(), workflowID, *userDid)
 	if err != nil {
-		a.logger.Logf("error checking manager authorization for workflow csv %s improver %s: %s", workflowID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error checking manager authorization for workflow csv %s improver %s: %s", workflowID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1636,16 +1604,16 @@ func (a *AppService) DownloadManagedWorkflowCSV(w http.ResponseWriter, r *http.R
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading managed workflow %s for csv export: %s", workflowID, err)
+		a.logger.Errorf(r.Context(), "error loading managed workflow %s for csv export: %s", workflowID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	photoFileNamesByID := map[string]string{}
 	photoFileNameCounts := map[string]int{}
-	photoExports, err := a.db.GetWorkflowSubmissionPhotoExports(r.Context(), workflowID)
+	photoExports, err := a.db.GetWorkflowSubmissionPhotoExports(r.Context(), workflowID, "")
 	if err != nil {
-		a.logger.Logf("error loading managed workflow %s photos for csv export: %s", workflowID, err)
+		a.logger.Errorf(r.Context(), "error loading managed workflow %s photos for csv export: %s", workflowID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1656,7 +1624,7 @@ func (a *AppService) DownloadManagedWorkflowCSV(w http.ResponseWriter, r *http.R
 
 	csvData, err := buildManagedWorkflowCSV(workflow, photoFileNamesByID)
 	if err != nil {
-		a.logger.Logf("error building csv export for managed workflow %s: %s", workflowID, err)
+		a.logger.Errorf(r.Context(), "error building csv export for managed workflow %s: %s", workflowID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1728,7 +1696,7 @@ func (a *AppService) DownloadManagedWorkflowPhotosZip(w http.ResponseWriter, r *
 
 	isManager, err := a.db.IsWorkflowManagedByImprover(r.Context(), workflowID, *userDid)
 	if err != nil {
-		a.logger.Logf("error checking manager authorization for workflow photo export %s improver %s: %s", workflowID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error checking manager authorization for workflow photo export %s improver %s: %s", workflowID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1737,9 +1705,9 @@ func (a *AppService) DownloadManagedWorkflowPhotosZip(w http.ResponseWriter, r *
 		return
 	}
 
-	photos, err := a.db.GetWorkflowSubmissionPhotoExports(r.Context(), workflowID)
+	photos, err := a.db.GetWorkflowSubmissionPhotoExports(r.Context(), workflowID, "")
 	if err != nil {
-		a.logger.Logf("error loading workflow photo export rows for workflow %s: %s", workflowID, err)
+		a.logger.Errorf(r.Context(), "error loading workflow photo export rows for workflow %s: %s", workflowID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -1752,15 +1720,15 @@ func (a *AppService) DownloadManagedWorkflowPhotosZip(w http.ResponseWriter, r *
 
 		entryWriter, entryErr := zipWriter.Create(archiveFileName)
 		if entryErr != nil {
-			a.logger.Logf("error creating photo zip entry for workflow %s photo %s: %s", workflowID, photoExport.Photo.Id, entryErr)
+			a.logger.Errorf(r.Context(), "error creating photo zip entry for workflow %s photo %s: %s", workflowID, photoExport.Photo.Id, entryErr)
 			continue
 		}
 		if _, writeErr := entryWriter.Write(photoExport.Photo.PhotoData); writeErr != nil {
-			a.logger.Logf("error writing photo zip entry for workflow %s photo %s: %s", workflowID, photoExport.Photo.Id, writeErr)
+			a.logger.Errorf(r.Context(), "error writing photo zip entry for workflow %s photo %s: %s", workflowID, photoExport.Photo.Id, writeErr)
 		}
 	}
 	if err := zipWriter.Close(); err != nil {
-		a.logger.Logf("error finalizing photo zip for workflow %s: %s", workflowID, err)
+		a.logger.Errorf(r.Context(), "error finalizing photo zip for workflow %s: %s", workflowID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2230,7 +2198,7 @@ func (a *AppService) ExportSupervisorWorkflowData(w http.ResponseWriter, r *http
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading supervisor workflow export body for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading supervisor workflow export body for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2251,7 +2219,7 @@ func (a *AppService) ExportSupervisorWorkflowData(w http.ResponseWriter, r *http
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error selecting supervisor workflows for export by %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error selecting supervisor workflows for export by %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2261,6 +2229,12 @@ func (a *AppService) ExportSupervisorWorkflowData(w http.ResponseWriter, r *http
 	}
 
 	exportLocation, exportTimezone := resolveSupervisorExportTimezone(req.Timezone)
+	photoVariant, err := workflowPhotoVariant(req.PhotoSize)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("photo_size must be thumbnail, medium or original"))
+		return
+	}
 
 	workflows := make([]*structs.Workflow, 0, len(workflowIDs))
 	improverIDs := map[string]struct{}{}
@@ -2270,7 +2244,7 @@ func (a *AppService) ExportSupervisorWorkflowData(w http.ResponseWriter, r *http
 			if err == pgx.ErrNoRows {
 				continue
 			}
-			a.logger.Logf("error loading workflow %s for supervisor export by %s: %s", workflowID, *userDid, err)
+			a.logger.Errorf(r.Context(), "error loading workflow %s for supervisor export by %s: %s", workflowID, *userDid, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -2303,7 +2277,7 @@ func (a *AppService) ExportSupervisorWorkflowData(w http.ResponseWriter, r *http
 	}
 	improverEmails, err := a.db.GetUserEmailsByIDs(r.Context(), improverIDList)
 	if err != nil {
-		a.logger.Logf("error loading improver emails for supervisor export by %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading improver emails for supervisor export by %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2313,9 +2287,9 @@ func (a *AppService) ExportSupervisorWorkflowData(w http.ResponseWriter, r *http
 	fileNameCounts := map[string]int{}
 	photoFileNamesByID := map[string]string{}
 	for _, workflow := range workflows {
-		photos, err := a.db.GetWorkflowSubmissionPhotoExports(r.Context(), workflow.Id)
+		photos, err := a.db.GetWorkflowSubmissionPhotoExports(r.Context(), workflow.Id, photoVariant)
 		if err != nil {
-			a.logger.Logf("error loading supervisor photo exports for workflow %s user %s: %s", workflow.Id, *userDid, err)
+			a.logger.Errorf(r.Context(), "error loading supervisor photo exports for workflow %s user %s: %s", workflow.Id, *userDid, err)
 			continue
 		}
 		for _, photoExport := range photos {
@@ -2324,13 +2298,13 @@ func (a *AppService) ExportSupervisorWorkflowData(w http.ResponseWriter, r *http
 			if !supervisorWorkflowPhotoShouldUseOriginalBytes(photoExport.Photo.ContentType, photoExport.Photo.FileName) {
 				img, _, decodeErr := image.Decode(bytes.NewReader(photoExport.Photo.PhotoData))
 				if decodeErr != nil {
-					a.logger.Logf("error decoding supervisor export photo %s for workflow %s: %s", photoExport.Photo.Id, workflow.Id, decodeErr)
+					a.logger.Errorf(r.Context(), "error decoding supervisor export photo %s for workflow %s: %s", photoExport.Photo.Id, workflow.Id, decodeErr)
 					continue
 				}
 
 				var jpegBuffer bytes.Buffer
 				if err := jpeg.Encode(&jpegBuffer, img, &jpeg.Options{Quality: 97}); err != nil {
-					a.logger.Logf("error encoding supervisor export photo %s for workflow %s: %s", photoExport.Photo.Id, workflow.Id, err)
+					a.logger.Errorf(r.Context(), "error encoding supervisor export photo %s for workflow %s: %s", photoExport.Photo.Id, workflow.Id, err)
 					continue
 				}
 				photoBytes = jpegBuffer.Bytes()
@@ -2349,25 +2323,25 @@ func (a *AppService) ExportSupervisorWorkflowData(w http.ResponseWriter, r *http
 
 	csvData, err := buildSupervisorWorkflowCSV(workflows, improverEmails, photoFileNamesByID, exportLocation, exportTimezone)
 	if err != nil {
-		a.logger.Logf("error building supervisor workflow csv for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error building supervisor workflow csv for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	csvEntry, err := zipWriter.Create("supervisor_workflows.csv")
 	if err != nil {
-		a.logger.Logf("error creating supervisor export csv zip entry for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error creating supervisor export csv zip entry for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 	if _, err := csvEntry.Write(csvData); err != nil {
-		a.logger.Logf("error writing supervisor export csv for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error writing supervisor export csv for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	if err := zipWriter.Close(); err != nil {
-		a.logger.Logf("error finalizing supervisor workflow export zip for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error finalizing supervisor workflow export zip for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2548,12 +2522,9 @@ func valueOrEmpty(value *string) string {
 	return *value
 }
 
-func writeWorkflowPhotoResponse(w http.ResponseWriter, photo *structs.WorkflowSubmissionPhotoBlob, inline bool) {
-	if photo == nil {
-		w.WriteHeader(http.StatusNotFound)
-		return
-	}
-
+// workflowPhotoContentHeaders returns the Content-Type and
+// Content-Disposition a photo is served with.
+func workflowPhotoContentHeaders(photo *structs.WorkflowSubmissionPhotoBlob, inline bool) (string, string) {
 	contentType := strings.TrimSpace(photo.ContentType)
 	if contentType == "" {
 		contentType = "application/octet-stream"
@@ -2564,15 +2535,27 @@ func writeWorkflowPhotoResponse(w http.ResponseWriter, photo *structs.WorkflowSu
 	}
 
 	dispositionType := "attachment"
-	cacheControl := "no-store"
 	if inline {
 		dispositionType = "inline"
+	}
+	return contentType, fmt.Sprintf("%s; filename=\"%s\"", dispositionType, filename)
+}
+
+func writeWorkflowPhotoResponse(w http.ResponseWriter, photo *structs.WorkflowSubmissionPhotoBlob, inline bool) {
+	if photo == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	contentType, disposition := workflowPhotoContentHeaders(photo, inline)
+	cacheControl := "no-store"
+	if inline {
 		cacheControl = "public, max-age=31536000, immutable"
 		w.Header().Set("X-Robots-Tag", "noindex, nofollow, noimageindex")
 	}
 
 	w.Header().Set("Content-Type", contentType)
-	w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=\"%s\"", dispositionType, filename))
+	w.Header().Set("Content-Disposition", disposition)
 	w.Header().Set("Cache-Control", cacheControl)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(photo.PhotoData)
@@ -2591,7 +2574,7 @@ func (a *AppService) GetPublicWorkflowPhoto(w http.ResponseWriter, r *http.Reque
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading public workflow photo %s: %s", photoID, err)
+		a.logger.Errorf(r.Context(), "error loading public workflow photo %s: %s", photoID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2612,13 +2595,13 @@ func (a *AppService) GetWorkflowPhoto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	photo, err := a.db.GetWorkflowSubmissionPhotoBlobByID(r.Context(), photoID)
+	photo, err := a.db.GetWorkflowSubmissionPhotoByID(r.Context(), photoID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow photo %s: %s", photoID, err)
+		a.logger.Errorf(r.Context(), "error loading workflow photo %s: %s", photoID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2628,7 +2611,7 @@ func (a *AppService) GetWorkflowPhoto(w http.ResponseWriter, r *http.Request) {
 	if !canAccess {
 		workflow, wfErr := a.db.GetWorkflowByID(r.Context(), photo.WorkflowId)
 		if wfErr != nil && wfErr != pgx.ErrNoRows {
-			a.logger.Logf("error checking workflow photo access for photo %s user %s: %s", photoID, *userDid, wfErr)
+			a.logger.Errorf(r.Context(), "error checking workflow photo access for photo %s user %s: %s", photoID, *userDid, wfErr)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -2642,6 +2625,42 @@ func (a *AppService) GetWorkflowPhoto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	variant, err := workflowPhotoVariant(r.URL.Query().Get("size"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if signed, _ := strconv.ParseBool(r.URL.Query().Get("signed")); signed {
+		target, servedVariant, err := a.resolveWorkflowPhotoVariant(r.Context(), photo, variant, false)
+		if err != nil {
+			a.logger.Errorf(r.Context(), "error loading workflow photo %s %s rendition: %s", photoID, variant, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		photoURL, err := a.signWorkflowPhotoURL(target, servedVariant, time.Now())
+		if err != nil {
+			a.logger.Errorf(r.Context(), "error signing url for workflow photo %s: %s", photoID, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(photoURL)
+		return
+	}
+
+	photo, _, err = a.resolveWorkflowPhotoVariant(r.Context(), photo, variant, true)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		a.logger.Errorf(r.Context(), "error loading workflow photo %s: %s", photoID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	writeWorkflowPhotoResponse(w, photo, false)
 }
 
@@ -2654,7 +2673,7 @@ func (a *AppService) GetImproverAbsencePeriods(w http.ResponseWriter, r *http.Re
 
 	periods, err := a.db.GetImproverAbsencePeriods(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error loading improver absence periods for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading improver absence periods for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2673,7 +2692,7 @@ func (a *AppService) CreateImproverAbsencePeriod(w http.ResponseWriter, r *http.
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading improver absence request body for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading improver absence request body for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2717,7 +2736,7 @@ func (a *AppService) CreateImproverAbsencePeriod(w http.ResponseWriter, r *http.
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error creating improver absence period for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error creating improver absence period for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2742,7 +2761,7 @@ func (a *AppService) UpdateImproverAbsencePeriod(w http.ResponseWriter, r *http.
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading improver absence update request body for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading improver absence update request body for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2789,7 +2808,7 @@ func (a *AppService) UpdateImproverAbsencePeriod(w http.ResponseWriter, r *http.
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error updating improver absence period %s for %s: %s", absenceID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error updating improver absence period %s for %s: %s", absenceID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2828,7 +2847,7 @@ func (a *AppService) DeleteImproverAbsencePeriod(w http.ResponseWriter, r *http.
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error deleting improver absence period %s for %s: %s", absenceID, *userDid, err)
+		a.logger.Errorf(r.Context(), "error deleting improver absence period %s for %s: %s", absenceID, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2847,7 +2866,7 @@ func (a *AppService) UnclaimImproverWorkflowSeries(w http.ResponseWriter, r *htt
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading improver workflow series unclaim body for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading improver workflow series unclaim body for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2871,7 +2890,7 @@ func (a *AppService) UnclaimImproverWorkflowSeries(w http.ResponseWriter, r *htt
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error unclaiming workflow series %s step %d for improver %s: %s", req.SeriesId, req.StepOrder, *userDid, err)
+		a.logger.Errorf(r.Context(), "error unclaiming workflow series %s step %d for improver %s: %s", req.SeriesId, req.StepOrder, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2894,7 +2913,7 @@ func (a *AppService) ClaimWorkflowStep(w http.ResponseWriter, r *http.Request) {
 		}
 		a.sendWorkflowSeriesFundingShortfallEmails(r.Context(), refreshResult.SeriesFundingChecks)
 	} else {
-		a.logger.Logf("error refreshing workflow start availability before claim for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error refreshing workflow start availability before claim for improver %s: %s", *userDid, err)
 	}
 
 	workflowId := strings.TrimSpace(r.PathValue("workflow_id"))
@@ -2936,7 +2955,7 @@ func (a *AppService) ClaimWorkflowStep(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error claiming workflow step %s on workflow %s for improver %s: %s", stepId, workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error claiming workflow step %s on workflow %s for improver %s: %s", stepId, workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -2963,7 +2982,7 @@ func (a *AppService) StartWorkflowStep(w http.ResponseWriter, r *http.Request) {
 		}
 		a.sendWorkflowSeriesFundingShortfallEmails(r.Context(), refreshResult.SeriesFundingChecks)
 	} else {
-		a.logger.Logf("error refreshing workflow start availability before start for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error refreshing workflow start availability before start for improver %s: %s", *userDid, err)
 	}
 
 	workflowId := strings.TrimSpace(r.PathValue("workflow_id"))
@@ -2990,7 +3009,7 @@ func (a *AppService) StartWorkflowStep(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error starting workflow step %s on workflow %s for improver %s: %s", stepId, workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error starting workflow step %s on workflow %s for improver %s: %s", stepId, workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3014,7 +3033,7 @@ func (a *AppService) CompleteWorkflowStep(w http.ResponseWriter, r *http.Request
 		}
 		a.sendWorkflowSeriesFundingShortfallEmails(r.Context(), refreshResult.SeriesFundingChecks)
 	} else {
-		a.logger.Logf("error refreshing workflow start availability before complete for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error refreshing workflow start availability before complete for improver %s: %s", *userDid, err)
 	}
 
 	workflowId := strings.TrimSpace(r.PathValue("workflow_id"))
@@ -3031,7 +3050,7 @@ func (a *AppService) CompleteWorkflowStep(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	result, err := a.db.CompleteWorkflowStep(r.Context(), workflowId, stepId, *userDid, req.StepNotPossible, req.StepNotPossibleDetails, req.Items)
+	result, err := a.db.CompleteWorkflowStep(r.Context(), workflowId, stepId, *userDid, req.StepNotPossible, req.StepNotPossibleDetails, req.Location, req.Items)
 	if err != nil {
 		errMsg := err.Error()
 		if strings.Contains(errMsg, "not assigned") {
@@ -3048,7 +3067,7 @@ func (a *AppService) CompleteWorkflowStep(w http.ResponseWriter, r *http.Request
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error completing workflow step %s on workflow %s for improver %s: %s", stepId, workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error completing workflow step %s on workflow %s for improver %s: %s", stepId, workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3064,7 +3083,7 @@ func (a *AppService) CompleteWorkflowStep(w http.ResponseWriter, r *http.Request
 
 	workflow, err := a.db.GetWorkflowByID(r.Context(), workflowId)
 	if err != nil {
-		a.logger.Logf("error loading workflow %s after step completion: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow %s after step completion: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3134,7 +3153,7 @@ func (a *AppService) UploadWorkflowStepPhoto(w http.ResponseWriter, r *http.Requ
 
 	data, err := io.ReadAll(file)
 	if err != nil {
-		a.logger.Logf("error reading workflow photo upload body for improver %s step %s: %s", *userDid, stepId, err)
+		a.logger.Errorf(r.Context(), "error reading workflow photo upload body for improver %s step %s: %s", *userDid, stepId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3192,7 +3211,7 @@ func (a *AppService) UploadWorkflowStepPhoto(w http.ResponseWriter, r *http.Requ
 				w.WriteHeader(http.StatusNotFound)
 				return
 			}
-			a.logger.Logf("error uploading workflow photo chunk for improver %s workflow %s step %s: %s", *userDid, workflowId, stepId, err)
+			a.logger.Errorf(r.Context(), "error uploading workflow photo chunk for improver %s workflow %s step %s: %s", *userDid, workflowId, stepId, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -3223,7 +3242,7 @@ func (a *AppService) UploadWorkflowStepPhoto(w http.ResponseWriter, r *http.Requ
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error uploading workflow photo for improver %s workflow %s step %s: %s", *userDid, workflowId, stepId, err)
+		a.logger.Errorf(r.Context(), "error uploading workflow photo for improver %s workflow %s step %s: %s", *userDid, workflowId, stepId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3247,7 +3266,7 @@ func (a *AppService) GetVoterWorkflows(w http.ResponseWriter, r *http.Request) {
 
 	workflows, err := a.db.GetVoterWorkflows(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting voter workflows for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting voter workflows for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3259,14 +3278,14 @@ func (a *AppService) GetVoterWorkflows(w http.ResponseWriter, r *http.Request) {
 
 		allowApproval, err := a.workflowApprovalAllowed(r.Context(), workflow)
 		if err != nil {
-			a.logger.Logf("error checking faucet allocation for workflow %s vote evaluation: %s", workflow.Id, err)
+			a.logger.Errorf(r.Context(), "error checking faucet allocation for workflow %s vote evaluation: %s", workflow.Id, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
 		evaluatedWorkflow, err := a.db.EvaluateWorkflowVoteStateWithApproval(r.Context(), workflow.Id, allowApproval)
 		if err != nil {
-			a.logger.Logf("error evaluating workflow vote state %s for voter %s: %s", workflow.Id, *userDid, err)
+			a.logger.Errorf(r.Context(), "error evaluating workflow vote state %s for voter %s: %s", workflow.Id, *userDid, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -3300,7 +3319,7 @@ func (a *AppService) GetVoterWorkflow(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := a.refreshWorkflowStartAvailabilityAndNotify(r.Context()); err != nil {
-		a.logger.Logf("error refreshing workflow availability before voter workflow detail %s for user %s: %s", workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error refreshing workflow availability before voter workflow detail %s for user %s: %s", workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3311,7 +3330,7 @@ func (a *AppService) GetVoterWorkflow(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error getting voter workflow %s for %s: %s", workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting voter workflow %s for %s: %s", workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3354,7 +3373,7 @@ func (a *AppService) VoteWorkflow(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow %s for voting: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow %s for voting: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3368,21 +3387,21 @@ func (a *AppService) VoteWorkflow(w http.ResponseWriter, r *http.Request) {
 
 	_, err = a.db.RecordWorkflowVote(r.Context(), workflowId, *userDid, req.Decision, req.Comment)
 	if err != nil {
-		a.logger.Logf("error recording workflow vote for workflow %s voter %s: %s", workflowId, *userDid, err)
+		a.logger.Errorf(r.Context(), "error recording workflow vote for workflow %s voter %s: %s", workflowId, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	allowApproval, err := a.workflowApprovalAllowed(r.Context(), workflow)
 	if err != nil {
-		a.logger.Logf("error checking faucet allocation for workflow %s vote: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error checking faucet allocation for workflow %s vote: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	updatedWorkflow, err := a.db.EvaluateWorkflowVoteStateWithApproval(r.Context(), workflowId, allowApproval)
 	if err != nil {
-		a.logger.Logf("error evaluating workflow vote state for workflow %s: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error evaluating workflow vote state for workflow %s: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3422,7 +3441,7 @@ func (a *AppService) AdminForceApproveWorkflow(w http.ResponseWriter, r *http.Re
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow %s for admin force approve: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow %s for admin force approve: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3436,7 +3455,7 @@ func (a *AppService) AdminForceApproveWorkflow(w http.ResponseWriter, r *http.Re
 
 	allowApproval, err := a.workflowApprovalAllowed(r.Context(), workflow)
 	if err != nil {
-		a.logger.Logf("error checking faucet allocation for admin force approval on workflow %s: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error checking faucet allocation for admin force approval on workflow %s: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3449,17 +3468,18 @@ func (a *AppService) AdminForceApproveWorkflow(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := a.db.ForceApproveWorkflowAsAdmin(r.Context(), workflowId, *adminId); err != nil {
-		a.logger.Logf("error force approving workflow %s by admin %s: %s", workflowId, *adminId, err)
+		a.logger.Errorf(r.Context(), "error force approving workflow %s by admin %s: %s", workflowId, *adminId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	updatedWorkflow, err := a.db.GetWorkflowByID(r.Context(), workflowId)
 	if err != nil {
-		a.logger.Logf("error loading workflow %s after admin force approve: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow %s after admin force approve: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditWorkflowForceApprove, "workflow", workflowId, workflowAuditState(workflow.Status), workflowAuditState(updatedWorkflow.Status))
 
 	a.sendWorkflowProposalOutcomeEmailByWorkflow(r.Context(), updatedWorkflow.Id)
 	sanitizeWorkflowForUser(updatedWorkflow, *adminId, true)
@@ -3484,7 +3504,7 @@ func (a *AppService) ResolveAdminWorkflowPayoutLock(w http.ResponseWriter, r *ht
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading admin workflow payout resolution body for workflow %s: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error reading admin workflow payout resolution body for workflow %s: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3497,7 +3517,11 @@ func (a *AppService) ResolveAdminWorkflowPayoutLock(w http.ResponseWriter, r *ht
 		}
 	}
 
-	if err := a.db.ResolveWorkflowPayoutLockByAdmin(r.Context(), *adminId, workflowId, &req); err != nil {
+	apiKeyId := ""
+	if keyId := utils.GetAPIKeyId(r); keyId != nil {
+		apiKeyId = *keyId
+	}
+	if err := a.db.ResolveWorkflowPayoutLockByAdmin(r.Context(), *adminId, apiKeyId, workflowId, &req); err != nil {
 		errMsg := err.Error()
 		if err == pgx.ErrNoRows {
 			w.WriteHeader(http.StatusNotFound)
@@ -3516,7 +3540,7 @@ func (a *AppService) ResolveAdminWorkflowPayoutLock(w http.ResponseWriter, r *ht
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf(
+		a.logger.Errorf(r.Context(),
 			"error resolving admin payout lock for workflow %s by admin %s target %s step %s action %s: %s",
 			workflowId,
 			*adminId,
@@ -3531,7 +3555,7 @@ func (a *AppService) ResolveAdminWorkflowPayoutLock(w http.ResponseWriter, r *ht
 
 	if strings.EqualFold(strings.TrimSpace(req.Action), "mark_paid_out") {
 		if _, err := a.db.FinalizeWorkflowPaidOutIfSettled(r.Context(), workflowId); err != nil {
-			a.logger.Logf("error finalizing workflow %s after admin payout resolution: %s", workflowId, err)
+			a.logger.Errorf(r.Context(), "error finalizing workflow %s after admin payout resolution: %s", workflowId, err)
 		}
 	}
 
@@ -3541,13 +3565,13 @@ func (a *AppService) ResolveAdminWorkflowPayoutLock(w http.ResponseWriter, r *ht
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow %s after admin payout resolution: %s", workflowId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow %s after admin payout resolution: %s", workflowId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 	sanitizeWorkflowForUser(workflow, *adminId, true)
 
-	a.logger.Logf(
+	a.logger.Infof(r.Context(),
 		"admin payout resolution applied: admin=%s workflow=%s target=%s step=%s action=%s",
 		*adminId,
 		workflowId,
@@ -3555,6 +3579,7 @@ func (a *AppService) ResolveAdminWorkflowPayoutLock(w http.ResponseWriter, r *ht
 		strings.TrimSpace(req.StepId),
 		strings.TrimSpace(req.Action),
 	)
+	a.recordAdminAudit(r, structs.AdminAuditWorkflowPayoutLockResolve, "workflow", workflowId, nil, req)
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(workflow)
@@ -3575,7 +3600,7 @@ func (a *AppService) GetActiveWorkflows(w http.ResponseWriter, r *http.Request)
 
 	workflows, err := a.db.GetActiveWorkflows(r.Context())
 	if err != nil {
-		a.logger.Logf("error loading active workflows for user %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error loading active workflows for user %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3611,7 +3636,7 @@ func (a *AppService) GetAdminWorkflows(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error loading admin workflows: %s", err)
+		a.logger.Errorf(r.Context(), "error loading admin workflows: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3635,7 +3660,7 @@ func (a *AppService) GetAdminWorkflowSeriesClaimants(w http.ResponseWriter, r *h
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error loading workflow series claimants for %s: %s", seriesId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow series claimants for %s: %s", seriesId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3654,7 +3679,7 @@ func (a *AppService) RevokeAdminWorkflowSeriesImproverClaim(w http.ResponseWrite
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading admin workflow series revoke body for %s: %s", seriesId, err)
+		a.logger.Errorf(r.Context(), "error reading admin workflow series revoke body for %s: %s", seriesId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3678,10 +3703,11 @@ func (a *AppService) RevokeAdminWorkflowSeriesImproverClaim(w http.ResponseWrite
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error revoking workflow series claim for %s improver %s: %s", seriesId, req.ImproverUserId, err)
+		a.logger.Errorf(r.Context(), "error revoking workflow series claim for %s improver %s: %s", seriesId, req.ImproverUserId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditWorkflowSeriesClaimRevoke, "workflow_series", seriesId, map[string]any{"improver_user_id": req.ImproverUserId}, result)
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(result)
@@ -3705,7 +3731,7 @@ func (a *AppService) ProposeWorkflowEdit(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading workflow edit proposal body for workflow %s proposer %s: %s", workflowID, *requesterID, err)
+		a.logger.Errorf(r.Context(), "error reading workflow edit proposal body for workflow %s proposer %s: %s", workflowID, *requesterID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3742,7 +3768,7 @@ func (a *AppService) ProposeWorkflowEdit(w http.ResponseWriter, r *http.Request)
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error creating workflow edit proposal for workflow %s proposer %s: %s", workflowID, *requesterID, err)
+		a.logger.Errorf(r.Context(), "error creating workflow edit proposal for workflow %s proposer %s: %s", workflowID, *requesterID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3784,7 +3810,7 @@ func (a *AppService) GetVoterWorkflowEditProposals(w http.ResponseWriter, r *htt
 
 	proposals, err := a.db.GetWorkflowEditProposalsForVoter(r.Context(), *voterID)
 	if err != nil {
-		a.logger.Logf("error loading workflow edit proposals for voter %s: %s", *voterID, err)
+		a.logger.Errorf(r.Context(), "error loading workflow edit proposals for voter %s: %s", *voterID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3812,7 +3838,7 @@ func (a *AppService) VoteWorkflowEditProposal(w http.ResponseWriter, r *http.Req
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading workflow edit vote body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading workflow edit vote body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3834,7 +3860,7 @@ func (a *AppService) VoteWorkflowEditProposal(w http.ResponseWriter, r *http.Req
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow edit proposal %s for vote: %s", proposalID, err)
+		a.logger.Errorf(r.Context(), "error loading workflow edit proposal %s for vote: %s", proposalID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3845,14 +3871,14 @@ func (a *AppService) VoteWorkflowEditProposal(w http.ResponseWriter, r *http.Req
 	}
 
 	if _, err := a.db.RecordWorkflowEditVote(r.Context(), proposalID, *voterID, req.Decision, req.Comment); err != nil {
-		a.logger.Logf("error recording workflow edit vote %s by %s: %s", proposalID, *voterID, err)
+		a.logger.Errorf(r.Context(), "error recording workflow edit vote %s by %s: %s", proposalID, *voterID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	updatedProposal, err := a.db.EvaluateWorkflowEditVoteState(r.Context(), proposalID)
 	if err != nil {
-		a.logger.Logf("error evaluating workflow edit vote state %s: %s", proposalID, err)
+		a.logger.Errorf(r.Context(), "error evaluating workflow edit vote state %s: %s", proposalID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3887,7 +3913,7 @@ func (a *AppService) AdminForceApproveWorkflowEditProposal(w http.ResponseWriter
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow edit proposal %s for admin force approve: %s", proposalID, err)
+		a.logger.Errorf(r.Context(), "error loading workflow edit proposal %s for admin force approve: %s", proposalID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3899,17 +3925,18 @@ func (a *AppService) AdminForceApproveWorkflowEditProposal(w http.ResponseWriter
 	}
 
 	if err := a.db.ForceApproveWorkflowEditProposalAsAdmin(r.Context(), proposalID, *adminId); err != nil {
-		a.logger.Logf("error force approving workflow edit proposal %s by admin %s: %s", proposalID, *adminId, err)
+		a.logger.Errorf(r.Context(), "error force approving workflow edit proposal %s by admin %s: %s", proposalID, *adminId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	updatedProposal, err := a.db.GetWorkflowEditProposalByIDForUser(r.Context(), proposalID, nil)
 	if err != nil {
-		a.logger.Logf("error loading workflow edit proposal %s after admin force approve: %s", proposalID, err)
+		a.logger.Errorf(r.Context(), "error loading workflow edit proposal %s after admin force approve: %s", proposalID, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditWorkflowEditForceApprove, "workflow_edit_proposal", proposalID, workflowAuditState(proposal.Status), workflowAuditState(updatedProposal.Status))
 	sanitizeWorkflowEditProposalForVoteView(updatedProposal)
 
 	w.WriteHeader(http.StatusOK)
@@ -3930,7 +3957,7 @@ func (a *AppService) ProposeWorkflowDeletion(w http.ResponseWriter, r *http.Requ
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading workflow deletion proposal body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading workflow deletion proposal body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3963,7 +3990,7 @@ func (a *AppService) ProposeWorkflowDeletion(w http.ResponseWriter, r *http.Requ
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error creating workflow deletion proposal for requester %s: %s", *requesterId, err)
+		a.logger.Errorf(r.Context(), "error creating workflow deletion proposal for requester %s: %s", *requesterId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3981,7 +4008,7 @@ func (a *AppService) GetVoterWorkflowDeletionProposals(w http.ResponseWriter, r
 
 	proposals, err := a.db.GetWorkflowDeletionProposalsForVoter(r.Context(), *voterId)
 	if err != nil {
-		a.logger.Logf("error loading workflow deletion proposals for voter %s: %s", *voterId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow deletion proposals for voter %s: %s", *voterId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -3999,7 +4026,7 @@ func (a *AppService) GetProposerWorkflowDeletionProposals(w http.ResponseWriter,
 
 	proposals, err := a.db.GetWorkflowDeletionProposalsForVoter(r.Context(), *proposerId)
 	if err != nil {
-		a.logger.Logf("error loading workflow deletion proposals for proposer %s: %s", *proposerId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow deletion proposals for proposer %s: %s", *proposerId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4024,7 +4051,7 @@ func (a *AppService) VoteWorkflowDeletionProposal(w http.ResponseWriter, r *http
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading workflow deletion vote body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading workflow deletion vote body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4046,7 +4073,7 @@ func (a *AppService) VoteWorkflowDeletionProposal(w http.ResponseWriter, r *http
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow deletion proposal %s for vote: %s", proposalId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow deletion proposal %s for vote: %s", proposalId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4057,14 +4084,14 @@ func (a *AppService) VoteWorkflowDeletionProposal(w http.ResponseWriter, r *http
 	}
 
 	if _, err := a.db.RecordWorkflowDeletionVote(r.Context(), proposalId, *voterId, req.Decision, req.Comment); err != nil {
-		a.logger.Logf("error recording workflow deletion vote %s by %s: %s", proposalId, *voterId, err)
+		a.logger.Errorf(r.Context(), "error recording workflow deletion vote %s by %s: %s", proposalId, *voterId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	updatedProposal, err := a.db.EvaluateWorkflowDeletionVoteState(r.Context(), proposalId)
 	if err != nil {
-		a.logger.Logf("error evaluating workflow deletion vote state %s: %s", proposalId, err)
+		a.logger.Errorf(r.Context(), "error evaluating workflow deletion vote state %s: %s", proposalId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4097,7 +4124,7 @@ func (a *AppService) AdminForceApproveWorkflowDeletionProposal(w http.ResponseWr
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error loading workflow deletion proposal %s for admin force approve: %s", proposalId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow deletion proposal %s for admin force approve: %s", proposalId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4109,17 +4136,18 @@ func (a *AppService) AdminForceApproveWorkflowDeletionProposal(w http.ResponseWr
 	}
 
 	if err := a.db.ForceApproveWorkflowDeletionProposalAsAdmin(r.Context(), proposalId, *adminId); err != nil {
-		a.logger.Logf("error force approving workflow deletion proposal %s by admin %s: %s", proposalId, *adminId, err)
+		a.logger.Errorf(r.Context(), "error force approving workflow deletion proposal %s by admin %s: %s", proposalId, *adminId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	updatedProposal, err := a.db.GetWorkflowDeletionProposalByIDForUser(r.Context(), proposalId, nil)
 	if err != nil {
-		a.logger.Logf("error loading workflow deletion proposal %s after admin force approve: %s", proposalId, err)
+		a.logger.Errorf(r.Context(), "error loading workflow deletion proposal %s after admin force approve: %s", proposalId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditWorkflowDeletionForceApprove, "workflow_deletion_proposal", proposalId, workflowAuditState(proposal.Status), workflowAuditState(updatedProposal.Status))
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(updatedProposal)
@@ -4130,7 +4158,7 @@ func (a *AppService) GetIssuers(w http.ResponseWriter, r *http.Request) {
 	page, count := parsePageAndCount(r.URL.Query(), 20, 100)
 	issuers, err := a.db.GetIssuersWithScopes(r.Context(), search, page, count)
 	if err != nil {
-		a.logger.Logf("error getting issuers: %s", err)
+		a.logger.Errorf(r.Context(), "error getting issuers: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4149,7 +4177,7 @@ func (a *AppService) UpdateIssuerScopes(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading issuer scope update body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading issuer scope update body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4160,6 +4188,7 @@ func (a *AppService) UpdateIssuerScopes(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	before := a.issuerScopesAuditState(r.Context(), strings.TrimSpace(req.UserId))
 	issuer, err := a.db.SetIssuerScopes(r.Context(), *adminId, &req)
 	if err != nil {
 		errMsg := err.Error()
@@ -4172,10 +4201,11 @@ func (a *AppService) UpdateIssuerScopes(w http.ResponseWriter, r *http.Request)
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error updating issuer scopes for %s: %s", req.UserId, err)
+		a.logger.Errorf(r.Context(), "error updating issuer scopes for %s: %s", req.UserId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditIssuerScopesUpdate, "user", issuer.UserId, before, issuer)
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(issuer)
@@ -4184,7 +4214,7 @@ func (a *AppService) UpdateIssuerScopes(w http.ResponseWriter, r *http.Request)
 func (a *AppService) GetCredentialTypes(w http.ResponseWriter, r *http.Request) {
 	credentialTypes, err := a.db.GetGlobalCredentialTypes(r.Context())
 	if err != nil {
-		a.logger.Logf("error getting credential types: %s", err)
+		a.logger.Errorf(r.Context(), "error getting credential types: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4202,7 +4232,7 @@ func (a *AppService) GetImproverCredentialRequests(w http.ResponseWriter, r *htt
 
 	requests, err := a.db.GetCredentialRequestsByUser(r.Context(), *userDid)
 	if err != nil {
-		a.logger.Logf("error getting credential requests for improver %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error getting credential requests for improver %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4221,7 +4251,7 @@ func (a *AppService) CreateImproverCredentialRequest(w http.ResponseWriter, r *h
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading improver credential request body for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error reading improver credential request body for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4255,7 +4285,7 @@ func (a *AppService) CreateImproverCredentialRequest(w http.ResponseWriter, r *h
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error creating improver credential request for %s: %s", *userDid, err)
+		a.logger.Errorf(r.Context(), "error creating improver credential request for %s: %s", *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4284,7 +4314,7 @@ func (a *AppService) GetIssuerCredentialRequests(w http.ResponseWriter, r *http.
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error getting credential requests for issuer %s: %s", *issuerId, err)
+		a.logger.Errorf(r.Context(), "error getting credential requests for issuer %s: %s", *issuerId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4331,7 +4361,7 @@ func (a *AppService) DecideIssuerCredentialRequest(w http.ResponseWriter, r *htt
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading issuer credential request decision body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading issuer credential request decision body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4370,7 +4400,7 @@ func (a *AppService) DecideIssuerCredentialRequest(w http.ResponseWriter, r *htt
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error resolving credential request %s by issuer %s: %s", requestId, *issuerId, err)
+		a.logger.Errorf(r.Context(), "error resolving credential request %s by issuer %s: %s", requestId, *issuerId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4392,7 +4422,7 @@ func (a *AppService) GetMyIssuerScopes(w http.ResponseWriter, r *http.Request) {
 	if isAdmin {
 		allTypes, err := a.db.GetGlobalCredentialTypes(r.Context())
 		if err != nil {
-			a.logger.Logf("error getting credential types for admin %s: %s", *userDid, err)
+			a.logger.Errorf(r.Context(), "error getting credential types for admin %s: %s", *userDid, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -4402,7 +4432,7 @@ func (a *AppService) GetMyIssuerScopes(w http.ResponseWriter, r *http.Request) {
 	} else {
 		scopes, err := a.db.GetIssuerScopeCredentials(r.Context(), *userDid)
 		if err != nil {
-			a.logger.Logf("error getting issuer scopes for %s: %s", *userDid, err)
+			a.logger.Errorf(r.Context(), "error getting issuer scopes for %s: %s", *userDid, err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -4428,7 +4458,7 @@ func (a *AppService) IssueCredential(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading issue credential body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading issue credential body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4457,7 +4487,7 @@ func (a *AppService) IssueCredential(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error issuing credential %s to %s by %s: %s", req.CredentialType, req.UserId, *issuerId, err)
+		a.logger.Errorf(r.Context(), "error issuing credential %s to %s by %s: %s", req.CredentialType, req.UserId, *issuerId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4476,7 +4506,7 @@ func (a *AppService) RevokeCredential(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading revoke credential body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading revoke credential body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4505,10 +4535,11 @@ func (a *AppService) RevokeCredential(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error revoking credential %s from %s by %s: %s", req.CredentialType, req.UserId, *issuerId, err)
+		a.logger.Errorf(r.Context(), "error revoking credential %s from %s by %s: %s", req.CredentialType, req.UserId, *issuerId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditCredentialRevoke, "user", req.UserId, map[string]any{"credential_type": req.CredentialType, "active": true}, map[string]any{"credential_type": req.CredentialType, "active": false})
 
 	w.WriteHeader(http.StatusOK)
 }
@@ -4522,7 +4553,7 @@ func (a *AppService) GetIssuerUserCredentials(w http.ResponseWriter, r *http.Req
 
 	credentials, err := a.db.GetUserCredentials(r.Context(), userId)
 	if err != nil {
-		a.logger.Logf("error getting credentials for user %s: %s", userId, err)
+		a.logger.Errorf(r.Context(), "error getting credentials for user %s: %s", userId, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -4536,7 +4567,7 @@ func (a *AppService) workflowApprovalAllowed(ctx context.Context, workflow *stru
 		return false, fmt.Errorf("workflow is required")
 	}
 	if a.bot == nil {
-		a.logger.Logf("workflow vote approval checks are disabled because bot service is not configured")
+		a.logger.Warnf(ctx, "workflow vote approval checks are disabled because bot service is not configured")
 		return false, nil
 	}
 
@@ -4551,7 +4582,7 @@ func (a *AppService) workflowApprovalAllowed(ctx context.Context, workflow *stru
 func (a *AppService) expireStaleWorkflowProposalsAndNotify(ctx context.Context) {
 	expiredNotices, err := a.db.ExpireStaleWorkflowProposals(ctx)
 	if err != nil {
-		a.logger.Logf("error expiring stale workflow proposals: %s", err)
+		a.logger.Errorf(ctx, "error expiring stale workflow proposals: %s", err)
 	} else {
 		for _, notice := range expiredNotices {
 			a.sendWorkflowProposalExpiredEmail(notice)
@@ -4559,7 +4590,7 @@ func (a *AppService) expireStaleWorkflowProposalsAndNotify(ctx context.Context)
 	}
 
 	if err := a.db.ExpireStaleWorkflowEditProposals(ctx); err != nil {
-		a.logger.Logf("error expiring stale workflow edit proposals: %s", err)
+		a.logger.Errorf(ctx, "error expiring stale workflow edit proposals: %s", err)
 	}
 }
 
@@ -4569,7 +4600,7 @@ func (a *AppService) sendWorkflowProposalOutcomeEmailByWorkflow(ctx context.Cont
 		if strings.Contains(err.Error(), "not finalized") {
 			return
 		}
-		a.logger.Logf("error building workflow proposal outcome notification for %s: %s", workflowId, err)
+		a.logger.Errorf(ctx, "error building workflow proposal outcome notification for %s: %s", workflowId, err)
 		return
 	}
 	a.sendWorkflowProposalOutcomeEmail(*notification)
@@ -4581,41 +4612,21 @@ func (a *AppService) sendWorkflowProposalOutcomeEmail(notification structs.Workf
 		return
 	}
 
-	emailSender := utils.NewEmailSender()
-	if emailSender == nil {
-		return
-	}
-
-	outcomeLabel := "approved"
-	subtitle := "Your workflow proposal has been approved."
-	title := "Workflow Proposal Approved"
-	if notification.Decision == "rejected" {
-		outcomeLabel = "rejected"
-		subtitle = "Your workflow proposal has been rejected."
-		title = "Workflow Proposal Rejected"
-	}
-	htmlContent := utils.BuildStyledEmail(
-		title,
-		subtitle,
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:160px;">Workflow</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Workflow ID</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Outcome</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827; font-weight:600;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(notification.WorkflowTitle), utils.EscapeEmailHTML(notification.WorkflowId), utils.EscapeEmailHTML(strings.ToUpper(outcomeLabel))),
-	)
-
-	if err := emailSender.SendEmail(toEmail, "Proposer", title, htmlContent, utils.NotificationFromEmail(), "SFLuv Workflows"); err != nil {
-		a.logger.Logf("error sending workflow proposal outcome email for %s: %s", notification.WorkflowId, err)
+	if err := a.Notify(context.Background(), &Notification{
+		Category: structs.NotificationCategoryWorkflowProposals,
+		Email: &NotificationEmail{
+			To:       toEmail,
+			ToName:   "Proposer",
+			Template: "workflow_proposal_outcome",
+			Data: emails.Data{
+				"WorkflowTitle": notification.WorkflowTitle,
+				"WorkflowId":    notification.WorkflowId,
+				"Approved":      notification.Decision != "rejected",
+			},
+			FromName: "SFLuv Workflows",
+		},
+	}); err != nil {
+		a.logger.Errorf(context.Background(), "error sending workflow proposal outcome email for %s: %s", notification.WorkflowId, err)
 	}
 }
 
@@ -4625,34 +4636,24 @@ func (a *AppService) sendWorkflowProposalExpiredEmail(notification structs.Workf
 		return
 	}
 
-	emailSender := utils.NewEmailSender()
-	if emailSender == nil {
-		return
-	}
-
-	title := "Workflow Proposal Expired"
-	htmlContent := utils.BuildStyledEmail(
-		title,
-		"Your workflow proposal expired before reaching a voting decision.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:160px;">Workflow</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Workflow ID</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(notification.WorkflowTitle), utils.EscapeEmailHTML(notification.WorkflowId)),
-	)
-
-	if err := emailSender.SendEmail(toEmail, "Proposer", title, htmlContent, utils.NotificationFromEmail(), "SFLuv Workflows"); err != nil {
-		a.logger.Logf("error sending workflow proposal expiry email for %s: %s", notification.WorkflowId, err)
+	if err := a.Notify(context.Background(), &Notification{
+		Category: structs.NotificationCategoryWorkflowProposals,
+		Email: &NotificationEmail{
+			To:       toEmail,
+			ToName:   "Proposer",
+			Template: "workflow_proposal_expired",
+			Data: emails.Data{
+				"WorkflowTitle": notification.WorkflowTitle,
+				"WorkflowId":    notification.WorkflowId,
+			},
+			FromName: "SFLuv Workflows",
+		},
+	}); err != nil {
+		a.logger.Errorf(context.Background(), "error sending workflow proposal expiry email for %s: %s", notification.WorkflowId, err)
 	}
 }
 
-func (a *AppService) sendRoleRequestEmail(envKey string, title string, subtitle string, details string) {
+func (a *AppService) sendRoleRequestEmail(envKey string, template string, data emails.Data) {
 	adminEmail := strings.TrimSpace(os.Getenv(envKey))
 	if adminEmail == "" {
 		adminEmail = strings.TrimSpace(os.Getenv("AFFILIATE_ADMIN_EMAIL"))
@@ -4661,32 +4662,24 @@ func (a *AppService) sendRoleRequestEmail(envKey string, title string, subtitle
 		return
 	}
 
-	emailSender := utils.NewEmailSender()
-	if emailSender == nil {
-		return
-	}
-
-	htmlContent := utils.BuildStyledEmail(title, subtitle, details)
-	if err := emailSender.SendEmail(adminEmail, "Admin", title, htmlContent, utils.NotificationFromEmail(), "SFLuv Workflows"); err != nil {
-		a.logger.Logf("error sending %s email: %s", title, err.Error())
+	if err := a.Notify(context.Background(), &Notification{
+		Category: structs.NotificationCategoryAdmin,
+		Email:    &NotificationEmail{To: adminEmail, ToName: "Admin", Template: template, Data: data, FromName: "SFLuv Workflows"},
+	}); err != nil {
+		a.logger.Errorf(context.Background(), "error sending %s email: %s", template, err.Error())
 	}
 }
 
 func (a *AppService) sendCredentialRequestEmails(ctx context.Context, request structs.CredentialRequest) {
 	recipients, err := a.db.GetIssuersAllowedForCredential(ctx, request.CredentialType)
 	if err != nil {
-		a.logger.Logf("error loading issuer recipients for credential request %s: %s", request.Id, err)
+		a.logger.Errorf(ctx, "error loading issuer recipients for credential request %s: %s", request.Id, err)
 		return
 	}
 	if len(recipients) == 0 {
 		return
 	}
 
-	emailSender := utils.NewEmailSender()
-	if emailSender == nil {
-		return
-	}
-
 	credentialLabel := request.CredentialType
 	types, err := a.db.GetGlobalCredentialTypes(ctx)
 	if err == nil {
@@ -4698,46 +4691,15 @@ func (a *AppService) sendCredentialRequestEmails(ctx context.Context, request st
 		}
 	}
 
-	title := "New Credential Request"
-	htmlContent := utils.BuildStyledEmail(
-		title,
-		"A user requested a credential your issuer account can grant.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:170px;">Requester</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Requester Email</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Credential</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s (%s)</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Request ID</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Requester User ID</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Requested At (UTC)</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>
-</table>`,
-			utils.EscapeEmailHTML(request.RequesterName),
-			utils.EscapeEmailHTML(request.RequesterEmail),
-			utils.EscapeEmailHTML(credentialLabel),
-			utils.EscapeEmailHTML(request.CredentialType),
-			utils.EscapeEmailHTML(request.Id),
-			utils.EscapeEmailHTML(request.UserId),
-			utils.EscapeEmailHTML(request.RequestedAt.UTC().Format(time.RFC3339)),
-		),
-	)
+	data := emails.Data{
+		"RequesterName":   request.RequesterName,
+		"RequesterEmail":  request.RequesterEmail,
+		"CredentialLabel": credentialLabel,
+		"CredentialType":  request.CredentialType,
+		"RequestId":       request.Id,
+		"RequesterUserId": request.UserId,
+		"RequestedAt":     request.RequestedAt.UTC().Format(time.RFC3339),
+	}
 
 	sentEmails := map[string]struct{}{}
 	for _, recipient := range recipients {
@@ -4755,8 +4717,12 @@ func (a *AppService) sendCredentialRequestEmails(ctx context.Context, request st
 			recipientName = "Issuer"
 		}
 
-		if err := emailSender.SendEmail(toEmail, recipientName, title, htmlContent, utils.NotificationFromEmail(), "SFLuv Workflows"); err != nil {
-			a.logger.Logf("error sending credential request email %s to issuer %s: %s", request.Id, recipient.UserId, err.Error())
+		if err := a.Notify(ctx, &Notification{
+			Category: structs.NotificationCategoryCredentials,
+			UserId:   recipient.UserId,
+			Email:    &NotificationEmail{To: toEmail, ToName: recipientName, Template: "credential_request", Data: data, FromName: "SFLuv Workflows"},
+		}); err != nil {
+			a.logger.Errorf(ctx, "error sending credential request email %s to issuer %s: %s", request.Id, recipient.UserId, err.Error())
 		}
 	}
 }
@@ -4766,13 +4732,13 @@ func (a *AppService) sendWorkflowSeriesFundingShortfallEmails(ctx context.Contex
 		return
 	}
 	if a.bot == nil {
-		a.logger.Logf("workflow series funding checks skipped: bot service is not configured")
+		a.logger.Warnf(ctx, "workflow series funding checks skipped: bot service is not configured")
 		return
 	}
 
 	unallocatedTokens, err := a.bot.unallocatedBalanceTokens(ctx)
 	if err != nil {
-		a.logger.Logf("error getting unallocated faucet balance for workflow series funding checks: %s", err)
+		a.logger.Errorf(ctx, "error getting unallocated faucet balance for workflow series funding checks: %s", err)
 		return
 	}
 
@@ -4783,46 +4749,16 @@ func (a *AppService) sendWorkflowSeriesFundingShortfallEmails(ctx context.Contex
 		}
 
 		shortfallTokens := new(big.Int).Sub(requiredTokens, unallocatedTokens)
-		a.sendRoleRequestEmail(
-			"WORKFLOW_ADMIN_EMAIL",
-			"Workflow Series Funding Shortfall",
-			"A series workflow reached start time with insufficient unallocated faucet balance.",
-			fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:170px;">Workflow</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Workflow ID</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Series ID</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Recurrence</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Start At (UTC)</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Required Unallocated</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s SFLuv</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Current Unallocated</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s SFLuv</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Amount Needed</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827; font-weight:600;">%s SFLuv</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(check.WorkflowTitle), utils.EscapeEmailHTML(check.WorkflowId), utils.EscapeEmailHTML(check.SeriesId), utils.EscapeEmailHTML(check.Recurrence), utils.EscapeEmailHTML(time.Unix(check.StartAt, 0).UTC().Format(time.RFC3339)), utils.EscapeEmailHTML(requiredTokens.String()), utils.EscapeEmailHTML(unallocatedTokens.String()), utils.EscapeEmailHTML(shortfallTokens.String())),
-		)
+		a.sendRoleRequestEmail("WORKFLOW_ADMIN_EMAIL", "workflow_funding_shortfall", emails.Data{
+			"WorkflowTitle": check.WorkflowTitle,
+			"WorkflowId":    check.WorkflowId,
+			"SeriesId":      check.SeriesId,
+			"Recurrence":    check.Recurrence,
+			"StartAt":       time.Unix(check.StartAt, 0).UTC().Format(time.RFC3339),
+			"Required":      requiredTokens.String(),
+			"Current":       unallocatedTokens.String(),
+			"Shortfall":     shortfallTokens.String(),
+		})
 	}
 }
 
@@ -4862,6 +4798,10 @@ func collectWorkflowPayoutTargets(workflow *structs.Workflow) []workflowPayoutTa
 		if step.PayoutInProgress {
 			continue
 		}
+		// Completions held for review are paid once a supervisor approves them.
+		if step.ReviewStatus != "" && step.ReviewStatus != structs.WorkflowStepReviewApproved {
+			continue
+		}
 		manualRetryRequired := step.PayoutError != nil && strings.TrimSpace(*step.PayoutError) != ""
 		if manualRetryRequired && step.RetryRequestedAt == nil {
 			continue
@@ -4903,43 +4843,34 @@ func collectWorkflowPayoutTargets(workflow *structs.Workflow) []workflowPayoutTa
 	return targets
 }
 
-func (a *AppService) attemptWorkflowPayoutTransfer(ctx context.Context, amount uint64, walletAddress string) (*big.Int, *big.Int, bool, string, error) {
+func (a *AppService) submitWorkflowPayoutTransfer(ctx context.Context, payoutBot bot.IBot, amount uint64, walletAddress string) (*big.Int, *big.Int, bool, string, error) {
 	neededTokens := new(big.Int).SetUint64(amount)
 
-	if a.bot == nil || a.bot.bot == nil {
+	if payoutBot == nil {
 		return nil, neededTokens, false, "", fmt.Errorf("bot service is not configured")
 	}
 
-	faucetBalanceWei, err := a.bot.bot.Balance()
+	faucetBalanceWei, err := payoutBot.Balance(ctx)
 	if err != nil {
 		return nil, neededTokens, false, "", fmt.Errorf("error checking faucet balance: %s", err)
 	}
 
-	multiplier, err := getTokenMultiplier()
-	if err != nil {
-		return nil, neededTokens, false, "", fmt.Errorf("error reading token decimals: %s", err)
-	}
+	// Token decimals can differ between chains, so scale by the payout
+	// chain's token rather than TOKEN_DECIMALS.
+	multiplier := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(payoutBot.TokenDecimals())), nil)
 
 	currentTokens := new(big.Int).Div(faucetBalanceWei, multiplier)
 	if currentTokens.Cmp(neededTokens) < 0 {
 		return currentTokens, neededTokens, true, "", fmt.Errorf("insufficient faucet balance for workflow payout")
 	}
 
-	txHash, err := a.bot.bot.SubmitTransfer(amount, walletAddress)
+	txHash, err := payoutBot.SubmitTransfer(ctx, amount, walletAddress)
 	if err != nil {
 		errLower := strings.ToLower(err.Error())
 		isInsufficient := strings.Contains(errLower, "insufficient")
 		return currentTokens, neededTokens, isInsufficient, txHash, err
 	}
 
-	waitCtx, waitCancel := context.WithTimeout(context.Background(), 2*time.Minute)
-	defer waitCancel()
-	if err := a.waitForWorkflowPayoutTransferConfirmation(waitCtx, txHash, walletAddress, amount); err != nil {
-		errLower := strings.ToLower(err.Error())
-		isInsufficient := strings.Contains(errLower, "insufficient")
-		return currentTokens, neededTokens, isInsufficient, txHash, err
-	}
-
 	return currentTokens, neededTokens, false, txHash, nil
 }
 
@@ -4969,97 +4900,43 @@ func (a *AppService) sendWorkflowPayoutErrorEmail(
 		}
 	}
 
-	targetLabel := "Workflow Step"
-	targetDetails := fmt.Sprintf("Step %d: %s", target.StepOrder, target.StepTitle)
-	if target.IsManager {
-		targetLabel = "Workflow Manager"
-		targetDetails = "Workflow manager completion payout"
+	data := emails.Data{
+		"WorkflowTitle":  target.WorkflowTitle,
+		"WorkflowId":     target.WorkflowId,
+		"SeriesId":       target.SeriesId,
+		"StepOrder":      target.StepOrder,
+		"StepTitle":      target.StepTitle,
+		"IsManager":      target.IsManager,
+		"ImproverName":   improverName,
+		"ImproverId":     target.ImproverId,
+		"ImproverEmail":  improverEmail,
+		"WalletAddress":  strings.TrimSpace(walletAddress),
+		"Amount":         target.Amount,
+		"Insufficient":   false,
+		"CurrentBalance": "",
+		"NeededBalance":  "",
+		"Shortfall":      "",
+		"Error":          errorMessage,
 	}
-
-	extraRows := ""
 	if isInsufficient && currentBalance != nil && neededBalance != nil {
 		shortfall := new(big.Int).Sub(new(big.Int).Set(neededBalance), currentBalance)
 		if shortfall.Sign() < 0 {
 			shortfall = big.NewInt(0)
 		}
-		extraRows = fmt.Sprintf(`
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Current Faucet Balance</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s SFLuv</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Needed For This Payout</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s SFLuv</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Amount Needed</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; font-weight:600;">%s SFLuv</td>
-  </tr>`, utils.EscapeEmailHTML(currentBalance.String()), utils.EscapeEmailHTML(neededBalance.String()), utils.EscapeEmailHTML(shortfall.String()))
-	}
-
-	a.sendRoleRequestEmail(
-		"WORKFLOW_ADMIN_EMAIL",
-		"Workflow Payout Error",
-		"A workflow payout attempt failed.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:180px;">Workflow</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Workflow ID</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Series ID</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Target</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Target Details</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Improver</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s (%s)</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Improver Email</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Payout Wallet</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Payout Amount</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%d SFLuv</td>
-  </tr>%s
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Error</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827; white-space:pre-wrap;">%s</td>
-  </tr>
-</table>`,
-			utils.EscapeEmailHTML(target.WorkflowTitle),
-			utils.EscapeEmailHTML(target.WorkflowId),
-			utils.EscapeEmailHTML(target.SeriesId),
-			utils.EscapeEmailHTML(targetLabel),
-			utils.EscapeEmailHTML(targetDetails),
-			utils.EscapeEmailHTML(improverName),
-			utils.EscapeEmailHTML(target.ImproverId),
-			utils.EscapeEmailHTML(improverEmail),
-			utils.EscapeEmailHTML(strings.TrimSpace(walletAddress)),
-			target.Amount,
-			extraRows,
-			utils.EscapeEmailHTML(errorMessage),
-		),
-	)
+		data["Insufficient"] = true
+		data["CurrentBalance"] = currentBalance.String()
+		data["NeededBalance"] = neededBalance.String()
+		data["Shortfall"] = shortfall.String()
+	}
+
+	a.sendRoleRequestEmail("WORKFLOW_ADMIN_EMAIL", "workflow_payout_error", data)
 }
 
+// processWorkflowSeriesPayouts walks the series containing triggerWorkflowID
+// in order and queues payout jobs for every payable target on the first
+// workflow that is not yet settled. Transfers themselves run on the
+// WorkflowPayoutQueue worker, which calls back in here once a job settles so
+// later workflows in the series can advance.
 func (a *AppService) processWorkflowSeriesPayouts(ctx context.Context, triggerWorkflowID string) {
 	triggerWorkflowID = strings.TrimSpace(triggerWorkflowID)
 	if triggerWorkflowID == "" {
@@ -5070,16 +4947,11 @@ func (a *AppService) processWorkflowSeriesPayouts(ctx context.Context, triggerWo
 	defer cancel()
 	ctx = processCtx
 
-	if a.bot == nil || a.bot.bot == nil {
-		a.logger.Logf("workflow payout processing skipped for %s: bot service is not configured", triggerWorkflowID)
-		return
-	}
-
 	staleBefore := time.Now().UTC().Add(-workflowPayoutStaleLockTimeout).Unix()
 	if recoveredSteps, recoveredManagers, err := a.db.RecoverStaleWorkflowPayoutLocksForSeries(ctx, triggerWorkflowID, staleBefore, workflowPayoutErrorTimedOut); err != nil {
-		a.logger.Logf("error recovering stale payout locks before payout processing for %s: %s", triggerWorkflowID, err)
+		a.logger.Errorf(ctx, "error recovering stale payout locks before payout processing for %s: %s", triggerWorkflowID, err)
 	} else if recoveredSteps > 0 || recoveredManagers > 0 {
-		a.logger.Logf(
+		a.logger.Infof(ctx,
 			"recovered stale payout locks before payout processing for %s: steps=%d managers=%d",
 			triggerWorkflowID,
 			recoveredSteps,
@@ -5088,20 +4960,27 @@ func (a *AppService) processWorkflowSeriesPayouts(ctx context.Context, triggerWo
 	}
 
 	if err := a.refreshWorkflowStartAvailabilityAndNotify(ctx); err != nil {
-		a.logger.Logf("error refreshing workflow availability before payout processing for %s: %s", triggerWorkflowID, err)
+		a.logger.Errorf(ctx, "error refreshing workflow availability before payout processing for %s: %s", triggerWorkflowID, err)
 		return
 	}
 
 	workflowIDs, err := a.db.GetWorkflowSeriesOrderedIDs(ctx, triggerWorkflowID)
 	if err != nil {
-		a.logger.Logf("error loading workflow series order for payout processing %s: %s", triggerWorkflowID, err)
+		a.logger.Errorf(ctx, "error loading workflow series order for payout processing %s: %s", triggerWorkflowID, err)
 		return
 	}
 
+	enqueued := false
+	defer func() {
+		if enqueued {
+			a.payoutQueue.Notify()
+		}
+	}()
+
 	for _, workflowID := range workflowIDs {
 		workflow, err := a.db.GetWorkflowByID(ctx, workflowID)
 		if err != nil {
-			a.logger.Logf("error loading workflow %s during payout processing: %s", workflowID, err)
+			a.logger.Errorf(ctx, "error loading workflow %s during payout processing: %s", workflowID, err)
 			return
 		}
 
@@ -5111,207 +4990,33 @@ func (a *AppService) processWorkflowSeriesPayouts(ctx context.Context, triggerWo
 		case "in_progress":
 			targets := collectWorkflowPayoutTargets(workflow)
 			for _, target := range targets {
-				if target.Amount == 0 {
-					if _, markErr := a.db.MarkWorkflowStepPaidOut(ctx, target.WorkflowId, target.StepId); markErr != nil {
-						a.logger.Logf("error auto-settling zero-value workflow step payout target workflow %s step %s: %s", target.WorkflowId, target.StepId, markErr)
-						return
-					}
-					continue
-				}
-
-				claimed, claimErr := a.db.ClaimWorkflowStepPayoutAttempt(ctx, target.WorkflowId, target.StepId)
-				if claimErr != nil {
-					a.logger.Logf("error claiming step payout attempt lock for workflow %s step %s: %s", target.WorkflowId, target.StepId, claimErr)
-					return
-				}
-				if !claimed {
-					continue
-				}
-
-				walletAddress := ""
-				if target.ImproverId == "" {
-					errMsg := workflowPayoutErrorNoImproverAssigned
-					if dbErr := a.db.MarkWorkflowStepPayoutFailed(ctx, target.WorkflowId, target.StepId, errMsg); dbErr != nil {
-						a.logger.Logf("error recording step payout assignment failure for workflow %s step %s: %s", target.WorkflowId, target.StepId, dbErr)
-						return
-					}
-					a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, errMsg, nil, nil, false)
-					continue
-				}
-
-				walletAddress, err = a.db.GetPreferredWorkflowPayoutAddressForUser(ctx, target.ImproverId, false)
-				if err != nil {
-					errMsg := workflowPayoutErrorNoPayoutWallet
-					if dbErr := a.db.MarkWorkflowStepPayoutFailed(ctx, target.WorkflowId, target.StepId, errMsg); dbErr != nil {
-						a.logger.Logf("error recording step payout wallet failure for workflow %s step %s: %s", target.WorkflowId, target.StepId, dbErr)
-						return
-					}
-					a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, fmt.Sprintf("%s Detail: %s", errMsg, err), nil, nil, false)
-					continue
-				}
-
-				currentBalance, neededBalance, insufficient, txHash, transferErr := a.attemptWorkflowPayoutTransfer(ctx, target.Amount, walletAddress)
-				if strings.TrimSpace(txHash) != "" {
-					if dbErr := a.db.RecordWorkflowStepPayoutTxHash(ctx, target.WorkflowId, target.StepId, txHash, a.activeChainID()); dbErr != nil {
-						a.logger.Logf("error recording step payout tx hash for workflow %s step %s: %s", target.WorkflowId, target.StepId, dbErr)
-						return
-					}
-				}
-				if transferErr != nil {
-					errMsg := workflowPayoutErrorTransferFailed
-					if insufficient {
-						errMsg = workflowPayoutErrorInsufficientFaucet
-					}
-					if dbErr := a.db.MarkWorkflowStepPayoutFailed(ctx, target.WorkflowId, target.StepId, errMsg); dbErr != nil {
-						a.logger.Logf("error recording step payout transfer failure for workflow %s step %s: %s", target.WorkflowId, target.StepId, dbErr)
-						return
-					}
-					a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, fmt.Sprintf("%s Detail: %s", errMsg, transferErr), currentBalance, neededBalance, insufficient)
-					continue
-				}
-
-				if _, err := a.db.MarkWorkflowStepPaidOut(ctx, target.WorkflowId, target.StepId); err != nil {
-					errMsg := fmt.Sprintf("workflow payout post-transfer state update failed: %s", err)
-					a.logger.Logf("error marking step payout complete for workflow %s step %s: %s", target.WorkflowId, target.StepId, err)
-					a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, errMsg, nil, nil, false)
+				queued, ok := a.enqueueWorkflowPayoutTarget(ctx, target)
+				if !ok {
 					return
 				}
+				enqueued = enqueued || queued
 			}
 			// Hold later workflows in the series until this workflow is fully finished.
 			return
 		case "completed":
 			targets := collectWorkflowPayoutTargets(workflow)
 			for _, target := range targets {
-				if target.Amount == 0 {
-					var markErr error
-					if target.IsManager {
-						_, markErr = a.db.MarkWorkflowManagerPaidOut(ctx, target.WorkflowId)
-					} else {
-						_, markErr = a.db.MarkWorkflowStepPaidOut(ctx, target.WorkflowId, target.StepId)
-					}
-					if markErr != nil {
-						a.logger.Logf("error auto-settling zero-value workflow payout target workflow %s step %s manager %t: %s", target.WorkflowId, target.StepId, target.IsManager, markErr)
-						return
-					}
-					continue
-				}
-
-				if target.IsManager {
-					claimed, claimErr := a.db.ClaimWorkflowManagerPayoutAttempt(ctx, target.WorkflowId)
-					if claimErr != nil {
-						a.logger.Logf("error claiming manager payout attempt lock for workflow %s: %s", target.WorkflowId, claimErr)
-						return
-					}
-					if !claimed {
-						continue
-					}
-				} else {
-					claimed, claimErr := a.db.ClaimWorkflowStepPayoutAttempt(ctx, target.WorkflowId, target.StepId)
-					if claimErr != nil {
-						a.logger.Logf("error claiming step payout attempt lock for workflow %s step %s: %s", target.WorkflowId, target.StepId, claimErr)
-						return
-					}
-					if !claimed {
-						continue
-					}
-				}
-
-				walletAddress := ""
-				if target.ImproverId == "" {
-					errMsg := workflowPayoutErrorNoImproverAssigned
-					if target.IsManager {
-						if dbErr := a.db.MarkWorkflowManagerPayoutFailed(ctx, target.WorkflowId, errMsg); dbErr != nil {
-							a.logger.Logf("error recording manager payout assignment failure for workflow %s: %s", target.WorkflowId, dbErr)
-							return
-						}
-					} else {
-						if dbErr := a.db.MarkWorkflowStepPayoutFailed(ctx, target.WorkflowId, target.StepId, errMsg); dbErr != nil {
-							a.logger.Logf("error recording step payout assignment failure for workflow %s step %s: %s", target.WorkflowId, target.StepId, dbErr)
-							return
-						}
-					}
-					a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, errMsg, nil, nil, false)
-					continue
-				}
-
-				walletAddress, err = a.db.GetPreferredWorkflowPayoutAddressForUser(ctx, target.ImproverId, target.IsManager)
-				if err != nil {
-					errMsg := workflowPayoutErrorNoPayoutWallet
-					if target.IsManager {
-						if dbErr := a.db.MarkWorkflowManagerPayoutFailed(ctx, target.WorkflowId, errMsg); dbErr != nil {
-							a.logger.Logf("error recording manager payout wallet failure for workflow %s: %s", target.WorkflowId, dbErr)
-							return
-						}
-					} else {
-						if dbErr := a.db.MarkWorkflowStepPayoutFailed(ctx, target.WorkflowId, target.StepId, errMsg); dbErr != nil {
-							a.logger.Logf("error recording step payout wallet failure for workflow %s step %s: %s", target.WorkflowId, target.StepId, dbErr)
-							return
-						}
-					}
-					a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, fmt.Sprintf("%s Detail: %s", errMsg, err), nil, nil, false)
-					continue
-				}
-
-				currentBalance, neededBalance, insufficient, txHash, transferErr := a.attemptWorkflowPayoutTransfer(ctx, target.Amount, walletAddress)
-				if strings.TrimSpace(txHash) != "" {
-					if target.IsManager {
-						if dbErr := a.db.RecordWorkflowManagerPayoutTxHash(ctx, target.WorkflowId, txHash, a.activeChainID()); dbErr != nil {
-							a.logger.Logf("error recording manager payout tx hash for workflow %s: %s", target.WorkflowId, dbErr)
-							return
-						}
-					} else {
-						if dbErr := a.db.RecordWorkflowStepPayoutTxHash(ctx, target.WorkflowId, target.StepId, txHash, a.activeChainID()); dbErr != nil {
-							a.logger.Logf("error recording step payout tx hash for workflow %s step %s: %s", target.WorkflowId, target.StepId, dbErr)
-							return
-						}
-					}
-				}
-				if transferErr != nil {
-					errMsg := workflowPayoutErrorTransferFailed
-					if insufficient {
-						errMsg = workflowPayoutErrorInsufficientFaucet
-					}
-					if target.IsManager {
-						if dbErr := a.db.MarkWorkflowManagerPayoutFailed(ctx, target.WorkflowId, errMsg); dbErr != nil {
-							a.logger.Logf("error recording manager payout transfer failure for workflow %s: %s", target.WorkflowId, dbErr)
-							return
-						}
-					} else {
-						if dbErr := a.db.MarkWorkflowStepPayoutFailed(ctx, target.WorkflowId, target.StepId, errMsg); dbErr != nil {
-							a.logger.Logf("error recording step payout transfer failure for workflow %s step %s: %s", target.WorkflowId, target.StepId, dbErr)
-							return
-						}
-					}
-					a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, fmt.Sprintf("%s Detail: %s", errMsg, transferErr), currentBalance, neededBalance, insufficient)
-					continue
-				}
-
-				if target.IsManager {
-					if _, err := a.db.MarkWorkflowManagerPaidOut(ctx, target.WorkflowId); err != nil {
-						errMsg := fmt.Sprintf("workflow payout post-transfer state update failed: %s", err)
-						a.logger.Logf("error marking manager payout complete for workflow %s: %s", target.WorkflowId, err)
-						a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, errMsg, nil, nil, false)
-						return
-					}
-				} else {
-					if _, err := a.db.MarkWorkflowStepPaidOut(ctx, target.WorkflowId, target.StepId); err != nil {
-						errMsg := fmt.Sprintf("workflow payout post-transfer state update failed: %s", err)
-						a.logger.Logf("error marking step payout complete for workflow %s step %s: %s", target.WorkflowId, target.StepId, err)
-						a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, errMsg, nil, nil, false)
-						return
-					}
+				queued, ok := a.enqueueWorkflowPayoutTarget(ctx, target)
+				if !ok {
+					return
 				}
+				enqueued = enqueued || queued
 			}
 
 			settled, err := a.db.FinalizeWorkflowPaidOutIfSettled(ctx, workflowID)
 			if err != nil {
-				a.logger.Logf("error finalizing workflow paid_out status for workflow %s: %s", workflowID, err)
+				a.logger.Errorf(ctx, "error finalizing workflow paid_out status for workflow %s: %s", workflowID, err)
 				return
 			}
 			if !settled {
 				updatedWorkflow, refreshErr := a.db.GetWorkflowByID(ctx, workflowID)
 				if refreshErr != nil {
-					a.logger.Logf("error refreshing workflow %s after payout processing: %s", workflowID, refreshErr)
+					a.logger.Errorf(ctx, "error refreshing workflow %s after payout processing: %s", workflowID, refreshErr)
 					return
 				}
 				if workflowHasBlockingPendingPayouts(updatedWorkflow) {
@@ -5327,91 +5032,117 @@ func (a *AppService) processWorkflowSeriesPayouts(ctx context.Context, triggerWo
 	}
 }
 
+// enqueueWorkflowPayoutTarget settles zero-value targets inline and otherwise
+// claims the target's payout lock and hands it to the payout queue. It returns
+// whether a job was queued and whether series processing should continue.
+func (a *AppService) enqueueWorkflowPayoutTarget(ctx context.Context, target workflowPayoutTarget) (bool, bool) {
+	if target.Amount == 0 {
+		var markErr error
+		if target.IsManager {
+			_, markErr = a.db.MarkWorkflowManagerPaidOut(ctx, target.WorkflowId)
+		} else {
+			_, markErr = a.db.MarkWorkflowStepPaidOut(ctx, target.WorkflowId, target.StepId)
+		}
+		if markErr != nil {
+			a.logger.Errorf(ctx, "error auto-settling zero-value workflow payout target workflow %s step %s manager %t: %s", target.WorkflowId, target.StepId, target.IsManager, markErr)
+			return false, false
+		}
+		return false, true
+	}
+
+	targetType := structs.WorkflowPayoutTargetStep
+	if target.IsManager {
+		targetType = structs.WorkflowPayoutTargetSupervisor
+		claimed, claimErr := a.db.ClaimWorkflowManagerPayoutAttempt(ctx, target.WorkflowId)
+		if claimErr != nil {
+			a.logger.Errorf(ctx, "error claiming manager payout attempt lock for workflow %s: %s", target.WorkflowId, claimErr)
+			return false, false
+		}
+		if !claimed {
+			return false, true
+		}
+	} else {
+		claimed, claimErr := a.db.ClaimWorkflowStepPayoutAttempt(ctx, target.WorkflowId, target.StepId)
+		if claimErr != nil {
+			a.logger.Errorf(ctx, "error claiming step payout attempt lock for workflow %s step %s: %s", target.WorkflowId, target.StepId, claimErr)
+			return false, false
+		}
+		if !claimed {
+			return false, true
+		}
+	}
+
+	queued, err := a.db.EnqueueWorkflowPayoutJob(ctx, target.WorkflowId, target.StepId, targetType, target.Amount, true, workflowPayoutJobMaxAttempts())
+	if err != nil {
+		a.logger.Errorf(ctx, "error enqueueing payout job for workflow %s step %s manager %t: %s", target.WorkflowId, target.StepId, target.IsManager, err)
+		var dbErr error
+		if target.IsManager {
+			dbErr = a.db.MarkWorkflowManagerPayoutFailed(ctx, target.WorkflowId, workflowPayoutErrorProcessingFailed)
+		} else {
+			dbErr = a.db.MarkWorkflowStepPayoutFailed(ctx, target.WorkflowId, target.StepId, workflowPayoutErrorProcessingFailed)
+		}
+		if dbErr != nil {
+			a.logger.Errorf(ctx, "error releasing payout lock after enqueue failure for workflow %s step %s manager %t: %s", target.WorkflowId, target.StepId, target.IsManager, dbErr)
+		}
+		return false, false
+	}
+	return queued, true
+}
+
 func (a *AppService) sendWorkflowStepAvailableEmail(notification structs.WorkflowStepAvailabilityNotification) {
 	toEmail := strings.TrimSpace(notification.Email)
 	if toEmail == "" {
 		return
 	}
 
-	emailSender := utils.NewEmailSender()
-	if emailSender == nil {
-		return
-	}
-
 	recipientName := strings.TrimSpace(notification.Name)
 	if recipientName == "" {
 		recipientName = "Improver"
 	}
 
-	title := "Workflow Step Available"
-	htmlContent := utils.BuildStyledEmail(
-		title,
-		"A step assigned to you is now ready for completion.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:140px;">Workflow</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Step</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Workflow ID</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(notification.WorkflowTitle), utils.EscapeEmailHTML(notification.StepTitle), utils.EscapeEmailHTML(notification.WorkflowId)),
-	)
-
-	if err := emailSender.SendEmail(toEmail, recipientName, title, htmlContent, utils.NotificationFromEmail(), "SFLuv Workflows"); err != nil {
-		a.logger.Logf("error sending step-available email for workflow %s step %s user %s: %s", notification.WorkflowId, notification.StepId, notification.UserId, err.Error())
+	if err := a.Notify(context.Background(), &Notification{
+		Category: structs.NotificationCategoryWorkflowSteps,
+		UserId:   notification.UserId,
+		Email: &NotificationEmail{
+			To:       toEmail,
+			ToName:   recipientName,
+			Template: "workflow_step_available",
+			Data: emails.Data{
+				"WorkflowTitle": notification.WorkflowTitle,
+				"StepTitle":     notification.StepTitle,
+				"WorkflowId":    notification.WorkflowId,
+			},
+			FromName: "SFLuv Workflows",
+		},
+	}); err != nil {
+		a.logger.Errorf(context.Background(), "error sending step-available email for workflow %s step %s user %s: %s", notification.WorkflowId, notification.StepId, notification.UserId, err.Error())
 	}
 }
 
 const (
-	workflowDropdownEmailAttachmentMaxDimension = 1280
+	workflowDropdownEmailAttachmentMaxDimension = photoproc.MediumMaxDimension
 	workflowDropdownEmailAttachmentJPEGQuality  = 82
 )
 
-func resizeWorkflowEmailAttachmentImage(img image.Image, maxDimension int) image.Image {
-	if img == nil || maxDimension <= 0 {
-		return img
-	}
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
-	if width <= 0 || height <= 0 {
-		return img
-	}
-
-	longestEdge := width
-	if height > longestEdge {
-		longestEdge = height
+// workflowEmailAttachmentJPEG returns photo as a JPEG no larger than the
+// medium rendition. Photos with a medium rendition already are; older ones
+// are resized here.
+func workflowEmailAttachmentJPEG(photo *structs.WorkflowSubmissionPhotoBlob) ([]byte, error) {
+	decodedImage, format, err := image.Decode(bytes.NewReader(photo.PhotoData))
+	if err != nil {
+		return nil, err
 	}
-	if longestEdge <= maxDimension {
-		return img
+	bounds := decodedImage.Bounds()
+	if format == "jpeg" && bounds.Dx() <= workflowDropdownEmailAttachmentMaxDimension && bounds.Dy() <= workflowDropdownEmailAttachmentMaxDimension {
+		return photo.PhotoData, nil
 	}
 
-	targetWidth := width
-	targetHeight := height
-	if width >= height {
-		targetWidth = maxDimension
-		targetHeight = max(1, int(float64(height)*float64(maxDimension)/float64(width)))
-	} else {
-		targetHeight = maxDimension
-		targetWidth = max(1, int(float64(width)*float64(maxDimension)/float64(height)))
-	}
-
-	scaled := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
-	for y := 0; y < targetHeight; y++ {
-		sourceY := bounds.Min.Y + (y * height / targetHeight)
-		for x := 0; x < targetWidth; x++ {
-			sourceX := bounds.Min.X + (x * width / targetWidth)
-			scaled.Set(x, y, img.At(sourceX, sourceY))
-		}
+	var jpegBuffer bytes.Buffer
+	scaledImage := photoproc.Fit(decodedImage, workflowDropdownEmailAttachmentMaxDimension)
+	if err := jpeg.Encode(&jpegBuffer, scaledImage, &jpeg.Options{Quality: workflowDropdownEmailAttachmentJPEGQuality}); err != nil {
+		return nil, err
 	}
-	return scaled
+	return jpegBuffer.Bytes(), nil
 }
 
 func sanitizeWorkflowEmailAttachmentBase(value string) string {
@@ -5464,23 +5195,18 @@ func (a *AppService) buildWorkflowDropdownAlertAttachments(
 		}
 		seenPhotoIDs[photoID] = struct{}{}
 
-		photo, err := a.db.GetWorkflowSubmissionPhotoBlobByID(ctx, photoID)
-		if err != nil {
-			a.logger.Logf("error loading dropdown-alert photo %s for workflow %s: %s", photoID, notification.WorkflowId, err)
-			continue
+		photo, err := a.db.GetWorkflowSubmissionPhotoByID(ctx, photoID)
+		if err == nil {
+			photo, _, err = a.resolveWorkflowPhotoVariant(ctx, photo, photoproc.VariantMedium, true)
 		}
-
-		decodedImage, _, err := image.Decode(bytes.NewReader(photo.PhotoData))
 		if err != nil {
-			a.logger.Logf("error decoding dropdown-alert photo %s for workflow %s: %s", photoID, notification.WorkflowId, err)
+			a.logger.Errorf(ctx, "error loading dropdown-alert photo %s for workflow %s: %s", photoID, notification.WorkflowId, err)
 			continue
 		}
 
-		scaledImage := resizeWorkflowEmailAttachmentImage(decodedImage, workflowDropdownEmailAttachmentMaxDimension)
-
-		var jpegBuffer bytes.Buffer
-		if err := jpeg.Encode(&jpegBuffer, scaledImage, &jpeg.Options{Quality: workflowDropdownEmailAttachmentJPEGQuality}); err != nil {
-			a.logger.Logf("error encoding dropdown-alert photo %s for workflow %s: %s", photoID, notification.WorkflowId, err)
+		attachmentData, err := workflowEmailAttachmentJPEG(photo)
+		if err != nil {
+			a.logger.Errorf(ctx, "error preparing dropdown-alert photo %s for workflow %s: %s", photoID, notification.WorkflowId, err)
 			continue
 		}
 
@@ -5491,7 +5217,7 @@ func (a *AppService) buildWorkflowDropdownAlertAttachments(
 		filename := fmt.Sprintf("%s_%02d.jpg", baseName, attachmentIndex+1)
 		attachments = append(attachments, utils.EmailAttachment{
 			Filename: filename,
-			Data:     jpegBuffer.Bytes(),
+			Data:     attachmentData,
 		})
 	}
 
@@ -5507,16 +5233,24 @@ func workflowPhotoPublicPageURL(photoID string) string {
 	return baseURL + "/photos/" + url.PathEscape(photoID)
 }
 
-func buildWorkflowDropdownAlertPhotoLinksHTML(notification structs.WorkflowDropdownNotification) string {
+// workflowDropdownAlertPhotoLink is one photo link in a dropdown alert
+// email. Index numbers photos of the same item and is 0 when it has only one.
+type workflowDropdownAlertPhotoLink struct {
+	URL       string
+	ItemTitle string
+	Index     int
+}
+
+func buildWorkflowDropdownAlertPhotoLinks(notification structs.WorkflowDropdownNotification) []workflowDropdownAlertPhotoLink {
 	if !notification.SendPicturesWithEmail || (len(notification.PhotoLinks) == 0 && len(notification.PhotoIDs) == 0) {
-		return ""
+		return []workflowDropdownAlertPhotoLink{}
 	}
 
 	linkCapacity := len(notification.PhotoLinks)
 	if len(notification.PhotoIDs) > linkCapacity {
 		linkCapacity = len(notification.PhotoIDs)
 	}
-	links := make([]string, 0, linkCapacity)
+	links := make([]workflowDropdownAlertPhotoLink, 0, linkCapacity)
 	seenPhotoIDs := map[string]struct{}{}
 
 	type photoLinkMeta struct {
@@ -5578,29 +5312,17 @@ func buildWorkflowDropdownAlertPhotoLinksHTML(notification structs.WorkflowDropd
 			groupKey = photoID
 		}
 		photoIndexByItem[groupKey]++
-		linkLabel := fmt.Sprintf("View %s", strings.TrimSpace(meta.ItemTitle))
-		if strings.TrimSpace(meta.ItemTitle) == "" {
-			linkLabel = "View Photo"
+		link := workflowDropdownAlertPhotoLink{
+			URL:       workflowPhotoPublicPageURL(photoID),
+			ItemTitle: meta.ItemTitle,
 		}
 		if totalPhotosByItem[groupKey] > 1 {
-			linkLabel = fmt.Sprintf("%s %d", linkLabel, photoIndexByItem[groupKey])
+			link.Index = photoIndexByItem[groupKey]
 		}
-
-		linkURL := workflowPhotoPublicPageURL(photoID)
-		links = append(
-			links,
-			fmt.Sprintf(
-				`<a href="%s" style="display:inline-block; margin:0 10px 10px 0; padding:8px 10px; border:1px solid #f0b1b1; border-radius:6px; color:#d55c5c; text-decoration:none; font-weight:600;">%s</a>`,
-				utils.EscapeEmailHTML(linkURL),
-				utils.EscapeEmailHTML(linkLabel),
-			),
-		)
-	}
-	if len(links) == 0 {
-		return ""
+		links = append(links, link)
 	}
 
-	return strings.Join(links, "")
+	return links
 }
 
 func (a *AppService) sendWorkflowDropdownAlertEmail(ctx context.Context, notification structs.WorkflowDropdownNotification) {
@@ -5608,58 +5330,26 @@ func (a *AppService) sendWorkflowDropdownAlertEmail(ctx context.Context, notific
 		return
 	}
 
-	emailSender := utils.NewEmailSender()
-	if emailSender == nil {
-		return
-	}
-
-	photoLinksHTML := buildWorkflowDropdownAlertPhotoLinksHTML(notification)
-	photoLinksRow := ""
-	if photoLinksHTML != "" {
-		photoLinksRow = fmt.Sprintf(`
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Photos</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>`, photoLinksHTML)
-	}
-
-	title := strings.Join(strings.Fields(notification.EmailSubject), " ")
-	if title == "" {
-		title = "Workflow Alert"
-	}
-	htmlContent := utils.BuildStyledEmail(
-		title,
-		"A watched dropdown response was submitted.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:140px;">Workflow</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Step</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Item</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Response</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-%s
-</table>`, utils.EscapeEmailHTML(notification.WorkflowTitle), utils.EscapeEmailHTML(notification.StepTitle), utils.EscapeEmailHTML(notification.ItemTitle), utils.EscapeEmailHTML(notification.DropdownValue), photoLinksRow),
-	)
+	data := emails.Data{
+		"Subject":       strings.Join(strings.Fields(notification.EmailSubject), " "),
+		"WorkflowTitle": notification.WorkflowTitle,
+		"StepTitle":     notification.StepTitle,
+		"ItemTitle":     notification.ItemTitle,
+		"Response":      notification.DropdownValue,
+		"PhotoLinks":    buildWorkflowDropdownAlertPhotoLinks(notification),
+	}
 
 	for _, toEmail := range notification.Emails {
 		toEmail = strings.TrimSpace(toEmail)
 		if toEmail == "" {
 			continue
 		}
-		err := emailSender.SendEmail(toEmail, "Workflow Watcher", title, htmlContent, utils.NotificationFromEmail(), "SFLuv Workflows")
+		err := a.Notify(ctx, &Notification{
+			Category: structs.NotificationCategoryWorkflowAlerts,
+			Email:    &NotificationEmail{To: toEmail, ToName: "Workflow Watcher", Template: "workflow_dropdown_alert", Data: data, FromName: "SFLuv Workflows"},
+		})
 		if err != nil {
-			a.logger.Logf("error sending dropdown-alert email for workflow %s step %s item %s to %s: %s", notification.WorkflowId, notification.StepId, notification.ItemId, toEmail, err.Error())
+			a.logger.Errorf(ctx, "error sending dropdown-alert email for workflow %s step %s item %s to %s: %s", notification.WorkflowId, notification.StepId, notification.ItemId, toEmail, err.Error())
 		}
 	}
 }
@@ -5723,7 +5413,7 @@ func parseAbsenceBoundary(value string, isEnd bool) (time.Time, error) {
 func (a *AppService) IsProposer(ctx context.Context, id string) bool {
 	isProposer, err := a.db.IsProposer(ctx, id)
 	if err != nil {
-		a.logger.Logf("error getting proposer state for user %s: %s", id, err)
+		a.logger.Errorf(ctx, "error getting proposer state for user %s: %s", id, err)
 		return false
 	}
 	return isProposer
@@ -5732,7 +5422,7 @@ func (a *AppService) IsProposer(ctx context.Context, id string) bool {
 func (a *AppService) IsImprover(ctx context.Context, id string) bool {
 	isImprover, err := a.db.IsImprover(ctx, id)
 	if err != nil {
-		a.logger.Logf("error getting improver state for user %s: %s", id, err)
+		a.logger.Errorf(ctx, "error getting improver state for user %s: %s", id, err)
 		return false
 	}
 	return isImprover
@@ -5741,7 +5431,7 @@ func (a *AppService) IsImprover(ctx context.Context, id string) bool {
 func (a *AppService) IsVoter(ctx context.Context, id string) bool {
 	isVoter, err := a.db.IsVoter(ctx, id)
 	if err != nil {
-		a.logger.Logf("error getting voter state for user %s: %s", id, err)
+		a.logger.Errorf(ctx, "error getting voter state for user %s: %s", id, err)
 		return false
 	}
 	return isVoter
@@ -5750,7 +5440,7 @@ func (a *AppService) IsVoter(ctx context.Context, id string) bool {
 func (a *AppService) IsIssuer(ctx context.Context, id string) bool {
 	isIssuer, err := a.db.IsIssuer(ctx, id)
 	if err != nil {
-		a.logger.Logf("error getting issuer state for user %s: %s", id, err)
+		a.logger.Errorf(ctx, "error getting issuer state for user %s: %s", id, err)
 		return false
 	}
 	return isIssuer
@@ -5759,7 +5449,7 @@ func (a *AppService) IsIssuer(ctx context.Context, id string) bool {
 func (a *AppService) IsSupervisor(ctx context.Context, id string) bool {
 	isSupervisor, err := a.db.IsSupervisor(ctx, id)
 	if err != nil {
-		a.logger.Logf("error getting supervisor state for user %s: %s", id, err)
+		a.logger.Errorf(ctx, "error getting supervisor state for user %s: %s", id, err)
 		return false
 	}
 	return isSupervisor
@@ -5775,7 +5465,7 @@ func (a *AppService) RequestIssuerStatus(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading issuer request body for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error reading issuer request body for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -5798,31 +5488,16 @@ func (a *AppService) RequestIssuerStatus(w http.ResponseWriter, r *http.Request)
 			w.Write([]byte(err.Error()))
 			return
 		}
-		a.logger.Logf("error upserting issuer request for user %s: %s", *userDid, err.Error())
+		a.logger.Errorf(r.Context(), "error upserting issuer request for user %s: %s", *userDid, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	a.sendRoleRequestEmail(
-		"ISSUER_ADMIN_EMAIL",
-		"New Issuer Request",
-		"A user has requested issuer status.",
-		fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:140px;">User</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Organization</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Notification Email</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(issuer.UserId), utils.EscapeEmailHTML(issuer.Organization), utils.EscapeEmailHTML(issuer.Email)),
-	)
+	a.sendRoleRequestEmail("ISSUER_ADMIN_EMAIL", "issuer_request", emails.Data{
+		"UserId":       issuer.UserId,
+		"Organization": issuer.Organization,
+		"Email":        issuer.Email,
+	})
 
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(issuer)
@@ -5833,7 +5508,7 @@ func (a *AppService) GetIssuerRequests(w http.ResponseWriter, r *http.Request) {
 	page, count := parsePageAndCount(r.URL.Query(), 20, 100)
 	issuers, err := a.db.GetIssuerRequests(r.Context(), search, page, count)
 	if err != nil {
-		a.logger.Logf("error getting issuer requests: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error getting issuer requests: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -5846,7 +5521,7 @@ func (a *AppService) UpdateIssuerRequest(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update issuer request body: %s", err.Error())
+		a.logger.Errorf(r.Context(), "error reading update issuer request body: %s", err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -5857,6 +5532,7 @@ func (a *AppService) UpdateIssuerRequest(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	before, _ := a.db.GetIssuerByUser(r.Context(), req.UserId)
 	issuer, err := a.db.UpdateIssuerRequest(r.Context(), &req)
 	if err != nil {
 		errMsg := err.Error()
@@ -5865,10 +5541,11 @@ func (a *AppService) UpdateIssuerRequest(w http.ResponseWriter, r *http.Request)
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error updating issuer request %s: %s", req.UserId, err.Error())
+		a.logger.Errorf(r.Context(), "error updating issuer request %s: %s", req.UserId, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditIssuerRequestUpdate, "user", req.UserId, before, issuer)
 
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(issuer)
@@ -5877,7 +5554,7 @@ func (a *AppService) UpdateIssuerRequest(w http.ResponseWriter, r *http.Request)
 func (a *AppService) GetAdminCredentialTypes(w http.ResponseWriter, r *http.Request) {
 	types, err := a.db.GetGlobalCredentialTypes(r.Context())
 	if err != nil {
-		a.logger.Logf("error getting credential types: %s", err)
+		a.logger.Errorf(r.Context(), "error getting credential types: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -5890,7 +5567,7 @@ func (a *AppService) CreateAdminCredentialType(w http.ResponseWriter, r *http.Re
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading create credential type body: %s", err)
+		a.logger.Errorf(r.Context(), "error reading create credential type body: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -5909,7 +5586,7 @@ func (a *AppService) CreateAdminCredentialType(w http.ResponseWriter, r *http.Re
 			w.Write([]byte(errMsg))
 			return
 		}
-		a.logger.Logf("error creating credential type %s: %s", req.Value, err)
+		a.logger.Errorf(r.Context(), "error creating credential type %s: %s", req.Value, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -5935,7 +5612,7 @@ func (a *AppService) DeleteAdminCredentialType(w http.ResponseWriter, r *http.Re
 			w.Write([]byte(err.Error()))
 			return
 		}
-		a.logger.Logf("error deleting credential type %s: %s", value, err)
+		a.logger.Errorf(r.Context(), "error deleting credential type %s: %s", value, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -5953,7 +5630,7 @@ func (a *AppService) UpdateAdminCredentialType(w http.ResponseWriter, r *http.Re
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.logger.Logf("error reading update credential type body for %s: %s", value, err)
+		a.logger.Errorf(r.Context(), "error reading update credential type body for %s: %s", value, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -5989,7 +5666,7 @@ func (a *AppService) UpdateAdminCredentialType(w http.ResponseWriter, r *http.Re
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		a.logger.Logf("error updating credential type %s: %s", value, err)
+		a.logger.Errorf(r.Context(), "error updating credential type %s: %s", value, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -6012,7 +5689,7 @@ func (a *AppService) GetUserByAddress(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte("user not found"))
 			return
 		}
-		a.logger.Logf("error looking up user by address %s: %s", address, err)
+		a.logger.Errorf(r.Context(), "error looking up user by address %s: %s", address, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
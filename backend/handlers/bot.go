@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math/big"
 	"net/http"
 	"net/url"
@@ -17,6 +18,8 @@ import (
 
 	"github.com/SFLuv/app/backend/bot"
 	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/emails"
+	"github.com/SFLuv/app/backend/metrics"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
 	"github.com/ethereum/go-ethereum/common"
@@ -26,20 +29,27 @@ import (
 type BotService struct {
 	db                 *db.BotDB
 	appDb              *db.AppDB
+	ponderDb           *db.PonderDB
 	bot                bot.IBot
+	payouts            *bot.Router
 	w9                 *W9Service
 	affiliateScheduler *AffiliateScheduler
 	activeChainID      int64
 	readRPCURL         string
+	notify             Notifier
 }
 
 var redeemCodeUUIDPattern = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}`)
 
-func NewBotService(db *db.BotDB, appDb *db.AppDB, bot bot.IBot, w9 *W9Service, affiliateScheduler *AffiliateScheduler, activeChainID int64, readRPCURL string) *BotService {
+// NewBotService wires the faucet to the bot that pays redemptions; recovery
+// and workflow payouts pick their own chain from payouts.
+func NewBotService(db *db.BotDB, appDb *db.AppDB, ponderDb *db.PonderDB, payouts *bot.Router, w9 *W9Service, affiliateScheduler *AffiliateScheduler, activeChainID int64, readRPCURL string) *BotService {
 	return &BotService{
 		db:                 db,
 		appDb:              appDb,
-		bot:                bot,
+		ponderDb:           ponderDb,
+		bot:                payouts.ForTarget(bot.PayoutTargetRedemption),
+		payouts:            payouts,
 		w9:                 w9,
 		affiliateScheduler: affiliateScheduler,
 		activeChainID:      activeChainID,
@@ -47,6 +57,22 @@ func NewBotService(db *db.BotDB, appDb *db.AppDB, bot bot.IBot, w9 *W9Service, a
 	}
 }
 
+// SetNotifier routes faucet alerts through the app's notification
+// dispatcher; without one they are emailed directly.
+func (s *BotService) SetNotifier(notify Notifier) {
+	s.notify = notify
+}
+
+func (s *BotService) sendNotification(ctx context.Context, n *Notification) error {
+	if s.notify != nil {
+		return s.notify(ctx, n)
+	}
+	if n.Email == nil {
+		return nil
+	}
+	return deliverNotificationEmail(n.Email)
+}
+
 func (s *BotService) chainID() int64 {
 	if s != nil && s.activeChainID > 0 {
 		return s.activeChainID
@@ -54,6 +80,40 @@ func (s *BotService) chainID() int64 {
 	return 80094
 }
 
+// payoutBot returns the bot that pays target.
+func (s *BotService) payoutBot(target string) bot.IBot {
+	if s == nil {
+		return nil
+	}
+	if s.payouts != nil {
+		return s.payouts.ForTarget(target)
+	}
+	return s.bot
+}
+
+// botForChain returns the bot running on chainID, for verifying a payout on
+// the chain it was sent on. A non-positive chainID means the default chain.
+func (s *BotService) botForChain(chainID int64) (bot.IBot, bool) {
+	if s == nil {
+		return nil, false
+	}
+	if s.payouts != nil {
+		return s.payouts.ForChain(chainID)
+	}
+	if s.bot == nil || (chainID > 0 && chainID != s.bot.ChainID()) {
+		return nil, false
+	}
+	return s.bot, true
+}
+
+// payoutChainID is the chain a transfer sent by b is recorded under.
+func (s *BotService) payoutChainID(b bot.IBot) int64 {
+	if b != nil && b.ChainID() > 0 {
+		return b.ChainID()
+	}
+	return s.chainID()
+}
+
 func EnsureLogin(w http.ResponseWriter, r *http.Request) bool {
 	adminKey := os.Getenv("ADMIN_KEY")
 	header := r.Header[http.CanonicalHeaderKey("X-API-KEY")]
@@ -73,7 +133,7 @@ func EnsureBody(w http.ResponseWriter, r *http.Request) []byte {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		fmt.Println(err)
+		slog.WarnContext(r.Context(), "error reading request body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return nil
 	}
@@ -83,7 +143,7 @@ func EnsureBody(w http.ResponseWriter, r *http.Request) []byte {
 func EnsureUnmarshal(w http.ResponseWriter, obj any, body []byte) bool {
 	err := json.Unmarshal(body, obj)
 	if err != nil {
-		fmt.Println(err)
+		slog.Debug("error decoding request body", "error", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return false
 	}
@@ -109,51 +169,54 @@ func normalizeRedeemCode(raw string) string {
 	return strings.ToLower(code)
 }
 
-func (s *BotService) resolveRedeemPayoutAddress(ctx context.Context, requestedAddress string) string {
+// resolveRedeemPayoutAddress maps a redeem address to its owner's primary
+// wallet. It also returns the owning user's id when the address is known.
+func (s *BotService) resolveRedeemPayoutAddress(ctx context.Context, requestedAddress string) (string, string) {
 	normalizedRequestedAddress := strings.ToLower(strings.TrimSpace(requestedAddress))
 	if !common.IsHexAddress(normalizedRequestedAddress) {
-		return normalizedRequestedAddress
+		return normalizedRequestedAddress, ""
 	}
 	normalizedRequestedAddress = strings.ToLower(common.HexToAddress(normalizedRequestedAddress).Hex())
 
 	if s.appDb == nil {
-		return normalizedRequestedAddress
+		return normalizedRequestedAddress, ""
 	}
 
 	ownerLookup, err := s.appDb.GetWalletAddressOwnerLookup(ctx, normalizedRequestedAddress)
 	if err != nil {
-		fmt.Printf("error resolving wallet owner for redeem address %s: %s\n", normalizedRequestedAddress, err)
-		return normalizedRequestedAddress
+		slog.ErrorContext(ctx, "error resolving wallet owner for redeem address", "address", normalizedRequestedAddress, "error", err)
+		return normalizedRequestedAddress, ""
 	}
 	if ownerLookup == nil || strings.TrimSpace(ownerLookup.UserID) == "" {
-		return normalizedRequestedAddress
+		return normalizedRequestedAddress, ""
 	}
 
+	ownerId := strings.TrimSpace(ownerLookup.UserID)
 	user, err := s.appDb.GetUserById(ctx, ownerLookup.UserID)
 	if err == nil {
 		primaryWalletAddress := strings.TrimSpace(user.PrimaryWalletAddress)
 		if common.IsHexAddress(primaryWalletAddress) {
-			return strings.ToLower(common.HexToAddress(primaryWalletAddress).Hex())
+			return strings.ToLower(common.HexToAddress(primaryWalletAddress).Hex()), ownerId
 		}
 	} else {
-		fmt.Printf("error loading user primary wallet for owner %s redeem address %s: %s\n", ownerLookup.UserID, normalizedRequestedAddress, err)
+		slog.ErrorContext(ctx, "error loading user primary wallet for redeem address", "user_id", ownerLookup.UserID, "address", normalizedRequestedAddress, "error", err)
 	}
 
 	primarySmartWallet, err := s.appDb.GetSmartWalletByOwnerIndex(ctx, ownerLookup.UserID, 0)
 	if err != nil {
-		fmt.Printf("error loading primary smart wallet for owner %s redeem address %s: %s\n", ownerLookup.UserID, normalizedRequestedAddress, err)
-		return normalizedRequestedAddress
+		slog.ErrorContext(ctx, "error loading primary smart wallet for redeem address", "user_id", ownerLookup.UserID, "address", normalizedRequestedAddress, "error", err)
+		return normalizedRequestedAddress, ownerId
 	}
 	if primarySmartWallet == nil || primarySmartWallet.SmartAddress == nil {
-		return normalizedRequestedAddress
+		return normalizedRequestedAddress, ownerId
 	}
 
 	smartWalletAddress := strings.TrimSpace(*primarySmartWallet.SmartAddress)
 	if !common.IsHexAddress(smartWalletAddress) {
-		return normalizedRequestedAddress
+		return normalizedRequestedAddress, ownerId
 	}
 
-	return strings.ToLower(common.HexToAddress(smartWalletAddress).Hex())
+	return strings.ToLower(common.HexToAddress(smartWalletAddress).Hex()), ownerId
 }
 
 func validateEventTiming(event *structs.Event) error {
@@ -225,23 +288,23 @@ func (s *BotService) NewEvent(w http.ResponseWriter, r *http.Request) {
 	eventTotal := big.NewInt(int64(event.Amount) * int64(event.Codes))
 	decimals, err := strconv.Atoi(os.Getenv("TOKEN_DECIMALS"))
 	if err != nil {
-		fmt.Println("invalid token decimals in .env")
+		slog.ErrorContext(r.Context(), "invalid TOKEN_DECIMALS in .env", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	eventTotal.Mul(eventTotal, big.NewInt(int64(decimals)))
 
-	balance, err := s.bot.Balance()
+	balance, err := s.bot.Balance(r.Context())
 	if err != nil {
-		fmt.Printf("error getting current bot balance: %s\n", err)
+		slog.ErrorContext(r.Context(), "error getting current bot balance", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	allocatedBalance, err := s.totalAllocatedBalance(r.Context())
 	if err != nil {
-		fmt.Printf("error getting allocated balance for faucet: %s", err)
+		slog.ErrorContext(r.Context(), "error getting allocated balance for faucet", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -252,7 +315,7 @@ func (s *BotService) NewEvent(w http.ResponseWriter, r *http.Request) {
 	unallocated := bigAllocated.Sub(balance, bigAllocated)
 
 	if eventTotal.Cmp(unallocated) > 0 {
-		fmt.Println("total event rewards should not exceed unallocated balance")
+		slog.WarnContext(r.Context(), "total event rewards should not exceed unallocated balance")
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte("insufficient balance"))
 		return
@@ -260,7 +323,7 @@ func (s *BotService) NewEvent(w http.ResponseWriter, r *http.Request) {
 
 	id, err := s.db.NewEvent(r.Context(), event)
 	if err != nil {
-		fmt.Println(err)
+		slog.ErrorContext(r.Context(), "error creating event", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -271,21 +334,21 @@ func (s *BotService) NewEvent(w http.ResponseWriter, r *http.Request) {
 func (s *BotService) RemainingBalance(w http.ResponseWriter, r *http.Request) {
 	decimals, err := strconv.Atoi(os.Getenv("TOKEN_DECIMALS"))
 	if err != nil {
-		fmt.Println("invalid token decimals in .env")
+		slog.ErrorContext(r.Context(), "invalid TOKEN_DECIMALS in .env", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	balance, err := s.bot.Balance()
+	balance, err := s.bot.Balance(r.Context())
 	if err != nil {
-		fmt.Printf("error getting current bot balance: %s\n", err)
+		slog.ErrorContext(r.Context(), "error getting current bot balance", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	allocatedBalance, err := s.totalAllocatedBalance(r.Context())
 	if err != nil {
-		fmt.Printf("error getting allocated balance for faucet: %s", err)
+		slog.ErrorContext(r.Context(), "error getting allocated balance for faucet", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -318,7 +381,7 @@ func (s *BotService) NewCodesRequest(w http.ResponseWriter, r *http.Request) {
 
 	codes, err := s.db.NewCodes(r.Context(), new_codes)
 	if err != nil {
-		fmt.Println(err)
+		slog.ErrorContext(r.Context(), "error creating codes", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -342,14 +405,14 @@ func (s *BotService) GetEvents(w http.ResponseWriter, r *http.Request) {
 		Expired: expired,
 	})
 	if err != nil {
-		fmt.Printf("error getting events: page %d, count %d, search %s, expired %t\n: %s", page, count, search, expired, err)
+		slog.ErrorContext(r.Context(), "error getting events", "page", page, "count", count, "search", search, "expired", expired, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	bytes, err := json.Marshal(events)
 	if err != nil {
-		fmt.Printf("error marshalling events bytes: %s\n", err)
+		slog.ErrorContext(r.Context(), "error marshalling events", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -376,7 +439,7 @@ func (s *BotService) GetCodesRequest(w http.ResponseWriter, r *http.Request) {
 
 	codes, err := s.GetCodes(event, count, page)
 	if err != nil {
-		fmt.Println(err)
+		slog.ErrorContext(r.Context(), "error getting codes", "event_id", event, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -387,7 +450,7 @@ func (s *BotService) GetCodesRequest(w http.ResponseWriter, r *http.Request) {
 
 	bytes, err := json.Marshal(codes)
 	if err != nil {
-		fmt.Println(err)
+		slog.ErrorContext(r.Context(), "error marshalling codes", "event_id", event, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -415,22 +478,22 @@ func (s *BotService) DeleteEvent(w http.ResponseWriter, r *http.Request) {
 		if err == pgx.ErrNoRows {
 			// Not an affiliate, nothing to refund.
 		} else if err != nil {
-			fmt.Printf("error checking affiliate owner for event %s: %s\n", event, err)
+			slog.ErrorContext(r.Context(), "error checking affiliate owner for event", "event_id", event, "error", err)
 		} else {
 			freed, err := s.db.EventUnredeemedValue(r.Context(), event)
 			if err == nil && freed > 0 {
 				if err := s.appDb.AddAffiliateWeeklyBalance(r.Context(), owner, freed); err != nil {
-					fmt.Printf("error refunding affiliate balance for event %s: %s\n", event, err)
+					slog.ErrorContext(r.Context(), "error refunding affiliate balance for event", "event_id", event, "error", err)
 				}
 			} else {
-				fmt.Printf("error getting event unredeemed value for event %s refund: %s\n", event, err)
+				slog.ErrorContext(r.Context(), "error getting event unredeemed value for refund", "event_id", event, "error", err)
 			}
 		}
 	}
 
 	err := s.db.DeleteEvent(r.Context(), event)
 	if err != nil {
-		fmt.Printf("error deleting event %s: %s\n", event, err)
+		slog.ErrorContext(r.Context(), "error deleting event", "event_id", event, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -474,48 +537,71 @@ func (s *BotService) AffiliateNewEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eventTotal := uint64(event.Amount) * uint64(event.Codes)
-	reservation, err := s.appDb.ReserveAffiliateBalance(r.Context(), *userDid, eventTotal)
+	id, err := s.CreateAffiliateEvent(r.Context(), event)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
 			w.WriteHeader(http.StatusForbidden)
-			return
-		}
-		if err.Error() == "insufficient affiliate balance" {
+		case errors.Is(err, errInsufficientAffiliateBalance):
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte("insufficient affiliate balance"))
-			return
+		case errors.Is(err, errInsufficientFaucetBalance):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Not enough balance in faucet. Please try again later, or contact us at admin@sfluv.org."))
+		default:
+			slog.ErrorContext(r.Context(), "error creating affiliate event", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
 		}
-		fmt.Printf("error reserving affiliate balance: %s\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(id))
+}
+
+var (
+	errInsufficientAffiliateBalance = errors.New("insufficient affiliate balance")
+	errInsufficientFaucetBalance    = errors.New("insufficient faucet balance")
+)
+
+// CreateAffiliateEvent reserves the event total against the owner's affiliate
+// balance, checks the faucet can cover it and creates the event with its
+// codes. The reservation is refunded if any later step fails.
+func (s *BotService) CreateAffiliateEvent(ctx context.Context, event *structs.Event) (string, error) {
+	eventTotal := uint64(event.Amount) * uint64(event.Codes)
+	reservation, err := s.appDb.ReserveAffiliateBalance(ctx, event.Owner, eventTotal)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", err
+		}
+		if err.Error() == "insufficient affiliate balance" {
+			return "", errInsufficientAffiliateBalance
+		}
+		return "", fmt.Errorf("error reserving affiliate balance: %s", err)
+	}
+	refund := func() {
+		_ = s.appDb.RefundAffiliateBalance(ctx, event.Owner, reservation.WeeklyDeducted, reservation.OneTimeDeducted)
+	}
+
 	decimals, err := strconv.Atoi(os.Getenv("TOKEN_DECIMALS"))
 	if err != nil {
-		fmt.Println("invalid token decimals in .env")
-		_ = s.appDb.RefundAffiliateBalance(r.Context(), *userDid, reservation.WeeklyDeducted, reservation.OneTimeDeducted)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		refund()
+		return "", fmt.Errorf("invalid token decimals in .env")
 	}
 
 	eventTotalBig := new(big.Int).SetUint64(eventTotal)
 	eventTotalBig.Mul(eventTotalBig, big.NewInt(int64(decimals)))
 
-	faucetBalance, err := s.bot.Balance()
+	faucetBalance, err := s.bot.Balance(ctx)
 	if err != nil {
-		fmt.Printf("error getting current bot balance: %s\n", err)
-		_ = s.appDb.RefundAffiliateBalance(r.Context(), *userDid, reservation.WeeklyDeducted, reservation.OneTimeDeducted)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		refund()
+		return "", fmt.Errorf("error getting current bot balance: %s", err)
 	}
 
-	allocatedBalance, err := s.totalAllocatedBalance(r.Context())
+	allocatedBalance, err := s.totalAllocatedBalance(ctx)
 	if err != nil {
-		fmt.Printf("error getting allocated balance for faucet: %s", err)
-		_ = s.appDb.RefundAffiliateBalance(r.Context(), *userDid, reservation.WeeklyDeducted, reservation.OneTimeDeducted)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		refund()
+		return "", fmt.Errorf("error getting allocated balance for faucet: %s", err)
 	}
 
 	bigAllocated := big.NewInt(int64(allocatedBalance))
@@ -524,59 +610,43 @@ func (s *BotService) AffiliateNewEvent(w http.ResponseWriter, r *http.Request) {
 	unallocated := bigAllocated.Sub(faucetBalance, bigAllocated)
 
 	if eventTotalBig.Cmp(unallocated) > 0 {
-		fmt.Println("total event rewards should not exceed unallocated balance")
+		slog.WarnContext(ctx, "total event rewards should not exceed unallocated balance", "affiliate", event.Owner)
 		adminEmail := os.Getenv("AFFILIATE_ADMIN_EMAIL")
-		emailSender := utils.NewEmailSender()
-		if adminEmail != "" && emailSender != nil {
+		if adminEmail != "" {
 			availableTokens := new(big.Int).Div(unallocated, big.NewInt(int64(decimals)))
-			subject := "Failed Affiliate Event Creation (Faucet Balance)"
-			details := fmt.Sprintf(`
-<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" style="border-collapse:collapse;">
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280; width:180px;">Affiliate</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827; word-break:break-all;">%s</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#6b7280;">Required Balance</td>
-    <td style="padding:12px 0; border-bottom:1px solid #e5e7eb; font-size:13px; color:#111827;">%d SFLuv</td>
-  </tr>
-  <tr>
-    <td style="padding:12px 0; font-size:13px; color:#6b7280;">Available Faucet Balance</td>
-    <td style="padding:12px 0; font-size:13px; color:#111827;">%s SFLuv</td>
-  </tr>
-</table>`, utils.EscapeEmailHTML(*userDid), eventTotal, utils.EscapeEmailHTML(availableTokens.String()))
-
-			htmlContent := utils.BuildStyledEmail(
-				"Failed Affiliate Event Creation",
-				"Affiliate event creation failed due to faucet balance.",
-				details,
-			)
-
-			err = emailSender.SendEmail(adminEmail, "Admin", subject, htmlContent, utils.NotificationFromEmail(), "SFLuv Affiliates")
+			err = s.sendNotification(ctx, &Notification{
+				Category: structs.NotificationCategoryAdmin,
+				Email: &NotificationEmail{
+					To:       adminEmail,
+					ToName:   "Admin",
+					Template: "affiliate_faucet_shortfall",
+					Data: emails.Data{
+						"Affiliate":        event.Owner,
+						"RequiredBalance":  eventTotal,
+						"AvailableBalance": availableTokens.String(),
+					},
+					FromName: "SFLuv Affiliates",
+				},
+			})
 			if err != nil {
-				fmt.Printf("error sending affiliate faucet balance email: %s\n", err)
+				slog.ErrorContext(ctx, "error sending affiliate faucet balance email", "error", err)
 			}
 		}
-		_ = s.appDb.RefundAffiliateBalance(r.Context(), *userDid, reservation.WeeklyDeducted, reservation.OneTimeDeducted)
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte("Not enough balance in faucet. Please try again later, or contact us at admin@sfluv.org."))
-		return
+		refund()
+		return "", errInsufficientFaucetBalance
 	}
 
-	id, err := s.db.NewEvent(r.Context(), event)
+	id, err := s.db.NewEvent(ctx, event)
 	if err != nil {
-		fmt.Println(err)
-		_ = s.appDb.RefundAffiliateBalance(r.Context(), *userDid, reservation.WeeklyDeducted, reservation.OneTimeDeducted)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		refund()
+		return "", err
 	}
 
 	if s.affiliateScheduler != nil {
-		s.affiliateScheduler.ScheduleEventExpiration(id, *userDid, event.Expiration)
+		s.affiliateScheduler.ScheduleEventExpiration(id, event.Owner, event.Expiration)
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte(id))
+	return id, nil
 }
 
 func (s *BotService) AffiliateGetEvents(w http.ResponseWriter, r *http.Request) {
@@ -598,14 +668,14 @@ func (s *BotService) AffiliateGetEvents(w http.ResponseWriter, r *http.Request)
 		Expired: expired,
 	}, *userDid)
 	if err != nil {
-		fmt.Printf("error getting affiliate events: page %d, count %d, search %s, expired %t\n: %s", page, count, search, expired, err)
+		slog.ErrorContext(r.Context(), "error getting affiliate events", "page", page, "count", count, "search", search, "expired", expired, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	bytes, err := json.Marshal(events)
 	if err != nil {
-		fmt.Printf("error marshalling events bytes: %s\n", err)
+		slog.ErrorContext(r.Context(), "error marshalling events", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -642,7 +712,7 @@ func (s *BotService) AffiliateGetCodes(w http.ResponseWriter, r *http.Request) {
 
 	codes, err := s.GetCodes(event, count, page)
 	if err != nil {
-		fmt.Println(err)
+		slog.ErrorContext(r.Context(), "error getting codes", "event_id", event, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -653,7 +723,7 @@ func (s *BotService) AffiliateGetCodes(w http.ResponseWriter, r *http.Request) {
 
 	bytes, err := json.Marshal(codes)
 	if err != nil {
-		fmt.Println(err)
+		slog.ErrorContext(r.Context(), "error marshalling codes", "event_id", event, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -687,17 +757,17 @@ func (s *BotService) AffiliateDeleteEvent(w http.ResponseWriter, r *http.Request
 
 	freed, err := s.db.EventUnredeemedValue(r.Context(), event)
 	if err == nil && freed > 0 {
-		fmt.Printf("freed affiliate balance %d for event %s\n", freed, event)
+		slog.InfoContext(r.Context(), "freed affiliate balance", "event_id", event, "amount", freed)
 		if s.appDb != nil {
 			if err := s.appDb.AddAffiliateWeeklyBalance(r.Context(), owner, freed); err != nil {
-				fmt.Printf("error refunding affiliate balance for event %s: %s\n", event, err)
+				slog.ErrorContext(r.Context(), "error refunding affiliate balance for event", "event_id", event, "error", err)
 			}
 		}
 	}
 
 	err = s.db.DeleteEvent(r.Context(), event)
 	if err != nil {
-		fmt.Printf("error deleting event %s: %s\n", event, err)
+		slog.ErrorContext(r.Context(), "error deleting event", "event_id", event, "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -718,14 +788,14 @@ func (s *BotService) AffiliateBalance(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		fmt.Printf("error getting affiliate balance: %s\n", err)
+		slog.ErrorContext(r.Context(), "error getting affiliate balance", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	bytes, err := json.Marshal(balance)
 	if err != nil {
-		fmt.Printf("error marshalling affiliate balance: %s\n", err)
+		slog.ErrorContext(r.Context(), "error marshalling affiliate balance", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -801,11 +871,18 @@ func (s *BotService) Redeem(w http.ResponseWriter, r *http.Request) {
 	}
 	request.Address = strings.ToLower(common.HexToAddress(request.Address).Hex())
 
-	resolveAddressCtx, resolveAddressCancel := context.WithTimeout(context.Background(), 5*time.Second)
-	request.Address = s.resolveRedeemPayoutAddress(resolveAddressCtx, request.Address)
+	// The redemption outlives the request if the client hangs up, but keeps its
+	// request ID so the payout can be traced through the bot and the database.
+	ctx := context.WithoutCancel(r.Context())
+	resolveAddressCtx, resolveAddressCancel := context.WithTimeout(ctx, 5*time.Second)
+	claimant := structs.RedeemClaimant{}
+	request.Address, claimant.UserId = s.resolveRedeemPayoutAddress(resolveAddressCtx, request.Address)
 	resolveAddressCancel()
+	if userDid := utils.GetDid(r); userDid != nil {
+		claimant.UserId = *userDid
+	}
 
-	complianceCtx, complianceCancel := context.WithTimeout(context.Background(), 20*time.Second)
+	complianceCtx, complianceCancel := context.WithTimeout(ctx, 20*time.Second)
 	defer complianceCancel()
 
 	amount := uint64(0)
@@ -817,7 +894,7 @@ func (s *BotService) Redeem(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		redeemInfoCtx, redeemInfoCancel := context.WithTimeout(context.Background(), 8*time.Second)
+		redeemInfoCtx, redeemInfoCancel := context.WithTimeout(ctx, 8*time.Second)
 		var amountErr error
 		amount, amountErr = s.db.GetCodeAmount(redeemInfoCtx, request.Code)
 		redeemInfoCancel()
@@ -827,13 +904,13 @@ func (s *BotService) Redeem(w http.ResponseWriter, r *http.Request) {
 				w.Write([]byte("code redeemed"))
 				return
 			}
-			fmt.Printf("error loading redemption amount for code %s: %s\n", request.Code, amountErr)
+			slog.ErrorContext(ctx, "error loading redemption amount", "code", request.Code, "error", amountErr)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
 		amountWei := new(big.Int).Mul(decimals, big.NewInt(int64(amount)))
-		resp, err := s.w9.CheckCompliance(complianceCtx, os.Getenv("BOT_ADDRESS"), request.Address, amountWei)
+		resp, err := s.w9.CheckComplianceOnChain(complianceCtx, s.payoutChainID(s.bot), os.Getenv("BOT_ADDRESS"), request.Address, amountWei)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			return
@@ -846,11 +923,29 @@ func (s *BotService) Redeem(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	redeemCtx, redeemCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	redeemCtx, redeemCancel := context.WithTimeout(ctx, 10*time.Second)
 	defer redeemCancel()
 
-	amount, err := s.db.Redeem(redeemCtx, request.Code, request.Address, s.chainID())
+	amount, err := s.db.Redeem(redeemCtx, request.Code, request.Address, s.payoutChainID(s.bot), claimant)
 	if err != nil {
+		var limitErr *db.RedemptionLimitError
+		if errors.As(err, &limitErr) && limitErr.Limit.Reason == "address_limit" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("user redeemed"))
+			return
+		}
+		if errors.As(err, &limitErr) {
+			bytes, _ := json.Marshal(limitErr.Limit)
+			w.Header().Set("Content-Type", "application/json")
+			if limitErr.Limit.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.FormatInt(limitErr.Limit.RetryAfter, 10))
+				w.WriteHeader(http.StatusTooManyRequests)
+			} else {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			w.Write(bytes)
+			return
+		}
 		switch err.Error() {
 		case "code not started":
 			w.WriteHeader(http.StatusBadRequest)
@@ -865,43 +960,67 @@ func (s *BotService) Redeem(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusBadRequest)
 			w.Write([]byte("user redeemed"))
 		default:
-			fmt.Printf("error reserving redemption for code %s address %s: %s\n", request.Code, request.Address, err)
+			slog.ErrorContext(ctx, "error reserving redemption", "code", request.Code, "address", request.Address, "error", err)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 		return
 	}
 
-	if err := s.bot.Send(amount, request.Address); err != nil {
-		fmt.Printf("error sending redeem payout for code %s address %s: %s\n", request.Code, request.Address, err)
+	payoutChainID := s.payoutChainID(s.bot)
+	txHash, err := s.bot.Send(ctx, amount, request.Address)
+	ledgerEntry := &structs.RedemptionLedgerEntry{
+		Code:    request.Code,
+		Address: request.Address,
+		ChainId: payoutChainID,
+		Amount:  amount,
+		Action:  structs.RedemptionLedgerSent,
+		TxHash:  txHash,
+	}
+	if err != nil {
+		ledgerEntry.Action = structs.RedemptionLedgerFailed
+		ledgerEntry.Error = err.Error()
+	}
+	ledgerCtx, ledgerCancel := context.WithTimeout(ctx, 5*time.Second)
+	if ledgerErr := s.db.RecordRedemptionLedger(ledgerCtx, ledgerEntry); ledgerErr != nil {
+		slog.ErrorContext(ctx, "error recording redemption ledger", "code", request.Code, "tx_hash", txHash, "error", ledgerErr)
+	}
+	ledgerCancel()
+
+	if err != nil {
+		slog.ErrorContext(ctx, "error sending redeem payout", "code", request.Code, "address", request.Address, "amount", amount, "chain_id", payoutChainID, "tx_hash", txHash, "error", err)
 		if bot.ShouldRevertRedemption(err) {
-			undoCtx, undoCancel := context.WithTimeout(context.Background(), 10*time.Second)
-			if undoErr := s.db.UndoRedeem(undoCtx, request.Code, request.Address, s.chainID()); undoErr != nil {
-				fmt.Printf("error undoing redemption for code %s address %s after payout failure: %s\n", request.Code, request.Address, undoErr)
+			undoCtx, undoCancel := context.WithTimeout(ctx, 10*time.Second)
+			if undoErr := s.db.UndoRedeem(undoCtx, request.Code, request.Address, payoutChainID); undoErr != nil {
+				slog.ErrorContext(ctx, "error undoing redemption after payout failure", "code", request.Code, "address", request.Address, "error", undoErr)
 			}
 			undoCancel()
 		}
+		metrics.Redemption(metrics.ResultFailed)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	metrics.Redemption(metrics.ResultPaid)
+	slog.InfoContext(ctx, "redeemed code", "code", request.Code, "address", request.Address, "amount", amount, "chain_id", payoutChainID, "tx_hash", txHash)
 	w.WriteHeader(http.StatusOK)
 }
 
 func (s *BotService) Drain(w http.ResponseWriter, r *http.Request) {
 	a := os.Getenv("ADMIN_ADDRESS")
 	if a == "" || a == "x" || a == "0x" {
-		fmt.Println("WARNING: be sure to specify an admin address in .env")
+		slog.ErrorContext(r.Context(), "ADMIN_ADDRESS is not set in .env")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	adminAddress := common.HexToAddress(a)
-	err := s.bot.Drain(adminAddress)
+	err := s.bot.Drain(r.Context(), adminAddress)
 	if err != nil {
-		fmt.Printf("error draining faucet: %s\n", err)
+		slog.ErrorContext(r.Context(), "error draining faucet", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	s.recordAdminAudit(r, structs.AdminAuditFaucetDrain, "faucet", adminAddress.Hex(), nil, map[string]string{"destination": adminAddress.Hex()})
 
 	w.WriteHeader(http.StatusCreated)
 }
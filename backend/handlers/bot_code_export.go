@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+	"github.com/SFLuv/app/backend/utils/pdf"
+	"github.com/SFLuv/app/backend/utils/qrcode"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultRedeemAppOrigin = "https://app.sfluv.org"
+	codeSheetColumns       = 2
+	codeSheetRows          = 3
+	codeSheetMargin        = 36.0
+	codeSheetHeader        = 40.0
+	codeSheetQRSize        = 130.0
+)
+
+var codeExportFilenamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// eventRedeemURL is the link encoded in printed codes. It matches the
+// frontend's QR cards: the app origin with ?code=...&page=redeem.
+func eventRedeemURL(code string) string {
+	origin := strings.TrimSpace(os.Getenv("REDEEM_APP_ORIGIN"))
+	if origin == "" {
+		origin = strings.TrimSpace(os.Getenv("APP_BASE_URL"))
+	}
+	if origin == "" {
+		origin = defaultRedeemAppOrigin
+	}
+	query := url.Values{}
+	query.Set("code", strings.TrimSpace(code))
+	query.Set("page", "redeem")
+	return strings.TrimRight(origin, "/") + "?" + query.Encode()
+}
+
+// Export an event's codes as printable QR sheets (format=pdf, the default) or
+// a CSV with redemption status (format=csv). unredeemed=true skips codes that
+// were already redeemed so fresh sheets can be printed mid-event.
+func (s *BotService) ExportCodes(w http.ResponseWriter, r *http.Request) {
+	event := r.PathValue("event_id")
+	if event == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.writeCodesExport(w, r, event)
+}
+
+func (s *BotService) AffiliateExportCodes(w http.ResponseWriter, r *http.Request) {
+	event := r.PathValue("event_id")
+	if event == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	owner, err := s.db.GetEventOwner(r.Context(), event)
+	if err != nil || owner == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if owner != *userDid {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	s.writeCodesExport(w, r, event)
+}
+
+func (s *BotService) writeCodesExport(w http.ResponseWriter, r *http.Request, eventId string) {
+	params := r.URL.Query()
+	format := strings.ToLower(strings.TrimSpace(params.Get("format")))
+	if format == "" {
+		format = "pdf"
+	}
+	if format != "pdf" && format != "csv" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("format must be pdf or csv"))
+		return
+	}
+	unredeemedOnly := params.Get("unredeemed") == "true"
+
+	event, err := s.db.GetEvent(r.Context(), eventId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		slog.ErrorContext(r.Context(), "error loading event for code export", "event_id", eventId, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	codes, err := s.db.GetCodesForExport(r.Context(), eventId, unredeemedOnly)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error loading codes for code export", "event_id", eventId, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(codes) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var body []byte
+	contentType := "application/pdf"
+	if format == "csv" {
+		body, err = renderCodesCSV(codes)
+		contentType = "text/csv; charset=utf-8"
+	} else {
+		body, err = renderCodesPDF(event, codes, time.Now())
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error rendering code export", "format", format, "event_id", eventId, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+codeExportFilename(event, unredeemedOnly, format)+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+func codeExportFilename(event *structs.Event, unredeemedOnly bool, format string) string {
+	name := strings.Trim(codeExportFilenamePattern.ReplaceAllString(strings.ToLower(event.Title), "-"), "-")
+	if name == "" {
+		name = event.Id
+	}
+	if unredeemedOnly {
+		name += "-unredeemed"
+	}
+	return "sfluv-codes-" + name + "-" + time.Now().UTC().Format("2006-01-02") + "." + format
+}
+
+func renderCodesCSV(codes []*structs.CodeExportRow) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write([]string{"code", "redeem_url", "redeemed", "redeemed_by", "chain_id"}); err != nil {
+		return nil, err
+	}
+	for _, code := range codes {
+		chainId := ""
+		if code.ChainId > 0 {
+			chainId = strconv.FormatInt(code.ChainId, 10)
+		}
+		if err := writer.Write([]string{
+			code.Id,
+			eventRedeemURL(code.Id),
+			strconv.FormatBool(code.Redeemed),
+			code.RedeemedBy,
+			chainId,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderCodesPDF lays the codes out six to a US Letter page, each in a
+// cut-out card with the redeem QR code and the code printed underneath.
+func renderCodesPDF(event *structs.Event, codes []*structs.CodeExportRow, generatedAt time.Time) ([]byte, error) {
+	doc := pdf.New(pdf.LetterWidth, pdf.LetterHeight)
+	perPage := codeSheetColumns * codeSheetRows
+	pageCount := (len(codes) + perPage - 1) / perPage
+	cellWidth := (pdf.LetterWidth - 2*codeSheetMargin) / codeSheetColumns
+	cellHeight := (pdf.LetterHeight - 2*codeSheetMargin - codeSheetHeader) / codeSheetRows
+
+	title := strings.TrimSpace(event.Title)
+	if title == "" {
+		title = "SFLuv event"
+	}
+	subtitle := fmt.Sprintf("%d codes - %d SFLUV each - generated %s", len(codes), event.Amount, generatedAt.UTC().Format("Jan 2, 2006"))
+
+	var page *pdf.Page
+	for i, code := range codes {
+		slot := i % perPage
+		if slot == 0 {
+			page = doc.AddPage()
+			page.Text(codeSheetMargin, codeSheetMargin+12, 14, true, pdf.Truncate(title, pdf.LetterWidth-2*codeSheetMargin, 14, true))
+			page.Text(codeSheetMargin, codeSheetMargin+28, 9, false, subtitle)
+			page.TextCentered(pdf.LetterWidth/2, pdf.LetterHeight-18, 8, false, fmt.Sprintf("Page %d of %d", doc.PageCount(), pageCount))
+		}
+
+		x := codeSheetMargin + float64(slot%codeSheetColumns)*cellWidth
+		y := codeSheetMargin + codeSheetHeader + float64(slot/codeSheetColumns)*cellHeight
+		if err := drawCodeCard(page, x, y, cellWidth, cellHeight, event, code.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawCodeCard(page *pdf.Page, x float64, y float64, w float64, h float64, event *structs.Event, code string) error {
+	// Light cut lines around the card.
+	const border = 0.5
+	page.Rect(x, y, w, border, 0.8)
+	page.Rect(x, y+h-border, w, border, 0.8)
+	page.Rect(x, y, border, h, 0.8)
+	page.Rect(x+w-border, y, border, h, 0.8)
+
+	cx := x + w/2
+	page.TextCentered(cx, y+22, 12, true, "Thank you from SFLuv!")
+	page.TextCentered(cx, y+37, 10, false, fmt.Sprintf("%d SFLUV", event.Amount))
+
+	qr, err := qrcode.Encode([]byte(eventRedeemURL(code)))
+	if err != nil {
+		return err
+	}
+	drawQR(page, cx-codeSheetQRSize/2, y+45, codeSheetQRSize, qr)
+
+	page.TextCentered(cx, y+45+codeSheetQRSize+12, 7, false, code)
+	page.TextCentered(cx, y+45+codeSheetQRSize+26, 8, false, "Scan with your phone camera to redeem")
+	return nil
+}
+
+// drawQR draws the symbol with a four-module quiet zone inside size x size
+// points, merging horizontal runs of dark modules into single rectangles.
+func drawQR(page *pdf.Page, x float64, y float64, size float64, qr *qrcode.Code) {
+	module := size / float64(qr.Size+8)
+	for row := 0; row < qr.Size; row++ {
+		for col := 0; col < qr.Size; {
+			if !qr.Modules[row][col] {
+				col++
+				continue
+			}
+			start := col
+			for col < qr.Size && qr.Modules[row][col] {
+				col++
+			}
+			page.Rect(x+float64(start+4)*module, y+float64(row+4)*module, float64(col-start)*module, module, 0)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestEventRedeemURL(t *testing.T) {
+	t.Setenv("REDEEM_APP_ORIGIN", "")
+	t.Setenv("APP_BASE_URL", "")
+	if got := eventRedeemURL("abc-123"); got != "https://app.sfluv.org?code=abc-123&page=redeem" {
+		t.Fatalf("eventRedeemURL() = %q", got)
+	}
+
+	t.Setenv("REDEEM_APP_ORIGIN", "https://staging.sfluv.org/")
+	if got := eventRedeemURL("abc-123"); got != "https://staging.sfluv.org?code=abc-123&page=redeem" {
+		t.Fatalf("eventRedeemURL() with origin = %q", got)
+	}
+}
+
+func TestRenderCodesCSV(t *testing.T) {
+	t.Setenv("REDEEM_APP_ORIGIN", "https://app.sfluv.org")
+	body, err := renderCodesCSV([]*structs.CodeExportRow{
+		{Id: "code-1"},
+		{Id: "code-2", Redeemed: true, RedeemedBy: "0xabc", ChainId: 42220},
+	})
+	if err != nil {
+		t.Fatalf("renderCodesCSV() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	want := []string{
+		"code,redeem_url,redeemed,redeemed_by,chain_id",
+		"code-1,https://app.sfluv.org?code=code-1&page=redeem,false,,",
+		"code-2,https://app.sfluv.org?code=code-2&page=redeem,true,0xabc,42220",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines; want %d", len(lines), len(want))
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("line %d = %q; want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestRenderCodesPDFPaginates(t *testing.T) {
+	codes := make([]*structs.CodeExportRow, 13)
+	for i := range codes {
+		codes[i] = &structs.CodeExportRow{Id: "3f2b8c1e-5d4a-4e7b-9c0d-1a2b3c4d5e6f"}
+	}
+	body, err := renderCodesPDF(&structs.Event{Id: "event-1", Title: "Spring cleanup", Amount: 50}, codes, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("renderCodesPDF() error = %v", err)
+	}
+	out := string(body)
+	if !strings.Contains(out, "/Count 3") {
+		t.Fatalf("13 codes should fill 3 pages")
+	}
+	if !strings.Contains(out, "(Page 3 of 3)") || !strings.Contains(out, "(50 SFLUV)") {
+		t.Fatalf("missing footer or amount text")
+	}
+}
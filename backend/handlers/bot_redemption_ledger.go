@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ponder indexes transfers a little after they are mined, so recent attempts
+// are reported without being flagged.
+const redemptionReconcileGrace = 5 * time.Minute
+
+// A reservation made just before an event expires can be paid a while after,
+// once a stuck transaction has been bumped, so the scan of faucet transfers
+// runs this far past the expiration.
+const redemptionReconcileSettleWindow = time.Hour
+
+// Block timestamps and the server clock can disagree slightly, so a transfer
+// may appear to land just before the reservation it paid.
+const redemptionReconcileClockSkew = time.Minute
+
+// redemptionAttempts folds an event's ledger into one row per reservation.
+// Payout and undo entries belong to the latest reservation of their code.
+func redemptionAttempts(entries []*structs.RedemptionLedgerEntry) []*structs.RedemptionReconciliationRow {
+	rows := []*structs.RedemptionReconciliationRow{}
+	current := map[string]*structs.RedemptionReconciliationRow{}
+
+	for _, entry := range entries {
+		row := current[entry.Code]
+		if entry.Action == structs.RedemptionLedgerReserved || row == nil {
+			row = &structs.RedemptionReconciliationRow{
+				Code:       entry.Code,
+				Address:    entry.Address,
+				ChainId:    entry.ChainId,
+				Amount:     entry.Amount,
+				ReservedAt: entry.CreatedAt,
+				TxHashes:   []string{},
+			}
+			rows = append(rows, row)
+			current[entry.Code] = row
+		}
+		row.Status = entry.Action
+		if entry.TxHash != "" {
+			row.TxHashes = append(row.TxHashes, entry.TxHash)
+		}
+	}
+
+	return rows
+}
+
+// matchRedemptionTransfers pairs attempts with the faucet's on-chain
+// transfers. Each attempt first takes the transfer of one of its candidate
+// hashes, which include gas bump replacements of the hashes it recorded, and
+// is flagged transfer_differs when that transfer's recipient or amount is not
+// what the ledger holds. Attempts left over take the earliest transfer to
+// their address for their amount, made after they were reserved, that no
+// ledger entry records. Unit scales whole tokens to base units.
+//
+// It returns the transfers of eventAmount that neither an attempt nor any
+// ledger entry accounts for.
+func matchRedemptionTransfers(rows []*structs.RedemptionReconciliationRow, candidates map[*structs.RedemptionReconciliationRow][]string, transfers []*structs.PonderTransaction, recorded map[string]bool, eventAmount uint64, unit *big.Int) []*structs.RedemptionUnrecordedTransfer {
+	byHash := make(map[string]*structs.PonderTransaction, len(transfers))
+	for _, transfer := range transfers {
+		byHash[strings.ToLower(transfer.Hash)] = transfer
+	}
+	claimed := map[*structs.PonderTransaction]bool{}
+	claim := func(row *structs.RedemptionReconciliationRow, transfer *structs.PonderTransaction, matchedBy string) {
+		claimed[transfer] = true
+		row.OnChainHash = strings.ToLower(transfer.Hash)
+		row.OnChainTo = strings.ToLower(transfer.To)
+		row.OnChainAmount = transfer.Amount
+		row.MatchedBy = matchedBy
+	}
+	expected := func(amount uint64) string {
+		return new(big.Int).Mul(new(big.Int).SetUint64(amount), unit).String()
+	}
+
+	for _, row := range rows {
+		if row.Skipped != "" {
+			continue
+		}
+		for _, hash := range candidates[row] {
+			transfer := byHash[strings.ToLower(hash)]
+			if transfer == nil || claimed[transfer] {
+				continue
+			}
+			claim(row, transfer, structs.RedemptionMatchedByTxHash)
+			if !strings.EqualFold(transfer.To, row.Address) || transfer.Amount != expected(row.Amount) {
+				row.Mismatch = structs.RedemptionMismatchTransferDiffers
+			}
+			break
+		}
+	}
+
+	skew := int64(redemptionReconcileClockSkew / time.Second)
+	for _, row := range rows {
+		if row.Skipped != "" || row.OnChainHash != "" {
+			continue
+		}
+		amount := expected(row.Amount)
+		for _, transfer := range transfers {
+			if claimed[transfer] || recorded[strings.ToLower(transfer.Hash)] {
+				continue
+			}
+			if int64(transfer.Timestamp)+skew < row.ReservedAt || !strings.EqualFold(transfer.To, row.Address) || transfer.Amount != amount {
+				continue
+			}
+			claim(row, transfer, structs.RedemptionMatchedByRecipient)
+			break
+		}
+	}
+
+	unrecorded := []*structs.RedemptionUnrecordedTransfer{}
+	amount := expected(eventAmount)
+	for _, transfer := range transfers {
+		if claimed[transfer] || recorded[strings.ToLower(transfer.Hash)] || transfer.Amount != amount {
+			continue
+		}
+		unrecorded = append(unrecorded, &structs.RedemptionUnrecordedTransfer{
+			Hash:      strings.ToLower(transfer.Hash),
+			To:        strings.ToLower(transfer.To),
+			Amount:    transfer.Amount,
+			Timestamp: transfer.Timestamp,
+		})
+	}
+	return unrecorded
+}
+
+// classifyRedemptionAttempt sets row.Mismatch from the ledger status and
+// how the attempt was matched to an on-chain transfer, unless matching has
+// already flagged it.
+func classifyRedemptionAttempt(row *structs.RedemptionReconciliationRow, now time.Time) {
+	if row.Mismatch != "" || row.Skipped != "" {
+		return
+	}
+	onChain := row.OnChainHash != ""
+	settled := now.Sub(time.Unix(row.ReservedAt, 0)) >= redemptionReconcileGrace
+
+	// A transfer found only by recipient paid the attempt without the
+	// ledger recording it, whatever the ledger says happened.
+	if onChain && row.MatchedBy == structs.RedemptionMatchedByRecipient {
+		row.Mismatch = structs.RedemptionMismatchSentNotRecorded
+		return
+	}
+
+	switch row.Status {
+	case structs.RedemptionLedgerSent, structs.RedemptionLedgerReserved:
+		if !onChain && settled {
+			row.Mismatch = structs.RedemptionMismatchRecordedNotSent
+		}
+	case structs.RedemptionLedgerFailed, structs.RedemptionLedgerUndone:
+		if onChain {
+			row.Mismatch = structs.RedemptionMismatchSentNotRecorded
+		}
+	}
+}
+
+// redemptionCandidateHashes returns the attempt's recorded hashes along with
+// any gas bump replacements of them.
+func (s *BotService) redemptionCandidateHashes(ctx context.Context, row *structs.RedemptionReconciliationRow) ([]string, error) {
+	candidates := []string{}
+	for _, txHash := range row.TxHashes {
+		group, err := s.db.GetBotTransactionGroupHashes(ctx, txHash)
+		if err != nil {
+			return nil, err
+		}
+		if len(group) == 0 {
+			group = []string{txHash}
+		}
+		candidates = append(candidates, group...)
+	}
+	return candidates, nil
+}
+
+// reconcileRedemptions diffs an event's ledger against the faucet transfers
+// Ponder indexed during the event, in both directions: attempts without a
+// matching transfer and transfers without a ledger entry.
+func (s *BotService) reconcileRedemptions(ctx context.Context, event string) (*structs.RedemptionReconciliation, error) {
+	eventInfo, err := s.db.GetEvent(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.db.GetRedemptionLedger(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ponder only indexes the active chain, so transfers are scanned from the
+	// faucet running there.
+	chainID := s.chainID()
+	faucet, ok := s.botForChain(chainID)
+	if !ok || faucet == nil {
+		return nil, fmt.Errorf("no faucet bot is running on indexed chain %d", chainID)
+	}
+	faucetAddress := faucet.Key()
+	if faucetAddress == "" {
+		return nil, fmt.Errorf("faucet address is not available")
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(faucet.TokenDecimals())), nil)
+
+	now := time.Now()
+	end := now.Unix()
+	if eventInfo.Expiration > 0 {
+		if settleEnd := int64(eventInfo.Expiration) + int64(redemptionReconcileSettleWindow/time.Second); settleEnd < end {
+			end = settleEnd
+		}
+	}
+	transfers, err := s.ponderDb.GetTransfersFromAddress(ctx, faucetAddress, int64(eventInfo.StartAt), end, chainID)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		hashes = append(hashes, transfer.Hash)
+	}
+	recorded, err := s.db.GetRedemptionLedgerRecordedHashes(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &structs.RedemptionReconciliation{
+		Event:     event,
+		CheckedAt: now.Unix(),
+		Rows:      redemptionAttempts(entries),
+	}
+	candidates := map[*structs.RedemptionReconciliationRow][]string{}
+	for _, row := range report.Rows {
+		if row.ChainId != 0 && row.ChainId != chainID {
+			row.Skipped = fmt.Sprintf("chain %d is not indexed", row.ChainId)
+			report.Skipped++
+			continue
+		}
+		candidates[row], err = s.redemptionCandidateHashes(ctx, row)
+		if err != nil {
+			return nil, fmt.Errorf("error loading transactions for redemption of code %s: %s", row.Code, err)
+		}
+	}
+
+	report.UnrecordedTransfers = matchRedemptionTransfers(report.Rows, candidates, transfers, recorded, eventInfo.Amount, unit)
+	for _, row := range report.Rows {
+		classifyRedemptionAttempt(row, now)
+		if row.Mismatch != "" {
+			report.Mismatches++
+		}
+	}
+	report.Attempts = len(report.Rows)
+	report.Mismatches += len(report.UnrecordedTransfers)
+
+	return report, nil
+}
+
+// Compare an event's redemption ledger against Ponder transfers. Responds
+// with every attempt, flagging sent_not_recorded, recorded_not_sent and
+// transfer_differs, and lists faucet transfers missing from the ledger.
+func (s *BotService) ReconcileRedemptions(w http.ResponseWriter, r *http.Request) {
+	event := r.PathValue("event_id")
+	if event == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.writeRedemptionReconciliation(w, r, event)
+}
+
+func (s *BotService) AffiliateReconcileRedemptions(w http.ResponseWriter, r *http.Request) {
+	event := r.PathValue("event_id")
+	if event == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	owner, err := s.db.GetEventOwner(r.Context(), event)
+	if err != nil || owner == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if owner != *userDid {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	s.writeRedemptionReconciliation(w, r, event)
+}
+
+func (s *BotService) writeRedemptionReconciliation(w http.ResponseWriter, r *http.Request, event string) {
+	if s.ponderDb == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	report, err := s.reconcileRedemptions(ctx, event)
+	if err == pgx.ErrNoRows {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error reconciling redemptions", "event_id", event, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	bytes, err := json.Marshal(report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(bytes)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestRedemptionAttemptsGroupsByReservation(t *testing.T) {
+	entries := []*structs.RedemptionLedgerEntry{
+		{Code: "code-1", Address: "0xa", Amount: 10, Action: structs.RedemptionLedgerReserved, CreatedAt: 100},
+		{Code: "code-2", Address: "0xb", Amount: 10, Action: structs.RedemptionLedgerReserved, CreatedAt: 101},
+		{Code: "code-1", Address: "0xa", Action: structs.RedemptionLedgerFailed, TxHash: "0x01"},
+		{Code: "code-1", Address: "0xa", Action: structs.RedemptionLedgerUndone},
+		{Code: "code-2", Address: "0xb", Action: structs.RedemptionLedgerSent, TxHash: "0x02"},
+		{Code: "code-1", Address: "0xc", Amount: 10, Action: structs.RedemptionLedgerReserved, CreatedAt: 200},
+		{Code: "code-1", Address: "0xc", Action: structs.RedemptionLedgerSent, TxHash: "0x03"},
+	}
+
+	rows := redemptionAttempts(entries)
+	if len(rows) != 3 {
+		t.Fatalf("got %d attempts; want 3", len(rows))
+	}
+	want := []struct {
+		code, address, status, hash string
+	}{
+		{"code-1", "0xa", structs.RedemptionLedgerUndone, "0x01"},
+		{"code-2", "0xb", structs.RedemptionLedgerSent, "0x02"},
+		{"code-1", "0xc", structs.RedemptionLedgerSent, "0x03"},
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.Code != w.code || row.Address != w.address || row.Status != w.status {
+			t.Fatalf("attempt %d = %+v; want %+v", i, row, w)
+		}
+		if len(row.TxHashes) != 1 || row.TxHashes[0] != w.hash {
+			t.Fatalf("attempt %d hashes = %v; want [%s]", i, row.TxHashes, w.hash)
+		}
+	}
+}
+
+func TestClassifyRedemptionAttempt(t *testing.T) {
+	now := time.Unix(10000, 0)
+	old := now.Add(-time.Hour).Unix()
+	recent := now.Add(-time.Minute).Unix()
+
+	cases := []struct {
+		name     string
+		row      structs.RedemptionReconciliationRow
+		mismatch string
+	}{
+		{name: "sent and indexed", row: structs.RedemptionReconciliationRow{Status: structs.RedemptionLedgerSent, OnChainHash: "0x01", ReservedAt: old}},
+		{name: "sent but missing", row: structs.RedemptionReconciliationRow{Status: structs.RedemptionLedgerSent, ReservedAt: old}, mismatch: structs.RedemptionMismatchRecordedNotSent},
+		{name: "sent recently", row: structs.RedemptionReconciliationRow{Status: structs.RedemptionLedgerSent, ReservedAt: recent}},
+		{name: "reserved without payout", row: structs.RedemptionReconciliationRow{Status: structs.RedemptionLedgerReserved, ReservedAt: old}, mismatch: structs.RedemptionMismatchRecordedNotSent},
+		{name: "failed but landed", row: structs.RedemptionReconciliationRow{Status: structs.RedemptionLedgerFailed, OnChainHash: "0x01", ReservedAt: old}, mismatch: structs.RedemptionMismatchSentNotRecorded},
+		{name: "undone but landed", row: structs.RedemptionReconciliationRow{Status: structs.RedemptionLedgerUndone, OnChainHash: "0x01", ReservedAt: recent}, mismatch: structs.RedemptionMismatchSentNotRecorded},
+		{name: "undone and not sent", row: structs.RedemptionReconciliationRow{Status: structs.RedemptionLedgerUndone, ReservedAt: old}},
+	}
+
+	for _, tc := range cases {
+		row := tc.row
+		classifyRedemptionAttempt(&row, now)
+		if row.Mismatch != tc.mismatch {
+			t.Fatalf("%s: mismatch = %q; want %q", tc.name, row.Mismatch, tc.mismatch)
+		}
+	}
+}
+
+func TestMatchRedemptionTransfers(t *testing.T) {
+	unit := big.NewInt(100)
+	rows := []*structs.RedemptionReconciliationRow{
+		{Code: "hashed", Address: "0xA", Amount: 10, Status: structs.RedemptionLedgerSent, TxHashes: []string{"0x01"}, ReservedAt: 100},
+		{Code: "bumped", Address: "0xb", Amount: 10, Status: structs.RedemptionLedgerSent, TxHashes: []string{"0x02"}, ReservedAt: 100},
+		{Code: "wrong-recipient", Address: "0xc", Amount: 10, Status: structs.RedemptionLedgerSent, TxHashes: []string{"0x03"}, ReservedAt: 100},
+		{Code: "unrecorded-hash", Address: "0xd", Amount: 10, Status: structs.RedemptionLedgerReserved, TxHashes: []string{}, ReservedAt: 200},
+		{Code: "never-sent", Address: "0xe", Amount: 10, Status: structs.RedemptionLedgerSent, TxHashes: []string{"0x05"}, ReservedAt: 100},
+		{Code: "other-chain", Address: "0xf", Amount: 10, Status: structs.RedemptionLedgerSent, TxHashes: []string{"0x06"}, ChainId: 1, Skipped: "chain 1 is not indexed"},
+	}
+	candidates := map[*structs.RedemptionReconciliationRow][]string{
+		rows[0]: {"0x01"},
+		rows[1]: {"0x12", "0x02"},
+		rows[2]: {"0x03"},
+		rows[4]: {"0x05"},
+	}
+	transfers := []*structs.PonderTransaction{
+		{Hash: "0x01", To: "0xa", Amount: "1000", Timestamp: 101},
+		{Hash: "0x12", To: "0xb", Amount: "1000", Timestamp: 130},
+		{Hash: "0x03", To: "0x9", Amount: "1000", Timestamp: 101},
+		// Paid before the reservation, so it cannot be this attempt's.
+		{Hash: "0x30", To: "0xd", Amount: "1000", Timestamp: 50},
+		{Hash: "0x31", To: "0xd", Amount: "1000", Timestamp: 201},
+		// Another event's payout to the same address.
+		{Hash: "0x32", To: "0xd", Amount: "1000", Timestamp: 202},
+		// A faucet transfer of another amount, such as a workflow payout.
+		{Hash: "0x40", To: "0x8", Amount: "2500", Timestamp: 150},
+	}
+	recorded := map[string]bool{"0x01": true, "0x12": true, "0x03": true, "0x32": true}
+
+	unrecorded := matchRedemptionTransfers(rows, candidates, transfers, recorded, 10, unit)
+	now := time.Unix(10000, 0)
+	for _, row := range rows {
+		classifyRedemptionAttempt(row, now)
+	}
+
+	want := []struct {
+		hash, matchedBy, mismatch string
+	}{
+		{"0x01", structs.RedemptionMatchedByTxHash, ""},
+		{"0x12", structs.RedemptionMatchedByTxHash, ""},
+		{"0x03", structs.RedemptionMatchedByTxHash, structs.RedemptionMismatchTransferDiffers},
+		{"0x31", structs.RedemptionMatchedByRecipient, structs.RedemptionMismatchSentNotRecorded},
+		{"", "", structs.RedemptionMismatchRecordedNotSent},
+		{"", "", ""},
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.OnChainHash != w.hash || row.MatchedBy != w.matchedBy || row.Mismatch != w.mismatch {
+			t.Fatalf("%s = %+v; want hash %q matched by %q mismatch %q", row.Code, row, w.hash, w.matchedBy, w.mismatch)
+		}
+	}
+
+	if len(unrecorded) != 1 || unrecorded[0].Hash != "0x30" {
+		t.Fatalf("unrecorded transfers = %+v; want [0x30]", unrecorded)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
 	"github.com/jackc/pgx/v5"
 )
@@ -184,6 +185,7 @@ func (s *BotService) AdminForceApproveWorkflow(w http.ResponseWriter, r *http.Re
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	s.recordAdminAudit(r, structs.AdminAuditWorkflowForceApprove, "workflow", workflowId, workflowAuditState(workflow.Status), workflowAuditState(updatedWorkflow.Status))
 	sanitizeWorkflowForUser(updatedWorkflow, *adminId, true)
 
 	w.WriteHeader(http.StatusOK)
@@ -221,7 +223,7 @@ func getTokenMultiplier() (*big.Int, error) {
 }
 
 func (s *BotService) unallocatedBalanceWei(ctx context.Context) (*big.Int, error) {
-	faucetBalance, err := s.bot.Balance()
+	faucetBalance, err := s.bot.Balance(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/lifecycle"
+	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/metrics"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	emailOutboxLease           = time.Minute
+	emailOutboxPollInterval    = 5 * time.Second
+	emailOutboxSendTimeout     = 30 * time.Second
+	emailOutboxBatchSize       = 10
+	emailOutboxBackoffBase     = 30 * time.Second
+	emailOutboxBackoffMax      = time.Hour
+	emailOutboxDefaultAttempts = 8
+)
+
+func emailOutboxMaxAttempts() int {
+	attempts := envInt("EMAIL_OUTBOX_MAX_ATTEMPTS", emailOutboxDefaultAttempts)
+	if attempts <= 0 {
+		return emailOutboxDefaultAttempts
+	}
+	return attempts
+}
+
+// emailOutboxBackoff returns the delay before retry number attempt, doubling
+// from emailOutboxBackoffBase up to emailOutboxBackoffMax.
+func emailOutboxBackoff(attempt int) time.Duration {
+	delay := emailOutboxBackoffBase
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= emailOutboxBackoffMax {
+			return emailOutboxBackoffMax
+		}
+	}
+	return delay
+}
+
+// EmailOutbox is the worker that drains email_outbox into the configured
+// sink: Mailgun, or the test notification writer in notification test mode.
+type EmailOutbox struct {
+	app       *AppService
+	sink      utils.EmailSink
+	logger    *logger.LogCloser
+	lifecycle *lifecycle.Manager
+	workerId  string
+	wake      chan struct{}
+}
+
+func NewEmailOutbox(app *AppService, sink utils.EmailSink, logger *logger.LogCloser) *EmailOutbox {
+	hostname, err := os.Hostname()
+	if err != nil || strings.TrimSpace(hostname) == "" {
+		hostname = "backend"
+	}
+
+	return &EmailOutbox{
+		app:      app,
+		sink:     sink,
+		logger:   logger,
+		workerId: fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func (o *EmailOutbox) Start(lc *lifecycle.Manager) {
+	if o == nil || o.app == nil {
+		return
+	}
+	if o.sink == nil {
+		o.logger.Warnf(lc.Context(), "email outbox has no sink configured; queued emails will wait until MAILGUN_DOMAIN and MAILGUN_API_KEY are set")
+		return
+	}
+	o.lifecycle = lc
+
+	lc.Go("email outbox", func(ctx context.Context) {
+		ticker := time.NewTicker(emailOutboxPollInterval)
+		defer ticker.Stop()
+
+		for {
+			o.drain(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-o.wake:
+			}
+		}
+	})
+}
+
+// Notify wakes the worker so newly queued emails go out without waiting for
+// the next poll.
+func (o *EmailOutbox) Notify() {
+	if o == nil {
+		return
+	}
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *EmailOutbox) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := o.app.db.LeaseEmailOutboxMessages(ctx, o.workerId, int64(emailOutboxLease/time.Second), emailOutboxBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				o.logger.Errorf(ctx, "error leasing email outbox messages: %s", err)
+			}
+			return
+		}
+		if len(messages) == 0 {
+			return
+		}
+		// Leased messages are delivered on the work context so a shutdown
+		// does not abandon a send Mailgun may already have accepted.
+		for _, msg := range messages {
+			o.deliver(o.lifecycle.WorkContext(), msg)
+		}
+	}
+}
+
+func (o *EmailOutbox) deliver(ctx context.Context, msg *structs.EmailOutboxMessage) {
+	requestId := msg.RequestId
+	if requestId == "" {
+		requestId = "email-" + msg.Id
+	}
+	ctx = logger.WithRequestID(ctx, requestId)
+
+	attachments := make([]utils.EmailAttachment, 0, len(msg.Attachments))
+	for _, attachment := range msg.Attachments {
+		attachments = append(attachments, utils.EmailAttachment{Filename: attachment.Filename, Data: attachment.Data})
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, emailOutboxSendTimeout)
+	providerMessageId, err := o.sink.Deliver(sendCtx, &utils.EmailMessage{
+		ToEmail:     msg.ToEmail,
+		ToName:      msg.ToName,
+		Subject:     msg.Subject,
+		HTML:        msg.HTML,
+		Text:        msg.Text,
+		FromEmail:   msg.FromEmail,
+		FromName:    msg.FromName,
+		Attachments: attachments,
+	})
+	cancel()
+
+	if err == nil {
+		metrics.EmailSend(o.sink.Name(), metrics.ResultSent)
+		if err := o.app.db.MarkEmailOutboxSent(ctx, msg.Id, o.workerId, o.sink.Name(), providerMessageId); err != nil {
+			o.logger.Errorf(ctx, "error marking email outbox message %s sent: %s", msg.Id, err)
+		}
+		return
+	}
+
+	attempt := msg.Attempts + 1
+	if attempt >= msg.MaxAttempts {
+		metrics.EmailSend(o.sink.Name(), metrics.ResultFailed)
+		if dbErr := o.app.db.FailEmailOutboxMessage(ctx, msg.Id, o.workerId, err.Error()); dbErr != nil {
+			o.logger.Errorf(ctx, "error failing email outbox message %s: %s", msg.Id, dbErr)
+			return
+		}
+		o.logger.Errorf(ctx, "email outbox message %s to %s failed after %d attempts: %s", msg.Id, msg.ToEmail, attempt, err)
+		return
+	}
+
+	metrics.EmailSend(o.sink.Name(), metrics.ResultRetried)
+	delay := emailOutboxBackoff(attempt)
+	if dbErr := o.app.db.RetryEmailOutboxMessage(ctx, msg.Id, o.workerId, int64(delay/time.Second), err.Error()); dbErr != nil {
+		o.logger.Errorf(ctx, "error scheduling retry for email outbox message %s: %s", msg.Id, dbErr)
+	}
+}
+
+func (a *AppService) SetEmailOutbox(outbox *EmailOutbox) {
+	a.emailOutbox = outbox
+}
+
+// enqueueEmail hands email to the outbox, or sends it right away for
+// services built without one, like the init sync service.
+func (a *AppService) enqueueEmail(ctx context.Context, category string, userId string, email *NotificationEmail) error {
+	if a.emailOutbox == nil {
+		return deliverNotificationEmail(email)
+	}
+	if strings.TrimSpace(email.To) == "" {
+		return nil
+	}
+
+	fromName := email.FromName
+	if fromName == "" {
+		fromName = "SFLuv"
+	}
+	attachments := make([]structs.EmailOutboxAttachment, 0, len(email.Attachments))
+	for _, attachment := range email.Attachments {
+		if strings.TrimSpace(attachment.Filename) == "" || len(attachment.Data) == 0 {
+			continue
+		}
+		attachments = append(attachments, structs.EmailOutboxAttachment{Filename: attachment.Filename, Data: attachment.Data})
+	}
+
+	_, err := a.db.EnqueueEmail(ctx, &structs.EmailOutboxMessage{
+		Category:    category,
+		UserId:      userId,
+		ToEmail:     email.To,
+		ToName:      email.ToName,
+		FromEmail:   utils.NotificationFromEmail(),
+		FromName:    fromName,
+		Subject:     email.Subject,
+		HTML:        email.HTML,
+		Text:        email.Text,
+		Attachments: attachments,
+		MaxAttempts: emailOutboxMaxAttempts(),
+	})
+	if err != nil {
+		return err
+	}
+	a.emailOutbox.Notify()
+	return nil
+}
+
+func (a *AppService) GetAdminEmailOutbox(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	statuses := []string{}
+	for _, status := range strings.Split(params.Get("status"), ",") {
+		status = strings.TrimSpace(status)
+		switch status {
+		case "":
+			continue
+		case structs.EmailOutboxStatusPending,
+			structs.EmailOutboxStatusSending,
+			structs.EmailOutboxStatusSent,
+			structs.EmailOutboxStatusFailed,
+			structs.EmailOutboxStatusBounced:
+			statuses = append(statuses, status)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("invalid status value"))
+			return
+		}
+	}
+	page, count := parsePageAndCount(params, 20, 200)
+
+	response, err := a.db.GetEmailOutboxMessages(r.Context(), statuses, page, count)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error loading email outbox: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (a *AppService) ResendAdminEmailOutboxMessage(w http.ResponseWriter, r *http.Request) {
+	adminId := utils.GetDid(r)
+	if adminId == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	messageId := strings.TrimSpace(r.PathValue("message_id"))
+	if messageId == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	msg, err := a.db.ResendEmailOutboxMessage(r.Context(), messageId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "can be resent") {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		a.logger.Errorf(r.Context(), "error resending email outbox message %s by admin %s: %s", messageId, *adminId, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	a.logger.Infof(r.Context(), "email outbox message %s requeued by admin %s", messageId, *adminId)
+	a.emailOutbox.Notify()
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(msg)
+}
+
+// mailgunWebhookMaxAge is how far a signed webhook timestamp may be from now.
+// Older deliveries are refused as replays, and tokens are remembered for
+// twice as long so any delivery still inside the window is acted on once.
+const mailgunWebhookMaxAge = 5 * time.Minute
+
+type mailgunWebhookEvent struct {
+	Event     string `json:"event"`
+	Severity  string `json:"severity"`
+	Reason    string `json:"reason"`
+	Recipient string `json:"recipient"`
+	Message   struct {
+		Headers struct {
+			MessageId string `json:"message-id"`
+		} `json:"headers"`
+	} `json:"message"`
+	DeliveryStatus struct {
+		Message     string `json:"message"`
+		Description string `json:"description"`
+	} `json:"delivery-status"`
+}
+
+type mailgunWebhookPayload struct {
+	Signature struct {
+		Timestamp string `json:"timestamp"`
+		Token     string `json:"token"`
+		Signature string `json:"signature"`
+	} `json:"signature"`
+	EventData mailgunWebhookEvent `json:"event-data"`
+}
+
+// verifyMailgunWebhookSignature checks Mailgun's hex HMAC-SHA256 of
+// timestamp+token under the webhook signing key.
+func verifyMailgunWebhookSignature(signingKey string, timestamp string, token string, signature string) bool {
+	if signingKey == "" || timestamp == "" || token == "" || signature == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(timestamp + token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
+
+// mailgunWebhookFresh reports whether timestamp, in Unix seconds, is within
+// mailgunWebhookMaxAge of now in either direction.
+func mailgunWebhookFresh(timestamp string, now time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	return age <= mailgunWebhookMaxAge && age >= -mailgunWebhookMaxAge
+}
+
+// mailgunWebhookBounce returns the outbox message id and reason to record for
+// event, and false for events that do not bounce a message. Temporary failures
+// are ignored because Mailgun keeps retrying those itself.
+func mailgunWebhookBounce(event mailgunWebhookEvent) (string, string, bool) {
+	if event.Event != "failed" || event.Severity != "permanent" {
+		return "", "", false
+	}
+	messageId := utils.NormalizeEmailMessageId(event.Message.Headers.MessageId)
+	if messageId == "" {
+		return "", "", false
+	}
+
+	reason := strings.TrimSpace(event.DeliveryStatus.Description)
+	if reason == "" {
+		reason = strings.TrimSpace(event.DeliveryStatus.Message)
+	}
+	if reason == "" {
+		reason = event.Reason
+	}
+	return messageId, reason, true
+}
+
+// MailgunEventsWebhook records permanent delivery failures as bounces. Each
+// signed delivery is acted on once: stale timestamps are refused and tokens
+// already seen are acknowledged without effect.
+func (a *AppService) MailgunEventsWebhook(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	signingKey := strings.TrimSpace(os.Getenv("MAILGUN_WEBHOOK_SIGNING_KEY"))
+	if signingKey == "" {
+		a.logger.Warnf(r.Context(), "mailgun webhook received but MAILGUN_WEBHOOK_SIGNING_KEY is not set")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var payload mailgunWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !verifyMailgunWebhookSignature(signingKey, payload.Signature.Timestamp, payload.Signature.Token, payload.Signature.Signature) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if !mailgunWebhookFresh(payload.Signature.Timestamp, time.Now()) {
+		a.logger.Warnf(r.Context(), "refusing mailgun webhook with stale timestamp %s", payload.Signature.Timestamp)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	event := payload.EventData
+	messageId, reason, ok := mailgunWebhookBounce(event)
+	if !ok {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	token := payload.Signature.Token
+	claimed, err := a.db.ClaimMailgunWebhookToken(r.Context(), token, int64(2*mailgunWebhookMaxAge/time.Second))
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error checking mailgun webhook token: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !claimed {
+		a.logger.Warnf(r.Context(), "ignoring replayed mailgun webhook for message %s", messageId)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	found, err := a.db.MarkEmailOutboxBounced(r.Context(), messageId, reason)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error recording mailgun bounce for message %s: %s", messageId, err)
+		if releaseErr := a.db.ReleaseMailgunWebhookToken(r.Context(), token); releaseErr != nil {
+			a.logger.Errorf(r.Context(), "error releasing mailgun webhook token: %s", releaseErr)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if found {
+		metrics.EmailBounce()
+		a.logger.Infof(r.Context(), "email to %s bounced: %s", event.Recipient, reason)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailOutboxBackoff(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 30 * time.Second},
+		{attempt: 2, want: time.Minute},
+		{attempt: 4, want: 4 * time.Minute},
+		{attempt: 8, want: time.Hour},
+		{attempt: 20, want: time.Hour},
+	}
+	for _, tc := range cases {
+		if got := emailOutboxBackoff(tc.attempt); got != tc.want {
+			t.Fatalf("emailOutboxBackoff(%d) = %s; want %s", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestVerifyMailgunWebhookSignature(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("signing-key"))
+	mac.Write([]byte("1700000000" + "token-1"))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	if !verifyMailgunWebhookSignature("signing-key", "1700000000", "token-1", signature) {
+		t.Fatalf("expected valid signature to verify")
+	}
+	if verifyMailgunWebhookSignature("other-key", "1700000000", "token-1", signature) {
+		t.Fatalf("expected signature under another key to fail")
+	}
+	if verifyMailgunWebhookSignature("signing-key", "1700000001", "token-1", signature) {
+		t.Fatalf("expected signature over another timestamp to fail")
+	}
+	if verifyMailgunWebhookSignature("", "1700000000", "token-1", signature) {
+		t.Fatalf("expected missing signing key to fail")
+	}
+}
+
+func TestMailgunWebhookFresh(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cases := []struct {
+		name      string
+		timestamp string
+		want      bool
+	}{
+		{name: "now", timestamp: "1700000000", want: true},
+		{name: "within window", timestamp: "1699999760", want: true},
+		{name: "stale", timestamp: "1699999699", want: false},
+		{name: "small future skew", timestamp: "1700000060", want: true},
+		{name: "far future", timestamp: "1700000301", want: false},
+		{name: "malformed", timestamp: "yesterday", want: false},
+		{name: "empty", timestamp: "", want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := mailgunWebhookFresh(tc.timestamp, now); got != tc.want {
+				t.Fatalf("mailgunWebhookFresh(%q) = %t; want %t", tc.timestamp, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMailgunWebhookBounce(t *testing.T) {
+	event := func(name string, severity string, messageId string) mailgunWebhookEvent {
+		e := mailgunWebhookEvent{Event: name, Severity: severity, Reason: "bounce"}
+		e.Message.Headers.MessageId = messageId
+		return e
+	}
+
+	if _, _, ok := mailgunWebhookBounce(event("delivered", "", "<id-1@mg>")); ok {
+		t.Fatalf("expected delivered event to be ignored")
+	}
+	if _, _, ok := mailgunWebhookBounce(event("failed", "temporary", "<id-1@mg>")); ok {
+		t.Fatalf("expected temporary failure to be ignored")
+	}
+	if _, _, ok := mailgunWebhookBounce(event("failed", "permanent", "")); ok {
+		t.Fatalf("expected failure without a message id to be ignored")
+	}
+
+	permanent := event("failed", "permanent", "<id-1@mg>")
+	messageId, reason, ok := mailgunWebhookBounce(permanent)
+	if !ok || messageId != "id-1@mg" || reason != "bounce" {
+		t.Fatalf("mailgunWebhookBounce = %q, %q, %t; want id-1@mg, bounce, true", messageId, reason, ok)
+	}
+
+	permanent.DeliveryStatus.Message = "mailbox full"
+	permanent.DeliveryStatus.Description = "  no such user  "
+	if _, reason, _ := mailgunWebhookBounce(permanent); reason != "no such user" {
+		t.Fatalf("reason = %q; want delivery status description", reason)
+	}
+}
+
+func TestMailgunEventsWebhookRejections(t *testing.T) {
+	sign := func(key string, timestamp string, token string) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(timestamp + token))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	body := func(timestamp string, signature string, severity string) string {
+		payload := mailgunWebhookPayload{}
+		payload.Signature.Timestamp = timestamp
+		payload.Signature.Token = "token-1"
+		payload.Signature.Signature = signature
+		payload.EventData.Event = "failed"
+		payload.EventData.Severity = severity
+		payload.EventData.Message.Headers.MessageId = "<id-1@mg>"
+		encoded, _ := json.Marshal(payload)
+		return string(encoded)
+	}
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	cases := []struct {
+		name       string
+		signingKey string
+		body       string
+		want       int
+	}{
+		{name: "no signing key", signingKey: "", body: body(now, sign("key", now, "token-1"), "permanent"), want: http.StatusServiceUnavailable},
+		{name: "malformed body", signingKey: "key", body: "{", want: http.StatusBadRequest},
+		{name: "bad signature", signingKey: "key", body: body(now, sign("other", now, "token-1"), "permanent"), want: http.StatusUnauthorized},
+		{name: "stale timestamp", signingKey: "key", body: body(stale, sign("key", stale, "token-1"), "permanent"), want: http.StatusUnauthorized},
+		{name: "temporary failure", signingKey: "key", body: body(now, sign("key", now, "token-1"), "temporary"), want: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("MAILGUN_WEBHOOK_SIGNING_KEY", tc.signingKey)
+
+			service := &AppService{}
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/mailgun", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			service.MailgunEventsWebhook(rec, req)
+
+			if rec.Code != tc.want {
+				t.Fatalf("status = %d; want %d", rec.Code, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/SFLuv/app/backend/emails"
+)
+
+func (a *AppService) GetAdminEmailTemplates(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(emails.Templates())
+}
+
+// PreviewAdminEmailTemplate renders a template with its sample data so
+// admins can check copy changes. format is html (default), text or json.
+func (a *AppService) PreviewAdminEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.PathValue("name"))
+	if !emails.Exists(name) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	params := r.URL.Query()
+	locale := strings.TrimSpace(params.Get("locale"))
+	if locale != "" && !emails.SupportedLocale(locale) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("unsupported locale " + locale))
+		return
+	}
+
+	rendered, err := emails.Preview(name, locale)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error previewing email template %s: %s", name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	switch strings.TrimSpace(params.Get("format")) {
+	case "", "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(rendered.HTML))
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(rendered.Text))
+	case "json":
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(rendered)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("format must be html, text or json"))
+	}
+}
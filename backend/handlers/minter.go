@@ -33,41 +33,41 @@ func NewMinterService(appDb *db.AppDB, log *logger.LogCloser, config *clientconf
 	}
 
 	if config == nil {
-		service.logf("minter sync disabled: client config is not loaded")
+		service.log.Warnf(context.Background(), "minter sync disabled: client config is not loaded")
 		return service
 	}
 	primaryToken, err := config.PrimaryToken()
 	if err != nil {
-		service.logf("minter sync disabled: invalid client config: %s", err)
+		service.log.Warnf(context.Background(), "minter sync disabled: invalid client config: %s", err)
 		return service
 	}
 
 	rpcURL := strings.TrimSpace(config.ReadRPCURL())
 	tokenID := strings.TrimSpace(primaryToken.Address)
 	if rpcURL == "" || tokenID == "" {
-		service.logf("minter sync disabled: missing primary RPC URL or token address in client config")
+		service.log.Warnf(context.Background(), "minter sync disabled: missing primary RPC URL or token address in client config")
 		return service
 	}
 	if !common.IsHexAddress(tokenID) {
-		service.logf("minter sync disabled: invalid configured token address %q", tokenID)
+		service.log.Warnf(context.Background(), "minter sync disabled: invalid configured token address %q", tokenID)
 		return service
 	}
 
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
-		service.logf("minter sync disabled: error connecting RPC: %s", err)
+		service.log.Warnf(context.Background(), "minter sync disabled: error connecting RPC: %s", err)
 		return service
 	}
 
 	contract, err := abi.NewSFLUVv2(common.HexToAddress(tokenID), client)
 	if err != nil {
-		service.logf("minter sync disabled: failed to initialize SFLUV contract: %s", err)
+		service.log.Warnf(context.Background(), "minter sync disabled: failed to initialize SFLUV contract: %s", err)
 		return service
 	}
 
 	minterRole, err := contract.MINTERROLE(&bind.CallOpts{Context: context.Background()})
 	if err != nil {
-		service.logf("minter sync disabled: failed to read MINTER_ROLE: %s", err)
+		service.log.Warnf(context.Background(), "minter sync disabled: failed to read MINTER_ROLE: %s", err)
 		return service
 	}
 
@@ -75,7 +75,7 @@ func NewMinterService(appDb *db.AppDB, log *logger.LogCloser, config *clientconf
 	service.contract = contract
 	service.minterRole = minterRole
 	service.enabled = true
-	service.logf("minter role sync enabled")
+	service.log.Infof(context.Background(), "minter role sync enabled")
 
 	return service
 }
@@ -109,12 +109,12 @@ func (m *MinterService) SyncWalletMinterStatuses(ctx context.Context) error {
 
 		hasRole, err := m.contract.HasRole(&bind.CallOpts{Context: ctx}, m.minterRole, *walletAddress)
 		if err != nil {
-			m.logf("error checking MINTER_ROLE for wallet %d (%s): %s", *wallet.Id, walletAddress.Hex(), err)
+			m.log.Errorf(ctx, "error checking MINTER_ROLE for wallet %d (%s): %s", *wallet.Id, walletAddress.Hex(), err)
 			continue
 		}
 
 		if err := m.appDb.SetWalletMinterStatus(ctx, *wallet.Id, hasRole); err != nil {
-			m.logf("error updating is_minter for wallet %d (%s): %s", *wallet.Id, walletAddress.Hex(), err)
+			m.log.Errorf(ctx, "error updating is_minter for wallet %d (%s): %s", *wallet.Id, walletAddress.Hex(), err)
 			continue
 		}
 	}
@@ -140,10 +140,3 @@ func (m *MinterService) walletAddress(wallet *structs.Wallet) *common.Address {
 
 	return nil
 }
-
-func (m *MinterService) logf(format string, args ...any) {
-	if m.log == nil {
-		return
-	}
-	m.log.Logf(format, args...)
-}
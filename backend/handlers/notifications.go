@@ -0,0 +1,528 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/emails"
+	"github.com/SFLuv/app/backend/lifecycle"
+	"github.com/SFLuv/app/backend/metrics"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+)
+
+const (
+	defaultNotificationTimezone = "America/Los_Angeles"
+	heldPushPollInterval        = time.Minute
+	heldPushBatchSize           = 50
+	heldPushLease               = 5 * time.Minute
+	heldPushRetryDelay          = 5 * time.Minute
+	heldPushMaxAttempts         = 5
+)
+
+var errNotificationEmailNotConfigured = errors.New("email sender is not configured")
+
+// Notification is one message to one recipient. Every sender goes through
+// Notify so the recipient's preferences and quiet hours apply in one place.
+// UserId may be left empty for email-only notifications; Notify looks the
+// user up by address and falls back to defaults for non-users like admins.
+type Notification struct {
+	Category string
+	UserId   string
+	Email    *NotificationEmail
+	Push     *NotificationPush
+}
+
+// NotificationEmail names one of the templates in the emails package and
+// the data it renders with. Notify renders it in the recipient's locale and
+// fills in Subject, HTML and Text.
+type NotificationEmail struct {
+	To          string
+	ToName      string
+	Template    string
+	Data        emails.Data
+	Subject     string
+	HTML        string
+	Text        string
+	FromName    string
+	Attachments []utils.EmailAttachment
+}
+
+func (e *NotificationEmail) render(locale string) error {
+	if e.Template == "" {
+		return nil
+	}
+	rendered, err := emails.Render(e.Template, locale, e.Data)
+	if err != nil {
+		return err
+	}
+	e.Subject = rendered.Subject
+	e.HTML = rendered.HTML
+	e.Text = rendered.Text
+	return nil
+}
+
+type NotificationPush struct {
+	Subscriptions []*structs.MobilePushSubscription
+	Title         string
+	Body          string
+	Data          map[string]string
+}
+
+// Notifier is how services outside AppService reach the dispatcher.
+type Notifier func(context.Context, *Notification) error
+
+// Notify delivers n on every channel the recipient allows for its category.
+// Quiet hours hold back pushes for categories that are not required until
+// the window ends; email is always delivered when enabled.
+func (a *AppService) Notify(ctx context.Context, n *Notification) error {
+	if n == nil {
+		return nil
+	}
+	info, ok := structs.NotificationCategoryByName(n.Category)
+	if !ok {
+		return fmt.Errorf("unknown notification category %s", n.Category)
+	}
+
+	userId := strings.TrimSpace(n.UserId)
+	if userId == "" && n.Email != nil {
+		found, err := a.db.GetUserIdByNotificationEmail(ctx, n.Email.To)
+		if err != nil {
+			a.logger.Errorf(ctx, "error resolving user for %s notification: %s", n.Category, err)
+		}
+		userId = found
+	}
+
+	var stored *structs.NotificationSettings
+	if userId != "" {
+		settings, err := a.db.GetNotificationSettings(ctx, userId)
+		if err != nil {
+			a.logger.Errorf(ctx, "error loading notification settings for user %s, using defaults: %s", userId, err)
+		} else {
+			stored = settings
+		}
+	}
+	settings := resolveNotificationSettings(stored)
+	preference := notificationPreferenceFor(settings, info)
+
+	var errs []error
+	if n.Email != nil && preference.Email {
+		if err := n.Email.render(settings.Locale); err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		} else if err := a.enqueueEmail(ctx, info.Category, userId, n.Email); err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		}
+	}
+
+	if n.Push != nil && preference.Push {
+		if deliverAt, quiet := quietHoursEnd(settings, time.Now()); quiet && !info.Required {
+			for _, listener := range n.Push.Subscriptions {
+				if listener == nil {
+					continue
+				}
+				if err := a.db.HoldPushNotification(ctx, &structs.HeldPushNotification{
+					Category:     info.Category,
+					Title:        n.Push.Title,
+					Body:         n.Push.Body,
+					Data:         n.Push.Data,
+					DeliverAt:    deliverAt.Unix(),
+					Subscription: listener,
+				}); err != nil {
+					errs = append(errs, fmt.Errorf("%s push for address %s: %w", listener.Provider, listener.Address, err))
+				}
+			}
+			return errors.Join(errs...)
+		}
+		for _, listener := range n.Push.Subscriptions {
+			if listener == nil {
+				continue
+			}
+			ticket, err := a.sendPushNotification(ctx, listener, n.Push.Title, n.Push.Body, n.Push.Data)
+			a.handlePushTicket(ctx, listener, ticket)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s push for address %s: %w", listener.Provider, listener.Address, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// StartHeldPushDelivery sends the pushes Notify held back during quiet hours
+// once each recipient's window has ended.
+func (a *AppService) StartHeldPushDelivery(lc *lifecycle.Manager) {
+	lc.Go("held push delivery", func(ctx context.Context) {
+		ticker := time.NewTicker(heldPushPollInterval)
+		defer ticker.Stop()
+
+		for {
+			a.deliverHeldPushes(ctx, lc)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+func (a *AppService) deliverHeldPushes(ctx context.Context, lc *lifecycle.Manager) {
+	for ctx.Err() == nil {
+		due, err := a.db.LeaseDueHeldPushNotifications(ctx, int64(heldPushLease/time.Second), heldPushBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.logger.Errorf(ctx, "error leasing held push notifications: %s", err)
+			}
+			return
+		}
+		if len(due) == 0 {
+			return
+		}
+		// Leased pushes are sent on the work context so a shutdown does not
+		// cut a send short. Any left unsettled are leased again once their
+		// lease expires.
+		for _, held := range due {
+			a.deliverHeldPush(lc.WorkContext(), held)
+		}
+	}
+}
+
+// deliverHeldPush sends one leased push and removes it only once it is sent.
+// A failed send is retried after heldPushRetryDelay, up to heldPushMaxAttempts.
+func (a *AppService) deliverHeldPush(ctx context.Context, held *structs.HeldPushNotification) {
+	if !held.Subscription.Active {
+		if err := a.db.DeleteHeldPushNotification(ctx, held.Id); err != nil {
+			a.logger.Errorf(ctx, "error dropping held push for inactive subscription %d: %s", held.Subscription.Id, err)
+		}
+		return
+	}
+
+	ticket, err := a.sendPushNotification(ctx, held.Subscription, held.Title, held.Body, held.Data)
+	a.handlePushTicket(ctx, held.Subscription, ticket)
+	if err == nil {
+		if err := a.db.DeleteHeldPushNotification(ctx, held.Id); err != nil {
+			a.logger.Errorf(ctx, "error removing sent held push %s: %s", held.Id, err)
+		}
+		return
+	}
+
+	attempt := held.Attempts + 1
+	if attempt >= heldPushMaxAttempts {
+		a.logger.Errorf(ctx, "giving up on held %s push for address %s after %d attempts: %s", held.Category, held.Subscription.Address, attempt, err)
+		if err := a.db.DeleteHeldPushNotification(ctx, held.Id); err != nil {
+			a.logger.Errorf(ctx, "error removing failed held push %s: %s", held.Id, err)
+		}
+		return
+	}
+
+	a.logger.Errorf(ctx, "error sending held %s push for address %s: %s", held.Category, held.Subscription.Address, err)
+	if err := a.db.RetryHeldPushNotification(ctx, held.Id, int64(heldPushRetryDelay/time.Second)); err != nil {
+		a.logger.Errorf(ctx, "error scheduling retry for held push %s: %s", held.Id, err)
+	}
+}
+
+// deliverNotificationEmail sends immediately, bypassing preferences and the
+// outbox. It is the fallback for services built without either.
+func deliverNotificationEmail(email *NotificationEmail) error {
+	toEmail := strings.TrimSpace(email.To)
+	if toEmail == "" {
+		return nil
+	}
+
+	if err := email.render(emails.DefaultLocale); err != nil {
+		return err
+	}
+
+	sender := utils.NewEmailSender()
+	if sender == nil {
+		return errNotificationEmailNotConfigured
+	}
+
+	fromName := email.FromName
+	if fromName == "" {
+		fromName = "SFLuv"
+	}
+	err := sender.Send(&utils.EmailMessage{
+		ToEmail:     toEmail,
+		ToName:      email.ToName,
+		Subject:     email.Subject,
+		HTML:        email.HTML,
+		Text:        email.Text,
+		FromEmail:   utils.NotificationFromEmail(),
+		FromName:    fromName,
+		Attachments: email.Attachments,
+	})
+	if err != nil {
+		metrics.EmailSend("direct", metrics.ResultFailed)
+		return err
+	}
+	metrics.EmailSend("direct", metrics.ResultSent)
+	return nil
+}
+
+// resolveNotificationSettings fills in the locale, timezone and every category the
+// user has not saved, so callers always see the full preferences center.
+func resolveNotificationSettings(stored *structs.NotificationSettings) *structs.NotificationSettings {
+	resolved := &structs.NotificationSettings{
+		Locale:      emails.DefaultLocale,
+		Timezone:    defaultNotificationTimezone,
+		Preferences: make([]structs.NotificationPreference, 0, len(structs.NotificationCategories)),
+		Categories:  structs.NotificationCategories,
+	}
+
+	saved := map[string]structs.NotificationPreference{}
+	if stored != nil {
+		if strings.TrimSpace(stored.Locale) != "" {
+			resolved.Locale = emails.NormalizeLocale(stored.Locale)
+		}
+		if strings.TrimSpace(stored.Timezone) != "" {
+			resolved.Timezone = stored.Timezone
+		}
+		resolved.QuietHoursStart = stored.QuietHoursStart
+		resolved.QuietHoursEnd = stored.QuietHoursEnd
+		for _, preference := range stored.Preferences {
+			saved[preference.Category] = preference
+		}
+	}
+
+	for _, info := range structs.NotificationCategories {
+		preference := structs.NotificationPreference{
+			Category: info.Category,
+			Email:    notificationCategoryOffers(info, structs.NotificationChannelEmail),
+			Push:     notificationCategoryOffers(info, structs.NotificationChannelPush),
+		}
+		if existing, ok := saved[info.Category]; ok && !info.Required {
+			preference.Email = preference.Email && existing.Email
+			preference.Push = preference.Push && existing.Push
+		}
+		resolved.Preferences = append(resolved.Preferences, preference)
+	}
+
+	return resolved
+}
+
+func notificationPreferenceFor(settings *structs.NotificationSettings, info structs.NotificationCategoryInfo) structs.NotificationPreference {
+	for _, preference := range settings.Preferences {
+		if preference.Category == info.Category {
+			return preference
+		}
+	}
+	return structs.NotificationPreference{
+		Category: info.Category,
+		Email:    notificationCategoryOffers(info, structs.NotificationChannelEmail),
+		Push:     notificationCategoryOffers(info, structs.NotificationChannelPush),
+	}
+}
+
+func notificationCategoryOffers(info structs.NotificationCategoryInfo, channel string) bool {
+	for _, offered := range info.Channels {
+		if offered == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// parseQuietHoursClock turns "HH:MM" into minutes after midnight.
+func parseQuietHoursClock(value string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, false
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, false
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+// quietHoursEnd returns when the quiet window now falls in ends, and false
+// when now is outside it. The window may wrap past midnight (22:00 to 07:00).
+func quietHoursEnd(settings *structs.NotificationSettings, now time.Time) (time.Time, bool) {
+	start, ok := parseQuietHoursClock(settings.QuietHoursStart)
+	if !ok {
+		return time.Time{}, false
+	}
+	end, ok := parseQuietHoursClock(settings.QuietHoursEnd)
+	if !ok || start == end {
+		return time.Time{}, false
+	}
+
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		loc, err = time.LoadLocation(defaultNotificationTimezone)
+		if err != nil {
+			loc = time.UTC
+		}
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	quiet := minute >= start || minute < end
+	if start < end {
+		quiet = minute >= start && minute < end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	ends := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, loc)
+	if !ends.After(now) {
+		ends = time.Date(local.Year(), local.Month(), local.Day()+1, end/60, end%60, 0, 0, loc)
+	}
+	return ends, true
+}
+
+// applyNotificationSettingsUpdate validates update against the category list
+// and merges it into settings.
+func applyNotificationSettingsUpdate(settings *structs.NotificationSettings, update *structs.NotificationSettingsUpdate) error {
+	if update.Locale != nil {
+		locale := strings.ToLower(strings.TrimSpace(*update.Locale))
+		if locale == "" {
+			locale = emails.DefaultLocale
+		}
+		if !emails.SupportedLocale(locale) {
+			return fmt.Errorf("unsupported locale %s", locale)
+		}
+		settings.Locale = locale
+	}
+	if update.Timezone != nil {
+		timezone := strings.TrimSpace(*update.Timezone)
+		if timezone == "" {
+			timezone = defaultNotificationTimezone
+		}
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("unknown timezone %s", timezone)
+		}
+		settings.Timezone = timezone
+	}
+	if update.QuietHoursStart != nil {
+		value := strings.TrimSpace(*update.QuietHoursStart)
+		if _, ok := parseQuietHoursClock(value); value != "" && !ok {
+			return fmt.Errorf("quiet_hours_start must be HH:MM")
+		}
+		settings.QuietHoursStart = value
+	}
+	if update.QuietHoursEnd != nil {
+		value := strings.TrimSpace(*update.QuietHoursEnd)
+		if _, ok := parseQuietHoursClock(value); value != "" && !ok {
+			return fmt.Errorf("quiet_hours_end must be HH:MM")
+		}
+		settings.QuietHoursEnd = value
+	}
+
+	for _, preference := range update.Preferences {
+		info, ok := structs.NotificationCategoryByName(preference.Category)
+		if !ok {
+			return fmt.Errorf("unknown notification category %s", preference.Category)
+		}
+		if preference.Email && !notificationCategoryOffers(info, structs.NotificationChannelEmail) {
+			return fmt.Errorf("%s notifications are not sent by email", info.Category)
+		}
+		if preference.Push && !notificationCategoryOffers(info, structs.NotificationChannelPush) {
+			return fmt.Errorf("%s notifications are not sent by push", info.Category)
+		}
+		if info.Required && (preference.Email != notificationCategoryOffers(info, structs.NotificationChannelEmail) ||
+			preference.Push != notificationCategoryOffers(info, structs.NotificationChannelPush)) {
+			return fmt.Errorf("%s notifications cannot be turned off", info.Category)
+		}
+
+		for i := range settings.Preferences {
+			if settings.Preferences[i].Category == info.Category {
+				settings.Preferences[i].Email = preference.Email
+				settings.Preferences[i].Push = preference.Push
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *AppService) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	stored, err := a.db.GetNotificationSettings(r.Context(), *userDid)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error getting notification settings for user %s: %s", *userDid, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res, err := json.Marshal(resolveNotificationSettings(stored))
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error marshalling notification settings for user %s: %s", *userDid, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(res)
+}
+
+func (a *AppService) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var update structs.NotificationSettingsUpdate
+	if err := json.Unmarshal(body, &update); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	stored, err := a.db.GetNotificationSettings(r.Context(), *userDid)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error getting notification settings for user %s: %s", *userDid, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	settings := resolveNotificationSettings(stored)
+	if err := applyNotificationSettingsUpdate(settings, &update); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if err := a.db.SaveNotificationSettings(r.Context(), *userDid, settings); err != nil {
+		a.logger.Errorf(r.Context(), "error saving notification settings for user %s: %s", *userDid, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	res, err := json.Marshal(settings)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error marshalling notification settings for user %s: %s", *userDid, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(res)
+}
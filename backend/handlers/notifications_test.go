@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestResolveNotificationSettingsDefaultsAndRequired(t *testing.T) {
+	settings := resolveNotificationSettings(&structs.NotificationSettings{
+		Preferences: []structs.NotificationPreference{
+			{Category: structs.NotificationCategoryTransactions, Email: false, Push: true},
+			{Category: structs.NotificationCategoryW9, Email: false},
+		},
+	})
+
+	if settings.Timezone != defaultNotificationTimezone || settings.Locale != "en" {
+		t.Fatalf("got timezone %q and locale %q; want defaults", settings.Timezone, settings.Locale)
+	}
+	if len(settings.Preferences) != len(structs.NotificationCategories) {
+		t.Fatalf("got %d preferences; want one per category", len(settings.Preferences))
+	}
+
+	transactions, _ := structs.NotificationCategoryByName(structs.NotificationCategoryTransactions)
+	if got := notificationPreferenceFor(settings, transactions); got.Email || !got.Push {
+		t.Fatalf("got transactions %+v; want push only", got)
+	}
+	w9, _ := structs.NotificationCategoryByName(structs.NotificationCategoryW9)
+	if got := notificationPreferenceFor(settings, w9); !got.Email {
+		t.Fatalf("got w9 %+v; want required email kept on", got)
+	}
+	steps, _ := structs.NotificationCategoryByName(structs.NotificationCategoryWorkflowSteps)
+	if got := notificationPreferenceFor(settings, steps); !got.Email || got.Push {
+		t.Fatalf("got workflow steps %+v; want email on and no push", got)
+	}
+}
+
+func TestApplyNotificationSettingsUpdateValidates(t *testing.T) {
+	locale := "ES"
+	timezone := "America/New_York"
+	start := "22:00"
+	settings := resolveNotificationSettings(nil)
+	err := applyNotificationSettingsUpdate(settings, &structs.NotificationSettingsUpdate{
+		Locale:          &locale,
+		Timezone:        &timezone,
+		QuietHoursStart: &start,
+		Preferences: []structs.NotificationPreference{
+			{Category: structs.NotificationCategoryAffiliate},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	affiliate, _ := structs.NotificationCategoryByName(structs.NotificationCategoryAffiliate)
+	if settings.Locale != "es" || settings.Timezone != timezone || settings.QuietHoursStart != start || notificationPreferenceFor(settings, affiliate).Email {
+		t.Fatalf("update not applied: %+v", settings)
+	}
+
+	badLocale := "fr"
+	badTimezone := "Mars/Olympus"
+	badClock := "7pm"
+	cases := []*structs.NotificationSettingsUpdate{
+		{Locale: &badLocale},
+		{Timezone: &badTimezone},
+		{QuietHoursEnd: &badClock},
+		{Preferences: []structs.NotificationPreference{{Category: "unknown", Email: true}}},
+		{Preferences: []structs.NotificationPreference{{Category: structs.NotificationCategoryAccount}}},
+		{Preferences: []structs.NotificationPreference{{Category: structs.NotificationCategoryAdmin, Email: true, Push: true}}},
+	}
+	for i, update := range cases {
+		if err := applyNotificationSettingsUpdate(resolveNotificationSettings(nil), update); err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		}
+	}
+}
+
+func TestQuietHoursEnd(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+	overnight := &structs.NotificationSettings{Timezone: "America/Los_Angeles", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}
+	daytime := &structs.NotificationSettings{Timezone: "America/Los_Angeles", QuietHoursStart: "09:00", QuietHoursEnd: "17:00"}
+
+	cases := []struct {
+		settings *structs.NotificationSettings
+		at       time.Time
+		want     bool
+		ends     time.Time
+	}{
+		{overnight, time.Date(2026, 3, 2, 23, 30, 0, 0, loc), true, time.Date(2026, 3, 3, 7, 0, 0, 0, loc)},
+		{overnight, time.Date(2026, 3, 2, 6, 59, 0, 0, loc), true, time.Date(2026, 3, 2, 7, 0, 0, 0, loc)},
+		{overnight, time.Date(2026, 3, 2, 7, 0, 0, 0, loc), false, time.Time{}},
+		{overnight, time.Date(2026, 3, 2, 12, 0, 0, 0, loc), false, time.Time{}},
+		// The window spans the spring-forward night, so it ends at 07:00 PDT.
+		{overnight, time.Date(2026, 3, 7, 23, 0, 0, 0, loc), true, time.Date(2026, 3, 8, 7, 0, 0, 0, loc)},
+		{daytime, time.Date(2026, 3, 2, 12, 0, 0, 0, loc), true, time.Date(2026, 3, 2, 17, 0, 0, 0, loc)},
+		{daytime, time.Date(2026, 3, 2, 20, 0, 0, 0, loc), false, time.Time{}},
+		{&structs.NotificationSettings{QuietHoursStart: "22:00"}, time.Date(2026, 3, 2, 23, 0, 0, 0, loc), false, time.Time{}},
+	}
+	for i, c := range cases {
+		// Pass UTC so the check has to convert into the user's timezone.
+		ends, got := quietHoursEnd(c.settings, c.at.UTC())
+		if got != c.want {
+			t.Fatalf("case %d: got %t; want %t", i, got, c.want)
+		}
+		if !ends.Equal(c.ends) {
+			t.Fatalf("case %d: window ends %s; want %s", i, ends, c.ends)
+		}
+	}
+}
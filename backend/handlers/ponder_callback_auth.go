@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ponder signs callbacks with HMAC-SHA256 over "<timestamp>.<nonce>.<body>".
+// Transfer hooks use the secret generated for the hook in createPonderHook;
+// W9 callbacks use W9_HOOK_SECRET.
+const (
+	ponderCallbackHookIDHeader    = "X-SFLUV-Hook-Id"
+	ponderCallbackTimestampHeader = "X-SFLUV-Timestamp"
+	ponderCallbackNonceHeader     = "X-SFLUV-Nonce"
+	ponderCallbackSignatureHeader = "X-SFLUV-Signature"
+
+	ponderCallbackMaxSkew     = 5 * time.Minute
+	ponderCallbackMaxNonceLen = 128
+
+	ponderCallbackSourceTransfer = "transfer"
+	ponderCallbackSourceW9       = "w9"
+)
+
+var errPonderCallbackUnsigned = errors.New("ponder callback is not signed")
+
+func newPonderHookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// allowUnsignedPonderCallbacks keeps hooks created before signing working
+// during rollout, as long as they carry the Ponder admin key.
+func allowUnsignedPonderCallbacks() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("PONDER_ALLOW_UNSIGNED_CALLBACKS")), "true")
+}
+
+func ponderCallbackSignature(secret string, timestamp string, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(nonce))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyPonderCallback checks the signature headers of a callback and returns
+// its nonce. It does not check whether the nonce was used before.
+func verifyPonderCallback(secret string, header http.Header, body []byte, now time.Time) (string, error) {
+	signature := strings.TrimSpace(header.Get(ponderCallbackSignatureHeader))
+	if signature == "" {
+		return "", errPonderCallbackUnsigned
+	}
+	if secret == "" {
+		return "", fmt.Errorf("no signing secret for ponder callback")
+	}
+
+	timestamp := strings.TrimSpace(header.Get(ponderCallbackTimestampHeader))
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid ponder callback timestamp %q", timestamp)
+	}
+	skew := now.Sub(time.Unix(sentAt, 0))
+	if skew > ponderCallbackMaxSkew || skew < -ponderCallbackMaxSkew {
+		return "", fmt.Errorf("ponder callback timestamp %d outside of allowed window", sentAt)
+	}
+
+	nonce := strings.TrimSpace(header.Get(ponderCallbackNonceHeader))
+	if nonce == "" || len(nonce) > ponderCallbackMaxNonceLen {
+		return "", fmt.Errorf("invalid ponder callback nonce")
+	}
+
+	expected := ponderCallbackSignature(secret, timestamp, nonce, body)
+	if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+		return "", fmt.Errorf("ponder callback signature mismatch")
+	}
+
+	return nonce, nil
+}
+
+// authenticatePonderCallback verifies a signed callback and burns its nonce.
+// Unsigned callbacks pass only for endpoints with no secret on file, and only
+// while PONDER_ALLOW_UNSIGNED_CALLBACKS is set; the caller has already
+// checked the admin key.
+func (a *AppService) authenticatePonderCallback(r *http.Request, body []byte, secret string) bool {
+	nonce, err := verifyPonderCallback(secret, r.Header, body, time.Now())
+	if err == errPonderCallbackUnsigned {
+		if secret != "" {
+			a.logger.Warnf(r.Context(), "rejected unsigned ponder callback to %s for keyed hook", r.URL.Path)
+			return false
+		}
+		return allowUnsignedPonderCallbacks()
+	}
+	if err != nil {
+		a.logger.Warnf(r.Context(), "rejected ponder callback to %s: %s", r.URL.Path, err)
+		return false
+	}
+
+	fresh, err := a.db.ClaimPonderCallbackNonce(r.Context(), nonce, int64(2*ponderCallbackMaxSkew/time.Second))
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error claiming ponder callback nonce: %s", err)
+		return false
+	}
+	if !fresh {
+		a.logger.Warnf(r.Context(), "rejected replayed ponder callback to %s", r.URL.Path)
+		return false
+	}
+
+	return true
+}
+
+// ponderHookSecret looks up the signing secret for the hook named in a
+// transfer callback's headers.
+func (a *AppService) ponderHookSecret(r *http.Request) (string, error) {
+	raw := strings.TrimSpace(r.Header.Get(ponderCallbackHookIDHeader))
+	if raw == "" {
+		return "", nil
+	}
+	hookID, err := strconv.Atoi(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid ponder hook id %q", raw)
+	}
+	return a.db.GetPonderHookSecret(r.Context(), hookID)
+}
+
+// claimPonderCallbackEvent reports whether a transfer log should be handled
+// by source. Callbacks without a log index predate dedupe and are always
+// handled. The returned release func undoes the claim for callbacks that fail
+// before doing anything.
+func (a *AppService) claimPonderCallbackEvent(ctx context.Context, source string, chainID int64, txHash string, logIndex *int) (bool, func(), error) {
+	noop := func() {}
+	if logIndex == nil || strings.TrimSpace(txHash) == "" {
+		return true, noop, nil
+	}
+
+	fresh, err := a.db.ClaimPonderCallbackEvent(ctx, source, chainID, txHash, *logIndex)
+	if err != nil || !fresh {
+		return false, noop, err
+	}
+
+	release := func() {
+		if err := a.db.ReleasePonderCallbackEvent(context.Background(), source, chainID, txHash, *logIndex); err != nil {
+			a.logger.Errorf(ctx, "%s", err)
+		}
+	}
+	return true, release, nil
+}
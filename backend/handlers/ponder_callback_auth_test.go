@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signedPonderCallbackHeader(secret string, sentAt time.Time, nonce string, body []byte) http.Header {
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	header := http.Header{}
+	header.Set(ponderCallbackTimestampHeader, timestamp)
+	header.Set(ponderCallbackNonceHeader, nonce)
+	header.Set(ponderCallbackSignatureHeader, ponderCallbackSignature(secret, timestamp, nonce, body))
+	return header
+}
+
+func TestVerifyPonderCallbackAcceptsValidSignature(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"hash":"0x01","log_index":3}`)
+	header := signedPonderCallbackHeader("secret", now.Add(-time.Minute), "nonce-1", body)
+
+	nonce, err := verifyPonderCallback("secret", header, body, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if nonce != "nonce-1" {
+		t.Fatalf("got nonce %q; want nonce-1", nonce)
+	}
+}
+
+func TestVerifyPonderCallbackRejectsTampering(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"hash":"0x01","amount":"10"}`)
+	header := signedPonderCallbackHeader("secret", now, "nonce-1", body)
+
+	cases := []struct {
+		name   string
+		secret string
+		header func() http.Header
+		body   []byte
+	}{
+		{"body", "secret", func() http.Header { return header.Clone() }, []byte(`{"hash":"0x01","amount":"99"}`)},
+		{"secret", "other", func() http.Header { return header.Clone() }, body},
+		{"nonce", "secret", func() http.Header {
+			h := header.Clone()
+			h.Set(ponderCallbackNonceHeader, "nonce-2")
+			return h
+		}, body},
+		{"stale", "secret", func() http.Header {
+			return signedPonderCallbackHeader("secret", now.Add(-ponderCallbackMaxSkew-time.Second), "nonce-1", body)
+		}, body},
+		{"future", "secret", func() http.Header {
+			return signedPonderCallbackHeader("secret", now.Add(ponderCallbackMaxSkew+time.Second), "nonce-1", body)
+		}, body},
+		{"no secret", "", func() http.Header { return header.Clone() }, body},
+	}
+	for _, tc := range cases {
+		if _, err := verifyPonderCallback(tc.secret, tc.header(), tc.body, now); err == nil {
+			t.Errorf("%s: expected verification to fail", tc.name)
+		}
+	}
+}
+
+func TestVerifyPonderCallbackReportsUnsigned(t *testing.T) {
+	_, err := verifyPonderCallback("secret", http.Header{}, []byte(`{}`), time.Now())
+	if err != errPonderCallbackUnsigned {
+		t.Fatalf("got %v; want errPonderCallbackUnsigned", err)
+	}
+}
+
+func TestAuthenticatePonderCallbackRejectsUnsignedForKeyedHook(t *testing.T) {
+	t.Setenv("PONDER_ALLOW_UNSIGNED_CALLBACKS", "true")
+	a := &AppService{}
+	body := []byte(`{"hash":"0x01"}`)
+
+	r := httptest.NewRequest(http.MethodPost, "/ponder/callback", strings.NewReader(string(body)))
+	if a.authenticatePonderCallback(r, body, "secret") {
+		t.Fatalf("unsigned callback accepted for a hook with a secret")
+	}
+	if !a.authenticatePonderCallback(r, body, "") {
+		t.Fatalf("unsigned callback rejected for a legacy hook while unsigned callbacks are allowed")
+	}
+
+	t.Setenv("PONDER_ALLOW_UNSIGNED_CALLBACKS", "false")
+	if a.authenticatePonderCallback(r, body, "") {
+		t.Fatalf("unsigned callback accepted while unsigned callbacks are disallowed")
+	}
+}
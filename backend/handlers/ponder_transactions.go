@@ -111,7 +111,7 @@ func (p *PonderService) GetTransactionHistory(w http.ResponseWriter, r *http.Req
 
 	txs, err := p.db.GetTransactionsPaginated(r.Context(), address, iPage, iCount, descending)
 	if err != nil {
-		p.logger.Logf("error getting paginated transactions: %s", err)
+		p.logger.Errorf(r.Context(), "error getting paginated transactions: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -131,7 +131,7 @@ func (p *PonderService) GetTransactionHistory(w http.ResponseWriter, r *http.Req
 	if txs != nil && len(txs.Transactions) > 0 && userDid != nil {
 		ownedAddresses, addressErr := p.appDB.GetOwnedWalletAddressSet(r.Context(), *userDid)
 		if addressErr != nil {
-			p.logger.Logf("error loading wallet addresses for memo auth user %s: %s", *userDid, addressErr)
+			p.logger.Errorf(r.Context(), "error loading wallet addresses for memo auth user %s: %s", *userDid, addressErr)
 		} else if len(ownedAddresses) > 0 {
 			authorizedHashes := make([]string, 0, len(txs.Transactions))
 			for _, tx := range txs.Transactions {
@@ -156,7 +156,7 @@ func (p *PonderService) GetTransactionHistory(w http.ResponseWriter, r *http.Req
 
 			memosByTx, memoErr := p.transactionMemosByChainHash(r.Context(), authorizedHashes, txs.Transactions)
 			if memoErr != nil {
-				p.logger.Logf("error loading authorized memos for user %s address %s: %s", *userDid, address, memoErr)
+				p.logger.Errorf(r.Context(), "error loading authorized memos for user %s address %s: %s", *userDid, address, memoErr)
 			} else {
 				for _, tx := range txs.Transactions {
 					if tx == nil {
@@ -173,7 +173,7 @@ func (p *PonderService) GetTransactionHistory(w http.ResponseWriter, r *http.Req
 
 	bytes, err := json.Marshal(txs)
 	if err != nil {
-		p.logger.Logf("error marshalling transactions page into response: %s", err)
+		p.logger.Errorf(r.Context(), "error marshalling transactions page into response: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -215,7 +215,7 @@ func (p *PonderService) UpsertTransactionMemo(w http.ResponseWriter, r *http.Req
 
 	txParties, txErr := p.db.GetTransactionPartiesByHash(r.Context(), txHash, chainID)
 	if txErr != nil {
-		p.logger.Logf("error looking up transaction %s for memo authorization: %s", txHash, txErr)
+		p.logger.Errorf(r.Context(), "error looking up transaction %s for memo authorization: %s", txHash, txErr)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -225,7 +225,7 @@ func (p *PonderService) UpsertTransactionMemo(w http.ResponseWriter, r *http.Req
 	if txParties != nil {
 		authorized, authErr := p.appDB.UserOwnsAnyWalletAddress(r.Context(), *userDid, []string{txParties.From, txParties.To})
 		if authErr != nil {
-			p.logger.Logf("error checking memo authorization for tx %s user %s: %s", txHash, *userDid, authErr)
+			p.logger.Errorf(r.Context(), "error checking memo authorization for tx %s user %s: %s", txHash, *userDid, authErr)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
@@ -243,7 +243,7 @@ func (p *PonderService) UpsertTransactionMemo(w http.ResponseWriter, r *http.Req
 
 	err := p.appDB.UpsertTransactionMemo(r.Context(), txHash, chainID, memo, *userDid)
 	if err != nil {
-		p.logger.Logf("error upserting transaction memo for tx %s user %s: %s", txHash, *userDid, err)
+		p.logger.Errorf(r.Context(), "error upserting transaction memo for tx %s user %s: %s", txHash, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -275,7 +275,7 @@ func (p *PonderService) GetBalanceAtTimestamp(w http.ResponseWriter, r *http.Req
 
 	balance, err := p.db.GetBalanceAtTimestamp(r.Context(), address, parsedTimestamp)
 	if err != nil {
-		p.logger.Logf("error getting balance at timestamp: %s", err)
+		p.logger.Errorf(r.Context(), "error getting balance at timestamp: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -289,7 +289,7 @@ func (p *PonderService) GetBalanceAtTimestamp(w http.ResponseWriter, r *http.Req
 
 	bytes, err := json.Marshal(resp)
 	if err != nil {
-		p.logger.Logf("error marshalling balance response: %s", err)
+		p.logger.Errorf(r.Context(), "error marshalling balance response: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
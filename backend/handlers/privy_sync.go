@@ -160,7 +160,7 @@ func (a *AppService) SyncPrivyLinkedEmailsForUser(ctx context.Context, userDid s
 	verifiedCount := 0
 	for _, email := range emails {
 		if _, err := a.db.UpsertVerifiedUserEmail(ctx, userDid, email); err != nil {
-			a.logger.Logf("error upserting Privy-linked verified email for user %s and email %s: %s", userDid, email, err)
+			a.logger.Errorf(ctx, "error upserting Privy-linked verified email for user %s and email %s: %s", userDid, email, err)
 			continue
 		}
 		verifiedCount++
@@ -172,7 +172,7 @@ func (a *AppService) SyncPrivyLinkedEmailsForUser(ctx context.Context, userDid s
 func (a *AppService) SyncPrivyLinkedEmailsForAllUsers(ctx context.Context) error {
 	_, _, ok := a.privyManagementCredentials()
 	if !ok {
-		a.logger.Logf("skipping Privy linked-email sync on startup: PRIVY_APP_ID or PRIVY_APP_SECRET not configured")
+		a.logger.Warnf(ctx, "skipping Privy linked-email sync on startup: PRIVY_APP_ID or PRIVY_APP_SECRET not configured")
 		return nil
 	}
 
@@ -187,13 +187,13 @@ func (a *AppService) SyncPrivyLinkedEmailsForAllUsers(ctx context.Context) error
 		count, err := a.SyncPrivyLinkedEmailsForUser(ctx, userDid)
 		if err != nil {
 			totalFailures++
-			a.logger.Logf("error syncing Privy linked emails for user %s: %s", userDid, err)
+			a.logger.Errorf(ctx, "error syncing Privy linked emails for user %s: %s", userDid, err)
 			continue
 		}
 		totalVerified += count
 	}
 
-	a.logger.Logf(
+	a.logger.Infof(ctx,
 		"completed Privy linked-email sync on startup: users=%d verified_emails_upserted=%d failures=%d",
 		len(userIds),
 		totalVerified,
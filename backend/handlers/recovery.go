@@ -2,7 +2,7 @@ package handlers
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"math/big"
 	"net/http"
 	"os"
@@ -34,7 +34,7 @@ func (s *BotService) recoveryClaimExpiry() time.Duration {
 // refreshed) record. Verification/transient errors leave the claim untouched
 // rather than blocking the request.
 func (s *BotService) reconcileRecoveryBalance(ctx context.Context, rb *structs.RecoveryBalance) *structs.RecoveryBalance {
-	if rb == nil || s.bot == nil {
+	if rb == nil {
 		return rb
 	}
 	if rb.ClaimStatus != structs.RecoveryStatusClaimed ||
@@ -51,9 +51,16 @@ func (s *BotService) reconcileRecoveryBalance(ctx context.Context, rb *structs.R
 	if !ok {
 		return rb
 	}
-	res, err := s.bot.VerifyTransferBaseUnits(ctx, rb.ClaimTxHash, rb.ClaimedBy, amount)
+	// Verify on the chain the payout was sent on; if that chain no longer has
+	// a bot, leave the claim alone rather than reset a payout we can't see.
+	payoutBot, ok := s.botForChain(rb.ClaimTxChainID)
+	if !ok {
+		slog.WarnContext(ctx, "recovery reconcile: no bot for chain to verify claim tx", "chain_id", rb.ClaimTxChainID, "tx_hash", rb.ClaimTxHash, "address", rb.Address)
+		return rb
+	}
+	res, err := payoutBot.VerifyTransferBaseUnits(ctx, rb.ClaimTxHash, rb.ClaimedBy, amount)
 	if err != nil {
-		fmt.Printf("recovery reconcile: error verifying tx %s for %s: %s\n", rb.ClaimTxHash, rb.Address, err)
+		slog.ErrorContext(ctx, "recovery reconcile: error verifying claim tx", "tx_hash", rb.ClaimTxHash, "address", rb.Address, "error", err)
 		return rb
 	}
 	if res != nil && res.Found {
@@ -63,14 +70,14 @@ func (s *BotService) reconcileRecoveryBalance(ctx context.Context, rb *structs.R
 
 	reset, err := s.db.ResetRecoveryClaim(ctx, rb.Address, rb.ClaimTxHash)
 	if err != nil {
-		fmt.Printf("recovery reconcile: error resetting %s: %s\n", rb.Address, err)
+		slog.ErrorContext(ctx, "recovery reconcile: error resetting claim", "address", rb.Address, "error", err)
 		return rb
 	}
 	if !reset {
 		// A concurrent claim moved the row; keep what we have.
 		return rb
 	}
-	fmt.Printf("recovery reconcile: reset %s to unclaimed (tx %s never confirmed)\n", rb.Address, rb.ClaimTxHash)
+	slog.InfoContext(ctx, "recovery reconcile: reset claim to unclaimed; tx never confirmed", "address", rb.Address, "tx_hash", rb.ClaimTxHash)
 
 	fresh, err := s.db.GetRecoveryBalance(ctx, rb.Address)
 	if err != nil || fresh == nil {
@@ -236,11 +243,12 @@ func (s *BotService) RecoveryClaim(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	payoutBot := s.payoutBot(bot.PayoutTargetRecovery)
 	amount, ok := new(big.Int).SetString(amountStr, 10)
 	if !ok || amount.Sign() <= 0 {
 		// Nothing to send; close the record out so it cannot be re-reserved.
 		closeCtx, cancelClose := context.WithTimeout(context.Background(), 8*time.Second)
-		_ = s.db.CompleteRecoveryClaim(closeCtx, account, "", s.chainID())
+		_ = s.db.CompleteRecoveryClaim(closeCtx, account, "", s.payoutChainID(payoutBot))
 		cancelClose()
 		writeJSON(w, http.StatusOK, structs.RecoveryClaimResponse{
 			Claimed: true, Amount: amountStr, Recipient: recipient, Message: "no positive balance to recover",
@@ -249,7 +257,7 @@ func (s *BotService) RecoveryClaim(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Send the exact base-unit balance from the faucet.
-	txHash, sendErr := s.bot.SubmitTransferBaseUnits(amount, recipient)
+	txHash, sendErr := payoutBot.SubmitTransferBaseUnits(r.Context(), amount, recipient)
 	if sendErr != nil {
 		// Pre-broadcast failures are revertable; return the balance to unclaimed
 		// so the user can retry. A non-revertable error may have broadcast, so the
@@ -259,7 +267,7 @@ func (s *BotService) RecoveryClaim(w http.ResponseWriter, r *http.Request) {
 			_ = s.db.RevertRecoveryClaim(revertCtx, account)
 			cancelRevert()
 		}
-		fmt.Printf("error sending recovery payout for account %s to %s: %s\n", account, recipient, sendErr)
+		slog.ErrorContext(r.Context(), "error sending recovery payout", "account", account, "recipient", recipient, "error", sendErr)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -267,10 +275,10 @@ func (s *BotService) RecoveryClaim(w http.ResponseWriter, r *http.Request) {
 	// Persist the claim + tx hash. The payout already broadcast, so never revert
 	// here even if bookkeeping fails — the stored hash enables reconciliation.
 	completeCtx, cancelComplete := context.WithTimeout(context.Background(), 8*time.Second)
-	completeErr := s.db.CompleteRecoveryClaim(completeCtx, account, txHash, s.chainID())
+	completeErr := s.db.CompleteRecoveryClaim(completeCtx, account, txHash, s.payoutChainID(payoutBot))
 	cancelComplete()
 	if completeErr != nil {
-		fmt.Printf("recovery payout for %s sent (tx %s) but failed to mark claimed: %s\n", account, txHash, completeErr)
+		slog.ErrorContext(r.Context(), "recovery payout sent but failed to mark claimed", "account", account, "tx_hash", txHash, "error", completeErr)
 	}
 
 	writeJSON(w, http.StatusOK, structs.RecoveryClaimResponse{
@@ -43,26 +43,26 @@ func NewRedeemerService(appDb *db.AppDB, log *logger.LogCloser, config *clientco
 
 	inProductionRaw := strings.TrimSpace(os.Getenv("IN_PRODUCTION"))
 	if inProductionRaw == "" {
-		service.logf("redeemer auto-grant disabled: IN_PRODUCTION is not set (defaults to false)")
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: IN_PRODUCTION is not set (defaults to false)")
 		return service
 	}
 	inProduction, err := strconv.ParseBool(inProductionRaw)
 	if err != nil {
-		service.logf("redeemer auto-grant disabled: invalid IN_PRODUCTION value %q", inProductionRaw)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: invalid IN_PRODUCTION value %q", inProductionRaw)
 		return service
 	}
 	if !inProduction {
-		service.logf("redeemer auto-grant disabled: IN_PRODUCTION is false")
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: IN_PRODUCTION is false")
 		return service
 	}
 
 	if config == nil {
-		service.logf("redeemer auto-grant disabled: client config is not loaded")
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: client config is not loaded")
 		return service
 	}
 	primaryToken, err := config.PrimaryToken()
 	if err != nil {
-		service.logf("redeemer auto-grant disabled: invalid client config: %s", err)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: invalid client config: %s", err)
 		return service
 	}
 
@@ -72,35 +72,35 @@ func NewRedeemerService(appDb *db.AppDB, log *logger.LogCloser, config *clientco
 	redeemerAdminAddress := strings.TrimSpace(os.Getenv("REDEEMER_ADMIN_ADDRESS"))
 
 	if rpcURL == "" || tokenID == "" || redeemerAdminKey == "" || redeemerAdminAddress == "" {
-		service.logf("redeemer auto-grant disabled: missing primary RPC/token config or one of REDEEMER_ADMIN_KEY, REDEEMER_ADMIN_ADDRESS")
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: missing primary RPC/token config or one of REDEEMER_ADMIN_KEY, REDEEMER_ADMIN_ADDRESS")
 		return service
 	}
 
 	if !common.IsHexAddress(tokenID) {
-		service.logf("redeemer auto-grant disabled: invalid configured token address %q", tokenID)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: invalid configured token address %q", tokenID)
 		return service
 	}
 	if !common.IsHexAddress(redeemerAdminAddress) {
-		service.logf("redeemer auto-grant disabled: invalid REDEEMER_ADMIN_ADDRESS %q", redeemerAdminAddress)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: invalid REDEEMER_ADMIN_ADDRESS %q", redeemerAdminAddress)
 		return service
 	}
 
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
-		service.logf("redeemer auto-grant disabled: error connecting RPC: %s", err)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: error connecting RPC: %s", err)
 		return service
 	}
 
 	privateKey, err := crypto.HexToECDSA(redeemerAdminKey)
 	if err != nil {
-		service.logf("redeemer auto-grant disabled: invalid REDEEMER_ADMIN_KEY: %s", err)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: invalid REDEEMER_ADMIN_KEY: %s", err)
 		return service
 	}
 
 	fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
 	configuredFrom := common.HexToAddress(redeemerAdminAddress)
 	if strings.ToLower(fromAddress.Hex()) != strings.ToLower(configuredFrom.Hex()) {
-		service.logf(
+		service.log.Warnf(context.Background(),
 			"redeemer auto-grant disabled: REDEEMER_ADMIN_KEY address %s does not match REDEEMER_ADMIN_ADDRESS %s",
 			fromAddress.Hex(),
 			configuredFrom.Hex(),
@@ -110,19 +110,19 @@ func NewRedeemerService(appDb *db.AppDB, log *logger.LogCloser, config *clientco
 
 	contract, err := abi.NewSFLUVv2(common.HexToAddress(tokenID), client)
 	if err != nil {
-		service.logf("redeemer auto-grant disabled: failed to initialize SFLUV contract: %s", err)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: failed to initialize SFLUV contract: %s", err)
 		return service
 	}
 
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
-		service.logf("redeemer auto-grant disabled: failed to read chain id: %s", err)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: failed to read chain id: %s", err)
 		return service
 	}
 
 	redeemerRole, err := contract.REDEEMERROLE(&bind.CallOpts{Context: context.Background()})
 	if err != nil {
-		service.logf("redeemer auto-grant disabled: failed to read REDEEMER_ROLE: %s", err)
+		service.log.Warnf(context.Background(), "redeemer auto-grant disabled: failed to read REDEEMER_ROLE: %s", err)
 		return service
 	}
 
@@ -134,7 +134,7 @@ func NewRedeemerService(appDb *db.AppDB, log *logger.LogCloser, config *clientco
 	service.redeemerRole = redeemerRole
 	service.enabled = true
 
-	service.logf("redeemer auto-grant enabled with admin wallet %s", service.fromAddress.Hex())
+	service.log.Infof(context.Background(), "redeemer auto-grant enabled with admin wallet %s", service.fromAddress.Hex())
 	return service
 }
 
@@ -157,7 +157,7 @@ func (r *RedeemerService) SyncApprovedMerchants(ctx context.Context) error {
 
 	for _, ownerID := range ownerIDs {
 		if err := r.EnsureMerchantHasRedeemerWallet(ctx, ownerID); err != nil {
-			r.logf("error ensuring redeemer wallet for user %s: %s", ownerID, err)
+			r.log.Errorf(ctx, "error ensuring redeemer wallet for user %s: %s", ownerID, err)
 		}
 	}
 
@@ -179,7 +179,7 @@ func (r *RedeemerService) SyncAdmins(ctx context.Context) error {
 
 	for _, adminID := range adminIDs {
 		if err := r.EnsureMerchantHasRedeemerWallet(ctx, adminID); err != nil {
-			r.logf("error ensuring redeemer wallet for admin %s: %s", adminID, err)
+			r.log.Errorf(ctx, "error ensuring redeemer wallet for admin %s: %s", adminID, err)
 		}
 	}
 
@@ -259,13 +259,6 @@ func (r *RedeemerService) grantRedeemerRole(ctx context.Context, walletAddress c
 		return fmt.Errorf("grantRole transaction reverted: %s", tx.Hash().Hex())
 	}
 
-	r.logf("granted REDEEMER_ROLE to %s in tx %s", walletAddress.Hex(), tx.Hash().Hex())
+	r.log.Infof(ctx, "granted REDEEMER_ROLE to %s in tx %s", walletAddress.Hex(), tx.Hash().Hex())
 	return nil
 }
-
-func (r *RedeemerService) logf(format string, args ...any) {
-	if r.log == nil {
-		return
-	}
-	r.log.Logf(format, args...)
-}
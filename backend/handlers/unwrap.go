@@ -60,7 +60,7 @@ func (a *AppService) CheckUnwrapEligibility(w http.ResponseWriter, r *http.Reque
 	}
 	minimumFollowupAmountWei, err := minimumFollowupUnwrapAmountWei()
 	if err != nil {
-		a.logger.Logf("error loading unwrap threshold: %s", err)
+		a.logger.Errorf(r.Context(), "error loading unwrap threshold: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -71,7 +71,7 @@ func (a *AppService) CheckUnwrapEligibility(w http.ResponseWriter, r *http.Reque
 			w.WriteHeader(http.StatusForbidden)
 			return
 		}
-		a.logger.Logf("error loading wallet for unwrap eligibility user=%s wallet=%s: %s", *userDid, req.WalletAddress, err)
+		a.logger.Errorf(r.Context(), "error loading wallet for unwrap eligibility user=%s wallet=%s: %s", *userDid, req.WalletAddress, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -152,7 +152,7 @@ func (a *AppService) RecordUnwrap(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusForbidden)
 			return
 		}
-		a.logger.Logf("error loading wallet for unwrap record user=%s wallet=%s: %s", *userDid, req.WalletAddress, err)
+		a.logger.Errorf(r.Context(), "error loading wallet for unwrap record user=%s wallet=%s: %s", *userDid, req.WalletAddress, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -167,7 +167,7 @@ func (a *AppService) RecordUnwrap(w http.ResponseWriter, r *http.Request) {
 
 	recordedAt := time.Now().UTC()
 	if err := a.db.SetWalletLastUnwrapAt(r.Context(), *wallet.Id, recordedAt); err != nil {
-		a.logger.Logf("error setting wallet last_unwrap_at wallet_id=%d user=%s: %s", *wallet.Id, *userDid, err)
+		a.logger.Errorf(r.Context(), "error setting wallet last_unwrap_at wallet_id=%d user=%s: %s", *wallet.Id, *userDid, err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
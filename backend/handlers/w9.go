@@ -9,10 +9,12 @@ import (
 	"math/big"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/SFLuv/app/backend/db"
+	"github.com/SFLuv/app/backend/emails"
 	"github.com/SFLuv/app/backend/logger"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/SFLuv/app/backend/utils"
@@ -52,6 +54,13 @@ func requiresApprovedW9(newTotal *big.Int, limit *big.Int) bool {
 }
 
 func (w *W9Service) CheckCompliance(ctx context.Context, fromAddress string, toAddress string, amount *big.Int) (*structs.W9CheckResponse, error) {
+	return w.CheckComplianceOnChain(ctx, 0, fromAddress, toAddress, amount)
+}
+
+// CheckComplianceOnChain checks a payout that will be sent on chainID. Paid
+// totals are summed across chains, but the earnings row is kept per chain so
+// payouts running on two chains side by side stay separately reportable.
+func (w *W9Service) CheckComplianceOnChain(ctx context.Context, chainID int64, fromAddress string, toAddress string, amount *big.Int) (*structs.W9CheckResponse, error) {
 	if w.appDb == nil || w.ponderDb == nil {
 		return nil, fmt.Errorf("w9 service not configured")
 	}
@@ -61,7 +70,7 @@ func (w *W9Service) CheckCompliance(ctx context.Context, fromAddress string, toA
 	}
 
 	year, _, _ := utils.CurrentYearBounds()
-	chainID := w.chainIDOrActive(0)
+	chainID = w.chainIDOrActive(chainID)
 	totalStr, err := w.ponderDb.GetPaidTotalForWalletYear(ctx, toAddress, year, adminAddresses)
 	if err != nil {
 		return nil, err
@@ -311,7 +320,7 @@ func (a *AppService) SubmitW9(w http.ResponseWriter, r *http.Request) {
 func (a *AppService) GetPendingW9Submissions(w http.ResponseWriter, r *http.Request) {
 	submissions, err := a.db.GetPendingW9Submissions(r.Context())
 	if err != nil {
-		a.logger.Logf("error getting pending w9 submissions: %s", err)
+		a.logger.Errorf(r.Context(), "error getting pending w9 submissions: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -319,7 +328,7 @@ func (a *AppService) GetPendingW9Submissions(w http.ResponseWriter, r *http.Requ
 	for _, submission := range submissions {
 		userId, err := a.db.GetUserIdByWalletAddress(r.Context(), submission.WalletAddress)
 		if err != nil {
-			a.logger.Logf("error getting user by wallet for w9 submission %d: %s", submission.Id, err)
+			a.logger.Errorf(r.Context(), "error getting user by wallet for w9 submission %d: %s", submission.Id, err)
 			continue
 		}
 		if userId == nil {
@@ -327,7 +336,7 @@ func (a *AppService) GetPendingW9Submissions(w http.ResponseWriter, r *http.Requ
 		}
 		email, err := a.db.GetUserContactEmail(r.Context(), *userId)
 		if err != nil {
-			a.logger.Logf("error getting user contact email for w9 submission %d: %s", submission.Id, err)
+			a.logger.Errorf(r.Context(), "error getting user contact email for w9 submission %d: %s", submission.Id, err)
 			continue
 		}
 		if email != nil && *email != "" {
@@ -370,6 +379,7 @@ func (a *AppService) ApproveW9Submission(w http.ResponseWriter, r *http.Request)
 		approvedBy = *approver
 	}
 
+	before, _ := a.db.GetW9SubmissionByID(r.Context(), req.Id)
 	submission, err := a.db.ApproveW9Submission(r.Context(), req.Id, approvedBy)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -377,10 +387,11 @@ func (a *AppService) ApproveW9Submission(w http.ResponseWriter, r *http.Request)
 			w.Write([]byte(`{"error":"w9_not_pending"}`))
 			return
 		}
-		a.logger.Logf("error approving w9 submission: %s", err)
+		a.logger.Errorf(r.Context(), "error approving w9 submission: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditW9Approve, "w9_submission", strconv.Itoa(req.Id), before, submission)
 
 	a.sendW9ApprovedUserEmail(r.Context(), submission)
 
@@ -418,12 +429,14 @@ func (a *AppService) RejectW9Submission(w http.ResponseWriter, r *http.Request)
 		rejectedBy = *rejector
 	}
 
+	before, _ := a.db.GetW9SubmissionByID(r.Context(), req.Id)
 	submission, err := a.db.RejectW9Submission(r.Context(), req.Id, rejectedBy, req.Reason)
 	if err != nil {
-		a.logger.Logf("error rejecting w9 submission: %s", err)
+		a.logger.Errorf(r.Context(), "error rejecting w9 submission: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	a.recordAdminAudit(r, structs.AdminAuditW9Reject, "w9_submission", strconv.Itoa(req.Id), before, submission)
 
 	resp := map[string]any{
 		"submission": submission,
@@ -451,7 +464,7 @@ func (a *AppService) submitW9(ctx context.Context, w http.ResponseWriter, req *s
 
 	existing, err := a.db.GetW9SubmissionByWalletYear(ctx, req.WalletAddress, year)
 	if err != nil {
-		a.logger.Logf("error checking existing w9 submission: %s", err)
+		a.logger.Errorf(ctx, "error checking existing w9 submission: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return nil, false
 	}
@@ -474,7 +487,7 @@ func (a *AppService) submitW9(ctx context.Context, w http.ResponseWriter, req *s
 
 	stored, err := a.db.UpsertW9Submission(ctx, submission)
 	if err != nil {
-		a.logger.Logf("error upserting w9 submission: %s", err)
+		a.logger.Errorf(ctx, "error upserting w9 submission: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return nil, false
 	}
@@ -484,54 +497,31 @@ func (a *AppService) submitW9(ctx context.Context, w http.ResponseWriter, req *s
 }
 
 func (a *AppService) sendW9SubmissionReceivedUserEmail(submission *structs.W9Submission) {
-	sender := utils.NewEmailSender()
-	if sender == nil {
-		a.logger.Logf("w9 submission received email not sent; mailgun not configured")
-		return
-	}
-
 	recipientEmail := strings.TrimSpace(submission.Email)
 	if recipientEmail == "" {
-		a.logger.Logf("w9 submission received email not sent; no recipient email for wallet %s", submission.WalletAddress)
+		a.logger.Warnf(context.Background(), "w9 submission received email not sent; no recipient email for wallet %s", submission.WalletAddress)
 		return
 	}
 
-	subject := "We received your W9 request"
-	body := utils.BuildStyledEmail(
-		subject,
-		"We'll follow up shortly",
-		"<p style=\"margin:0 0 16px; line-height:1.6;\">Thank you for reaching out about your W9 form.</p><p style=\"margin:0; line-height:1.6;\">We will get back to you shortly.</p>",
-	)
-
-	err := sender.SendEmail(
-		recipientEmail,
-		"SFLuv User",
-		subject,
-		body,
-		utils.NotificationFromEmail(),
-		"SFLuv Admin",
-	)
+	err := a.Notify(context.Background(), &Notification{
+		Category: structs.NotificationCategoryW9,
+		Email:    &NotificationEmail{To: recipientEmail, ToName: "SFLuv User", Template: "w9_submission_received", FromName: "SFLuv Admin"},
+	})
 	if err != nil {
-		a.logger.Logf("error sending w9 submission received email: %s", err)
+		a.logger.Errorf(context.Background(), "error sending w9 submission received email: %s", err)
 	}
 }
 
 func (a *AppService) sendW9ApprovedUserEmail(ctx context.Context, submission *structs.W9Submission) {
-	sender := utils.NewEmailSender()
-	if sender == nil {
-		a.logger.Logf("w9 user approval email not sent; mailgun not configured")
-		return
-	}
-
 	recipientEmail := strings.TrimSpace(submission.Email)
 	if recipientEmail == "" {
 		userId, err := a.db.GetUserIdByWalletAddress(ctx, submission.WalletAddress)
 		if err != nil {
-			a.logger.Logf("error resolving user for w9 approval email: %s", err)
+			a.logger.Errorf(ctx, "error resolving user for w9 approval email: %s", err)
 		} else if userId != nil {
 			email, err := a.db.GetUserContactEmail(ctx, *userId)
 			if err != nil {
-				a.logger.Logf("error resolving user contact email for w9 approval email: %s", err)
+				a.logger.Errorf(ctx, "error resolving user contact email for w9 approval email: %s", err)
 			} else if email != nil {
 				recipientEmail = strings.TrimSpace(*email)
 			}
@@ -539,71 +529,48 @@ func (a *AppService) sendW9ApprovedUserEmail(ctx context.Context, submission *st
 	}
 
 	if recipientEmail == "" {
-		a.logger.Logf("w9 user approval email not sent; no recipient email for wallet %s", submission.WalletAddress)
+		a.logger.Warnf(ctx, "w9 user approval email not sent; no recipient email for wallet %s", submission.WalletAddress)
 		return
 	}
 
-	subject := "Your W9 form has been approved"
-	body := utils.BuildStyledEmail(
-		subject,
-		"Approval confirmed",
-		fmt.Sprintf(
-			"<p style=\"margin:0 0 16px; line-height:1.6;\">Your W9 form has been approved for wallet <strong>%s</strong>.</p><p style=\"margin:0; line-height:1.6;\">The restriction has been removed.</p>",
-			utils.EscapeEmailHTML(submission.WalletAddress),
-		),
-	)
-
-	err := sender.SendEmail(
-		recipientEmail,
-		"SFLuv User",
-		subject,
-		body,
-		utils.NotificationFromEmail(),
-		"SFLuv Admin",
-	)
+	err := a.Notify(ctx, &Notification{
+		Category: structs.NotificationCategoryW9,
+		Email: &NotificationEmail{
+			To:       recipientEmail,
+			ToName:   "SFLuv User",
+			Template: "w9_approved",
+			Data:     emails.Data{"WalletAddress": submission.WalletAddress},
+			FromName: "SFLuv Admin",
+		},
+	})
 	if err != nil {
-		a.logger.Logf("error sending w9 approval email: %s", err)
+		a.logger.Errorf(ctx, "error sending w9 approval email: %s", err)
 	}
 }
 
 func (a *AppService) sendW9AdminAlertEmail(submission *structs.W9Submission) {
-	sender := utils.NewEmailSender()
-	if sender == nil {
-		a.logger.Logf("w9 admin email not sent; mailgun not configured")
-		return
-	}
-
 	adminEmail := strings.TrimSpace(os.Getenv("W9_ADMIN_EMAIL"))
 	if adminEmail == "" {
 		adminEmail = "admin@sfluv.org"
 	}
 
-	subject := fmt.Sprintf("W9 request received for %s", submission.WalletAddress)
-	submittedAtUTC := submission.SubmittedAt.UTC().Format(time.RFC3339)
-	details := fmt.Sprintf(
-		"<p style=\"margin:0 0 16px; line-height:1.6;\"><strong>%s</strong> has submitted a request for a W9 form for wallet address <strong>%s</strong>.</p><p style=\"margin:0; line-height:1.7;\"><strong>Email:</strong> %s<br/><strong>Year:</strong> %d<br/><strong>Submitted at (UTC):</strong> %s</p>",
-		utils.EscapeEmailHTML(submission.Email),
-		utils.EscapeEmailHTML(submission.WalletAddress),
-		utils.EscapeEmailHTML(submission.Email),
-		submission.Year,
-		utils.EscapeEmailHTML(submittedAtUTC),
-	)
-	body := utils.BuildStyledEmail(
-		subject,
-		"Admin follow-up required",
-		details,
-	)
-
-	err := sender.SendEmail(
-		adminEmail,
-		"SFLuv Admin",
-		subject,
-		body,
-		utils.NotificationFromEmail(),
-		"SFLuv Admin",
-	)
-	if err != nil {
-		a.logger.Logf("error sending w9 admin alert email: %s", err)
+	err := a.Notify(context.Background(), &Notification{
+		Category: structs.NotificationCategoryAdmin,
+		Email: &NotificationEmail{
+			To:       adminEmail,
+			ToName:   "SFLuv Admin",
+			Template: "w9_admin_alert",
+			Data: emails.Data{
+				"Email":         submission.Email,
+				"WalletAddress": submission.WalletAddress,
+				"Year":          submission.Year,
+				"SubmittedAt":   submission.SubmittedAt.UTC().Format(time.RFC3339),
+			},
+			FromName: "SFLuv Admin",
+		},
+	})
+	if err != nil {
+		a.logger.Errorf(context.Background(), "error sending w9 admin alert email: %s", err)
 	}
 }
 
@@ -657,7 +624,7 @@ func (a *AppService) CheckW9Compliance(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := a.w9.CheckCompliance(r.Context(), req.FromAddress, req.ToAddress, amount)
 	if err != nil {
-		a.logger.Logf("error checking w9 compliance: %s", err)
+		a.logger.Errorf(r.Context(), "error checking w9 compliance: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -703,9 +670,30 @@ func (a *AppService) RecordW9Transaction(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if !a.authenticatePonderCallback(r, body, os.Getenv("W9_HOOK_SECRET")) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	chainID := req.ChainID
+	if chainID <= 0 {
+		chainID = a.activeChainID()
+	}
+	fresh, release, err := a.claimPonderCallbackEvent(r.Context(), ponderCallbackSourceW9, chainID, req.Hash, req.LogIndex)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error deduping w9 transaction %s: %s", req.Hash, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !fresh {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	_, err = a.w9.ProcessPaidTransfer(r.Context(), req.FromAddress, req.ToAddress, req.Amount, req.Hash, req.ChainID, req.Timestamp)
 	if err != nil {
-		a.logger.Logf("error processing w9 transaction: %s", err)
+		a.logger.Errorf(r.Context(), "error processing w9 transaction: %s", err)
+		release()
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
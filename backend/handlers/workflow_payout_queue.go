@@ -0,0 +1,654 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/bot"
+	"github.com/SFLuv/app/backend/lifecycle"
+	"github.com/SFLuv/app/backend/logger"
+	"github.com/SFLuv/app/backend/metrics"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	workflowPayoutJobLease             = 2 * time.Minute
+	workflowPayoutJobHeartbeatInterval = 30 * time.Second
+	workflowPayoutJobPollInterval      = 5 * time.Second
+	workflowPayoutJobConfirmTimeout    = 2 * time.Minute
+	workflowPayoutJobPendingDelay      = 15 * time.Second
+	workflowPayoutJobDropWindow        = 10 * time.Minute
+	workflowPayoutJobBackoffBase       = 30 * time.Second
+	workflowPayoutJobBackoffMax        = 30 * time.Minute
+	workflowPayoutJobDefaultAttempts   = 5
+)
+
+func workflowPayoutJobMaxAttempts() int {
+	attempts := envInt("WORKFLOW_PAYOUT_JOB_MAX_ATTEMPTS", workflowPayoutJobDefaultAttempts)
+	if attempts <= 0 {
+		return workflowPayoutJobDefaultAttempts
+	}
+	return attempts
+}
+
+// workflowPayoutJobBackoff returns the delay before retry number attempt,
+// doubling from workflowPayoutJobBackoffBase up to workflowPayoutJobBackoffMax.
+func workflowPayoutJobBackoff(attempt int) time.Duration {
+	if attempt <= 1 {
+		return workflowPayoutJobBackoffBase
+	}
+	delay := workflowPayoutJobBackoffBase
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= workflowPayoutJobBackoffMax {
+			return workflowPayoutJobBackoffMax
+		}
+	}
+	return delay
+}
+
+// WorkflowPayoutQueue is the worker that drains workflow_payout_jobs. Jobs are
+// run one at a time so faucet transfers never race each other for a nonce.
+//
+// On shutdown the queue stops leasing jobs but lets the one in flight run on
+// the lifecycle work context until its transfer is either recorded or known
+// not to have been sent.
+type WorkflowPayoutQueue struct {
+	app       *AppService
+	logger    *logger.LogCloser
+	lifecycle *lifecycle.Manager
+	workerId  string
+	wake      chan struct{}
+}
+
+func NewWorkflowPayoutQueue(app *AppService, logger *logger.LogCloser) *WorkflowPayoutQueue {
+	hostname, err := os.Hostname()
+	if err != nil || strings.TrimSpace(hostname) == "" {
+		hostname = "backend"
+	}
+
+	return &WorkflowPayoutQueue{
+		app:      app,
+		logger:   logger,
+		workerId: fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func (q *WorkflowPayoutQueue) Start(lc *lifecycle.Manager) {
+	if q == nil || q.app == nil {
+		return
+	}
+	q.lifecycle = lc
+
+	lc.Go("workflow payout queue", func(ctx context.Context) {
+		ticker := time.NewTicker(workflowPayoutJobPollInterval)
+		defer ticker.Stop()
+
+		for {
+			q.drain(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-q.wake:
+			}
+		}
+	})
+}
+
+// Notify wakes the worker so newly queued jobs run without waiting for the
+// next poll.
+func (q *WorkflowPayoutQueue) Notify() {
+	if q == nil {
+		return
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *WorkflowPayoutQueue) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		jobs, err := q.app.db.LeaseWorkflowPayoutJobs(ctx, q.workerId, int64(workflowPayoutJobLease/time.Second), 1)
+		if err != nil {
+			if ctx.Err() == nil {
+				q.logger.Errorf(ctx, "error leasing workflow payout jobs: %s", err)
+			}
+			return
+		}
+		if len(jobs) == 0 {
+			return
+		}
+		for _, job := range jobs {
+			q.runWithHeartbeat(q.lifecycle.WorkContext(), job)
+		}
+	}
+}
+
+// runWithHeartbeat runs job under the request ID of the request that queued
+// it, so its logs and queries join that request's trace.
+func (q *WorkflowPayoutQueue) runWithHeartbeat(ctx context.Context, job *structs.WorkflowPayoutJob) {
+	requestId := job.RequestId
+	if requestId == "" {
+		requestId = "payout-job-" + job.Id
+	}
+	ctx = logger.WithRequestID(ctx, requestId)
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(workflowPayoutJobHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+				held, err := q.app.db.HeartbeatWorkflowPayoutJob(jobCtx, job.Id, q.workerId, int64(workflowPayoutJobLease/time.Second))
+				if err != nil {
+					q.logger.Errorf(ctx, "error sending heartbeat for workflow payout job %s: %s", job.Id, err)
+					continue
+				}
+				if !held {
+					q.logger.Warnf(ctx, "lost lease on workflow payout job %s; abandoning run", job.Id)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	q.run(jobCtx, job)
+}
+
+// workflowPayoutJobFailure describes why a job run did not pay out.
+type workflowPayoutJobFailure struct {
+	message      string
+	detail       error
+	current      *big.Int
+	needed       *big.Int
+	insufficient bool
+}
+
+func (q *WorkflowPayoutQueue) run(ctx context.Context, job *structs.WorkflowPayoutJob) {
+	a := q.app
+
+	workflow, err := a.db.GetWorkflowByID(ctx, job.WorkflowId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			q.abandon(ctx, job, "workflow no longer exists")
+			return
+		}
+		q.retry(ctx, job, nil, &workflowPayoutJobFailure{message: workflowPayoutErrorProcessingFailed, detail: err})
+		return
+	}
+
+	target, paid, err := workflowPayoutJobTarget(workflow, job)
+	if err != nil {
+		q.abandon(ctx, job, err.Error())
+		return
+	}
+	if paid {
+		q.complete(ctx, job, target)
+		return
+	}
+
+	if strings.TrimSpace(job.TxHash) != "" {
+		q.resumeSubmittedTransfer(ctx, job, target)
+		return
+	}
+
+	if !job.TargetLocked {
+		claimed, err := q.claimTarget(ctx, job, target)
+		if err != nil {
+			// The claim and the job's record of it commit together, so a
+			// failure leaves no lock behind to release.
+			q.retry(ctx, job, nil, &workflowPayoutJobFailure{message: workflowPayoutErrorProcessingFailed, detail: err})
+			return
+		}
+		if !claimed {
+			q.retry(ctx, job, nil, &workflowPayoutJobFailure{message: "workflow payout target is locked by another payout attempt"})
+			return
+		}
+		job.TargetLocked = true
+	}
+
+	if target.ImproverId == "" {
+		q.fail(ctx, job, target, "", &workflowPayoutJobFailure{message: workflowPayoutErrorNoImproverAssigned})
+		return
+	}
+
+	walletAddress, err := a.db.GetPreferredWorkflowPayoutAddressForUser(ctx, target.ImproverId, target.IsManager)
+	if err != nil {
+		q.fail(ctx, job, target, "", &workflowPayoutJobFailure{message: workflowPayoutErrorNoPayoutWallet, detail: err})
+		return
+	}
+
+	if err := a.db.SetWorkflowPayoutJobWallet(ctx, job.Id, q.workerId, walletAddress, target.Amount); err != nil {
+		q.retry(ctx, job, &target, &workflowPayoutJobFailure{message: workflowPayoutErrorProcessingFailed, detail: err})
+		return
+	}
+	job.WalletAddress = walletAddress
+	job.Amount = target.Amount
+
+	payoutBot := a.bot.payoutBot(bot.PayoutTargetWorkflow)
+	if payoutBot == nil {
+		q.retry(ctx, job, &target, &workflowPayoutJobFailure{message: workflowPayoutErrorProcessingFailed, detail: fmt.Errorf("bot service is not configured")})
+		return
+	}
+
+	currentBalance, neededBalance, insufficient, txHash, transferErr := a.submitWorkflowPayoutTransfer(ctx, payoutBot, target.Amount, walletAddress)
+	if strings.TrimSpace(txHash) != "" {
+		// Keep going on a record failure: the transfer is already out, so the
+		// worst outcome is re-verifying it rather than sending it twice.
+		if err := q.recordTx(ctx, job, target, txHash, a.bot.payoutChainID(payoutBot)); err != nil {
+			q.logger.Errorf(ctx, "error recording tx hash %s for workflow payout job %s: %s", txHash, job.Id, err)
+		}
+	}
+	if transferErr != nil {
+		if strings.TrimSpace(txHash) != "" {
+			// The transaction reached the network; leave it for confirmation
+			// instead of sending a second transfer.
+			q.deferJob(ctx, job, transferErr.Error())
+			return
+		}
+		errMsg := workflowPayoutErrorTransferFailed
+		if insufficient {
+			errMsg = workflowPayoutErrorInsufficientFaucet
+		}
+		q.retry(ctx, job, &target, &workflowPayoutJobFailure{
+			message:      errMsg,
+			detail:       transferErr,
+			current:      currentBalance,
+			needed:       neededBalance,
+			insufficient: insufficient,
+		})
+		return
+	}
+
+	// The tx hash is on record by now, so a shutdown can stop waiting for
+	// confirmation and leave the job for the next worker to verify.
+	waitCtx, waitCancel := context.WithTimeout(ctx, workflowPayoutJobConfirmTimeout)
+	defer waitCancel()
+	stopWaiting := context.AfterFunc(q.lifecycle.Context(), waitCancel)
+	defer stopWaiting()
+	if err := a.waitForWorkflowPayoutTransferConfirmation(waitCtx, payoutBot, txHash, walletAddress, target.Amount); err != nil {
+		if q.lifecycle.Draining() {
+			q.deferJob(ctx, job, fmt.Sprintf("server shut down before payout tx %s confirmed", txHash))
+			return
+		}
+		q.resumeSubmittedTransfer(ctx, job, target)
+		return
+	}
+	q.complete(ctx, job, target)
+}
+
+// resumeSubmittedTransfer settles a job that already has a tx hash on record,
+// either from earlier in this run or from a worker that stopped mid-payout.
+func (q *WorkflowPayoutQueue) resumeSubmittedTransfer(ctx context.Context, job *structs.WorkflowPayoutJob, target workflowPayoutTarget) {
+	a := q.app
+	if a.bot == nil {
+		q.deferJob(ctx, job, "bot service is not configured")
+		return
+	}
+	var txChainID int64
+	if job.TxChainId != nil {
+		txChainID = *job.TxChainId
+	}
+	payoutBot, ok := a.bot.botForChain(txChainID)
+	if !ok {
+		q.fail(ctx, job, target, job.WalletAddress, &workflowPayoutJobFailure{
+			message: workflowPayoutErrorProcessingFailed,
+			detail:  fmt.Errorf("payout tx %s was submitted on chain %d, which has no payout bot running", job.TxHash, txChainID),
+		})
+		return
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	result, err := payoutBot.VerifyTransfer(checkCtx, job.TxHash, job.WalletAddress, job.Amount)
+	if err != nil {
+		q.deferJob(ctx, job, fmt.Sprintf("error verifying payout tx %s: %s", job.TxHash, err))
+		return
+	}
+
+	switch {
+	case result != nil && result.Successful:
+		q.complete(ctx, job, target)
+	case result != nil && result.Pending:
+		q.deferJob(ctx, job, fmt.Sprintf("payout tx %s is pending confirmation", job.TxHash))
+	case result != nil && result.Found:
+		q.retry(ctx, job, &target, &workflowPayoutJobFailure{
+			message: workflowPayoutErrorTransferFailed,
+			detail:  fmt.Errorf("transfer tx %s did not produce the expected successful SFLUV transfer", job.TxHash),
+		})
+	default:
+		if job.TxSubmittedAt != nil && time.Since(time.Unix(*job.TxSubmittedAt, 0)) < workflowPayoutJobDropWindow {
+			q.deferJob(ctx, job, fmt.Sprintf("payout tx %s not yet visible on chain", job.TxHash))
+			return
+		}
+		q.retry(ctx, job, &target, &workflowPayoutJobFailure{
+			message: workflowPayoutErrorTransferFailed,
+			detail:  fmt.Errorf("transfer tx %s was not found on chain", job.TxHash),
+		})
+	}
+}
+
+func (q *WorkflowPayoutQueue) claimTarget(ctx context.Context, job *structs.WorkflowPayoutJob, target workflowPayoutTarget) (bool, error) {
+	return q.app.db.ClaimWorkflowPayoutJobTarget(ctx, job.Id, q.workerId, target.WorkflowId, target.StepId, target.IsManager)
+}
+
+func (q *WorkflowPayoutQueue) recordTx(ctx context.Context, job *structs.WorkflowPayoutJob, target workflowPayoutTarget, txHash string, chainID int64) error {
+	a := q.app
+	submittedAt := time.Now().Unix()
+	job.TxHash = txHash
+	job.TxChainId = &chainID
+	job.TxSubmittedAt = &submittedAt
+
+	if err := a.db.RecordWorkflowPayoutJobTx(ctx, job.Id, q.workerId, txHash, chainID); err != nil {
+		return err
+	}
+
+	if target.IsManager {
+		return a.db.RecordWorkflowManagerPayoutTxHash(ctx, target.WorkflowId, txHash, chainID)
+	}
+	return a.db.RecordWorkflowStepPayoutTxHash(ctx, target.WorkflowId, target.StepId, txHash, chainID)
+}
+
+func (q *WorkflowPayoutQueue) complete(ctx context.Context, job *structs.WorkflowPayoutJob, target workflowPayoutTarget) {
+	a := q.app
+
+	var err error
+	if target.IsManager {
+		_, err = a.db.MarkWorkflowManagerPaidOut(ctx, target.WorkflowId)
+	} else {
+		_, err = a.db.MarkWorkflowStepPaidOut(ctx, target.WorkflowId, target.StepId)
+	}
+	if err != nil {
+		// The transfer landed, so the job stays leased and is picked up again
+		// once the lease expires rather than being retried as a new transfer.
+		q.logger.Errorf(ctx, "error marking payout complete for workflow payout job %s: %s", job.Id, err)
+		a.sendWorkflowPayoutErrorEmail(ctx, target, job.WalletAddress, fmt.Sprintf("workflow payout post-transfer state update failed: %s", err), nil, nil, false)
+		return
+	}
+
+	if err := a.db.CompleteWorkflowPayoutJob(ctx, job.Id, q.workerId); err != nil {
+		q.logger.Errorf(ctx, "error completing workflow payout job %s: %s", job.Id, err)
+		return
+	}
+	metrics.WorkflowPayout(job.TargetType, metrics.ResultPaid)
+
+	if _, err := a.db.FinalizeWorkflowPaidOutIfSettled(ctx, target.WorkflowId); err != nil {
+		q.logger.Errorf(ctx, "error finalizing workflow paid_out status for workflow %s: %s", target.WorkflowId, err)
+	}
+	a.processWorkflowSeriesPayouts(ctx, target.WorkflowId)
+}
+
+func (q *WorkflowPayoutQueue) deferJob(ctx context.Context, job *structs.WorkflowPayoutJob, reason string) {
+	if err := q.app.db.DeferWorkflowPayoutJob(ctx, job.Id, q.workerId, int64(workflowPayoutJobPendingDelay/time.Second), reason); err != nil {
+		q.logger.Errorf(ctx, "error deferring workflow payout job %s: %s", job.Id, err)
+		return
+	}
+	metrics.WorkflowPayout(job.TargetType, metrics.ResultDeferred)
+}
+
+// retry schedules another attempt, or dead-letters the job once its attempt
+// budget is spent. target is nil when there is no payout lock to release.
+func (q *WorkflowPayoutQueue) retry(ctx context.Context, job *structs.WorkflowPayoutJob, target *workflowPayoutTarget, failure *workflowPayoutJobFailure) {
+	attempt := job.Attempts + 1
+	if attempt >= job.MaxAttempts {
+		if target == nil {
+			q.deadLetterJob(ctx, job, failureMessage(failure))
+			return
+		}
+		q.fail(ctx, job, *target, job.WalletAddress, failure)
+		return
+	}
+
+	delay := workflowPayoutJobBackoff(attempt)
+	if err := q.app.db.RetryWorkflowPayoutJob(ctx, job.Id, q.workerId, int64(delay/time.Second), failureMessage(failure)); err != nil {
+		q.logger.Errorf(ctx, "error scheduling retry for workflow payout job %s: %s", job.Id, err)
+		return
+	}
+	metrics.WorkflowPayout(job.TargetType, metrics.ResultRetried)
+	q.logger.Warnf(ctx, "workflow payout job %s attempt %d/%d failed, retrying in %s: %s", job.Id, attempt, job.MaxAttempts, delay, failureMessage(failure))
+}
+
+// fail records the payout failure on the step or supervisor target, which
+// releases its payout lock so the improver can request a retry, notifies the
+// workflow admins, and dead-letters the job.
+func (q *WorkflowPayoutQueue) fail(ctx context.Context, job *structs.WorkflowPayoutJob, target workflowPayoutTarget, walletAddress string, failure *workflowPayoutJobFailure) {
+	a := q.app
+
+	var err error
+	if target.IsManager {
+		err = a.db.MarkWorkflowManagerPayoutFailed(ctx, target.WorkflowId, failure.message)
+	} else {
+		err = a.db.MarkWorkflowStepPayoutFailed(ctx, target.WorkflowId, target.StepId, failure.message)
+	}
+	if err != nil {
+		q.logger.Errorf(ctx, "error recording payout failure for workflow payout job %s: %s", job.Id, err)
+	}
+
+	q.deadLetterJob(ctx, job, failureMessage(failure))
+	a.sendWorkflowPayoutErrorEmail(ctx, target, walletAddress, failureMessage(failure), failure.current, failure.needed, failure.insufficient)
+}
+
+// abandon dead-letters a job whose target can no longer be paid, first
+// releasing the target's payout lock if this job holds it.
+func (q *WorkflowPayoutQueue) abandon(ctx context.Context, job *structs.WorkflowPayoutJob, reason string) {
+	if job.TargetLocked {
+		isManager := job.TargetType == structs.WorkflowPayoutTargetSupervisor
+		if err := q.app.db.ReleaseWorkflowPayoutTarget(ctx, job.WorkflowId, job.StepId, isManager, reason); err != nil {
+			q.logger.Errorf(ctx, "error releasing payout lock for workflow payout job %s: %s", job.Id, err)
+		}
+	}
+	q.deadLetterJob(ctx, job, reason)
+}
+
+func (q *WorkflowPayoutQueue) deadLetterJob(ctx context.Context, job *structs.WorkflowPayoutJob, reason string) {
+	if err := q.app.db.DeadLetterWorkflowPayoutJob(ctx, job.Id, q.workerId, reason); err != nil {
+		q.logger.Errorf(ctx, "error dead-lettering workflow payout job %s: %s", job.Id, err)
+		return
+	}
+	metrics.WorkflowPayout(job.TargetType, metrics.ResultDeadLettered)
+	q.logger.Infof(ctx, "workflow payout job %s for workflow %s moved to dead letter: %s", job.Id, job.WorkflowId, reason)
+}
+
+func failureMessage(failure *workflowPayoutJobFailure) string {
+	if failure == nil {
+		return workflowPayoutErrorUnknown
+	}
+	if failure.detail == nil {
+		return failure.message
+	}
+	return fmt.Sprintf("%s Detail: %s", failure.message, failure.detail)
+}
+
+// workflowPayoutJobTarget resolves the step or supervisor payout a job refers
+// to. paid is true when the target has already been settled.
+func workflowPayoutJobTarget(workflow *structs.Workflow, job *structs.WorkflowPayoutJob) (workflowPayoutTarget, bool, error) {
+	target := workflowPayoutTarget{
+		WorkflowId:    workflow.Id,
+		WorkflowTitle: workflow.Title,
+		SeriesId:      workflow.SeriesId,
+	}
+
+	switch job.TargetType {
+	case structs.WorkflowPayoutTargetSupervisor:
+		target.IsManager = true
+		target.Amount = workflow.ManagerBounty
+		if workflow.ManagerImproverId != nil {
+			target.ImproverId = strings.TrimSpace(*workflow.ManagerImproverId)
+		}
+		if workflow.ManagerPaidOutAt != nil {
+			return target, true, nil
+		}
+		if workflow.Status != "completed" || workflow.ManagerBounty == 0 || target.ImproverId == "" {
+			return target, false, errors.New("workflow supervisor payout is no longer payable")
+		}
+		return target, false, nil
+	case structs.WorkflowPayoutTargetStep:
+		for _, step := range workflow.Steps {
+			if step.Id != job.StepId {
+				continue
+			}
+			target.StepId = step.Id
+			target.StepTitle = step.Title
+			target.StepOrder = step.StepOrder
+			target.Amount = step.Bounty
+			if step.AssignedImproverId != nil {
+				target.ImproverId = strings.TrimSpace(*step.AssignedImproverId)
+			}
+			if step.Status == "paid_out" {
+				return target, true, nil
+			}
+			if step.Status != "completed" || step.Bounty == 0 {
+				return target, false, errors.New("workflow step payout is no longer payable")
+			}
+			return target, false, nil
+		}
+		return target, false, errors.New("workflow step no longer exists")
+	default:
+		return target, false, fmt.Errorf("invalid workflow payout job target type %q", job.TargetType)
+	}
+}
+
+func (a *AppService) GetAdminWorkflowPayoutJobs(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	status := strings.TrimSpace(params.Get("status"))
+	switch status {
+	case "",
+		structs.WorkflowPayoutJobStatusQueued,
+		structs.WorkflowPayoutJobStatusLeased,
+		structs.WorkflowPayoutJobStatusSucceeded,
+		structs.WorkflowPayoutJobStatusDead:
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid status value"))
+		return
+	}
+	page, count := parsePageAndCount(params, 20, 200)
+
+	response, err := a.db.GetWorkflowPayoutJobs(r.Context(), status, params.Get("workflow_id"), page, count)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error loading workflow payout jobs: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (a *AppService) RequeueAdminWorkflowPayoutJob(w http.ResponseWriter, r *http.Request) {
+	adminId := utils.GetDid(r)
+	if adminId == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	jobId := strings.TrimSpace(r.PathValue("job_id"))
+	if jobId == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	before, err := a.db.GetWorkflowPayoutJob(r.Context(), jobId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		a.logger.Errorf(r.Context(), "error loading workflow payout job %s for requeue by admin %s: %s", jobId, *adminId, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if before.Status != structs.WorkflowPayoutJobStatusDead {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("only dead workflow payout jobs can be requeued"))
+		return
+	}
+
+	// A recorded transfer that landed or may still land must be settled by
+	// the worker, not replaced by a second one.
+	clearTx := true
+	if strings.TrimSpace(before.TxHash) != "" {
+		mayLand, err := a.workflowPayoutTxMayLand(r.Context(), before)
+		if err != nil {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(fmt.Sprintf("could not verify payout tx %s; try again later: %s", before.TxHash, err)))
+			return
+		}
+		clearTx = !mayLand
+	}
+
+	job, err := a.db.RequeueWorkflowPayoutJob(r.Context(), jobId, clearTx)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		errMsg := err.Error()
+		if strings.Contains(errMsg, "only dead") || strings.Contains(errMsg, "already has an active job") {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(errMsg))
+			return
+		}
+		a.logger.Errorf(r.Context(), "error requeueing workflow payout job %s by admin %s: %s", jobId, *adminId, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	a.logger.Infof(r.Context(), "workflow payout job %s requeued by admin %s", jobId, *adminId)
+	a.recordAdminAudit(
+		r,
+		structs.AdminAuditWorkflowPayoutJobRequeue,
+		"workflow_payout_job",
+		jobId,
+		map[string]any{"status": before.Status, "attempts": before.Attempts, "tx_hash": before.TxHash, "last_error": before.LastError},
+		map[string]any{"status": job.Status, "attempts": job.Attempts, "tx_hash": job.TxHash},
+	)
+	a.payoutQueue.Notify()
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+// workflowPayoutTxMayLand reports whether a job's recorded transfer succeeded
+// or is still pending on chain.
+func (a *AppService) workflowPayoutTxMayLand(ctx context.Context, job *structs.WorkflowPayoutJob) (bool, error) {
+	var txChainID int64
+	if job.TxChainId != nil {
+		txChainID = *job.TxChainId
+	}
+	payoutBot, ok := a.bot.botForChain(txChainID)
+	if !ok {
+		return false, fmt.Errorf("no payout bot is running on chain %d", txChainID)
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	result, err := payoutBot.VerifyTransfer(checkCtx, job.TxHash, job.WalletAddress, job.Amount)
+	if err != nil {
+		return false, err
+	}
+	return result != nil && (result.Successful || result.Pending), nil
+}
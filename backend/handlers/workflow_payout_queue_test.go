@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestWorkflowPayoutJobBackoff(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 30 * time.Second},
+		{attempt: 1, want: 30 * time.Second},
+		{attempt: 2, want: time.Minute},
+		{attempt: 3, want: 2 * time.Minute},
+		{attempt: 6, want: 16 * time.Minute},
+		{attempt: 7, want: 30 * time.Minute},
+		{attempt: 50, want: 30 * time.Minute},
+	}
+
+	for _, tc := range cases {
+		if got := workflowPayoutJobBackoff(tc.attempt); got != tc.want {
+			t.Fatalf("workflowPayoutJobBackoff(%d) = %s; want %s", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestWorkflowPayoutJobTarget(t *testing.T) {
+	improver := "improver-1"
+	supervisor := "supervisor-1"
+	paidAt := int64(1700000000)
+
+	workflow := &structs.Workflow{
+		Id:                "wf-1",
+		Status:            "completed",
+		ManagerBounty:     25,
+		ManagerImproverId: &supervisor,
+		Steps: []structs.WorkflowStep{
+			{Id: "step-1", Status: "completed", Bounty: 10, AssignedImproverId: &improver},
+			{Id: "step-2", Status: "paid_out", Bounty: 10, AssignedImproverId: &improver},
+			{Id: "step-3", Status: "in_progress", Bounty: 10},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		job        structs.WorkflowPayoutJob
+		paidOutAt  *int64
+		wantPaid   bool
+		wantErr    bool
+		wantAmount uint64
+		wantUser   string
+	}{
+		{name: "payable step", job: structs.WorkflowPayoutJob{TargetType: "step", StepId: "step-1"}, wantAmount: 10, wantUser: improver},
+		{name: "already paid step", job: structs.WorkflowPayoutJob{TargetType: "step", StepId: "step-2"}, wantPaid: true, wantAmount: 10, wantUser: improver},
+		{name: "step not completed", job: structs.WorkflowPayoutJob{TargetType: "step", StepId: "step-3"}, wantErr: true},
+		{name: "missing step", job: structs.WorkflowPayoutJob{TargetType: "step", StepId: "step-9"}, wantErr: true},
+		{name: "supervisor", job: structs.WorkflowPayoutJob{TargetType: "supervisor"}, wantAmount: 25, wantUser: supervisor},
+		{name: "supervisor paid", job: structs.WorkflowPayoutJob{TargetType: "supervisor"}, paidOutAt: &paidAt, wantPaid: true, wantAmount: 25, wantUser: supervisor},
+		{name: "invalid target", job: structs.WorkflowPayoutJob{TargetType: "other"}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			workflow.ManagerPaidOutAt = tc.paidOutAt
+			target, paid, err := workflowPayoutJobTarget(workflow, &tc.job)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if paid != tc.wantPaid {
+				t.Fatalf("paid = %t; want %t", paid, tc.wantPaid)
+			}
+			if target.Amount != tc.wantAmount || target.ImproverId != tc.wantUser {
+				t.Fatalf("target = %+v; want amount %d improver %s", target, tc.wantAmount, tc.wantUser)
+			}
+		})
+	}
+}
+
+func TestCollectWorkflowPayoutTargetsHoldsUnapprovedSteps(t *testing.T) {
+	improver := "improver-1"
+	workflow := &structs.Workflow{
+		Id:     "workflow-1",
+		Status: "in_progress",
+		Steps: []structs.WorkflowStep{
+			{Id: "step-1", StepOrder: 1, Status: "completed", Bounty: 10, AssignedImproverId: &improver},
+			{Id: "step-2", StepOrder: 2, Status: "completed", Bounty: 10, AssignedImproverId: &improver, ReviewStatus: structs.WorkflowStepReviewFlagged},
+			{Id: "step-3", StepOrder: 3, Status: "completed", Bounty: 10, AssignedImproverId: &improver, ReviewStatus: structs.WorkflowStepReviewApproved},
+			{Id: "step-4", StepOrder: 4, Status: "completed", Bounty: 10, AssignedImproverId: &improver, ReviewStatus: structs.WorkflowStepReviewPending},
+			{Id: "step-5", StepOrder: 5, Status: "completed", Bounty: 10, AssignedImproverId: &improver, ReviewStatus: structs.WorkflowStepReviewRejected},
+		},
+	}
+
+	targets := collectWorkflowPayoutTargets(workflow)
+	if len(targets) != 2 || targets[0].StepId != "step-1" || targets[1].StepId != "step-3" {
+		t.Fatalf("expected only unheld and approved steps to be paid, got %+v", targets)
+	}
+}
+
+func TestWorkflowHasBlockingPendingPayoutsSkipsRejectedSteps(t *testing.T) {
+	workflow := &structs.Workflow{
+		Status: "completed",
+		Steps: []structs.WorkflowStep{
+			{Id: "step-1", StepOrder: 1, Status: "paid_out", Bounty: 10},
+			{Id: "step-2", StepOrder: 2, Status: "completed", Bounty: 10, ReviewStatus: structs.WorkflowStepReviewRejected},
+		},
+	}
+	if workflowHasBlockingPendingPayouts(workflow) {
+		t.Fatalf("expected a rejected step not to hold the series")
+	}
+
+	workflow.Steps[1].ReviewStatus = structs.WorkflowStepReviewPending
+	if !workflowHasBlockingPendingPayouts(workflow) {
+		t.Fatalf("expected a step awaiting review to hold the series")
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SFLuv/app/backend/photoproc"
+	"github.com/SFLuv/app/backend/photostore"
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/jackc/pgx/v5"
+)
+
+const defaultWorkflowPhotoURLTTL = 5 * time.Minute
+
+var (
+	errPhotoURLSigningDisabled  = errors.New("photo url signing is not configured")
+	errInvalidWorkflowPhotoSize = errors.New("size must be thumbnail, medium or original")
+)
+
+// SetPhotoURLSigning configures the short-lived links GetWorkflowPhoto hands
+// out. Photos in stores that cannot sign their own URLs are served back
+// through GetSignedWorkflowPhoto under baseURL, the backend's public origin.
+func (a *AppService) SetPhotoURLSigning(signer *photostore.URLSigner, ttl time.Duration, baseURL string) {
+	if ttl <= 0 {
+		ttl = defaultWorkflowPhotoURLTTL
+	}
+	a.photoURLSigner = signer
+	a.photoURLTTL = ttl
+	a.photoURLBase = strings.TrimRight(baseURL, "/")
+}
+
+// workflowPhotoVariant parses a requested photo size naming a rendition. An
+// empty variant means the original.
+func workflowPhotoVariant(size string) (string, error) {
+	variant := strings.ToLower(strings.TrimSpace(size))
+	if variant == "" || variant == "original" {
+		return "", nil
+	}
+	if !photoproc.IsVariant(variant) {
+		return "", errInvalidWorkflowPhotoSize
+	}
+	return variant, nil
+}
+
+// resolveWorkflowPhotoVariant swaps photo for its rendition. Photos without
+// one, stored before renditions existed or in formats that could not be
+// decoded, are served as the original; the variant returned is what is
+// actually served.
+func (a *AppService) resolveWorkflowPhotoVariant(ctx context.Context, photo *structs.WorkflowSubmissionPhotoBlob, variant string, withData bool) (*structs.WorkflowSubmissionPhotoBlob, string, error) {
+	if variant != "" {
+		var rendition *structs.WorkflowSubmissionPhotoBlob
+		var err error
+		if withData {
+			rendition, err = a.db.GetWorkflowPhotoRenditionBlob(ctx, photo, variant)
+		} else {
+			rendition, err = a.db.GetWorkflowPhotoRendition(ctx, photo, variant)
+		}
+		if err == nil {
+			return rendition, variant, nil
+		}
+		if err != pgx.ErrNoRows {
+			return nil, "", err
+		}
+	}
+	if !withData {
+		return photo, "", nil
+	}
+	original, err := a.db.GetWorkflowSubmissionPhotoBlobByID(ctx, photo.Id)
+	return original, "", err
+}
+
+// workflowPhotoSigningID is what a backend link's signature covers, so a
+// link to a thumbnail cannot be edited into one for the original.
+func workflowPhotoSigningID(photoID string, variant string) string {
+	if variant == "" {
+		return photoID
+	}
+	return photoID + ":" + variant
+}
+
+func (a *AppService) signWorkflowPhotoURL(photo *structs.WorkflowSubmissionPhotoBlob, variant string, now time.Time) (*structs.WorkflowPhotoURL, error) {
+	ttl := a.photoURLTTL
+	if ttl <= 0 {
+		ttl = defaultWorkflowPhotoURLTTL
+	}
+	expiresAt := now.Add(ttl)
+
+	if signer, ok := a.db.WorkflowPhotoSigner(photo); ok {
+		contentType, disposition := workflowPhotoContentHeaders(photo, false)
+		signed, err := signer.SignedURL(photo.StorageKey, photostore.URLOptions{
+			ContentType:        contentType,
+			ContentDisposition: disposition,
+		}, ttl, now)
+		if err != nil {
+			return nil, err
+		}
+		return &structs.WorkflowPhotoURL{URL: signed, ExpiresAt: expiresAt.Unix()}, nil
+	}
+
+	if a.photoURLSigner == nil {
+		return nil, errPhotoURLSigningDisabled
+	}
+	query := a.photoURLSigner.Sign(workflowPhotoSigningID(photo.Id, variant), expiresAt)
+	if variant != "" {
+		query.Set("size", variant)
+	}
+	signed := a.photoURLBase + "/workflow-photos/" + url.PathEscape(photo.Id) + "/signed?" + query.Encode()
+	return &structs.WorkflowPhotoURL{URL: signed, ExpiresAt: expiresAt.Unix()}, nil
+}
+
+// GetSignedWorkflowPhoto serves a photo to anyone holding a link issued by
+// GetWorkflowPhoto; the signature stands in for the session.
+func (a *AppService) GetSignedWorkflowPhoto(w http.ResponseWriter, r *http.Request) {
+	photoID := strings.TrimSpace(r.PathValue("photo_id"))
+	if photoID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if a.photoURLSigner == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	variant, err := workflowPhotoVariant(r.URL.Query().Get("size"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	if err := a.photoURLSigner.Verify(workflowPhotoSigningID(photoID, variant), r.URL.Query(), time.Now()); err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	photo, err := a.db.GetWorkflowSubmissionPhotoByID(r.Context(), photoID)
+	if err == nil {
+		photo, _, err = a.resolveWorkflowPhotoVariant(r.Context(), photo, variant, true)
+	}
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		a.logger.Errorf(r.Context(), "error loading signed workflow photo %s: %s", photoID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeWorkflowPhotoResponse(w, photo, false)
+}
+
+// GetAdminWorkflowPhotoReuses lists submitted photos whose perceptual hash
+// matches a photo uploaded for another step.
+func (a *AppService) GetAdminWorkflowPhotoReuses(w http.ResponseWriter, r *http.Request) {
+	page, count := parsePageAndCount(r.URL.Query(), 20, 200)
+
+	response, err := a.db.GetWorkflowPhotoReuses(r.Context(), page, count)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error loading reused workflow photos: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/SFLuv/app/backend/structs"
+	"github.com/SFLuv/app/backend/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// workflowStepReviewOutcomes maps review decisions to their audit action
+// and the review status they leave the step in.
+var workflowStepReviewOutcomes = map[string]struct {
+	action string
+	status string
+}{
+	structs.WorkflowStepReviewDecisionApprove:             {structs.AdminAuditWorkflowStepReviewApprove, structs.WorkflowStepReviewApproved},
+	structs.WorkflowStepReviewDecisionReject:              {structs.AdminAuditWorkflowStepReviewReject, structs.WorkflowStepReviewRejected},
+	structs.WorkflowStepReviewDecisionRequestResubmission: {structs.AdminAuditWorkflowStepReviewResubmit, structs.WorkflowStepReviewResubmissionRequested},
+}
+
+// isWorkflowStepImprover reports whether userID is the improver assigned to
+// the step, who may not review their own completion.
+func isWorkflowStepImprover(workflow *structs.Workflow, stepID string, userID string) bool {
+	if workflow == nil || userID == "" {
+		return false
+	}
+	for _, step := range workflow.Steps {
+		if step.Id != stepID {
+			continue
+		}
+		return step.AssignedImproverId != nil && strings.TrimSpace(*step.AssignedImproverId) == strings.TrimSpace(userID)
+	}
+	return false
+}
+
+// ReviewWorkflowStep records a supervisor's decision on a step completion
+// held for review, either because the workflow requires supervisor approval
+// or because it was reported outside the step's geofence or completion
+// window. Approving it releases the step's payout; rejecting it or asking
+// for a resubmission requires a comment for the improver.
+func (a *AppService) ReviewWorkflowStep(w http.ResponseWriter, r *http.Request) {
+	userDid := utils.GetDid(r)
+	if userDid == nil {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	isAdmin := a.IsAdmin(r.Context(), *userDid)
+
+	workflowId := strings.TrimSpace(r.PathValue("workflow_id"))
+	stepId := strings.TrimSpace(r.PathValue("step_id"))
+	if workflowId == "" || stepId == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	defer r.Body.Close()
+	req := structs.WorkflowStepReviewRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	decision := strings.ToLower(strings.TrimSpace(req.Decision))
+	outcome, ok := workflowStepReviewOutcomes[decision]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("decision must be approve, reject or request_resubmission"))
+		return
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if decision != structs.WorkflowStepReviewDecisionApprove && comment == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("comment is required to reject or request a resubmission"))
+		return
+	}
+
+	workflow, err := a.db.GetWorkflowByID(r.Context(), workflowId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		a.logger.Errorf(r.Context(), "error loading workflow %s for step review: %s", workflowId, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if !isAdmin && !canUserViewWorkflowSupervisorData(workflow, *userDid) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if isWorkflowStepImprover(workflow, stepId, *userDid) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("the assigned improver cannot review their own step"))
+		return
+	}
+
+	previousStatus, err := a.db.RecordWorkflowStepReview(r.Context(), workflowId, stepId, *userDid, decision, comment)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "not awaiting review") || strings.Contains(err.Error(), "workflow is not active") {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		a.logger.Errorf(r.Context(), "error recording %s review of step %s on workflow %s by %s: %s", decision, stepId, workflowId, *userDid, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	a.recordAdminAudit(
+		r,
+		outcome.action,
+		"workflow_step",
+		stepId,
+		map[string]string{"review_status": previousStatus},
+		map[string]string{"review_status": outcome.status, "comment": comment},
+	)
+
+	if decision != structs.WorkflowStepReviewDecisionRequestResubmission {
+		a.processWorkflowSeriesPayouts(r.Context(), workflowId)
+	}
+
+	workflow, err = a.db.GetWorkflowByID(r.Context(), workflowId)
+	if err != nil {
+		a.logger.Errorf(r.Context(), "error loading workflow %s after step review: %s", workflowId, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	sanitizeWorkflowForUserWithOptions(workflow, *userDid, isAdmin, true, false)
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(workflow)
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/SFLuv/app/backend/structs"
+)
+
+func TestIsWorkflowStepImprover(t *testing.T) {
+	improver := "did:privy:improver"
+	other := "did:privy:other"
+	workflow := &structs.Workflow{
+		Steps: []structs.WorkflowStep{
+			{Id: "step-1", AssignedImproverId: &improver},
+			{Id: "step-2", AssignedImproverId: &other},
+			{Id: "step-3"},
+		},
+	}
+
+	cases := []struct {
+		name   string
+		stepID string
+		userID string
+		want   bool
+	}{
+		{name: "own step", stepID: "step-1", userID: improver, want: true},
+		{name: "someone else's step", stepID: "step-2", userID: improver},
+		{name: "unassigned step", stepID: "step-3", userID: improver},
+		{name: "unknown step", stepID: "step-4", userID: improver},
+		{name: "no user", stepID: "step-1", userID: ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isWorkflowStepImprover(workflow, tc.stepID, tc.userID); got != tc.want {
+				t.Fatalf("isWorkflowStepImprover(%s, %q) = %v; want %v", tc.stepID, tc.userID, got, tc.want)
+			}
+		})
+	}
+}
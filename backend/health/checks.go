@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/SFLuv/app/backend/bot"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Pool pings a database pool and reports its connection counts.
+func Pool(pool *pgxpool.Pool) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		if pool == nil {
+			return nil, fmt.Errorf("pool is not configured")
+		}
+		stat := pool.Stat()
+		detail := map[string]int32{
+			"total_connections":    stat.TotalConns(),
+			"idle_connections":     stat.IdleConns(),
+			"acquired_connections": stat.AcquiredConns(),
+			"max_connections":      stat.MaxConns(),
+		}
+		if err := pool.Ping(ctx); err != nil {
+			return detail, fmt.Errorf("ping failed: %w", err)
+		}
+		return detail, nil
+	}
+}
+
+// RPC checks that the read RPC answers and serves the expected chain. The
+// URL is left out of the report since provider URLs often embed API keys.
+func RPC(url string, chainID int64) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		if url == "" {
+			return nil, fmt.Errorf("read rpc url is not configured")
+		}
+		client, err := ethclient.DialContext(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("error dialing rpc: %w", err)
+		}
+		defer client.Close()
+
+		gotChainID, err := client.ChainID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting chain id: %w", err)
+		}
+		block, err := client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting block number: %w", err)
+		}
+		detail := map[string]any{"chain_id": gotChainID.Int64(), "block": block}
+		if chainID > 0 && gotChainID.Int64() != chainID {
+			return detail, fmt.Errorf("rpc serves chain %d, expected %d", gotChainID.Int64(), chainID)
+		}
+		return detail, nil
+	}
+}
+
+// BotThresholds are the balances below which a bot check fails, in whole
+// tokens and whole native units. Zero disables a threshold.
+type BotThresholds struct {
+	MinTokens float64
+	MinGas    float64
+}
+
+// Bot reports a payout bot's key address, token balance, gas balance and
+// pending transactions, and fails when either balance is under its
+// threshold.
+func Bot(b bot.IBot, thresholds BotThresholds) CheckFunc {
+	return func(ctx context.Context) (any, error) {
+		if b == nil {
+			return nil, fmt.Errorf("bot is not configured")
+		}
+		detail := map[string]any{
+			"chain_id":             b.ChainID(),
+			"pending_transactions": b.PendingTransactions(),
+		}
+		address := b.Key()
+		if address == "" {
+			return detail, fmt.Errorf("bot key is not available")
+		}
+		detail["address"] = address
+
+		tokenBalance, err := b.Balance(ctx)
+		if err != nil {
+			return detail, fmt.Errorf("error getting token balance: %w", err)
+		}
+		tokens := WholeUnits(tokenBalance, b.TokenDecimals())
+		detail["token_balance"] = tokens
+
+		gasBalance, err := b.GasBalance(ctx)
+		if err != nil {
+			return detail, fmt.Errorf("error getting gas balance: %w", err)
+		}
+		gas := WholeUnits(gasBalance, 18)
+		detail["gas_balance"] = gas
+
+		if thresholds.MinTokens > 0 && tokens < thresholds.MinTokens {
+			return detail, fmt.Errorf("token balance %g is below %g", tokens, thresholds.MinTokens)
+		}
+		if thresholds.MinGas > 0 && gas < thresholds.MinGas {
+			return detail, fmt.Errorf("gas balance %g is below %g", gas, thresholds.MinGas)
+		}
+		return detail, nil
+	}
+}
+
+// WholeUnits scales a base-unit amount down by decimals.
+func WholeUnits(amount *big.Int, decimals int) float64 {
+	if amount == nil {
+		return 0
+	}
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	units, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), scale).Float64()
+	return units
+}
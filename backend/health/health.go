@@ -0,0 +1,161 @@
+// Package health serves the liveness and readiness probes. /healthz only
+// says the process is up and serving; /readyz runs every registered
+// dependency check and reports each one, so load balancers and the deploy
+// script can hold traffic until the databases, RPC, bot key and schema are
+// all where this build expects them.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultCheckTimeout = 5 * time.Second
+
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusFailed      = "failed"
+)
+
+// CheckFunc runs one dependency check. The returned detail is reported as is
+// alongside the check's status, whether or not it failed.
+type CheckFunc func(ctx context.Context) (any, error)
+
+type check struct {
+	name     string
+	critical bool
+	run      CheckFunc
+}
+
+type Checker struct {
+	mu        sync.RWMutex
+	checks    []check
+	timeout   time.Duration
+	startedAt time.Time
+	version   string
+}
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	Critical   bool   `json:"critical"`
+	DurationMs int64  `json:"duration_ms"`
+	Detail     any    `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type Report struct {
+	Status        string                  `json:"status"`
+	Version       string                  `json:"version,omitempty"`
+	UptimeSeconds int64                   `json:"uptime_seconds"`
+	Checks        map[string]*CheckResult `json:"checks,omitempty"`
+}
+
+// New returns a Checker reporting version, the schema version this build
+// expects, on both probes.
+func New(version string) *Checker {
+	return &Checker{timeout: defaultCheckTimeout, startedAt: time.Now(), version: version}
+}
+
+// Add registers a readiness check. A failing critical check makes the
+// service unavailable; a failing non-critical one only degrades it, which
+// still answers 200 so a low faucet balance alone never pulls the API out of
+// rotation.
+func (c *Checker) Add(name string, critical bool, run CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, critical: critical, run: run})
+}
+
+// Run executes every check concurrently, each under the checker's timeout.
+func (c *Checker) Run(ctx context.Context) *Report {
+	c.mu.RLock()
+	checks := append([]check(nil), c.checks...)
+	c.mu.RUnlock()
+
+	results := make([]*CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, chk := range checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			results[i] = c.runCheck(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	report := c.report()
+	report.Checks = make(map[string]*CheckResult, len(checks))
+	for i, chk := range checks {
+		result := results[i]
+		report.Checks[chk.name] = result
+		if result.Status == StatusOK {
+			continue
+		}
+		if chk.critical {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (c *Checker) runCheck(ctx context.Context, chk check) (result *CheckResult) {
+	start := time.Now()
+	result = &CheckResult{Status: StatusOK, Critical: chk.critical}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result.Status = StatusFailed
+			result.Error = "check panicked"
+		}
+		result.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	detail, err := chk.run(checkCtx)
+	result.Detail = detail
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (c *Checker) report() *Report {
+	return &Report{
+		Status:        StatusOK,
+		Version:       c.version,
+		UptimeSeconds: int64(time.Since(c.startedAt).Seconds()),
+	}
+}
+
+// Liveness answers 200 as long as the process can serve requests. It runs
+// no dependency checks so a database outage never gets the process
+// restarted.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, http.StatusOK, c.report())
+}
+
+// Readiness answers 200 when every critical check passes and 503 otherwise,
+// with the per-check breakdown in the body either way.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+	status := http.StatusOK
+	if report.Status == StatusUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	writeReport(w, status, report)
+}
+
+func writeReport(w http.ResponseWriter, status int, report *Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func ok(ctx context.Context) (any, error) { return "fine", nil }
+
+func failing(ctx context.Context) (any, error) { return nil, errors.New("down") }
+
+func readiness(t *testing.T, c *Checker) (int, *Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	c.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	report := &Report{}
+	if err := json.Unmarshal(rec.Body.Bytes(), report); err != nil {
+		t.Fatalf("decoding report: %s", err)
+	}
+	return rec.Code, report
+}
+
+func TestReadinessStatuses(t *testing.T) {
+	c := New("1.31")
+	c.Add("app_db", true, ok)
+	code, report := readiness(t, c)
+	if code != http.StatusOK || report.Status != StatusOK || report.Version != "1.31" {
+		t.Fatalf("expected ok, got %d %+v", code, report)
+	}
+
+	c.Add("bot_1", false, failing)
+	code, report = readiness(t, c)
+	if code != http.StatusOK || report.Status != StatusDegraded {
+		t.Fatalf("expected degraded 200 for a non-critical failure, got %d %s", code, report.Status)
+	}
+	if result := report.Checks["bot_1"]; result == nil || result.Status != StatusFailed || result.Error != "down" {
+		t.Fatalf("unexpected bot_1 result: %+v", result)
+	}
+
+	c.Add("rpc", true, func(ctx context.Context) (any, error) { panic("boom") })
+	code, report = readiness(t, c)
+	if code != http.StatusServiceUnavailable || report.Status != StatusUnavailable {
+		t.Fatalf("expected 503 for a critical failure, got %d %s", code, report.Status)
+	}
+	if report.Checks["app_db"].Detail != "fine" {
+		t.Fatalf("expected passing checks to keep their detail, got %+v", report.Checks["app_db"])
+	}
+}
+
+func TestChecksTimeOut(t *testing.T) {
+	c := New("")
+	c.timeout = 10 * time.Millisecond
+	c.Add("slow", true, func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if report := c.Run(context.Background()); report.Status != StatusUnavailable {
+		t.Fatalf("expected a timed out check to fail, got %s", report.Status)
+	}
+}
+
+func TestLivenessSkipsChecks(t *testing.T) {
+	c := New("")
+	c.Add("app_db", true, failing)
+	rec := httptest.NewRecorder()
+	c.Liveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected liveness to ignore dependency checks, got %d", rec.Code)
+	}
+}
+
+func TestWholeUnits(t *testing.T) {
+	amount, _ := new(big.Int).SetString("1500000000000000000", 10)
+	if got := WholeUnits(amount, 18); got != 1.5 {
+		t.Fatalf("WholeUnits = %v; want 1.5", got)
+	}
+}
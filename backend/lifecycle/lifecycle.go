@@ -0,0 +1,200 @@
+// Package lifecycle tracks the server's background workers so shutdown can
+// happen in order: stop handing out new work, wait for whatever is in flight
+// (payout transfers in particular) to reach a recorded state, and only then
+// release shared resources such as the database pools.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hardStopGrace is how long Shutdown keeps waiting for workers after their
+// work context is cancelled at the drain deadline.
+const hardStopGrace = 5 * time.Second
+
+// Manager owns two contexts. Context is cancelled as soon as shutdown begins
+// and tells loops to stop picking up new work. WorkContext stays live until
+// the drain deadline passes, so a transfer that is already out can still be
+// recorded after SIGTERM.
+//
+// A nil *Manager is valid: Go runs fn untracked with a background context,
+// which keeps services built without one (tests, the init command) working.
+type Manager struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	workCtx    context.Context
+	workCancel context.CancelFunc
+
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	running  map[string]int
+	closers  []func()
+	draining bool
+}
+
+func New(parent context.Context) *Manager {
+	ctx, cancel := context.WithCancel(parent)
+	workCtx, workCancel := context.WithCancel(context.WithoutCancel(parent))
+	return &Manager{
+		ctx:        ctx,
+		cancel:     cancel,
+		workCtx:    workCtx,
+		workCancel: workCancel,
+		running:    map[string]int{},
+	}
+}
+
+// Context is cancelled when shutdown begins.
+func (m *Manager) Context() context.Context {
+	if m == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// WorkContext is cancelled once Shutdown stops waiting for workers.
+func (m *Manager) WorkContext() context.Context {
+	if m == nil {
+		return context.Background()
+	}
+	return m.workCtx
+}
+
+// Draining reports whether shutdown has begun.
+func (m *Manager) Draining() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.draining
+}
+
+// Go runs fn on a tracked goroutine, passing it Context. It returns false
+// without running fn once shutdown has begun.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) bool {
+	if m == nil {
+		go fn(context.Background())
+		return true
+	}
+
+	done, ok := m.Begin(name)
+	if !ok {
+		return false
+	}
+	go func() {
+		defer done()
+		fn(m.ctx)
+	}()
+	return true
+}
+
+// Begin marks a unit of work as in flight until done is called. ok is false
+// once shutdown has begun, in which case the work should not be started.
+func (m *Manager) Begin(name string) (done func(), ok bool) {
+	if m == nil {
+		return func() {}, true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.draining {
+		return func() {}, false
+	}
+	m.wg.Add(1)
+	m.running[name]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			m.running[name]--
+			if m.running[name] <= 0 {
+				delete(m.running, name)
+			}
+			m.mu.Unlock()
+			m.wg.Done()
+		})
+	}, true
+}
+
+// OnClose registers fn to run after every worker has stopped. Closers run in
+// the reverse of the order they were registered in, so register the pools
+// before anything that depends on them.
+func (m *Manager) OnClose(fn func()) {
+	if m == nil || fn == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closers = append(m.closers, fn)
+}
+
+// Running lists the workers still in flight, with a count where more than one
+// shares a name.
+func (m *Manager) Running() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.running))
+	for name, count := range m.running {
+		if count > 1 {
+			name = fmt.Sprintf("%s (%d)", name, count)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shutdown stops new work, waits until every tracked worker returns or ctx
+// expires, then runs the closers. When ctx expires first the work context is
+// cancelled and workers get a short grace period to record where they got to;
+// the returned error names any that were still running.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	m.draining = true
+	m.mu.Unlock()
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		m.workCancel()
+		select {
+		case <-done:
+		case <-time.After(hardStopGrace):
+			err = fmt.Errorf("shutdown deadline passed with workers still running: %s", strings.Join(m.Running(), ", "))
+		}
+	}
+	m.workCancel()
+
+	m.mu.Lock()
+	closers := m.closers
+	m.closers = nil
+	m.mu.Unlock()
+	for i := len(closers) - 1; i >= 0; i-- {
+		closers[i]()
+	}
+
+	return err
+}
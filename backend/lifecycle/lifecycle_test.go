@@ -0,0 +1,87 @@
+package lifecycle
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdownWaitsForWorkersThenCloses(t *testing.T) {
+	m := New(context.Background())
+
+	var order []string
+	m.OnClose(func() { order = append(order, "pools") })
+	m.OnClose(func() { order = append(order, "logger") })
+
+	released := make(chan struct{})
+	m.Go("loop", func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	done, ok := m.Begin("transfer")
+	if !ok {
+		t.Fatal("expected Begin to succeed before shutdown")
+	}
+	go func() {
+		<-released
+		order = append(order, "transfer")
+		done()
+	}()
+
+	result := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		result <- m.Shutdown(ctx)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if !m.Draining() {
+		t.Fatal("expected manager to be draining")
+	}
+	if m.WorkContext().Err() != nil {
+		t.Fatal("work context cancelled before in-flight transfer finished")
+	}
+	if _, ok := m.Begin("transfer"); ok {
+		t.Fatal("expected Begin to refuse new work while draining")
+	}
+	if m.Go("late", func(context.Context) {}) {
+		t.Fatal("expected Go to refuse new work while draining")
+	}
+	close(released)
+
+	if err := <-result; err != nil {
+		t.Fatalf("unexpected shutdown error: %s", err)
+	}
+	if got := strings.Join(order, ","); got != "transfer,logger,pools" {
+		t.Fatalf("expected transfer to finish before closers ran in reverse, got %s", got)
+	}
+	if m.WorkContext().Err() == nil {
+		t.Fatal("expected work context to be cancelled after shutdown")
+	}
+}
+
+func TestShutdownDeadlineCancelsWorkContext(t *testing.T) {
+	m := New(context.Background())
+	m.Go("payout", func(context.Context) {
+		<-m.WorkContext().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("expected worker to stop once its work context was cancelled, got %s", err)
+	}
+}
+
+func TestNilManagerRunsUntracked(t *testing.T) {
+	var m *Manager
+	ran := make(chan struct{})
+	if !m.Go("loop", func(context.Context) { close(ran) }) {
+		t.Fatal("expected nil manager to run fn")
+	}
+	<-ran
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
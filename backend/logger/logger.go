@@ -1,51 +1,187 @@
+// Package logger provides the app's structured logger. It is a thin layer
+// over log/slog that picks its format, level and destination from the
+// environment and stamps every record with the request ID carried by the
+// context, so one redemption or payout can be followed across handlers, the
+// bot, the database and background jobs.
+//
+//	LOG_FORMAT       json (default) or text
+//	LOG_OUTPUT       file (default) writes to the path passed to New and
+//	                 rotates it; stdout writes to standard output
+//	LOG_LEVEL        debug, info (default), warn or error
+//	LOG_MAX_SIZE_MB  size a log file may reach before it is rotated (100)
+//	LOG_MAX_BACKUPS  rotated files kept next to the live one (5)
 package logger
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/SFLuv/app/backend/utils"
 )
 
+const (
+	defaultMaxSizeMB  = 100
+	defaultMaxBackups = 5
+)
+
 type LogCloser struct {
-	logger *log.Logger
-	file   *os.File
+	logger *slog.Logger
+	closer io.Closer
 }
 
 // New dynamically finds the root directory of your project. Pass in a relative path as though ./ is your project's root.
+// The path is only used when LOG_OUTPUT is file. prefix names the service
+// ("APP: " becomes service=app) on every record.
 func New(relativePath string, prefix string) (*LogCloser, error) {
-	root, err := utils.GetProjectRoot()
-	if err != nil {
-		return nil, err
-	}
+	var out io.Writer = os.Stdout
+	var closer io.Closer
+	if !strings.EqualFold(strings.TrimSpace(os.Getenv("LOG_OUTPUT")), "stdout") {
+		root, err := utils.GetProjectRoot()
+		if err != nil {
+			return nil, err
+		}
 
-	path := path.Join(root, relativePath)
-	dirPath := filepath.Dir(path)
-	if !utils.Exists(dirPath) {
-		err := os.MkdirAll(dirPath, 0755)
+		path := path.Join(root, relativePath)
+		dirPath := filepath.Dir(path)
+		if !utils.Exists(dirPath) {
+			err := os.MkdirAll(dirPath, 0755)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		file, err := newRotatingFile(path, int64(envInt("LOG_MAX_SIZE_MB", defaultMaxSizeMB))*1024*1024, envInt("LOG_MAX_BACKUPS", defaultMaxBackups))
 		if err != nil {
 			return nil, err
 		}
+		out = file
+		closer = file
 	}
 
-	logFile, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return nil, err
+	handler := NewHandler(out, os.Getenv("LOG_FORMAT"), ParseLevel(os.Getenv("LOG_LEVEL")))
+	l := slog.New(handler)
+	if service := serviceName(prefix); service != "" {
+		l = l.With("service", service)
 	}
+	return &LogCloser{logger: l, closer: closer}, nil
+}
 
-	logger := log.New(logFile, prefix, log.Ldate|log.Ltime|log.Llongfile)
+// NewHandler returns a json or text handler writing to out that adds the
+// context's request ID to each record.
+func NewHandler(out io.Writer, format string, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{AddSource: true, Level: level}
+	var handler slog.Handler
+	if strings.EqualFold(strings.TrimSpace(format), "text") {
+		handler = slog.NewTextHandler(out, opts)
+	} else {
+		handler = slog.NewJSONHandler(out, opts)
+	}
+	return &contextHandler{Handler: handler}
+}
 
-	return &LogCloser{logger: logger, file: logFile}, nil
+// ParseLevel reads a LOG_LEVEL value, defaulting to info.
+func ParseLevel(value string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func serviceName(prefix string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(prefix), ":"))
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
 }
 
-func (l *LogCloser) Logf(message string, a ...any) {
-	formatted := fmt.Sprintf(message, a...)
-	l.logger.Printf("\n	%s\n", formatted)
+// Slog returns the underlying logger, for handing to code that takes a
+// *slog.Logger or for slog.SetDefault.
+func (l *LogCloser) Slog() *slog.Logger {
+	if l == nil || l.logger == nil {
+		return slog.Default()
+	}
+	return l.logger
+}
+
+// With returns a logger that adds args to every record.
+func (l *LogCloser) With(args ...any) *LogCloser {
+	return &LogCloser{logger: l.Slog().With(args...)}
+}
+
+func (l *LogCloser) Debug(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelDebug, msg, args...)
+}
+
+func (l *LogCloser) Info(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelInfo, msg, args...)
+}
+
+func (l *LogCloser) Warn(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelWarn, msg, args...)
+}
+
+func (l *LogCloser) Error(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelError, msg, args...)
+}
+
+// Debugf, Infof, Warnf and Errorf log a printf style message at their level.
+// Pass the request or job context so the record carries its request ID.
+func (l *LogCloser) Debugf(ctx context.Context, format string, a ...any) {
+	l.log(ctx, slog.LevelDebug, fmt.Sprintf(format, a...))
+}
+
+func (l *LogCloser) Infof(ctx context.Context, format string, a ...any) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprintf(format, a...))
+}
+
+func (l *LogCloser) Warnf(ctx context.Context, format string, a ...any) {
+	l.log(ctx, slog.LevelWarn, fmt.Sprintf(format, a...))
+}
+
+func (l *LogCloser) Errorf(ctx context.Context, format string, a ...any) {
+	l.log(ctx, slog.LevelError, fmt.Sprintf(format, a...))
+}
+
+// log records the caller of the exported method rather than this file as
+// the source.
+func (l *LogCloser) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logger := l.Slog()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.Add(args...)
+	_ = logger.Handler().Handle(ctx, record)
 }
 
 func (l *LogCloser) Close() error {
-	return l.file.Close()
+	if l == nil || l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
 }
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestLogger(buf *bytes.Buffer, level slog.Level) *LogCloser {
+	return &LogCloser{logger: slog.New(NewHandler(buf, "json", level)).With("service", "test")}
+}
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	records := []map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("decoding %q: %s", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRecordsCarryRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, slog.LevelInfo)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	l.Info(ctx, "redeemed code", "code", "abc")
+	l.Errorf(ctx, "error sending payout for %s", "wf-1")
+	l.Info(context.Background(), "no request")
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	if records[0]["request_id"] != "req-1" || records[0]["code"] != "abc" || records[0]["service"] != "test" {
+		t.Fatalf("unexpected first record: %v", records[0])
+	}
+	if records[1]["request_id"] != "req-1" || records[1]["level"] != "ERROR" {
+		t.Fatalf("unexpected Errorf record: %v", records[1])
+	}
+	if _, ok := records[2]["request_id"]; ok {
+		t.Fatalf("expected no request_id without one in context: %v", records[2])
+	}
+
+	source, _ := records[0]["source"].(map[string]any)
+	if file, _ := source["file"].(string); !strings.HasSuffix(file, "logger_test.go") {
+		t.Fatalf("expected source to point at the caller, got %v", records[0]["source"])
+	}
+}
+
+func TestLevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, ParseLevel("warn"))
+
+	l.Debug(context.Background(), "debug")
+	l.Infof(context.Background(), "loaded client config")
+	l.Warn(context.Background(), "warn")
+	l.Warnf(context.Background(), "retrying payout without an error in the message")
+	l.Errorf(context.Background(), "leasing jobs: %s", "boom")
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 3 || records[0]["msg"] != "warn" || records[1]["level"] != "WARN" || records[2]["level"] != "ERROR" {
+		t.Fatalf("unexpected records: %v", records)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]slog.Level{
+		"":        slog.LevelInfo,
+		"DEBUG":   slog.LevelDebug,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+		"verbose": slog.LevelInfo,
+	}
+	for in, want := range cases {
+		if got := ParseLevel(in); got != want {
+			t.Fatalf("ParseLevel(%q) = %s; want %s", in, got, want)
+		}
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	if !ValidRequestID("7f3c1a2e-req") {
+		t.Fatalf("expected a plain id to be valid")
+	}
+	for _, id := range []string{"", "has space", "line\nbreak", strings.Repeat("a", maxRequestIDLength+1)} {
+		if ValidRequestID(id) {
+			t.Fatalf("expected %q to be invalid", id)
+		}
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := newRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("newRotatingFile: %s", err)
+	}
+	for _, line := range []string{"first---\n", "second--\n", "third---\n", "fourth--\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %s", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Close: %s", err)
+	}
+
+	want := map[string]string{
+		path:        "fourth--\n",
+		path + ".1": "third---\n",
+		path + ".2": "second--\n",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("reading %s: %s", name, err)
+		}
+		if string(got) != content {
+			t.Fatalf("%s = %q; want %q", name, got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected only %d backups to be kept", 2)
+	}
+}
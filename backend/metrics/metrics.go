@@ -0,0 +1,211 @@
+// Package metrics exposes runtime telemetry in the Prometheus format on
+// /metrics: HTTP traffic per route, database pool usage, faucet bot balances
+// and pending transactions, and the outcomes of payouts, redemptions, push
+// tickets and emails. Everything registers on Registry rather than the
+// global Prometheus registry so tests and tools can gather it directly.
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "sfluv"
+
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by route pattern.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "route"})
+
+	botBalance = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "bot_balance_tokens",
+		Help:      "Faucet bot token balance in whole tokens, by chain.",
+	}, []string{"chain_id"})
+
+	botBalanceErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bot_balance_errors_total",
+		Help:      "Failed faucet bot balance reads, by chain.",
+	}, []string{"chain_id"})
+
+	botPendingTransactions = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "bot_pending_transactions",
+		Help:      "Bot transactions broadcast but not yet mined, by chain.",
+	}, []string{"chain_id"})
+
+	workflowPayouts = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "workflow_payouts_total",
+		Help:      "Workflow payout job runs, by target (step or supervisor) and result.",
+	}, []string{"target", "result"})
+
+	redemptions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redemptions_total",
+		Help:      "Faucet code redemption payouts, by result.",
+	}, []string{"result"})
+
+	pushTickets = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "push_tickets_total",
+		Help:      "Push sends, by provider and ticket status or error code.",
+	}, []string{"provider", "outcome"})
+
+	pushReceipts = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "push_receipts_total",
+		Help:      "Push delivery receipts, by provider and receipt status or error code.",
+	}, []string{"provider", "outcome"})
+
+	emailSends = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "email_sends_total",
+		Help:      "Outbox email delivery attempts, by sink and result.",
+	}, []string{"sink", "result"})
+
+	emailBounces = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "email_bounces_total",
+		Help:      "Sent emails later reported as bounced by the provider.",
+	})
+
+	rateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected with 429, by rate limit group.",
+	}, []string{"group"})
+)
+
+// Payout and send results used as label values.
+const (
+	ResultPaid         = "paid"
+	ResultRetried      = "retried"
+	ResultDeferred     = "deferred"
+	ResultFailed       = "failed"
+	ResultDeadLettered = "dead_lettered"
+	ResultSent         = "sent"
+)
+
+// Handler serves Registry to scrapes that send METRICS_TOKEN as a bearer
+// token. Without METRICS_TOKEN the endpoint is not served at all.
+func Handler() http.Handler {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSpace(os.Getenv("METRICS_TOKEN"))
+		if token == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), []byte(token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Middleware records the status and latency of every request under its chi
+// route pattern, so /workflows/{workflow_id} is one series rather than one
+// per workflow. Requests no route matched share the "unmatched" route.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					route = pattern
+				}
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			httpRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+			httpDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
+func chainLabel(chainID int64) string {
+	return strconv.FormatInt(chainID, 10)
+}
+
+func SetBotBalance(chainID int64, tokens float64) {
+	botBalance.WithLabelValues(chainLabel(chainID)).Set(tokens)
+}
+
+func BotBalanceError(chainID int64) {
+	botBalanceErrors.WithLabelValues(chainLabel(chainID)).Inc()
+}
+
+func SetBotPendingTransactions(chainID int64, count int) {
+	botPendingTransactions.WithLabelValues(chainLabel(chainID)).Set(float64(count))
+}
+
+// WorkflowPayout counts one payout job run ending in result.
+func WorkflowPayout(target string, result string) {
+	workflowPayouts.WithLabelValues(target, result).Inc()
+}
+
+func Redemption(result string) {
+	redemptions.WithLabelValues(result).Inc()
+}
+
+// PushTicket counts a push send. outcome is the ticket status, or its error
+// code when the provider rejected the message.
+func PushTicket(provider string, outcome string) {
+	pushTickets.WithLabelValues(provider, outcome).Inc()
+}
+
+func PushReceipt(provider string, outcome string) {
+	pushReceipts.WithLabelValues(provider, outcome).Inc()
+}
+
+func EmailSend(sink string, result string) {
+	emailSends.WithLabelValues(sink, result).Inc()
+}
+
+func EmailBounce() {
+	emailBounces.Inc()
+}
+
+func RateLimited(group string) {
+	rateLimited.WithLabelValues(group).Inc()
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector reads pgxpool statistics at scrape time.
+type poolCollector struct {
+	name string
+	pool *pgxpool.Pool
+}
+
+var (
+	poolAcquiredConns = prometheus.NewDesc(namespace+"_db_pool_acquired_connections", "Connections currently checked out of the pool.", []string{"pool"}, nil)
+	poolIdleConns     = prometheus.NewDesc(namespace+"_db_pool_idle_connections", "Idle connections in the pool.", []string{"pool"}, nil)
+	poolTotalConns    = prometheus.NewDesc(namespace+"_db_pool_total_connections", "Connections in the pool, including ones still being opened.", []string{"pool"}, nil)
+	poolMaxConns      = prometheus.NewDesc(namespace+"_db_pool_max_connections", "Configured maximum pool size.", []string{"pool"}, nil)
+	poolAcquires      = prometheus.NewDesc(namespace+"_db_pool_acquires_total", "Successful connection acquires.", []string{"pool"}, nil)
+	poolEmptyAcquires = prometheus.NewDesc(namespace+"_db_pool_empty_acquires_total", "Acquires that had to wait because the pool was empty.", []string{"pool"}, nil)
+	poolCanceled      = prometheus.NewDesc(namespace+"_db_pool_canceled_acquires_total", "Acquires canceled by their context.", []string{"pool"}, nil)
+	poolAcquireTime   = prometheus.NewDesc(namespace+"_db_pool_acquire_duration_seconds_total", "Time spent acquiring connections.", []string{"pool"}, nil)
+)
+
+// RegisterDBPool exposes the statistics of pool under the pool label name.
+// Registering the same name twice is an error.
+func RegisterDBPool(name string, pool *pgxpool.Pool) error {
+	if pool == nil {
+		return nil
+	}
+	return Registry.Register(&poolCollector{name: name, pool: pool})
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquires
+	ch <- poolEmptyAcquires
+	ch <- poolCanceled
+	ch <- poolAcquireTime
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(stat.IdleConns()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(stat.TotalConns()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(stat.MaxConns()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(stat.AcquireCount()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolCanceled, prometheus.CounterValue, float64(stat.CanceledAcquireCount()), c.name)
+	ch <- prometheus.MustNewConstMetric(poolAcquireTime, prometheus.CounterValue, stat.AcquireDuration().Seconds(), c.name)
+}
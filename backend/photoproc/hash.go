@@ -0,0 +1,37 @@
+package photoproc
+
+import (
+	"image"
+	"math/bits"
+)
+
+// DifferenceHash is a 64-bit perceptual hash of img: it is shrunk to 9x8
+// grayscale and each bit records whether a pixel is brighter than its right
+// neighbour. Re-encoding, resizing and mild edits change only a few bits.
+func DifferenceHash(img image.Image) uint64 {
+	small := resize(flatten(img), 9, 8)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if luminance(small, x, y) > luminance(small, x+1, y) {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// Distance is the number of bits that differ between two hashes.
+func Distance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func luminance(img *image.RGBA, x int, y int) uint32 {
+	offset := img.PixOffset(x, y)
+	r := uint32(img.Pix[offset])
+	g := uint32(img.Pix[offset+1])
+	b := uint32(img.Pix[offset+2])
+	return 299*r + 587*g + 114*b
+}
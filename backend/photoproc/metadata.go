@@ -0,0 +1,361 @@
+package photoproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+const (
+	tiffTagOrientation = 0x0112
+	tiffTagGPSInfo     = 0x8825
+
+	gpsTagLatitudeRef  = 1
+	gpsTagLatitude     = 2
+	gpsTagLongitudeRef = 3
+	gpsTagLongitude    = 4
+
+	tiffTypeASCII    = 2
+	tiffTypeRational = 5
+)
+
+var (
+	jpegExifHeader  = []byte("Exif\x00\x00")
+	jpegXMPHeader   = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	pngSignature    = []byte("\x89PNG\r\n\x1a\n")
+	gifSignature87a = []byte("GIF87a")
+	gifSignature89a = []byte("GIF89a")
+	xmpGPSMarker    = []byte("GPS")
+
+	errMalformedTIFF = errors.New("malformed tiff metadata")
+)
+
+type metadata struct {
+	Orientation      int
+	LocationStripped bool
+	Location         *Location
+}
+
+// scrubMetadata returns a copy of a JPEG or PNG with its GPS metadata
+// removed when stripGPS is set, along with its EXIF orientation and the GPS
+// position it held. EXIF it cannot parse is dropped whole, and XMP packets
+// mentioning GPS are dropped too since they can carry the same coordinates.
+// GIFs, which have no EXIF, are returned unchanged. For any other format ok
+// is false and data is returned untouched, since its metadata cannot be read.
+func scrubMetadata(data []byte, stripGPS bool) ([]byte, metadata, bool) {
+	switch {
+	case len(data) > 2 && data[0] == 0xFF && data[1] == 0xD8:
+		scrubbed, meta := scrubJPEG(data, stripGPS)
+		return scrubbed, meta, true
+	case bytes.HasPrefix(data, pngSignature):
+		scrubbed, meta := scrubPNG(data, stripGPS)
+		return scrubbed, meta, true
+	case bytes.HasPrefix(data, gifSignature87a) || bytes.HasPrefix(data, gifSignature89a):
+		return data, metadata{}, true
+	}
+	return data, metadata{}, false
+}
+
+func scrubJPEG(data []byte, stripGPS bool) ([]byte, metadata) {
+	meta := metadata{}
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			break
+		}
+		marker := data[pos+1]
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+		// Start of scan: entropy-coded data follows, with no more metadata.
+		if marker == 0xDA {
+			break
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out = append(out, data[pos:pos+2]...)
+			pos += 2
+			continue
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			break
+		}
+		segment := data[pos:end]
+		payload := segment[4:]
+
+		if marker == 0xE1 && bytes.HasPrefix(payload, jpegExifHeader) {
+			tiff := append([]byte(nil), payload[len(jpegExifHeader):]...)
+			tiffMeta, err := scrubTIFF(tiff, stripGPS)
+			if err != nil {
+				if stripGPS {
+					meta.LocationStripped = true
+					pos = end
+					continue
+				}
+			} else {
+				meta.Orientation = tiffMeta.Orientation
+				meta.Location = tiffMeta.Location
+				if tiffMeta.LocationStripped {
+					meta.LocationStripped = true
+					rewritten := make([]byte, 0, len(segment))
+					rewritten = append(rewritten, segment[:4+len(jpegExifHeader)]...)
+					segment = append(rewritten, tiff...)
+				}
+			}
+		}
+		if stripGPS && marker == 0xE1 && bytes.HasPrefix(payload, jpegXMPHeader) && bytes.Contains(payload, xmpGPSMarker) {
+			meta.LocationStripped = true
+			pos = end
+			continue
+		}
+
+		out = append(out, segment...)
+		pos = end
+	}
+	out = append(out, data[pos:]...)
+	return out, meta
+}
+
+func scrubPNG(data []byte, stripGPS bool) ([]byte, metadata) {
+	meta := metadata{}
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+
+	pos := len(pngSignature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			break
+		}
+		chunkType := string(data[pos+4 : pos+8])
+		chunk := data[pos:end]
+		body := chunk[8 : 8+length]
+
+		switch {
+		case chunkType == "eXIf":
+			tiff := append([]byte(nil), body...)
+			tiffMeta, err := scrubTIFF(tiff, stripGPS)
+			if err != nil {
+				if stripGPS {
+					meta.LocationStripped = true
+					pos = end
+					continue
+				}
+			} else {
+				meta.Orientation = tiffMeta.Orientation
+				meta.Location = tiffMeta.Location
+				if tiffMeta.LocationStripped {
+					meta.LocationStripped = true
+					chunk = pngChunk(chunkType, tiff)
+				}
+			}
+		case stripGPS && chunkType == "iTXt" && bytes.HasPrefix(body, []byte("XML:com.adobe.xmp\x00")) && bytes.Contains(body, xmpGPSMarker):
+			meta.LocationStripped = true
+			pos = end
+			continue
+		}
+
+		out = append(out, chunk...)
+		pos = end
+	}
+	out = append(out, data[pos:]...)
+	return out, meta
+}
+
+func pngChunk(chunkType string, body []byte) []byte {
+	chunk := make([]byte, 8, 12+len(body))
+	binary.BigEndian.PutUint32(chunk[:4], uint32(len(body)))
+	copy(chunk[4:8], chunkType)
+	chunk = append(chunk, body...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// scrubTIFF reads the orientation and GPS position from an EXIF TIFF block
+// and, when stripGPS is set, blanks its GPS IFD in place. The IFD is left as
+// an empty directory so no other offsets move.
+func scrubTIFF(tiff []byte, stripGPS bool) (metadata, error) {
+	if len(tiff) < 8 {
+		return metadata{}, errMalformedTIFF
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return metadata{}, errMalformedTIFF
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return metadata{}, errMalformedTIFF
+	}
+
+	ifd0 := int(order.Uint32(tiff[4:8]))
+	entries, err := tiffEntries(tiff, order, ifd0)
+	if err != nil {
+		return metadata{}, err
+	}
+
+	meta := metadata{}
+	gpsOffset := -1
+	for _, entry := range entries {
+		switch entry.tag {
+		case tiffTagOrientation:
+			meta.Orientation = int(order.Uint16(tiff[entry.valueAt : entry.valueAt+2]))
+		case tiffTagGPSInfo:
+			gpsOffset = int(order.Uint32(tiff[entry.valueAt : entry.valueAt+4]))
+		}
+	}
+	if gpsOffset < 0 {
+		return meta, nil
+	}
+
+	gpsEntries, err := tiffEntries(tiff, order, gpsOffset)
+	if err != nil {
+		if !stripGPS {
+			return meta, nil
+		}
+		return metadata{}, err
+	}
+	meta.Location = gpsLocation(tiff, order, gpsEntries)
+	if !stripGPS {
+		return meta, nil
+	}
+
+	for _, entry := range gpsEntries {
+		if entry.size > 4 {
+			clear(tiff[entry.dataAt : entry.dataAt+entry.size])
+		}
+	}
+	// Zero the entry count, the entries and the next-IFD link, leaving an
+	// empty directory.
+	clear(tiff[gpsOffset : gpsOffset+2+12*len(gpsEntries)+4])
+	meta.LocationStripped = true
+	return meta, nil
+}
+
+// gpsLocation reads the latitude and longitude from a GPS IFD. It returns
+// nil when either is missing or malformed, and for 0,0, which receivers
+// without a fix commonly write.
+func gpsLocation(tiff []byte, order binary.ByteOrder, entries []tiffEntry) *Location {
+	var latitudeRef, longitudeRef byte
+	var latitude, longitude []float64
+	for _, entry := range entries {
+		switch entry.tag {
+		case gpsTagLatitudeRef:
+			latitudeRef = tiffRef(tiff, entry)
+		case gpsTagLongitudeRef:
+			longitudeRef = tiffRef(tiff, entry)
+		case gpsTagLatitude:
+			latitude = tiffRationals(tiff, order, entry)
+		case gpsTagLongitude:
+			longitude = tiffRationals(tiff, order, entry)
+		}
+	}
+	if len(latitude) != 3 || len(longitude) != 3 {
+		return nil
+	}
+
+	location := &Location{
+		Latitude:  latitude[0] + latitude[1]/60 + latitude[2]/3600,
+		Longitude: longitude[0] + longitude[1]/60 + longitude[2]/3600,
+	}
+	switch latitudeRef {
+	case 'N':
+	case 'S':
+		location.Latitude = -location.Latitude
+	default:
+		return nil
+	}
+	switch longitudeRef {
+	case 'E':
+	case 'W':
+		location.Longitude = -location.Longitude
+	default:
+		return nil
+	}
+	if location.Latitude > 90 || location.Latitude < -90 || location.Longitude > 180 || location.Longitude < -180 {
+		return nil
+	}
+	if location.Latitude == 0 && location.Longitude == 0 {
+		return nil
+	}
+	return location
+}
+
+func tiffRef(tiff []byte, entry tiffEntry) byte {
+	if entry.typ != tiffTypeASCII || entry.size < 1 {
+		return 0
+	}
+	return tiff[entry.dataAt]
+}
+
+func tiffRationals(tiff []byte, order binary.ByteOrder, entry tiffEntry) []float64 {
+	if entry.typ != tiffTypeRational {
+		return nil
+	}
+	values := make([]float64, 0, entry.size/8)
+	for at := entry.dataAt; at+8 <= entry.dataAt+entry.size; at += 8 {
+		denominator := order.Uint32(tiff[at+4 : at+8])
+		if denominator == 0 {
+			return nil
+		}
+		values = append(values, float64(order.Uint32(tiff[at:at+4]))/float64(denominator))
+	}
+	return values
+}
+
+type tiffEntry struct {
+	tag     uint16
+	typ     uint16
+	valueAt int
+	dataAt  int
+	size    int
+}
+
+var tiffTypeSizes = map[uint16]int{
+	1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8,
+}
+
+func tiffEntries(tiff []byte, order binary.ByteOrder, offset int) ([]tiffEntry, error) {
+	if offset < 8 || offset+2 > len(tiff) {
+		return nil, errMalformedTIFF
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	if offset+2+12*count+4 > len(tiff) {
+		return nil, errMalformedTIFF
+	}
+
+	entries := make([]tiffEntry, 0, count)
+	for i := 0; i < count; i++ {
+		at := offset + 2 + 12*i
+		typ := order.Uint16(tiff[at+2 : at+4])
+		typeSize, ok := tiffTypeSizes[typ]
+		if !ok {
+			typeSize = 1
+		}
+		size := typeSize * int(order.Uint32(tiff[at+4:at+8]))
+		entry := tiffEntry{
+			tag:     order.Uint16(tiff[at : at+2]),
+			typ:     typ,
+			valueAt: at + 8,
+			dataAt:  at + 8,
+			size:    size,
+		}
+		if size > 4 {
+			entry.dataAt = int(order.Uint32(tiff[at+8 : at+12]))
+			if size < 0 || entry.dataAt < 0 || entry.dataAt+size > len(tiff) {
+				return nil, errMalformedTIFF
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,133 @@
+// Package photoproc prepares workflow photos at upload: it reads and then
+// strips GPS metadata, renders the thumbnail and medium sizes served in
+// place of the original, and computes a perceptual hash used to spot photos
+// reused across submissions.
+package photoproc
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	VariantThumbnail = "thumbnail"
+	VariantMedium    = "medium"
+
+	ThumbnailMaxDimension = 320
+	MediumMaxDimension    = 1280
+
+	renditionJPEGQuality = 82
+
+	// maxDecodePixels bounds the images decoded for renditions and hashing;
+	// larger ones are stored without them.
+	maxDecodePixels = 64 * 1000 * 1000
+)
+
+// ErrLocationNotRemovable is returned by Process for formats whose metadata
+// it cannot parse, such as HEIC, WebP and TIFF, unless the location is kept.
+// Storing them would keep whatever GPS position they carry.
+var ErrLocationNotRemovable = errors.New("photo format does not support location removal")
+
+// Options controls how an upload is processed.
+type Options struct {
+	// KeepLocation leaves GPS metadata in place, for work items that ask
+	// for the photo's location.
+	KeepLocation bool
+}
+
+// Rendition is a resized JPEG copy of a photo.
+type Rendition struct {
+	Variant     string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// Location is a GPS position in decimal degrees.
+type Location struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Result is a processed photo. Data is the original with GPS metadata
+// removed unless kept; Location is the EXIF position the original carried,
+// read before stripping. Formats the standard library cannot decode, such
+// as HEIC kept with its location, come back with no renditions and HasHash
+// unset.
+type Result struct {
+	Data             []byte
+	LocationStripped bool
+	Location         *Location
+	Width            int
+	Height           int
+	HasHash          bool
+	Hash             uint64
+	Renditions       []Rendition
+}
+
+// IsVariant reports whether variant names a rendition Process produces.
+func IsVariant(variant string) bool {
+	return variant == VariantThumbnail || variant == VariantMedium
+}
+
+// Process prepares an uploaded photo. Metadata it cannot parse is dropped
+// rather than kept, and formats it cannot read metadata from at all are
+// refused with ErrLocationNotRemovable unless opts keeps the location. If a
+// rendition cannot be encoded it still returns the scrubbed photo, without
+// renditions, alongside the error.
+func Process(data []byte, opts Options) (*Result, error) {
+	scrubbed, meta, ok := scrubMetadata(data, !opts.KeepLocation)
+	if !ok && !opts.KeepLocation {
+		return nil, ErrLocationNotRemovable
+	}
+	result := &Result{
+		Data:             scrubbed,
+		LocationStripped: meta.LocationStripped,
+		Location:         meta.Location,
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxDecodePixels {
+		return result, nil
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return result, nil
+	}
+
+	img := flatten(orient(decoded, meta.Orientation))
+	bounds := img.Bounds()
+	result.Width = bounds.Dx()
+	result.Height = bounds.Dy()
+	result.Hash = DifferenceHash(img)
+	result.HasHash = true
+
+	for _, size := range []struct {
+		variant      string
+		maxDimension int
+	}{
+		{VariantThumbnail, ThumbnailMaxDimension},
+		{VariantMedium, MediumMaxDimension},
+	} {
+		scaled := Fit(img, size.maxDimension)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: renditionJPEGQuality}); err != nil {
+			result.Renditions = nil
+			return result, err
+		}
+		result.Renditions = append(result.Renditions, Rendition{
+			Variant:     size.variant,
+			ContentType: "image/jpeg",
+			Width:       scaled.Bounds().Dx(),
+			Height:      scaled.Bounds().Dy(),
+			Data:        buf.Bytes(),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,236 @@
+package photoproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"testing"
+)
+
+// testTIFF builds a little-endian EXIF block with an orientation tag and a
+// GPS IFD holding the latitude and longitude of 37°46'29.94"N
+// 122°25'9.6"W.
+func testTIFF(orientation uint16) []byte {
+	order := binary.LittleEndian
+	tiff := []byte("II")
+	tiff = order.AppendUint16(tiff, 42)
+	tiff = order.AppendUint32(tiff, 8)
+
+	// IFD0 at 8: two entries, then the next-IFD link.
+	gpsOffset := uint32(8 + 2 + 2*12 + 4)
+	tiff = order.AppendUint16(tiff, 2)
+	tiff = appendTIFFEntry(tiff, tiffTagOrientation, 3, 1, uint32(orientation))
+	tiff = appendTIFFEntry(tiff, tiffTagGPSInfo, 4, 1, gpsOffset)
+	tiff = order.AppendUint32(tiff, 0)
+
+	// GPS IFD: references inline, coordinates as three rationals each after it.
+	latitudeOffset := gpsOffset + 2 + 4*12 + 4
+	longitudeOffset := latitudeOffset + 3*8
+	tiff = order.AppendUint16(tiff, 4)
+	tiff = appendTIFFEntry(tiff, gpsTagLatitudeRef, 2, 2, uint32('N'))
+	tiff = appendTIFFEntry(tiff, gpsTagLatitude, 5, 3, latitudeOffset)
+	tiff = appendTIFFEntry(tiff, gpsTagLongitudeRef, 2, 2, uint32('W'))
+	tiff = appendTIFFEntry(tiff, gpsTagLongitude, 5, 3, longitudeOffset)
+	tiff = order.AppendUint32(tiff, 0)
+	for _, value := range []uint32{37, 1, 46, 1, 2994, 100, 122, 1, 25, 1, 960, 100} {
+		tiff = order.AppendUint32(tiff, value)
+	}
+	return tiff
+}
+
+func appendTIFFEntry(tiff []byte, tag uint16, typ uint16, count uint32, value uint32) []byte {
+	order := binary.LittleEndian
+	tiff = order.AppendUint16(tiff, tag)
+	tiff = order.AppendUint16(tiff, typ)
+	tiff = order.AppendUint32(tiff, count)
+	return order.AppendUint32(tiff, value)
+}
+
+func testImage(width int, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / width), G: uint8(y * 255 / height), B: 90, A: 255})
+		}
+	}
+	return img
+}
+
+func testJPEG(t *testing.T, img image.Image, tiff []byte) []byte {
+	t.Helper()
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatalf("encode: %s", err)
+	}
+	if tiff == nil {
+		return encoded.Bytes()
+	}
+	payload := append(append([]byte(nil), jpegExifHeader...), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	data := append([]byte{0xFF, 0xD8}, segment...)
+	return append(data, encoded.Bytes()[2:]...)
+}
+
+func gpsLatitude(t *testing.T, data []byte) bool {
+	t.Helper()
+	// The latitude rationals are the only place 2994/100 appears.
+	needle := binary.LittleEndian.AppendUint32(nil, 2994)
+	return bytes.Contains(data, needle)
+}
+
+func TestProcessStripsGPSAndRotates(t *testing.T) {
+	data := testJPEG(t, testImage(400, 200), testTIFF(6))
+	if !gpsLatitude(t, data) {
+		t.Fatalf("test image is missing its GPS data")
+	}
+
+	result, err := Process(data, Options{})
+	if err != nil {
+		t.Fatalf("process: %s", err)
+	}
+	if !result.LocationStripped || gpsLatitude(t, result.Data) {
+		t.Fatalf("expected GPS data to be stripped")
+	}
+	if result.Location == nil || math.Abs(result.Location.Latitude-37.774983) > 1e-5 || math.Abs(result.Location.Longitude+122.419333) > 1e-5 {
+		t.Fatalf("expected the original GPS position, got %+v", result.Location)
+	}
+	if _, meta, _ := scrubMetadata(result.Data, false); meta.Location != nil {
+		t.Fatalf("expected no GPS position left after stripping")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(result.Data)); err != nil {
+		t.Fatalf("stripped photo no longer decodes: %s", err)
+	}
+	_, meta, _ := scrubMetadata(result.Data, false)
+	if meta.Orientation != 6 {
+		t.Fatalf("expected orientation to survive stripping, got %d", meta.Orientation)
+	}
+
+	if result.Width != 200 || result.Height != 400 {
+		t.Fatalf("expected rotated 200x400, got %dx%d", result.Width, result.Height)
+	}
+	if len(result.Renditions) != 2 {
+		t.Fatalf("expected 2 renditions, got %d", len(result.Renditions))
+	}
+	thumbnail := result.Renditions[0]
+	if thumbnail.Variant != VariantThumbnail || thumbnail.Width != 160 || thumbnail.Height != ThumbnailMaxDimension {
+		t.Fatalf("unexpected thumbnail %s %dx%d", thumbnail.Variant, thumbnail.Width, thumbnail.Height)
+	}
+	medium := result.Renditions[1]
+	if medium.Variant != VariantMedium || medium.Width != 200 || medium.Height != 400 {
+		t.Fatalf("unexpected medium rendition %s %dx%d", medium.Variant, medium.Width, medium.Height)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(thumbnail.Data)); err != nil {
+		t.Fatalf("thumbnail does not decode: %s", err)
+	}
+}
+
+func TestProcessKeepsLocationWhenAsked(t *testing.T) {
+	data := testJPEG(t, testImage(64, 64), testTIFF(1))
+	result, err := Process(data, Options{KeepLocation: true})
+	if err != nil {
+		t.Fatalf("process: %s", err)
+	}
+	if result.LocationStripped || !bytes.Equal(result.Data, data) {
+		t.Fatalf("expected the photo to be left untouched")
+	}
+	if result.Location == nil {
+		t.Fatalf("expected the GPS position to be read")
+	}
+}
+
+func TestProcessDropsUnparseableExif(t *testing.T) {
+	data := testJPEG(t, testImage(64, 64), []byte("II*\x00\xff\xff\xff\xff"))
+	result, err := Process(data, Options{})
+	if err != nil {
+		t.Fatalf("process: %s", err)
+	}
+	if !result.LocationStripped || bytes.Contains(result.Data, jpegExifHeader) {
+		t.Fatalf("expected the unparseable EXIF segment to be dropped")
+	}
+}
+
+func TestProcessStripsPNGExif(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, testImage(32, 32)); err != nil {
+		t.Fatalf("encode: %s", err)
+	}
+	raw := encoded.Bytes()
+	// Insert an eXIf chunk right after IHDR.
+	ihdrEnd := len(pngSignature) + 12 + int(binary.BigEndian.Uint32(raw[8:12]))
+	data := append([]byte(nil), raw[:ihdrEnd]...)
+	data = append(data, pngChunk("eXIf", testTIFF(1))...)
+	data = append(data, raw[ihdrEnd:]...)
+
+	result, err := Process(data, Options{})
+	if err != nil {
+		t.Fatalf("process: %s", err)
+	}
+	if !result.LocationStripped || gpsLatitude(t, result.Data) {
+		t.Fatalf("expected GPS data to be stripped")
+	}
+	if _, err := png.Decode(bytes.NewReader(result.Data)); err != nil {
+		t.Fatalf("stripped png no longer decodes: %s", err)
+	}
+}
+
+func TestProcessPassesThroughUndecodableImages(t *testing.T) {
+	data := []byte("\x00\x00\x00\x18ftypheic not really an image")
+	result, err := Process(data, Options{KeepLocation: true})
+	if err != nil {
+		t.Fatalf("process: %s", err)
+	}
+	if !bytes.Equal(result.Data, data) || result.HasHash || len(result.Renditions) != 0 {
+		t.Fatalf("expected undecodable photo to pass through unprocessed")
+	}
+}
+
+func TestProcessRefusesUnscrubbableFormats(t *testing.T) {
+	for _, data := range [][]byte{
+		[]byte("\x00\x00\x00\x18ftypheic not really an image"),
+		[]byte("RIFF\x00\x00\x00\x00WEBPVP8 not really an image"),
+		[]byte("II*\x00 not really an image"),
+	} {
+		if _, err := Process(data, Options{}); err != ErrLocationNotRemovable {
+			t.Fatalf("expected ErrLocationNotRemovable for %q, got %v", data[:12], err)
+		}
+	}
+}
+
+func TestDifferenceHashMatchesReencodedCopies(t *testing.T) {
+	original := testJPEG(t, testImage(800, 600), nil)
+	first, err := Process(original, Options{})
+	if err != nil {
+		t.Fatalf("process: %s", err)
+	}
+
+	// A downsized, recompressed copy should land within a few bits.
+	var smaller bytes.Buffer
+	if err := jpeg.Encode(&smaller, Fit(testImage(800, 600), 300), &jpeg.Options{Quality: 40}); err != nil {
+		t.Fatalf("encode: %s", err)
+	}
+	second, err := Process(smaller.Bytes(), Options{})
+	if err != nil {
+		t.Fatalf("process: %s", err)
+	}
+	if distance := Distance(first.Hash, second.Hash); distance > 4 {
+		t.Fatalf("expected copies to hash closely, distance %d", distance)
+	}
+
+	flipped := image.NewRGBA(image.Rect(0, 0, 800, 600))
+	source := testImage(800, 600)
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 800; x++ {
+			flipped.Set(x, y, source.At(799-x, y))
+		}
+	}
+	if distance := Distance(first.Hash, DifferenceHash(flipped)); distance < 32 {
+		t.Fatalf("expected a different image to hash apart, distance %d", distance)
+	}
+}
@@ -0,0 +1,120 @@
+package photoproc
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Fit scales img down, keeping its aspect ratio, so neither side exceeds
+// maxDimension. Smaller images are returned as they are.
+func Fit(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+	if maxDimension <= 0 || width <= 0 || height <= 0 {
+		return img
+	}
+	if width <= maxDimension && height <= maxDimension {
+		return img
+	}
+
+	targetWidth := maxDimension
+	targetHeight := maxDimension
+	if width >= height {
+		targetHeight = max(1, height*maxDimension/width)
+	} else {
+		targetWidth = max(1, width*maxDimension/height)
+	}
+	return resize(flatten(img), targetWidth, targetHeight)
+}
+
+// resize box-filters src down to width x height, averaging every source
+// pixel under each target pixel.
+func resize(src *image.RGBA, width int, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth := bounds.Dx()
+	srcHeight := bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(bounds.Min.X+x0, bounds.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// flatten draws img onto an opaque white RGBA canvas, so transparent areas
+// come out white rather than black once encoded as JPEG.
+func flatten(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Opaque() {
+		return rgba
+	}
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Over)
+	return dst
+}
+
+// orient applies an EXIF orientation so the image is upright. Values
+// outside 2-8 leave it unchanged.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for dy := 0; dy < dstHeight; dy++ {
+		for dx := 0; dx < dstWidth; dx++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = width-1-dx, dy
+			case 3:
+				sx, sy = width-1-dx, height-1-dy
+			case 4:
+				sx, sy = dx, height-1-dy
+			case 5:
+				sx, sy = dy, dx
+			case 6:
+				sx, sy = dy, height-1-dx
+			case 7:
+				sx, sy = width-1-dy, height-1-dx
+			case 8:
+				sx, sy = width-1-dy, dx
+			}
+			dst.Set(dx, dy, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,56 @@
+// Package photostore keeps workflow submission photo bytes. Postgres is the
+// original backend and keeps the bytes on the photo rows themselves; the
+// local filesystem and S3-compatible stores move them out of the app
+// database so it and its backups stay small.
+package photostore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	BackendPostgres = "postgres"
+	BackendLocal    = "local"
+	BackendS3       = "s3"
+)
+
+var ErrNotFound = errors.New("photo object not found")
+
+// Store reads and writes photo objects by key.
+type Store interface {
+	Backend() string
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// URLOptions are response overrides baked into a signed URL.
+type URLOptions struct {
+	ContentType        string
+	ContentDisposition string
+}
+
+// Signer is implemented by stores that can hand out short-lived URLs clients
+// fetch directly from the store.
+type Signer interface {
+	SignedURL(key string, opts URLOptions, ttl time.Duration, now time.Time) (string, error)
+}
+
+// ObjectKey is where a photo lives in an external store. The key does not
+// change when an upload is attached to a submission, so the object never
+// has to be copied.
+func ObjectKey(workflowId string, photoId string) string {
+	return path.Join("workflow-photos", workflowId, photoId)
+}
+
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return fmt.Errorf("invalid photo object key %q", key)
+	}
+	return nil
+}
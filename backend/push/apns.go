@@ -0,0 +1,212 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+
+	// Apple rejects provider tokens older than an hour and throttles ones
+	// refreshed more than every 20 minutes.
+	apnsTokenTTL = 40 * time.Minute
+)
+
+// apnsDeadTokenReasons are APNs rejection reasons for tokens that will never
+// deliver to this app again.
+var apnsDeadTokenReasons = map[string]bool{
+	"BadDeviceToken":         true,
+	"Unregistered":           true,
+	"DeviceTokenNotForTopic": true,
+}
+
+type apnsProvider struct {
+	key     *ecdsa.PrivateKey
+	keyID   string
+	teamID  string
+	topic   string
+	baseURL string
+	client  *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsProviderFromFile reads the .p8 token signing key downloaded from the
+// Apple developer portal.
+func NewAPNsProviderFromFile(keyPath string, keyID string, teamID string, topic string, sandbox bool) (Provider, error) {
+	raw, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading APNs key: %s", err)
+	}
+	key, err := parseAPNsKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	return NewAPNsProvider(key, keyID, teamID, topic, sandbox)
+}
+
+func NewAPNsProvider(key *ecdsa.PrivateKey, keyID string, teamID string, topic string, sandbox bool) (Provider, error) {
+	keyID = strings.TrimSpace(keyID)
+	teamID = strings.TrimSpace(teamID)
+	topic = strings.TrimSpace(topic)
+	if key == nil || keyID == "" || teamID == "" || topic == "" {
+		return nil, fmt.Errorf("APNs key, key id, team id and topic are required")
+	}
+
+	baseURL := apnsProductionURL
+	if sandbox {
+		baseURL = apnsSandboxURL
+	}
+	return &apnsProvider{
+		key:     key,
+		keyID:   keyID,
+		teamID:  teamID,
+		topic:   topic,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func parseAPNsKey(raw []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("APNs key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing APNs key: %s", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("APNs key is not an ECDSA key")
+	}
+	return key, nil
+}
+
+func (p *apnsProvider) Name() string {
+	return ProviderAPNs
+}
+
+// providerToken returns the cached ES256 provider JWT, minting a new one when
+// it is due for rotation.
+func (p *apnsProvider) providerToken(now time.Time) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && now.Sub(p.issuedAt) < apnsTokenTTL {
+		return p.token, nil
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": p.keyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{"iss": p.teamID, "iat": now.Unix()})
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, p.key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing APNs provider token: %s", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	p.token = signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+	p.issuedAt = now
+	return p.token, nil
+}
+
+func (p *apnsProvider) expireProviderToken() {
+	p.mu.Lock()
+	p.token = ""
+	p.mu.Unlock()
+}
+
+func (p *apnsProvider) Send(ctx context.Context, msg Message) (*Ticket, error) {
+	if strings.TrimSpace(msg.Token) == "" {
+		return nil, fmt.Errorf("empty APNs device token")
+	}
+
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for key, value := range msg.Data {
+		if key == "aps" {
+			continue
+		}
+		payload[key] = value
+	}
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	providerToken, err := p.providerToken(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/3/device/%s", p.baseURL, msg.Token), bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", p.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return &Ticket{Status: StatusOK}, nil
+	}
+
+	bodyBytes, _ := io.ReadAll(res.Body)
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(bodyBytes, &apnsErr)
+	if apnsErr.Reason == "ExpiredProviderToken" || apnsErr.Reason == "InvalidProviderToken" {
+		p.expireProviderToken()
+	}
+
+	ticket := &Ticket{Status: StatusError, Message: apnsErr.Reason}
+	if res.StatusCode == http.StatusGone || apnsDeadTokenReasons[apnsErr.Reason] {
+		ticket.ErrorCode = ErrorDeviceNotRegistered
+	}
+	return ticket, fmt.Errorf("apns returned %d: %s", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
+}
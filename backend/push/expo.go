@@ -0,0 +1,219 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultExpoPushAPIURL        = "https://exp.host/--/api/v2/push/send"
+	defaultExpoPushReceiptAPIURL = "https://exp.host/--/api/v2/push/getReceipts"
+)
+
+type expoPushTicket struct {
+	Status  string         `json:"status"`
+	ID      string         `json:"id,omitempty"`
+	Message string         `json:"message,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type expoPushSendResponse struct {
+	Data   json.RawMessage  `json:"data"`
+	Errors []expoPushTicket `json:"errors,omitempty"`
+}
+
+type expoPushReceipt struct {
+	Status  string         `json:"status"`
+	Message string         `json:"message,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type expoPushReceiptResponse struct {
+	Data   map[string]expoPushReceipt `json:"data"`
+	Errors []expoPushTicket           `json:"errors,omitempty"`
+}
+
+type expoProvider struct {
+	sendURL    string
+	receiptURL string
+	client     *http.Client
+}
+
+// NewExpoProvider sends through Expo's push API. EXPO_PUSH_API_URL and
+// EXPO_PUSH_RECEIPT_API_URL override the endpoints.
+func NewExpoProvider() ReceiptProvider {
+	sendURL := strings.TrimSpace(os.Getenv("EXPO_PUSH_API_URL"))
+	if sendURL == "" {
+		sendURL = defaultExpoPushAPIURL
+	}
+	receiptURL := strings.TrimSpace(os.Getenv("EXPO_PUSH_RECEIPT_API_URL"))
+	if receiptURL == "" {
+		receiptURL = defaultExpoPushReceiptAPIURL
+	}
+	return &expoProvider{
+		sendURL:    sendURL,
+		receiptURL: receiptURL,
+		client:     &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (e *expoProvider) Name() string {
+	return ProviderExpo
+}
+
+func expoDetailsError(details map[string]any) string {
+	if len(details) == 0 {
+		return ""
+	}
+	if value, ok := details["error"].(string); ok {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+func parseExpoPushTickets(raw json.RawMessage) ([]expoPushTicket, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+	if trimmed[0] == '[' {
+		var tickets []expoPushTicket
+		if err := json.Unmarshal(trimmed, &tickets); err != nil {
+			return nil, err
+		}
+		return tickets, nil
+	}
+
+	var ticket expoPushTicket
+	if err := json.Unmarshal(trimmed, &ticket); err != nil {
+		return nil, err
+	}
+	return []expoPushTicket{ticket}, nil
+}
+
+func (t expoPushTicket) toTicket() *Ticket {
+	return &Ticket{
+		Status:    t.Status,
+		ID:        strings.TrimSpace(t.ID),
+		Message:   t.Message,
+		ErrorCode: expoDetailsError(t.Details),
+	}
+}
+
+func (e *expoProvider) postJSON(ctx context.Context, url string, payload any) ([]byte, int, error) {
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bodyBytes, res.StatusCode, nil
+}
+
+func (e *expoProvider) Send(ctx context.Context, msg Message) (*Ticket, error) {
+	if strings.TrimSpace(msg.Token) == "" {
+		return nil, fmt.Errorf("empty Expo push token")
+	}
+
+	bodyBytes, status, err := e.postJSON(ctx, e.sendURL, map[string]any{
+		"to":    msg.Token,
+		"title": msg.Title,
+		"body":  msg.Body,
+		"sound": "default",
+		"data":  msg.Data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("expo push api returned %d: %s", status, strings.TrimSpace(string(bodyBytes)))
+	}
+
+	var sendResponse expoPushSendResponse
+	if err := json.Unmarshal(bodyBytes, &sendResponse); err != nil {
+		return nil, fmt.Errorf("error parsing Expo push response: %w", err)
+	}
+
+	tickets, err := parseExpoPushTickets(sendResponse.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Expo push ticket: %w", err)
+	}
+	if len(tickets) == 0 {
+		if len(sendResponse.Errors) > 0 {
+			ticket := sendResponse.Errors[0].toTicket()
+			return ticket, fmt.Errorf("expo push api returned error: %s", ticket.Message)
+		}
+		return nil, fmt.Errorf("expo push api returned no ticket")
+	}
+
+	ticket := tickets[0].toTicket()
+	if ticket.Status == StatusError {
+		message := strings.TrimSpace(ticket.Message)
+		if message == "" {
+			message = "unknown Expo push ticket error"
+		}
+		return ticket, fmt.Errorf("%s", message)
+	}
+
+	return ticket, nil
+}
+
+func (e *expoProvider) Receipt(ctx context.Context, ticketID string) (*Receipt, error) {
+	ticketID = strings.TrimSpace(ticketID)
+	if ticketID == "" {
+		return nil, fmt.Errorf("empty Expo push ticket id")
+	}
+
+	bodyBytes, status, err := e.postJSON(ctx, e.receiptURL, map[string]any{
+		"ids": []string{ticketID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status < 200 || status >= 300 {
+		return nil, fmt.Errorf("expo push receipt api returned %d: %s", status, strings.TrimSpace(string(bodyBytes)))
+	}
+
+	var receiptResponse expoPushReceiptResponse
+	if err := json.Unmarshal(bodyBytes, &receiptResponse); err != nil {
+		return nil, fmt.Errorf("error parsing Expo push receipt response: %w", err)
+	}
+	if len(receiptResponse.Errors) > 0 {
+		errTicket := receiptResponse.Errors[0]
+		return nil, fmt.Errorf("expo push receipt api returned error: %s", errTicket.Message)
+	}
+
+	receipt, ok := receiptResponse.Data[ticketID]
+	if !ok {
+		return nil, fmt.Errorf("Expo push receipt %s not found", ticketID)
+	}
+
+	return &Receipt{
+		Status:    receipt.Status,
+		Message:   receipt.Message,
+		ErrorCode: expoDetailsError(receipt.Details),
+	}, nil
+}
@@ -0,0 +1,71 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeProvider records messages in memory instead of delivering them. Tokens
+// marked with Unregister are rejected as DeviceNotRegistered.
+type FakeProvider struct {
+	mu           sync.Mutex
+	sent         []Message
+	unregistered map[string]bool
+	receipts     map[string]*Receipt
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		unregistered: map[string]bool{},
+		receipts:     map[string]*Receipt{},
+	}
+}
+
+func (f *FakeProvider) Name() string {
+	return ProviderFake
+}
+
+func (f *FakeProvider) Unregister(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.unregistered[strings.TrimSpace(token)] = true
+}
+
+func (f *FakeProvider) Send(ctx context.Context, msg Message) (*Ticket, error) {
+	if strings.TrimSpace(msg.Token) == "" {
+		return nil, fmt.Errorf("empty fake push token")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.unregistered[strings.TrimSpace(msg.Token)] {
+		return &Ticket{Status: StatusError, ErrorCode: ErrorDeviceNotRegistered}, fmt.Errorf("fake push token is not registered")
+	}
+
+	f.sent = append(f.sent, msg)
+	ticketID := fmt.Sprintf("fake-%d", len(f.sent))
+	f.receipts[ticketID] = &Receipt{Status: StatusOK}
+	return &Ticket{Status: StatusOK, ID: ticketID}, nil
+}
+
+func (f *FakeProvider) Receipt(ctx context.Context, ticketID string) (*Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	receipt, ok := f.receipts[ticketID]
+	if !ok {
+		return nil, fmt.Errorf("fake push receipt %s not found", ticketID)
+	}
+	copied := *receipt
+	return &copied, nil
+}
+
+// Sent returns the messages delivered so far.
+func (f *FakeProvider) Sent() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.sent...)
+}
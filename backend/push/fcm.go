@@ -0,0 +1,267 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmAPIURL          = "https://fcm.googleapis.com"
+	fcmDefaultTokenURL = "https://oauth2.googleapis.com/token"
+	fcmScope           = "https://www.googleapis.com/auth/firebase.messaging"
+
+	// Access tokens are refreshed this long before Google expires them.
+	fcmTokenRefreshMargin = time.Minute
+)
+
+// FCMServiceAccount is the subset of a Google service account key file the
+// FCM provider uses.
+type FCMServiceAccount struct {
+	ProjectId    string `json:"project_id"`
+	PrivateKeyId string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenUri     string `json:"token_uri"`
+}
+
+type fcmProvider struct {
+	projectID   string
+	clientEmail string
+	keyID       string
+	key         *rsa.PrivateKey
+	tokenURL    string
+	apiURL      string
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func NewFCMProviderFromFile(accountPath string, projectID string) (Provider, error) {
+	raw, err := os.ReadFile(accountPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading FCM service account: %s", err)
+	}
+	var account FCMServiceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("error parsing FCM service account: %s", err)
+	}
+	if strings.TrimSpace(projectID) != "" {
+		account.ProjectId = projectID
+	}
+	return NewFCMProvider(account)
+}
+
+func NewFCMProvider(account FCMServiceAccount) (Provider, error) {
+	account.ProjectId = strings.TrimSpace(account.ProjectId)
+	account.ClientEmail = strings.TrimSpace(account.ClientEmail)
+	if account.ProjectId == "" || account.ClientEmail == "" {
+		return nil, fmt.Errorf("FCM project id and client email are required")
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("FCM private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing FCM private key: %s", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("FCM private key is not an RSA key")
+	}
+
+	tokenURL := strings.TrimSpace(account.TokenUri)
+	if tokenURL == "" {
+		tokenURL = fcmDefaultTokenURL
+	}
+	return &fcmProvider{
+		projectID:   account.ProjectId,
+		clientEmail: account.ClientEmail,
+		keyID:       strings.TrimSpace(account.PrivateKeyId),
+		key:         key,
+		tokenURL:    tokenURL,
+		apiURL:      fcmAPIURL,
+		client:      &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (p *fcmProvider) Name() string {
+	return ProviderFCM
+}
+
+// assertion builds the RS256 JWT exchanged for an OAuth access token.
+func (p *fcmProvider) assertion(now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	if p.keyID != "" {
+		header["kid"] = p.keyID
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   p.clientEmail,
+		"scope": fcmScope,
+		"aud":   p.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing FCM assertion: %s", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (p *fcmProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.accessToken != "" && now.Before(p.expiresAt) {
+		return p.accessToken, nil
+	}
+
+	assertion, err := p.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting FCM access token: %s", err)
+	}
+	defer res.Body.Close()
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("FCM token endpoint returned %d: %s", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
+	}
+
+	var tokenResponse struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(bodyBytes, &tokenResponse); err != nil {
+		return "", fmt.Errorf("error parsing FCM access token: %s", err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", fmt.Errorf("FCM token endpoint returned no access token")
+	}
+
+	p.accessToken = tokenResponse.AccessToken
+	p.expiresAt = now.Add(time.Duration(tokenResponse.ExpiresIn)*time.Second - fcmTokenRefreshMargin)
+	return p.accessToken, nil
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (p *fcmProvider) Send(ctx context.Context, msg Message) (*Ticket, error) {
+	if strings.TrimSpace(msg.Token) == "" {
+		return nil, fmt.Errorf("empty FCM registration token")
+	}
+
+	reqBody, err := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"token": msg.Token,
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data": msg.Data,
+			"android": map[string]string{
+				"priority": "high",
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := p.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v1/projects/%s/messages:send", p.apiURL, p.projectID), bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return &Ticket{Status: StatusOK}, nil
+	}
+
+	var fcmErr fcmErrorResponse
+	_ = json.Unmarshal(bodyBytes, &fcmErr)
+	ticket := &Ticket{Status: StatusError, Message: fcmErr.Error.Message}
+	if fcmErr.Error.Status == "NOT_FOUND" {
+		ticket.ErrorCode = ErrorDeviceNotRegistered
+	}
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			ticket.ErrorCode = ErrorDeviceNotRegistered
+		}
+	}
+	if res.StatusCode == http.StatusUnauthorized {
+		p.mu.Lock()
+		p.accessToken = ""
+		p.mu.Unlock()
+	}
+	return ticket, fmt.Errorf("fcm returned %d: %s", res.StatusCode, strings.TrimSpace(string(bodyBytes)))
+}
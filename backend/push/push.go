@@ -0,0 +1,151 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Provider names stored on mobile push subscriptions. Subscriptions that
+// predate providers are Expo.
+const (
+	ProviderExpo = "expo"
+	ProviderAPNs = "apns"
+	ProviderFCM  = "fcm"
+	ProviderFake = "fake"
+)
+
+// ErrorDeviceNotRegistered is the normalized error code every provider
+// reports for tokens that will never deliver again.
+const ErrorDeviceNotRegistered = "DeviceNotRegistered"
+
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+type Message struct {
+	Token string
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Ticket is a provider's answer to a send. ID is only set when the outcome
+// has to be fetched later through ReceiptProvider.
+type Ticket struct {
+	Status    string
+	ID        string
+	Message   string
+	ErrorCode string
+}
+
+type Receipt struct {
+	Status    string
+	Message   string
+	ErrorCode string
+}
+
+// Provider delivers a push message to a device token. Send returns a ticket
+// alongside an error when the provider rejected the message, so callers can
+// act on ErrorCode.
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, msg Message) (*Ticket, error)
+}
+
+// ReceiptProvider is implemented by providers that accept messages
+// asynchronously and report delivery through receipts, like Expo.
+type ReceiptProvider interface {
+	Provider
+	Receipt(ctx context.Context, ticketID string) (*Receipt, error)
+}
+
+// NormalizeProvider lowercases a provider name and defaults it to Expo.
+func NormalizeProvider(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ProviderExpo
+	}
+	return name
+}
+
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: map[string]Provider{}}
+	for _, provider := range providers {
+		r.Register(provider)
+	}
+	return r
+}
+
+// NewRegistryFromEnv always registers Expo, and APNs and FCM when their
+// credentials are configured:
+//
+//   - APNs: APNS_KEY_PATH (.p8 token signing key), APNS_KEY_ID, APNS_TEAM_ID,
+//     APNS_TOPIC (bundle id) and APNS_SANDBOX=true for development builds
+//   - FCM: FCM_SERVICE_ACCOUNT_PATH (service account JSON) and optionally
+//     FCM_PROJECT_ID when it differs from the service account's project
+//
+// PUSH_FAKE_PROVIDER=true adds the in-memory fake provider for local testing.
+func NewRegistryFromEnv() (*Registry, error) {
+	r := NewRegistry(NewExpoProvider())
+
+	if keyPath := strings.TrimSpace(os.Getenv("APNS_KEY_PATH")); keyPath != "" {
+		apns, err := NewAPNsProviderFromFile(
+			keyPath,
+			os.Getenv("APNS_KEY_ID"),
+			os.Getenv("APNS_TEAM_ID"),
+			os.Getenv("APNS_TOPIC"),
+			strings.EqualFold(strings.TrimSpace(os.Getenv("APNS_SANDBOX")), "true"),
+		)
+		if err != nil {
+			return nil, err
+		}
+		r.Register(apns)
+	}
+
+	if accountPath := strings.TrimSpace(os.Getenv("FCM_SERVICE_ACCOUNT_PATH")); accountPath != "" {
+		fcm, err := NewFCMProviderFromFile(accountPath, os.Getenv("FCM_PROJECT_ID"))
+		if err != nil {
+			return nil, err
+		}
+		r.Register(fcm)
+	}
+
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("PUSH_FAKE_PROVIDER")), "true") {
+		r.Register(NewFakeProvider())
+	}
+
+	return r, nil
+}
+
+func (r *Registry) Register(provider Provider) {
+	if provider == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[NormalizeProvider(provider.Name())] = provider
+}
+
+func (r *Registry) Get(name string) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[NormalizeProvider(name)]
+	if !ok {
+		return nil, fmt.Errorf("push provider %q is not configured", NormalizeProvider(name))
+	}
+	return provider, nil
+}
+
+func (r *Registry) Has(name string) bool {
+	_, err := r.Get(name)
+	return err == nil
+}
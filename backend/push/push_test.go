@@ -0,0 +1,229 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryDefaultsToExpo(t *testing.T) {
+	fake := NewFakeProvider()
+	registry := NewRegistry(NewExpoProvider(), fake)
+
+	provider, err := registry.Get("")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if provider.Name() != ProviderExpo {
+		t.Fatalf("got provider %s; want expo", provider.Name())
+	}
+	if !registry.Has(" FAKE ") {
+		t.Fatalf("expected fake provider to be registered")
+	}
+	if registry.Has(ProviderAPNs) {
+		t.Fatalf("expected apns to be unconfigured")
+	}
+}
+
+func TestFakeProviderRecordsAndRejectsUnregistered(t *testing.T) {
+	fake := NewFakeProvider()
+	fake.Unregister("dead")
+
+	ticket, err := fake.Send(context.Background(), Message{Token: "live", Title: "t", Body: "b"})
+	if err != nil || ticket.Status != StatusOK || ticket.ID == "" {
+		t.Fatalf("got ticket %+v, err %v; want ok with id", ticket, err)
+	}
+	receipt, err := fake.Receipt(context.Background(), ticket.ID)
+	if err != nil || receipt.Status != StatusOK {
+		t.Fatalf("got receipt %+v, err %v; want ok", receipt, err)
+	}
+
+	ticket, err = fake.Send(context.Background(), Message{Token: "dead"})
+	if err == nil || ticket.ErrorCode != ErrorDeviceNotRegistered {
+		t.Fatalf("got ticket %+v, err %v; want DeviceNotRegistered", ticket, err)
+	}
+	if sent := fake.Sent(); len(sent) != 1 || sent[0].Token != "live" {
+		t.Fatalf("got sent %+v; want only the live message", sent)
+	}
+}
+
+func TestExpoProviderNormalizesTicketErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/getReceipts") {
+			w.Write([]byte(`{"data":{"ticket-1":{"status":"error","message":"gone","details":{"error":"DeviceNotRegistered"}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"status":"ok","id":"ticket-1"}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("EXPO_PUSH_API_URL", server.URL+"/send")
+	t.Setenv("EXPO_PUSH_RECEIPT_API_URL", server.URL+"/getReceipts")
+	expo := NewExpoProvider()
+
+	ticket, err := expo.Send(context.Background(), Message{Token: "ExponentPushToken[x]", Title: "t", Body: "b"})
+	if err != nil || ticket.ID != "ticket-1" {
+		t.Fatalf("got ticket %+v, err %v; want ticket-1", ticket, err)
+	}
+	receipt, err := expo.Receipt(context.Background(), ticket.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if receipt.Status != StatusError || receipt.ErrorCode != ErrorDeviceNotRegistered {
+		t.Fatalf("got receipt %+v; want DeviceNotRegistered error", receipt)
+	}
+}
+
+func TestAPNsProviderSignsProviderToken(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotAuth, gotTopic, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotTopic = r.Header.Get("apns-topic")
+		gotPath = r.URL.Path
+		if strings.HasSuffix(r.URL.Path, "/dead") {
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"reason":"Unregistered"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	provider, err := NewAPNsProvider(key, "KEY123", "TEAM123", "org.sfluv.wallet", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.(*apnsProvider).baseURL = server.URL
+
+	ticket, err := provider.Send(context.Background(), Message{Token: "abc", Title: "t", Body: "b"})
+	if err != nil || ticket.Status != StatusOK {
+		t.Fatalf("got ticket %+v, err %v; want ok", ticket, err)
+	}
+	if gotPath != "/3/device/abc" || gotTopic != "org.sfluv.wallet" {
+		t.Fatalf("got path %s topic %s", gotPath, gotTopic)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(gotAuth, "bearer "), ".")
+	if len(parts) != 3 {
+		t.Fatalf("got authorization %q; want bearer JWT", gotAuth)
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+	}
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil || claims.Iss != "TEAM123" {
+		t.Fatalf("got claims %s; want iss TEAM123", claimsJSON)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if len(signature) != 64 || !ecdsa.Verify(&key.PublicKey, digest[:], r, s) {
+		t.Fatalf("provider token signature does not verify")
+	}
+
+	ticket, err = provider.Send(context.Background(), Message{Token: "dead"})
+	if err == nil || ticket.ErrorCode != ErrorDeviceNotRegistered {
+		t.Fatalf("got ticket %+v, err %v; want DeviceNotRegistered", ticket, err)
+	}
+}
+
+func TestAPNsProviderReusesProviderToken(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	provider, err := NewAPNsProvider(key, "KEY123", "TEAM123", "org.sfluv.wallet", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apns := provider.(*apnsProvider)
+
+	now := time.Unix(1_700_000_000, 0)
+	first, _ := apns.providerToken(now)
+	again, _ := apns.providerToken(now.Add(apnsTokenTTL - time.Second))
+	rotated, _ := apns.providerToken(now.Add(apnsTokenTTL))
+	if first != again {
+		t.Fatalf("expected token to be reused within its ttl")
+	}
+	if first == rotated {
+		t.Fatalf("expected token to rotate after its ttl")
+	}
+}
+
+func TestFCMProviderExchangesTokenAndFlagsUnregistered(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			r.ParseForm()
+			if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.Form.Get("assertion") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"access_token":"access-1","expires_in":3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer access-1" || r.URL.Path != "/v1/projects/sfluv/messages:send" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"token":"dead"`) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+			return
+		}
+		w.Write([]byte(`{"name":"projects/sfluv/messages/1"}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewFCMProvider(FCMServiceAccount{
+		ProjectId:   "sfluv",
+		PrivateKey:  pemKey,
+		ClientEmail: "push@sfluv.iam.gserviceaccount.com",
+		TokenUri:    server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.(*fcmProvider).apiURL = server.URL
+
+	ticket, err := provider.Send(context.Background(), Message{Token: "live", Title: "t", Body: "b", Data: map[string]string{"hash": "0x01"}})
+	if err != nil || ticket.Status != StatusOK {
+		t.Fatalf("got ticket %+v, err %v; want ok", ticket, err)
+	}
+	ticket, err = provider.Send(context.Background(), Message{Token: "dead"})
+	if err == nil || ticket.ErrorCode != ErrorDeviceNotRegistered {
+		t.Fatalf("got ticket %+v, err %v; want DeviceNotRegistered", ticket, err)
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("got %d token requests; want the access token cached", tokenRequests)
+	}
+}
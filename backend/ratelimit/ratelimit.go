@@ -0,0 +1,303 @@
+// Package ratelimit throttles abuse-prone public routes with token buckets.
+// Each route group has its own limit, and a request is counted against one
+// bucket per key it carries (client IP, user DID, wallet address), so moving
+// between IPs does not reset a wallet's budget and vice versa. Buckets live
+// in a Store: in memory for a single instance, or in Postgres when several
+// instances share traffic.
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SFLuv/app/backend/metrics"
+)
+
+// maxPeekBytes caps how much of a request body ByWallet reads to find the
+// wallet address. The rest of the body is left for the handler untouched.
+const maxPeekBytes = 64 << 10
+
+// Limit allows Burst requests at once, refilled evenly over Period.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+func (l Limit) enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Burst, l.Period)
+}
+
+// Bucket is the stored state of one key's token bucket.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Take refills b for the time since it was last updated and spends one token
+// if there is one. When there is not, retryAfter is how long until there is.
+// A zero Bucket starts full.
+func Take(b Bucket, limit Limit, now time.Time) (next Bucket, allowed bool, retryAfter time.Duration) {
+	burst := float64(limit.Burst)
+	perToken := limit.Period / time.Duration(limit.Burst)
+
+	tokens := burst
+	if !b.UpdatedAt.IsZero() {
+		elapsed := now.Sub(b.UpdatedAt)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(burst, b.Tokens+float64(elapsed)/float64(perToken))
+	}
+
+	if tokens >= 1 {
+		return Bucket{Tokens: tokens - 1, UpdatedAt: now}, true, 0
+	}
+	wait := time.Duration(math.Ceil((1 - tokens) * float64(perToken)))
+	return Bucket{Tokens: tokens, UpdatedAt: now}, false, wait
+}
+
+// Store spends a token from the bucket for key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// StoreFunc adapts a function, such as the app database's bucket query, to
+// Store.
+type StoreFunc func(ctx context.Context, key string, limit Limit, now time.Time) (bool, time.Duration, error)
+
+func (f StoreFunc) Take(ctx context.Context, key string, limit Limit, now time.Time) (bool, time.Duration, error) {
+	return f(ctx, key, limit, now)
+}
+
+// KeyFunc names the bucket a request is counted against, for example
+// "ip:203.0.113.7". It returns "" when the request has no such key.
+type KeyFunc func(r *http.Request) string
+
+// Rule is one route group's limit and the keys it is applied to.
+type Rule struct {
+	Limit Limit
+	Keys  []KeyFunc
+}
+
+// Limiter applies per-group rules. A nil *Limiter lets every request through.
+type Limiter struct {
+	store Store
+	now   func() time.Time
+
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+func New(store Store) *Limiter {
+	return &Limiter{
+		store: store,
+		now:   time.Now,
+		rules: map[string]Rule{},
+	}
+}
+
+// Set configures group. RATE_LIMIT_<GROUP>, e.g. RATE_LIMIT_REDEEM=10/1m,
+// overrides the limit, and a value of 0 disables the group.
+func (l *Limiter) Set(group string, rule Rule) {
+	if l == nil {
+		return
+	}
+	envKey := "RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(group, "-", "_"))
+	if raw := strings.TrimSpace(os.Getenv(envKey)); raw != "" {
+		limit, err := ParseLimit(raw)
+		if err != nil {
+			slog.Error("invalid rate limit override; keeping default", "env", envKey, "value", raw, "default", rule.Limit.String(), "error", err)
+		} else {
+			rule.Limit = limit
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rules[group] = rule
+}
+
+// ParseLimit reads "burst/period", like "10/1m" or "5/30s". "0" disables.
+func ParseLimit(raw string) (Limit, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "0" {
+		return Limit{}, nil
+	}
+	burstRaw, periodRaw, ok := strings.Cut(raw, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("expected burst/period, got %q", raw)
+	}
+	burst, err := strconv.Atoi(strings.TrimSpace(burstRaw))
+	if err != nil || burst < 0 {
+		return Limit{}, fmt.Errorf("invalid burst %q", burstRaw)
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(periodRaw))
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid period %q", periodRaw)
+	}
+	return Limit{Burst: burst, Period: period}, nil
+}
+
+// Wrap throttles next under group's rule. Keys are checked in order and the
+// first over-limit one rejects the request with 429 and a Retry-After header
+// in whole seconds, without spending tokens from the keys after it. Store
+// errors let the request through, since failing closed would take the routes
+// down with the database.
+func (l *Limiter) Wrap(group string, next http.HandlerFunc) http.HandlerFunc {
+	if l == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		l.mu.RLock()
+		rule, ok := l.rules[group]
+		l.mu.RUnlock()
+		if !ok || !rule.Limit.enabled() || l.store == nil {
+			next(w, r)
+			return
+		}
+
+		now := l.now()
+		var wait time.Duration
+		for _, keyFunc := range rule.Keys {
+			key := keyFunc(r)
+			if key == "" {
+				continue
+			}
+			allowed, retryAfter, err := l.store.Take(r.Context(), group+":"+key, rule.Limit, now)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "rate limit store error; allowing request", "group", group, "error", err)
+				continue
+			}
+			if !allowed {
+				wait = retryAfter
+				break
+			}
+		}
+
+		if wait > 0 {
+			metrics.RateLimited(group)
+			seconds := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte("rate limit exceeded"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// ByIP keys on the client IP. X-Forwarded-For is only trusted when
+// RATE_LIMIT_TRUST_FORWARDED_FOR=true, and then only its last entry, the one
+// added by our own proxy.
+func ByIP(r *http.Request) string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("RATE_LIMIT_TRUST_FORWARDED_FOR")), "true") {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			parts := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(parts[len(parts)-1]); ip != "" {
+				return "ip:" + ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if host == "" {
+		return ""
+	}
+	return "ip:" + host
+}
+
+// ByUser keys on the authenticated user's DID, if there is one.
+func ByUser(r *http.Request) string {
+	userDid, ok := r.Context().Value("userDid").(string)
+	if !ok || userDid == "" {
+		return ""
+	}
+	return "user:" + userDid
+}
+
+// ByWallet keys on the first of fields found in the request's JSON body. The
+// body is restored so the handler reads it in full.
+func ByWallet(fields ...string) KeyFunc {
+	return func(r *http.Request) string {
+		if r.Body == nil {
+			return ""
+		}
+		peeked, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBytes))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(peeked), r.Body), r.Body}
+		if err != nil || len(peeked) == 0 {
+			return ""
+		}
+
+		values := map[string]any{}
+		if err := json.Unmarshal(peeked, &values); err != nil {
+			return ""
+		}
+		for _, field := range fields {
+			address, _ := values[field].(string)
+			address = strings.ToLower(strings.TrimSpace(address))
+			if address != "" {
+				return "wallet:" + address
+			}
+		}
+		return ""
+	}
+}
+
+// MemoryStore keeps buckets in process. Full buckets are swept every
+// sweepInterval so idle keys do not accumulate.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]memoryBucket
+	lastSweep time.Time
+}
+
+type memoryBucket struct {
+	Bucket
+	limit Limit
+}
+
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]memoryBucket{}}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, b := range s.buckets {
+			if now.Sub(b.UpdatedAt) >= b.limit.Period {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	next, allowed, retryAfter := Take(s.buckets[key].Bucket, limit, now)
+	s.buckets[key] = memoryBucket{Bucket: next, limit: limit}
+	return allowed, retryAfter, nil
+}
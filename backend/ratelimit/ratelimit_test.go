@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTakeRefillsOverPeriod(t *testing.T) {
+	limit := Limit{Burst: 2, Period: time.Minute}
+	now := time.Unix(1_700_000_000, 0)
+
+	b, allowed, _ := Take(Bucket{}, limit, now)
+	if !allowed {
+		t.Fatal("expected a new bucket to start full")
+	}
+	b, allowed, _ = Take(b, limit, now)
+	if !allowed {
+		t.Fatal("expected the second token of the burst")
+	}
+	b, allowed, wait := Take(b, limit, now)
+	if allowed || wait != 30*time.Second {
+		t.Fatalf("expected denial with a 30s wait, got allowed=%v wait=%s", allowed, wait)
+	}
+
+	_, allowed, _ = Take(b, limit, now.Add(30*time.Second))
+	if !allowed {
+		t.Fatal("expected one token to refill after 30s")
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("20/1m")
+	if err != nil || limit.Burst != 20 || limit.Period != time.Minute {
+		t.Fatalf("unexpected limit %+v err %v", limit, err)
+	}
+	if limit, err := ParseLimit("0"); err != nil || limit.enabled() {
+		t.Fatalf("expected 0 to disable, got %+v err %v", limit, err)
+	}
+	for _, raw := range []string{"20", "x/1m", "5/soon", "5/0s"} {
+		if _, err := ParseLimit(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestWrapSetsRetryAfter(t *testing.T) {
+	limits := New(NewMemoryStore())
+	limits.Set("redeem", Rule{Limit: Limit{Burst: 1, Period: 10 * time.Second}, Keys: []KeyFunc{ByIP}})
+
+	handler := limits.Wrap("redeem", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	send := func(remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/redeem", nil)
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	if rec := send("203.0.113.7:5000"); rec.Code != http.StatusOK {
+		t.Fatalf("expected first request through, got %d", rec.Code)
+	}
+	rec := send("203.0.113.7:5001")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "10" {
+		t.Fatalf("expected 429 with Retry-After 10, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := send("198.51.100.2:5000"); rec.Code != http.StatusOK {
+		t.Fatalf("expected a different IP to have its own bucket, got %d", rec.Code)
+	}
+}
+
+func TestWrapLimitsWalletAcrossIPs(t *testing.T) {
+	limits := New(NewMemoryStore())
+	limits.Set("redeem", Rule{Limit: Limit{Burst: 1, Period: time.Minute}, Keys: []KeyFunc{ByIP, ByWallet("address")}})
+
+	var bodies []string
+	handler := limits.Wrap("redeem", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	})
+
+	body := `{"code":"abc","address":"0xABC"}`
+	for i, remote := range []string{"203.0.113.7:1", "198.51.100.2:1"} {
+		req := httptest.NewRequest(http.MethodPost, "/redeem", strings.NewReader(body))
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if i == 1 && rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected the wallet bucket to apply across IPs, got %d", rec.Code)
+		}
+	}
+	if len(bodies) != 1 || bodies[0] != body {
+		t.Fatalf("expected the handler to read the full body once, got %q", bodies)
+	}
+}
+
+func TestWrapStopsAtFirstRejectedKey(t *testing.T) {
+	limits := New(NewMemoryStore())
+	limits.Set("redeem", Rule{Limit: Limit{Burst: 1, Period: time.Minute}, Keys: []KeyFunc{ByIP, ByWallet("address")}})
+
+	handler := limits.Wrap("redeem", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	send := func(remote string, address string) int {
+		req := httptest.NewRequest(http.MethodPost, "/redeem", strings.NewReader(`{"address":"`+address+`"}`))
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	if code := send("203.0.113.7:1", "0xaaa"); code != http.StatusOK {
+		t.Fatalf("expected first request through, got %d", code)
+	}
+	if code := send("203.0.113.7:2", "0xbbb"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected the IP bucket to reject, got %d", code)
+	}
+	// The rejected request must not have spent the second wallet's token.
+	if code := send("198.51.100.2:1", "0xbbb"); code != http.StatusOK {
+		t.Fatalf("expected the wallet rejected by IP to keep its token, got %d", code)
+	}
+}
+
+func TestWrapFailsOpenOnStoreError(t *testing.T) {
+	limits := New(StoreFunc(func(context.Context, string, Limit, time.Time) (bool, time.Duration, error) {
+		return false, 0, errors.New("db down")
+	}))
+	limits.Set("locations", Rule{Limit: Limit{Burst: 1, Period: time.Minute}, Keys: []KeyFunc{ByIP}})
+
+	rec := httptest.NewRecorder()
+	limits.Wrap("locations", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})(rec, httptest.NewRequest(http.MethodGet, "/locations", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected store errors to let requests through, got %d", rec.Code)
+	}
+}
+
+func TestSetAppliesEnvOverride(t *testing.T) {
+	t.Setenv("RATE_LIMIT_W9_SUBMIT", "0")
+	limits := New(NewMemoryStore())
+	limits.Set("w9_submit", Rule{Limit: Limit{Burst: 1, Period: time.Hour}, Keys: []KeyFunc{ByIP}})
+
+	handler := limits.Wrap("w9_submit", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/w9/submit", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected override to disable the limit, got %d", rec.Code)
+		}
+	}
+}
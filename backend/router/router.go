@@ -2,15 +2,21 @@ package router
 
 import (
 	"context"
+	"crypto/subtle"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/SFLuv/app/backend/handlers"
+	"github.com/SFLuv/app/backend/health"
+	"github.com/SFLuv/app/backend/metrics"
+	"github.com/SFLuv/app/backend/ratelimit"
 	"github.com/SFLuv/app/backend/structs"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 
 	m "github.com/SFLuv/app/backend/utils/middleware"
@@ -84,56 +90,110 @@ func allowedOrigins() []string {
 	return origins
 }
 
-func New(s *handlers.BotService, a *handlers.AppService, p *handlers.PonderService) *chi.Mux {
+// Rate limit groups. Each can be overridden with RATE_LIMIT_<GROUP>, e.g.
+// RATE_LIMIT_REDEEM=20/1m.
+const (
+	rateLimitRedeem      = "redeem"
+	rateLimitRecovery    = "recovery"
+	rateLimitEmailVerify = "email_verify"
+	rateLimitW9Submit    = "w9_submit"
+	rateLimitLocations   = "locations"
+)
+
+func newRateLimiter(store ratelimit.Store) *ratelimit.Limiter {
+	limits := ratelimit.New(store)
+	limits.Set(rateLimitRedeem, ratelimit.Rule{
+		Limit: ratelimit.Limit{Burst: 10, Period: time.Minute},
+		Keys:  []ratelimit.KeyFunc{ratelimit.ByIP, ratelimit.ByUser, ratelimit.ByWallet("address")},
+	})
+	limits.Set(rateLimitRecovery, ratelimit.Rule{
+		Limit: ratelimit.Limit{Burst: 5, Period: time.Minute},
+		Keys:  []ratelimit.KeyFunc{ratelimit.ByIP, ratelimit.ByUser, ratelimit.ByWallet("sigAuthAccount")},
+	})
+	limits.Set(rateLimitEmailVerify, ratelimit.Rule{
+		Limit: ratelimit.Limit{Burst: 10, Period: 10 * time.Minute},
+		Keys:  []ratelimit.KeyFunc{ratelimit.ByIP},
+	})
+	limits.Set(rateLimitW9Submit, ratelimit.Rule{
+		Limit: ratelimit.Limit{Burst: 5, Period: time.Hour},
+		Keys:  []ratelimit.KeyFunc{ratelimit.ByIP, ratelimit.ByWallet("wallet_address")},
+	})
+	limits.Set(rateLimitLocations, ratelimit.Rule{
+		Limit: ratelimit.Limit{Burst: 120, Period: time.Minute},
+		Keys:  []ratelimit.KeyFunc{ratelimit.ByIP},
+	})
+	return limits
+}
+
+func New(s *handlers.BotService, a *handlers.AppService, p *handlers.PonderService, h *health.Checker, limitStore ratelimit.Store) *chi.Mux {
 	r := chi.NewRouter()
+	limits := newRateLimiter(limitStore)
 
-	r.Use(middleware.Logger)
+	r.Use(m.RequestID)
+	r.Use(m.AccessLog)
+	r.Use(metrics.Middleware)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   allowedOrigins(),
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Access-Token", "X-Admin-Key", "X-SFLUV-Client-Platform", "X-SFLUV-Client-Version", "X-SFLUV-Client-Build", "X-SFLUV-Client-Installation"},
-		ExposedHeaders:   []string{"Link", "X-SFLUV-Auth-Reason"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Access-Token", "X-Admin-Key", "X-SFLUV-Client-Platform", "X-SFLUV-Client-Version", "X-SFLUV-Client-Build", "X-Request-Id"},
+		ExposedHeaders:   []string{"Link", "X-SFLUV-Auth-Reason", "X-Request-Id", "Retry-After"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
 	r.Use(m.AuthMiddleware)
 
-	AddBotRoutes(r, s, a)
+	AddHealthRoutes(r, h)
+	AddMetricsRoutes(r)
+	AddBotRoutes(r, s, a, limits)
 	AddClientConfigRoutes(r, a)
-	AddUserRoutes(r, a)
+	AddUserRoutes(r, a, limits)
 	AddAdminRoutes(r, a)
 	AddAffiliateRoutes(r, s, a)
 	AddWorkflowRoutes(r, s, a)
 	AddWalletRoutes(r, a)
-	AddLocationRoutes(r, a)
+	AddLocationRoutes(r, a, limits)
 	AddContactRoutes(r, a)
 	AddMerchantModeRoutes(r, a)
 	AddPonderRoutes(r, a, p)
-	AddW9Routes(r, a)
+	AddW9Routes(r, a, limits)
 	AddUnwrapRoutes(r, a)
 
 	return r
 }
 
+func AddHealthRoutes(r *chi.Mux, h *health.Checker) {
+	if h == nil {
+		return
+	}
+	r.Get("/healthz", h.Liveness)
+	r.Get("/readyz", h.Readiness)
+}
+
+func AddMetricsRoutes(r *chi.Mux) {
+	r.Method(http.MethodGet, "/metrics", metrics.Handler())
+}
+
 func AddClientConfigRoutes(r *chi.Mux, s *handlers.AppService) {
 	r.Get("/config", s.GetClientConfig)
 	r.Get("/client-version", s.GetClientVersion)
 }
 
-func AddBotRoutes(r *chi.Mux, s *handlers.BotService, a *handlers.AppService) {
-	r.Post("/events", withAdmin(s.NewEvent, a))
-	r.Post("/events/{event_id}/codes", withAdmin(s.NewCodesRequest, a))
-	r.Get("/events/{event}", withAdmin(s.GetCodesRequest, a))
+func AddBotRoutes(r *chi.Mux, s *handlers.BotService, a *handlers.AppService, limits *ratelimit.Limiter) {
+	r.Post("/events", withAdminScope(structs.APIKeyScopeEventsCreate, s.NewEvent, a))
+	r.Post("/events/{event_id}/codes", withAdminScope(structs.APIKeyScopeEventsCreate, s.NewCodesRequest, a))
+	r.Get("/events/{event_id}/codes/export", withAdminScope(structs.APIKeyScopeEventsRead, s.ExportCodes, a))
+	r.Get("/events/{event_id}/reconciliation", withAdminScope(structs.APIKeyScopeEventsRead, s.ReconcileRedemptions, a))
+	r.Get("/events/{event}", withAdminScope(structs.APIKeyScopeEventsRead, s.GetCodesRequest, a))
 	r.Delete("/events/{event}", withAdmin(s.DeleteEvent, a))
-	r.Get("/events", withAdmin(s.GetEvents, a))
-	r.Post("/redeem", s.Redeem)
+	r.Get("/events", withAdminScope(structs.APIKeyScopeEventsRead, s.GetEvents, a))
+	r.Post("/redeem", limits.Wrap(rateLimitRedeem, s.Redeem))
 	r.Post("/drain", withAdmin(s.Drain, a))
 	r.Get("/balance", withAdmin(s.RemainingBalance, a))
-	r.Post("/recovery/balance", s.RecoveryPreview)
-	r.Post("/recovery/claim", withAuth(s.RecoveryClaim))
+	r.Post("/recovery/balance", limits.Wrap(rateLimitRecovery, s.RecoveryPreview))
+	r.Post("/recovery/claim", limits.Wrap(rateLimitRecovery, withAuth(s.RecoveryClaim)))
 }
 
-func AddUserRoutes(r *chi.Mux, s *handlers.AppService) {
+func AddUserRoutes(r *chi.Mux, s *handlers.AppService, limits *ratelimit.Limiter) {
 	r.Post("/users", withAuth(s.AddUser))
 	r.Get("/users/policy-status", withAuth(s.GetUserPolicyStatus))
 	r.Post("/users/policies/accept", withAuth(s.AcceptUserPolicies))
@@ -149,7 +209,9 @@ func AddUserRoutes(r *chi.Mux, s *handlers.AppService) {
 	r.Get("/users/verified-emails", withActiveAuth(s.GetUserVerifiedEmails, s))
 	r.Post("/users/verified-emails", withActiveAuth(s.RequestUserEmailVerification, s))
 	r.Post("/users/verified-emails/{email_id}/resend", withActiveAuth(s.ResendUserEmailVerification, s))
-	r.Post("/users/verified-emails/verify", s.VerifyUserEmailToken)
+	r.Post("/users/verified-emails/verify", limits.Wrap(rateLimitEmailVerify, s.VerifyUserEmailToken))
+	r.Get("/users/notification-preferences", withActiveAuth(s.GetNotificationPreferences, s))
+	r.Put("/users/notification-preferences", withActiveAuth(s.UpdateNotificationPreferences, s))
 }
 
 func AddAdminRoutes(r *chi.Mux, s *handlers.AppService) {
@@ -161,6 +223,11 @@ func AddAdminRoutes(r *chi.Mux, s *handlers.AppService) {
 	r.Put("/admin/locations", withAdmin(s.UpdateLocationApproval, s))
 	r.Get("/admin/affiliates", withAdmin(s.GetAffiliates, s))
 	r.Put("/admin/affiliates", withAdmin(s.UpdateAffiliate, s))
+	r.Get("/admin/api-keys", withAdmin(s.GetAdminAPIKeys, s))
+	r.Post("/admin/api-keys", withAdmin(s.CreateAdminAPIKey, s))
+	r.Delete("/admin/api-keys/{key_id}", withAdmin(s.RevokeAdminAPIKey, s))
+	r.Get("/admin/audit-log", withAdmin(s.GetAdminAuditLog, s))
+	r.Get("/admin/audit-log.csv", withAdmin(s.ExportAdminAuditLog, s))
 }
 
 func AddAffiliateRoutes(r *chi.Mux, s *handlers.BotService, a *handlers.AppService) {
@@ -170,7 +237,13 @@ func AddAffiliateRoutes(r *chi.Mux, s *handlers.BotService, a *handlers.AppServi
 	r.Post("/affiliates/events", withAffiliate(s.AffiliateNewEvent, a))
 	r.Get("/affiliates/events", withAffiliate(s.AffiliateGetEvents, a))
 	r.Get("/affiliates/events/{event}", withAffiliate(s.AffiliateGetCodes, a))
+	r.Get("/affiliates/events/{event_id}/codes/export", withAffiliate(s.AffiliateExportCodes, a))
+	r.Get("/affiliates/events/{event_id}/reconciliation", withAffiliate(s.AffiliateReconcileRedemptions, a))
 	r.Delete("/affiliates/events/{event}", withAffiliate(s.AffiliateDeleteEvent, a))
+	r.Post("/affiliates/event-templates", withAffiliate(s.AffiliateNewEventTemplate, a))
+	r.Get("/affiliates/event-templates", withAffiliate(s.AffiliateGetEventTemplates, a))
+	r.Delete("/affiliates/event-templates/{template_id}", withAffiliate(s.AffiliateDeleteEventTemplate, a))
+	r.Post("/affiliates/event-templates/{template_id}/events", withAffiliate(s.AffiliateNewEventFromTemplate, a))
 	r.Get("/affiliates/{user_id}", withAffiliate(a.GetAffiliate, a))
 }
 
@@ -212,6 +285,7 @@ func AddWorkflowRoutes(r *chi.Mux, s *handlers.BotService, a *handlers.AppServic
 
 	r.Get("/supervisors/workflows", withSupervisor(a.GetSupervisorWorkflows, a))
 	r.Post("/supervisors/workflows/export", withSupervisor(a.ExportSupervisorWorkflowData, a))
+	r.Post("/supervisors/workflows/{workflow_id}/steps/{step_id}/review", withSupervisor(a.ReviewWorkflowStep, a))
 	r.Put("/supervisors/primary-rewards-account", withSupervisor(a.UpdateSupervisorPrimaryRewardsAccount, a))
 
 	r.Get("/admin/proposers", withAdmin(a.GetProposers, a))
@@ -236,6 +310,14 @@ func AddWorkflowRoutes(r *chi.Mux, s *handlers.BotService, a *handlers.AppServic
 	r.Post("/admin/workflow-edit-proposals/{proposal_id}/force-approve", withAdmin(a.AdminForceApproveWorkflowEditProposal, a))
 	r.Post("/admin/workflow-deletion-proposals/{proposal_id}/force-approve", withAdmin(a.AdminForceApproveWorkflowDeletionProposal, a))
 	r.Post("/admin/workflows/{workflow_id}/payout-lock-resolution", withAdmin(a.ResolveAdminWorkflowPayoutLock, a))
+	r.Get("/admin/workflow-payout-jobs", withAdmin(a.GetAdminWorkflowPayoutJobs, a))
+	r.Post("/admin/workflow-payout-jobs/{job_id}/requeue", withAdmin(a.RequeueAdminWorkflowPayoutJob, a))
+	r.Get("/admin/workflow-photo-reuse", withAdmin(a.GetAdminWorkflowPhotoReuses, a))
+	r.Get("/admin/email-outbox", withAdmin(a.GetAdminEmailOutbox, a))
+	r.Post("/admin/email-outbox/{message_id}/resend", withAdmin(a.ResendAdminEmailOutboxMessage, a))
+	r.Get("/admin/email-templates", withAdmin(a.GetAdminEmailTemplates, a))
+	r.Get("/admin/email-templates/{name}/preview", withAdmin(a.PreviewAdminEmailTemplate, a))
+	r.Post("/email/mailgun/events", a.MailgunEventsWebhook)
 
 	r.Get("/voters/workflows", withVoter(a.GetVoterWorkflows, a))
 	r.Get("/voters/workflows/{workflow_id}", withVoter(a.GetVoterWorkflow, a))
@@ -246,6 +328,7 @@ func AddWorkflowRoutes(r *chi.Mux, s *handlers.BotService, a *handlers.AppServic
 	r.Get("/workflows/{workflow_id}", withActiveAuth(a.GetWorkflow, a))
 	r.Get("/workflow-photos/public/{photo_id}", a.GetPublicWorkflowPhoto)
 	r.Get("/workflow-photos/{photo_id}", withActiveAuth(a.GetWorkflowPhoto, a))
+	r.Get("/workflow-photos/{photo_id}/signed", a.GetSignedWorkflowPhoto)
 	r.Post("/workflows/{workflow_id}/votes", withVoter(a.VoteWorkflow, a))
 	r.Post("/workflow-edit-proposals/{proposal_id}/votes", withVoter(a.VoteWorkflowEditProposal, a))
 	r.Post("/workflow-deletion-proposals/{proposal_id}/votes", withVoter(a.VoteWorkflowDeletionProposal, a))
@@ -265,10 +348,10 @@ func AddWalletRoutes(r *chi.Mux, s *handlers.AppService) {
 	r.Put("/wallets", withActiveAuth(s.UpdateWallet, s))
 }
 
-func AddLocationRoutes(r *chi.Mux, s *handlers.AppService) {
+func AddLocationRoutes(r *chi.Mux, s *handlers.AppService, limits *ratelimit.Limiter) {
 	r.Post("/locations", withActiveAuth(s.AddLocation, s))
-	r.Get("/locations/{id}", s.GetLocation)
-	r.Get("/locations", s.GetLocations)
+	r.Get("/locations/{id}", limits.Wrap(rateLimitLocations, s.GetLocation))
+	r.Get("/locations", limits.Wrap(rateLimitLocations, s.GetLocations))
 	r.Get("/locations/user", withActiveAuth(s.GetLocationsByUser, s))
 	r.Put("/locations", withActiveAuth(s.UpdateLocation, s))
 	r.Put("/locations/{id}/wallet-settings", withActiveAuth(s.UpdateLocationWalletSettings, s))
@@ -303,13 +386,13 @@ func AddPonderRoutes(r *chi.Mux, s *handlers.AppService, p *handlers.PonderServi
 	r.Get("/transactions", p.GetTransactionHistory)
 	r.Post("/transactions/memo", withActiveAuth(p.UpsertTransactionMemo, s))
 	r.Get("/transactions/balance", withActiveAuth(p.GetBalanceAtTimestamp, s))
-	r.Get("/admin/analytics/dashboard", withAdmin(p.GetAdminAnalyticsDashboard, s))
+	r.Get("/admin/analytics/dashboard", withAdminScope(structs.APIKeyScopeAnalyticsRead, p.GetAdminAnalyticsDashboard, s))
 }
 
-func AddW9Routes(r *chi.Mux, s *handlers.AppService) {
-	r.Post("/w9/submit", s.SubmitW9)
-	r.Post("/w9/transaction", withAdmin(s.RecordW9Transaction, s))
-	r.Post("/w9/check", withAdmin(s.CheckW9Compliance, s))
+func AddW9Routes(r *chi.Mux, s *handlers.AppService, limits *ratelimit.Limiter) {
+	r.Post("/w9/submit", limits.Wrap(rateLimitW9Submit, s.SubmitW9))
+	r.Post("/w9/transaction", withAdminScope(structs.APIKeyScopeW9Write, s.RecordW9Transaction, s))
+	r.Post("/w9/check", withAdminScope(structs.APIKeyScopeW9Write, s.CheckW9Compliance, s))
 	r.Get("/admin/w9/pending", withAdmin(s.GetPendingW9Submissions, s))
 	r.Put("/admin/w9/approve", withAdmin(s.ApproveW9Submission, s))
 	r.Put("/admin/w9/reject", withAdmin(s.RejectW9Submission, s))
@@ -379,18 +462,47 @@ func withActiveAuth(handlerFunc http.HandlerFunc, s *handlers.AppService) http.H
 }
 
 func withAdmin(handlerFunc http.HandlerFunc, s *handlers.AppService) http.HandlerFunc {
+	return withAdminScope(structs.APIKeyScopeAdmin, handlerFunc, s)
+}
+
+var legacyAdminKeyWarning sync.Once
+
+// withAdminScope admits signed-in admins, and server-to-server callers whose
+// X-Admin-Key is an issued API key carrying scope. A key request acts as the
+// key's owner and carries the key id for audit records. The ADMIN_KEY env
+// value is still accepted with full access while integrations move to issued
+// keys.
+func withAdminScope(scope string, handlerFunc http.HandlerFunc, s *handlers.AppService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		reqKey := r.Header.Get("X-Admin-Key")
-		envKey := os.Getenv("ADMIN_KEY")
-		if reqKey == envKey && envKey != "" {
-			if _, ok := r.Context().Value("userDid").(string); !ok {
-				adminId := s.GetFirstAdminId(r.Context())
-				if adminId != "" {
-					ctx := context.WithValue(r.Context(), "userDid", adminId)
-					r = r.WithContext(ctx)
+		if reqKey := strings.TrimSpace(r.Header.Get("X-Admin-Key")); reqKey != "" {
+			envKey := os.Getenv("ADMIN_KEY")
+			if envKey != "" && subtle.ConstantTimeCompare([]byte(reqKey), []byte(envKey)) == 1 {
+				legacyAdminKeyWarning.Do(func() {
+					slog.WarnContext(r.Context(), "request authenticated with deprecated ADMIN_KEY; issue a scoped api key instead")
+				})
+				ctx := context.WithValue(r.Context(), "apiKeyId", structs.LegacyAdminKeyId)
+				if _, ok := ctx.Value("userDid").(string); !ok {
+					adminId := s.GetFirstAdminId(ctx)
+					if adminId != "" {
+						ctx = context.WithValue(ctx, "userDid", adminId)
+					}
 				}
+				handlerFunc(w, r.WithContext(ctx))
+				return
 			}
-			handlerFunc(w, r)
+
+			key, err := s.AuthenticateAPIKey(r.Context(), reqKey)
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if !key.HasScope(scope) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			ctx := context.WithValue(r.Context(), "userDid", key.OwnerUserId)
+			ctx = context.WithValue(ctx, "apiKeyId", key.Id)
+			handlerFunc(w, r.WithContext(ctx))
 			return
 		}
 
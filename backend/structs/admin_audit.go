@@ -0,0 +1,65 @@
+package structs
+
+import "encoding/json"
+
+// Admin audit actions, named <target>.<verb>.
+const (
+	AdminAuditUserRoleUpdate               = "user_role.update"
+	AdminAuditProposerUpdate               = "proposer.update"
+	AdminAuditImproverUpdate               = "improver.update"
+	AdminAuditSupervisorUpdate             = "supervisor.update"
+	AdminAuditAffiliateUpdate              = "affiliate.update"
+	AdminAuditIssuerRequestUpdate          = "issuer_request.update"
+	AdminAuditIssuerScopesUpdate           = "issuer_scopes.update"
+	AdminAuditLocationApprovalUpdate       = "location_approval.update"
+	AdminAuditW9Approve                    = "w9.approve"
+	AdminAuditW9Reject                     = "w9.reject"
+	AdminAuditWorkflowForceApprove         = "workflow.force_approve"
+	AdminAuditWorkflowEditForceApprove     = "workflow_edit_proposal.force_approve"
+	AdminAuditWorkflowDeletionForceApprove = "workflow_deletion_proposal.force_approve"
+	AdminAuditWorkflowPayoutLockResolve    = "workflow_payout_lock.resolve"
+	AdminAuditWorkflowPayoutJobRequeue     = "workflow_payout_job.requeue"
+	AdminAuditWorkflowSeriesClaimRevoke    = "workflow_series_claim.revoke"
+	AdminAuditWorkflowStepReviewApprove    = "workflow_step_review.approve"
+	AdminAuditWorkflowStepReviewReject     = "workflow_step_review.reject"
+	AdminAuditWorkflowStepReviewResubmit   = "workflow_step_review.request_resubmission"
+	AdminAuditCredentialRevoke             = "credential.revoke"
+	AdminAuditFaucetDrain                  = "faucet.drain"
+	AdminAuditAPIKeyCreate                 = "api_key.create"
+	AdminAuditAPIKeyRevoke                 = "api_key.revoke"
+)
+
+// AdminAuditEntry records one privileged mutation. Before and After hold the
+// target's state around the change, or null when there is no meaningful
+// state on that side (a drain, a newly created key). ApiKeyId is set when
+// the actor authenticated with an API key rather than a session.
+type AdminAuditEntry struct {
+	Id          int64           `json:"id"`
+	ActorUserId string          `json:"actor_user_id"`
+	ApiKeyId    string          `json:"api_key_id,omitempty"`
+	Action      string          `json:"action"`
+	TargetType  string          `json:"target_type"`
+	TargetId    string          `json:"target_id"`
+	Before      json.RawMessage `json:"before"`
+	After       json.RawMessage `json:"after"`
+	RequestId   string          `json:"request_id,omitempty"`
+	CreatedAt   int64           `json:"created_at"`
+}
+
+// AdminAuditFilter narrows the audit log query. Empty fields match
+// everything; From and To are inclusive unix seconds.
+type AdminAuditFilter struct {
+	ActorUserId string
+	Action      string
+	TargetType  string
+	TargetId    string
+	From        int64
+	To          int64
+}
+
+type AdminAuditListResponse struct {
+	Items []*AdminAuditEntry `json:"items"`
+	Total int                `json:"total"`
+	Page  int                `json:"page"`
+	Count int                `json:"count"`
+}
@@ -0,0 +1,66 @@
+package structs
+
+// API key scopes. A key is only accepted on admin routes tagged with one of
+// its scopes; APIKeyScopeAdmin covers every admin route.
+const (
+	APIKeyScopeAdmin         = "admin"
+	APIKeyScopeW9Write       = "w9:write"
+	APIKeyScopeEventsCreate  = "events:create"
+	APIKeyScopeEventsRead    = "events:read"
+	APIKeyScopeAnalyticsRead = "analytics:read"
+)
+
+var APIKeyScopes = []string{
+	APIKeyScopeAdmin,
+	APIKeyScopeW9Write,
+	APIKeyScopeEventsCreate,
+	APIKeyScopeEventsRead,
+	APIKeyScopeAnalyticsRead,
+}
+
+// LegacyAdminKeyId attributes actions taken with the deprecated ADMIN_KEY
+// env value in audit records.
+const LegacyAdminKeyId = "env:ADMIN_KEY"
+
+// APIKey is an issued server-to-server credential. Only a SHA-256 hash of the
+// key is stored; Prefix is kept in the clear to look the key up and to tell
+// keys apart in the admin list. Requests made with a key act as its owner,
+// who must still be an admin when the key is used.
+type APIKey struct {
+	Id              string   `json:"id"`
+	Name            string   `json:"name"`
+	OwnerUserId     string   `json:"owner_user_id"`
+	Prefix          string   `json:"prefix"`
+	KeyHash         string   `json:"-"`
+	Scopes          []string `json:"scopes"`
+	CreatedAt       int64    `json:"created_at"`
+	ExpiresAt       int64    `json:"expires_at"`
+	LastUsedAt      *int64   `json:"last_used_at,omitempty"`
+	RevokedAt       *int64   `json:"revoked_at,omitempty"`
+	RevokedByUserId *string  `json:"revoked_by_user_id,omitempty"`
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	if k == nil {
+		return false
+	}
+	for _, s := range k.Scopes {
+		if s == scope || s == APIKeyScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKeyCreateRequest struct {
+	Name        string   `json:"name"`
+	Scopes      []string `json:"scopes"`
+	OwnerUserId string   `json:"owner_user_id,omitempty"`
+	ExpiresAt   int64    `json:"expires_at,omitempty"`
+}
+
+// APIKeyCreateResponse carries the plaintext key, which is shown only once.
+type APIKeyCreateResponse struct {
+	Key    string  `json:"key"`
+	APIKey *APIKey `json:"api_key"`
+}
@@ -13,12 +13,15 @@ type PonderSubscriptionRequest struct {
 }
 
 type PushSubscriptionSyncRequest struct {
-	Token             string   `json:"token"`
-	Addresses         []string `json:"addresses"`
-	InstallationID    string   `json:"installation_id,omitempty"`
-	Enabled           *bool    `json:"enabled,omitempty"`
-	PreferenceEnabled *bool    `json:"preference_enabled,omitempty"`
-	DeviceRegistered  *bool    `json:"device_registered,omitempty"`
+	Token          string   `json:"token"`
+	Addresses      []string `json:"addresses"`
+	InstallationID string   `json:"installation_id,omitempty"`
+	// Provider names the push service the token belongs to: expo (default),
+	// apns or fcm.
+	Provider          string `json:"provider,omitempty"`
+	Enabled           *bool  `json:"enabled,omitempty"`
+	PreferenceEnabled *bool  `json:"preference_enabled,omitempty"`
+	DeviceRegistered  *bool  `json:"device_registered,omitempty"`
 }
 
 type PonderSubscription struct {
@@ -41,12 +44,16 @@ type MobilePushSubscription struct {
 	DeviceRegistered   bool             `json:"device_registered"`
 	InstallationIDHash string           `json:"-"`
 	PonderHookId       *int             `json:"ponder_hook_id,omitempty"`
+	Provider           string           `json:"provider"`
 }
 
 type PonderSubscriptionServerRequest struct {
 	Id      int    `json:"id"`
 	Address string `json:"address"`
 	Url     string `json:"url"`
+	// Secret signs this hook's callbacks. It is only sent when the hook is
+	// created or re-keyed.
+	Secret string `json:"secret,omitempty"`
 }
 
 type PonderHookData struct {
@@ -55,4 +62,7 @@ type PonderHookData struct {
 	From    string `json:"from"`
 	Hash    string `json:"hash"`
 	Amount  string `json:"amount"`
+	// LogIndex is nil on callbacks from Ponder builds that predate
+	// callback dedupe.
+	LogIndex *int `json:"log_index,omitempty"`
 }
@@ -142,22 +142,26 @@ type WorkflowCreateRequest struct {
 }
 
 type WorkflowTemplateCreateRequest struct {
-	TemplateTitle        string                        `json:"template_title"`
-	TemplateDescription  string                        `json:"template_description"`
-	SeriesId             *string                       `json:"series_id,omitempty"`
-	Recurrence           string                        `json:"recurrence"`
-	StartAt              *string                       `json:"start_at,omitempty"`
-	SupervisorUserId     *string                       `json:"supervisor_user_id,omitempty"`
-	SupervisorBounty     *uint64                       `json:"supervisor_bounty,omitempty"`
-	SupervisorDataFields []WorkflowSupervisorDataField `json:"supervisor_data_fields,omitempty"`
-	Manager              *WorkflowManagerCreateInput   `json:"manager,omitempty"`
-	Roles                []WorkflowRoleCreateInput     `json:"roles"`
-	Steps                []WorkflowStepCreateInput     `json:"steps"`
+	TemplateTitle              string                        `json:"template_title"`
+	TemplateDescription        string                        `json:"template_description"`
+	SeriesId                   *string                       `json:"series_id,omitempty"`
+	Recurrence                 string                        `json:"recurrence"`
+	StartAt                    *string                       `json:"start_at,omitempty"`
+	SupervisorUserId           *string                       `json:"supervisor_user_id,omitempty"`
+	SupervisorBounty           *uint64                       `json:"supervisor_bounty,omitempty"`
+	SupervisorApprovalRequired bool                          `json:"supervisor_approval_required,omitempty"`
+	SupervisorDataFields       []WorkflowSupervisorDataField `json:"supervisor_data_fields,omitempty"`
+	Manager                    *WorkflowManagerCreateInput   `json:"manager,omitempty"`
+	Roles                      []WorkflowRoleCreateInput     `json:"roles"`
+	Steps                      []WorkflowStepCreateInput     `json:"steps"`
 }
 
 type WorkflowSupervisorCreateInput struct {
 	UserId string `json:"user_id"`
 	Bounty uint64 `json:"bounty"`
+	// ApprovalRequired holds every step completion until the supervisor
+	// approves it.
+	ApprovalRequired bool `json:"approval_required,omitempty"`
 }
 
 type WorkflowSupervisorDataField struct {
@@ -182,21 +186,44 @@ type WorkflowStepCreateInput struct {
 	Bounty               uint64                        `json:"bounty"`
 	RoleClientId         string                        `json:"role_client_id"`
 	AllowStepNotPossible bool                          `json:"allow_step_not_possible"`
+	Geofence             *WorkflowStepGeofence         `json:"geofence,omitempty"`
+	CompletionWindow     *WorkflowStepCompletionWindow `json:"completion_window,omitempty"`
 	WorkItems            []WorkflowWorkItemCreateInput `json:"work_items"`
 }
 
+// WorkflowStepGeofence ties a step to a place. Completions reported outside
+// the radius, or with no location at all, are held for supervisor review
+// instead of being paid.
+type WorkflowStepGeofence struct {
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	RadiusMeters float64 `json:"radius_meters"`
+}
+
+// WorkflowStepCompletionWindow bounds when a step should be completed, in
+// minutes after the workflow's start_at so that it carries over to each
+// recurrence. Completions outside it are held for supervisor review.
+type WorkflowStepCompletionWindow struct {
+	OpensAfterMinutes  int `json:"opens_after_minutes"`
+	ClosesAfterMinutes int `json:"closes_after_minutes"`
+}
+
 type WorkflowWorkItemCreateInput struct {
 	Title              string                              `json:"title"`
 	Description        string                              `json:"description"`
 	Optional           bool                                `json:"optional"`
 	RequiresPhoto      bool                                `json:"requires_photo"`
 	CameraCaptureOnly  bool                                `json:"camera_capture_only"`
+	KeepPhotoLocation  bool                                `json:"keep_photo_location"`
 	PhotoRequiredCount int                                 `json:"photo_required_count"`
 	PhotoAllowAnyCount bool                                `json:"photo_allow_any_count"`
 	PhotoAspectRatio   string                              `json:"photo_aspect_ratio"`
 	RequiresWritten    bool                                `json:"requires_written_response"`
 	RequiresDropdown   bool                                `json:"requires_dropdown"`
 	DropdownOptions    []WorkflowDropdownOptionCreateInput `json:"dropdown_options"`
+	// BountyWeight is the item's share of the step bounty relative to the
+	// step's other items. Steps with no weighted items pay in full.
+	BountyWeight int `json:"bounty_weight,omitempty"`
 }
 
 type WorkflowDropdownOptionCreateInput struct {
@@ -209,6 +236,9 @@ type WorkflowDropdownOptionCreateInput struct {
 	NotifyEmailCount        int      `json:"notify_email_count,omitempty"`
 	NotifyEmailSubject      string   `json:"notify_email_subject,omitempty"`
 	SendPicturesWithEmail   bool     `json:"send_pictures_with_email,omitempty"`
+	// BountyWeight, when set, replaces the item's weight if this option is
+	// selected.
+	BountyWeight *int `json:"bounty_weight,omitempty"`
 }
 
 type WorkflowDropdownOption struct {
@@ -222,6 +252,7 @@ type WorkflowDropdownOption struct {
 	NotifyEmailCount        int      `json:"notify_email_count,omitempty"`
 	NotifyEmailSubject      string   `json:"notify_email_subject,omitempty"`
 	SendPicturesWithEmail   bool     `json:"send_pictures_with_email,omitempty"`
+	BountyWeight            *int     `json:"bounty_weight,omitempty"`
 }
 
 type Workflow struct {
@@ -249,6 +280,7 @@ type Workflow struct {
 	SupervisorRequired         bool                          `json:"supervisor_required"`
 	SupervisorUserId           *string                       `json:"supervisor_user_id,omitempty"`
 	SupervisorBounty           uint64                        `json:"supervisor_bounty"`
+	SupervisorApprovalRequired bool                          `json:"supervisor_approval_required"`
 	SupervisorDataFields       []WorkflowSupervisorDataField `json:"supervisor_data_fields,omitempty"`
 	SupervisorPaidOutAt        *int64                        `json:"supervisor_paid_out_at,omitempty"`
 	SupervisorPayoutError      *string                       `json:"supervisor_payout_error,omitempty"`
@@ -424,33 +456,34 @@ type WorkflowEditProposalVoteRequest struct {
 }
 
 type WorkflowEditProposal struct {
-	Id                  string                    `json:"id"`
-	SeriesId            string                    `json:"series_id"`
-	TargetWorkflowId    *string                   `json:"target_workflow_id,omitempty"`
-	ProposedStateId     string                    `json:"proposed_state_id"`
-	RequestedByUserId   string                    `json:"requested_by_user_id"`
-	Reason              string                    `json:"reason"`
-	Status              string                    `json:"status"`
-	VoteQuorumReachedAt *int64                    `json:"vote_quorum_reached_at,omitempty"`
-	VoteFinalizeAt      *int64                    `json:"vote_finalize_at,omitempty"`
-	VoteFinalizedAt     *int64                    `json:"vote_finalized_at,omitempty"`
-	VoteFinalizedBy     *string                   `json:"vote_finalized_by_user_id,omitempty"`
-	VoteDecision        *string                   `json:"vote_decision,omitempty"`
-	CreatedAt           int64                     `json:"created_at"`
-	UpdatedAt           int64                     `json:"updated_at"`
-	WorkflowTitle       string                    `json:"workflow_title"`
-	WorkflowDescription string                    `json:"workflow_description"`
-	WorkflowStartAt     int64                     `json:"workflow_start_at"`
-	Recurrence          string                    `json:"recurrence"`
-	RecurrenceEndAt     *int64                    `json:"recurrence_end_at,omitempty"`
-	SupervisorRequired  bool                      `json:"supervisor_required"`
-	SupervisorUserId    *string                   `json:"supervisor_user_id,omitempty"`
-	SupervisorBounty    uint64                    `json:"supervisor_bounty"`
-	TotalBounty         uint64                    `json:"total_bounty"`
-	WeeklyRequirement   uint64                    `json:"weekly_bounty_requirement"`
-	Roles               []WorkflowRoleCreateInput `json:"roles,omitempty"`
-	Steps               []WorkflowStepCreateInput `json:"steps,omitempty"`
-	Votes               WorkflowVotes             `json:"votes"`
+	Id                         string                    `json:"id"`
+	SeriesId                   string                    `json:"series_id"`
+	TargetWorkflowId           *string                   `json:"target_workflow_id,omitempty"`
+	ProposedStateId            string                    `json:"proposed_state_id"`
+	RequestedByUserId          string                    `json:"requested_by_user_id"`
+	Reason                     string                    `json:"reason"`
+	Status                     string                    `json:"status"`
+	VoteQuorumReachedAt        *int64                    `json:"vote_quorum_reached_at,omitempty"`
+	VoteFinalizeAt             *int64                    `json:"vote_finalize_at,omitempty"`
+	VoteFinalizedAt            *int64                    `json:"vote_finalized_at,omitempty"`
+	VoteFinalizedBy            *string                   `json:"vote_finalized_by_user_id,omitempty"`
+	VoteDecision               *string                   `json:"vote_decision,omitempty"`
+	CreatedAt                  int64                     `json:"created_at"`
+	UpdatedAt                  int64                     `json:"updated_at"`
+	WorkflowTitle              string                    `json:"workflow_title"`
+	WorkflowDescription        string                    `json:"workflow_description"`
+	WorkflowStartAt            int64                     `json:"workflow_start_at"`
+	Recurrence                 string                    `json:"recurrence"`
+	RecurrenceEndAt            *int64                    `json:"recurrence_end_at,omitempty"`
+	SupervisorRequired         bool                      `json:"supervisor_required"`
+	SupervisorUserId           *string                   `json:"supervisor_user_id,omitempty"`
+	SupervisorBounty           uint64                    `json:"supervisor_bounty"`
+	SupervisorApprovalRequired bool                      `json:"supervisor_approval_required"`
+	TotalBounty                uint64                    `json:"total_bounty"`
+	WeeklyRequirement          uint64                    `json:"weekly_bounty_requirement"`
+	Roles                      []WorkflowRoleCreateInput `json:"roles,omitempty"`
+	Steps                      []WorkflowStepCreateInput `json:"steps,omitempty"`
+	Votes                      WorkflowVotes             `json:"votes"`
 }
 
 type WorkflowProposalExpiryNotice struct {
@@ -469,23 +502,24 @@ type WorkflowProposalOutcomeNotification struct {
 }
 
 type WorkflowTemplate struct {
-	Id                   string                        `json:"id"`
-	TemplateTitle        string                        `json:"template_title"`
-	TemplateDescription  string                        `json:"template_description"`
-	OwnerUserId          *string                       `json:"owner_user_id,omitempty"`
-	CreatedByUserId      string                        `json:"created_by_user_id"`
-	IsDefault            bool                          `json:"is_default"`
-	Recurrence           string                        `json:"recurrence"`
-	StartAt              int64                         `json:"start_at"`
-	SeriesId             *string                       `json:"series_id,omitempty"`
-	SupervisorUserId     *string                       `json:"supervisor_user_id,omitempty"`
-	SupervisorBounty     *uint64                       `json:"supervisor_bounty,omitempty"`
-	SupervisorDataFields []WorkflowSupervisorDataField `json:"supervisor_data_fields,omitempty"`
-	Manager              *WorkflowManagerCreateInput   `json:"-"`
-	Roles                []WorkflowRoleCreateInput     `json:"roles"`
-	Steps                []WorkflowStepCreateInput     `json:"steps"`
-	CreatedAt            int64                         `json:"created_at"`
-	UpdatedAt            int64                         `json:"updated_at"`
+	Id                         string                        `json:"id"`
+	TemplateTitle              string                        `json:"template_title"`
+	TemplateDescription        string                        `json:"template_description"`
+	OwnerUserId                *string                       `json:"owner_user_id,omitempty"`
+	CreatedByUserId            string                        `json:"created_by_user_id"`
+	IsDefault                  bool                          `json:"is_default"`
+	Recurrence                 string                        `json:"recurrence"`
+	StartAt                    int64                         `json:"start_at"`
+	SeriesId                   *string                       `json:"series_id,omitempty"`
+	SupervisorUserId           *string                       `json:"supervisor_user_id,omitempty"`
+	SupervisorBounty           *uint64                       `json:"supervisor_bounty,omitempty"`
+	SupervisorApprovalRequired bool                          `json:"supervisor_approval_required"`
+	SupervisorDataFields       []WorkflowSupervisorDataField `json:"supervisor_data_fields,omitempty"`
+	Manager                    *WorkflowManagerCreateInput   `json:"-"`
+	Roles                      []WorkflowRoleCreateInput     `json:"roles"`
+	Steps                      []WorkflowStepCreateInput     `json:"steps"`
+	CreatedAt                  int64                         `json:"created_at"`
+	UpdatedAt                  int64                         `json:"updated_at"`
 }
 
 type WorkflowRole struct {
@@ -497,26 +531,64 @@ type WorkflowRole struct {
 }
 
 type WorkflowStep struct {
-	Id                   string                  `json:"id"`
-	WorkflowId           string                  `json:"workflow_id"`
-	StepOrder            int                     `json:"step_order"`
-	Title                string                  `json:"title"`
-	Description          string                  `json:"description"`
-	Bounty               uint64                  `json:"bounty"`
-	AllowStepNotPossible bool                    `json:"allow_step_not_possible"`
-	RoleId               *string                 `json:"role_id,omitempty"`
-	AssignedImproverId   *string                 `json:"assigned_improver_id,omitempty"`
-	AssignedImproverName *string                 `json:"assigned_improver_name,omitempty"`
-	Status               string                  `json:"status"`
-	StartedAt            *int64                  `json:"started_at,omitempty"`
-	CompletedAt          *int64                  `json:"completed_at,omitempty"`
-	PayoutError          *string                 `json:"payout_error,omitempty"`
-	PayoutLastTryAt      *int64                  `json:"payout_last_try_at,omitempty"`
-	PayoutInProgress     bool                    `json:"-"`
-	RetryRequestedAt     *int64                  `json:"retry_requested_at,omitempty"`
-	RetryRequestedBy     *string                 `json:"retry_requested_by,omitempty"`
-	Submission           *WorkflowStepSubmission `json:"submission,omitempty"`
-	WorkItems            []WorkflowWorkItem      `json:"work_items"`
+	Id                   string                        `json:"id"`
+	WorkflowId           string                        `json:"workflow_id"`
+	StepOrder            int                           `json:"step_order"`
+	Title                string                        `json:"title"`
+	Description          string                        `json:"description"`
+	Bounty               uint64                        `json:"bounty"`
+	FullBounty           *uint64                       `json:"full_bounty,omitempty"`
+	AllowStepNotPossible bool                          `json:"allow_step_not_possible"`
+	RoleId               *string                       `json:"role_id,omitempty"`
+	AssignedImproverId   *string                       `json:"assigned_improver_id,omitempty"`
+	AssignedImproverName *string                       `json:"assigned_improver_name,omitempty"`
+	Status               string                        `json:"status"`
+	StartedAt            *int64                        `json:"started_at,omitempty"`
+	CompletedAt          *int64                        `json:"completed_at,omitempty"`
+	PayoutError          *string                       `json:"payout_error,omitempty"`
+	PayoutLastTryAt      *int64                        `json:"payout_last_try_at,omitempty"`
+	PayoutInProgress     bool                          `json:"-"`
+	RetryRequestedAt     *int64                        `json:"retry_requested_at,omitempty"`
+	RetryRequestedBy     *string                       `json:"retry_requested_by,omitempty"`
+	Geofence             *WorkflowStepGeofence         `json:"geofence,omitempty"`
+	CompletionWindow     *WorkflowStepCompletionWindow `json:"completion_window,omitempty"`
+	ReviewStatus         string                        `json:"review_status,omitempty"`
+	ReviewedAt           *int64                        `json:"reviewed_at,omitempty"`
+	ReviewedBy           *string                       `json:"reviewed_by,omitempty"`
+	ReviewComment        *string                       `json:"review_comment,omitempty"`
+	Submission           *WorkflowStepSubmission       `json:"submission,omitempty"`
+	WorkItems            []WorkflowWorkItem            `json:"work_items"`
+}
+
+// Workflow step review statuses. A step with no review status was never
+// held. Pending and flagged completions are not paid until a supervisor
+// approves them; rejected ones are never paid, and a step sent back for
+// resubmission returns to in_progress until the improver completes it again.
+const (
+	WorkflowStepReviewPending               = "pending_review"
+	WorkflowStepReviewFlagged               = "flagged"
+	WorkflowStepReviewApproved              = "approved"
+	WorkflowStepReviewRejected              = "rejected"
+	WorkflowStepReviewResubmissionRequested = "resubmission_requested"
+)
+
+// Supervisor decisions on a held step completion.
+const (
+	WorkflowStepReviewDecisionApprove             = "approve"
+	WorkflowStepReviewDecisionReject              = "reject"
+	WorkflowStepReviewDecisionRequestResubmission = "request_resubmission"
+)
+
+// Reasons a step completion is flagged for review.
+const (
+	WorkflowStepFlagOutsideGeofence         = "outside_geofence"
+	WorkflowStepFlagLocationUnverified      = "location_unverified"
+	WorkflowStepFlagOutsideCompletionWindow = "outside_completion_window"
+)
+
+type WorkflowStepReviewFlag struct {
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
 }
 
 type WorkflowWorkItem struct {
@@ -528,6 +600,7 @@ type WorkflowWorkItem struct {
 	Optional                   bool                     `json:"optional"`
 	RequiresPhoto              bool                     `json:"requires_photo"`
 	CameraCaptureOnly          bool                     `json:"camera_capture_only"`
+	KeepPhotoLocation          bool                     `json:"keep_photo_location"`
 	PhotoRequiredCount         int                      `json:"photo_required_count"`
 	PhotoAllowAnyCount         bool                     `json:"photo_allow_any_count"`
 	PhotoAspectRatio           string                   `json:"photo_aspect_ratio"`
@@ -535,6 +608,7 @@ type WorkflowWorkItem struct {
 	RequiresDropdown           bool                     `json:"requires_dropdown"`
 	DropdownOptions            []WorkflowDropdownOption `json:"dropdown_options"`
 	DropdownRequiresWrittenMap map[string]bool          `json:"dropdown_requires_written_response"`
+	BountyWeight               int                      `json:"bounty_weight"`
 }
 
 type WorkflowVotes struct {
@@ -643,20 +717,49 @@ type WorkflowStepPhotoUploadResult struct {
 }
 
 type WorkflowSubmissionPhoto struct {
-	Id           string `json:"id"`
-	WorkflowId   string `json:"workflow_id"`
-	StepId       string `json:"step_id"`
-	ItemId       string `json:"item_id"`
-	SubmissionId string `json:"submission_id"`
-	FileName     string `json:"file_name"`
-	ContentType  string `json:"content_type"`
-	SizeBytes    int    `json:"size_bytes"`
-	CreatedAt    int64  `json:"created_at"`
+	Id               string  `json:"id"`
+	WorkflowId       string  `json:"workflow_id"`
+	StepId           string  `json:"step_id"`
+	ItemId           string  `json:"item_id"`
+	SubmissionId     string  `json:"submission_id"`
+	FileName         string  `json:"file_name"`
+	ContentType      string  `json:"content_type"`
+	SizeBytes        int     `json:"size_bytes"`
+	LocationStripped bool    `json:"location_stripped"`
+	ReusedPhotoId    *string `json:"reused_photo_id,omitempty"`
+	ReuseDistance    *int    `json:"reuse_distance,omitempty"`
+	CreatedAt        int64   `json:"created_at"`
 }
 
 type WorkflowSubmissionPhotoBlob struct {
 	WorkflowSubmissionPhoto
-	PhotoData []byte `json:"-"`
+	PhotoData      []byte `json:"-"`
+	StorageBackend string `json:"-"`
+	StorageKey     string `json:"-"`
+}
+
+// WorkflowPhotoURL is a short-lived link to a photo that needs no further
+// authentication.
+type WorkflowPhotoURL struct {
+	URL       string `json:"url"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// WorkflowPhotoReuse is a submitted photo that looks like one already
+// uploaded for a different step.
+type WorkflowPhotoReuse struct {
+	Photo            WorkflowSubmissionPhoto `json:"photo"`
+	ImproverId       string                  `json:"improver_id"`
+	ReusedWorkflowId string                  `json:"reused_workflow_id"`
+	ReusedStepId     string                  `json:"reused_step_id"`
+	ReusedCreatedAt  int64                   `json:"reused_created_at"`
+}
+
+type WorkflowPhotoReuseListResponse struct {
+	Items []*WorkflowPhotoReuse `json:"items"`
+	Total int                   `json:"total"`
+	Page  int                   `json:"page"`
+	Count int                   `json:"count"`
 }
 
 type WorkflowSubmissionPhotoExport struct {
@@ -676,6 +779,7 @@ type WorkflowStepSubmission struct {
 	StepNotPossible        bool                       `json:"step_not_possible"`
 	StepNotPossibleDetails *string                    `json:"step_not_possible_details,omitempty"`
 	ItemResponses          []WorkflowStepItemResponse `json:"item_responses"`
+	ReviewFlags            []WorkflowStepReviewFlag   `json:"review_flags,omitempty"`
 	SubmittedAt            int64                      `json:"submitted_at"`
 	UpdatedAt              int64                      `json:"updated_at"`
 }
@@ -683,9 +787,23 @@ type WorkflowStepSubmission struct {
 type WorkflowStepCompleteRequest struct {
 	StepNotPossible        bool                       `json:"step_not_possible"`
 	StepNotPossibleDetails *string                    `json:"step_not_possible_details,omitempty"`
+	Location               *WorkflowStepLocation      `json:"location,omitempty"`
 	Items                  []WorkflowStepItemResponse `json:"items"`
 }
 
+// WorkflowStepLocation is the device position an improver reports when
+// completing a step.
+type WorkflowStepLocation struct {
+	Latitude       float64  `json:"latitude"`
+	Longitude      float64  `json:"longitude"`
+	AccuracyMeters *float64 `json:"accuracy_meters,omitempty"`
+}
+
+type WorkflowStepReviewRequest struct {
+	Decision string `json:"decision"`
+	Comment  string `json:"comment,omitempty"`
+}
+
 type AdminWorkflowPayoutResolutionRequest struct {
 	TargetType   string `json:"target_type"`
 	Action       string `json:"action"`
@@ -810,6 +928,7 @@ type SupervisorWorkflowListItem struct {
 	SupervisorUserId       *string `json:"supervisor_user_id,omitempty"`
 	SupervisorTitle        *string `json:"supervisor_title,omitempty"`
 	SupervisorOrganization *string `json:"supervisor_organization,omitempty"`
+	StepsAwaitingReview    int     `json:"steps_awaiting_review"`
 }
 
 type SupervisorWorkflowListResponse struct {
@@ -828,6 +947,7 @@ type SupervisorWorkflowExportRequest struct {
 	DateFrom         string   `json:"date_from"`
 	DateTo           string   `json:"date_to"`
 	Timezone         string   `json:"timezone,omitempty"`
+	PhotoSize        string   `json:"photo_size,omitempty"`
 }
 
 type UserCredential struct {
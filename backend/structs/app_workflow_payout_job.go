@@ -0,0 +1,53 @@
+package structs
+
+// Workflow payout job statuses for workflow_payout_jobs.status.
+const (
+	WorkflowPayoutJobStatusQueued    = "queued"
+	WorkflowPayoutJobStatusLeased    = "leased"
+	WorkflowPayoutJobStatusSucceeded = "succeeded"
+	WorkflowPayoutJobStatusDead      = "dead"
+)
+
+// Workflow payout job target types. "supervisor" is the workflow manager
+// payout, matching the admin payout-lock resolution target names.
+const (
+	WorkflowPayoutTargetStep       = "step"
+	WorkflowPayoutTargetSupervisor = "supervisor"
+)
+
+// WorkflowPayoutJob is one durable payout unit owned by the payout queue. A job
+// is leased by a worker, holds the target's payout lock while it runs, and
+// keeps the submitted tx hash so a restarted worker can resume confirmation
+// instead of re-sending.
+type WorkflowPayoutJob struct {
+	Id             string `json:"id"`
+	WorkflowId     string `json:"workflow_id"`
+	WorkflowTitle  string `json:"workflow_title,omitempty"`
+	StepId         string `json:"step_id,omitempty"`
+	TargetType     string `json:"target_type"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	MaxAttempts    int    `json:"max_attempts"`
+	RunAt          int64  `json:"run_at"`
+	LeaseOwner     string `json:"lease_owner,omitempty"`
+	LeaseExpiresAt *int64 `json:"lease_expires_at,omitempty"`
+	HeartbeatAt    *int64 `json:"heartbeat_at,omitempty"`
+	TargetLocked   bool   `json:"target_locked"`
+	WalletAddress  string `json:"wallet_address,omitempty"`
+	Amount         uint64 `json:"amount"`
+	TxHash         string `json:"tx_hash,omitempty"`
+	TxChainId      *int64 `json:"tx_chain_id,omitempty"`
+	TxSubmittedAt  *int64 `json:"tx_submitted_at,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+	CompletedAt    *int64 `json:"completed_at,omitempty"`
+	RequestId      string `json:"request_id,omitempty"`
+}
+
+type WorkflowPayoutJobListResponse struct {
+	Items []*WorkflowPayoutJob `json:"items"`
+	Total int                  `json:"total"`
+	Page  int                  `json:"page"`
+	Count int                  `json:"count"`
+}
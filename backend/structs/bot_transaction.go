@@ -0,0 +1,30 @@
+package structs
+
+// Bot transaction statuses for bot_transactions.status. Every broadcast,
+// including gas-bump replacements, gets its own row; rows that share a nonce
+// share an original_hash.
+const (
+	BotTransactionStatusPending   = "pending"
+	BotTransactionStatusReplaced  = "replaced"
+	BotTransactionStatusConfirmed = "confirmed"
+	BotTransactionStatusFailed    = "failed"
+	BotTransactionStatusDropped   = "dropped"
+)
+
+type BotTransaction struct {
+	Hash         string `json:"hash"`
+	OriginalHash string `json:"original_hash"`
+	ChainId      int64  `json:"chain_id"`
+	FromAddress  string `json:"from_address"`
+	ToAddress    string `json:"to_address"`
+	Nonce        uint64 `json:"nonce"`
+	Data         string `json:"data"`
+	GasLimit     uint64 `json:"gas_limit"`
+	GasFeeCap    string `json:"gas_fee_cap"`
+	GasTipCap    string `json:"gas_tip_cap"`
+	RawTx        string `json:"raw_tx"`
+	BumpCount    int    `json:"bump_count"`
+	Status       string `json:"status"`
+	SubmittedAt  int64  `json:"submitted_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
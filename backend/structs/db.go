@@ -9,6 +9,13 @@ type Event struct {
 	StartAt     uint64 `json:"start_at"`
 	Expiration  uint64 `json:"expiration"`
 	Owner       string `json:"owner"`
+	// Redemption policy. A MaxPerAddress of 0 keeps the default of one code
+	// per address; 0 leaves the user limit and the cooldown off. A user limit
+	// also turns away addresses no user owns.
+	MaxPerAddress   uint32 `json:"max_per_address"`
+	MaxPerUser      uint32 `json:"max_per_user"`
+	CooldownSeconds uint64 `json:"cooldown_seconds"`
+	TemplateId      string `json:"template_id,omitempty"`
 }
 
 type EventsRequest struct {
@@ -24,6 +31,15 @@ type Code struct {
 	Event    string `json:"event"`
 }
 
+// CodeExportRow is a faucet code with its redemption, as listed in printable
+// code sheet exports.
+type CodeExportRow struct {
+	Id         string `json:"id"`
+	Redeemed   bool   `json:"redeemed"`
+	RedeemedBy string `json:"redeemed_by,omitempty"`
+	ChainId    int64  `json:"chain_id,omitempty"`
+}
+
 type CodesPageRequest struct {
 	Event string `json:"event"`
 	Count uint32 `json:"count"`
@@ -35,6 +51,23 @@ type RedeemRequest struct {
 	Address string `json:"address"`
 }
 
+// RedeemClaimant identifies who is redeeming a code beyond the payout
+// address, so event limits can count per verified user. UserId comes from
+// the session or the payout address's owner, never from the request body.
+type RedeemClaimant struct {
+	UserId string
+}
+
+// RedemptionLimit is the structured reason an event's redemption policy
+// rejected a claim. Reason is one of user_limit, user_required or cooldown;
+// address limits keep the plain "user redeemed" response.
+type RedemptionLimit struct {
+	Error      string `json:"error"`
+	Reason     string `json:"reason"`
+	Limit      uint32 `json:"limit,omitempty"`
+	RetryAfter int64  `json:"retry_after,omitempty"`
+}
+
 type NewCodesRequest struct {
 	Event string `json:"event"`
 	Count uint32 `json:"count"`
@@ -0,0 +1,51 @@
+package structs
+
+const (
+	EmailOutboxStatusPending = "pending"
+	EmailOutboxStatusSending = "sending"
+	EmailOutboxStatusSent    = "sent"
+	EmailOutboxStatusFailed  = "failed"
+	EmailOutboxStatusBounced = "bounced"
+)
+
+type EmailOutboxAttachment struct {
+	Filename string `json:"filename"`
+	Data     []byte `json:"data"`
+}
+
+// EmailOutboxMessage is one queued email. The outbox worker leases pending
+// messages, hands them to the configured sink and retries with backoff until
+// they are sent or run out of attempts. Bounces reported by the provider
+// move a sent message to bounced.
+type EmailOutboxMessage struct {
+	Id                string                  `json:"id"`
+	Category          string                  `json:"category,omitempty"`
+	UserId            string                  `json:"user_id,omitempty"`
+	ToEmail           string                  `json:"to_email"`
+	ToName            string                  `json:"to_name,omitempty"`
+	FromEmail         string                  `json:"from_email"`
+	FromName          string                  `json:"from_name,omitempty"`
+	Subject           string                  `json:"subject"`
+	HTML              string                  `json:"-"`
+	Text              string                  `json:"-"`
+	Attachments       []EmailOutboxAttachment `json:"-"`
+	Status            string                  `json:"status"`
+	Attempts          int                     `json:"attempts"`
+	MaxAttempts       int                     `json:"max_attempts"`
+	RunAt             int64                   `json:"run_at"`
+	Sink              string                  `json:"sink,omitempty"`
+	ProviderMessageId string                  `json:"provider_message_id,omitempty"`
+	LastError         string                  `json:"last_error,omitempty"`
+	CreatedAt         int64                   `json:"created_at"`
+	UpdatedAt         int64                   `json:"updated_at"`
+	SentAt            *int64                  `json:"sent_at,omitempty"`
+	BouncedAt         *int64                  `json:"bounced_at,omitempty"`
+	RequestId         string                  `json:"request_id,omitempty"`
+}
+
+type EmailOutboxListResponse struct {
+	Items []*EmailOutboxMessage `json:"items"`
+	Total int                   `json:"total"`
+	Page  int                   `json:"page"`
+	Count int                   `json:"count"`
+}
@@ -0,0 +1,41 @@
+package structs
+
+// Event template recurrences. Templates with no recurrence are only used to
+// create events on demand.
+const (
+	EventRecurrenceNone    = "none"
+	EventRecurrenceWeekly  = "weekly"
+	EventRecurrenceMonthly = "monthly"
+)
+
+// EventTemplate holds the settings an affiliate reuses for a regular event.
+// Recurring templates create their next event at NextStartAt. AnchorStartAt
+// is the first start the template was created with; monthly templates keep
+// its day of the month, moving to the last day in shorter months.
+type EventTemplate struct {
+	Id              string `json:"id"`
+	Owner           string `json:"owner"`
+	Title           string `json:"title"`
+	Description     string `json:"description"`
+	Codes           uint32 `json:"codes"`
+	Amount          uint64 `json:"amount"`
+	DurationSeconds uint64 `json:"duration_seconds"`
+	MaxPerAddress   uint32 `json:"max_per_address"`
+	MaxPerUser      uint32 `json:"max_per_user"`
+	CooldownSeconds uint64 `json:"cooldown_seconds"`
+	Recurrence      string `json:"recurrence"`
+	NextStartAt     uint64 `json:"next_start_at"`
+	AnchorStartAt   uint64 `json:"anchor_start_at,omitempty"`
+	Active          bool   `json:"active"`
+	LastEventId     string `json:"last_event_id,omitempty"`
+	LastRunAt       uint64 `json:"last_run_at,omitempty"`
+	LastError       string `json:"last_error,omitempty"`
+	CreatedAt       int64  `json:"created_at"`
+	UpdatedAt       int64  `json:"updated_at"`
+}
+
+// EventFromTemplateRequest creates an event from a template. StartAt of 0
+// starts it now.
+type EventFromTemplateRequest struct {
+	StartAt uint64 `json:"start_at"`
+}
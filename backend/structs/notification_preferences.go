@@ -0,0 +1,88 @@
+package structs
+
+const (
+	NotificationCategoryAccount           = "account"
+	NotificationCategoryTransactions      = "transactions"
+	NotificationCategoryWorkflowProposals = "workflow_proposals"
+	NotificationCategoryWorkflowSteps     = "workflow_steps"
+	NotificationCategoryWorkflowAlerts    = "workflow_alerts"
+	NotificationCategoryCredentials       = "credentials"
+	NotificationCategoryW9                = "w9"
+	NotificationCategoryAffiliate         = "affiliate"
+	NotificationCategoryAdmin             = "admin"
+
+	NotificationChannelEmail = "email"
+	NotificationChannelPush  = "push"
+)
+
+// NotificationCategoryInfo describes a category in the preferences center.
+// Required categories always deliver on every channel they offer.
+type NotificationCategoryInfo struct {
+	Category string   `json:"category"`
+	Label    string   `json:"label"`
+	Channels []string `json:"channels"`
+	Required bool     `json:"required"`
+}
+
+var NotificationCategories = []NotificationCategoryInfo{
+	{Category: NotificationCategoryAccount, Label: "Account and email verification", Channels: []string{NotificationChannelEmail}, Required: true},
+	{Category: NotificationCategoryTransactions, Label: "Incoming transactions", Channels: []string{NotificationChannelEmail, NotificationChannelPush}},
+	{Category: NotificationCategoryWorkflowProposals, Label: "Workflow proposal outcomes", Channels: []string{NotificationChannelEmail}},
+	{Category: NotificationCategoryWorkflowSteps, Label: "Workflow steps ready for you", Channels: []string{NotificationChannelEmail}},
+	{Category: NotificationCategoryWorkflowAlerts, Label: "Workflow dropdown alerts", Channels: []string{NotificationChannelEmail}},
+	{Category: NotificationCategoryCredentials, Label: "Credential requests", Channels: []string{NotificationChannelEmail}},
+	{Category: NotificationCategoryW9, Label: "W9 compliance", Channels: []string{NotificationChannelEmail}, Required: true},
+	{Category: NotificationCategoryAffiliate, Label: "Affiliate events", Channels: []string{NotificationChannelEmail}},
+	{Category: NotificationCategoryAdmin, Label: "Admin alerts", Channels: []string{NotificationChannelEmail}},
+}
+
+func NotificationCategoryByName(category string) (NotificationCategoryInfo, bool) {
+	for _, info := range NotificationCategories {
+		if info.Category == category {
+			return info, true
+		}
+	}
+	return NotificationCategoryInfo{}, false
+}
+
+// NotificationPreference is a user's choice of channels for one category;
+// both false means none.
+type NotificationPreference struct {
+	Category string `json:"category"`
+	Email    bool   `json:"email"`
+	Push     bool   `json:"push"`
+}
+
+// NotificationSettings is a user's notification preferences center. Quiet
+// hours are "HH:MM" in Timezone and hold back pushes; leaving either end empty
+// turns them off.
+type NotificationSettings struct {
+	Locale          string                     `json:"locale"`
+	Timezone        string                     `json:"timezone"`
+	QuietHoursStart string                     `json:"quiet_hours_start"`
+	QuietHoursEnd   string                     `json:"quiet_hours_end"`
+	Preferences     []NotificationPreference   `json:"preferences"`
+	Categories      []NotificationCategoryInfo `json:"categories,omitempty"`
+}
+
+// HeldPushNotification is a push that arrived during the recipient's quiet
+// hours, kept until DeliverAt when the window ends. Attempts counts failed
+// sends since then.
+type HeldPushNotification struct {
+	Id           string
+	Category     string
+	Title        string
+	Body         string
+	Data         map[string]string
+	DeliverAt    int64
+	Attempts     int
+	Subscription *MobilePushSubscription
+}
+
+type NotificationSettingsUpdate struct {
+	Locale          *string                  `json:"locale,omitempty"`
+	Timezone        *string                  `json:"timezone,omitempty"`
+	QuietHoursStart *string                  `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   *string                  `json:"quiet_hours_end,omitempty"`
+	Preferences     []NotificationPreference `json:"preferences,omitempty"`
+}
@@ -13,13 +13,16 @@ const (
 // known Berachain balance, decimal-adjusted for the Celo migration, recorded so
 // the holder can claim it after the migration.
 type RecoveryBalance struct {
-	Address     string     `json:"address"`
-	ChainID     int64      `json:"chain_id"`
-	Amount      string     `json:"amount"` // exact base units (NUMERIC), as a string
-	ClaimStatus string     `json:"claim_status"`
-	ClaimedBy   string     `json:"claimed_by,omitempty"`
-	ClaimTxHash string     `json:"claim_tx_hash,omitempty"`
-	ClaimedAt   *time.Time `json:"claimed_at,omitempty"`
+	Address     string `json:"address"`
+	ChainID     int64  `json:"chain_id"`
+	Amount      string `json:"amount"` // exact base units (NUMERIC), as a string
+	ClaimStatus string `json:"claim_status"`
+	ClaimedBy   string `json:"claimed_by,omitempty"`
+	ClaimTxHash string `json:"claim_tx_hash,omitempty"`
+	// ClaimTxChainID is the chain the claim payout was sent on, which can
+	// differ from ChainID (where the balance was held).
+	ClaimTxChainID int64      `json:"claim_tx_chain_id,omitempty"`
+	ClaimedAt      *time.Time `json:"claimed_at,omitempty"`
 }
 
 // RecoveryClaimRequest is the authenticated claim payload: the full Citizen
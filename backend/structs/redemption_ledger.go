@@ -0,0 +1,81 @@
+package structs
+
+// Redemption ledger actions. A code redemption writes "reserved" when the
+// code is claimed, then "sent" or "failed" once the payout is attempted, and
+// "undone" if the claim is rolled back.
+const (
+	RedemptionLedgerReserved = "reserved"
+	RedemptionLedgerSent     = "sent"
+	RedemptionLedgerFailed   = "failed"
+	RedemptionLedgerUndone   = "undone"
+)
+
+// Reconciliation mismatches between the ledger and on-chain transfers.
+const (
+	RedemptionMismatchSentNotRecorded = "sent_not_recorded"
+	RedemptionMismatchRecordedNotSent = "recorded_not_sent"
+	RedemptionMismatchTransferDiffers = "transfer_differs"
+)
+
+// How an attempt was paired with an on-chain transfer: by a recorded tx hash
+// (or one of its gas bump replacements), or by recipient and amount when the
+// ledger holds no hash that landed.
+const (
+	RedemptionMatchedByTxHash    = "tx_hash"
+	RedemptionMatchedByRecipient = "recipient"
+)
+
+type RedemptionLedgerEntry struct {
+	Id        int64  `json:"id"`
+	Code      string `json:"code"`
+	Event     string `json:"event"`
+	Address   string `json:"address"`
+	ChainId   int64  `json:"chain_id"`
+	Amount    uint64 `json:"amount"`
+	Action    string `json:"action"`
+	TxHash    string `json:"tx_hash,omitempty"`
+	Error     string `json:"error,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// RedemptionReconciliationRow is one redemption attempt (a reservation and
+// whatever followed it) checked against Ponder.
+type RedemptionReconciliationRow struct {
+	Code        string   `json:"code"`
+	Address     string   `json:"address"`
+	ChainId     int64    `json:"chain_id"`
+	Amount      uint64   `json:"amount"`
+	Status      string   `json:"status"`
+	TxHashes    []string `json:"tx_hashes"`
+	OnChainHash string   `json:"on_chain_hash,omitempty"`
+	// OnChainTo and OnChainAmount (in base units) are the matched transfer's
+	// recipient and amount.
+	OnChainTo     string `json:"on_chain_to,omitempty"`
+	OnChainAmount string `json:"on_chain_amount,omitempty"`
+	MatchedBy     string `json:"matched_by,omitempty"`
+	ReservedAt    int64  `json:"reserved_at"`
+	Mismatch      string `json:"mismatch,omitempty"`
+	// Skipped says why the attempt could not be checked, such as a payout on
+	// a chain Ponder does not index.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// RedemptionUnrecordedTransfer is a faucet transfer of the event's amount,
+// inside its redemption window, that no ledger entry accounts for.
+type RedemptionUnrecordedTransfer struct {
+	Hash      string `json:"hash"`
+	To        string `json:"to"`
+	Amount    string `json:"amount"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+type RedemptionReconciliation struct {
+	Event      string                         `json:"event"`
+	CheckedAt  int64                          `json:"checked_at"`
+	Attempts   int                            `json:"attempts"`
+	Mismatches int                            `json:"mismatches"`
+	Skipped    int                            `json:"skipped"`
+	Rows       []*RedemptionReconciliationRow `json:"rows"`
+	// UnrecordedTransfers are counted in Mismatches as sent_not_recorded.
+	UnrecordedTransfers []*RedemptionUnrecordedTransfer `json:"unrecorded_transfers"`
+}
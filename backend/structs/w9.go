@@ -74,4 +74,5 @@ type W9TransactionRequest struct {
 	Hash        string `json:"hash"`
 	ChainID     int64  `json:"chain_id"`
 	Timestamp   int64  `json:"timestamp"`
+	LogIndex    *int   `json:"log_index,omitempty"`
 }
@@ -349,11 +349,11 @@ func GroupHandlers(t *testing.T) {
 	testRouter.Use(middleware.AuthMiddleware)
 	testRouter.Use(Spoofer.Middleware)
 
-	router.AddUserRoutes(testRouter, appService)
+	router.AddUserRoutes(testRouter, appService, nil)
 	router.AddWalletRoutes(testRouter, appService)
-	router.AddLocationRoutes(testRouter, appService)
+	router.AddLocationRoutes(testRouter, appService, nil)
 	router.AddContactRoutes(testRouter, appService)
-	router.AddW9Routes(testRouter, appService)
+	router.AddW9Routes(testRouter, appService, nil)
 
 	TestServer = httptest.NewServer(testRouter)
 	defer TestServer.Close()
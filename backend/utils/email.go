@@ -3,22 +3,21 @@ package utils
 import (
 	"context"
 	"fmt"
-	"html"
 	"net/http"
 	"os"
 	"strings"
 	"time"
-
-	"github.com/mailgun/mailgun-go/v4"
 )
 
+// EmailSender delivers straight to a sink. Notifications go through the
+// outbox instead so failed sends are retried.
 type EmailSender struct {
-	mg mailgun.Mailgun
+	sink EmailSink
 }
 
 type EmailAttachment struct {
-	Filename string
-	Data     []byte
+	Filename string `json:"filename"`
+	Data     []byte `json:"data"`
 }
 
 func NotificationFromEmail() string {
@@ -29,42 +28,16 @@ func NotificationFromEmail() string {
 }
 
 func NewEmailSender() *EmailSender {
-	if NotificationTestModeEnabled() {
-		return &EmailSender{}
-	}
-
-	domain := os.Getenv("MAILGUN_DOMAIN")
-	apiKey := os.Getenv("MAILGUN_API_KEY")
-	if domain == "" || apiKey == "" {
+	sink := NewEmailSink()
+	if sink == nil {
 		fmt.Println("MAILGUN_DOMAIN or MAILGUN_API_KEY environment variable is not set")
 		return nil
 	}
-	mg := mailgun.NewMailgun(domain, apiKey)
-	return &EmailSender{mg: mg}
+	return &EmailSender{sink: sink}
 }
 
 func (es *EmailSender) SendEmail(toEmail, toName, subject, htmlContent string, fromEmail, fromName string) error {
-	if NotificationTestModeEnabled() {
-		_, err := WriteTestEmailNotification(toEmail, toName, subject, htmlContent, fromEmail, fromName, nil)
-		return err
-	}
-	if es == nil || es.mg == nil {
-		return fmt.Errorf("email sender is not configured")
-	}
-
-	m := mailgun.NewMessage(
-		fmt.Sprintf("%s <%s>", fromName, fromEmail),
-		subject,
-		"",
-		toEmail,
-	)
-	m.SetHTML(htmlContent)
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancel()
-
-	_, _, err := es.mg.Send(ctx, m)
-	return err
+	return es.SendEmailWithAttachments(toEmail, toName, subject, htmlContent, fromEmail, fromName, nil)
 }
 
 func (es *EmailSender) SendEmailWithAttachments(
@@ -76,109 +49,29 @@ func (es *EmailSender) SendEmailWithAttachments(
 	fromName string,
 	attachments []EmailAttachment,
 ) error {
-	if NotificationTestModeEnabled() {
-		_, err := WriteTestEmailNotification(toEmail, toName, subject, htmlContent, fromEmail, fromName, attachments)
-		return err
-	}
-	if es == nil || es.mg == nil {
-		return fmt.Errorf("email sender is not configured")
-	}
+	return es.Send(&EmailMessage{
+		ToEmail:     toEmail,
+		ToName:      toName,
+		Subject:     subject,
+		HTML:        htmlContent,
+		FromEmail:   fromEmail,
+		FromName:    fromName,
+		Attachments: attachments,
+	})
+}
 
-	m := mailgun.NewMessage(
-		fmt.Sprintf("%s <%s>", fromName, fromEmail),
-		subject,
-		"",
-		toEmail,
-	)
-	m.SetHTML(htmlContent)
-	for _, attachment := range attachments {
-		filename := strings.TrimSpace(attachment.Filename)
-		if filename == "" || len(attachment.Data) == 0 {
-			continue
-		}
-		m.AddBufferAttachment(filename, attachment.Data)
+func (es *EmailSender) Send(msg *EmailMessage) error {
+	if es == nil || es.sink == nil {
+		return fmt.Errorf("email sender is not configured")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 
-	_, _, err := es.mg.Send(ctx, m)
+	_, err := es.sink.Deliver(ctx, msg)
 	return err
 }
 
-func BuildStyledEmail(title, subtitle, contentHTML string) string {
-	return BuildStyledEmailWithFooter(title, subtitle, contentHTML, "SFLuv · Notifications")
-}
-
-func BuildStyledEmailWithFooter(title, subtitle, contentHTML string, footerText string) string {
-	sectionsHTML := fmt.Sprintf(`<tr>
-              <td style="padding:24px 28px 24px;">
-                %s
-              </td>
-            </tr>`, contentHTML)
-	return BuildStyledEmailWithSections(title, subtitle, sectionsHTML, footerText)
-}
-
-func BuildStyledEmailWithSections(title, subtitle, sectionsHTML string, footerText string) string {
-	template := `<!doctype html>
-<html lang="en">
-  <head>
-    <meta charset="utf-8" />
-    <meta name="viewport" content="width=device-width, initial-scale=1" />
-    <title>{{TITLE}}</title>
-  </head>
-  <body style="margin:0; padding:0; background-color:#f6f7fb; font-family: 'Helvetica Neue', Arial, sans-serif; color:#111827;">
-    <table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="background-color:#f6f7fb; padding:24px 0;">
-      <tr>
-        <td align="center">
-          <table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="max-width:560px; background-color:#ffffff; border-radius:16px; overflow:hidden; box-shadow:0 10px 30px rgba(15, 23, 42, 0.08);">
-            <tr>
-              <td style="background: linear-gradient(120deg, #ff8a8a 0%, #eb6c6c 55%, #d55c5c 100%); padding:20px 28px; color:#ffffff;">
-                <table role="presentation" width="100%" cellpadding="0" cellspacing="0">
-                  <tr>
-                    <td style="width:48px; padding-right:12px;">
-                      <img
-                        src="https://app.sfluv.org/icon.png"
-                        alt="SFLuv"
-                        width="40"
-                        height="40"
-                        style="display:block; border-radius:10px; background:#ffffff; padding:4px;"
-                      />
-                    </td>
-                    <td>
-                      <h1 style="margin:0 0 6px; font-size:20px; letter-spacing:0.4px;">{{TITLE}}</h1>
-                      <p style="margin:0; font-size:14px; opacity:0.9;">{{SUBTITLE}}</p>
-                    </td>
-                  </tr>
-                </table>
-              </td>
-            </tr>
-            {{SECTIONS}}
-          </table>
-          <p style="margin:16px 0 0; font-size:11px; color:#9ca3af;">{{FOOTER}}</p>
-        </td>
-      </tr>
-    </table>
-  </body>
-</html>`
-
-	escapedTitle := html.EscapeString(title)
-	escapedSubtitle := html.EscapeString(subtitle)
-	escapedFooter := html.EscapeString(footerText)
-
-	replacer := strings.NewReplacer(
-		"{{TITLE}}", escapedTitle,
-		"{{SUBTITLE}}", escapedSubtitle,
-		"{{SECTIONS}}", sectionsHTML,
-		"{{FOOTER}}", escapedFooter,
-	)
-	return replacer.Replace(template)
-}
-
-func EscapeEmailHTML(value string) string {
-	return html.EscapeString(value)
-}
-
 func (es *EmailSender) AddAuthorizedRecipient(toEmail string) error {
 	req, _ := http.NewRequest("POST",
 		"https://api.mailgun.net/v5/sandbox/auth_recipients?email="+toEmail, nil)
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mailgun/mailgun-go/v4"
+)
+
+const (
+	EmailSinkMailgun = "mailgun"
+	EmailSinkFile    = "file"
+)
+
+type EmailMessage struct {
+	ToEmail     string
+	ToName      string
+	Subject     string
+	HTML        string
+	Text        string
+	FromEmail   string
+	FromName    string
+	Attachments []EmailAttachment
+}
+
+// EmailSink is where outgoing email ends up. Deliver returns the sink's id
+// for the message so later events, like bounces, can be matched to it.
+type EmailSink interface {
+	Name() string
+	Deliver(ctx context.Context, msg *EmailMessage) (string, error)
+}
+
+// NewEmailSink picks the file sink in notification test mode, otherwise
+// Mailgun. It returns nil when Mailgun is not configured.
+func NewEmailSink() EmailSink {
+	if NotificationTestModeEnabled() {
+		return FileEmailSink{}
+	}
+
+	domain := os.Getenv("MAILGUN_DOMAIN")
+	apiKey := os.Getenv("MAILGUN_API_KEY")
+	if domain == "" || apiKey == "" {
+		return nil
+	}
+	return &MailgunEmailSink{mg: mailgun.NewMailgun(domain, apiKey)}
+}
+
+type MailgunEmailSink struct {
+	mg mailgun.Mailgun
+}
+
+func (s *MailgunEmailSink) Name() string {
+	return EmailSinkMailgun
+}
+
+func (s *MailgunEmailSink) Deliver(ctx context.Context, msg *EmailMessage) (string, error) {
+	if s == nil || s.mg == nil {
+		return "", fmt.Errorf("email sender is not configured")
+	}
+
+	m := mailgun.NewMessage(
+		fmt.Sprintf("%s <%s>", msg.FromName, msg.FromEmail),
+		msg.Subject,
+		msg.Text,
+		msg.ToEmail,
+	)
+	m.SetHTML(msg.HTML)
+	for _, attachment := range msg.Attachments {
+		filename := strings.TrimSpace(attachment.Filename)
+		if filename == "" || len(attachment.Data) == 0 {
+			continue
+		}
+		m.AddBufferAttachment(filename, attachment.Data)
+	}
+
+	_, id, err := s.mg.Send(ctx, m)
+	if err != nil {
+		return "", err
+	}
+	return NormalizeEmailMessageId(id), nil
+}
+
+// FileEmailSink writes each email to the notification test output directory.
+type FileEmailSink struct{}
+
+func (FileEmailSink) Name() string {
+	return EmailSinkFile
+}
+
+func (FileEmailSink) Deliver(ctx context.Context, msg *EmailMessage) (string, error) {
+	return WriteTestEmailNotification(msg.ToEmail, msg.ToName, msg.Subject, msg.HTML, msg.Text, msg.FromEmail, msg.FromName, msg.Attachments)
+}
+
+// NormalizeEmailMessageId strips the angle brackets Mailgun returns on send
+// but leaves off in webhook events.
+func NormalizeEmailMessageId(id string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(id), "<"), ">")
+}
@@ -0,0 +1,14 @@
+package utils
+
+import "net/http"
+
+// GetAPIKeyId returns the id of the API key the request was authenticated
+// with, or nil when it came from a signed-in user.
+func GetAPIKeyId(r *http.Request) *string {
+	apiKeyId, ok := r.Context().Value("apiKeyId").(string)
+	if !ok {
+		return nil
+	}
+
+	return &apiKeyId
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SFLuv/app/backend/logger"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logger.RequestIDHeader, "from-proxy-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if seen != "from-proxy-1" || rec.Header().Get(logger.RequestIDHeader) != "from-proxy-1" {
+		t.Fatalf("expected incoming id to be reused, got ctx %q header %q", seen, rec.Header().Get(logger.RequestIDHeader))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logger.RequestIDHeader, "bad id\n")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if seen == "" || seen == "bad id\n" || rec.Header().Get(logger.RequestIDHeader) != seen {
+		t.Fatalf("expected a fresh id for a malformed header, got ctx %q header %q", seen, rec.Header().Get(logger.RequestIDHeader))
+	}
+}
@@ -0,0 +1,154 @@
+// Package pdf writes simple printable PDF documents: filled rectangles and
+// single-line Helvetica text on fixed-size pages. It exists for export sheets
+// and does not try to be a general-purpose PDF library.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// US Letter in points.
+const (
+	LetterWidth  = 612.0
+	LetterHeight = 792.0
+)
+
+type Document struct {
+	width  float64
+	height float64
+	pages  []*Page
+}
+
+// Page collects drawing operators. Coordinates are in points from the
+// top-left corner; they are flipped to PDF's bottom-left origin on output.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+func New(width float64, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+func (d *Document) AddPage() *Page {
+	page := &Page{doc: d}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Rect fills a rectangle with a grey level from 0 (black) to 1 (white).
+func (p *Page) Rect(x float64, y float64, w float64, h float64, grey float64) {
+	fmt.Fprintf(&p.content, "%s g %s %s %s %s re f\n",
+		num(grey), num(x), num(p.doc.height-y-h), num(w), num(h))
+}
+
+// Text draws one line of text with its baseline at y.
+func (p *Page) Text(x float64, y float64, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "0 g BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, num(size), num(x), num(p.doc.height-y), escape(text))
+}
+
+// TextCentered draws text centered on cx.
+func (p *Page) TextCentered(cx float64, y float64, size float64, bold bool, text string) {
+	p.Text(cx-TextWidth(text, size, bold)/2, y, size, bold, text)
+}
+
+// TextWidth estimates the rendered width of text. Helvetica's average glyph
+// is a little over half the font size, which is close enough for centering
+// short labels.
+func TextWidth(text string, size float64, bold bool) float64 {
+	factor := 0.52
+	if bold {
+		factor = 0.56
+	}
+	return float64(len(text)) * size * factor
+}
+
+// Truncate shortens text with an ellipsis so it fits within width.
+func Truncate(text string, width float64, size float64, bold bool) string {
+	if TextWidth(text, size, bold) <= width {
+		return text
+	}
+	for len(text) > 0 && TextWidth(text+"...", size, bold) > width {
+		text = text[:len(text)-1]
+	}
+	return strings.TrimSpace(text) + "..."
+}
+
+// WriteTo serializes the document.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Fixed objects: 1 catalog, 2 page tree, 3-4 fonts. Each page then takes
+	// two objects: the page and its content stream.
+	pageIds := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageIds[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageIds, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(d.width), num(d.height), 6+i*2))
+		stream := page.content.Bytes()
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.WriteTo(w)
+}
+
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		return "0"
+	}
+	return s
+}
+
+// escape makes text safe inside a PDF string literal. Characters outside
+// printable ASCII are replaced, since only the standard fonts are embedded.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteToProducesValidXref(t *testing.T) {
+	doc := New(LetterWidth, LetterHeight)
+	first := doc.AddPage()
+	first.Rect(10, 10, 20, 20, 0)
+	first.Text(36, 50, 12, true, "Event (Spring) \\ 100 SFLUV")
+	doc.AddPage().TextCentered(306, 400, 10, false, "café")
+
+	var out bytes.Buffer
+	if _, err := doc.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	body := out.String()
+
+	if !strings.HasPrefix(body, "%PDF-1.4") || !strings.HasSuffix(body, "%%EOF\n") {
+		t.Fatalf("missing PDF header or trailer")
+	}
+	if !strings.Contains(body, "/Count 2") {
+		t.Fatalf("page tree should count 2 pages")
+	}
+	if !strings.Contains(body, `(Event \(Spring\) \\ 100 SFLUV)`) {
+		t.Fatalf("text was not escaped")
+	}
+	if !strings.Contains(body, "(caf?)") {
+		t.Fatalf("non-ascii text was not replaced")
+	}
+
+	// Every xref entry must point at the start of its object.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(body)
+	if startxref == nil {
+		t.Fatalf("missing startxref")
+	}
+	xrefAt, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(body[xrefAt:], "xref\n") {
+		t.Fatalf("startxref does not point at the xref table")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(body[xrefAt:], -1)
+	if len(entries) != 8 {
+		t.Fatalf("xref has %d objects; want 8", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := strconv.Itoa(i+1) + " 0 obj"; !strings.HasPrefix(body[offset:], want) {
+			t.Fatalf("xref entry %d points at %q", i+1, body[offset:offset+10])
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("short", 200, 10, false); got != "short" {
+		t.Fatalf("Truncate() = %q", got)
+	}
+	got := Truncate(strings.Repeat("long title ", 10), 100, 10, false)
+	if !strings.HasSuffix(got, "...") || TextWidth(got, 10, false) > 100 {
+		t.Fatalf("Truncate() = %q does not fit", got)
+	}
+}
@@ -0,0 +1,475 @@
+// Package qrcode encodes short byte strings (redeem links and the like) as QR
+// codes. It only implements byte mode at error correction level M for
+// versions 1-10, which covers payloads up to 213 bytes.
+package qrcode
+
+import "fmt"
+
+// Code is an encoded QR symbol. Modules[row][col] is true for dark modules.
+// The quiet zone is not included.
+type Code struct {
+	Version int
+	Size    int
+	Modules [][]bool
+}
+
+type versionInfo struct {
+	eccPerBlock int
+	group1      int
+	group1Data  int
+	group2      int
+	group2Data  int
+	alignment   []int
+}
+
+// versions holds the level M block structure and alignment pattern centers,
+// indexed by version - 1.
+var versions = []versionInfo{
+	{10, 1, 16, 0, 0, nil},
+	{16, 1, 28, 0, 0, []int{6, 18}},
+	{26, 1, 44, 0, 0, []int{6, 22}},
+	{18, 2, 32, 0, 0, []int{6, 26}},
+	{24, 2, 43, 0, 0, []int{6, 30}},
+	{16, 4, 27, 0, 0, []int{6, 34}},
+	{18, 4, 31, 0, 0, []int{6, 22, 38}},
+	{22, 2, 38, 2, 39, []int{6, 24, 42}},
+	{22, 3, 36, 2, 37, []int{6, 26, 46}},
+	{26, 4, 43, 1, 44, []int{6, 28, 50}},
+}
+
+func (v versionInfo) dataCodewords() int {
+	return v.group1*v.group1Data + v.group2*v.group2Data
+}
+
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// Encode returns the smallest QR code that holds data.
+func Encode(data []byte) (*Code, error) {
+	for i, info := range versions {
+		version := i + 1
+		capacityBits := info.dataCodewords() * 8
+		if 4+countBits(version)+len(data)*8 <= capacityBits {
+			return encode(data, version, info), nil
+		}
+	}
+	return nil, fmt.Errorf("qr payload of %d bytes is too long", len(data))
+}
+
+func encode(data []byte, version int, info versionInfo) *Code {
+	codewords := interleave(dataCodewords(data, version, info), info)
+
+	size := 17 + 4*version
+	m := &matrix{size: size, dark: newGrid(size), function: newGrid(size)}
+	m.drawFunctionPatterns(version, info)
+	m.drawCodewords(codewords)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormatBits(mask)
+		penalty := m.penalty()
+		if bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		m.applyMask(mask)
+	}
+	m.applyMask(bestMask)
+	m.drawFormatBits(bestMask)
+
+	return &Code{Version: version, Size: size, Modules: m.dark}
+}
+
+// dataCodewords packs data in byte mode and pads it to the version's data
+// capacity.
+func dataCodewords(data []byte, version int, info versionInfo) []byte {
+	var bits bitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), countBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := info.dataCodewords() * 8
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	if rem := len(bits) % 8; rem != 0 {
+		bits.append(0, 8-rem)
+	}
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// interleave splits the data into blocks, appends each block's Reed-Solomon
+// codewords and interleaves them as the symbol expects.
+func interleave(data []byte, info versionInfo) []byte {
+	divisor := rsDivisor(info.eccPerBlock)
+	var dataBlocks, eccBlocks [][]byte
+	offset := 0
+	for i := 0; i < info.group1+info.group2; i++ {
+		length := info.group1Data
+		if i >= info.group1 {
+			length = info.group2Data
+		}
+		block := data[offset : offset+length]
+		offset += length
+		dataBlocks = append(dataBlocks, block)
+		eccBlocks = append(eccBlocks, rsRemainder(block, divisor))
+	}
+
+	result := make([]byte, 0, len(data)+len(eccBlocks)*info.eccPerBlock)
+	longest := info.group1Data
+	if info.group2Data > longest {
+		longest = info.group2Data
+	}
+	for i := 0; i < longest; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.eccPerBlock; i++ {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value int, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 == 1)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return out
+}
+
+// gfMul multiplies in GF(2^8) modulo the QR polynomial x^8+x^4+x^3+x^2+1.
+func gfMul(x byte, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsDivisor returns the generator polynomial with roots a^0 .. a^(degree-1),
+// highest coefficient first and the leading 1 omitted.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMul(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+type matrix struct {
+	size     int
+	dark     [][]bool
+	function [][]bool
+}
+
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+func (m *matrix) setFunction(row int, col int, dark bool) {
+	m.dark[row][col] = dark
+	m.function[row][col] = true
+}
+
+func (m *matrix) drawFunctionPatterns(version int, info versionInfo) {
+	for i := 0; i < m.size; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+
+	m.drawFinder(3, 3)
+	m.drawFinder(3, m.size-4)
+	m.drawFinder(m.size-4, 3)
+
+	last := len(info.alignment) - 1
+	for i, row := range info.alignment {
+		for j, col := range info.alignment {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			m.drawAlignment(row, col)
+		}
+	}
+
+	// Reserve the format areas; drawFormatBits fills them in per mask.
+	m.drawFormatBits(0)
+	m.drawVersionBits(version)
+}
+
+func (m *matrix) drawFinder(centerRow int, centerCol int) {
+	for dr := -4; dr <= 4; dr++ {
+		for dc := -4; dc <= 4; dc++ {
+			row, col := centerRow+dr, centerCol+dc
+			if row < 0 || row >= m.size || col < 0 || col >= m.size {
+				continue
+			}
+			dist := max(abs(dr), abs(dc))
+			m.setFunction(row, col, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (m *matrix) drawAlignment(centerRow int, centerCol int) {
+	for dr := -2; dr <= 2; dr++ {
+		for dc := -2; dc <= 2; dc++ {
+			m.setFunction(centerRow+dr, centerCol+dc, max(abs(dr), abs(dc)) != 1)
+		}
+	}
+}
+
+func formatBits(mask int) int {
+	// Level M is encoded as 00, so the data bits are just the mask.
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (m *matrix) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return (bits>>uint(i))&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		m.setFunction(i, 8, bit(i))
+	}
+	m.setFunction(7, 8, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(8, 7, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(8, 14-i, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.setFunction(8, m.size-1-i, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(m.size-15+i, 8, bit(i))
+	}
+	m.setFunction(m.size-8, 8, true)
+}
+
+func (m *matrix) drawVersionBits(version int) {
+	if version < 7 {
+		return
+	}
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 == 1
+		a := m.size - 11 + i%3
+		b := i / 3
+		m.setFunction(b, a, dark)
+		m.setFunction(a, b, dark)
+	}
+}
+
+// drawCodewords fills the non-function modules in the zigzag order, two
+// columns at a time from the bottom-right corner.
+func (m *matrix) drawCodewords(codewords []byte) {
+	i := 0
+	total := len(codewords) * 8
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < m.size; vert++ {
+			for j := 0; j < 2; j++ {
+				col := right - j
+				upward := (right+1)&2 == 0
+				row := vert
+				if upward {
+					row = m.size - 1 - vert
+				}
+				if m.function[row][col] || i >= total {
+					continue
+				}
+				m.dark[row][col] = (codewords[i>>3]>>uint(7-i&7))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func maskBit(mask int, row int, col int) bool {
+	switch mask {
+	case 0:
+		return (row+col)%2 == 0
+	case 1:
+		return row%2 == 0
+	case 2:
+		return col%3 == 0
+	case 3:
+		return (row+col)%3 == 0
+	case 4:
+		return (row/2+col/3)%2 == 0
+	case 5:
+		return row*col%2+row*col%3 == 0
+	case 6:
+		return (row*col%2+row*col%3)%2 == 0
+	default:
+		return ((row+col)%2+row*col%3)%2 == 0
+	}
+}
+
+// applyMask XORs the mask over the data modules; applying it twice undoes it.
+func (m *matrix) applyMask(mask int) {
+	for row := 0; row < m.size; row++ {
+		for col := 0; col < m.size; col++ {
+			if !m.function[row][col] && maskBit(mask, row, col) {
+				m.dark[row][col] = !m.dark[row][col]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the four standard mask evaluation rules.
+func (m *matrix) penalty() int {
+	score := 0
+	line := make([]bool, m.size)
+	for horizontal := 0; horizontal < 2; horizontal++ {
+		for i := 0; i < m.size; i++ {
+			for j := 0; j < m.size; j++ {
+				if horizontal == 0 {
+					line[j] = m.dark[i][j]
+				} else {
+					line[j] = m.dark[j][i]
+				}
+			}
+			score += runPenalty(line) + finderPenalty(line)
+		}
+	}
+
+	darkCount := 0
+	for row := 0; row < m.size; row++ {
+		for col := 0; col < m.size; col++ {
+			if m.dark[row][col] {
+				darkCount++
+			}
+			if row+1 < m.size && col+1 < m.size {
+				c := m.dark[row][col]
+				if c == m.dark[row+1][col] && c == m.dark[row][col+1] && c == m.dark[row+1][col+1] {
+					score += 3
+				}
+			}
+		}
+	}
+
+	total := m.size * m.size
+	deviation := abs(darkCount*20-total*10) / total
+	score += deviation * 10
+	return score
+}
+
+func runPenalty(line []bool) int {
+	score := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += 3 + run - 5
+		}
+		run = 1
+	}
+	return score
+}
+
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+func finderPenalty(line []bool) int {
+	score := 0
+	for i := 0; i+len(finderLike) <= len(line); i++ {
+		match := true
+		for j, want := range finderLike {
+			if line[i+j] != want {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if lightRun(line, i-4, i) || lightRun(line, i+len(finderLike), i+len(finderLike)+4) {
+			score += 40
+		}
+	}
+	return score
+}
+
+// lightRun reports whether line[from:to] is all light, treating modules
+// outside the symbol as light quiet zone.
+func lightRun(line []bool, from int, to int) bool {
+	for i := from; i < to; i++ {
+		if i >= 0 && i < len(line) && line[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,182 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// readBack reverses Encode: it reads the format bits, unmasks the symbol,
+// de-interleaves the codewords and returns the data blocks after checking
+// that every block's Reed-Solomon syndromes are zero.
+func readBack(t *testing.T, code *Code) []byte {
+	t.Helper()
+	info := versions[code.Version-1]
+
+	m := &matrix{size: code.Size, dark: newGrid(code.Size), function: newGrid(code.Size)}
+	m.drawFunctionPatterns(code.Version, info)
+
+	format := 0
+	for i := 0; i <= 5; i++ {
+		if code.Modules[i][8] {
+			format |= 1 << uint(i)
+		}
+	}
+	mask := -1
+	for candidate := 0; candidate < 8; candidate++ {
+		if formatBits(candidate)&0x3F == format {
+			mask = candidate
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format bits %06b do not match any mask", format)
+	}
+
+	for row := 0; row < code.Size; row++ {
+		for col := 0; col < code.Size; col++ {
+			if !m.function[row][col] {
+				m.dark[row][col] = code.Modules[row][col]
+			}
+		}
+	}
+	m.applyMask(mask)
+
+	var bits bitBuffer
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < m.size; vert++ {
+			for j := 0; j < 2; j++ {
+				col := right - j
+				row := vert
+				if (right+1)&2 == 0 {
+					row = m.size - 1 - vert
+				}
+				if !m.function[row][col] {
+					bits = append(bits, m.dark[row][col])
+				}
+			}
+		}
+	}
+	raw := bits[:len(bits)-len(bits)%8].bytes()
+
+	blockCount := info.group1 + info.group2
+	blocks := make([][]byte, blockCount)
+	offset := 0
+	longest := max(info.group1Data, info.group2Data)
+	for i := 0; i < longest; i++ {
+		for b := 0; b < blockCount; b++ {
+			length := info.group1Data
+			if b >= info.group1 {
+				length = info.group2Data
+			}
+			if i < length {
+				blocks[b] = append(blocks[b], raw[offset])
+				offset++
+			}
+		}
+	}
+	for i := 0; i < info.eccPerBlock; i++ {
+		for b := 0; b < blockCount; b++ {
+			blocks[b] = append(blocks[b], raw[offset])
+			offset++
+		}
+	}
+
+	var data []byte
+	for b, block := range blocks {
+		root := byte(1)
+		for i := 0; i < info.eccPerBlock; i++ {
+			syndrome := byte(0)
+			for _, c := range block {
+				syndrome = gfMul(syndrome, root) ^ c
+			}
+			if syndrome != 0 {
+				t.Fatalf("block %d syndrome %d is %d", b, i, syndrome)
+			}
+			root = gfMul(root, 0x02)
+		}
+		data = append(data, block[:len(block)-info.eccPerBlock]...)
+	}
+	return data
+}
+
+func decodePayload(t *testing.T, version int, data []byte) []byte {
+	t.Helper()
+	var bits bitBuffer
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	read := func(pos *int, n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if bits[*pos] {
+				v |= 1
+			}
+			*pos++
+		}
+		return v
+	}
+	pos := 0
+	if mode := read(&pos, 4); mode != 0x4 {
+		t.Fatalf("mode = %x; want byte mode", mode)
+	}
+	length := read(&pos, countBits(version))
+	payload := make([]byte, length)
+	for i := range payload {
+		payload[i] = byte(read(&pos, 8))
+	}
+	return payload
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	payloads := []string{
+		"a",
+		"https://app.sfluv.org?code=3f2b8c1e-5d4a-4e7b-9c0d-1a2b3c4d5e6f&page=redeem",
+		strings.Repeat("x", 150),
+		strings.Repeat("y", 213),
+	}
+	for _, payload := range payloads {
+		code, err := Encode([]byte(payload))
+		if err != nil {
+			t.Fatalf("Encode(%d bytes) error = %v", len(payload), err)
+		}
+		if code.Size != 17+4*code.Version || len(code.Modules) != code.Size {
+			t.Fatalf("bad size %d for version %d", code.Size, code.Version)
+		}
+		got := decodePayload(t, code.Version, readBack(t, code))
+		if !bytes.Equal(got, []byte(payload)) {
+			t.Fatalf("round trip = %q; want %q", got, payload)
+		}
+	}
+}
+
+func TestEncodePicksSmallestVersion(t *testing.T) {
+	code, err := Encode([]byte("https://app.sfluv.org?code=3f2b8c1e-5d4a-4e7b-9c0d-1a2b3c4d5e6f&page=redeem"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 76 bytes needs 78 data codewords; version 5-M holds 86.
+	if code.Version != 5 {
+		t.Fatalf("version = %d; want 5", code.Version)
+	}
+	if _, err := Encode(make([]byte, 214)); err == nil {
+		t.Fatalf("expected error for a payload past version 10")
+	}
+}
+
+func TestFinderPatterns(t *testing.T) {
+	code, _ := Encode([]byte("finder"))
+	for _, origin := range [][2]int{{0, 0}, {0, code.Size - 7}, {code.Size - 7, 0}} {
+		for i := 0; i < 7; i++ {
+			if !code.Modules[origin[0]][origin[1]+i] || !code.Modules[origin[0]+i][origin[1]] {
+				t.Fatalf("finder at %v is missing its dark border", origin)
+			}
+		}
+		if code.Modules[origin[0]+1][origin[1]+1] || !code.Modules[origin[0]+3][origin[1]+3] {
+			t.Fatalf("finder at %v has the wrong core", origin)
+		}
+	}
+}
@@ -30,6 +30,7 @@ func WriteTestEmailNotification(
 	toName string,
 	subject string,
 	htmlContent string,
+	textContent string,
 	fromEmail string,
 	fromName string,
 	attachments []EmailAttachment,
@@ -52,6 +53,9 @@ func WriteTestEmailNotification(
 			metadata = append(metadata, fmt.Sprintf("- %s (%d bytes)", filename, len(attachment.Data)))
 		}
 	}
+	if strings.TrimSpace(textContent) != "" {
+		metadata = append(metadata, "Text:", strings.TrimRight(textContent, "\n"))
+	}
 
 	content := []byte(testHTMLComment(metadata) + "\n" + htmlContent)
 	return writeTestNotificationFile("email", subject, ".html", content)
@@ -143,6 +143,19 @@ const Page = () => {
             setError("W9 Pending")
             return
           }
+          if (data?.error === "redemption_limit") {
+            switch (data.reason) {
+            case "cooldown":
+              setError(`Please wait ${Math.ceil((data.retry_after || 60) / 60)} min before redeeming another code.`)
+              break;
+            case "user_required":
+              setError("Sign in and redeem to a wallet on your account for this event.")
+              break;
+            default:
+              setError("You have reached the redemption limit for this event.")
+            }
+            return
+          }
         } catch {
           // ignore json parse error
         }
@@ -1507,6 +1507,7 @@ export default function ProposerPage() {
           notify_email_count: option.notify_emails?.length || 0,
           notify_email_subject: option.notify_email_subject || "",
           send_pictures_with_email: Boolean(option.send_pictures_with_email),
+          bounty_weight: option.bounty_weight,
         })),
         dropdown_requires_written_response: Object.fromEntries(
           item.dropdown_options.map((option, optionIndex) => [
@@ -1514,6 +1515,7 @@ export default function ProposerPage() {
             Boolean(option.requires_written_response),
           ]),
         ),
+        bounty_weight: item.bounty_weight || 0,
       })),
     }))
 
@@ -1547,6 +1549,7 @@ export default function ProposerPage() {
       supervisor_required: Boolean(payload.supervisor),
       supervisor_user_id: supervisorId,
       supervisor_bounty: payload.supervisor?.bounty || 0,
+      supervisor_approval_required: Boolean(payload.supervisor?.approval_required),
       supervisor_data_fields: normalizedSupervisorDataFields,
       supervisor_paid_out_at: null,
       supervisor_payout_error: null,
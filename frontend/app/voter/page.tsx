@@ -83,6 +83,7 @@ function buildEditProposalPreviewWorkflow(proposal: WorkflowEditProposal): Workf
         photo_instructions: option.photo_instructions || "",
         notify_emails: [],
         notify_email_count: option.notify_email_count ?? option.notify_emails.length,
+        bounty_weight: option.bounty_weight,
       }))
 
       return {
@@ -103,6 +104,7 @@ function buildEditProposalPreviewWorkflow(proposal: WorkflowEditProposal): Workf
         dropdown_requires_written_response: Object.fromEntries(
           dropdownOptions.map((option) => [option.value, option.requires_written_response]),
         ),
+        bounty_weight: item.bounty_weight || 0,
       }
     }),
   }))
@@ -132,6 +134,7 @@ function buildEditProposalPreviewWorkflow(proposal: WorkflowEditProposal): Workf
     supervisor_required: proposal.supervisor_required,
     supervisor_user_id: proposal.supervisor_user_id,
     supervisor_bounty: proposal.supervisor_bounty,
+    supervisor_approval_required: proposal.supervisor_approval_required,
     supervisor_data_fields: [],
     supervisor_paid_out_at: null,
     supervisor_payout_error: null,
@@ -174,6 +177,7 @@ function buildDeletionProposalFallbackWorkflow(proposal: WorkflowDeletionProposa
     supervisor_required: false,
     supervisor_user_id: null,
     supervisor_bounty: 0,
+    supervisor_approval_required: false,
     supervisor_data_fields: [],
     supervisor_paid_out_at: null,
     supervisor_payout_error: null,
@@ -25,6 +25,7 @@ export interface WorkflowDropdownOption {
   notify_email_count?: number
   notify_email_subject?: string
   send_pictures_with_email?: boolean
+  bounty_weight?: number
 }
 
 export interface WorkflowDropdownOptionCreateInput {
@@ -37,6 +38,7 @@ export interface WorkflowDropdownOptionCreateInput {
   notify_email_count?: number
   notify_email_subject?: string
   send_pictures_with_email?: boolean
+  bounty_weight?: number
 }
 
 export interface WorkflowWorkItem {
@@ -48,6 +50,7 @@ export interface WorkflowWorkItem {
   optional: boolean
   requires_photo: boolean
   camera_capture_only: boolean
+  keep_photo_location?: boolean
   photo_required_count: number
   photo_allow_any_count: boolean
   photo_aspect_ratio: WorkflowPhotoAspectRatio
@@ -55,6 +58,35 @@ export interface WorkflowWorkItem {
   requires_dropdown: boolean
   dropdown_options: WorkflowDropdownOption[]
   dropdown_requires_written_response: Record<string, boolean>
+  bounty_weight: number
+}
+
+export interface WorkflowStepGeofence {
+  latitude: number
+  longitude: number
+  radius_meters: number
+}
+
+export interface WorkflowStepCompletionWindow {
+  opens_after_minutes: number
+  closes_after_minutes: number
+}
+
+export type WorkflowStepReviewStatus =
+  | "pending_review"
+  | "flagged"
+  | "approved"
+  | "rejected"
+  | "resubmission_requested"
+
+export interface WorkflowStepReviewRequest {
+  decision: "approve" | "reject" | "request_resubmission"
+  comment?: string
+}
+
+export interface WorkflowStepReviewFlag {
+  code: "outside_geofence" | "location_unverified" | "outside_completion_window"
+  detail: string
 }
 
 export interface WorkflowStep {
@@ -64,6 +96,7 @@ export interface WorkflowStep {
   title: string
   description: string
   bounty: number
+  full_bounty?: number
   allow_step_not_possible: boolean
   role_id?: string | null
   assigned_improver_id?: string | null
@@ -75,6 +108,12 @@ export interface WorkflowStep {
   payout_last_try_at?: number | null
   retry_requested_at?: number | null
   retry_requested_by?: string | null
+  geofence?: WorkflowStepGeofence | null
+  completion_window?: WorkflowStepCompletionWindow | null
+  review_status?: WorkflowStepReviewStatus
+  reviewed_at?: number | null
+  reviewed_by?: string | null
+  review_comment?: string | null
   submission?: WorkflowStepSubmission | null
   work_items: WorkflowWorkItem[]
 }
@@ -87,6 +126,7 @@ export interface WorkflowStepSubmission {
   step_not_possible: boolean
   step_not_possible_details?: string | null
   item_responses: WorkflowStepItemResponseInput[]
+  review_flags?: WorkflowStepReviewFlag[]
   submitted_at: number
   updated_at: number
 }
@@ -137,6 +177,7 @@ export interface Workflow {
   supervisor_required: boolean
   supervisor_user_id?: string | null
   supervisor_bounty: number
+  supervisor_approval_required: boolean
   supervisor_data_fields?: WorkflowSupervisorDataField[]
   supervisor_paid_out_at?: number | null
   supervisor_payout_error?: string | null
@@ -282,12 +323,14 @@ export interface WorkflowWorkItemCreateInput {
   optional: boolean
   requires_photo: boolean
   camera_capture_only: boolean
+  keep_photo_location?: boolean
   photo_required_count: number
   photo_allow_any_count: boolean
   photo_aspect_ratio: WorkflowPhotoAspectRatio
   requires_written_response: boolean
   requires_dropdown: boolean
   dropdown_options: WorkflowDropdownOptionCreateInput[]
+  bounty_weight?: number
 }
 
 export interface WorkflowStepCreateInput {
@@ -296,12 +339,15 @@ export interface WorkflowStepCreateInput {
   bounty: number
   role_client_id: string
   allow_step_not_possible: boolean
+  geofence?: WorkflowStepGeofence | null
+  completion_window?: WorkflowStepCompletionWindow | null
   work_items: WorkflowWorkItemCreateInput[]
 }
 
 export interface WorkflowSupervisorCreateInput {
   user_id: string
   bounty: number
+  approval_required?: boolean
 }
 
 export interface WorkflowSupervisorDataField {
@@ -363,6 +409,7 @@ export interface WorkflowEditProposal {
   supervisor_required: boolean
   supervisor_user_id?: string | null
   supervisor_bounty: number
+  supervisor_approval_required: boolean
   total_bounty: number
   weekly_bounty_requirement: number
   roles?: WorkflowRoleCreateInput[]
@@ -378,6 +425,7 @@ export interface WorkflowTemplateCreateRequest {
   start_at?: string
   supervisor_user_id?: string
   supervisor_bounty?: number
+  supervisor_approval_required?: boolean
   supervisor_data_fields?: WorkflowSupervisorDataField[]
   roles: WorkflowRoleCreateInput[]
   steps: WorkflowStepCreateInput[]
@@ -395,6 +443,7 @@ export interface WorkflowTemplate {
   series_id?: string | null
   supervisor_user_id?: string | null
   supervisor_bounty?: number | null
+  supervisor_approval_required: boolean
   supervisor_data_fields?: WorkflowSupervisorDataField[]
   roles: WorkflowRoleCreateInput[]
   steps: WorkflowStepCreateInput[]
@@ -475,6 +524,9 @@ export interface WorkflowSubmissionPhoto {
   file_name: string
   content_type: string
   size_bytes: number
+  location_stripped?: boolean
+  reused_photo_id?: string
+  reuse_distance?: number
   created_at: number
 }
 
@@ -500,6 +552,7 @@ export interface SupervisorWorkflowListItem {
   supervisor_user_id?: string | null
   supervisor_title?: string | null
   supervisor_organization?: string | null
+  steps_awaiting_review: number
 }
 
 export interface SupervisorWorkflowListResponse {
@@ -523,5 +576,10 @@ export interface SupervisorWorkflowExportRequest {
 export interface WorkflowStepCompleteRequest {
   step_not_possible?: boolean
   step_not_possible_details?: string
+  location?: {
+    latitude: number
+    longitude: number
+    accuracy_meters?: number
+  }
   items: WorkflowStepItemResponseInput[]
 }
@@ -2,7 +2,7 @@ import { db } from "ponder:api";
 import schema from "ponder:schema";
 import { Context, Hono } from "hono";
 import { client, graphql } from "ponder";
-import { addHook, deleteHook, PonderHook } from "../db";
+import { addHook, deleteHook, PonderHook, setHookSecret } from "../db";
 
 const app = new Hono();
 
@@ -67,4 +67,34 @@ app.delete("/hooks", async (c) => {
   }
 })
 
+app.patch("/hooks", async (c) => {
+  const adminKey = process.env.ADMIN_KEY
+  const authKey = c.req.header("X-Admin-Key")
+  if(adminKey != authKey) {
+    c.status(401)
+    return c.text("bad auth key")
+  }
+
+  const hookRequest = (await c.req.json()) as PonderHook
+  if(!hookRequest.id || !hookRequest.secret) {
+    c.status(400)
+    return c.text("bad request")
+  }
+
+  try {
+    const updated = await setHookSecret(hookRequest.id, hookRequest.secret)
+    if(!updated) {
+      c.status(404)
+      return c.text("hook not found")
+    }
+    c.status(200)
+    return c.text("ok")
+  }
+  catch(error) {
+    console.log(error)
+    c.status(500)
+    return c.text("internal server error")
+  }
+})
+
 export default app;
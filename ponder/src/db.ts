@@ -10,6 +10,7 @@ export interface PonderHook {
   id: number;
   address: string;
   url: string;
+  secret?: string | null;
 }
 
 export const createTables = async () => {
@@ -20,6 +21,8 @@ export const createTables = async () => {
       url TEXT NOT NULL
     );
 
+    ALTER TABLE ponder_hooks ADD COLUMN IF NOT EXISTS secret TEXT;
+
     CREATE INDEX IF NOT EXISTS hook_address ON ponder_hooks(address);
   `)
 }
@@ -37,15 +40,18 @@ export const addHook = async (hook: PonderHook): Promise<PonderHook> => {
   return (await pool.query(`
     INSERT INTO ponder_hooks(
       address,
-      url
+      url,
+      secret
     ) VALUES (
       LOWER($1),
-      $2
+      $2,
+      $3
     )
-    RETURNING *;
+    RETURNING id, address, url;
   `, [
     hook.address,
-    hook.url
+    hook.url,
+    hook.secret || null
   ])).rows[0]
 }
 
@@ -56,4 +62,15 @@ export const deleteHook = async (id: number) => {
     WHERE
       id = $1;
   `, [id])
-}
\ No newline at end of file
+}
+export const setHookSecret = async (id: number, secret: string): Promise<boolean> => {
+  const result = await pool.query(`
+    UPDATE
+      ponder_hooks
+    SET
+      secret = $2
+    WHERE
+      id = $1;
+  `, [id, secret])
+  return (result.rowCount ?? 0) > 0
+}
@@ -6,6 +6,7 @@ import {
   transferEvent,
 } from "ponder:schema";
 import { createTables, getHooks, PonderHook } from "./db";
+import { signedHeaders } from "./sign";
 
 createTables()
 
@@ -20,6 +21,7 @@ const parseAdminAddresses = () => {
 
 const adminAddresses = parseAdminAddresses();
 const w9TransactionUrl = process.env.W9_TRANSACTION_URL;
+const w9HookSecret = process.env.W9_HOOK_SECRET;
 
 ponder.on("ERC20:Transfer", async ({ event, context }) => {
   await context.db
@@ -60,20 +62,23 @@ ponder.on("ERC20:Transfer", async ({ event, context }) => {
       if(!set) return
       set.forEach(async (hook) => {
         try {
-          const hookBody = {
+          const hookBody = JSON.stringify({
+            chain_id: context.chain.id,
             to: event.args.to,
             from: event.args.from,
             hash: event.transaction.hash,
+            log_index: event.log.logIndex,
             amount: event.args.amount.toString()
-          }
+          })
 
           if(deduped[hook.url]) return
           deduped[hook.url] = true
           await fetch(hook.url, {
             method: "POST",
-            body: JSON.stringify(hookBody),
+            body: hookBody,
             headers: {
-              "X-Admin-Key": process.env.ADMIN_KEY as string
+              "X-Admin-Key": process.env.ADMIN_KEY as string,
+              ...signedHeaders(hook.secret, hookBody, hook.id)
             }
           })
         }
@@ -90,18 +95,22 @@ ponder.on("ERC20:Transfer", async ({ event, context }) => {
   try {
     const fromAddress = event.args.from.toLowerCase();
     if (w9TransactionUrl && adminAddresses.includes(fromAddress)) {
+      const w9Body = JSON.stringify({
+        from_address: event.args.from,
+        to_address: event.args.to,
+        hash: event.transaction.hash,
+        amount: event.args.amount.toString(),
+        timestamp: Number(event.block.timestamp),
+        chain_id: context.chain.id,
+        log_index: event.log.logIndex,
+      });
       await fetch(w9TransactionUrl, {
         method: "POST",
-        body: JSON.stringify({
-          from_address: event.args.from,
-          to_address: event.args.to,
-          hash: event.transaction.hash,
-          amount: event.args.amount.toString(),
-          timestamp: Number(event.block.timestamp),
-        }),
+        body: w9Body,
         headers: {
           "Content-Type": "application/json",
           "X-Admin-Key": process.env.ADMIN_KEY as string,
+          ...signedHeaders(w9HookSecret, w9Body),
         },
       });
     }
@@ -0,0 +1,25 @@
+import { createHmac, randomBytes } from "crypto"
+
+// Callbacks are signed with HMAC-SHA256 over "<timestamp>.<nonce>.<body>",
+// matching the backend's verifyPonderCallback.
+export const signedHeaders = (
+  secret: string | null | undefined,
+  body: string,
+  hookId?: number
+): Record<string, string> => {
+  if (!secret) return {}
+
+  const timestamp = Math.floor(Date.now() / 1000).toString()
+  const nonce = randomBytes(16).toString("hex")
+  const signature = createHmac("sha256", secret)
+    .update(`${timestamp}.${nonce}.${body}`)
+    .digest("hex")
+
+  const headers: Record<string, string> = {
+    "X-SFLUV-Timestamp": timestamp,
+    "X-SFLUV-Nonce": nonce,
+    "X-SFLUV-Signature": signature,
+  }
+  if (hookId !== undefined) headers["X-SFLUV-Hook-Id"] = hookId.toString()
+  return headers
+}
@@ -31,3 +31,27 @@ go build -o "$TEMP_BUILD_PATH" ./cmd/server
 mv "$TEMP_BUILD_PATH" "$BACKEND_DIR/backend"
 
 sudo systemctl restart sfluv-backend.service
+
+# Gate the cutover on /readyz. Readiness fails until migrations have run and
+# the databases, RPC and schema version check out; if it never passes, put
+# the previous build back.
+HEALTH_URL="${HEALTH_URL:-http://localhost:${PORT:-8080}/readyz}"
+READY_TIMEOUT_SECONDS="${READY_TIMEOUT_SECONDS:-120}"
+deadline=$((SECONDS + READY_TIMEOUT_SECONDS))
+until curl -fsS --max-time 10 "$HEALTH_URL" >/tmp/sfluv-readyz.json 2>/dev/null; do
+  if (( SECONDS >= deadline )); then
+    echo "backend did not become ready within ${READY_TIMEOUT_SECONDS}s:" >&2
+    curl -sS --max-time 10 "$HEALTH_URL" >&2 || true
+    echo >&2
+    if [[ -f "$ARCHIVE_PATH" ]]; then
+      echo "rolling back to $ARCHIVE_PATH" >&2
+      cp "$ARCHIVE_PATH" "$BACKEND_DIR/backend"
+      sudo systemctl restart sfluv-backend.service
+    fi
+    exit 1
+  fi
+  sleep 3
+done
+
+echo "backend ready:"
+cat /tmp/sfluv-readyz.json